@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"os"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cis"
@@ -14,6 +15,7 @@ import (
 type Config struct {
 	ClientVersion string
 	CIS           cis.Config
+	Cleanup       cis.CleanupConfig
 	Database      storage.Config
 	Broker        broker.ClientConfig
 }
@@ -51,8 +53,10 @@ func main() {
 	brokerClient := broker.NewClient(ctx, cfg.Broker)
 
 	// create SubAccountCleanerService and execute process
-	sacs := cis.NewSubAccountCleanupService(client, brokerClient, db.Instances(), logs)
-	fatalOnError(sacs.Run())
+	sacs := cis.NewSubAccountCleanupService(client, brokerClient, db.Instances(), db.SubAccountCleanupRuns(), cfg.Cleanup, logs)
+	run, err := sacs.Run()
+	cis.WriteSummary(os.Stdout, run)
+	fatalOnError(err)
 }
 
 func fatalOnError(err error) {
@@ -25,16 +25,20 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler/azure"
 	orchestrationExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/accountpool"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/appinfo"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/auditlog"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/avs"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cis"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/edp"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/health"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias/rotation"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/lms"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/lms/renewal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/metrics"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration"
@@ -47,6 +51,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/upgrade_kyma"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provider"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provisioner"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/retention"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime/components"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtimeoverrides"
@@ -68,6 +73,8 @@ import (
 // Config holds configuration for the whole application
 type Config struct {
 	DbInMemory bool `envconfig:"default=false"`
+	// DbEmbedded starts a local PostgreSQL server as a child process from the installed binaries, meant for local development only
+	DbEmbedded bool `envconfig:"default=false"`
 
 	// DisableProcessOperationsInProgress allows to disable processing operations
 	// which are in progress on starting application. Set to true if you are
@@ -88,10 +95,13 @@ type Config struct {
 	Port       string `envconfig:"default=8080"`
 	StatusPort string `envconfig:"default=8071"`
 
-	Provisioning input.Config
-	Director     director.Config
-	Database     storage.Config
-	Gardener     gardener.Config
+	Provisioning     input.Config
+	Director         director.Config
+	Database         storage.Config
+	EmbeddedDatabase storage.EmbeddedConfig
+	ReEncryption     storage.ReEncryptionConfig
+	Retention        retention.Config
+	Gardener         gardener.Config
 
 	ServiceManager servicemanager.Config
 
@@ -102,10 +112,12 @@ type Config struct {
 
 	Broker broker.Config
 
-	Avs avs.Config
-	LMS lms.Config
-	IAS ias.Config
-	EDP edp.Config
+	Avs         avs.Config
+	LMS         lms.Config
+	LMSRenewal  renewal.Config
+	IAS         ias.Config
+	IASRotation rotation.Config
+	EDP         edp.Config
 
 	// Service Manager services
 	XSUAA struct {
@@ -114,13 +126,29 @@ type Config struct {
 
 	AuditLog auditlog.Config
 
+	CIS            cis.Config `envconfig:"optional"`
+	AccountCleanup cis.EventCleanupConfig
+
 	VersionConfig struct {
 		Namespace string
 		Name      string
 	}
 
+	// HyperscalerPool.NearlyExhaustedThreshold is the number of free secrets in the account pool
+	// at which the pool is reported as nearly exhausted.
+	// HyperscalerPool.MaxShootsPerSharedSecret limits the number of shoots in one region which use
+	// the same shared secret, 0 means no limit.
+	HyperscalerPool struct {
+		NearlyExhaustedThreshold int `envconfig:"default=5"`
+		MaxShootsPerSharedSecret int `envconfig:"default=0"`
+	}
+
 	TrialRegionMappingFilePath string
 	MaxPaginationPage          int `envconfig:"default=100"`
+
+	// RuntimeLiveStatusTimeout limits fetching the Runtime status from the Provisioner and Gardener
+	// for the Runtime details endpoint
+	RuntimeLiveStatusTimeout time.Duration `envconfig:"default=5s"`
 }
 
 func main() {
@@ -161,12 +189,40 @@ func main() {
 	var db storage.BrokerStorage
 	if cfg.DbInMemory {
 		db = storage.NewMemoryStorage()
+	} else if cfg.DbEmbedded {
+		store, stop, err := storage.NewEmbeddedStorage(cfg.EmbeddedDatabase, logs.WithField("service", "storage"))
+		fatalOnError(err)
+		defer func() {
+			if err := stop(); err != nil {
+				logs.Errorf("while stopping embedded database: %s", err)
+			}
+		}()
+		db = store
 	} else {
 		store, conn, err := storage.NewFromConfig(cfg.Database, logs.WithField("service", "storage"))
 		fatalOnError(err)
 		db = store
 		dbStatsCollector := sqlstats.NewStatsCollector("broker", conn)
 		prometheus.MustRegister(dbStatsCollector)
+
+		// migrate encrypted values to the newest encryption key
+		if !cfg.ReEncryption.Disabled {
+			reEncryptionJob := storage.NewReEncryptionJob(map[string]storage.ReEncrypter{
+				"runtime states": db.RuntimeStates(),
+				"instances":      db.Instances(),
+				"operations":     db.Operations(),
+			}, cfg.ReEncryption, logs.WithField("service", "reEncryption"))
+			go reEncryptionJob.Run(ctx.Done())
+		}
+
+		// move finished operations and orchestrations out of the tables used by the broker
+		if !cfg.Retention.Disabled {
+			sink, err := retention.NewSink(cfg.Retention, db.Archive())
+			fatalOnError(err)
+			retentionJob := retention.NewJob(db.Archive(), sink, cfg.Retention, logs.WithField("service", "retention"))
+			prometheus.MustRegister(metrics.NewArchiveCollector(retentionJob))
+			go retentionJob.Run(ctx.Done())
+		}
 	}
 
 	// LMS
@@ -197,8 +253,9 @@ func main() {
 	fatalOnError(err)
 
 	gardenerAccountPool := hyperscaler.NewAccountPool(gardenerSecrets, gardenerShoots)
-	gardenerSharedPool := hyperscaler.NewSharedGardenerAccountPool(gardenerSecrets, gardenerShoots)
+	gardenerSharedPool := hyperscaler.NewSharedGardenerAccountPool(gardenerSecrets, gardenerShoots, cfg.HyperscalerPool.MaxShootsPerSharedSecret)
 	accountProvider := hyperscaler.NewAccountProvider(gardenerAccountPool, gardenerSharedPool)
+	poolInspector := hyperscaler.NewPoolInspector(gardenerSecrets)
 
 	regions, err := provider.ReadPlatformRegionMappingFromFile(cfg.TrialRegionMappingFilePath)
 	fatalOnError(err)
@@ -227,7 +284,7 @@ func main() {
 	eventBroker := event.NewPubSub(logs)
 
 	// metrics collectors
-	metrics.RegisterAll(eventBroker, db.Operations(), db.Instances())
+	metrics.RegisterAll(eventBroker, db.Operations(), db.Instances(), poolInspector, cfg.HyperscalerPool.NearlyExhaustedThreshold)
 
 	//setup runtime overrides appender
 	runtimeOverrides := runtimeoverrides.NewRuntimeOverrides(ctx, cli)
@@ -261,7 +318,7 @@ func main() {
 		},
 		{
 			weight: 2,
-			step:   provisioning.NewResolveCredentialsStep(db.Operations(), accountProvider),
+			step:   provisioning.NewResolveCredentialsStep(db.Operations(), accountProvider, regions),
 		},
 		{
 			weight: 2,
@@ -314,7 +371,7 @@ func main() {
 		{
 			weight: 5,
 			step: provisioning.NewLmsActivationStep(db.Operations(), cfg.LMS,
-				provisioning.NewLmsCertificatesStep(lmsClient, db.Operations(), cfg.LMS.Mandatory)),
+				provisioning.NewLmsCertificatesStep(lmsClient, db.Operations(), db.LMSCertificates(), cfg.LMS.Mandatory)),
 		},
 		{
 			weight:   6,
@@ -395,11 +452,13 @@ func main() {
 	plansValidator, err := broker.NewPlansSchemaValidator()
 	fatalOnError(err)
 
+	deprovisionEndpoint := broker.NewDeprovision(db.Instances(), db.Operations(), deprovisionQueue, logs)
+
 	// create KymaEnvironmentBroker endpoints
 	kymaEnvBroker := &broker.KymaEnvironmentBroker{
 		broker.NewServices(cfg.Broker, optComponentsSvc, logs),
-		broker.NewProvision(cfg.Broker, cfg.Gardener, db.Operations(), db.Instances(), provisionQueue, inputFactory, plansValidator, cfg.EnableOnDemandVersion, logs),
-		broker.NewDeprovision(db.Instances(), db.Operations(), deprovisionQueue, logs),
+		broker.NewProvision(cfg.Broker, cfg.Gardener, db.Operations(), db.Instances(), provisionQueue, inputFactory, plansValidator, provisioning.NewSharedCapacityChecker(accountProvider, regions), cfg.EnableOnDemandVersion, logs),
+		deprovisionEndpoint,
 		broker.NewUpdate(db.Instances(), logs),
 		broker.NewGetInstance(db.Instances(), logs),
 		broker.NewLastOperation(db.Operations(), db.Instances(), logs),
@@ -409,6 +468,17 @@ func main() {
 		broker.NewLastBindingOperation(logs),
 	}
 
+	// run account cleanup driven by CIS events
+	if !cfg.AccountCleanup.Disabled {
+		// the high-water mark is stored per CIS version, the events are fetched only from CIS 2.0
+		const cisEventsSource = "CIS-2.0"
+		cleanupSvc := cis.NewSubAccountCleanupService(nil, broker.NewInternalClient(deprovisionEndpoint), db.Instances(), db.SubAccountCleanupRuns(),
+			cfg.AccountCleanup.CleanupConfig(), logs.WithField("service", "accountCleanup"))
+		eventCleanupSvc := cis.NewEventCleanupService(cis.NewClient(ctx, cfg.CIS, logs), cleanupSvc, db.CISHighWaterMarks(),
+			cisEventsSource, cfg.AccountCleanup, logs.WithField("service", "accountCleanup"))
+		go eventCleanupSvc.Run(ctx.Done())
+	}
+
 	// create server
 	router := mux.NewRouter()
 
@@ -425,6 +495,31 @@ func main() {
 		gardenerNamespace, eventBroker, inputFactory, nil, time.Minute, runtimeVerConfigurator, cfg.DefaultRequestRegion, logs)
 	fatalOnError(err)
 
+	// upgrade kyma operations created outside of orchestrations, e.g. when optional components are changed
+	upgradeKymaManager := NewUpgradeKymaManager(db, runtimeOverrides, provisionerClient, eventBroker, inputFactory, nil, runtimeVerConfigurator, logs)
+	if !cfg.IAS.Disabled {
+		// IAS secret rotations are created only outside of orchestrations
+		upgradeKymaManager.AddStep(2, upgrade_kyma.NewIASSecretRotationStep(db.Operations(), bundleBuilder))
+	}
+	upgradeKymaManager.AddStep(3, upgrade_kyma.NewLMSCertificateRenewalStep(db.Operations(), db.LMSCertificates(), lmsClient))
+	upgradeKymaQueue := process.NewQueue(upgradeKymaManager, logs)
+	upgradeKymaQueue.Run(ctx.Done(), workersAmount)
+
+	// rotate IAS ServiceProviders secrets and keep ServiceProviders in sync with the dashboard URL
+	if !cfg.IAS.Disabled && !cfg.IASRotation.Disabled {
+		iasRotationJob := rotation.NewJob(db.Instances(), db.Operations(), db.IASRotations(), bundleBuilder, upgradeKymaQueue,
+			cfg.IASRotation, logs.WithField("service", "iasRotation"))
+		go iasRotationJob.Run(ctx.Done())
+	}
+
+	// renew LMS certificates before they expire
+	if !cfg.LMSRenewal.Disabled {
+		lmsRenewalJob := renewal.NewJob(db.Instances(), db.Operations(), db.LMSCertificates(), upgradeKymaQueue,
+			cfg.LMSRenewal, logs.WithField("service", "lmsRenewal"))
+		prometheus.MustRegister(metrics.NewLMSCertificatesCollector(db.LMSCertificates(), cfg.LMSRenewal.LeadTime))
+		go lmsRenewalJob.Run(ctx.Done())
+	}
+
 	// TODO: in case of cluster upgrade the same Azure Zones must be send to the Provisioner
 	orchestrationHandler := orchestrate.NewOrchestrationHandler(db, kymaQueue, cfg.MaxPaginationPage, logs)
 
@@ -435,6 +530,8 @@ func main() {
 		fatalOnError(err)
 		err = reprocessOrchestrations(db.Orchestrations(), db.Operations(), kymaQueue, logs)
 		fatalOnError(err)
+		err = processUpgradeKymaOperationsInProgress(db.Operations(), upgradeKymaQueue, logs)
+		fatalOnError(err)
 	} else {
 		logger.Info("Skipping processing operation in progress on start")
 	}
@@ -453,9 +550,27 @@ func main() {
 	orchestrationHandler.AttachRoutes(router)
 
 	// create list runtimes endpoint
-	runtimeHandler := runtime.NewHandler(db.Instances(), db.Operations(), cfg.MaxPaginationPage, cfg.DefaultRequestRegion)
+	runtimeHandler := runtime.NewHandler(db.Instances(), cfg.MaxPaginationPage, cfg.DefaultRequestRegion)
 	runtimeHandler.AttachRoutes(router)
 
+	// create runtime details endpoint
+	// the Gardener client does not accept a context, the live status requests are aborted by the client timeout
+	liveStatusGardenerConfig := rest.CopyConfig(gardenerClusterConfig)
+	liveStatusGardenerConfig.Timeout = cfg.RuntimeLiveStatusTimeout
+	liveStatusShoots, err := gardener.NewGardenerShootInterface(liveStatusGardenerConfig, cfg.Gardener.Project)
+	fatalOnError(err)
+	runtimeDetailsHandler := runtime.NewDetailsHandler(db.Instances(), db.Operations(), db.RuntimeStates(), provisionerClient, liveStatusShoots,
+		cfg.RuntimeLiveStatusTimeout, cfg.DefaultRequestRegion, logs.WithField("service", "runtimeDetailsHandler"))
+	runtimeDetailsHandler.AttachRoutes(router)
+
+	// create hyperscaler account pool endpoint
+	accountPoolHandler := accountpool.NewHandler(poolInspector)
+	accountPoolHandler.AttachRoutes(router)
+
+	// create optional components endpoint
+	componentsHandler := runtime.NewComponentsHandler(db.Instances(), db.Operations(), optComponentsSvc, upgradeKymaQueue, logs.WithField("service", "componentsHandler"))
+	componentsHandler.AttachRoutes(router)
+
 	router.StrictSlash(true).PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir("/swagger"))))
 	svr := handlers.CustomLoggingHandler(os.Stdout, router, func(writer io.Writer, params handlers.LogFormatterParams) {
 		logs.Infof("Call handled: method=%s url=%s statusCode=%d size=%d", params.Request.Method, params.URL.Path, params.StatusCode, params.Size)
@@ -477,6 +592,23 @@ func processOperationsInProgressByType(opType dbmodel.OperationType, op storage.
 	return nil
 }
 
+// queues in progress upgrade kyma operations which do not belong to any orchestration,
+// the orchestrated ones are resumed by the orchestration queue
+func processUpgradeKymaOperationsInProgress(op storage.Operations, queue *process.Queue, log logrus.FieldLogger) error {
+	operations, err := op.GetOperationsInProgressByType(dbmodel.OperationTypeUpgradeKyma)
+	if err != nil {
+		return errors.Wrap(err, "while getting in progress upgrade kyma operations from storage")
+	}
+	for _, operation := range operations {
+		if operation.OrchestrationID != "" {
+			continue
+		}
+		queue.Add(operation.ID)
+		log.Infof("Resuming the processing of %s operation ID: %s", dbmodel.OperationTypeUpgradeKyma, operation.ID)
+	}
+	return nil
+}
+
 func reprocessOrchestrations(orchestrationsStorage storage.Orchestrations, operationsStorage storage.Operations, queue *process.Queue, log logrus.FieldLogger) error {
 	if err := processCancelingOrchestrations(orchestrationsStorage, operationsStorage, queue, log); err != nil {
 		return errors.Wrap(err, "while processing canceled orchestrations")
@@ -565,6 +697,26 @@ func NewOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerStora
 	pollingInterval time.Duration, runtimeVerConfigurator *runtimeversion.RuntimeVersionConfigurator,
 	defaultRegion string, logs logrus.FieldLogger) (*process.Queue, error) {
 
+	upgradeKymaManager := NewUpgradeKymaManager(db, runtimeOverrides, provisionerClient, pub, inputFactory, icfg, runtimeVerConfigurator, logs)
+
+	runtimeLister := orchestration.NewRuntimeLister(db.Instances(), db.Operations(), runtime.NewConverter(defaultRegion), logs)
+	runtimeResolver := orchestrationExt.NewGardenerRuntimeResolver(gardenerClient, gardenerNamespace, runtimeLister, logs)
+
+	orchestrateKymaManager := kyma.NewUpgradeKymaManager(db.Orchestrations(), db.Operations(),
+		upgradeKymaManager, runtimeResolver, pollingInterval, logs)
+	queue := process.NewQueue(orchestrateKymaManager, logs)
+
+	// only one orchestration can be processed at the same time
+	queue.Run(ctx.Done(), 1)
+
+	return queue, nil
+}
+
+func NewUpgradeKymaManager(db storage.BrokerStorage, runtimeOverrides upgrade_kyma.RuntimeOverridesAppender,
+	provisionerClient provisioner.Client, pub event.Publisher, inputFactory input.CreatorForPlan,
+	icfg *upgrade_kyma.TimeSchedule, runtimeVerConfigurator *runtimeversion.RuntimeVersionConfigurator,
+	logs logrus.FieldLogger) *upgrade_kyma.Manager {
+
 	upgradeKymaManager := upgrade_kyma.NewManager(db.Operations(), pub, logs.WithField("upgradeKyma", "manager"))
 
 	upgradeKymaInit := upgrade_kyma.NewInitialisationStep(db.Operations(), db.Instances(), provisionerClient, inputFactory, icfg, runtimeVerConfigurator)
@@ -575,7 +727,8 @@ func NewOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerStora
 		step     upgrade_kyma.Step
 	}{
 		{
-			weight: 2,
+			// the steps which append overrides for the same version upgrades run before, with the weights 2 and 3
+			weight: 4,
 			step:   upgrade_kyma.NewOverridesFromSecretsAndConfigStep(db.Operations(), runtimeOverrides, runtimeVerConfigurator),
 		},
 		{
@@ -589,15 +742,5 @@ func NewOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerStora
 		}
 	}
 
-	runtimeLister := orchestration.NewRuntimeLister(db.Instances(), db.Operations(), runtime.NewConverter(defaultRegion), logs)
-	runtimeResolver := orchestrationExt.NewGardenerRuntimeResolver(gardenerClient, gardenerNamespace, runtimeLister, logs)
-
-	orchestrateKymaManager := kyma.NewUpgradeKymaManager(db.Orchestrations(), db.Operations(),
-		upgradeKymaManager, runtimeResolver, pollingInterval, logs)
-	queue := process.NewQueue(orchestrateKymaManager, logs)
-
-	// only one orchestration can be processed at the same time
-	queue.Run(ctx.Done(), 1)
-
-	return queue, nil
+	return upgradeKymaManager
 }
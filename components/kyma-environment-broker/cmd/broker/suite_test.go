@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -45,6 +47,9 @@ const (
 	defaultNamespace       = "kcp-system"
 	kymaVersionsConfigName = "kyma-versions"
 	defaultRegion          = "cf-eu10"
+
+	// embeddedDBEnv enables running the suite against the embedded PostgreSQL instead of the memory storage
+	embeddedDBEnv = "KEB_TEST_EMBEDDED_DB"
 )
 
 type OrchestrationSuite struct {
@@ -80,7 +85,7 @@ func NewOrchestrationSuite(t *testing.T) *OrchestrationSuite {
 	require.NoError(t, err)
 
 	ctx, _ := context.WithTimeout(context.Background(), 20*time.Minute)
-	db := storage.NewMemoryStorage()
+	db := newSuiteStorage(t, logs)
 	sch := runtime.NewScheme()
 	require.NoError(t, coreV1.AddToScheme(sch))
 	cli := fake.NewFakeClientWithScheme(sch, fixK8sResources()...)
@@ -154,6 +159,24 @@ func (o *RuntimeOptions) ProvidePlatformRegion() string {
 	}
 }
 
+func newSuiteStorage(t *testing.T, log logrus.FieldLogger) storage.BrokerStorage {
+	if os.Getenv(embeddedDBEnv) != "true" {
+		return storage.NewMemoryStorage()
+	}
+
+	db, stop, err := storage.NewEmbeddedStorage(storage.EmbeddedConfig{
+		Port:           15432,
+		MigrationsPath: filepath.Join("..", "..", "..", "schema-migrator", "migrations", "kyma-environment-broker"),
+		StartTimeout:   time.Minute,
+		SecretKey:      "################################",
+	}, log)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, stop())
+	})
+	return db
+}
+
 func (s *OrchestrationSuite) CreateProvisionedRuntime(options RuntimeOptions) string {
 	planID := broker.AzurePlanID
 	planName := broker.AzurePlanName
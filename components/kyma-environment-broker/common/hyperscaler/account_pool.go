@@ -33,6 +33,21 @@ type AccountPool interface {
 	IsSecretInternal(hyperscalerType Type, tenantName string) (bool, error)
 }
 
+// PoolExhaustedError is returned when there is no unassigned secret left in the pool
+type PoolExhaustedError struct {
+	HyperscalerType Type
+}
+
+func (e PoolExhaustedError) Error() string {
+	return fmt.Sprintf("failed to find unassigned secret for hyperscalerType: %s", e.HyperscalerType)
+}
+
+// IsPoolExhausted checks if the error was caused by the lack of unassigned secrets in the pool
+func IsPoolExhausted(err error) bool {
+	_, ok := errors.Cause(err).(PoolExhaustedError)
+	return ok
+}
+
 func NewAccountPool(secretsClient corev1.SecretInterface, shootsClient gardener_apis.ShootInterface) AccountPool {
 	return &secretsAccountPool{
 		secretsClient: secretsClient,
@@ -148,7 +163,7 @@ func (p *secretsAccountPool) Credentials(hyperscalerType Type, tenantName string
 	}
 
 	if secret == nil {
-		return Credentials{}, errors.Errorf("failed to find unassigned secret for hyperscalerType: %s", hyperscalerType)
+		return Credentials{}, PoolExhaustedError{HyperscalerType: hyperscalerType}
 	}
 
 	secret.Labels["tenantName"] = tenantName
@@ -8,7 +8,7 @@ import (
 //go:generate mockery -name=AccountProvider -output=automock -outpkg=automock -case=underscore
 type AccountProvider interface {
 	GardenerCredentials(hyperscalerType Type, tenantName string) (Credentials, error)
-	GardenerSharedCredentials(hyperscalerType Type) (Credentials, error)
+	GardenerSharedCredentials(hyperscalerType Type, region string) (Credentials, error)
 	MarkUnusedGardenerSecretAsDirty(hyperscalerType Type, tenantName string) error
 }
 
@@ -46,13 +46,13 @@ func (p *accountProvider) GardenerCredentials(hyperscalerType Type, tenantName s
 	return p.gardenerPool.Credentials(hyperscalerType, tenantName)
 }
 
-func (p *accountProvider) GardenerSharedCredentials(hyperscalerType Type) (Credentials, error) {
+func (p *accountProvider) GardenerSharedCredentials(hyperscalerType Type, region string) (Credentials, error) {
 	if p.sharedGardenerPool == nil {
 		return Credentials{},
 			errors.New("failed to get shared Gardener Credentials. Gardener Shared Account pool is not configured")
 	}
 
-	return p.sharedGardenerPool.SharedCredentials(hyperscalerType)
+	return p.sharedGardenerPool.SharedCredentials(hyperscalerType, region)
 }
 
 func (p *accountProvider) MarkUnusedGardenerSecretAsDirty(hyperscalerType Type, tenantName string) error {
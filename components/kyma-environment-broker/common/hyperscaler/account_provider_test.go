@@ -13,7 +13,7 @@ func TestGardenerSharedCredentials_Error(t *testing.T) {
 
 	accountProvider := NewAccountProvider(nil, nil)
 
-	_, err := accountProvider.GardenerSharedCredentials(Type("gcp"))
+	_, err := accountProvider.GardenerSharedCredentials(Type("gcp"), "")
 	require.Error(t, err)
 
 	assert.Contains(t, err.Error(), "Gardener Shared Account pool is not configured")
@@ -0,0 +1,118 @@
+package hyperscaler
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PoolSecret describes a single secret from the hyperscaler account pool
+type PoolSecret struct {
+	Name            string `json:"name"`
+	HyperscalerType Type   `json:"hyperscalerType"`
+	TenantName      string `json:"tenantName,omitempty"`
+	Dirty           bool   `json:"dirty"`
+	Shared          bool   `json:"shared"`
+	Internal        bool   `json:"internal"`
+}
+
+// PoolStats contains the number of secrets in the pool by their state,
+// shared secrets are counted only as shared
+type PoolStats struct {
+	Free     int `json:"free"`
+	Assigned int `json:"assigned"`
+	Dirty    int `json:"dirty"`
+	Shared   int `json:"shared"`
+}
+
+// PoolSecretsPage is returned by the hyperscaler account pool admin API
+type PoolSecretsPage struct {
+	Data  []PoolSecret       `json:"data"`
+	Count int                `json:"count"`
+	Stats map[Type]PoolStats `json:"stats"`
+}
+
+// PoolInspector provides read-only access to the hyperscaler account pool
+type PoolInspector struct {
+	secretsClient corev1.SecretInterface
+}
+
+func NewPoolInspector(secretsClient corev1.SecretInterface) *PoolInspector {
+	return &PoolInspector{
+		secretsClient: secretsClient,
+	}
+}
+
+// Secrets returns the pool secrets for the given hyperscaler type or all pool secrets if the type is empty
+func (p *PoolInspector) Secrets(hyperscalerType Type) ([]PoolSecret, error) {
+	labelSelector := "hyperscalerType"
+	if hyperscalerType != "" {
+		labelSelector = "hyperscalerType=" + string(hyperscalerType)
+	}
+
+	secrets, err := p.secretsClient.List(metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing secrets for LabelSelector: %s", labelSelector)
+	}
+
+	result := make([]PoolSecret, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		result = append(result, poolSecretFromSecret(secret))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].HyperscalerType == result[j].HyperscalerType {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].HyperscalerType < result[j].HyperscalerType
+	})
+
+	return result, nil
+}
+
+// Stats returns the number of free, assigned, dirty and shared secrets per hyperscaler type,
+// only hyperscaler types which have at least one secret in the pool are returned
+func (p *PoolInspector) Stats() (map[Type]PoolStats, error) {
+	secrets, err := p.Secrets("")
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputePoolStats(secrets), nil
+}
+
+func ComputePoolStats(secrets []PoolSecret) map[Type]PoolStats {
+	stats := make(map[Type]PoolStats)
+
+	for _, secret := range secrets {
+		s := stats[secret.HyperscalerType]
+		switch {
+		case secret.Shared:
+			s.Shared++
+		case secret.Dirty:
+			s.Dirty++
+		case secret.TenantName != "":
+			s.Assigned++
+		default:
+			s.Free++
+		}
+		stats[secret.HyperscalerType] = s
+	}
+
+	return stats
+}
+
+func poolSecretFromSecret(secret apiv1.Secret) PoolSecret {
+	return PoolSecret{
+		Name:            secret.Name,
+		HyperscalerType: Type(secret.Labels["hyperscalerType"]),
+		TenantName:      secret.Labels["tenantName"],
+		Dirty:           secret.Labels["dirty"] == "true",
+		Shared:          secret.Labels["shared"] == "true",
+		Internal:        secret.Labels["internal"] == "true",
+	}
+}
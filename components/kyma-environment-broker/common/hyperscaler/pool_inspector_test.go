@@ -0,0 +1,68 @@
+package hyperscaler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	machineryv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPoolInspector_Secrets(t *testing.T) {
+	// given
+	inspector := newTestPoolInspector()
+
+	// when
+	all, err := inspector.Secrets("")
+	require.NoError(t, err)
+	gcp, err := inspector.Secrets(GCP)
+	require.NoError(t, err)
+
+	// then
+	assert.Len(t, all, 5)
+	assert.Equal(t, []PoolSecret{
+		{Name: "free", HyperscalerType: GCP},
+		{Name: "internal", HyperscalerType: GCP, TenantName: "tenant2", Internal: true},
+		{Name: "shared", HyperscalerType: GCP, Shared: true},
+	}, gcp)
+}
+
+func TestPoolInspector_Stats(t *testing.T) {
+	// given
+	inspector := newTestPoolInspector()
+
+	// when
+	stats, err := inspector.Stats()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, PoolStats{Free: 1, Assigned: 1, Shared: 1}, stats[GCP])
+	assert.Equal(t, PoolStats{Assigned: 1, Dirty: 1}, stats[Azure])
+	assert.Equal(t, PoolStats{}, stats[AWS])
+}
+
+func newTestPoolInspector() *PoolInspector {
+	secrets := []*corev1.Secret{
+		fixPoolSecret("free", map[string]string{"hyperscalerType": "gcp"}),
+		fixPoolSecret("internal", map[string]string{"hyperscalerType": "gcp", "tenantName": "tenant2", "internal": "true"}),
+		fixPoolSecret("shared", map[string]string{"hyperscalerType": "gcp", "shared": "true"}),
+		fixPoolSecret("assigned", map[string]string{"hyperscalerType": "azure", "tenantName": "tenant1"}),
+		fixPoolSecret("dirty", map[string]string{"hyperscalerType": "azure", "tenantName": "tenant3", "dirty": "true"}),
+		fixPoolSecret("other", map[string]string{"app": "keb"}),
+	}
+	mockClient := fake.NewSimpleClientset(secrets[0], secrets[1], secrets[2], secrets[3], secrets[4], secrets[5])
+
+	return NewPoolInspector(mockClient.CoreV1().Secrets(testNamespace))
+}
+
+func fixPoolSecret(name string, labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: machineryv1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    labels,
+		},
+	}
+}
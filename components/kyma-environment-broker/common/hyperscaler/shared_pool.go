@@ -11,32 +11,62 @@ import (
 )
 
 type SharedPool interface {
-	SharedCredentials(hyperscalerType Type) (Credentials, error)
+	SharedCredentials(hyperscalerType Type, region string) (Credentials, error)
 }
 
-func NewSharedGardenerAccountPool(secretsClient corev1.SecretInterface, shootsClient gardener_apis.ShootInterface) *SharedAccountPool {
+// SharedPoolCapacityError is returned when every shared secret is used by the maximum number of shoots
+type SharedPoolCapacityError struct {
+	HyperscalerType    Type
+	Region             string
+	MaxShootsPerSecret int
+}
+
+func (e SharedPoolCapacityError) Error() string {
+	return fmt.Sprintf("all shared secrets for hyperscalerType: %s and region: %s reached the limit of %d shoots", e.HyperscalerType, e.Region, e.MaxShootsPerSecret)
+}
+
+// IsSharedPoolAtCapacity checks if the error was caused by all shared secrets reaching the shoots limit
+func IsSharedPoolAtCapacity(err error) bool {
+	_, ok := errors.Cause(err).(SharedPoolCapacityError)
+	return ok
+}
+
+// NewSharedGardenerAccountPool creates the pool of shared secrets, maxShootsPerSecret set to 0 means no limit
+func NewSharedGardenerAccountPool(secretsClient corev1.SecretInterface, shootsClient gardener_apis.ShootInterface, maxShootsPerSecret int) *SharedAccountPool {
 	return &SharedAccountPool{
-		secretsClient: secretsClient,
-		shootsClient:  shootsClient,
+		secretsClient:      secretsClient,
+		shootsClient:       shootsClient,
+		maxShootsPerSecret: maxShootsPerSecret,
 	}
 }
 
 type SharedAccountPool struct {
-	secretsClient corev1.SecretInterface
-	shootsClient  gardener_apis.ShootInterface
+	secretsClient      corev1.SecretInterface
+	shootsClient       gardener_apis.ShootInterface
+	maxShootsPerSecret int
 }
 
-func (sp *SharedAccountPool) SharedCredentials(hyperscalerType Type) (Credentials, error) {
+// SharedCredentials returns the shared secret with the fewest shoots in the given region.
+// Shoots from all regions are counted if the region is empty. The limit of shoots per secret
+// always applies to the shoots from all regions, because the hyperscaler quota is per account.
+func (sp *SharedAccountPool) SharedCredentials(hyperscalerType Type, region string) (Credentials, error) {
 	labelSelector := fmt.Sprintf("shared=true,hyperscalerType=%s", hyperscalerType)
 	secrets, err := getK8sSecrets(sp.secretsClient, labelSelector)
 	if err != nil {
 		return Credentials{}, err
 	}
 
-	secret, err := sp.getLeastUsed(secrets)
+	secret, found, err := sp.getLeastUsed(secrets, region)
 	if err != nil {
 		return Credentials{}, err
 	}
+	if !found {
+		return Credentials{}, SharedPoolCapacityError{
+			HyperscalerType:    hyperscalerType,
+			Region:             region,
+			MaxShootsPerSecret: sp.maxShootsPerSecret,
+		}
+	}
 
 	return credentialsFromSecret(&secret, hyperscalerType), nil
 }
@@ -56,39 +86,48 @@ func getK8sSecrets(secretsClient corev1.SecretInterface, labelSelector string) (
 	return secrets.Items, nil
 }
 
-func (sp *SharedAccountPool) getLeastUsed(secrets []apiv1.Secret) (apiv1.Secret, error) {
-	usageCount := make(map[string]int, len(secrets))
+// getLeastUsed returns the secret below the shoots limit with the fewest shoots in the region,
+// the result is false if every secret reached the limit
+func (sp *SharedAccountPool) getLeastUsed(secrets []apiv1.Secret, region string) (apiv1.Secret, bool, error) {
+	totalCount := make(map[string]int, len(secrets))
+	regionCount := make(map[string]int, len(secrets))
 	for _, s := range secrets {
-		usageCount[s.Name] = 0
+		totalCount[s.Name] = 0
+		regionCount[s.Name] = 0
 	}
 
 	shoots, err := sp.shootsClient.List(metav1.ListOptions{})
 	if err != nil {
-		return apiv1.Secret{}, errors.Wrap(err, "error while listing Shoots")
+		return apiv1.Secret{}, false, errors.Wrap(err, "error while listing Shoots")
 	}
 
-	if shoots == nil || len(shoots.Items) == 0 {
-		return secrets[0], nil
-	}
-
-	for _, s := range shoots.Items {
-		count, found := usageCount[s.Spec.SecretBindingName]
-		if !found {
-			continue
+	if shoots != nil {
+		for _, s := range shoots.Items {
+			count, found := totalCount[s.Spec.SecretBindingName]
+			if !found {
+				continue
+			}
+			totalCount[s.Spec.SecretBindingName] = count + 1
+
+			if region != "" && s.Spec.Region != region {
+				continue
+			}
+			regionCount[s.Spec.SecretBindingName]++
 		}
-
-		usageCount[s.Spec.SecretBindingName] = count + 1
 	}
 
-	min := usageCount[secrets[0].Name]
-	minIndex := 0
-
+	minIndex := -1
 	for i, s := range secrets {
-		if usageCount[s.Name] < min {
-			min = usageCount[s.Name]
+		if sp.maxShootsPerSecret > 0 && totalCount[s.Name] >= sp.maxShootsPerSecret {
+			continue
+		}
+		if minIndex < 0 || regionCount[s.Name] < regionCount[secrets[minIndex].Name] {
 			minIndex = i
 		}
 	}
+	if minIndex < 0 {
+		return apiv1.Secret{}, false, nil
+	}
 
-	return secrets[minIndex], nil
+	return secrets[minIndex], true, nil
 }
@@ -24,6 +24,7 @@ func TestSharedPool_SharedCredentials(t *testing.T) {
 		secrets        []runtime.Object
 		shoots         []runtime.Object
 		hyperscaler    Type
+		region         string
 		expectedSecret string
 	}{
 		{
@@ -103,6 +104,23 @@ func TestSharedPool_SharedCredentials(t *testing.T) {
 			hyperscaler:    "aws",
 			expectedSecret: "s1",
 		},
+		{
+			description: "should count only Shoots from the requested region",
+			secrets: []runtime.Object{
+				newSecret("s1", "azure", true),
+				newSecret("s2", "azure", true),
+			},
+			shoots: []runtime.Object{
+				newShootInRegion("sh1", "s1", "westeurope"),
+				newShootInRegion("sh2", "s2", "eastus"),
+				newShootInRegion("sh3", "s2", "eastus"),
+				newShootInRegion("sh4", "s2", "westeurope"),
+				newShootInRegion("sh5", "s2", "westeurope"),
+			},
+			hyperscaler:    "azure",
+			region:         "eastus",
+			expectedSecret: "s1",
+		},
 	} {
 		t.Run(testCase.description, func(t *testing.T) {
 			// given
@@ -113,10 +131,10 @@ func TestSharedPool_SharedCredentials(t *testing.T) {
 			gardenerFake := gardener_fake.NewSimpleClientset(testCase.shoots...)
 			mockShoots := gardenerFake.CoreV1beta1().Shoots(testNamespace)
 
-			pool := NewSharedGardenerAccountPool(mockSecrets, mockShoots)
+			pool := NewSharedGardenerAccountPool(mockSecrets, mockShoots, 0)
 
 			// when
-			credentials, err := pool.SharedCredentials(testCase.hyperscaler)
+			credentials, err := pool.SharedCredentials(testCase.hyperscaler, testCase.region)
 			require.NoError(t, err)
 
 			// then
@@ -134,15 +152,72 @@ func TestSharedPool_SharedCredentials_Errors(t *testing.T) {
 		)
 		mockSecrets := mockClient.CoreV1().Secrets(testNamespace)
 
-		pool := NewSharedGardenerAccountPool(mockSecrets, nil)
+		pool := NewSharedGardenerAccountPool(mockSecrets, nil, 0)
 
 		// when
-		_, err := pool.SharedCredentials("gcp")
+		_, err := pool.SharedCredentials("gcp", "")
 
 		// then
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no shared Secret found")
 	})
+
+	t.Run("should return error when all Secrets reached the Shoots limit", func(t *testing.T) {
+		mockClient := fake.NewSimpleClientset(
+			newSecret("s1", "azure", true),
+			newSecret("s2", "azure", true),
+		)
+		mockSecrets := mockClient.CoreV1().Secrets(testNamespace)
+
+		gardenerFake := gardener_fake.NewSimpleClientset(
+			newShootInRegion("sh1", "s1", "westeurope"),
+			newShootInRegion("sh2", "s1", "westeurope"),
+			newShootInRegion("sh3", "s2", "westeurope"),
+			newShootInRegion("sh4", "s2", "westeurope"),
+			newShootInRegion("sh5", "s2", "eastus"),
+		)
+		mockShoots := gardenerFake.CoreV1beta1().Shoots(testNamespace)
+
+		pool := NewSharedGardenerAccountPool(mockSecrets, mockShoots, 2)
+
+		// when
+		_, err := pool.SharedCredentials("azure", "westeurope")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, IsSharedPoolAtCapacity(err))
+
+		// when
+		_, err = pool.SharedCredentials("azure", "eastus")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, IsSharedPoolAtCapacity(err))
+	})
+
+	t.Run("should skip Secrets which reached the Shoots limit in other regions", func(t *testing.T) {
+		mockClient := fake.NewSimpleClientset(
+			newSecret("s1", "azure", true),
+			newSecret("s2", "azure", true),
+		)
+		mockSecrets := mockClient.CoreV1().Secrets(testNamespace)
+
+		gardenerFake := gardener_fake.NewSimpleClientset(
+			newShootInRegion("sh1", "s1", "westeurope"),
+			newShootInRegion("sh2", "s1", "westeurope"),
+			newShootInRegion("sh3", "s2", "eastus"),
+		)
+		mockShoots := gardenerFake.CoreV1beta1().Shoots(testNamespace)
+
+		pool := NewSharedGardenerAccountPool(mockSecrets, mockShoots, 2)
+
+		// when
+		credentials, err := pool.SharedCredentials("azure", "eastus")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "s2", credentials.Name)
+	})
 }
 
 func newSecret(name, hyperscaler string, shared bool) *corev1.Secret {
@@ -166,6 +241,10 @@ func newSecret(name, hyperscaler string, shared bool) *corev1.Secret {
 }
 
 func newShoot(name, secret string) *gardener_types.Shoot {
+	return newShootInRegion(name, secret, "")
+}
+
+func newShootInRegion(name, secret, region string) *gardener_types.Shoot {
 	return &gardener_types.Shoot{
 		ObjectMeta: machineryv1.ObjectMeta{
 			Name:      name,
@@ -173,6 +252,7 @@ func newShoot(name, secret string) *gardener_types.Shoot {
 		},
 		Spec: gardener_types.ShootSpec{
 			SecretBindingName: secret,
+			Region:            region,
 		},
 	}
 }
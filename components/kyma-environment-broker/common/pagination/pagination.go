@@ -31,9 +31,8 @@ func ValidatePageParameters(pageSize, page int) error {
 func ConvertPageAndPageSizeToOffset(pageSize, page int) int {
 	if page < 2 {
 		return 0
-	} else {
-		return page*pageSize - 1
 	}
+	return (page - 1) * pageSize
 }
 
 const (
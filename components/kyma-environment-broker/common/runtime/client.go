@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
 	"github.com/pkg/errors"
@@ -20,6 +21,7 @@ const defaultPageSize = 100
 // Client is the interface to interact with the KEB /runtimes API as an HTTP client using OIDC ID token in JWT format.
 type Client interface {
 	ListRuntimes(params ListParameters) (RuntimesPage, error)
+	GetRuntime(runtimeID string) (RuntimeDetailsDTO, error)
 }
 
 type client struct {
@@ -100,6 +102,41 @@ func (c *client) ListRuntimes(params ListParameters) (RuntimesPage, error) {
 	return runtimes, nil
 }
 
+// GetRuntime fetches the details of the Runtime with the given ID from KEB
+func (c *client) GetRuntime(runtimeID string) (details RuntimeDetailsDTO, err error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/runtimes/%s", c.url, url.PathEscape(runtimeID)), nil)
+	if err != nil {
+		return details, errors.Wrap(err, "while creating request")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return details, errors.Wrapf(err, "while calling %s", req.URL.String())
+	}
+	// Drain response body and close, return error to context if there isn't any.
+	defer func() {
+		derr := drainResponseBody(resp.Body)
+		if err == nil {
+			err = derr
+		}
+		cerr := resp.Body.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return details, fmt.Errorf("calling %s returned %d (%s) status", req.URL.String(), resp.StatusCode, resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&details)
+	if err != nil {
+		return details, errors.Wrap(err, "while decoding response body")
+	}
+
+	return details, nil
+}
+
 func setQuery(url *url.URL, params ListParameters) {
 	query := url.Query()
 	query.Add(pagination.PageParam, strconv.Itoa(params.Page))
@@ -111,6 +148,23 @@ func setQuery(url *url.URL, params ListParameters) {
 	setParamList(query, RegionParam, params.Regions)
 	setParamList(query, ShootParam, params.Shoots)
 	setParamList(query, PlanParam, params.Plans)
+	setParamList(query, StateParam, params.States)
+	setParamList(query, KymaVersionParam, params.KymaVersions)
+	if !params.CreatedFrom.IsZero() {
+		query.Add(CreatedFromParam, params.CreatedFrom.Format(time.RFC3339))
+	}
+	if !params.CreatedTo.IsZero() {
+		query.Add(CreatedToParam, params.CreatedTo.Format(time.RFC3339))
+	}
+	if params.Search != "" {
+		query.Add(SearchParam, params.Search)
+	}
+	if params.SortBy != "" {
+		query.Add(SortParam, params.SortBy)
+	}
+	if params.Order != "" {
+		query.Add(OrderParam, params.Order)
+	}
 	url.RawQuery = query.Encode()
 }
 
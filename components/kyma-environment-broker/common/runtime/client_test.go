@@ -45,6 +45,13 @@ func TestClient_ListRuntimes(t *testing.T) {
 			Regions:          []string{"region1", "region2"},
 			Shoots:           []string{"shoot1", "shoot2"},
 			Plans:            []string{"plan1", "plan2"},
+			States:           []string{StateSucceeded, StateSuspended},
+			KymaVersions:     []string{"1.17.0"},
+			CreatedFrom:      time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC),
+			CreatedTo:        time.Date(2020, 12, 2, 10, 0, 0, 0, time.UTC),
+			Search:           "shoot",
+			SortBy:           SortByState,
+			Order:            OrderDesc,
 		}
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			called++
@@ -61,6 +68,13 @@ func TestClient_ListRuntimes(t *testing.T) {
 			assert.ElementsMatch(t, params.Regions, query[RegionParam])
 			assert.ElementsMatch(t, params.Shoots, query[ShootParam])
 			assert.ElementsMatch(t, params.Plans, query[PlanParam])
+			assert.ElementsMatch(t, params.States, query[StateParam])
+			assert.ElementsMatch(t, params.KymaVersions, query[KymaVersionParam])
+			assert.Equal(t, "2020-12-01T10:00:00Z", query.Get(CreatedFromParam))
+			assert.Equal(t, "2020-12-02T10:00:00Z", query.Get(CreatedToParam))
+			assert.Equal(t, params.Search, query.Get(SearchParam))
+			assert.Equal(t, params.SortBy, query.Get(SortParam))
+			assert.Equal(t, params.Order, query.Get(OrderParam))
 
 			err := respondRuntimes(w, []RuntimeDTO{runtime1, runtime2}, 2)
 			require.NoError(t, err)
@@ -107,6 +121,49 @@ func TestClient_ListRuntimes(t *testing.T) {
 	})
 }
 
+func TestClient_GetRuntime(t *testing.T) {
+	t.Run("test request URL and response are correct", func(t *testing.T) {
+		// given
+		details := RuntimeDetailsDTO{
+			RuntimeDTO: runtime1,
+			Gardener:   GardenerStatus{ShootName: "c-1234567", Error: "timeout"},
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/runtimes/runtime1", r.URL.Path)
+			assert.Equal(t, r.Header.Get("Authorization"), fmt.Sprintf("Bearer %s", fixToken))
+
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(details))
+		}))
+		defer ts.Close()
+		client := NewClient(context.TODO(), ts.URL, fixToken)
+
+		// when
+		out, err := client.GetRuntime("runtime1")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, runtime1.InstanceID, out.InstanceID)
+		assert.Equal(t, details.Gardener, out.Gardener)
+	})
+
+	t.Run("test not found runtime", func(t *testing.T) {
+		// given
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+		client := NewClient(context.TODO(), ts.URL, fixToken)
+
+		// when
+		_, err := client.GetRuntime("not-existing")
+
+		// then
+		require.Error(t, err)
+	})
+}
+
 func fixRuntimeDTO(id string) RuntimeDTO {
 	return RuntimeDTO{
 		InstanceID:       id,
@@ -16,10 +16,12 @@ type RuntimeDTO struct {
 	ServiceClassName string        `json:"serviceClassName"`
 	ServicePlanID    string        `json:"servicePlanID"`
 	ServicePlanName  string        `json:"servicePlanName"`
+	KymaVersion      string        `json:"kymaVersion,omitempty"`
 	Status           RuntimeStatus `json:"status"`
 }
 
 type RuntimeStatus struct {
+	State          string         `json:"state,omitempty"`
 	CreatedAt      time.Time      `json:"createdAt"`
 	ModifiedAt     time.Time      `json:"modifiedAt"`
 	Provisioning   *Operation     `json:"provisioning"`
@@ -41,6 +43,80 @@ type Operation struct {
 	OrchestrationID *string   `json:"orchestrationID,omitempty"`
 }
 
+// RuntimeDetailsDTO describes a single Runtime with all its operations, the latest configuration stored by KEB
+// and the status fetched live from the Runtime Provisioner and Gardener
+type RuntimeDetailsDTO struct {
+	RuntimeDTO
+
+	RuntimeConfig *RuntimeConfigDTO `json:"runtimeConfig,omitempty"`
+	Provisioner   ProvisionerStatus `json:"provisioner"`
+	Gardener      GardenerStatus    `json:"gardener"`
+}
+
+// RuntimeConfigDTO is the cluster and Kyma configuration from the latest Runtime state
+type RuntimeConfigDTO struct {
+	KymaVersion       string `json:"kymaVersion"`
+	KymaProfile       string `json:"kymaProfile,omitempty"`
+	Provider          string `json:"provider"`
+	Region            string `json:"region"`
+	KubernetesVersion string `json:"kubernetesVersion"`
+	MachineType       string `json:"machineType"`
+	AutoScalerMin     int    `json:"autoScalerMin"`
+	AutoScalerMax     int    `json:"autoScalerMax"`
+}
+
+// ProvisionerStatus is the Runtime status reported by the Runtime Provisioner, Error is set when it could not be fetched
+type ProvisionerStatus struct {
+	LastOperation         *ProvisionerOperation `json:"lastOperation,omitempty"`
+	AgentConnectionStatus string                `json:"agentConnectionStatus,omitempty"`
+	Error                 string                `json:"error,omitempty"`
+}
+
+type ProvisionerOperation struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+}
+
+// GardenerStatus is the status of the Runtime's shoot reported by Gardener, Error is set when it could not be fetched
+type GardenerStatus struct {
+	ShootName     string              `json:"shootName,omitempty"`
+	LastOperation *GardenerOperation  `json:"lastOperation,omitempty"`
+	Conditions    []GardenerCondition `json:"conditions,omitempty"`
+	Error         string              `json:"error,omitempty"`
+}
+
+type GardenerOperation struct {
+	Type           string    `json:"type"`
+	State          string    `json:"state"`
+	Progress       int       `json:"progress"`
+	Description    string    `json:"description"`
+	LastUpdateTime time.Time `json:"lastUpdateTime"`
+}
+
+type GardenerCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+// ComponentsUpdate holds the optional components which should be enabled or disabled on the running Runtime
+type ComponentsUpdate struct {
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
+}
+
+// ComponentsDTO describes the optional components of the Runtime
+type ComponentsDTO struct {
+	RuntimeID   string   `json:"runtimeID"`
+	Installed   []string `json:"installed"`
+	Available   []string `json:"available"`
+	OperationID string   `json:"operationID,omitempty"`
+}
+
 type RuntimesPage struct {
 	Data       []RuntimeDTO `json:"data"`
 	Count      int          `json:"count"`
@@ -55,6 +131,38 @@ const (
 	RegionParam          = "region"
 	ShootParam           = "shoot"
 	PlanParam            = "plan"
+	StateParam           = "state"
+	KymaVersionParam     = "kyma_version"
+	CreatedFromParam     = "created_from"
+	CreatedToParam       = "created_to"
+	SearchParam          = "search"
+	SortParam            = "sort"
+	OrderParam           = "order"
+)
+
+// Runtime states resolved from the last operation of the runtime
+const (
+	StateSucceeded  = "succeeded"
+	StateFailed     = "failed"
+	StateInProgress = "in progress"
+	StateSuspended  = "suspended"
+)
+
+// Fields by which the runtimes can be sorted
+const (
+	SortByCreatedAt       = "createdAt"
+	SortByGlobalAccountID = "globalAccountID"
+	SortBySubAccountID    = "subAccountID"
+	SortByRegion          = "region"
+	SortByPlan            = "plan"
+	SortByState           = "state"
+	SortByKymaVersion     = "kymaVersion"
+)
+
+// Sort orders of the runtimes
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
 )
 
 type ListParameters struct {
@@ -67,4 +175,13 @@ type ListParameters struct {
 	Regions          []string
 	Shoots           []string
 	Plans            []string
+	States           []string
+	KymaVersions     []string
+	// CreatedFrom and CreatedTo limit the runtime creation time when not zero
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Search matches any part of the shoot name, global account ID or subaccount ID
+	Search string
+	SortBy string
+	Order  string
 }
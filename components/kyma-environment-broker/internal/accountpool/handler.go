@@ -0,0 +1,54 @@
+package accountpool
+
+import (
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+const hyperscalerParam = "hyperscaler"
+
+type PoolInspector interface {
+	Secrets(hyperscalerType hyperscaler.Type) ([]hyperscaler.PoolSecret, error)
+}
+
+// Handler exposes a read-only view of the hyperscaler account pool
+type Handler struct {
+	inspector PoolInspector
+}
+
+func NewHandler(inspector PoolInspector) *Handler {
+	return &Handler{
+		inspector: inspector,
+	}
+}
+
+func (h *Handler) AttachRoutes(router *mux.Router) {
+	router.HandleFunc("/hyperscaler-accounts", h.getAccounts).Methods(http.MethodGet)
+}
+
+func (h *Handler) getAccounts(w http.ResponseWriter, req *http.Request) {
+	hypType := hyperscaler.Type(req.URL.Query().Get(hyperscalerParam))
+	switch hypType {
+	case "", hyperscaler.GCP, hyperscaler.Azure, hyperscaler.AWS:
+	default:
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Errorf("unknown hyperscaler type %s", hypType))
+		return
+	}
+
+	secrets, err := h.inspector.Secrets(hypType)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching hyperscaler account pool secrets"))
+		return
+	}
+
+	httputil.WriteResponse(w, http.StatusOK, hyperscaler.PoolSecretsPage{
+		Data:  secrets,
+		Count: len(secrets),
+		Stats: hyperscaler.ComputePoolStats(secrets),
+	})
+}
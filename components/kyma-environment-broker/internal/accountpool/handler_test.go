@@ -0,0 +1,71 @@
+package accountpool_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/accountpool"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetAccounts(t *testing.T) {
+	inspector := &fakeInspector{secrets: []hyperscaler.PoolSecret{
+		{Name: "s1", HyperscalerType: hyperscaler.GCP},
+		{Name: "s2", HyperscalerType: hyperscaler.GCP, TenantName: "tenant"},
+		{Name: "s3", HyperscalerType: hyperscaler.Azure, TenantName: "tenant", Dirty: true},
+	}}
+	router := mux.NewRouter()
+	accountpool.NewHandler(inspector).AttachRoutes(router)
+
+	t.Run("should return pool secrets with stats", func(t *testing.T) {
+		// when
+		rr := doRequest(t, router, "/hyperscaler-accounts?hyperscaler=gcp")
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		var page hyperscaler.PoolSecretsPage
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+		assert.Equal(t, 2, page.Count)
+		assert.Equal(t, hyperscaler.GCP, inspector.requestedType)
+		assert.Equal(t, hyperscaler.PoolStats{Free: 1, Assigned: 1}, page.Stats[hyperscaler.GCP])
+	})
+
+	t.Run("should reject unknown hyperscaler type", func(t *testing.T) {
+		// when
+		rr := doRequest(t, router, "/hyperscaler-accounts?hyperscaler=unknown")
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+type fakeInspector struct {
+	secrets       []hyperscaler.PoolSecret
+	requestedType hyperscaler.Type
+}
+
+func (f *fakeInspector) Secrets(hyperscalerType hyperscaler.Type) ([]hyperscaler.PoolSecret, error) {
+	f.requestedType = hyperscalerType
+	var result []hyperscaler.PoolSecret
+	for _, s := range f.secrets {
+		if hyperscalerType == "" || s.HyperscalerType == hyperscalerType {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func doRequest(t *testing.T, router *mux.Router, url string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
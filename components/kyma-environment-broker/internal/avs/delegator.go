@@ -15,6 +15,7 @@ type Delegator struct {
 	avsConfig         Config
 	client            *Client
 	operationsStorage storage.Operations
+	operationUpdater  *storage.OperationUpdater
 }
 
 type avsNonSuccessResp struct {
@@ -28,6 +29,7 @@ func NewDelegator(client *Client, avsConfig Config, operationsStorage storage.Op
 		avsConfig:         avsConfig,
 		client:            client,
 		operationsStorage: operationsStorage,
+		operationUpdater:  storage.NewOperationUpdater(operationsStorage),
 	}
 }
 
@@ -62,9 +64,9 @@ func (del *Delegator) CreateEvaluation(logger logrus.FieldLogger, operation inte
 			return del.operationManager.OperationFailed(operation, errMsg)
 		}
 
-		evalAssistant.SetEvalId(&operation.Avs, evalResp.Id)
-
-		updatedOperation, d = del.operationManager.UpdateOperation(operation)
+		updatedOperation, d = del.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+			evalAssistant.SetEvalId(&operation.Avs, evalResp.Id)
+		})
 	}
 
 	provisionParams, err := updatedOperation.GetProvisioningParameters()
@@ -78,8 +80,6 @@ func (del *Delegator) CreateEvaluation(logger logrus.FieldLogger, operation inte
 
 func (del *Delegator) AddTags(logger logrus.FieldLogger, operation internal.ProvisioningOperation, evalAssistant EvalAssistant, tags []*Tag) (internal.ProvisioningOperation, time.Duration, error) {
 	logger.Infof("starting the AddTag to avs internal id [%d]", operation.Avs.AvsEvaluationInternalId)
-	var updatedOperation internal.ProvisioningOperation
-	d := 0 * time.Second
 
 	logger.Infof("making avs calls to add tags to the Evaluation")
 	evalId := evalAssistant.GetEvaluationId(operation.Avs)
@@ -102,9 +102,7 @@ func (del *Delegator) AddTags(logger logrus.FieldLogger, operation internal.Prov
 		}
 	}
 
-	updatedOperation, d = del.operationManager.UpdateOperation(operation)
-
-	return updatedOperation, d, nil
+	return operation, 0, nil
 }
 
 func (del *Delegator) DeleteAvsEvaluation(deProvisioningOperation internal.DeprovisioningOperation, logger logrus.FieldLogger, assistant EvalAssistant) (internal.DeprovisioningOperation, error) {
@@ -117,9 +115,9 @@ func (del *Delegator) DeleteAvsEvaluation(deProvisioningOperation internal.Depro
 		return deProvisioningOperation, err
 	}
 
-	assistant.markDeleted(&deProvisioningOperation.Avs)
-
-	updatedDeProvisioningOp, err := del.operationsStorage.UpdateDeprovisioningOperation(deProvisioningOperation)
+	updatedDeProvisioningOp, err := del.operationUpdater.UpdateDeprovisioningOperationWithRetry(deProvisioningOperation, func(operation *internal.DeprovisioningOperation) {
+		assistant.markDeleted(&operation.Avs)
+	})
 	if err != nil {
 		return deProvisioningOperation, err
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2/clientcredentials"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -79,6 +80,30 @@ func (c *Client) Deprovision(instance internal.Instance) (string, error) {
 	return response.Operation, nil
 }
 
+// InternalClient triggers Runtime deprovisioning directly on the DeprovisionEndpoint,
+// it is used by processes running inside KEB instead of calling the OSB API
+type InternalClient struct {
+	deprovision *DeprovisionEndpoint
+}
+
+func NewInternalClient(deprovision *DeprovisionEndpoint) *InternalClient {
+	return &InternalClient{
+		deprovision: deprovision,
+	}
+}
+
+// Deprovision triggers Runtime deprovisioning for the given instance and returns the operation ID
+func (c *InternalClient) Deprovision(instance internal.Instance) (string, error) {
+	spec, err := c.deprovision.Deprovision(context.Background(), instance.InstanceID, domain.DeprovisionDetails{
+		ServiceID: kymaClassID,
+		PlanID:    instance.ServicePlanID,
+	}, true)
+	if err != nil {
+		return "", errors.Wrapf(err, "while deprovisioning instance %s", instance.InstanceID)
+	}
+	return spec.OperationData, nil
+}
+
 func (c *Client) formatDeprovisionUrl(instance internal.Instance) (string, error) {
 	if len(instance.ServicePlanID) == 0 {
 		return "", errors.Errorf("empty ServicePlanID")
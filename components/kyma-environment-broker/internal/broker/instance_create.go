@@ -23,6 +23,7 @@ import (
 
 //go:generate mockery -name=Queue -output=automock -outpkg=automock -case=underscore
 //go:generate mockery -name=PlanValidator -output=automock -outpkg=automock -case=underscore
+//go:generate mockery -name=CapacityChecker -output=automock -outpkg=automock -case=underscore
 
 type (
 	Queue interface {
@@ -32,6 +33,11 @@ type (
 	PlanValidator interface {
 		IsPlanSupport(planID string) bool
 	}
+
+	// CapacityChecker checks if the hyperscaler accounts can take one more cluster for the given parameters
+	CapacityChecker interface {
+		HasCapacity(parameters internal.ProvisioningParameters) (bool, error)
+	}
 )
 
 type ProvisionEndpoint struct {
@@ -41,6 +47,7 @@ type ProvisionEndpoint struct {
 	builderFactory       PlanValidator
 	enabledPlanIDs       map[string]struct{}
 	plansSchemaValidator PlansSchemaValidator
+	capacityChecker      CapacityChecker
 	kymaVerOnDemand      bool
 
 	shootDomain  string
@@ -56,6 +63,7 @@ func NewProvision(cfg Config,
 	queue Queue,
 	builderFactory PlanValidator,
 	validator PlansSchemaValidator,
+	capacityChecker CapacityChecker,
 	kvod bool,
 	log logrus.FieldLogger) *ProvisionEndpoint {
 	enabledPlanIDs := map[string]struct{}{}
@@ -66,6 +74,7 @@ func NewProvision(cfg Config,
 
 	return &ProvisionEndpoint{
 		plansSchemaValidator: validator,
+		capacityChecker:      capacityChecker,
 		operationsStorage:    operationsStorage,
 		instanceStorage:      instanceStorage,
 		queue:                queue,
@@ -118,6 +127,17 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 		return b.handleExistingOperation(existingOperation, provisioningParameters, logger)
 	}
 
+	// reject the request instead of failing the operation when no hyperscaler account can take the cluster
+	hasCapacity, err := b.capacityChecker.HasCapacity(provisioningParameters)
+	switch {
+	case err != nil:
+		logger.Warnf("cannot check capacity of hyperscaler accounts, the operation is accepted: %s", err)
+	case !hasCapacity:
+		logger.Info("Provisioning rejected, all hyperscaler accounts reached the limit of clusters")
+		err := errors.New("all hyperscaler accounts for the region reached the limit of clusters, choose a different region or try again later")
+		return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusUnprocessableEntity, "provisioning")
+	}
+
 	// create SKR shoot name
 	shootName := gardener.CreateShootName()
 	dashboardURL := fmt.Sprintf("https://console.%s.%s.%s", shootName, b.shootProject, strings.Trim(b.shootDomain, "."))
@@ -18,6 +18,7 @@ import (
 
 	"github.com/kyma-incubator/compass/components/director/pkg/jsonschema"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pivotal-cf/brokerapi/v7/domain/apiresponses"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -57,6 +58,7 @@ func TestProvision_Provision(t *testing.T) {
 			queue,
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -115,6 +117,7 @@ func TestProvision_Provision(t *testing.T) {
 			nil,
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -157,6 +160,7 @@ func TestProvision_Provision(t *testing.T) {
 			nil,
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -197,6 +201,7 @@ func TestProvision_Provision(t *testing.T) {
 			queue,
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -232,6 +237,53 @@ func TestProvision_Provision(t *testing.T) {
 		assert.Equal(t, instance.GlobalAccountID, globalAccountID)
 	})
 
+	t.Run("provision trial should be rejected when hyperscaler accounts reached the limit", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		factoryBuilder := &automock.PlanValidator{}
+		factoryBuilder.On("IsPlanSupport", broker.TrialPlanID).Return(true)
+
+		capacityChecker := &automock.CapacityChecker{}
+		capacityChecker.On("HasCapacity", mock.MatchedBy(func(pp internal.ProvisioningParameters) bool {
+			return pp.PlanID == broker.TrialPlanID && pp.PlatformRegion == "req-region"
+		})).Return(false, nil).Once()
+		defer capacityChecker.AssertExpectations(t)
+
+		provisionEndpoint := broker.NewProvision(
+			broker.Config{EnablePlans: []string{"gcp", "azure", "trial"}},
+			gardener.Config{Project: "test", ShootDomain: "example.com"},
+			memoryStorage.Operations(),
+			memoryStorage.Instances(),
+			nil,
+			factoryBuilder,
+			fixAlwaysPassJSONValidator(),
+			capacityChecker,
+			false,
+			logrus.StandardLogger(),
+		)
+
+		// when
+		_, err := provisionEndpoint.Provision(fixReqCtxWithRegion(t, "req-region"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        broker.TrialPlanID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s"}`, clusterName)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s"}`, globalAccountID, subAccountID)),
+		}, true)
+
+		// then
+		require.Error(t, err)
+		failure, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusUnprocessableEntity, failure.ValidatedStatusCode(nil))
+		assert.Contains(t, err.Error(), "reached the limit of clusters")
+
+		_, err = memoryStorage.Operations().GetProvisioningOperationByInstanceID(instanceID)
+		assert.Error(t, err)
+		_, err = memoryStorage.Instances().GetByID(instanceID)
+		assert.Error(t, err)
+	})
+
 	t.Run("conflict should be handled", func(t *testing.T) {
 		// given
 		// #setup memory storage
@@ -253,6 +305,7 @@ func TestProvision_Provision(t *testing.T) {
 			nil,
 			factoryBuilder,
 			fixAlwaysPassJSONValidator(),
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -292,6 +345,7 @@ func TestProvision_Provision(t *testing.T) {
 			nil,
 			factoryBuilder,
 			fixValidator,
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -335,6 +389,7 @@ func TestProvision_Provision(t *testing.T) {
 			nil,
 			factoryBuilder,
 			fixValidator,
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -377,6 +432,7 @@ func TestProvision_Provision(t *testing.T) {
 			queue,
 			factoryBuilder,
 			fixValidator,
+			fixCapacityAvailable(),
 			true,
 			logrus.StandardLogger(),
 		)
@@ -418,6 +474,7 @@ func TestProvision_Provision(t *testing.T) {
 			nil,
 			factoryBuilder,
 			fixValidator,
+			fixCapacityAvailable(),
 			true,
 			logrus.StandardLogger(),
 		)
@@ -455,6 +512,7 @@ func TestProvision_Provision(t *testing.T) {
 			queue,
 			factoryBuilder,
 			fixValidator,
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -501,6 +559,7 @@ func TestProvision_Provision(t *testing.T) {
 			queue,
 			factoryBuilder,
 			fixValidator,
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -544,6 +603,7 @@ func TestProvision_Provision(t *testing.T) {
 			queue,
 			factoryBuilder,
 			fixValidator,
+			fixCapacityAvailable(),
 			false,
 			logrus.StandardLogger(),
 		)
@@ -592,6 +652,13 @@ func fixAlwaysPassJSONValidator() broker.PlansSchemaValidator {
 	return fixValidator
 }
 
+func fixCapacityAvailable() broker.CapacityChecker {
+	checkerMock := &automock.CapacityChecker{}
+	checkerMock.On("HasCapacity", mock.Anything).Return(true, nil)
+
+	return checkerMock
+}
+
 func fixInstance() internal.Instance {
 	return internal.Instance{
 		InstanceID:      instanceID,
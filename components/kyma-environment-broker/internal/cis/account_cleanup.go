@@ -1,9 +1,14 @@
 package cis
 
 import (
+	"fmt"
+	"io"
+	"time"
+
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -18,75 +23,259 @@ type BrokerClient interface {
 	Deprovision(instance internal.Instance) (string, error)
 }
 
+type CleanupConfig struct {
+	// DryRun only reports instances which would be deprovisioned
+	DryRun bool `envconfig:"default=false"`
+	// MaxDeletionsPerRun aborts the run when more instances are found, 0 means no limit
+	MaxDeletionsPerRun int `envconfig:"default=100"`
+}
+
 type SubAccountCleanupService struct {
 	client       CisClient
 	brokerClient BrokerClient
 	storage      storage.Instances
+	runs         storage.SubAccountCleanupRuns
+	cfg          CleanupConfig
 	log          logrus.FieldLogger
 	chunksAmount int
 }
 
-func NewSubAccountCleanupService(client CisClient, brokerClient BrokerClient, storage storage.Instances, log logrus.FieldLogger) *SubAccountCleanupService {
+func NewSubAccountCleanupService(client CisClient, brokerClient BrokerClient, storage storage.Instances, runs storage.SubAccountCleanupRuns, cfg CleanupConfig, log logrus.FieldLogger) *SubAccountCleanupService {
 	return &SubAccountCleanupService{
 		client:       client,
 		brokerClient: brokerClient,
 		storage:      storage,
+		runs:         runs,
+		cfg:          cfg,
 		log:          log,
 		chunksAmount: 50,
 	}
 }
 
-func (ac *SubAccountCleanupService) Run() error {
+// Run executes the cleanup process and persists the record of the run.
+// The error is returned also when the instances of some subaccounts could not be found.
+func (ac *SubAccountCleanupService) Run() (internal.SubAccountCleanupRun, error) {
+	run := ac.newRun()
+
 	subaccounts, err := ac.client.FetchSubAccountsToDelete()
 	if err != nil {
-		return errors.Wrap(err, "while fetching subaccounts by client")
+		return run, errors.Wrap(err, "while fetching subaccounts by client")
+	}
+	run.SubAccounts = subaccounts
+
+	batches, amount := ac.findInstances(&run, subaccounts, ac.storage.FindAllInstancesForSubAccounts)
+
+	run, err = ac.process(run, batches, amount)
+	if err != nil {
+		return run, err
+	}
+	if len(run.LookupErrors) > 0 {
+		// the instances found are deprovisioned anyway, the remaining ones are found by the next run
+		return run, errors.Errorf("SubAccount cleanup process failed: %d instance lookups failed", len(run.LookupErrors))
+	}
+	return run, nil
+}
+
+// CleanupAccounts deprovisions instances which belong to the given subaccounts or global accounts
+// and persists the record of the run
+func (ac *SubAccountCleanupService) CleanupAccounts(subaccounts, globalAccounts []string) (internal.SubAccountCleanupRun, error) {
+	run := ac.newRun()
+	run.SubAccounts = subaccounts
+	run.GlobalAccounts = globalAccounts
+
+	batches, _ := ac.findInstances(&run, subaccounts, ac.storage.FindAllInstancesForSubAccounts)
+	gaBatches, _ := ac.findInstances(&run, globalAccounts, ac.storage.FindAllInstancesForGlobalAccounts)
+	batches, amount := uniqueInstances(append(batches, gaBatches...))
+
+	return ac.process(run, batches, amount)
+}
+
+func (ac *SubAccountCleanupService) newRun() internal.SubAccountCleanupRun {
+	return internal.SubAccountCleanupRun{
+		ID:        uuid.New().String(),
+		DryRun:    ac.cfg.DryRun,
+		CreatedAt: time.Now(),
+	}
+}
+
+func (ac *SubAccountCleanupService) process(run internal.SubAccountCleanupRun, batches [][]internal.Instance, amount int) (internal.SubAccountCleanupRun, error) {
+	switch {
+	case ac.cfg.MaxDeletionsPerRun > 0 && amount > ac.cfg.MaxDeletionsPerRun:
+		run.Aborted = true
+		run.Instances = instancesToRecords(batches)
+		run.Description = fmt.Sprintf("found %d instances to deprovision which exceeds the limit of %d deletions per run", amount, ac.cfg.MaxDeletionsPerRun)
+		if err := ac.saveRun(&run); err != nil {
+			return run, err
+		}
+		return run, errors.Errorf("SubAccount cleanup process aborted: %s", run.Description)
+	case ac.cfg.DryRun:
+		run.Instances = instancesToRecords(batches)
+		for _, instance := range run.Instances {
+			ac.log.Infof("[dry run] instance %s (SubAccountID: %s) would be deprovisioned", instance.InstanceID, instance.SubAccountID)
+		}
+		run.Description = fmt.Sprintf("dry run finished, %d instances would be deprovisioned", amount)
+	default:
+		run.Instances = ac.deprovision(batches)
+		run.Description = fmt.Sprintf("deprovisioning triggered for %d instances", amount)
+	}
+
+	if err := ac.saveRun(&run); err != nil {
+		return run, err
+	}
+
+	ac.log.Info("SubAccount cleanup process finished")
+	return run, nil
+}
+
+// findInstances records failed lookups in the run, the instances of the affected accounts are not deprovisioned
+func (ac *SubAccountCleanupService) findInstances(run *internal.SubAccountCleanupRun, accounts []string, find func([]string) ([]internal.Instance, error)) ([][]internal.Instance, int) {
+	var (
+		batches [][]internal.Instance
+		amount  int
+	)
+
+	for _, chunk := range chunk(ac.chunksAmount, accounts) {
+		instances, err := find(chunk)
+		if err != nil {
+			err = errors.Wrap(err, "while finding all instances by accounts")
+			ac.log.Warnf("part of deprovisioning process failed with error: %s", err)
+			run.LookupErrors = append(run.LookupErrors, err.Error())
+			continue
+		}
+		if len(instances) == 0 {
+			continue
+		}
+		batches = append(batches, instances)
+		amount += len(instances)
 	}
 
-	subaccountsBatch := chunk(ac.chunksAmount, subaccounts)
-	chunks := len(subaccountsBatch)
-	errCh := make(chan error)
+	return batches, amount
+}
+
+func (ac *SubAccountCleanupService) deprovision(batches [][]internal.Instance) []internal.SubAccountCleanupRunInstance {
+	resultCh := make(chan internal.SubAccountCleanupRunInstance)
 	done := make(chan struct{})
-	var isDone bool
+	chunks := len(batches)
+	result := make([]internal.SubAccountCleanupRunInstance, 0)
 
-	for _, chunk := range subaccountsBatch {
-		go ac.executeDeprovisioning(chunk, done, errCh)
+	for _, batch := range batches {
+		go ac.executeDeprovisioning(batch, done, resultCh)
 	}
 
-	for !isDone {
+	for chunks > 0 {
 		select {
-		case err := <-errCh:
-			ac.log.Warnf("part of deprovisioning process failed with error: %s", err)
+		case r := <-resultCh:
+			if r.Error != "" {
+				ac.log.Warnf("part of deprovisioning process failed with error: %s", r.Error)
+			}
+			result = append(result, r)
 		case <-done:
 			chunks--
-			if chunks == 0 {
-				isDone = true
-			}
 		}
 	}
 
-	ac.log.Info("SubAccount cleanup process finished")
-	return nil
+	return result
 }
 
-func (ac *SubAccountCleanupService) executeDeprovisioning(subaccounts []string, done chan<- struct{}, errCh chan<- error) {
-	instances, err := ac.storage.FindAllInstancesForSubAccounts(subaccounts)
-	if err != nil {
-		errCh <- errors.Wrap(err, "while finding all instances by subaccounts")
-		return
-	}
-
+func (ac *SubAccountCleanupService) executeDeprovisioning(instances []internal.Instance, done chan<- struct{}, resultCh chan<- internal.SubAccountCleanupRunInstance) {
 	for _, instance := range instances {
+		r := internal.SubAccountCleanupRunInstance{
+			InstanceID:   instance.InstanceID,
+			SubAccountID: instance.SubAccountID,
+		}
 		operation, err := ac.brokerClient.Deprovision(instance)
 		if err != nil {
-			errCh <- errors.Wrapf(err, "error occurred during deprovisioning instance with ID %s", instance.InstanceID)
+			r.Error = errors.Wrapf(err, "error occurred during deprovisioning instance with ID %s", instance.InstanceID).Error()
+			resultCh <- r
 			continue
 		}
+		r.OperationID = operation
 		ac.log.Infof("deprovisioning for instance %s (SubAccountID: %s) was triggered, operation: %s", instance.InstanceID, instance.SubAccountID, operation)
+		resultCh <- r
 	}
 
 	done <- struct{}{}
 }
 
+func (ac *SubAccountCleanupService) saveRun(run *internal.SubAccountCleanupRun) error {
+	run.FinishedAt = time.Now()
+	if err := ac.runs.Insert(*run); err != nil {
+		return errors.Wrapf(err, "while saving subaccount cleanup run %s", run.ID)
+	}
+	return nil
+}
+
+// WriteSummary prints a human readable report of the cleanup run
+func WriteSummary(w io.Writer, run internal.SubAccountCleanupRun) {
+	var failed int
+	for _, instance := range run.Instances {
+		if instance.Error != "" {
+			failed++
+		}
+	}
+
+	fmt.Fprintf(w, "SubAccount cleanup run %s\n", run.ID)
+	fmt.Fprintf(w, "  dry run:      %t\n", run.DryRun)
+	fmt.Fprintf(w, "  aborted:      %t\n", run.Aborted)
+	fmt.Fprintf(w, "  subaccounts:  %d\n", len(run.SubAccounts))
+	fmt.Fprintf(w, "  instances:    %d\n", len(run.Instances))
+	fmt.Fprintf(w, "  failed:       %d\n", failed)
+	fmt.Fprintf(w, "  lookup errors: %d\n", len(run.LookupErrors))
+	fmt.Fprintf(w, "  description:  %s\n", run.Description)
+	for _, instance := range run.Instances {
+		switch {
+		case instance.Error != "":
+			fmt.Fprintf(w, "  - %s (SubAccountID: %s) failed: %s\n", instance.InstanceID, instance.SubAccountID, instance.Error)
+		case instance.OperationID != "":
+			fmt.Fprintf(w, "  - %s (SubAccountID: %s) operation: %s\n", instance.InstanceID, instance.SubAccountID, instance.OperationID)
+		default:
+			fmt.Fprintf(w, "  - %s (SubAccountID: %s)\n", instance.InstanceID, instance.SubAccountID)
+		}
+	}
+	for _, lookupErr := range run.LookupErrors {
+		fmt.Fprintf(w, "  - lookup failed: %s\n", lookupErr)
+	}
+}
+
+func uniqueInstances(batches [][]internal.Instance) ([][]internal.Instance, int) {
+	var (
+		result [][]internal.Instance
+		amount int
+	)
+	seen := map[string]struct{}{}
+
+	for _, batch := range batches {
+		var unique []internal.Instance
+		for _, instance := range batch {
+			if _, ok := seen[instance.InstanceID]; ok {
+				continue
+			}
+			seen[instance.InstanceID] = struct{}{}
+			unique = append(unique, instance)
+		}
+		if len(unique) == 0 {
+			continue
+		}
+		result = append(result, unique)
+		amount += len(unique)
+	}
+
+	return result, amount
+}
+
+func instancesToRecords(batches [][]internal.Instance) []internal.SubAccountCleanupRunInstance {
+	result := make([]internal.SubAccountCleanupRunInstance, 0)
+	for _, batch := range batches {
+		for _, instance := range batch {
+			result = append(result, internal.SubAccountCleanupRunInstance{
+				InstanceID:   instance.InstanceID,
+				SubAccountID: instance.SubAccountID,
+			})
+		}
+	}
+	return result
+}
+
 func chunk(amount int, data []string) [][]string {
 	var divided [][]string
 
@@ -1,6 +1,7 @@
 package cis
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // subAccountTestIDs contains test data in form: InstanceID : SubAccountID
@@ -46,14 +48,23 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 			assert.NoError(t, err)
 		}
 
-		service := NewSubAccountCleanupService(cisClient, brokerClient, memoryStorage.Instances(), logrus.New())
+		service := NewSubAccountCleanupService(cisClient, brokerClient, memoryStorage.Instances(), memoryStorage.SubAccountCleanupRuns(), CleanupConfig{}, logrus.New())
 		service.chunksAmount = 2
 
 		// When
-		err := service.Run()
+		run, err := service.Run()
 
 		// Then
 		assert.NoError(t, err)
+		assert.Len(t, run.Instances, len(subAccountTestIDs))
+		for _, instance := range run.Instances {
+			assert.Equal(t, "<operationUUID>", instance.OperationID)
+		}
+
+		storedRun, err := memoryStorage.SubAccountCleanupRuns().GetByID(run.ID)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, fixSubAccountIDs(), storedRun.SubAccounts)
+		assert.False(t, storedRun.DryRun)
 	})
 
 	t.Run("some deprovisioning should failed and warnings should be displayed", func(t *testing.T) {
@@ -82,11 +93,11 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 		}
 
 		log := logger.NewLogSpy()
-		service := NewSubAccountCleanupService(cisClient, brokerClient, memoryStorage.Instances(), log.Logger)
+		service := NewSubAccountCleanupService(cisClient, brokerClient, memoryStorage.Instances(), memoryStorage.SubAccountCleanupRuns(), CleanupConfig{}, log.Logger)
 		service.chunksAmount = 5
 
 		// When
-		err := service.Run()
+		_, err := service.Run()
 
 		// Then
 		assert.NoError(t, err)
@@ -103,15 +114,128 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 		brokerClient := &mocks.BrokerClient{}
 		memoryStorage := storage.NewMemoryStorage()
 
-		service := NewSubAccountCleanupService(cisClient, brokerClient, memoryStorage.Instances(), logrus.New())
+		service := NewSubAccountCleanupService(cisClient, brokerClient, memoryStorage.Instances(), memoryStorage.SubAccountCleanupRuns(), CleanupConfig{}, logrus.New())
 		service.chunksAmount = 7
 
 		// When
-		err := service.Run()
+		_, err := service.Run()
 
 		// Then
 		assert.Error(t, err)
 	})
+
+	t.Run("process should return with error when instances of subaccounts could not be found", func(t *testing.T) {
+		// Given
+		cisClient := &mocks.CisClient{}
+		cisClient.On("FetchSubAccountsToDelete").Return(fixSubAccountIDs(), nil)
+		defer cisClient.AssertExpectations(t)
+
+		brokerClient := &mocks.BrokerClient{}
+		defer brokerClient.AssertExpectations(t)
+
+		memoryStorage := storage.NewMemoryStorage()
+		instances := &subAccountLookupFailure{Instances: memoryStorage.Instances()}
+
+		service := NewSubAccountCleanupService(cisClient, brokerClient, instances, memoryStorage.SubAccountCleanupRuns(), CleanupConfig{}, logrus.New())
+
+		// When
+		run, err := service.Run()
+
+		// Then
+		assert.Error(t, err)
+		storedRun, err := memoryStorage.SubAccountCleanupRuns().GetByID(run.ID)
+		assert.NoError(t, err)
+		assert.Len(t, storedRun.LookupErrors, 1)
+	})
+
+	t.Run("dry run should not deprovision instances", func(t *testing.T) {
+		// Given
+		cisClient := &mocks.CisClient{}
+		cisClient.On("FetchSubAccountsToDelete").Return(fixSubAccountIDs(), nil)
+		defer cisClient.AssertExpectations(t)
+
+		brokerClient := &mocks.BrokerClient{}
+		defer brokerClient.AssertExpectations(t)
+
+		memoryStorage := storage.NewMemoryStorage()
+		for _, instance := range fixInstances() {
+			err := memoryStorage.Instances().Insert(instance)
+			assert.NoError(t, err)
+		}
+
+		log := logger.NewLogSpy()
+		service := NewSubAccountCleanupService(cisClient, brokerClient, memoryStorage.Instances(), memoryStorage.SubAccountCleanupRuns(), CleanupConfig{DryRun: true}, log.Logger)
+		service.chunksAmount = 3
+
+		// When
+		run, err := service.Run()
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, run.DryRun)
+		assert.Len(t, run.Instances, len(subAccountTestIDs))
+		brokerClient.AssertNotCalled(t, "Deprovision", mock.Anything)
+		log.AssertLogged(t, logrus.InfoLevel, "[dry run] instance 7b84e7e7-62df-412a-9e09-b4581253efba (SubAccountID: e65a807a-488f-4062-9e02-b39090ec0258) would be deprovisioned")
+
+		_, err = memoryStorage.SubAccountCleanupRuns().GetByID(run.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("process should be aborted when limit of deletions is exceeded", func(t *testing.T) {
+		// Given
+		cisClient := &mocks.CisClient{}
+		cisClient.On("FetchSubAccountsToDelete").Return(fixSubAccountIDs(), nil)
+		defer cisClient.AssertExpectations(t)
+
+		brokerClient := &mocks.BrokerClient{}
+		defer brokerClient.AssertExpectations(t)
+
+		memoryStorage := storage.NewMemoryStorage()
+		for _, instance := range fixInstances() {
+			err := memoryStorage.Instances().Insert(instance)
+			assert.NoError(t, err)
+		}
+
+		service := NewSubAccountCleanupService(cisClient, brokerClient, memoryStorage.Instances(), memoryStorage.SubAccountCleanupRuns(), CleanupConfig{MaxDeletionsPerRun: 5}, logrus.New())
+		service.chunksAmount = 4
+
+		// When
+		run, err := service.Run()
+
+		// Then
+		assert.Error(t, err)
+		assert.True(t, run.Aborted)
+		brokerClient.AssertNotCalled(t, "Deprovision", mock.Anything)
+
+		storedRun, err := memoryStorage.SubAccountCleanupRuns().GetByID(run.ID)
+		assert.NoError(t, err)
+		assert.True(t, storedRun.Aborted)
+		assert.Len(t, storedRun.Instances, len(subAccountTestIDs))
+	})
+}
+
+func TestWriteSummary(t *testing.T) {
+	// Given
+	run := internal.SubAccountCleanupRun{
+		ID:          "run-id",
+		SubAccounts: []string{"sa-1", "sa-2"},
+		Instances: []internal.SubAccountCleanupRunInstance{
+			{InstanceID: "inst-1", SubAccountID: "sa-1", OperationID: "op-1"},
+			{InstanceID: "inst-2", SubAccountID: "sa-2", Error: "cannot deprovision"},
+		},
+		LookupErrors: []string{"while finding all instances by accounts: connection refused"},
+	}
+	buf := &bytes.Buffer{}
+
+	// When
+	WriteSummary(buf, run)
+
+	// Then
+	assert.Contains(t, buf.String(), "SubAccount cleanup run run-id")
+	assert.Contains(t, buf.String(), "failed:       1")
+	assert.Contains(t, buf.String(), "- inst-1 (SubAccountID: sa-1) operation: op-1")
+	assert.Contains(t, buf.String(), "- inst-2 (SubAccountID: sa-2) failed: cannot deprovision")
+	assert.Contains(t, buf.String(), "- lookup failed: while finding all instances by accounts: connection refused")
 }
 
 func fixSubAccountIDs() []string {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 const (
 	eventServicePath = "%s/events/v1/events/central"
 	eventType        = "Subaccount_Deletion"
+	gaEventType      = "GlobalAccount_Deletion"
 	defaultPageSize  = "150"
 )
 
@@ -84,7 +86,7 @@ func (c *Client) FetchSubAccountsToDelete() ([]string, error) {
 }
 
 func (c *Client) fetchSubAccountsFromDeleteEvents(collection *subAccounts, page int) error {
-	request, err := c.buildRequest(page)
+	request, err := c.buildRequest(eventType, page, "creationTime", time.Time{})
 	if err != nil {
 		return errors.Wrap(err, "while building request for event service")
 	}
@@ -93,6 +95,7 @@ func (c *Client) fetchSubAccountsFromDeleteEvents(collection *subAccounts, page
 	if err != nil {
 		return errors.Wrap(err, "while executing request to event service")
 	}
+	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
 		return fmt.Errorf("while processing response: %s", c.handleWrongStatusCode(response))
@@ -127,18 +130,96 @@ func (c *Client) fetchSubAccountsFromDeleteEvents(collection *subAccounts, page
 	return nil
 }
 
-func (c *Client) buildRequest(page int) (*http.Request, error) {
+// FetchDeletionEventsSince returns subaccount and global account deletion events with the action time
+// not earlier than since, sorted from the oldest one
+func (c *Client) FetchDeletionEventsSince(since time.Time) ([]DeletionEvent, error) {
+	var collection []Event
+
+	for _, evType := range []string{eventType, gaEventType} {
+		if err := c.fetchEvents(evType, since, &collection, 0); err != nil {
+			return []DeletionEvent{}, errors.Wrapf(err, "while fetching %s events", evType)
+		}
+	}
+
+	sort.SliceStable(collection, func(i, j int) bool {
+		if collection[i].ActionTime == collection[j].ActionTime {
+			return collection[i].ID < collection[j].ID
+		}
+		return collection[i].ActionTime < collection[j].ActionTime
+	})
+
+	events := make([]DeletionEvent, 0, len(collection))
+	for _, event := range collection {
+		delType := SubAccountDeletion
+		if event.Type == gaEventType {
+			delType = GlobalAccountDeletion
+		}
+		events = append(events, DeletionEvent{
+			ID:         strconv.FormatInt(event.ID, 10),
+			Type:       delType,
+			EntityID:   event.SubAccount,
+			ActionTime: time.Unix(0, event.ActionTime*int64(time.Millisecond)),
+		})
+	}
+
+	c.log.Infof("client fetched %d deletion events since %s", len(events), since)
+
+	return events, nil
+}
+
+func (c *Client) fetchEvents(evType string, since time.Time, collection *[]Event, page int) error {
+	// events are sorted by the action time, the same order as the one of the high-water mark
+	request, err := c.buildRequest(evType, page, "actionTime", since)
+	if err != nil {
+		return errors.Wrap(err, "while building request for event service")
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "while executing request to event service")
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("while processing response: %s", c.handleWrongStatusCode(response))
+	}
+
+	var cisResponse CisResponse
+	err = json.NewDecoder(response.Body).Decode(&cisResponse)
+	if err != nil {
+		return errors.Wrap(err, "while decoding CIS response")
+	}
+
+	for _, event := range cisResponse.Events {
+		if event.Type != evType {
+			c.log.Warnf("event type %s is not equal to %s, skip event", event.Type, evType)
+			continue
+		}
+		*collection = append(*collection, event)
+	}
+
+	page++
+	if page <= cisResponse.TotalPages {
+		return c.fetchEvents(evType, since, collection, page)
+	}
+	return nil
+}
+
+func (c *Client) buildRequest(evType string, page int, sortField string, since time.Time) (*http.Request, error) {
 	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf(eventServicePath, c.config.EventServiceURL), nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "while creating request")
 	}
 
 	q := request.URL.Query()
-	q.Add("eventType", eventType)
+	q.Add("eventType", evType)
 	q.Add("pageSize", c.config.PageSize)
 	q.Add("pageNum", strconv.Itoa(page))
-	q.Add("sortField", "creationTime")
+	q.Add("sortField", sortField)
 	q.Add("sortOrder", "ASC")
+	if !since.IsZero() {
+		q.Add("fromActionTime", strconv.FormatInt(since.UnixNano()/int64(time.Millisecond), 10))
+	}
 
 	request.URL.RawQuery = q.Encode()
 
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
 
@@ -17,6 +18,8 @@ const (
 	subAccountTest1 = "fda14cab-bacc-4d0b-a10f-18557a6d9060"
 	subAccountTest2 = "7514cf27-41b0-4266-a273-637cb3a2c051"
 	subAccountTest3 = "47af15c8-adfe-4404-8675-525a878c4601"
+
+	globalAccountTest1 = "3c2ef4d4-0c38-4b8b-9e2b-7b2a6a0e5d3e"
 )
 
 func TestClient_FetchSubAccountsToDelete(t *testing.T) {
@@ -62,9 +65,42 @@ func TestClient_FetchSubAccountsToDelete(t *testing.T) {
 	})
 }
 
+func TestClient_FetchDeletionEventsSince(t *testing.T) {
+	// Given
+	srv := newServer(t)
+	testServer := fixHTTPServer(srv)
+	defer testServer.Close()
+
+	client := NewClient(context.TODO(), Config{
+		EventServiceURL: testServer.URL,
+		PageSize:        "3",
+	}, logger.NewLogDummy())
+	client.SetHttpClient(testServer.Client())
+
+	// When
+	events, err := client.FetchDeletionEventsSince(time.Unix(1597000000, 0))
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	require.Equal(t, "1597000000000", srv.fromActionTime)
+	require.Equal(t, "actionTime", srv.sortField)
+	require.Equal(t, DeletionEvent{
+		ID:         "629224",
+		Type:       SubAccountDeletion,
+		EntityID:   subAccountTest3,
+		ActionTime: time.Unix(0, 1597090066116*int64(time.Millisecond)),
+	}, events[0])
+	require.Equal(t, GlobalAccountDeletion, events[2].Type)
+	require.Equal(t, globalAccountTest1, events[2].EntityID)
+	require.Equal(t, subAccountTest1, events[3].EntityID)
+}
+
 type server struct {
-	serverErr bool
-	t         *testing.T
+	serverErr      bool
+	fromActionTime string
+	sortField      string
+	t              *testing.T
 }
 
 func newServer(t *testing.T) *server {
@@ -83,6 +119,25 @@ func fixHTTPServer(srv *server) *httptest.Server {
 
 func (s *server) returnCISEvents(w http.ResponseWriter, r *http.Request) {
 	eventType := r.URL.Query().Get("eventType")
+	s.fromActionTime = r.URL.Query().Get("fromActionTime")
+	s.sortField = r.URL.Query().Get("sortField")
+	if eventType == "GlobalAccount_Deletion" {
+		s.writeResponse(w, []byte(fmt.Sprintf(`{
+			"total": 1,
+			"totalPages": 1,
+			"pageNum": 0,
+			"events": [
+				{
+					"id": 629300,
+					"actionTime": 1597100000000,
+					"creationTime": 1597100000100,
+					"entityId": "%s",
+					"entityType": "GlobalAccount",
+					"eventType": "GlobalAccount_Deletion"
+				}]
+		}`, globalAccountTest1)))
+		return
+	}
 	if eventType != "Subaccount_Deletion" {
 		w.WriteHeader(http.StatusNotFound)
 		return
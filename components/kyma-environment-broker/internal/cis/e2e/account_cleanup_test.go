@@ -61,10 +61,10 @@ func TestSubAccountCleanup(t *testing.T) {
 		brokerClient := NewFakeBrokerClient(storageManager.Instances())
 
 		t.Log("create subaccount cleanup service")
-		sacs := cis.NewSubAccountCleanupService(client, brokerClient, storageManager.Instances(), logger.NewLogDummy())
+		sacs := cis.NewSubAccountCleanupService(client, brokerClient, storageManager.Instances(), storageManager.SubAccountCleanupRuns(), cis.CleanupConfig{}, logger.NewLogDummy())
 
 		// When
-		err = sacs.Run()
+		_, err = sacs.Run()
 
 		// Then
 		require.NoError(t, err)
@@ -112,10 +112,10 @@ func TestSubAccountCleanup(t *testing.T) {
 		brokerClient := NewFakeBrokerClient(storageManager.Instances())
 
 		t.Log("create subaccount cleanup service")
-		sacs := cis.NewSubAccountCleanupService(client, brokerClient, storageManager.Instances(), logger.NewLogDummy())
+		sacs := cis.NewSubAccountCleanupService(client, brokerClient, storageManager.Instances(), storageManager.SubAccountCleanupRuns(), cis.CleanupConfig{}, logger.NewLogDummy())
 
 		// When
-		err = sacs.Run()
+		_, err = sacs.Run()
 
 		// Then
 		require.NoError(t, err)
@@ -30,6 +30,12 @@ func initTestDBInstancesTables(t *testing.T, connectionURL string) error {
 	}
 	t.Log("Table Instances added to database")
 
+	if _, err := connection.Exec(tables[postsql.SubAccountCleanupRunTableName]); err != nil {
+		t.Log("Cannot create table SubAccountCleanupRuns")
+		return err
+	}
+	t.Log("Table SubAccountCleanupRuns added to database")
+
 	return nil
 }
 
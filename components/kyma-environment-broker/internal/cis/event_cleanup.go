@@ -0,0 +1,196 @@
+package cis
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type EventClient interface {
+	FetchDeletionEventsSince(since time.Time) ([]DeletionEvent, error)
+}
+
+// EventCleanupConfig configures the account cleanup driven by CIS events which runs inside KEB
+type EventCleanupConfig struct {
+	Disabled           bool          `envconfig:"default=true"`
+	Interval           time.Duration `envconfig:"default=10m"`
+	DryRun             bool          `envconfig:"default=false"`
+	MaxDeletionsPerRun int           `envconfig:"default=100"`
+	// MaxEventsPerRun limits the number of events processed in a single run, the remaining ones are processed in the next runs
+	MaxEventsPerRun int `envconfig:"default=50"`
+	// MaxEventAttempts is the number of runs in which an event is processed before it is skipped
+	MaxEventAttempts int `envconfig:"default=5"`
+}
+
+func (c EventCleanupConfig) CleanupConfig() CleanupConfig {
+	return CleanupConfig{
+		DryRun:             c.DryRun,
+		MaxDeletionsPerRun: c.MaxDeletionsPerRun,
+	}
+}
+
+// dryRunMarkSuffix is appended to the source of the high-water mark used in dry run,
+// so dry runs process every event once and do not move the mark of the real cleanup
+const dryRunMarkSuffix = "-dry-run"
+
+// EventCleanupService processes only CIS deletion events which were not processed yet.
+// The last processed event is stored as a high-water mark in the database.
+type EventCleanupService struct {
+	client           EventClient
+	cleanup          *SubAccountCleanupService
+	marks            storage.CISHighWaterMarks
+	source           string
+	interval         time.Duration
+	maxEventsPerRun  int
+	maxEventAttempts int
+	log              logrus.FieldLogger
+}
+
+func NewEventCleanupService(client EventClient, cleanup *SubAccountCleanupService, marks storage.CISHighWaterMarks, source string, cfg EventCleanupConfig, log logrus.FieldLogger) *EventCleanupService {
+	if cfg.DryRun {
+		source = source + dryRunMarkSuffix
+	}
+	return &EventCleanupService{
+		client:           client,
+		cleanup:          cleanup,
+		marks:            marks,
+		source:           source,
+		interval:         cfg.Interval,
+		maxEventsPerRun:  cfg.MaxEventsPerRun,
+		maxEventAttempts: cfg.MaxEventAttempts,
+		log:              log,
+	}
+}
+
+// Run processes new events periodically until the stop channel is closed
+func (s *EventCleanupService) Run(stop <-chan struct{}) {
+	wait.Until(func() {
+		if err := s.ProcessNewEvents(); err != nil {
+			s.log.Errorf("while processing CIS deletion events: %s", err)
+		}
+	}, s.interval, stop)
+}
+
+// ProcessNewEvents fetches events newer than the high-water mark and deprovisions the instances of deleted accounts.
+// Events are processed one by one in the order of their action time and the mark is moved after every processed event.
+// When an event fails, the run stops and the event is processed again in the next run. An event which failed
+// in MaxEventAttempts runs is skipped, the failures are kept in the records of its cleanup runs.
+// An event whose cleanup run was aborted because of the deletions limit is never skipped, the processing stops
+// at the event until an operator resolves it. Dry runs use a separate mark.
+// Deprovisioning is idempotent, so processing an event twice is safe.
+func (s *EventCleanupService) ProcessNewEvents() error {
+	mark, err := s.marks.Get(s.source)
+	switch {
+	case err == nil:
+	case dberr.IsNotFound(err):
+		mark = internal.CISHighWaterMark{Source: s.source}
+	default:
+		return errors.Wrap(err, "while getting CIS high-water mark")
+	}
+
+	events, err := s.client.FetchDeletionEventsSince(mark.LastEventTime)
+	if err != nil {
+		return errors.Wrap(err, "while fetching CIS deletion events")
+	}
+	events = newerThan(events, mark)
+	if len(events) == 0 {
+		s.log.Infof("no new CIS deletion events since %s", mark.LastEventTime)
+		return nil
+	}
+	if s.maxEventsPerRun > 0 && len(events) > s.maxEventsPerRun {
+		s.log.Infof("found %d new CIS deletion events, %d of them are processed in this run", len(events), s.maxEventsPerRun)
+		events = events[:s.maxEventsPerRun]
+	}
+
+	for _, event := range events {
+		run, err := s.cleanupAccount(event)
+		switch {
+		case run.Aborted:
+			return errors.Errorf("cleanup run %s of event %s for %s account %s was aborted and requires operator action, high-water mark is not moved: %s",
+				run.ID, event.ID, event.Type, event.EntityID, run.Description)
+		case err == nil && !hasFailures(run):
+			mark.FailedAttempts = 0
+		case mark.FailedAttempts+1 < s.maxEventAttempts:
+			mark.FailedAttempts++
+			s.log.Warnf("cleanup run %s of event %s failed (attempt %d of %d), high-water mark is not moved: %v",
+				run.ID, event.ID, mark.FailedAttempts, s.maxEventAttempts, err)
+			if err := s.marks.Save(mark); err != nil {
+				return errors.Wrap(err, "while saving CIS high-water mark")
+			}
+			return nil
+		default:
+			s.log.Errorf("cleanup run %s of event %s for %s account %s failed %d times, skipping the event: %v",
+				run.ID, event.ID, event.Type, event.EntityID, mark.FailedAttempts+1, err)
+			mark.FailedAttempts = 0
+		}
+
+		mark.LastEventID = event.ID
+		mark.LastEventTime = event.ActionTime
+		if err := s.marks.Save(mark); err != nil {
+			return errors.Wrap(err, "while saving CIS high-water mark")
+		}
+		s.log.Infof("event %s processed by cleanup run %s, high-water mark moved", event.ID, run.ID)
+	}
+
+	return nil
+}
+
+func (s *EventCleanupService) cleanupAccount(event DeletionEvent) (internal.SubAccountCleanupRun, error) {
+	if event.Type == GlobalAccountDeletion {
+		return s.cleanup.CleanupAccounts(nil, []string{event.EntityID})
+	}
+	return s.cleanup.CleanupAccounts([]string{event.EntityID}, nil)
+}
+
+// newerThan returns events which are after the mark in the order of the action time and the numeric ID,
+// which is the order of the fetched events, so events with the same action time are processed only once
+func newerThan(events []DeletionEvent, mark internal.CISHighWaterMark) []DeletionEvent {
+	var result []DeletionEvent
+	for _, event := range events {
+		if event.ActionTime.Before(mark.LastEventTime) {
+			continue
+		}
+		if event.ActionTime.Equal(mark.LastEventTime) && compareEventIDs(event.ID, mark.LastEventID) <= 0 {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result
+}
+
+// compareEventIDs compares numeric IDs of the CIS events, IDs which are not numbers (e.g. the empty ID of a new mark)
+// are compared as strings
+func compareEventIDs(a, b string) int {
+	numA, errA := strconv.ParseInt(a, 10, 64)
+	numB, errB := strconv.ParseInt(b, 10, 64)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case numA < numB:
+		return -1
+	case numA > numB:
+		return 1
+	}
+	return 0
+}
+
+func hasFailures(run internal.SubAccountCleanupRun) bool {
+	if len(run.LookupErrors) > 0 {
+		return true
+	}
+	for _, instance := range run.Instances {
+		if instance.Error != "" {
+			return true
+		}
+	}
+	return false
+}
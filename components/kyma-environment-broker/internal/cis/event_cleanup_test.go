@@ -0,0 +1,293 @@
+package cis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	mocks "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/cis/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixSource = "CIS-2.0"
+
+func TestEventCleanupService_ProcessNewEvents(t *testing.T) {
+	t.Run("should deprovision instances of deleted accounts and move the mark", func(t *testing.T) {
+		// Given
+		memoryStorage := storage.NewMemoryStorage()
+		saInstance := internal.Instance{InstanceID: "inst-sa", SubAccountID: "sa-1", GlobalAccountID: "ga-0"}
+		gaInstance := internal.Instance{InstanceID: "inst-ga", SubAccountID: "sa-2", GlobalAccountID: "ga-1"}
+		require.NoError(t, memoryStorage.Instances().Insert(saInstance))
+		require.NoError(t, memoryStorage.Instances().Insert(gaInstance))
+
+		brokerClient := &mocks.BrokerClient{}
+		brokerClient.On("Deprovision", saInstance).Return("op-sa", nil).Once()
+		brokerClient.On("Deprovision", gaInstance).Return("op-ga", nil).Once()
+		defer brokerClient.AssertExpectations(t)
+
+		client := &fakeEventClient{events: []DeletionEvent{
+			fixDeletionEvent("1", SubAccountDeletion, "sa-1", 1000),
+			fixDeletionEvent("2", GlobalAccountDeletion, "ga-1", 2000),
+		}}
+		svc := fixEventCleanupService(client, brokerClient, memoryStorage, fixEventCleanupConfig())
+
+		// When
+		err := svc.ProcessNewEvents()
+
+		// Then
+		require.NoError(t, err)
+		mark, err := memoryStorage.CISHighWaterMarks().Get(fixSource)
+		require.NoError(t, err)
+		assert.Equal(t, "2", mark.LastEventID)
+		assert.True(t, mark.LastEventTime.Equal(time.Unix(2, 0)))
+	})
+
+	t.Run("should skip already processed events", func(t *testing.T) {
+		// Given
+		memoryStorage := storage.NewMemoryStorage()
+		require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: "inst-sa", SubAccountID: "sa-1"}))
+		require.NoError(t, memoryStorage.CISHighWaterMarks().Save(internal.CISHighWaterMark{
+			Source:        fixSource,
+			LastEventID:   "1",
+			LastEventTime: time.Unix(1, 0),
+		}))
+
+		brokerClient := &mocks.BrokerClient{}
+		defer brokerClient.AssertExpectations(t)
+
+		client := &fakeEventClient{events: []DeletionEvent{
+			fixDeletionEvent("1", SubAccountDeletion, "sa-1", 1000),
+		}}
+		svc := fixEventCleanupService(client, brokerClient, memoryStorage, fixEventCleanupConfig())
+
+		// When
+		err := svc.ProcessNewEvents()
+
+		// Then
+		require.NoError(t, err)
+		assert.True(t, client.since.Equal(time.Unix(1, 0)))
+	})
+
+	t.Run("should move a separate mark in dry run", func(t *testing.T) {
+		// Given
+		memoryStorage := storage.NewMemoryStorage()
+		require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: "inst-sa", SubAccountID: "sa-1"}))
+
+		brokerClient := &mocks.BrokerClient{}
+		defer brokerClient.AssertExpectations(t)
+
+		client := &fakeEventClient{events: []DeletionEvent{
+			fixDeletionEvent("1", SubAccountDeletion, "sa-1", 1000),
+		}}
+		cfg := fixEventCleanupConfig()
+		cfg.DryRun = true
+		svc := fixEventCleanupService(client, brokerClient, memoryStorage, cfg)
+
+		// When
+		require.NoError(t, svc.ProcessNewEvents())
+		err := svc.ProcessNewEvents()
+
+		// Then
+		require.NoError(t, err)
+		_, err = memoryStorage.CISHighWaterMarks().Get(fixSource)
+		assert.Error(t, err)
+		mark, err := memoryStorage.CISHighWaterMarks().Get(fixSource + dryRunMarkSuffix)
+		require.NoError(t, err)
+		assert.Equal(t, "1", mark.LastEventID)
+		assert.True(t, client.since.Equal(time.Unix(1, 0)))
+	})
+
+	t.Run("should stop at the event whose run was aborted without counting the attempt", func(t *testing.T) {
+		// Given
+		memoryStorage := storage.NewMemoryStorage()
+		require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: "inst-1", SubAccountID: "sa-1", GlobalAccountID: "ga-1"}))
+		require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: "inst-2", SubAccountID: "sa-2", GlobalAccountID: "ga-1"}))
+
+		brokerClient := &mocks.BrokerClient{}
+		defer brokerClient.AssertExpectations(t)
+
+		client := &fakeEventClient{events: []DeletionEvent{
+			fixDeletionEvent("1", GlobalAccountDeletion, "ga-1", 1000),
+			fixDeletionEvent("2", SubAccountDeletion, "sa-3", 2000),
+		}}
+		cfg := fixEventCleanupConfig()
+		cfg.MaxDeletionsPerRun = 1
+		cfg.MaxEventAttempts = 1
+		svc := fixEventCleanupService(client, brokerClient, memoryStorage, cfg)
+
+		// When
+		require.Error(t, svc.ProcessNewEvents())
+		err := svc.ProcessNewEvents()
+
+		// Then
+		assert.Error(t, err)
+		_, err = memoryStorage.CISHighWaterMarks().Get(fixSource)
+		assert.Error(t, err)
+	})
+
+	t.Run("should stop at the failed event and count the attempt", func(t *testing.T) {
+		// Given
+		memoryStorage := storage.NewMemoryStorage()
+		require.NoError(t, memoryStorage.Instances().Insert(internal.Instance{InstanceID: "inst-ga", SubAccountID: "sa-2", GlobalAccountID: "ga-1"}))
+
+		brokerClient := &mocks.BrokerClient{}
+		defer brokerClient.AssertExpectations(t)
+
+		client := &fakeEventClient{events: []DeletionEvent{
+			fixDeletionEvent("1", SubAccountDeletion, "sa-1", 1000),
+			fixDeletionEvent("2", GlobalAccountDeletion, "ga-1", 2000),
+		}}
+		svc := fixLookupFailureEventCleanupService(client, brokerClient, memoryStorage, fixEventCleanupConfig())
+
+		// When
+		err := svc.ProcessNewEvents()
+
+		// Then
+		require.NoError(t, err)
+		mark, err := memoryStorage.CISHighWaterMarks().Get(fixSource)
+		require.NoError(t, err)
+		assert.Empty(t, mark.LastEventID)
+		assert.Equal(t, 1, mark.FailedAttempts)
+	})
+
+	t.Run("should skip the event which failed too many times", func(t *testing.T) {
+		// Given
+		memoryStorage := storage.NewMemoryStorage()
+		gaInstance := internal.Instance{InstanceID: "inst-ga", SubAccountID: "sa-2", GlobalAccountID: "ga-1"}
+		require.NoError(t, memoryStorage.Instances().Insert(gaInstance))
+
+		brokerClient := &mocks.BrokerClient{}
+		brokerClient.On("Deprovision", gaInstance).Return("op-ga", nil).Once()
+		defer brokerClient.AssertExpectations(t)
+
+		client := &fakeEventClient{events: []DeletionEvent{
+			fixDeletionEvent("1", SubAccountDeletion, "sa-1", 1000),
+			fixDeletionEvent("2", GlobalAccountDeletion, "ga-1", 2000),
+		}}
+		cfg := fixEventCleanupConfig()
+		cfg.MaxEventAttempts = 2
+		svc := fixLookupFailureEventCleanupService(client, brokerClient, memoryStorage, cfg)
+
+		// When
+		require.NoError(t, svc.ProcessNewEvents())
+		err := svc.ProcessNewEvents()
+
+		// Then
+		require.NoError(t, err)
+		mark, err := memoryStorage.CISHighWaterMarks().Get(fixSource)
+		require.NoError(t, err)
+		assert.Equal(t, "2", mark.LastEventID)
+		assert.Equal(t, 0, mark.FailedAttempts)
+	})
+
+	t.Run("should process a limited number of events in a single run", func(t *testing.T) {
+		// Given
+		memoryStorage := storage.NewMemoryStorage()
+		saInstance := internal.Instance{InstanceID: "inst-sa", SubAccountID: "sa-1", GlobalAccountID: "ga-0"}
+		require.NoError(t, memoryStorage.Instances().Insert(saInstance))
+
+		brokerClient := &mocks.BrokerClient{}
+		brokerClient.On("Deprovision", saInstance).Return("op-sa", nil).Once()
+		defer brokerClient.AssertExpectations(t)
+
+		client := &fakeEventClient{events: []DeletionEvent{
+			fixDeletionEvent("1", SubAccountDeletion, "sa-1", 1000),
+			fixDeletionEvent("2", SubAccountDeletion, "sa-2", 2000),
+		}}
+		cfg := fixEventCleanupConfig()
+		cfg.MaxEventsPerRun = 1
+		svc := fixEventCleanupService(client, brokerClient, memoryStorage, cfg)
+
+		// When
+		err := svc.ProcessNewEvents()
+
+		// Then
+		require.NoError(t, err)
+		mark, err := memoryStorage.CISHighWaterMarks().Get(fixSource)
+		require.NoError(t, err)
+		assert.Equal(t, "1", mark.LastEventID)
+	})
+
+	t.Run("should process every event with the same action time exactly once", func(t *testing.T) {
+		// Given
+		memoryStorage := storage.NewMemoryStorage()
+		brokerClient := &mocks.BrokerClient{}
+		defer brokerClient.AssertExpectations(t)
+
+		var events []DeletionEvent
+		for _, id := range []string{"9", "10", "11"} {
+			instance := internal.Instance{InstanceID: "inst-" + id, SubAccountID: "sa-" + id, GlobalAccountID: "ga-0"}
+			require.NoError(t, memoryStorage.Instances().Insert(instance))
+			brokerClient.On("Deprovision", instance).Return("op-"+id, nil).Once()
+			events = append(events, fixDeletionEvent(id, SubAccountDeletion, "sa-"+id, 1000))
+		}
+
+		client := &fakeEventClient{events: events}
+		cfg := fixEventCleanupConfig()
+		cfg.MaxEventsPerRun = 1
+		svc := fixEventCleanupService(client, brokerClient, memoryStorage, cfg)
+
+		for run, expectedID := range []string{"9", "10", "11", "11"} {
+			// When
+			err := svc.ProcessNewEvents()
+
+			// Then
+			require.NoError(t, err)
+			mark, err := memoryStorage.CISHighWaterMarks().Get(fixSource)
+			require.NoError(t, err)
+			assert.Equal(t, expectedID, mark.LastEventID, "run %d", run+1)
+		}
+	})
+}
+
+type subAccountLookupFailure struct {
+	storage.Instances
+}
+
+func (s *subAccountLookupFailure) FindAllInstancesForSubAccounts(_ []string) ([]internal.Instance, error) {
+	return nil, errors.New("connection refused")
+}
+
+type fakeEventClient struct {
+	events []DeletionEvent
+	since  time.Time
+}
+
+func (c *fakeEventClient) FetchDeletionEventsSince(since time.Time) ([]DeletionEvent, error) {
+	c.since = since
+	return c.events, nil
+}
+
+func fixEventCleanupService(client EventClient, brokerClient BrokerClient, db storage.BrokerStorage, cfg EventCleanupConfig) *EventCleanupService {
+	cleanup := NewSubAccountCleanupService(nil, brokerClient, db.Instances(), db.SubAccountCleanupRuns(), cfg.CleanupConfig(), logrus.New())
+	return NewEventCleanupService(client, cleanup, db.CISHighWaterMarks(), fixSource, cfg, logrus.New())
+}
+
+// fixLookupFailureEventCleanupService returns the service which fails to find instances of subaccounts
+func fixLookupFailureEventCleanupService(client EventClient, brokerClient BrokerClient, db storage.BrokerStorage, cfg EventCleanupConfig) *EventCleanupService {
+	cleanup := NewSubAccountCleanupService(nil, brokerClient, &subAccountLookupFailure{Instances: db.Instances()},
+		db.SubAccountCleanupRuns(), cfg.CleanupConfig(), logrus.New())
+	return NewEventCleanupService(client, cleanup, db.CISHighWaterMarks(), fixSource, cfg, logrus.New())
+}
+
+func fixEventCleanupConfig() EventCleanupConfig {
+	return EventCleanupConfig{
+		Interval:         time.Minute,
+		MaxEventsPerRun:  50,
+		MaxEventAttempts: 5,
+	}
+}
+
+func fixDeletionEvent(id string, evType DeletionEventType, entityID string, actionTimeMs int64) DeletionEvent {
+	return DeletionEvent{
+		ID:         id,
+		Type:       evType,
+		EntityID:   entityID,
+		ActionTime: time.Unix(0, actionTimeMs*int64(time.Millisecond)),
+	}
+}
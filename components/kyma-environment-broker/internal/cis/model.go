@@ -1,11 +1,32 @@
 package cis
 
+import (
+	"time"
+)
+
 type Event struct {
+	ID           int64  `json:"id"`
+	ActionTime   int64  `json:"actionTime"`
 	CreationTime int64  `json:"creationTime"`
 	SubAccount   string `json:"entityId"`
 	Type         string `json:"eventType"`
 }
 
+type DeletionEventType string
+
+const (
+	SubAccountDeletion    DeletionEventType = "SubAccount"
+	GlobalAccountDeletion DeletionEventType = "GlobalAccount"
+)
+
+// DeletionEvent is a CIS event about a deleted subaccount or global account
+type DeletionEvent struct {
+	ID         string
+	Type       DeletionEventType
+	EntityID   string
+	ActionTime time.Time
+}
+
 type CisResponse struct {
 	Total      int     `json:"total"`
 	TotalPages int     `json:"totalPages"`
@@ -12,6 +12,29 @@ type Bundle struct {
 	mock.Mock
 }
 
+// AddSecret provides a mock function with given fields: description
+func (_m *Bundle) AddSecret(description string) (*ias.ServiceProviderSecret, error) {
+	ret := _m.Called(description)
+
+	var r0 *ias.ServiceProviderSecret
+	if rf, ok := ret.Get(0).(func(string) *ias.ServiceProviderSecret); ok {
+		r0 = rf(description)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ias.ServiceProviderSecret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(description)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ConfigureServiceProvider provides a mock function with given fields:
 func (_m *Bundle) ConfigureServiceProvider() error {
 	ret := _m.Called()
@@ -105,6 +128,20 @@ func (_m *Bundle) GenerateSecret() (*ias.ServiceProviderSecret, error) {
 	return r0, r1
 }
 
+// RemoveSecretsExcept provides a mock function with given fields: description
+func (_m *Bundle) RemoveSecretsExcept(description string) error {
+	ret := _m.Called(description)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(description)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ServiceProviderExist provides a mock function with given fields:
 func (_m *Bundle) ServiceProviderExist() bool {
 	ret := _m.Called()
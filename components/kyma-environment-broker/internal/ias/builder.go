@@ -21,6 +21,8 @@ type (
 		ConfigureServiceProvider() error
 		ConfigureServiceProviderType(path string) error
 		GenerateSecret() (*ServiceProviderSecret, error)
+		AddSecret(description string) (*ServiceProviderSecret, error)
+		RemoveSecretsExcept(description string) error
 	}
 )
 
@@ -8,6 +8,8 @@ import (
 	"github.com/pkg/errors"
 )
 
+const secretDescription = "SAP Kyma Runtime Secret"
+
 type (
 	ProviderID string
 
@@ -250,16 +252,45 @@ func (b *ServiceProviderBundle) ConfigureServiceProvider() error {
 
 // GenerateSecret generates new ID and Secret for ServiceProvider, removes already existing secrets
 func (b *ServiceProviderBundle) GenerateSecret() (*ServiceProviderSecret, error) {
-	err := b.removeSecrets()
+	err := b.removeSecrets(func(SPSecret) bool { return true })
 	if err != nil {
 		return &ServiceProviderSecret{}, errors.Wrap(err, "while removing existing secrets")
 	}
 
+	return b.generateSecret(secretDescription)
+}
+
+// AddSecret generates new ID and Secret for ServiceProvider and keeps the existing secrets, so the clients using them
+// keep working until they get the new secret. The secrets with the same description are replaced.
+func (b *ServiceProviderBundle) AddSecret(description string) (*ServiceProviderSecret, error) {
+	err := b.removeSecrets(func(s SPSecret) bool { return s.Description == description })
+	if err != nil {
+		return &ServiceProviderSecret{}, errors.Wrap(err, "while removing secrets with the same description")
+	}
+
+	return b.generateSecret(description)
+}
+
+// RemoveSecretsExcept removes all secrets of ServiceProvider besides the ones with the given description
+func (b *ServiceProviderBundle) RemoveSecretsExcept(description string) error {
+	err := b.removeSecrets(func(s SPSecret) bool { return s.Description != description })
+	if err != nil {
+		return errors.Wrap(err, "while removing secrets")
+	}
+	return nil
+}
+
+// RotatedSecretDescription returns the description of the secret added by the given operation
+func RotatedSecretDescription(operationID string) string {
+	return fmt.Sprintf("%s (operation: %s)", secretDescription, operationID)
+}
+
+func (b *ServiceProviderBundle) generateSecret(description string) (*ServiceProviderSecret, error) {
 	secretCfg := SecretConfiguration{
 		Organization: b.organization,
 		ID:           b.serviceProvider.ID,
 		RestAPIClientSecret: RestAPIClientSecret{
-			Description: "SAP Kyma Runtime Secret",
+			Description: description,
 			Scopes:      []string{"ManageApp", "ManageUsers", "OAuth"},
 		},
 	}
@@ -272,14 +303,15 @@ func (b *ServiceProviderBundle) GenerateSecret() (*ServiceProviderSecret, error)
 	return sps, nil
 }
 
-func (b *ServiceProviderBundle) removeSecrets() error {
-	if len(b.serviceProvider.Secret) == 0 {
-		return nil
-	}
-
+func (b *ServiceProviderBundle) removeSecrets(selected func(SPSecret) bool) error {
 	var secretsIDs []string
 	for _, s := range b.serviceProvider.Secret {
-		secretsIDs = append(secretsIDs, s.SecretID)
+		if selected(s) {
+			secretsIDs = append(secretsIDs, s.SecretID)
+		}
+	}
+	if len(secretsIDs) == 0 {
+		return nil
 	}
 
 	deleteSecrets := SecretsRef{
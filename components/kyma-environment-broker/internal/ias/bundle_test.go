@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestServiceProviderBundle_ServiceProviderType(t *testing.T) {
@@ -140,6 +141,42 @@ func TestServiceProviderBundle_GenerateSecret(t *testing.T) {
 	assert.Len(t, provider.Secret, 1)
 }
 
+func TestServiceProviderBundle_AddSecret(t *testing.T) {
+	// given
+	client := NewFakeClient()
+	bundle := NewServiceProviderBundle(FakeGrafanaName, ServiceProviderInputs[SPGrafanaID], client, Config{IdentityProvider: FakeIdentityProviderName})
+	require.NoError(t, bundle.FetchServiceProviderData())
+	_, err := bundle.GenerateSecret()
+	require.NoError(t, err)
+	description := RotatedSecretDescription("operation-id")
+
+	// when
+	require.NoError(t, bundle.FetchServiceProviderData())
+	_, err = bundle.AddSecret(description)
+	require.NoError(t, err)
+	require.NoError(t, bundle.FetchServiceProviderData())
+	_, err = bundle.AddSecret(description)
+	require.NoError(t, err)
+
+	// then
+	provider, err := client.GetServiceProvider(FakeGrafanaID)
+	require.NoError(t, err)
+	require.Len(t, provider.Secret, 2)
+	assert.Equal(t, "SAP Kyma Runtime Secret", provider.Secret[0].Description)
+	assert.Equal(t, description, provider.Secret[1].Description)
+
+	// when
+	require.NoError(t, bundle.FetchServiceProviderData())
+	err = bundle.RemoveSecretsExcept(description)
+
+	// then
+	require.NoError(t, err)
+	provider, err = client.GetServiceProvider(FakeGrafanaID)
+	require.NoError(t, err)
+	require.Len(t, provider.Secret, 1)
+	assert.Equal(t, description, provider.Secret[0].Description)
+}
+
 func TestServiceProviderBundle_DeleteServiceProvider(t *testing.T) {
 	// given
 	client := NewFakeClient()
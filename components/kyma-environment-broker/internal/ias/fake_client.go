@@ -90,8 +90,12 @@ func (f FakeClient) GenerateServiceProviderSecret(ss SecretConfiguration) (*Serv
 		return &ServiceProviderSecret{}, err
 	}
 
+	secretID := FakeClientID
+	if len(serviceProvider.Secret) > 0 {
+		secretID = fmt.Sprintf("%s-%d", FakeClientID, len(serviceProvider.Secret))
+	}
 	serviceProvider.Secret = append(serviceProvider.Secret, SPSecret{
-		SecretID:    FakeClientID,
+		SecretID:    secretID,
 		Description: ss.RestAPIClientSecret.Description,
 		Scopes:      ss.RestAPIClientSecret.Scopes,
 	})
@@ -0,0 +1,204 @@
+package rotation
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type Config struct {
+	Disabled bool          `envconfig:"default=true"`
+	Interval time.Duration `envconfig:"default=1h"`
+	// SecretMaxAge is the age after which the client secrets of the IAS ServiceProviders are rotated
+	SecretMaxAge time.Duration `envconfig:"default=2160h"`
+	BatchSize    int           `envconfig:"default=100"`
+	// MaxRotationsPerRun limits the number of upgrades triggered in one run
+	MaxRotationsPerRun int `envconfig:"default=50"`
+	// RetryBackoff is the delay before a failed rotation is retried, it doubles with every failure in a row
+	RetryBackoff time.Duration `envconfig:"default=1h"`
+	// MaxFailedAttempts is the number of failures in a row after which the rotation is not retried anymore
+	MaxFailedAttempts int `envconfig:"default=5"`
+}
+
+// NewJob creates the job which periodically rotates the client secrets of the IAS ServiceProviders and keeps
+// the ServiceProviders in sync with the dashboard URL of the instance.
+// The new secrets are pushed into the Runtime by the upgrade Kyma operation with the currently installed Kyma version.
+func NewJob(instances storage.Instances, operations storage.Operations, rotations storage.IASRotations,
+	builder ias.BundleBuilder, queue process.UpgradeKymaQueue, cfg Config, log logrus.FieldLogger) *process.SameVersionUpgradeJob {
+	rotation := &secretRotation{
+		operations:    operations,
+		rotations:     rotations,
+		bundleBuilder: builder,
+		cfg:           cfg,
+	}
+	return process.NewSameVersionUpgradeJob(instances, operations, queue, rotation, process.SameVersionUpgradeJobConfig{
+		Interval:          cfg.Interval,
+		BatchSize:         cfg.BatchSize,
+		MaxUpgradesPerRun: cfg.MaxRotationsPerRun,
+	}, log)
+}
+
+type secretRotation struct {
+	operations    storage.Operations
+	rotations     storage.IASRotations
+	bundleBuilder ias.BundleBuilder
+	cfg           Config
+
+	byInstance map[string]internal.IASRotation
+	// retried holds the failed rotations which are retried in the current run by the instance ID
+	retried map[string]*internal.UpgradeKymaOperation
+}
+
+func (r *secretRotation) Name() string {
+	return "IAS secret rotation"
+}
+
+func (r *secretRotation) Prepare() error {
+	rotations, err := r.rotations.List()
+	if err != nil {
+		return errors.Wrap(err, "while listing IAS rotations")
+	}
+	r.byInstance = make(map[string]internal.IASRotation, len(rotations))
+	for _, rotation := range rotations {
+		r.byInstance[rotation.InstanceID] = rotation
+	}
+	r.retried = make(map[string]*internal.UpgradeKymaOperation)
+	return nil
+}
+
+// MarkOperation marks the operation to rotate the secrets, the operation which retries a failed rotation reuses
+// the secrets added by the failed one, so no new secret is added to the ServiceProviders on every retry
+func (r *secretRotation) MarkOperation(operation *internal.UpgradeKymaOperation) {
+	operation.RotateIASSecret = true
+
+	failed, found := r.retried[operation.InstanceID]
+	if !found || len(failed.IASSecrets) == 0 {
+		return
+	}
+	operation.IASSecrets = failed.IASSecrets
+	operation.IASSecretsOperationID = failed.IASSecretsAddedBy()
+}
+
+func (r *secretRotation) UpgradeDue(instance internal.Instance, pOpr *internal.ProvisioningOperation, log logrus.FieldLogger) (bool, error) {
+	if instance.DashboardURL == "" {
+		// the Runtime is not provisioned yet
+		return false, nil
+	}
+
+	rotation, found := r.byInstance[instance.InstanceID]
+	if !found {
+		// the ServiceProviders were registered and configured during provisioning
+		rotation = internal.IASRotation{
+			InstanceID:      instance.InstanceID,
+			SecretRotatedAt: pOpr.UpdatedAt,
+			DashboardURL:    instance.DashboardURL,
+		}
+		if err := r.rotations.Save(rotation); err != nil {
+			return false, errors.Wrap(err, "while saving IAS rotation")
+		}
+	}
+
+	if rotation.DashboardURL != instance.DashboardURL {
+		log.Infof("dashboard URL changed from %s to %s, updating IAS ServiceProviders", rotation.DashboardURL, instance.DashboardURL)
+		if err := r.updateServiceProviders(instance); err != nil {
+			return false, errors.Wrap(err, "while updating IAS ServiceProviders")
+		}
+		rotation.DashboardURL = instance.DashboardURL
+		if err := r.rotations.Save(rotation); err != nil {
+			return false, errors.Wrap(err, "while saving IAS rotation")
+		}
+	}
+
+	last, err := process.LastSameVersionUpgrade(r.operations, instance.InstanceID, isRotation)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case last == nil:
+	case last.State == orchestration.Failed:
+		// the Runtime still uses the old secrets, the secrets generated by the failed operation are reused
+		// by the next rotation
+		return r.retryDue(instance.InstanceID, last, log)
+	case last.State == orchestration.Succeeded && len(last.IASSecrets) > 0 && last.UpdatedAt.After(rotation.SecretRotatedAt):
+		// the Runtime uses the secrets generated by the operation, the old ones can be removed
+		if err := r.removeOldSecrets(instance.InstanceID, ias.RotatedSecretDescription(last.IASSecretsAddedBy())); err != nil {
+			return false, errors.Wrap(err, "while removing old IAS ServiceProvider secrets")
+		}
+		rotation.SecretRotatedAt = last.UpdatedAt
+		if err := r.rotations.Save(rotation); err != nil {
+			return false, errors.Wrap(err, "while saving IAS rotation")
+		}
+		log.Infof("IAS secrets rotated by operation %s", last.Operation.ID)
+	}
+
+	return time.Since(rotation.SecretRotatedAt) >= r.cfg.SecretMaxAge, nil
+}
+
+// retryDue returns true when the backoff after the failed rotation elapsed, the rotation is not retried anymore
+// after MaxFailedAttempts failures in a row
+func (r *secretRotation) retryDue(instanceID string, failed *internal.UpgradeKymaOperation, log logrus.FieldLogger) (bool, error) {
+	failures, err := process.FailedSameVersionUpgrades(r.operations, instanceID, isRotation)
+	if err != nil {
+		return false, err
+	}
+	if failures >= r.cfg.MaxFailedAttempts {
+		log.Errorf("IAS secret rotation failed %d times in a row, the last operation %s, it is not retried anymore", failures, failed.Operation.ID)
+		return false, nil
+	}
+	if time.Since(failed.UpdatedAt) < process.SameVersionUpgradeRetryDelay(r.cfg.RetryBackoff, failures) {
+		return false, nil
+	}
+
+	log.Warnf("IAS secret rotation %s failed, retrying (failures in a row: %d)", failed.Operation.ID, failures)
+	r.retried[instanceID] = failed
+	return true, nil
+}
+
+func isRotation(operation internal.UpgradeKymaOperation) bool {
+	return operation.RotateIASSecret
+}
+
+func (r *secretRotation) removeOldSecrets(instanceID, description string) error {
+	for spID := range ias.ServiceProviderInputs {
+		spb, err := r.bundleBuilder.NewBundle(instanceID, spID)
+		if err != nil {
+			return errors.Wrap(err, "while creating ServiceProvider Bundle")
+		}
+		if spb.ServiceProviderType() != ias.OIDC {
+			continue
+		}
+		if err := spb.FetchServiceProviderData(); err != nil {
+			return errors.Wrapf(err, "while fetching ServiceProvider %q data", spb.ServiceProviderName())
+		}
+		if err := spb.RemoveSecretsExcept(description); err != nil {
+			return errors.Wrapf(err, "while removing secrets of ServiceProvider %q", spb.ServiceProviderName())
+		}
+	}
+	return nil
+}
+
+func (r *secretRotation) updateServiceProviders(instance internal.Instance) error {
+	for spID := range ias.ServiceProviderInputs {
+		spb, err := r.bundleBuilder.NewBundle(instance.InstanceID, spID)
+		if err != nil {
+			return errors.Wrap(err, "while creating ServiceProvider Bundle")
+		}
+		if err := spb.FetchServiceProviderData(); err != nil {
+			return errors.Wrapf(err, "while fetching ServiceProvider %q data", spb.ServiceProviderName())
+		}
+		if !spb.ServiceProviderExist() {
+			return errors.Errorf("ServiceProvider %q does not exist", spb.ServiceProviderName())
+		}
+		if err := spb.ConfigureServiceProviderType(instance.DashboardURL); err != nil {
+			return errors.Wrapf(err, "while configuring ServiceProvider %q", spb.ServiceProviderName())
+		}
+	}
+	return nil
+}
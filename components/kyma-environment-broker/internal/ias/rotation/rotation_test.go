@@ -0,0 +1,272 @@
+package rotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	instanceID   = "instance-id"
+	dashboardURL = "https://console.abcd.kyma.example.com"
+	secretMaxAge = 24 * time.Hour
+)
+
+func TestJob_RotateAll(t *testing.T) {
+	t.Run("should trigger rotation of expired secrets with the installed Kyma version", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now().Add(-2*secretMaxAge))
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation("upgrade-1", domain.Succeeded, "1.17.0", false)))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, &automock.BundleBuilder{}, queue).UpgradeAll()
+
+		// then
+		require.Len(t, queue.ids, 1)
+		operation, err := db.Operations().GetUpgradeKymaOperationByID(queue.ids[0])
+		require.NoError(t, err)
+		assert.True(t, operation.RotateIASSecret)
+		assert.Equal(t, orchestration.InProgress, string(operation.State))
+		assert.Equal(t, "1.17.0", operation.RuntimeVersion.Version)
+
+		rotation, err := db.IASRotations().Get(instanceID)
+		require.NoError(t, err)
+		assert.Equal(t, dashboardURL, rotation.DashboardURL)
+	})
+
+	t.Run("should not rotate fresh secrets", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now())
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, &automock.BundleBuilder{}, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+	})
+
+	t.Run("should trigger rotation again when the last one failed", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now())
+		failed := fixUpgradeKymaOperation("rotation-1", orchestration.Failed, "1.16.0", true)
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(failed))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, &automock.BundleBuilder{}, queue).UpgradeAll()
+
+		// then
+		assert.Len(t, queue.ids, 1)
+	})
+
+	t.Run("should reuse secrets of the failed rotation", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now())
+		failed := fixUpgradeKymaOperation("rotation-2", orchestration.Failed, "1.16.0", true)
+		failed.IASSecrets = map[string]internal.IASClientSecret{
+			"MockServiceProvider": {ClientID: "client-id", ClientSecret: "client-secret"},
+		}
+		failed.IASSecretsOperationID = "rotation-1"
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(failed))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, &automock.BundleBuilder{}, queue).UpgradeAll()
+
+		// then
+		require.Len(t, queue.ids, 1)
+		operation, err := db.Operations().GetUpgradeKymaOperationByID(queue.ids[0])
+		require.NoError(t, err)
+		assert.True(t, operation.RotateIASSecret)
+		assert.Equal(t, failed.IASSecrets, operation.IASSecrets)
+		assert.Equal(t, "rotation-1", operation.IASSecretsAddedBy())
+	})
+
+	t.Run("should back off rotation which failed several times in a row", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now())
+		for _, id := range []string{"rotation-1", "rotation-2"} {
+			require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation(id, orchestration.Failed, "1.16.0", true)))
+		}
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, &automock.BundleBuilder{}, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+	})
+
+	t.Run("should not retry rotation after max failed attempts", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now().Add(-2*secretMaxAge))
+		for i, id := range []string{"rotation-1", "rotation-2", "rotation-3"} {
+			failed := fixUpgradeKymaOperation(id, orchestration.Failed, "1.16.0", true)
+			failed.CreatedAt = time.Now().Add(-time.Duration(10-i) * 24 * time.Hour)
+			failed.UpdatedAt = failed.CreatedAt
+			require.NoError(t, db.Operations().InsertUpgradeKymaOperation(failed))
+		}
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, &automock.BundleBuilder{}, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+	})
+
+	t.Run("should remove old secrets when the rotation succeeded", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now().Add(-2*secretMaxAge))
+		succeeded := fixUpgradeKymaOperation("rotation-1", orchestration.Succeeded, "1.16.0", true)
+		succeeded.IASSecrets = map[string]internal.IASClientSecret{
+			"MockServiceProvider": {ClientID: "client-id", ClientSecret: "client-secret"},
+		}
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(succeeded))
+
+		bundleBuilder := &automock.BundleBuilder{}
+		defer bundleBuilder.AssertExpectations(t)
+		for inputID := range ias.ServiceProviderInputs {
+			bundle := &automock.Bundle{}
+			defer bundle.AssertExpectations(t)
+			bundle.On("ServiceProviderName").Return("MockServiceProvider")
+			bundle.On("ServiceProviderType").Return(ias.OIDC)
+			bundle.On("FetchServiceProviderData").Return(nil).Once()
+			bundle.On("RemoveSecretsExcept", ias.RotatedSecretDescription("rotation-1")).Return(nil).Once()
+			bundleBuilder.On("NewBundle", instanceID, inputID).Return(bundle, nil).Once()
+		}
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, bundleBuilder, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+		rotation, err := db.IASRotations().Get(instanceID)
+		require.NoError(t, err)
+		assert.Equal(t, succeeded.UpdatedAt.Unix(), rotation.SecretRotatedAt.Unix())
+	})
+
+	t.Run("should postpone rotation when the Runtime is being upgraded", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now().Add(-2*secretMaxAge))
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation("upgrade-1", orchestration.InProgress, "1.17.0", false)))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, &automock.BundleBuilder{}, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+	})
+
+	t.Run("should update ServiceProviders when the dashboard URL changed", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, dashboardURL, time.Now())
+		require.NoError(t, db.IASRotations().Save(internal.IASRotation{
+			InstanceID:      instanceID,
+			SecretRotatedAt: time.Now(),
+			DashboardURL:    "https://console.old.kyma.example.com",
+		}))
+
+		bundleBuilder := &automock.BundleBuilder{}
+		defer bundleBuilder.AssertExpectations(t)
+		for inputID := range ias.ServiceProviderInputs {
+			bundle := &automock.Bundle{}
+			defer bundle.AssertExpectations(t)
+			bundle.On("ServiceProviderName").Return("MockServiceProvider").Maybe()
+			bundle.On("FetchServiceProviderData").Return(nil).Once()
+			bundle.On("ServiceProviderExist").Return(true).Once()
+			bundle.On("ConfigureServiceProviderType", dashboardURL).Return(nil).Once()
+			bundleBuilder.On("NewBundle", instanceID, inputID).Return(bundle, nil).Once()
+		}
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, bundleBuilder, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+		rotation, err := db.IASRotations().Get(instanceID)
+		require.NoError(t, err)
+		assert.Equal(t, dashboardURL, rotation.DashboardURL)
+	})
+}
+
+type queueStub struct {
+	ids []string
+}
+
+func (q *queueStub) Add(operationID string) {
+	q.ids = append(q.ids, operationID)
+}
+
+func fixJob(db storage.BrokerStorage, builder ias.BundleBuilder, queue *queueStub) *process.SameVersionUpgradeJob {
+	return NewJob(db.Instances(), db.Operations(), db.IASRotations(), builder, queue, Config{
+		SecretMaxAge:      secretMaxAge,
+		BatchSize:         10,
+		RetryBackoff:      45 * time.Minute,
+		MaxFailedAttempts: 3,
+	}, logger.NewLogDummy())
+}
+
+func fixProvisionedInstance(t *testing.T, db storage.BrokerStorage, dashboardURL string, provisionedAt time.Time) {
+	require.NoError(t, db.Instances().Insert(internal.Instance{
+		InstanceID:   instanceID,
+		RuntimeID:    "runtime-id",
+		DashboardURL: dashboardURL,
+		CreatedAt:    provisionedAt,
+	}))
+
+	provisioning := internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:         "provisioning-id",
+			InstanceID: instanceID,
+			State:      domain.Succeeded,
+			CreatedAt:  provisionedAt,
+			UpdatedAt:  provisionedAt,
+		},
+		RuntimeVersion: internal.RuntimeVersionData{Version: "1.16.0", Origin: internal.Defaults},
+	}
+	require.NoError(t, provisioning.SetProvisioningParameters(internal.ProvisioningParameters{PlanID: "plan-id"}))
+	require.NoError(t, db.Operations().InsertProvisioningOperation(provisioning))
+}
+
+func fixUpgradeKymaOperation(id string, state domain.LastOperationState, version string, rotation bool) internal.UpgradeKymaOperation {
+	return internal.UpgradeKymaOperation{
+		Operation: internal.Operation{
+			ID:         id,
+			InstanceID: instanceID,
+			State:      state,
+			CreatedAt:  time.Now().Add(-time.Hour),
+			UpdatedAt:  time.Now().Add(-time.Hour),
+		},
+		RuntimeOperation: orchestration.RuntimeOperation{
+			Runtime: orchestration.Runtime{InstanceID: instanceID},
+		},
+		RuntimeVersion:  internal.RuntimeVersionData{Version: version, Origin: internal.Defaults},
+		RotateIASSecret: rotation,
+	}
+}
@@ -0,0 +1,60 @@
+package lms
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+
+	"github.com/pkg/errors"
+)
+
+// CertificateExpiry returns the expiration time of the PEM encoded certificate
+func CertificateExpiry(cert string) (time.Time, error) {
+	block, _ := pem.Decode([]byte(cert))
+	if block == nil {
+		return time.Time{}, errors.New("certificate is not PEM encoded")
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "while parsing certificate")
+	}
+	return parsed.NotAfter, nil
+}
+
+// CertificateOverrides returns the logging component overrides which configure fluent-bit
+// to forward logs to the LMS tenant with the given certificates
+func CertificateOverrides(tenantDNS, caCert, signedCert string, privateKey []byte) []*gqlschema.ConfigEntryInput {
+	return []*gqlschema.ConfigEntryInput{
+		{Key: "fluent-bit.conf.Output.forward.enabled", Value: "true"},
+		{Key: "fluent-bit.conf.Output.forward.Match", Value: "kube.*"},
+
+		{Key: "fluent-bit.backend.forward.host", Value: fmt.Sprintf("forward.%s", tenantDNS)},
+		{Key: "fluent-bit.backend.forward.port", Value: "8443"},
+		{Key: "fluent-bit.backend.forward.tls.enabled", Value: "true"},
+		{Key: "fluent-bit.backend.forward.tls.verify", Value: "On"},
+
+		// certs and private key must be encoded by base64
+		{Key: "fluent-bit.backend.forward.tls.ca", Value: base64.StdEncoding.EncodeToString([]byte(caCert))},
+		{Key: "fluent-bit.backend.forward.tls.cert", Value: base64.StdEncoding.EncodeToString([]byte(signedCert))},
+		{Key: "fluent-bit.backend.forward.tls.key", Value: base64.StdEncoding.EncodeToString(privateKey)},
+	}
+}
+
+// RecordModifierOverrides returns the logging component overrides which add the subaccount ID to every log entry
+// and exclude logs containing sensitive data
+func RecordModifierOverrides(subAccountID string) []*gqlschema.ConfigEntryInput {
+	return []*gqlschema.ConfigEntryInput{
+		{Key: "fluent-bit.conf.Filter.record_modifier.enabled", Value: "true"},
+		{Key: "fluent-bit.conf.Filter.record_modifier.Match", Value: "kube.*"},
+		{Key: "fluent-bit.conf.Filter.record_modifier.Key", Value: "subaccount_id"},
+		{Key: "fluent-bit.conf.Filter.record_modifier.Value", Value: subAccountID}, // cluster_name is a tag added to log entry, allows to filter logs by a cluster
+		//kubernetes filter should not parse the document to avoid indexing on LMS side
+		{Key: "fluent-bit.conf.Filter.Kubernetes.Merge_Log", Value: "Off"},
+		//input should not contain dex logs as it contains sensitive data
+		{Key: "fluent-bit.conf.Input.Kubernetes.Exclude_Path", Value: "/var/log/containers/*_dex-*.log,/var/log/containers/*_kcproxy-*.log"},
+	}
+}
@@ -0,0 +1,53 @@
+package lms
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateExpiry(t *testing.T) {
+	t.Run("should return expiration time of the certificate", func(t *testing.T) {
+		// given
+		notAfter := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+		cert := fixCertificate(t, notAfter)
+
+		// when
+		expiresAt, err := CertificateExpiry(cert)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, notAfter.Equal(expiresAt))
+	})
+
+	t.Run("should return error when certificate is not PEM encoded", func(t *testing.T) {
+		// when
+		_, err := CertificateExpiry(FakeSignedCertificate)
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func fixCertificate(t *testing.T, notAfter time.Time) string {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fluentbit"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -0,0 +1,178 @@
+package renewal
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type Config struct {
+	Disabled bool          `envconfig:"default=true"`
+	Interval time.Duration `envconfig:"default=1h"`
+	// LeadTime is the time before the certificate expiry when the certificate is renewed
+	LeadTime  time.Duration `envconfig:"default=720h"`
+	BatchSize int           `envconfig:"default=100"`
+	// CertificateValidity is assumed for the certificates which expiry is unknown
+	CertificateValidity time.Duration `envconfig:"default=2160h"`
+	// MaxRenewalsPerRun limits the number of upgrades triggered in one run
+	MaxRenewalsPerRun int `envconfig:"default=50"`
+	// RetryBackoff is the delay before a failed renewal is retried, it doubles with every failure in a row
+	RetryBackoff time.Duration `envconfig:"default=1h"`
+	// MaxFailedAttempts is the number of failures in a row after which the renewal is not retried anymore
+	MaxFailedAttempts int `envconfig:"default=5"`
+}
+
+// NewJob creates the job which periodically checks the expiry of the LMS client certificates and renews
+// the certificates which expire within the configured lead time. The new certificate is requested and pushed
+// into the Runtime by the upgrade Kyma operation with the currently installed Kyma version.
+func NewJob(instances storage.Instances, operations storage.Operations, certificates storage.LMSCertificates,
+	queue process.UpgradeKymaQueue, cfg Config, log logrus.FieldLogger) *process.SameVersionUpgradeJob {
+	renewal := &certificateRenewal{
+		operations:   operations,
+		certificates: certificates,
+		cfg:          cfg,
+	}
+	return process.NewSameVersionUpgradeJob(instances, operations, queue, renewal, process.SameVersionUpgradeJobConfig{
+		Interval:          cfg.Interval,
+		BatchSize:         cfg.BatchSize,
+		MaxUpgradesPerRun: cfg.MaxRenewalsPerRun,
+	}, log)
+}
+
+type certificateRenewal struct {
+	operations   storage.Operations
+	certificates storage.LMSCertificates
+	cfg          Config
+
+	byInstance map[string]internal.LMSCertificate
+	// retried holds the failed renewals which are retried in the current run by the instance ID
+	retried map[string]*internal.UpgradeKymaOperation
+}
+
+func (r *certificateRenewal) Name() string {
+	return "LMS certificate renewal"
+}
+
+func (r *certificateRenewal) Prepare() error {
+	certificates, err := r.certificates.List()
+	if err != nil {
+		return errors.Wrap(err, "while listing LMS certificates")
+	}
+	r.byInstance = make(map[string]internal.LMSCertificate, len(certificates))
+	for _, c := range certificates {
+		r.byInstance[c.InstanceID] = c
+	}
+	r.retried = make(map[string]*internal.UpgradeKymaOperation)
+	return nil
+}
+
+// MarkOperation marks the operation to renew the certificate, the operation which retries a failed renewal reuses
+// the certificate issued by the failed one if the certificate does not expire within the lead time
+func (r *certificateRenewal) MarkOperation(operation *internal.UpgradeKymaOperation) {
+	operation.RenewLMSCertificate = true
+
+	failed, found := r.retried[operation.InstanceID]
+	if !found || !r.reusable(failed.LMSCertificateRenewal) {
+		return
+	}
+	operation.LMSCertificateRenewal = failed.LMSCertificateRenewal
+}
+
+// reusable returns true for the certificate which was signed and is valid long enough, the expiry is known only
+// for the certificates which the failed operation fetched from the LMS
+func (r *certificateRenewal) reusable(renewal internal.LMSCertificateRenewal) bool {
+	return renewal.CertificateURL != "" && !renewal.ExpiresAt.IsZero() && time.Until(renewal.ExpiresAt) > r.cfg.LeadTime
+}
+
+func (r *certificateRenewal) UpgradeDue(instance internal.Instance, pOpr *internal.ProvisioningOperation, log logrus.FieldLogger) (bool, error) {
+	if pOpr.Lms.TenantID == "" || pOpr.Lms.Failed {
+		// the Runtime does not ship logs to LMS
+		return false, nil
+	}
+
+	certificate, found := r.byInstance[instance.InstanceID]
+	changed := !found
+	if !found {
+		// the certificate was issued during provisioning, before the certificates were tracked
+		certificate = internal.LMSCertificate{
+			InstanceID: instance.InstanceID,
+			TenantID:   pOpr.Lms.TenantID,
+			IssuedAt:   pOpr.CreatedAt,
+		}
+	}
+	if certificate.IssuedAt.IsZero() {
+		certificate.IssuedAt = pOpr.CreatedAt
+		changed = true
+	}
+
+	last, err := process.LastSameVersionUpgrade(r.operations, instance.InstanceID, isRenewal)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case last == nil:
+	case last.State == orchestration.Failed:
+		return r.retryDue(instance.InstanceID, last, log)
+	case last.State == orchestration.Succeeded && last.LMSCertificateRenewal.RequestedAt.After(certificate.IssuedAt):
+		log.Infof("LMS certificate renewed by operation %s", last.Operation.ID)
+		certificate.IssuedAt = last.LMSCertificateRenewal.RequestedAt
+		certificate.ExpiresAt = last.LMSCertificateRenewal.ExpiresAt
+		changed = true
+	}
+
+	if certificate.ExpiresAt.IsZero() {
+		// the expiry of the certificate is unknown, e.g. the LMS returned the certificate which could not be parsed
+		certificate.ExpiresAt = certificate.IssuedAt.Add(r.cfg.CertificateValidity)
+		log.Infof("LMS certificate expiry is unknown, assuming %s", certificate.ExpiresAt)
+		changed = true
+	}
+	if changed {
+		if err := r.certificates.Save(certificate); err != nil {
+			return false, errors.Wrap(err, "while saving LMS certificate")
+		}
+	}
+
+	return r.expiryDue(certificate, log), nil
+}
+
+// retryDue returns true when the backoff after the failed renewal elapsed, the renewal is not retried anymore
+// after MaxFailedAttempts failures in a row
+func (r *certificateRenewal) retryDue(instanceID string, failed *internal.UpgradeKymaOperation, log logrus.FieldLogger) (bool, error) {
+	failures, err := process.FailedSameVersionUpgrades(r.operations, instanceID, isRenewal)
+	if err != nil {
+		return false, err
+	}
+	if failures >= r.cfg.MaxFailedAttempts {
+		log.Errorf("LMS certificate renewal failed %d times in a row, the last operation %s, it is not retried anymore", failures, failed.Operation.ID)
+		return false, nil
+	}
+	if time.Since(failed.UpdatedAt) < process.SameVersionUpgradeRetryDelay(r.cfg.RetryBackoff, failures) {
+		return false, nil
+	}
+
+	log.Warnf("LMS certificate renewal %s failed, retrying (failures in a row: %d)", failed.Operation.ID, failures)
+	r.retried[instanceID] = failed
+	return true, nil
+}
+
+func isRenewal(operation internal.UpgradeKymaOperation) bool {
+	return operation.RenewLMSCertificate
+}
+
+func (r *certificateRenewal) expiryDue(certificate internal.LMSCertificate, log logrus.FieldLogger) bool {
+	switch {
+	case time.Now().After(certificate.ExpiresAt):
+		log.Warnf("LMS certificate expired at %s", certificate.ExpiresAt)
+		return true
+	case time.Until(certificate.ExpiresAt) <= r.cfg.LeadTime:
+		log.Warnf("LMS certificate expires at %s", certificate.ExpiresAt)
+		return true
+	}
+	return false
+}
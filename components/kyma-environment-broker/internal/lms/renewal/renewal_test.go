@@ -0,0 +1,359 @@
+package renewal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	instanceID = "instance-id"
+	tenantID   = "lms-tenant-id"
+	leadTime   = 7 * 24 * time.Hour
+	validity   = 90 * 24 * time.Hour
+)
+
+func TestJob_RenewAll(t *testing.T) {
+	t.Run("should trigger renewal of the certificate close to expiry with the installed Kyma version", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(leadTime/2))
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation("upgrade-1", domain.Succeeded, "1.17.0")))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		require.Len(t, queue.ids, 1)
+		operation, err := db.Operations().GetUpgradeKymaOperationByID(queue.ids[0])
+		require.NoError(t, err)
+		assert.True(t, operation.RenewLMSCertificate)
+		assert.Equal(t, orchestration.InProgress, string(operation.State))
+		assert.Equal(t, "1.17.0", operation.RuntimeVersion.Version)
+	})
+
+	t.Run("should trigger renewal of the expired certificate", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(-time.Hour))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Len(t, queue.ids, 1)
+	})
+
+	t.Run("should not renew the certificate far from expiry", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(2*leadTime))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+	})
+
+	t.Run("should track untracked certificate with the assumed expiry", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+		certificate, err := db.LMSCertificates().Get(instanceID)
+		require.NoError(t, err)
+		assert.Equal(t, tenantID, certificate.TenantID)
+		assert.Equal(t, certificate.IssuedAt.Add(validity).Unix(), certificate.ExpiresAt.Unix())
+	})
+
+	t.Run("should renew certificate with the unknown expiry issued long ago", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		require.NoError(t, db.LMSCertificates().Save(internal.LMSCertificate{
+			InstanceID: instanceID,
+			TenantID:   tenantID,
+			IssuedAt:   time.Now().Add(-validity),
+		}))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Len(t, queue.ids, 1)
+	})
+
+	t.Run("should limit the number of renewals triggered in one run", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(-time.Hour))
+		fixProvisionedInstanceWithID(t, db, "other-instance-id", tenantID, time.Now().Add(-validity-time.Hour))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Len(t, queue.ids, 1)
+	})
+
+	t.Run("should retry the failed renewal", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(2*leadTime))
+		renewal := fixUpgradeKymaOperation("upgrade-1", orchestration.Failed, "1.17.0")
+		renewal.RenewLMSCertificate = true
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(renewal))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Len(t, queue.ids, 1)
+	})
+
+	t.Run("should reuse the certificate issued by the failed renewal", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(leadTime/2))
+		failed := fixUpgradeKymaOperation("upgrade-1", orchestration.Failed, "1.17.0")
+		failed.RenewLMSCertificate = true
+		failed.LMSCertificateRenewal = internal.LMSCertificateRenewal{
+			CertificateURL: "cert-url",
+			PrivateKey:     "private-key",
+			RequestedAt:    time.Now().Add(-2 * time.Hour),
+			ExpiresAt:      time.Now().Add(validity),
+		}
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(failed))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		require.Len(t, queue.ids, 1)
+		operation, err := db.Operations().GetUpgradeKymaOperationByID(queue.ids[0])
+		require.NoError(t, err)
+		assert.True(t, operation.RenewLMSCertificate)
+		assert.Equal(t, "cert-url", operation.LMSCertificateRenewal.CertificateURL)
+		assert.Equal(t, "private-key", operation.LMSCertificateRenewal.PrivateKey)
+	})
+
+	t.Run("should request a new certificate when the failed renewal did not get the signed one", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(leadTime/2))
+		failed := fixUpgradeKymaOperation("upgrade-1", orchestration.Failed, "1.17.0")
+		failed.RenewLMSCertificate = true
+		failed.LMSCertificateRenewal = internal.LMSCertificateRenewal{
+			CertificateURL: "cert-url",
+			PrivateKey:     "private-key",
+			RequestedAt:    time.Now().Add(-2 * time.Hour),
+		}
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(failed))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		require.Len(t, queue.ids, 1)
+		operation, err := db.Operations().GetUpgradeKymaOperationByID(queue.ids[0])
+		require.NoError(t, err)
+		assert.Empty(t, operation.LMSCertificateRenewal.CertificateURL)
+	})
+
+	t.Run("should back off renewal which failed several times in a row", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(leadTime/2))
+		for _, id := range []string{"upgrade-1", "upgrade-2"} {
+			failed := fixUpgradeKymaOperation(id, orchestration.Failed, "1.17.0")
+			failed.RenewLMSCertificate = true
+			require.NoError(t, db.Operations().InsertUpgradeKymaOperation(failed))
+		}
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+	})
+
+	t.Run("should not retry renewal after max failed attempts", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(leadTime/2))
+		for i, id := range []string{"upgrade-1", "upgrade-2", "upgrade-3"} {
+			failed := fixUpgradeKymaOperation(id, orchestration.Failed, "1.17.0")
+			failed.RenewLMSCertificate = true
+			failed.CreatedAt = time.Now().Add(-time.Duration(10-i) * 24 * time.Hour)
+			failed.UpdatedAt = failed.CreatedAt
+			require.NoError(t, db.Operations().InsertUpgradeKymaOperation(failed))
+		}
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+	})
+
+	t.Run("should track the certificate delivered by the succeeded renewal", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(leadTime/2))
+		renewal := fixUpgradeKymaOperation("upgrade-1", orchestration.Succeeded, "1.17.0")
+		renewal.RenewLMSCertificate = true
+		renewal.LMSCertificateRenewal = internal.LMSCertificateRenewal{
+			CertificateURL: "cert-url",
+			RequestedAt:    time.Now().Add(-time.Hour),
+			ExpiresAt:      time.Now().Add(90 * 24 * time.Hour),
+		}
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(renewal))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+		certificate, err := db.LMSCertificates().Get(instanceID)
+		require.NoError(t, err)
+		assert.Equal(t, renewal.LMSCertificateRenewal.ExpiresAt.Unix(), certificate.ExpiresAt.Unix())
+	})
+
+	t.Run("should skip Runtimes without LMS", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, "")
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+		_, err := db.LMSCertificates().Get(instanceID)
+		assert.Error(t, err)
+	})
+
+	t.Run("should postpone renewal when the Runtime is being upgraded", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixProvisionedInstance(t, db, tenantID)
+		fixCertificate(t, db, time.Now().Add(-time.Hour))
+		require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation("upgrade-1", orchestration.InProgress, "1.17.0")))
+		queue := &queueStub{}
+
+		// when
+		fixJob(db, queue).UpgradeAll()
+
+		// then
+		assert.Empty(t, queue.ids)
+	})
+}
+
+type queueStub struct {
+	ids []string
+}
+
+func (q *queueStub) Add(operationID string) {
+	q.ids = append(q.ids, operationID)
+}
+
+func fixJob(db storage.BrokerStorage, queue *queueStub) *process.SameVersionUpgradeJob {
+	return NewJob(db.Instances(), db.Operations(), db.LMSCertificates(), queue, Config{
+		LeadTime:            leadTime,
+		BatchSize:           10,
+		CertificateValidity: validity,
+		MaxRenewalsPerRun:   1,
+		RetryBackoff:        45 * time.Minute,
+		MaxFailedAttempts:   3,
+	}, logger.NewLogDummy())
+}
+
+func fixCertificate(t *testing.T, db storage.BrokerStorage, expiresAt time.Time) {
+	require.NoError(t, db.LMSCertificates().Save(internal.LMSCertificate{
+		InstanceID: instanceID,
+		TenantID:   tenantID,
+		IssuedAt:   expiresAt.Add(-90 * 24 * time.Hour),
+		ExpiresAt:  expiresAt,
+	}))
+}
+
+func fixProvisionedInstance(t *testing.T, db storage.BrokerStorage, lmsTenantID string) {
+	fixProvisionedInstanceWithID(t, db, instanceID, lmsTenantID, time.Now().Add(-24*time.Hour))
+}
+
+func fixProvisionedInstanceWithID(t *testing.T, db storage.BrokerStorage, instanceID, lmsTenantID string, provisionedAt time.Time) {
+	require.NoError(t, db.Instances().Insert(internal.Instance{
+		InstanceID:   instanceID,
+		RuntimeID:    "runtime-id",
+		DashboardURL: "https://console.abcd.kyma.example.com",
+		CreatedAt:    provisionedAt,
+	}))
+
+	provisioning := internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:         "provisioning-" + instanceID,
+			InstanceID: instanceID,
+			State:      domain.Succeeded,
+			CreatedAt:  provisionedAt,
+			UpdatedAt:  provisionedAt,
+		},
+		Lms:            internal.LMS{TenantID: lmsTenantID},
+		RuntimeVersion: internal.RuntimeVersionData{Version: "1.16.0", Origin: internal.Defaults},
+	}
+	require.NoError(t, provisioning.SetProvisioningParameters(internal.ProvisioningParameters{PlanID: "plan-id"}))
+	require.NoError(t, db.Operations().InsertProvisioningOperation(provisioning))
+}
+
+func fixUpgradeKymaOperation(id string, state domain.LastOperationState, version string) internal.UpgradeKymaOperation {
+	return internal.UpgradeKymaOperation{
+		Operation: internal.Operation{
+			ID:         id,
+			InstanceID: instanceID,
+			State:      state,
+			CreatedAt:  time.Now().Add(-time.Hour),
+			UpdatedAt:  time.Now().Add(-time.Hour),
+		},
+		RuntimeOperation: orchestration.RuntimeOperation{
+			Runtime: orchestration.Runtime{InstanceID: instanceID},
+		},
+		RuntimeVersion: internal.RuntimeVersionData{Version: version, Origin: internal.Defaults},
+	}
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// ArchiveStatsGetter provides the number of records moved out of the broker tables by the retention job:
+// - compass_keb_archived_records_total{"kind"} - the number of archived operations by operation type and orchestrations
+// - compass_keb_archive_failures_total{"kind"} - the number of batches which failed to be archived
+type ArchiveStatsGetter interface {
+	ArchivedStats() map[string]int
+	FailureStats() map[string]int
+}
+
+type ArchiveCollector struct {
+	statsGetter ArchiveStatsGetter
+
+	archivedDesc *prometheus.Desc
+	failuresDesc *prometheus.Desc
+}
+
+func NewArchiveCollector(statsGetter ArchiveStatsGetter) *ArchiveCollector {
+	return &ArchiveCollector{
+		statsGetter: statsGetter,
+
+		archivedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "archived_records_total"),
+			"The number of finished operations and orchestrations moved out of the broker tables",
+			[]string{"kind"},
+			nil),
+		failuresDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "archive_failures_total"),
+			"The number of batches of finished records which failed to be archived",
+			[]string{"kind"},
+			nil),
+	}
+}
+
+func (c *ArchiveCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.archivedDesc
+	ch <- c.failuresDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *ArchiveCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectCounters(ch, c.archivedDesc, c.statsGetter.ArchivedStats())
+	c.collectCounters(ch, c.failuresDesc, c.statsGetter.FailureStats())
+}
+
+func (c *ArchiveCollector) collectCounters(ch chan<- prometheus.Metric, desc *prometheus.Desc, counts map[string]int) {
+	for kind, count := range counts {
+		m, err := prometheus.NewConstMetric(desc, prometheus.CounterValue, float64(count), kind)
+		if err != nil {
+			logrus.Errorf("unable to register metric %s", err.Error())
+			continue
+		}
+		ch <- m
+	}
+}
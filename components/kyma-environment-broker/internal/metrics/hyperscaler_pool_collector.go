@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// PoolStatsGetter provides the number of secrets in the hyperscaler account pool by state:
+// - compass_keb_hyperscaler_pool_free_secrets - the number of unassigned secrets per hyperscaler type
+// - compass_keb_hyperscaler_pool_assigned_secrets - the number of secrets assigned to tenants per hyperscaler type
+// - compass_keb_hyperscaler_pool_dirty_secrets - the number of secrets waiting for cleanup per hyperscaler type
+// - compass_keb_hyperscaler_pool_nearly_exhausted - 1 if the number of free secrets is not greater than the threshold, 0 otherwise
+type PoolStatsGetter interface {
+	Stats() (map[hyperscaler.Type]hyperscaler.PoolStats, error)
+}
+
+type HyperscalerPoolCollector struct {
+	statsGetter PoolStatsGetter
+	threshold   int
+
+	freeDesc            *prometheus.Desc
+	assignedDesc        *prometheus.Desc
+	dirtyDesc           *prometheus.Desc
+	nearlyExhaustedDesc *prometheus.Desc
+}
+
+func NewHyperscalerPoolCollector(statsGetter PoolStatsGetter, threshold int) *HyperscalerPoolCollector {
+	return &HyperscalerPoolCollector{
+		statsGetter: statsGetter,
+		threshold:   threshold,
+
+		freeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "hyperscaler_pool_free_secrets"),
+			"The number of unassigned secrets in the hyperscaler account pool",
+			[]string{"hyperscaler_type"},
+			nil),
+		assignedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "hyperscaler_pool_assigned_secrets"),
+			"The number of secrets in the hyperscaler account pool assigned to tenants",
+			[]string{"hyperscaler_type"},
+			nil),
+		dirtyDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "hyperscaler_pool_dirty_secrets"),
+			"The number of dirty secrets in the hyperscaler account pool",
+			[]string{"hyperscaler_type"},
+			nil),
+		nearlyExhaustedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "hyperscaler_pool_nearly_exhausted"),
+			"Set to 1 when the number of free secrets in the hyperscaler account pool is not greater than the threshold",
+			[]string{"hyperscaler_type"},
+			nil),
+	}
+}
+
+func (c *HyperscalerPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.freeDesc
+	ch <- c.assignedDesc
+	ch <- c.dirtyDesc
+	ch <- c.nearlyExhaustedDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *HyperscalerPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.statsGetter.Stats()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	for hypType, s := range stats {
+		collect(ch, c.freeDesc, s.Free, string(hypType))
+		collect(ch, c.assignedDesc, s.Assigned, string(hypType))
+		collect(ch, c.dirtyDesc, s.Dirty, string(hypType))
+
+		nearlyExhausted := 0
+		if s.Free <= c.threshold {
+			nearlyExhausted = 1
+		}
+		collect(ch, c.nearlyExhaustedDesc, nearlyExhausted, string(hypType))
+	}
+}
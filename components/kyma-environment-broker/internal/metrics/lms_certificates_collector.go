@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// LMSCertificatesGetter provides the tracked LMS client certificates:
+// - compass_keb_lms_certificates_expiring - the number of certificates which expire within the renewal lead time
+// - compass_keb_lms_certificates_expired - the number of expired certificates
+// - compass_keb_lms_certificates_expiry_unknown - the number of certificates which expiry is not known yet
+type LMSCertificatesGetter interface {
+	List() ([]internal.LMSCertificate, error)
+}
+
+type LMSCertificatesCollector struct {
+	certificatesGetter LMSCertificatesGetter
+	leadTime           time.Duration
+
+	expiringDesc      *prometheus.Desc
+	expiredDesc       *prometheus.Desc
+	expiryUnknownDesc *prometheus.Desc
+}
+
+func NewLMSCertificatesCollector(certificatesGetter LMSCertificatesGetter, leadTime time.Duration) *LMSCertificatesCollector {
+	return &LMSCertificatesCollector{
+		certificatesGetter: certificatesGetter,
+		leadTime:           leadTime,
+
+		expiringDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "lms_certificates_expiring"),
+			"The number of LMS client certificates which expire within the renewal lead time",
+			[]string{},
+			nil),
+		expiredDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "lms_certificates_expired"),
+			"The number of expired LMS client certificates",
+			[]string{},
+			nil),
+		expiryUnknownDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "lms_certificates_expiry_unknown"),
+			"The number of LMS client certificates which expiry is not known yet",
+			[]string{},
+			nil),
+	}
+}
+
+func (c *LMSCertificatesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.expiringDesc
+	ch <- c.expiredDesc
+	ch <- c.expiryUnknownDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *LMSCertificatesCollector) Collect(ch chan<- prometheus.Metric) {
+	certificates, err := c.certificatesGetter.List()
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	expiring, expired, expiryUnknown := 0, 0, 0
+	now := time.Now()
+	for _, certificate := range certificates {
+		switch {
+		case certificate.ExpiresAt.IsZero():
+			expiryUnknown++
+		case now.After(certificate.ExpiresAt):
+			expired++
+		case certificate.ExpiresAt.Sub(now) <= c.leadTime:
+			expiring++
+		}
+	}
+	collect(ch, c.expiringDesc, expiring)
+	collect(ch, c.expiredDesc, expired)
+	collect(ch, c.expiryUnknownDesc, expiryUnknown)
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// OperationConflictsGetter provides the number of optimistic locking conflicts of operation updates:
+// - compass_keb_operation_update_conflicts_total{"operation_type"}
+type OperationConflictsGetter func() map[dbmodel.OperationType]int
+
+type OperationConflictsCollector struct {
+	conflictsGetter OperationConflictsGetter
+
+	conflictsDesc *prometheus.Desc
+}
+
+func NewOperationConflictsCollector(conflictsGetter OperationConflictsGetter) *OperationConflictsCollector {
+	return &OperationConflictsCollector{
+		conflictsGetter: conflictsGetter,
+
+		conflictsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "operation_update_conflicts_total"),
+			"The number of operation updates rejected because the operation was changed concurrently",
+			[]string{"operation_type"},
+			nil),
+	}
+}
+
+func (c *OperationConflictsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.conflictsDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *OperationConflictsCollector) Collect(ch chan<- prometheus.Metric) {
+	for operationType, conflicts := range c.conflictsGetter() {
+		m, err := prometheus.NewConstMetric(c.conflictsDesc, prometheus.CounterValue, float64(conflicts), string(operationType))
+		if err != nil {
+			logrus.Errorf("unable to register metric %s", err.Error())
+			continue
+		}
+		ch <- m
+	}
+}
@@ -43,6 +43,26 @@ type LMS struct {
 	RequestedAt time.Time `json:"requested_at"`
 }
 
+// LMSCertificate tracks the LMS client certificate used by the Runtime to ship logs to the LMS tenant.
+// The zero ExpiresAt means the expiry of the certificate is unknown, the zero IssuedAt means the certificate
+// was issued before KEB started to track the certificates.
+type LMSCertificate struct {
+	InstanceID string
+	TenantID   string
+	IssuedAt   time.Time
+	ExpiresAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// LMSCertificateRenewal is the LMS client certificate requested for the upgrade operation. The certificate is tracked
+// in LMSCertificate only after the operation delivered it to the Runtime.
+type LMSCertificateRenewal struct {
+	CertificateURL string    `json:"certificate_url"`
+	PrivateKey     string    `json:"private_key"`
+	RequestedAt    time.Time `json:"requested_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
 type AvsLifecycleData struct {
 	AvsEvaluationInternalId int64 `json:"avs_evaluation_internal_id"`
 	AVSEvaluationExternalId int64 `json:"avs_evaluation_external_id"`
@@ -156,6 +176,21 @@ type InstanceWithOperation struct {
 	Description sql.NullString
 }
 
+// InstanceWithOperations is an instance with its latest operations, Kyma version and state resolved
+type InstanceWithOperations struct {
+	Instance
+
+	// State is the state of the last operation, "suspended" when the instance was successfully deprovisioned
+	State       string
+	KymaVersion string
+
+	Provisioning   *Operation
+	Deprovisioning *Operation
+	// UpgradeKyma holds the latest non dry run upgrade Kyma operations, newest first
+	UpgradeKyma      []Operation
+	UpgradeKymaTotal int
+}
+
 type SMClientFactory interface {
 	ForCustomerCredentials(reqCredentials *servicemanager.Credentials, log logrus.FieldLogger) (servicemanager.Client, error)
 	ProvideCredentials(reqCredentials *servicemanager.Credentials, log logrus.FieldLogger) (*servicemanager.Credentials, error)
@@ -233,6 +268,35 @@ type UpgradeKymaOperation struct {
 	ProvisioningParameters string `json:"provisioning_parameters"`
 
 	RuntimeVersion RuntimeVersionData `json:"runtime_version"`
+
+	// RotateIASSecret is set when the upgrade is triggered to push new IAS ServiceProvider client secrets into the Runtime
+	RotateIASSecret bool `json:"rotate_ias_secret,omitempty"`
+	// RenewLMSCertificate is set when the upgrade is triggered to push a new LMS client certificate into the Runtime
+	RenewLMSCertificate bool `json:"renew_lms_certificate,omitempty"`
+	// UpdateOptionalComponents is set when the upgrade is triggered to change the optional components of the Runtime,
+	// OptionalComponentsToInstall are stored in the provisioning parameters only when the upgrade succeeds
+	UpdateOptionalComponents    bool     `json:"update_optional_components,omitempty"`
+	OptionalComponentsToInstall []string `json:"optional_components_to_install,omitempty"`
+	// LMSCertificateRenewal keeps the LMS client certificate requested by the operation between the step retries
+	LMSCertificateRenewal LMSCertificateRenewal `json:"lms_certificate_renewal"`
+	// IASSecrets keeps the client secrets generated by the operation between the step retries by the ServiceProvider name
+	IASSecrets map[string]IASClientSecret `json:"ias_secrets,omitempty"`
+	// IASSecretsOperationID is the ID of the operation which added the IAS secrets, the secrets added by a failed
+	// rotation are reused by the next one
+	IASSecretsOperationID string `json:"ias_secrets_operation_id,omitempty"`
+}
+
+// IASSecretsAddedBy returns the ID of the operation which added the IAS secrets used by the operation
+func (o UpgradeKymaOperation) IASSecretsAddedBy() string {
+	if o.IASSecretsOperationID == "" {
+		return o.Operation.ID
+	}
+	return o.IASSecretsOperationID
+}
+
+type IASClientSecret struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
 }
 
 func NewRuntimeState(runtimeID, operationID string, kymaConfig *gqlschema.KymaConfigInput, clusterConfig *gqlschema.GardenerConfigInput) RuntimeState {
@@ -269,6 +333,61 @@ type RuntimeState struct {
 	ClusterConfig gqlschema.GardenerConfigInput `json:"clusterConfig"`
 }
 
+// SubAccountCleanupRun is a record of a single subaccount cleanup execution
+type SubAccountCleanupRun struct {
+	ID string `json:"id"`
+
+	DryRun      bool   `json:"dryRun"`
+	Aborted     bool   `json:"aborted"`
+	Description string `json:"description"`
+
+	SubAccounts    []string                       `json:"subAccounts"`
+	GlobalAccounts []string                       `json:"globalAccounts,omitempty"`
+	Instances      []SubAccountCleanupRunInstance `json:"instances"`
+	// LookupErrors contains errors of the instance lookups, the instances of the affected accounts were not deprovisioned
+	LookupErrors []string `json:"lookupErrors,omitempty"`
+
+	CreatedAt  time.Time `json:"createdAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// ReEncryptionResult summarizes a single batch of values re-encrypted with the newest encryption key
+type ReEncryptionResult struct {
+	Listed  int
+	Updated int
+	// Skipped is the number of values which could not be decrypted, e.g. because their key was removed
+	Skipped int
+	// LastID is the ID of the last listed value, the next batch starts after it
+	LastID string
+}
+
+// CISHighWaterMark points to the last CIS event processed by the account cleanup
+type CISHighWaterMark struct {
+	Source        string
+	LastEventID   string
+	LastEventTime time.Time
+	// FailedAttempts is the number of runs in which the event following the mark failed
+	FailedAttempts int
+	UpdatedAt      time.Time
+}
+
+// IASRotation tracks the IAS ServiceProviders of the instance: when their client secrets were rotated
+// and which dashboard URL they were configured with
+type IASRotation struct {
+	InstanceID      string
+	SecretRotatedAt time.Time
+	DashboardURL    string
+	UpdatedAt       time.Time
+}
+
+// SubAccountCleanupRunInstance describes what happened to an instance during a cleanup run
+type SubAccountCleanupRunInstance struct {
+	InstanceID   string `json:"instanceId"`
+	SubAccountID string `json:"subAccountId"`
+	OperationID  string `json:"operationId,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
 // OperationStats provide number of operations per type and state
 type OperationStats struct {
 	Provisioning   map[domain.LastOperationState]int
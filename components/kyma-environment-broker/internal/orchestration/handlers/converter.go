@@ -87,7 +87,66 @@ func (c *Converter) UpgradeKymaOperationToDetailDTO(op internal.UpgradeKymaOpera
 	}
 	return orchestration.OperationDetailResponse{
 		OperationResponse: resp,
-		KymaConfig:        kymaConfig,
+		KymaConfig:        redactSecrets(kymaConfig),
 		ClusterConfig:     clusterConfig,
 	}, nil
 }
+
+// redactedValue replaces values of secret overrides and credentials, e.g. Service Manager credentials, which must not be exposed
+const redactedValue = "<redacted>"
+
+// credentialKeys are overrides with credentials set by the broker which are not always marked as secret,
+// e.g. the Service Manager user or the LMS private key
+var credentialKeys = map[string]struct{}{
+	"sm.user":        {},
+	"sm.password":    {},
+	"kafka.username": {},
+	"kafka.password": {},
+	"grafana.env.GF_AUTH_GENERIC_OAUTH_CLIENT_ID":     {},
+	"grafana.env.GF_AUTH_GENERIC_OAUTH_CLIENT_SECRET": {},
+	"fluent-bit.backend.forward.tls.key":              {},
+}
+
+// redactSecrets returns a copy of the Kyma configuration with values of secret overrides and credentials replaced
+func redactSecrets(kymaConfig gqlschema.KymaConfigInput) gqlschema.KymaConfigInput {
+	kymaConfig.Configuration = redactConfigEntries(kymaConfig.Configuration)
+
+	components := make([]*gqlschema.ComponentConfigurationInput, 0, len(kymaConfig.Components))
+	for _, component := range kymaConfig.Components {
+		if component == nil {
+			components = append(components, nil)
+			continue
+		}
+		c := *component
+		c.Configuration = redactConfigEntries(component.Configuration)
+		components = append(components, &c)
+	}
+	kymaConfig.Components = components
+
+	return kymaConfig
+}
+
+func redactConfigEntries(entries []*gqlschema.ConfigEntryInput) []*gqlschema.ConfigEntryInput {
+	if entries == nil {
+		return nil
+	}
+	result := make([]*gqlschema.ConfigEntryInput, 0, len(entries))
+	for _, entry := range entries {
+		if entry == nil || !isSensitive(entry) {
+			result = append(result, entry)
+			continue
+		}
+		e := *entry
+		e.Value = redactedValue
+		result = append(result, &e)
+	}
+	return result
+}
+
+func isSensitive(entry *gqlschema.ConfigEntryInput) bool {
+	if entry.Secret != nil && *entry.Secret {
+		return true
+	}
+	_, found := credentialKeys[entry.Key]
+	return found
+}
@@ -102,6 +102,50 @@ func TestConverter_UpgradeKymaOperationToDetailDTO(t *testing.T) {
 	assert.Equal(t, id, resp.ClusterConfig.KubernetesVersion)
 }
 
+func TestConverter_UpgradeKymaOperationToDetailDTO_RedactsSecrets(t *testing.T) {
+	// given
+	c := handlers.Converter{}
+
+	secret := true
+	kymaConfig := gqlschema.KymaConfigInput{
+		Configuration: []*gqlschema.ConfigEntryInput{
+			{Key: "global.domainName", Value: "example.com"},
+			{Key: "sm.password", Value: "pass", Secret: &secret},
+		},
+		Components: []*gqlschema.ComponentConfigurationInput{
+			{
+				Component: "service-manager-proxy",
+				Configuration: []*gqlschema.ConfigEntryInput{
+					{Key: "config.sm.url", Value: "http://sm.url"},
+					{Key: "sm.user", Value: "user"},
+					{Key: "grafana.env.GF_AUTH_GENERIC_OAUTH_CLIENT_ID", Value: "client-id"},
+					{Key: "fluent-bit.backend.forward.tls.key", Value: "private-key"},
+					{Key: "connector-service.tokenExpirationMinutes", Value: "5"},
+					{Key: "global.disableLegacyConnectivity", Value: "true"},
+				},
+			},
+		},
+	}
+
+	// when
+	resp, err := c.UpgradeKymaOperationToDetailDTO(fixOperation("id"), kymaConfig, gqlschema.GardenerConfigInput{})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", resp.KymaConfig.Configuration[0].Value)
+	assert.NotEqual(t, "pass", resp.KymaConfig.Configuration[1].Value)
+	assert.Equal(t, "http://sm.url", resp.KymaConfig.Components[0].Configuration[0].Value)
+	assert.NotEqual(t, "user", resp.KymaConfig.Components[0].Configuration[1].Value)
+	assert.NotEqual(t, "client-id", resp.KymaConfig.Components[0].Configuration[2].Value)
+	assert.NotEqual(t, "private-key", resp.KymaConfig.Components[0].Configuration[3].Value)
+	assert.Equal(t, "5", resp.KymaConfig.Components[0].Configuration[4].Value)
+	assert.Equal(t, "true", resp.KymaConfig.Components[0].Configuration[5].Value)
+
+	// the given configuration is not modified
+	assert.Equal(t, "pass", kymaConfig.Configuration[1].Value)
+	assert.Equal(t, "user", kymaConfig.Components[0].Configuration[1].Value)
+}
+
 func fixOperation(id string) internal.UpgradeKymaOperation {
 	return internal.UpgradeKymaOperation{
 		Operation: internal.Operation{
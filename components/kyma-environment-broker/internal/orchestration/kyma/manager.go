@@ -21,6 +21,7 @@ import (
 type upgradeKymaManager struct {
 	orchestrationStorage storage.Orchestrations
 	operationStorage     storage.Operations
+	operationUpdater     *storage.OperationUpdater
 	resolver             orchestration.RuntimeResolver
 	kymaUpgradeExecutor  process.Executor
 	log                  logrus.FieldLogger
@@ -33,6 +34,7 @@ func NewUpgradeKymaManager(orchestrationStorage storage.Orchestrations, operatio
 	return &upgradeKymaManager{
 		orchestrationStorage: orchestrationStorage,
 		operationStorage:     operationStorage,
+		operationUpdater:     storage.NewOperationUpdater(operationStorage),
 		resolver:             resolver,
 		kymaUpgradeExecutor:  kymaUpgradeExecutor,
 		pollingInterval:      pollingInterval,
@@ -262,9 +264,14 @@ func (u *upgradeKymaManager) resolveCanceledOperations(o *internal.Orchestration
 		return errors.Wrap(err, "while listing upgrade operations")
 	}
 	for _, op := range ops {
-		op.State = orchestration.Canceled
-		op.Description = "Operation was canceled"
-		_, err := u.operationStorage.UpdateUpgradeKymaOperation(op)
+		// the operation could be already taken by the worker, only the pending one is canceled
+		_, err := u.operationUpdater.UpdateUpgradeKymaOperationWithRetry(op, func(op *internal.UpgradeKymaOperation) {
+			if op.State != orchestration.Pending {
+				return
+			}
+			op.State = orchestration.Canceled
+			op.Description = "Operation was canceled"
+		})
 		if err != nil {
 			return errors.Wrap(err, "while updating upgrade kyma operation")
 		}
@@ -11,6 +11,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/orchestration/kyma"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
@@ -207,6 +208,66 @@ func TestUpgradeKymaManager_Execute(t *testing.T) {
 
 		assert.Equal(t, orchestration.Canceled, string(op.State))
 	})
+
+	t.Run("Canceled does not override the operation taken by the worker", func(t *testing.T) {
+		// given
+		store := storage.NewMemoryStorage()
+
+		resolver := &automock.RuntimeResolver{}
+		defer resolver.AssertExpectations(t)
+
+		id := "id"
+		err := store.Orchestrations().Insert(internal.Orchestration{
+			OrchestrationID: id,
+			State:           orchestration.Canceling,
+			Parameters: orchestration.Parameters{Strategy: orchestration.StrategySpec{
+				Type:     orchestration.ParallelStrategy,
+				Schedule: orchestration.Immediate,
+			}},
+		})
+		require.NoError(t, err)
+		err = store.Operations().InsertUpgradeKymaOperation(internal.UpgradeKymaOperation{
+			Operation: internal.Operation{
+				ID:              id,
+				OrchestrationID: id,
+				State:           orchestration.Pending,
+			},
+		})
+		require.NoError(t, err)
+
+		operations := &takenByWorker{Operations: store.Operations()}
+		svc := kyma.NewUpgradeKymaManager(store.Orchestrations(), operations, &testExecutor{}, resolver, poolingInterval, logrus.New())
+
+		// when
+		_, err = svc.Execute(id)
+		require.NoError(t, err)
+
+		// then
+		op, err := store.Operations().GetUpgradeKymaOperationByID(id)
+		require.NoError(t, err)
+
+		assert.Equal(t, orchestration.InProgress, string(op.State))
+	})
+}
+
+// takenByWorker moves the pending operations listed for cancellation to in progress, like the worker does between
+// the listing and the update of the orchestration manager
+type takenByWorker struct {
+	storage.Operations
+}
+
+func (w *takenByWorker) ListUpgradeKymaOperationsByOrchestrationID(orchestrationID string, filter dbmodel.OperationFilter) ([]internal.UpgradeKymaOperation, int, int, error) {
+	ops, count, total, err := w.Operations.ListUpgradeKymaOperationsByOrchestrationID(orchestrationID, filter)
+	if err != nil || len(filter.States) != 1 || filter.States[0] != orchestration.Pending {
+		return ops, count, total, err
+	}
+	for _, op := range ops {
+		op.State = orchestration.InProgress
+		if _, err := w.Operations.UpdateUpgradeKymaOperation(op); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+	return ops, count, total, nil
 }
 
 type testExecutor struct{}
@@ -15,11 +15,13 @@ import (
 
 type DeprovisionOperationManager struct {
 	storage storage.Operations
+	updater *storage.OperationUpdater
 }
 
-func NewDeprovisionOperationManager(storage storage.Operations) *DeprovisionOperationManager {
+func NewDeprovisionOperationManager(operations storage.Operations) *DeprovisionOperationManager {
 	return &DeprovisionOperationManager{
-		storage: storage,
+		storage: operations,
+		updater: storage.NewOperationUpdater(operations),
 	}
 }
 
@@ -45,10 +47,13 @@ func (om *DeprovisionOperationManager) OperationFailed(operation internal.Deprov
 	return updatedOperation, 0, errors.New(description)
 }
 
-// UpdateOperation updates a given operation
-func (om *DeprovisionOperationManager) UpdateOperation(operation internal.DeprovisioningOperation) (internal.DeprovisioningOperation, time.Duration, error) {
-	updatedOperation, err := om.storage.UpdateDeprovisioningOperation(operation)
+// UpdateOperation applies the update to the given operation and stores it, on conflict the update is applied
+// to the latest version of the operation, so all changes which should be stored must be done by the update function
+func (om *DeprovisionOperationManager) UpdateOperation(operation internal.DeprovisioningOperation, update func(operation *internal.DeprovisioningOperation)) (internal.DeprovisioningOperation, time.Duration, error) {
+	updatedOperation, err := om.updater.UpdateDeprovisioningOperationWithRetry(operation, update)
+	// repeat if there is a problem with the storage
 	if err != nil {
+		update(&operation)
 		return operation, 1 * time.Minute, nil
 	}
 	return *updatedOperation, 0, nil
@@ -100,12 +105,15 @@ func (om *DeprovisionOperationManager) RetryOperationWithoutFail(operation inter
 }
 
 func (om *DeprovisionOperationManager) update(operation internal.DeprovisioningOperation, state domain.LastOperationState, description string) (internal.DeprovisioningOperation, time.Duration) {
-	operation.State = state
-	operation.Description = fmt.Sprintf("%s : %s", operation.Description, description)
+	mutate := func(op *internal.DeprovisioningOperation) {
+		op.State = state
+		op.Description = fmt.Sprintf("%s : %s", op.Description, description)
+	}
 
-	updatedOperation, err := om.storage.UpdateDeprovisioningOperation(operation)
+	updatedOperation, err := om.updater.UpdateDeprovisioningOperationWithRetry(operation, mutate)
 	// repeat if there is a problem with the storage
 	if err != nil {
+		mutate(&operation)
 		return operation, 1 * time.Minute
 	}
 
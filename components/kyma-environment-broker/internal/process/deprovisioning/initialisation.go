@@ -78,9 +78,6 @@ func (s *InitialisationStep) run(operation internal.DeprovisioningOperation, log
 		return operation, time.Minute, nil
 	}
 	operation.SMClientFactory = s.serviceManagerClientFactory
-	operation.XSUAA = op.XSUAA
-
-	setAvsIds(&operation, op, log)
 
 	parameters, err := op.GetProvisioningParameters()
 	if err != nil {
@@ -94,7 +91,12 @@ func (s *InitialisationStep) run(operation internal.DeprovisioningOperation, log
 		return s.operationManager.OperationFailed(operation, err.Error())
 	}
 
-	operation, repeat, _ := s.operationManager.UpdateOperation(operation)
+	provisioningParameters := operation.ProvisioningParameters
+	operation, repeat, _ := s.operationManager.UpdateOperation(operation, func(operation *internal.DeprovisioningOperation) {
+		operation.XSUAA = op.XSUAA
+		operation.ProvisioningParameters = provisioningParameters
+		setAvsIds(operation, op, log)
+	})
 	if repeat != 0 {
 		log.Errorf("cannot save the operation")
 		return operation, time.Second, nil
@@ -70,10 +70,11 @@ func (s *RemoveRuntimeStep) Run(operation internal.DeprovisioningOperation, log
 			log.Errorf("unable to deprovision runtime: %s", err)
 			return operation, 10 * time.Second, nil
 		}
-		operation.ProvisionerOperationID = provisionerResponse
 		log.Infof("fetched ProvisionerOperationID=%s", provisionerResponse)
 
-		operation, repeat, err := s.operationManager.UpdateOperation(operation)
+		operation, repeat, err := s.operationManager.UpdateOperation(operation, func(operation *internal.DeprovisioningOperation) {
+			operation.ProvisionerOperationID = provisionerResponse
+		})
 		if repeat != 0 {
 			log.Errorf("cannot save operation ID from provisioner: %s", err)
 			return operation, 5 * time.Second, nil
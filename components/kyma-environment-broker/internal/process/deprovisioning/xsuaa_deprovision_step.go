@@ -41,8 +41,9 @@ func (s *XSUAADeprovisionStep) Run(operation internal.DeprovisioningOperation, l
 	if err != nil {
 		return s.handleError(operation, err, "unable to deprovision", log)
 	}
-	operation.XSUAA.Instance.InstanceID = ""
-	return s.operationManager.UpdateOperation(operation)
+	return s.operationManager.UpdateOperation(operation, func(operation *internal.DeprovisioningOperation) {
+		operation.XSUAA.Instance.InstanceID = ""
+	})
 }
 
 func (s *XSUAADeprovisionStep) handleError(operation internal.DeprovisioningOperation, err error, msg string, log logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
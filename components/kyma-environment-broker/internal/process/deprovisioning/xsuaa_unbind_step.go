@@ -42,8 +42,9 @@ func (s *XSUAAUnbindStep) Run(operation internal.DeprovisioningOperation, log lo
 	if err != nil {
 		return s.handleError(operation, err, fmt.Sprintf("unable to unbind, bindingId=%s", operation.XSUAA.BindingID), log)
 	}
-	operation.XSUAA.BindingID = ""
-	return s.operationManager.UpdateOperation(operation)
+	return s.operationManager.UpdateOperation(operation, func(operation *internal.DeprovisioningOperation) {
+		operation.XSUAA.BindingID = ""
+	})
 }
 
 func (s *XSUAAUnbindStep) handleError(operation internal.DeprovisioningOperation, err error, msg string, log logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
@@ -217,6 +217,7 @@ func (f *InputBuilderFactory) CreateUpgradeInput(pp internal.ProvisioningParamet
 
 	return &RuntimeInput{
 		upgradeRuntimeInput:       upgradeKymaInput,
+		provisioningParameters:    pp,
 		mutex:                     nsync.NewNamedMutex(),
 		overrides:                 make(map[string][]*gqlschema.ConfigEntryInput, 0),
 		globalOverrides:           make([]*gqlschema.ConfigEntryInput, 0),
@@ -102,6 +102,42 @@ func TestShouldEnableComponents(t *testing.T) {
 		})
 		assert.Len(t, input.KymaConfig.Components, 2)
 	})
+
+	t.Run("When creating UpgradeRuntimeInput with optional components from provisioning parameters", func(t *testing.T) {
+		// given
+		optionalComponentsDisablers := runtime.ComponentsDisablers{
+			components.Kiali:   runtime.NewGenericComponentDisabler(components.Kiali),
+			components.Tracing: runtime.NewGenericComponentDisabler(components.Tracing),
+		}
+		componentsProvider := &automock.ComponentListProvider{}
+		componentsProvider.On("AllComponents", mock.AnythingOfType("string")).
+			Return([]v1alpha1.KymaComponent{
+				{Name: components.Kiali},
+				{Name: components.Tracing},
+				{Name: "dex"},
+			}, nil)
+
+		builder, err := NewInputBuilderFactory(runtime.NewOptionalComponentsService(optionalComponentsDisablers), runtime.NewDisabledComponentsProvider(), componentsProvider, Config{}, "not-important", fixTrialRegionMapping())
+		assert.NoError(t, err)
+
+		pp := fixProvisioningParameters(broker.AzurePlanID, "1.14.0")
+		pp.Parameters.OptionalComponentsToInstall = []string{components.Tracing}
+		creator, err := builder.CreateUpgradeInput(pp, internal.RuntimeVersionData{Version: "1.14.0", Origin: internal.Defaults})
+		require.NoError(t, err)
+
+		// when
+		input, err := creator.CreateUpgradeRuntimeInput()
+		require.NoError(t, err)
+
+		// then
+		assertComponentExists(t, input.KymaConfig.Components, gqlschema.ComponentConfigurationInput{
+			Component: components.Tracing,
+		})
+		assertComponentExists(t, input.KymaConfig.Components, gqlschema.ComponentConfigurationInput{
+			Component: "dex",
+		})
+		assert.Len(t, input.KymaConfig.Components, 2)
+	})
 }
 
 func TestShouldDisableComponents(t *testing.T) {
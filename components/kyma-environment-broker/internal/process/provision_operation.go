@@ -15,10 +15,14 @@ import (
 
 type ProvisionOperationManager struct {
 	storage storage.Provisioning
+	updater *storage.OperationUpdater
 }
 
-func NewProvisionOperationManager(storage storage.Operations) *ProvisionOperationManager {
-	return &ProvisionOperationManager{storage: storage}
+func NewProvisionOperationManager(operations storage.Operations) *ProvisionOperationManager {
+	return &ProvisionOperationManager{
+		storage: operations,
+		updater: storage.NewOperationUpdater(operations),
+	}
 }
 
 // OperationSucceeded marks the operation as succeeded and only repeats it if there is a storage error
@@ -43,10 +47,13 @@ func (om *ProvisionOperationManager) OperationFailed(operation internal.Provisio
 	return updatedOperation, 0, errors.New(description)
 }
 
-// UpdateOperation updates a given operation
-func (om *ProvisionOperationManager) UpdateOperation(operation internal.ProvisioningOperation) (internal.ProvisioningOperation, time.Duration) {
-	updatedOperation, err := om.storage.UpdateProvisioningOperation(operation)
+// UpdateOperation applies the update to the given operation and stores it, on conflict the update is applied
+// to the latest version of the operation, so all changes which should be stored must be done by the update function
+func (om *ProvisionOperationManager) UpdateOperation(operation internal.ProvisioningOperation, update func(operation *internal.ProvisioningOperation)) (internal.ProvisioningOperation, time.Duration) {
+	updatedOperation, err := om.updater.UpdateProvisioningOperationWithRetry(operation, update)
+	// repeat if there is a problem with the storage
 	if err != nil {
+		update(&operation)
 		return operation, 1 * time.Minute
 	}
 	return *updatedOperation, 0
@@ -71,8 +78,16 @@ func (om *ProvisionOperationManager) RetryOperation(operation internal.Provision
 }
 
 func (om *ProvisionOperationManager) update(operation internal.ProvisioningOperation, state domain.LastOperationState, description string) (internal.ProvisioningOperation, time.Duration) {
-	operation.State = state
-	operation.Description = fmt.Sprintf("%s : %s", operation.Description, description)
+	mutate := func(op *internal.ProvisioningOperation) {
+		op.State = state
+		op.Description = fmt.Sprintf("%s : %s", op.Description, description)
+	}
 
-	return om.UpdateOperation(operation)
+	updatedOperation, err := om.updater.UpdateProvisioningOperationWithRetry(operation, mutate)
+	// repeat if there is a problem with the storage
+	if err != nil {
+		mutate(&operation)
+		return operation, 1 * time.Minute
+	}
+	return *updatedOperation, 0
 }
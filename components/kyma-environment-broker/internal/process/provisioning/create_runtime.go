@@ -79,11 +79,12 @@ func (s *CreateRuntimeStep) Run(operation internal.ProvisioningOperation, log lo
 			return s.operationManager.OperationFailed(operation, "call to the provisioner service failed")
 		}
 
-		operation.ProvisionerOperationID = *provisionerResponse.ID
-		if provisionerResponse.RuntimeID != nil {
-			operation.RuntimeID = *provisionerResponse.RuntimeID
-		}
-		operation, repeat := s.operationManager.UpdateOperation(operation)
+		operation, repeat := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+			operation.ProvisionerOperationID = *provisionerResponse.ID
+			if provisionerResponse.RuntimeID != nil {
+				operation.RuntimeID = *provisionerResponse.RuntimeID
+			}
+		})
 		if repeat != 0 {
 			log.Errorf("cannot save operation ID from provisioner")
 			return operation, 5 * time.Second, nil
@@ -1,7 +1,6 @@
 package provisioning
 
 import (
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"regexp"
@@ -12,7 +11,6 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/lms"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
-	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 
 	"crypto/x509/pkix"
 
@@ -40,10 +38,11 @@ type LmsClient interface {
 type lmsCertStep struct {
 	LmsStep
 	provider            LmsClient
+	certificates        storage.LMSCertificates
 	normalizationRegexp *regexp.Regexp
 }
 
-func NewLmsCertificatesStep(certProvider LmsClient, os storage.Operations, isMandatory bool) *lmsCertStep {
+func NewLmsCertificatesStep(certProvider LmsClient, os storage.Operations, certificates storage.LMSCertificates, isMandatory bool) *lmsCertStep {
 	return &lmsCertStep{
 		LmsStep: LmsStep{
 			operationManager: process.NewProvisionOperationManager(os),
@@ -51,6 +50,7 @@ func NewLmsCertificatesStep(certProvider LmsClient, os storage.Operations, isMan
 			expirationTime:   lmsTimeout,
 		},
 		provider:            certProvider,
+		certificates:        certificates,
 		normalizationRegexp: regexp.MustCompile("[^a-zA-Z0-9]+"),
 	}
 }
@@ -63,6 +63,7 @@ func (s *lmsCertStep) Name() string {
 // 1. check if the tenant is ready
 // 2. request certificates
 // 3. poll CA and signed certificates
+// 4. store the certificate expiry, so the certificate can be renewed before it expires
 func (s *lmsCertStep) Run(operation internal.ProvisioningOperation, l logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
 	if operation.Lms.Failed {
 		l.Info("LMS has failed, skipping")
@@ -122,6 +123,7 @@ func (s *lmsCertStep) Run(operation internal.ProvisioningOperation, l logrus.Fie
 		return operation, 5 * time.Second, nil
 	}
 	logger.Infof("Signed Certificate URL: %s", certURL)
+	issuedAt := time.Now()
 
 	var signedCert string
 	var caCert string
@@ -167,33 +169,32 @@ func (s *lmsCertStep) Run(operation internal.ProvisioningOperation, l logrus.Fie
 
 	operation.InputCreator.SetLabel(kibanaURLLabelKey, fmt.Sprintf("https://kibana.%s", tenantInfo.DNS))
 
-	operation.InputCreator.AppendOverrides("logging", []*gqlschema.ConfigEntryInput{
-		{Key: "fluent-bit.conf.Output.forward.enabled", Value: "true"},
-		{Key: "fluent-bit.conf.Output.forward.Match", Value: "kube.*"},
-
-		{Key: "fluent-bit.backend.forward.host", Value: fmt.Sprintf("forward.%s", tenantInfo.DNS)},
-		{Key: "fluent-bit.backend.forward.port", Value: "8443"},
-		{Key: "fluent-bit.backend.forward.tls.enabled", Value: "true"},
-		{Key: "fluent-bit.backend.forward.tls.verify", Value: "On"},
-
-		// certs and private key must be encoded by base64
-		{Key: "fluent-bit.backend.forward.tls.ca", Value: base64.StdEncoding.EncodeToString([]byte(caCert))},
-		{Key: "fluent-bit.backend.forward.tls.cert", Value: base64.StdEncoding.EncodeToString([]byte(signedCert))},
-		{Key: "fluent-bit.backend.forward.tls.key", Value: base64.StdEncoding.EncodeToString(pKey)},
-
-		// record modifier filter
-		{Key: "fluent-bit.conf.Filter.record_modifier.enabled", Value: "true"},
-		{Key: "fluent-bit.conf.Filter.record_modifier.Match", Value: "kube.*"},
-		{Key: "fluent-bit.conf.Filter.record_modifier.Key", Value: "subaccount_id"},
-		{Key: "fluent-bit.conf.Filter.record_modifier.Value", Value: pp.ErsContext.SubAccountID}, // cluster_name is a tag added to log entry, allows to filter logs by a cluster
-		//kubernetes filter should not parse the document to avoid indexing on LMS side
-		{Key: "fluent-bit.conf.Filter.Kubernetes.Merge_Log", Value: "Off"},
-		//input should not contain dex logs as it contains sensitive data
-		{Key: "fluent-bit.conf.Input.Kubernetes.Exclude_Path", Value: "/var/log/containers/*_dex-*.log,/var/log/containers/*_kcproxy-*.log"},
-	})
+	operation.InputCreator.AppendOverrides("logging", lms.CertificateOverrides(tenantInfo.DNS, caCert, signedCert, pKey))
+	operation.InputCreator.AppendOverrides("logging", lms.RecordModifierOverrides(pp.ErsContext.SubAccountID))
+
+	s.saveCertificate(operation, issuedAt, signedCert, logger)
+
 	return operation, 0, nil
 }
 
+// saveCertificate does not fail the operation, the certificate without the record is renewed by the renewal job
+func (s *lmsCertStep) saveCertificate(operation internal.ProvisioningOperation, issuedAt time.Time, signedCert string, log logrus.FieldLogger) {
+	certificate := internal.LMSCertificate{
+		InstanceID: operation.InstanceID,
+		TenantID:   operation.Lms.TenantID,
+		IssuedAt:   issuedAt,
+	}
+	expiresAt, err := lms.CertificateExpiry(signedCert)
+	if err != nil {
+		log.Warnf("Unable to read LMS Signed Certificate expiry: %s", err)
+	}
+	certificate.ExpiresAt = expiresAt
+
+	if err := s.certificates.Save(certificate); err != nil {
+		log.Errorf("Unable to save LMS certificate: %s", err)
+	}
+}
+
 type LmsStep struct {
 	operationManager *process.ProvisionOperationManager
 	isMandatory      bool
@@ -218,6 +219,8 @@ func (s *LmsStep) failLmsAndUpdate(operation internal.ProvisioningOperation, msg
 	if s.isMandatory {
 		return s.operationManager.OperationFailed(operation, msg)
 	}
-	modifiedOp, retry := s.operationManager.UpdateOperation(operation)
+	modifiedOp, retry := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		operation.Lms.Failed = true
+	})
 	return modifiedOp, retry, nil
 }
@@ -16,7 +16,7 @@ import (
 func TestCertStep_RunFreshOperation(t *testing.T) {
 	// given
 	repo := storage.NewMemoryStorage().Operations()
-	svc := NewLmsCertificatesStep(nil, repo, false)
+	svc := NewLmsCertificatesStep(nil, repo, storage.NewMemoryStorage().LMSCertificates(), false)
 	// a fresh operation
 	operation := internal.ProvisioningOperation{
 		Lms: internal.LMS{},
@@ -33,7 +33,7 @@ func TestCertStep_Run(t *testing.T) {
 	// given
 	cli, tID := newFakeClientWithTenant(0)
 	repo := storage.NewMemoryStorage().Operations()
-	svc := NewLmsCertificatesStep(cli, repo, false)
+	svc := NewLmsCertificatesStep(cli, repo, storage.NewMemoryStorage().LMSCertificates(), false)
 	operation := internal.ProvisioningOperation{
 		Lms: internal.LMS{
 			TenantID: tID,
@@ -59,7 +59,7 @@ func TestCertStep_TenantNotReady(t *testing.T) {
 		// given
 		cli, tID := newFakeClientWithTenant(time.Hour)
 		repo := storage.NewMemoryStorage().Operations()
-		svc := NewLmsCertificatesStep(cli, repo, isMandatory)
+		svc := NewLmsCertificatesStep(cli, repo, storage.NewMemoryStorage().LMSCertificates(), isMandatory)
 		operation := internal.ProvisioningOperation{
 			Lms: internal.LMS{
 				TenantID:    tID,
@@ -87,7 +87,7 @@ func TestCertStep_TenantNotReadyTimeout(t *testing.T) {
 		// given
 		cli, tID := newFakeClientWithTenant(time.Hour)
 		repo := storage.NewMemoryStorage().Operations()
-		svc := NewLmsCertificatesStep(cli, repo, isMandatory)
+		svc := NewLmsCertificatesStep(cli, repo, storage.NewMemoryStorage().LMSCertificates(), isMandatory)
 		operation := internal.ProvisioningOperation{
 			Lms: internal.LMS{
 				TenantID:    tID,
@@ -115,12 +115,14 @@ func TestLmsStepsHappyPath(t *testing.T) {
 	lmsClient := lms.NewFakeClient(0)
 	opRepo := storage.NewMemoryStorage().Operations()
 	tRepo := storage.NewMemoryStorage().LMSTenants()
-	certStep := NewLmsCertificatesStep(lmsClient, opRepo, false)
+	certRepo := storage.NewMemoryStorage().LMSCertificates()
+	certStep := NewLmsCertificatesStep(lmsClient, opRepo, certRepo, false)
 	tManager := lms.NewTenantManager(tRepo, lmsClient, fixLogger())
 	tenantStep := NewProvideLmsTenantStep(tManager, opRepo, "eu", false)
 
 	inputCreator := newInputCreator()
 	operation := internal.ProvisioningOperation{
+		Operation:              internal.Operation{ID: "op-id", InstanceID: "inst-id"},
 		Lms:                    internal.LMS{},
 		ProvisioningParameters: `{"Parameters": {"name":"Awesome Lms"}}`,
 		InputCreator:           inputCreator,
@@ -157,6 +159,13 @@ func TestLmsStepsHappyPath(t *testing.T) {
 		Key: "fluent-bit.backend.forward.tls.key", Value: "cHJpdmF0ZS1rZXk="})
 
 	inputCreator.AssertLabel(t, "operator_lmsUrl", fmt.Sprintf("https://kibana.%s", lms.FakeLmsHost))
+
+	certificate, err := certRepo.Get("inst-id")
+	require.NoError(t, err)
+	assert.Equal(t, op.Lms.TenantID, certificate.TenantID)
+	assert.False(t, certificate.IssuedAt.IsZero())
+	// the fake client does not return a real certificate
+	assert.True(t, certificate.ExpiresAt.IsZero())
 }
 
 func newFakeClientWithTenant(timeToReady time.Duration) (*lms.FakeClient, string) {
@@ -64,12 +64,12 @@ func (s *provideLmsTenantStep) Run(operation internal.ProvisioningOperation, log
 			err)
 	}
 
-	operation.Lms.TenantID = lmsTenantID
-	if operation.Lms.RequestedAt.IsZero() {
-		operation.Lms.RequestedAt = time.Now()
-	}
-
-	op, repeat := s.operationManager.UpdateOperation(operation)
+	op, repeat := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		operation.Lms.TenantID = lmsTenantID
+		if operation.Lms.RequestedAt.IsZero() {
+			operation.Lms.RequestedAt = time.Now()
+		}
+	})
 	if repeat != 0 {
 		logger.Errorf("cannot save LMS tenant ID")
 		return operation, time.Second, nil
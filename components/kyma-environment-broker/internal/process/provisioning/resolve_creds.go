@@ -2,6 +2,7 @@ package provisioning
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
@@ -9,6 +10,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/provider"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -17,8 +19,9 @@ import (
 type ResolveCredentialsStep struct {
 	operationManager *process.ProvisionOperationManager
 	accountProvider  hyperscaler.AccountProvider
-	opStorage        storage.Operations
 	tenant           string
+
+	trialPlatformRegionMapping map[string]string
 }
 
 func getHyperscalerType(pp internal.ProvisioningParameters) (hyperscaler.Type, error) {
@@ -50,12 +53,12 @@ func forTrialProvider(provider *internal.TrialCloudProvider) (hyperscaler.Type,
 
 }
 
-func NewResolveCredentialsStep(os storage.Operations, accountProvider hyperscaler.AccountProvider) *ResolveCredentialsStep {
+func NewResolveCredentialsStep(os storage.Operations, accountProvider hyperscaler.AccountProvider, trialPlatformRegionMapping map[string]string) *ResolveCredentialsStep {
 
 	return &ResolveCredentialsStep{
-		operationManager: process.NewProvisionOperationManager(os),
-		opStorage:        os,
-		accountProvider:  accountProvider,
+		operationManager:           process.NewProvisionOperationManager(os),
+		accountProvider:            accountProvider,
+		trialPlatformRegionMapping: trialPlatformRegionMapping,
 	}
 }
 
@@ -87,12 +90,25 @@ func (s *ResolveCredentialsStep) Run(operation internal.ProvisioningOperation, l
 	if !broker.IsTrialPlan(pp.PlanID) {
 		credentials, err = s.accountProvider.GardenerCredentials(hypType, pp.ErsContext.GlobalAccountID)
 	} else {
-		logger.Infof("HAP lookup for shared credentials")
-		credentials, err = s.accountProvider.GardenerSharedCredentials(hypType)
+		region := provider.TrialRegion(pp, s.trialPlatformRegionMapping)
+		logger.Infof("HAP lookup for shared credentials in region %s", region)
+		credentials, err = s.accountProvider.GardenerSharedCredentials(hypType, region)
+		// the provision endpoint rejects requests when the pool is at capacity,
+		// this handles the pool which filled up after the request was accepted
+		if hyperscaler.IsSharedPoolAtCapacity(err) {
+			errMsg := fmt.Sprintf("all shared hyperscaler accounts for %s in region %s reached the limit of clusters, choose a different region or try again later", hypType, region)
+			logger.Errorf("Aborting after failing to resolve shared credentials: %s", err)
+			return s.operationManager.OperationFailed(operation, errMsg)
+		}
 	}
 	if err != nil {
 		errMsg := fmt.Sprintf("HAP lookup for credentials to provision cluster for global account ID %s on Hyperscaler %s has failed: %s", pp.ErsContext.GlobalAccountID, hypType, err)
-		logger.Info(errMsg)
+		if hyperscaler.IsPoolExhausted(err) {
+			errMsg = fmt.Sprintf("the hyperscaler account pool for %s has no free credentials left, new secrets must be added to the pool to provision cluster for global account ID %s", hypType, pp.ErsContext.GlobalAccountID)
+			logger.Error(errMsg)
+		} else {
+			logger.Info(errMsg)
+		}
 
 		// if failed retry step every 10s by next 10min
 		dur := time.Since(operation.UpdatedAt).Round(time.Minute)
@@ -112,12 +128,96 @@ func (s *ResolveCredentialsStep) Run(operation internal.ProvisioningOperation, l
 		return s.operationManager.OperationFailed(operation, err.Error())
 	}
 
-	updatedOperation, err := s.opStorage.UpdateProvisioningOperation(operation)
-	if err != nil {
-		return operation, 1 * time.Minute, nil
+	updatedOperation, repeat := s.operationManager.UpdateOperation(operation, func(op *internal.ProvisioningOperation) {
+		op.ProvisioningParameters = operation.ProvisioningParameters
+	})
+	if repeat != 0 {
+		return updatedOperation, repeat, nil
 	}
 
 	logger.Infof("Resolved %s as target secret name to use for cluster provisioning for global account ID %s on Hyperscaler %s", *pp.Parameters.TargetSecret, pp.ErsContext.GlobalAccountID, hypType)
 
-	return *updatedOperation, 0, nil
+	return updatedOperation, 0, nil
+}
+
+// sharedCapacityCacheTTL defines how long the result of a capacity check is reused, the check lists all Gardener Shoots
+// so it must not be executed on every provisioning request
+const sharedCapacityCacheTTL = time.Minute
+
+// SharedCapacityChecker checks if the shared hyperscaler accounts used by trial Runtimes can take one more cluster
+type SharedCapacityChecker struct {
+	accountProvider            hyperscaler.AccountProvider
+	trialPlatformRegionMapping map[string]string
+
+	mu       sync.Mutex
+	cache    map[string]sharedCapacity
+	cacheTTL time.Duration
+	now      func() time.Time
+}
+
+type sharedCapacity struct {
+	hasCapacity bool
+	checkedAt   time.Time
+}
+
+func NewSharedCapacityChecker(accountProvider hyperscaler.AccountProvider, trialPlatformRegionMapping map[string]string) *SharedCapacityChecker {
+	return &SharedCapacityChecker{
+		accountProvider:            accountProvider,
+		trialPlatformRegionMapping: trialPlatformRegionMapping,
+		cache:                      make(map[string]sharedCapacity),
+		cacheTTL:                   sharedCapacityCacheTTL,
+		now:                        time.Now,
+	}
+}
+
+// HasCapacity returns false when all shared accounts in the trial region reached the limit of clusters,
+// Runtimes which do not use the shared accounts always have capacity. The result is cached per hyperscaler and region,
+// a stale positive result is caught by the ResolveCredentialsStep which fails the operation.
+func (c *SharedCapacityChecker) HasCapacity(pp internal.ProvisioningParameters) (bool, error) {
+	if !broker.IsTrialPlan(pp.PlanID) || pp.Parameters.TargetSecret != nil {
+		return true, nil
+	}
+
+	hypType, err := getHyperscalerType(pp)
+	if err != nil {
+		return false, err
+	}
+	region := provider.TrialRegion(pp, c.trialPlatformRegionMapping)
+	key := fmt.Sprintf("%s/%s", hypType, region)
+
+	if hasCapacity, found := c.cached(key); found {
+		return hasCapacity, nil
+	}
+
+	// the lock is not held while the Gardener Shoots are listed, so concurrent requests are not blocked by a slow call,
+	// requests which miss the cache at the same time check the capacity each on their own
+	_, err = c.accountProvider.GardenerSharedCredentials(hypType, region)
+	switch {
+	case hyperscaler.IsSharedPoolAtCapacity(err):
+		c.store(key, false)
+		return false, nil
+	case err != nil:
+		return false, errors.Wrap(err, "while checking capacity of shared hyperscaler accounts")
+	}
+
+	c.store(key, true)
+	return true, nil
+}
+
+func (c *SharedCapacityChecker) cached(key string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, found := c.cache[key]
+	if !found || c.now().Sub(cached.checkedAt) >= c.cacheTTL {
+		return false, false
+	}
+	return cached.hasCapacity, true
+}
+
+func (c *SharedCapacityChecker) store(key string, hasCapacity bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache[key] = sharedCapacity{hasCapacity: hasCapacity, checkedAt: c.now()}
 }
@@ -14,8 +14,10 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler"
 	hyperscalerMocks "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/hyperscaler/automock"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestResolveCredentialsStepHappyPath_Run(t *testing.T) {
@@ -39,7 +41,7 @@ func TestResolveCredentialsStepHappyPath_Run(t *testing.T) {
 		CredentialData:  map[string][]byte{},
 	}, nil)
 
-	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock)
+	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock, nil)
 
 	// when
 	operation, repeat, err := step.Run(operation, log)
@@ -71,13 +73,13 @@ func TestResolveCredentialsStepHappyPathTrialDefaultProvider_Run(t *testing.T) {
 
 	accountProviderMock := &hyperscalerMocks.AccountProvider{}
 
-	accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure).Return(hyperscaler.Credentials{
+	accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "westeurope").Return(hyperscaler.Credentials{
 		Name:            "gardener-secret-azure",
 		HyperscalerType: "azure",
 		CredentialData:  map[string][]byte{},
 	}, nil)
 
-	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock)
+	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock, nil)
 
 	// when
 	operation, repeat, err := step.Run(operation, log)
@@ -110,13 +112,13 @@ func TestResolveCredentialsStepHappyPathTrialGivenProvider_Run(t *testing.T) {
 
 	accountProviderMock := &hyperscalerMocks.AccountProvider{}
 
-	accountProviderMock.On("GardenerSharedCredentials", hyperscaler.GCP).Return(hyperscaler.Credentials{
+	accountProviderMock.On("GardenerSharedCredentials", hyperscaler.GCP, "europe-west4").Return(hyperscaler.Credentials{
 		Name:            "gardener-secret-gcp",
 		HyperscalerType: "gcp",
 		CredentialData:  map[string][]byte{},
 	}, nil)
 
-	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock)
+	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock, nil)
 
 	// when
 	operation, repeat, err := step.Run(operation, log)
@@ -150,7 +152,7 @@ func TestResolveCredentialsStepRetry_Run(t *testing.T) {
 
 	accountProviderMock.On("GardenerCredentials", hyperscaler.GCP, statusGlobalAccountID).Return(hyperscaler.Credentials{}, errors.New("Failed!"))
 
-	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock)
+	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock, nil)
 
 	operation.UpdatedAt = time.Now()
 
@@ -179,3 +181,170 @@ func TestResolveCredentialsStepRetry_Run(t *testing.T) {
 	assert.Empty(t, operation.State)
 	assert.Nil(t, pp.Parameters.TargetSecret)
 }
+
+func TestResolveCredentialsStepPoolExhausted_Run(t *testing.T) {
+	// given
+	log := logrus.New()
+	memoryStorage := storage.NewMemoryStorage()
+
+	operation := fixOperationRuntimeStatus(t, broker.GCPPlanID)
+	err := memoryStorage.Operations().InsertProvisioningOperation(operation)
+	assert.NoError(t, err)
+
+	accountProviderMock := &hyperscalerMocks.AccountProvider{}
+	accountProviderMock.On("GardenerCredentials", hyperscaler.GCP, statusGlobalAccountID).Return(hyperscaler.Credentials{}, hyperscaler.PoolExhaustedError{HyperscalerType: hyperscaler.GCP})
+
+	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock, nil)
+
+	operation.UpdatedAt = time.Now().Add(-11 * time.Minute)
+
+	// when
+	operation, repeat, err := step.Run(operation, log)
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, time.Duration(0), repeat)
+	assert.Contains(t, operation.Description, "the hyperscaler account pool for gcp has no free credentials left")
+}
+
+func TestResolveCredentialsStepSharedPoolAtCapacity_Run(t *testing.T) {
+	// given
+	log := logrus.New()
+	memoryStorage := storage.NewMemoryStorage()
+
+	operation := fixOperationRuntimeStatus(t, broker.TrialPlanID)
+	err := memoryStorage.Operations().InsertProvisioningOperation(operation)
+	assert.NoError(t, err)
+
+	accountProviderMock := &hyperscalerMocks.AccountProvider{}
+	accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "westeurope").Return(hyperscaler.Credentials{},
+		hyperscaler.SharedPoolCapacityError{HyperscalerType: hyperscaler.Azure, Region: "westeurope", MaxShootsPerSecret: 10})
+
+	step := NewResolveCredentialsStep(memoryStorage.Operations(), accountProviderMock, nil)
+
+	// when
+	operation, repeat, err := step.Run(operation, log)
+
+	// then
+	require.Error(t, err)
+	assert.Equal(t, time.Duration(0), repeat)
+	assert.Equal(t, domain.Failed, operation.State)
+	assert.Contains(t, operation.Description, "all shared hyperscaler accounts for azure in region westeurope reached the limit of clusters")
+}
+
+func TestSharedCapacityChecker_HasCapacity(t *testing.T) {
+	t.Run("should report no capacity when shared accounts reached the limit", func(t *testing.T) {
+		// given
+		pp := internal.ProvisioningParameters{PlanID: broker.TrialPlanID, PlatformRegion: "cf-asia"}
+		accountProviderMock := &hyperscalerMocks.AccountProvider{}
+		accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "southeastasia").Return(hyperscaler.Credentials{},
+			hyperscaler.SharedPoolCapacityError{HyperscalerType: hyperscaler.Azure, Region: "southeastasia", MaxShootsPerSecret: 10})
+
+		// when
+		hasCapacity, err := NewSharedCapacityChecker(accountProviderMock, map[string]string{"cf-asia": "asia"}).HasCapacity(pp)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, hasCapacity)
+	})
+
+	t.Run("should report capacity when a shared account is available", func(t *testing.T) {
+		// given
+		pp := internal.ProvisioningParameters{PlanID: broker.TrialPlanID}
+		accountProviderMock := &hyperscalerMocks.AccountProvider{}
+		accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "westeurope").Return(hyperscaler.Credentials{Name: "shared"}, nil)
+
+		// when
+		hasCapacity, err := NewSharedCapacityChecker(accountProviderMock, nil).HasCapacity(pp)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, hasCapacity)
+	})
+
+	t.Run("should return error when shared accounts cannot be checked", func(t *testing.T) {
+		// given
+		pp := internal.ProvisioningParameters{PlanID: broker.TrialPlanID}
+		accountProviderMock := &hyperscalerMocks.AccountProvider{}
+		accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "westeurope").Return(hyperscaler.Credentials{}, errors.New("connection refused"))
+
+		// when
+		_, err := NewSharedCapacityChecker(accountProviderMock, nil).HasCapacity(pp)
+
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should reuse the result of the last check until it expires", func(t *testing.T) {
+		// given
+		pp := internal.ProvisioningParameters{PlanID: broker.TrialPlanID}
+		accountProviderMock := &hyperscalerMocks.AccountProvider{}
+		accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "westeurope").Return(hyperscaler.Credentials{},
+			hyperscaler.SharedPoolCapacityError{HyperscalerType: hyperscaler.Azure, Region: "westeurope", MaxShootsPerSecret: 10}).Once()
+		accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "westeurope").Return(hyperscaler.Credentials{Name: "shared"}, nil).Once()
+		defer accountProviderMock.AssertExpectations(t)
+
+		now := time.Now()
+		checker := NewSharedCapacityChecker(accountProviderMock, nil)
+		checker.now = func() time.Time { return now }
+
+		// when
+		first, err := checker.HasCapacity(pp)
+		require.NoError(t, err)
+		second, err := checker.HasCapacity(pp)
+		require.NoError(t, err)
+
+		now = now.Add(sharedCapacityCacheTTL)
+		afterExpiry, err := checker.HasCapacity(pp)
+		require.NoError(t, err)
+
+		// then
+		assert.False(t, first)
+		assert.False(t, second)
+		assert.True(t, afterExpiry)
+	})
+
+	t.Run("should not block checks in other regions while shared accounts are listed", func(t *testing.T) {
+		// given
+		listing := make(chan struct{})
+		release := make(chan struct{})
+		accountProviderMock := &hyperscalerMocks.AccountProvider{}
+		accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "westeurope").Return(hyperscaler.Credentials{Name: "shared"}, nil).
+			Run(func(mock.Arguments) {
+				close(listing)
+				<-release
+			}).Once()
+		accountProviderMock.On("GardenerSharedCredentials", hyperscaler.Azure, "southeastasia").Return(hyperscaler.Credentials{Name: "shared"}, nil).Once()
+		defer accountProviderMock.AssertExpectations(t)
+
+		checker := NewSharedCapacityChecker(accountProviderMock, map[string]string{"cf-asia": "asia"})
+		slowCheck := make(chan error)
+		go func() {
+			_, err := checker.HasCapacity(internal.ProvisioningParameters{PlanID: broker.TrialPlanID})
+			slowCheck <- err
+		}()
+		<-listing
+
+		// when
+		hasCapacity, err := checker.HasCapacity(internal.ProvisioningParameters{PlanID: broker.TrialPlanID, PlatformRegion: "cf-asia"})
+		close(release)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, hasCapacity)
+		require.NoError(t, <-slowCheck)
+	})
+
+	t.Run("should not check shared accounts for plans which do not use them", func(t *testing.T) {
+		// given
+		accountProviderMock := &hyperscalerMocks.AccountProvider{}
+		defer accountProviderMock.AssertExpectations(t)
+
+		// when
+		hasCapacity, err := NewSharedCapacityChecker(accountProviderMock, nil).HasCapacity(internal.ProvisioningParameters{PlanID: broker.GCPPlanID})
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, hasCapacity)
+	})
+}
@@ -61,12 +61,11 @@ func (s *ServiceManagerOfferingStep) Run(operation internal.ProvisioningOperatio
 		return s.operationManager.OperationFailed(operation,
 			fmt.Sprintf("expected one %s Service Manager offering, but found %d", s.offeringName, len(offerings.ServiceOfferings)))
 	}
-	info.ServiceID = offerings.ServiceOfferings[0].CatalogID
-	info.BrokerID = offerings.ServiceOfferings[0].BrokerID
-	log.Infof("Found offering: catalogID=%s brokerID=%s", info.ServiceID, info.BrokerID)
+	offering := offerings.ServiceOfferings[0]
+	log.Infof("Found offering: catalogID=%s brokerID=%s", offering.CatalogID, offering.BrokerID)
 
 	// try to find the plan
-	plans, err := smCli.ListPlansByName(s.planName, offerings.ServiceOfferings[0].ID)
+	plans, err := smCli.ListPlansByName(s.planName, offering.ID)
 	if err != nil {
 		return s.handleError(operation, err, "unable to get Service Manager plan", log)
 	}
@@ -74,10 +73,15 @@ func (s *ServiceManagerOfferingStep) Run(operation internal.ProvisioningOperatio
 		return s.operationManager.OperationFailed(operation,
 			fmt.Sprintf("expected one %s Service Manager plan, but found %d", s.offeringName, len(offerings.ServiceOfferings)))
 	}
-	info.PlanID = plans.ServicePlans[0].CatalogID
-	log.Infof("Found plan: catalogID=%s", info.PlanID)
+	planID := plans.ServicePlans[0].CatalogID
+	log.Infof("Found plan: catalogID=%s", planID)
 
-	op, retry := s.operationManager.UpdateOperation(operation)
+	op, retry := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		info := s.extractor(operation)
+		info.ServiceID = offering.CatalogID
+		info.BrokerID = offering.BrokerID
+		info.PlanID = planID
+	})
 	if retry > 0 {
 		log.Errorf("unable to update the operation")
 		return op, retry, nil
@@ -54,8 +54,10 @@ func (s *XSUAABindingStep) Run(operation internal.ProvisioningOperation, log log
 
 	// execute binding
 	if operation.XSUAA.BindingID == "" {
-		operation.XSUAA.BindingID = uuid.New().String()
-		operation, retry := s.operationManager.UpdateOperation(operation)
+		bindingID := uuid.New().String()
+		operation, retry := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+			operation.XSUAA.BindingID = bindingID
+		})
 		if retry > 0 {
 			log.Errorf("unable to update operation")
 			return operation, time.Second, nil
@@ -45,21 +45,26 @@ func (s *XSUAAProvisioningStep) Run(operation internal.ProvisioningOperation, lo
 		// then KEB is restarted to a newer version
 		return s.operationManager.OperationFailed(operation, "The `ShootDomain` must be set in the operation, but it is empty")
 	}
-	if operation.XSUAA.Instance.InstanceID == "" {
-		operation.XSUAA.Instance.InstanceID = uuid.New().String()
+	instanceID := operation.XSUAA.Instance.InstanceID
+	if instanceID == "" {
+		instanceID = uuid.New().String()
 	}
-	if operation.XSUAA.XSAppname == "" {
-		operation.XSUAA.XSAppname = uaa.XSAppname(operation.ShootDomain)
+	xsAppname := operation.XSUAA.XSAppname
+	if xsAppname == "" {
+		xsAppname = uaa.XSAppname(operation.ShootDomain)
 	}
 	log.Infof("Trying to provision: brokerID=%s, serviceID=%s, planID=%s, instanceID=%s",
-		instanceInfo.BrokerID, instanceInfo.ServiceID, instanceInfo.PlanID, operation.XSUAA.Instance.InstanceID)
+		instanceInfo.BrokerID, instanceInfo.ServiceID, instanceInfo.PlanID, instanceID)
 
-	parameters := s.parametersFactory.Generate(operation.ShootName, operation.ShootDomain, operation.XSUAA.XSAppname)
+	parameters := s.parametersFactory.Generate(operation.ShootName, operation.ShootDomain, xsAppname)
 
 	// first try to save the instance ID then perform provisioning to be sure we do not loose provisioned Id
 	// We can always deprovision not existing instance and get http 410 which is handled correctly by the client
 	// more: https://github.com/openservicebrokerapi/servicebroker/blob/master/spec.md#deprovisioning
-	operation, retry := s.operationManager.UpdateOperation(operation)
+	operation, retry := s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		operation.XSUAA.Instance.InstanceID = instanceID
+		operation.XSUAA.XSAppname = xsAppname
+	})
 	if retry > 0 {
 		return operation, time.Second, nil
 	}
@@ -81,11 +86,12 @@ func (s *XSUAAProvisioningStep) Run(operation internal.ProvisioningOperation, lo
 	if err != nil {
 		return s.handleError(operation, err, "unable to provision XSUAA instance", log)
 	}
-	operation.XSUAA.Instance.ProvisioningTriggered = true
-	if resp.IsDone() {
-		operation.XSUAA.Instance.Provisioned = true
-	}
-	operation, retry = s.operationManager.UpdateOperation(operation)
+	operation, retry = s.operationManager.UpdateOperation(operation, func(operation *internal.ProvisioningOperation) {
+		operation.XSUAA.Instance.ProvisioningTriggered = true
+		if resp.IsDone() {
+			operation.XSUAA.Instance.Provisioned = true
+		}
+	})
 	if retry > 0 {
 		return operation, time.Second, nil
 	}
@@ -0,0 +1,113 @@
+package process
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/google/uuid"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+)
+
+// CheckNoOperationInProgress returns an error when the Runtime provisioning did not succeed, the Runtime deprovisioning
+// was triggered or one of the Kyma upgrades of the Runtime is pending or in progress
+func CheckNoOperationInProgress(operations storage.Operations, instanceID string, pOpr *internal.ProvisioningOperation) error {
+	if pOpr.State != domain.Succeeded {
+		return errors.Errorf("runtime provisioning is in state %q", pOpr.State)
+	}
+
+	dOpr, err := operations.GetDeprovisioningOperationByInstanceID(instanceID)
+	switch {
+	case err == nil:
+		return errors.Errorf("runtime deprovisioning was triggered, operation %s is in state %q", dOpr.ID, dOpr.State)
+	case !dberr.IsNotFound(err):
+		return errors.Wrap(err, "while fetching deprovisioning operation for instance")
+	}
+
+	ukOprs, err := operations.ListUpgradeKymaOperationsByInstanceID(instanceID)
+	if err != nil && !dberr.IsNotFound(err) {
+		return errors.Wrap(err, "while fetching upgrade kyma operations for instance")
+	}
+	for _, op := range ukOprs {
+		if op.InstanceID != instanceID || op.DryRun {
+			continue
+		}
+		if op.State == orchestration.Pending || op.State == orchestration.InProgress {
+			return errors.Errorf("upgrade kyma operation %s is in state %q", op.Operation.ID, op.State)
+		}
+	}
+
+	return nil
+}
+
+// NewSameVersionUpgradeKymaOperation creates the upgrade Kyma operation which uses the Kyma version currently installed
+// on the Runtime, so the changed configuration is applied without upgrading Kyma.
+// The provisioning parameters are taken from the provisioning operation.
+// The operation is created in progress, so it is resumed when KEB is restarted before the operation is processed.
+func NewSameVersionUpgradeKymaOperation(operations storage.Operations, instance internal.Instance, pOpr internal.ProvisioningOperation, description string) (internal.UpgradeKymaOperation, error) {
+	pp, err := pOpr.GetProvisioningParameters()
+	if err != nil {
+		return internal.UpgradeKymaOperation{}, err
+	}
+	version, err := CurrentRuntimeVersion(operations, instance.InstanceID, pOpr)
+	if err != nil {
+		return internal.UpgradeKymaOperation{}, err
+	}
+
+	id := uuid.New().String()
+	op := internal.UpgradeKymaOperation{
+		Operation: internal.Operation{
+			ID:          id,
+			Version:     0,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+			InstanceID:  instance.InstanceID,
+			State:       orchestration.InProgress,
+			Description: description,
+		},
+		RuntimeOperation: orchestration.RuntimeOperation{
+			ID: id,
+			Runtime: orchestration.Runtime{
+				InstanceID:      instance.InstanceID,
+				RuntimeID:       instance.RuntimeID,
+				GlobalAccountID: instance.GlobalAccountID,
+				SubAccountID:    instance.SubAccountID,
+				ShootName:       pOpr.ShootName,
+			},
+		},
+		PlanID:         pp.PlanID,
+		RuntimeVersion: version,
+	}
+	if err := op.SetProvisioningParameters(pp); err != nil {
+		return internal.UpgradeKymaOperation{}, err
+	}
+
+	return op, nil
+}
+
+// CurrentRuntimeVersion returns the Kyma version from the last succeeded upgrade Kyma operation,
+// or from the provisioning operation if the Runtime was never upgraded
+func CurrentRuntimeVersion(operations storage.Operations, instanceID string, pOpr internal.ProvisioningOperation) (internal.RuntimeVersionData, error) {
+	version := pOpr.RuntimeVersion
+	lastUpgrade := time.Time{}
+
+	ukOprs, err := operations.ListUpgradeKymaOperationsByInstanceID(instanceID)
+	if err != nil && !dberr.IsNotFound(err) {
+		return version, errors.Wrap(err, "while fetching upgrade kyma operations for instance")
+	}
+	for _, op := range ukOprs {
+		if op.InstanceID != instanceID || op.DryRun || op.State != domain.Succeeded || op.RuntimeVersion.IsEmpty() {
+			continue
+		}
+		if op.CreatedAt.After(lastUpgrade) {
+			lastUpgrade = op.CreatedAt
+			version = op.RuntimeVersion
+		}
+	}
+
+	return version, nil
+}
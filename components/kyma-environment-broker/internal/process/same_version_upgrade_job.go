@@ -0,0 +1,203 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type UpgradeKymaQueue interface {
+	Add(operationID string)
+}
+
+// SameVersionUpgrade decides which Runtimes are upgraded by the SameVersionUpgradeJob
+type SameVersionUpgrade interface {
+	// Name describes the reason of the upgrade in logs and in the description of the created operations
+	Name() string
+	// Prepare is called at the beginning of every run
+	Prepare() error
+	// UpgradeDue is called for every instance which was provisioned successfully
+	UpgradeDue(instance internal.Instance, pOpr *internal.ProvisioningOperation, log logrus.FieldLogger) (bool, error)
+	// MarkOperation marks the created operation, so the upgrade steps know what to push into the Runtime
+	MarkOperation(operation *internal.UpgradeKymaOperation)
+}
+
+type SameVersionUpgradeJobConfig struct {
+	Interval  time.Duration
+	BatchSize int
+	// MaxUpgradesPerRun limits the number of operations created in one run, zero means no limit
+	MaxUpgradesPerRun int
+}
+
+// SameVersionUpgradeJob periodically checks all instances and creates the upgrade Kyma operation with the currently
+// installed Kyma version for the instances which need the upgrade
+type SameVersionUpgradeJob struct {
+	instances  storage.Instances
+	operations storage.Operations
+	queue      UpgradeKymaQueue
+	upgrade    SameVersionUpgrade
+	cfg        SameVersionUpgradeJobConfig
+	log        logrus.FieldLogger
+
+	triggered int
+}
+
+func NewSameVersionUpgradeJob(instances storage.Instances, operations storage.Operations, queue UpgradeKymaQueue,
+	upgrade SameVersionUpgrade, cfg SameVersionUpgradeJobConfig, log logrus.FieldLogger) *SameVersionUpgradeJob {
+	return &SameVersionUpgradeJob{
+		instances:  instances,
+		operations: operations,
+		queue:      queue,
+		upgrade:    upgrade,
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// Run processes all instances periodically until the stop channel is closed
+func (j *SameVersionUpgradeJob) Run(stop <-chan struct{}) {
+	wait.Until(j.UpgradeAll, j.cfg.Interval, stop)
+}
+
+// UpgradeAll processes all instances page by page. A failure of one instance does not stop processing of the others,
+// the instance is processed again in the next run.
+func (j *SameVersionUpgradeJob) UpgradeAll() {
+	if err := j.upgrade.Prepare(); err != nil {
+		j.log.Errorf("while preparing %s: %s", j.upgrade.Name(), err)
+		return
+	}
+	j.triggered = 0
+
+	for page, processed := 1, 0; ; page++ {
+		instances, count, totalCount, err := j.instances.List(dbmodel.InstanceFilter{Page: page, PageSize: j.cfg.BatchSize})
+		if err != nil {
+			j.log.Errorf("while listing instances: %s", err)
+			return
+		}
+		for _, instance := range instances {
+			log := j.log.WithField("instanceID", instance.InstanceID)
+			if err := j.process(instance, log); err != nil {
+				log.Errorf("while processing %s: %s", j.upgrade.Name(), err)
+			}
+		}
+		processed += count
+		if count == 0 || processed >= totalCount {
+			return
+		}
+	}
+}
+
+func (j *SameVersionUpgradeJob) process(instance internal.Instance, log logrus.FieldLogger) error {
+	pOpr, err := j.operations.GetProvisioningOperationByInstanceID(instance.InstanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "while fetching provisioning operation for instance")
+	case pOpr.State != domain.Succeeded:
+		return nil
+	}
+
+	due, err := j.upgrade.UpgradeDue(instance, pOpr, log)
+	if err != nil || !due {
+		return err
+	}
+	if j.cfg.MaxUpgradesPerRun > 0 && j.triggered >= j.cfg.MaxUpgradesPerRun {
+		log.Infof("postponing %s, %d upgrades were already triggered in this run", j.upgrade.Name(), j.triggered)
+		return nil
+	}
+	return j.trigger(instance, pOpr, log)
+}
+
+func (j *SameVersionUpgradeJob) trigger(instance internal.Instance, pOpr *internal.ProvisioningOperation, log logrus.FieldLogger) error {
+	if err := CheckNoOperationInProgress(j.operations, instance.InstanceID, pOpr); err != nil {
+		log.Infof("postponing %s: %s", j.upgrade.Name(), err)
+		return nil
+	}
+
+	operation, err := NewSameVersionUpgradeKymaOperation(j.operations, instance, *pOpr, fmt.Sprintf("Operation created: %s", j.upgrade.Name()))
+	if err != nil {
+		return errors.Wrap(err, "while creating upgrade kyma operation")
+	}
+	j.upgrade.MarkOperation(&operation)
+	err = j.operations.InsertUpgradeKymaOperation(operation)
+	switch {
+	case dberr.IsConflict(err):
+		log.Infof("postponing %s: %s", j.upgrade.Name(), err)
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "while inserting upgrade kyma operation")
+	}
+	j.triggered++
+	j.queue.Add(operation.Operation.ID)
+	log.Infof("%s triggered, upgrade kyma operation %s created", j.upgrade.Name(), operation.Operation.ID)
+
+	return nil
+}
+
+// LastSameVersionUpgrade returns the last upgrade Kyma operation of the instance selected by the given function,
+// dry run operations are skipped
+func LastSameVersionUpgrade(operations storage.Operations, instanceID string, selected func(internal.UpgradeKymaOperation) bool) (*internal.UpgradeKymaOperation, error) {
+	ukOprs, err := operations.ListUpgradeKymaOperationsByInstanceID(instanceID)
+	if err != nil && !dberr.IsNotFound(err) {
+		return nil, errors.Wrap(err, "while fetching upgrade kyma operations for instance")
+	}
+	var last *internal.UpgradeKymaOperation
+	for i := range ukOprs {
+		op := &ukOprs[i]
+		if op.DryRun || !selected(*op) {
+			continue
+		}
+		if last == nil || op.CreatedAt.After(last.CreatedAt) {
+			last = op
+		}
+	}
+	return last, nil
+}
+
+// FailedSameVersionUpgrades returns the number of the last upgrade Kyma operations of the instance selected by the given
+// function which failed in a row, dry run operations are skipped
+func FailedSameVersionUpgrades(operations storage.Operations, instanceID string, selected func(internal.UpgradeKymaOperation) bool) (int, error) {
+	ukOprs, err := operations.ListUpgradeKymaOperationsByInstanceID(instanceID)
+	if err != nil && !dberr.IsNotFound(err) {
+		return 0, errors.Wrap(err, "while fetching upgrade kyma operations for instance")
+	}
+	var ops []internal.UpgradeKymaOperation
+	for _, op := range ukOprs {
+		if op.DryRun || !selected(op) {
+			continue
+		}
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].CreatedAt.After(ops[j].CreatedAt)
+	})
+
+	failures := 0
+	for _, op := range ops {
+		if op.State != domain.Failed {
+			break
+		}
+		failures++
+	}
+	return failures, nil
+}
+
+// SameVersionUpgradeRetryDelay returns how long to wait before the same version upgrade which failed the given number
+// of times in a row is retried, the delay doubles with every failure
+func SameVersionUpgradeRetryDelay(backoff time.Duration, failures int) time.Duration {
+	if failures < 1 {
+		return 0
+	}
+	return backoff * time.Duration(1<<uint(failures-1))
+}
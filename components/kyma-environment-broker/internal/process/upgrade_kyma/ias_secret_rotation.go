@@ -0,0 +1,111 @@
+package upgrade_kyma
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IASSecretRotationStep generates new client secrets of the OIDC IAS ServiceProviders and passes them to the Runtime
+// as overrides. It runs only for the upgrade operations created to rotate the secrets.
+// The secrets are generated once per operation and the old secrets are kept, they are removed by the rotation job
+// after the operation succeeded. The secrets added by a failed operation are reused by the operation which retries it.
+type IASSecretRotationStep struct {
+	operationManager *process.UpgradeKymaOperationManager
+	bundleBuilder    ias.BundleBuilder
+}
+
+func NewIASSecretRotationStep(os storage.Operations, builder ias.BundleBuilder) *IASSecretRotationStep {
+	return &IASSecretRotationStep{
+		operationManager: process.NewUpgradeKymaOperationManager(os),
+		bundleBuilder:    builder,
+	}
+}
+
+func (s *IASSecretRotationStep) Name() string {
+	return "IAS_Secret_Rotation"
+}
+
+func (s *IASSecretRotationStep) Run(operation internal.UpgradeKymaOperation, log logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+	if !operation.RotateIASSecret || operation.DryRun {
+		return operation, 0, nil
+	}
+
+	for spID := range ias.ServiceProviderInputs {
+		spb, err := s.bundleBuilder.NewBundle(operation.InstanceID, spID)
+		if err != nil {
+			msg := "failed to create new ServiceProvider Bundle"
+			log.Errorf("%s: %s", msg, err)
+			return s.operationManager.OperationFailed(operation, msg)
+		}
+		if spb.ServiceProviderType() != ias.OIDC {
+			continue
+		}
+
+		secret, found := operation.IASSecrets[spb.ServiceProviderName()]
+		if !found {
+			err = spb.FetchServiceProviderData()
+			if err != nil {
+				return s.handleError(operation, err, log, "fetching IAS ServiceProvider data failed")
+			}
+			if !spb.ServiceProviderExist() {
+				return s.operationManager.OperationFailed(operation, fmt.Sprintf("IAS ServiceProvider %q does not exist", spb.ServiceProviderName()))
+			}
+
+			log.Infof("Rotate IAS ServiceProvider %q Secret", spb.ServiceProviderName())
+			generated, err := spb.AddSecret(ias.RotatedSecretDescription(operation.IASSecretsAddedBy()))
+			if err != nil {
+				return s.handleError(operation, err, log, "creating secret for IAS ServiceProvider failed")
+			}
+			secret = internal.IASClientSecret{ClientID: generated.ClientID, ClientSecret: generated.ClientSecret}
+
+			var repeat time.Duration
+			if operation, repeat = s.operationManager.UpdateOperation(operation, func(operation *internal.UpgradeKymaOperation) {
+				if operation.IASSecrets == nil {
+					operation.IASSecrets = make(map[string]internal.IASClientSecret)
+				}
+				operation.IASSecrets[spb.ServiceProviderName()] = secret
+			}); repeat != 0 {
+				log.Errorf("Unable to save the generated IAS ServiceProvider secret")
+				return operation, repeat, nil
+			}
+		}
+
+		switch spID {
+		case ias.SPGrafanaID:
+			operation.InputCreator.AppendOverrides("monitoring", []*gqlschema.ConfigEntryInput{
+				{
+					Key:    "grafana.env.GF_AUTH_GENERIC_OAUTH_CLIENT_ID",
+					Value:  secret.ClientID,
+					Secret: ptr.Bool(true),
+				},
+				{
+					Key:    "grafana.env.GF_AUTH_GENERIC_OAUTH_CLIENT_SECRET",
+					Value:  secret.ClientSecret,
+					Secret: ptr.Bool(true),
+				},
+			})
+		}
+	}
+
+	return operation, 0, nil
+}
+
+func (s *IASSecretRotationStep) handleError(operation internal.UpgradeKymaOperation, err error, log logrus.FieldLogger, msg string) (internal.UpgradeKymaOperation, time.Duration, error) {
+	log.Errorf("%s: %s", msg, err)
+	switch {
+	case kebError.IsTemporaryError(err):
+		return s.operationManager.RetryOperation(operation, msg, 10*time.Second, time.Minute*30, log)
+	default:
+		return s.operationManager.OperationFailed(operation, msg)
+	}
+}
@@ -0,0 +1,180 @@
+package upgrade_kyma
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias"
+	iasAutomock "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ias/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/upgrade_kyma/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	iasInstanceID   = "cebd62ee-a32d-4dad-ad19-89dd12b0730e"
+	iasClientID     = "1234id"
+	iasClientSecret = "4567secret"
+)
+
+func TestIASSecretRotationStep_Run(t *testing.T) {
+	t.Run("should rotate secrets and append overrides", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		bundleBuilder := &iasAutomock.BundleBuilder{}
+		defer bundleBuilder.AssertExpectations(t)
+		for inputID := range ias.ServiceProviderInputs {
+			bundle := &iasAutomock.Bundle{}
+			defer bundle.AssertExpectations(t)
+			bundle.On("ServiceProviderName").Return("MockServiceProvider")
+			switch inputID {
+			case ias.SPGrafanaID:
+				bundle.On("ServiceProviderType").Return(ias.OIDC)
+				bundle.On("FetchServiceProviderData").Return(nil).Once()
+				bundle.On("ServiceProviderExist").Return(true).Once()
+				bundle.On("AddSecret", ias.RotatedSecretDescription("operation-id")).Return(&ias.ServiceProviderSecret{
+					ClientID:     iasClientID,
+					ClientSecret: iasClientSecret,
+				}, nil).Once()
+			default:
+				bundle.On("ServiceProviderType").Return(ias.SAML)
+			}
+			bundleBuilder.On("NewBundle", iasInstanceID, inputID).Return(bundle, nil).Once()
+		}
+
+		inputCreatorMock := &automock.ProvisionerInputCreator{}
+		defer inputCreatorMock.AssertExpectations(t)
+		inputCreatorMock.On("AppendOverrides", "monitoring", fixGrafanaOverrides()).Return(nil).Once()
+
+		operation := fixIASRotationOperation(inputCreatorMock)
+		require.NoError(t, memoryStorage.Operations().InsertUpgradeKymaOperation(operation))
+		step := NewIASSecretRotationStep(memoryStorage.Operations(), bundleBuilder)
+
+		// when
+		_, repeat, err := step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), repeat)
+		stored, err := memoryStorage.Operations().GetUpgradeKymaOperationByID(operation.Operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]internal.IASClientSecret{
+			"MockServiceProvider": {ClientID: iasClientID, ClientSecret: iasClientSecret},
+		}, stored.IASSecrets)
+	})
+
+	t.Run("should reuse secrets generated by the operation", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		bundleBuilder := &iasAutomock.BundleBuilder{}
+		defer bundleBuilder.AssertExpectations(t)
+		for inputID := range ias.ServiceProviderInputs {
+			bundle := &iasAutomock.Bundle{}
+			defer bundle.AssertExpectations(t)
+			bundle.On("ServiceProviderName").Return("MockServiceProvider")
+			switch inputID {
+			case ias.SPGrafanaID:
+				bundle.On("ServiceProviderType").Return(ias.OIDC)
+			default:
+				bundle.On("ServiceProviderType").Return(ias.SAML)
+			}
+			bundleBuilder.On("NewBundle", iasInstanceID, inputID).Return(bundle, nil).Once()
+		}
+
+		inputCreatorMock := &automock.ProvisionerInputCreator{}
+		defer inputCreatorMock.AssertExpectations(t)
+		inputCreatorMock.On("AppendOverrides", "monitoring", fixGrafanaOverrides()).Return(nil).Once()
+
+		operation := fixIASRotationOperation(inputCreatorMock)
+		operation.IASSecrets = map[string]internal.IASClientSecret{
+			"MockServiceProvider": {ClientID: iasClientID, ClientSecret: iasClientSecret},
+		}
+		step := NewIASSecretRotationStep(memoryStorage.Operations(), bundleBuilder)
+
+		// when
+		_, repeat, err := step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), repeat)
+	})
+
+	t.Run("should skip operations which do not rotate secrets", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		bundleBuilder := &iasAutomock.BundleBuilder{}
+		defer bundleBuilder.AssertExpectations(t)
+
+		operation := fixIASRotationOperation(&automock.ProvisionerInputCreator{})
+		operation.RotateIASSecret = false
+		step := NewIASSecretRotationStep(memoryStorage.Operations(), bundleBuilder)
+
+		// when
+		_, repeat, err := step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), repeat)
+	})
+
+	t.Run("should fail when the ServiceProvider does not exist", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+
+		bundle := &iasAutomock.Bundle{}
+		bundle.On("ServiceProviderName").Return("MockServiceProvider")
+		bundle.On("ServiceProviderType").Return(ias.OIDC)
+		bundle.On("FetchServiceProviderData").Return(nil)
+		bundle.On("ServiceProviderExist").Return(false)
+		bundleBuilder := &iasAutomock.BundleBuilder{}
+		bundleBuilder.On("NewBundle", iasInstanceID, ias.SPInputID(ias.SPGrafanaID)).Return(bundle, nil)
+
+		operation := fixIASRotationOperation(&automock.ProvisionerInputCreator{})
+		require.NoError(t, memoryStorage.Operations().InsertUpgradeKymaOperation(operation))
+		step := NewIASSecretRotationStep(memoryStorage.Operations(), bundleBuilder)
+
+		// when
+		operation, _, err := step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, orchestration.Failed, string(operation.State))
+	})
+}
+
+func fixIASRotationOperation(inputCreator internal.ProvisionerInputCreator) internal.UpgradeKymaOperation {
+	return internal.UpgradeKymaOperation{
+		Operation: internal.Operation{
+			ID:         "operation-id",
+			InstanceID: iasInstanceID,
+			State:      orchestration.InProgress,
+			UpdatedAt:  time.Now(),
+		},
+		InputCreator:    inputCreator,
+		RotateIASSecret: true,
+	}
+}
+
+func fixGrafanaOverrides() []*gqlschema.ConfigEntryInput {
+	return []*gqlschema.ConfigEntryInput{
+		{
+			Key:    "grafana.env.GF_AUTH_GENERIC_OAUTH_CLIENT_ID",
+			Value:  iasClientID,
+			Secret: ptr.Bool(true),
+		},
+		{
+			Key:    "grafana.env.GF_AUTH_GENERIC_OAUTH_CLIENT_SECRET",
+			Value:  iasClientSecret,
+			Secret: ptr.Bool(true),
+		},
+	}
+}
@@ -29,6 +29,7 @@ const (
 type InitialisationStep struct {
 	operationManager       *process.UpgradeKymaOperationManager
 	operationStorage       storage.Operations
+	operationUpdater       *storage.OperationUpdater
 	instanceStorage        storage.Instances
 	provisionerClient      provisioner.Client
 	inputBuilder           input.CreatorForPlan
@@ -49,6 +50,7 @@ func NewInitialisationStep(os storage.Operations, is storage.Instances, pc provi
 	return &InitialisationStep{
 		operationManager:       process.NewUpgradeKymaOperationManager(os),
 		operationStorage:       os,
+		operationUpdater:       storage.NewOperationUpdater(os),
 		instanceStorage:        is,
 		provisionerClient:      pc,
 		inputBuilder:           b,
@@ -67,9 +69,11 @@ func (s *InitialisationStep) Run(operation internal.UpgradeKymaOperation, log lo
 		return s.operationManager.OperationCanceled(operation, fmt.Sprintf("orchestration %s was canceled", operation.OrchestrationID))
 	}
 	if operation.State == orchestrationExt.Pending {
-		operation.State = orchestrationExt.InProgress
-
-		op, err := s.operationStorage.UpdateUpgradeKymaOperation(operation)
+		op, err := s.operationUpdater.UpdateUpgradeKymaOperationWithRetry(operation, func(op *internal.UpgradeKymaOperation) {
+			if op.State == orchestrationExt.Pending {
+				op.State = orchestrationExt.InProgress
+			}
+		})
 		if err != nil {
 			log.Errorf("while updating operation: %v", err)
 			return operation, s.timeSchedule.Retry, nil
@@ -92,6 +96,9 @@ func (s *InitialisationStep) Run(operation internal.UpgradeKymaOperation, log lo
 	if err != nil {
 		return s.operationManager.OperationFailed(operation, "cannot get provisioning parameters from operation")
 	}
+	if operation.UpdateOptionalComponents {
+		parameters.Parameters.OptionalComponentsToInstall = operation.OptionalComponentsToInstall
+	}
 
 	err = operation.SetProvisioningParameters(parameters)
 	if err != nil {
@@ -99,7 +106,10 @@ func (s *InitialisationStep) Run(operation internal.UpgradeKymaOperation, log lo
 		return s.operationManager.OperationFailed(operation, err.Error())
 	}
 
-	operation, repeat := s.operationManager.UpdateOperation(operation)
+	provisioningParameters := operation.ProvisioningParameters
+	operation, repeat := s.operationManager.UpdateOperation(operation, func(operation *internal.UpgradeKymaOperation) {
+		operation.ProvisioningParameters = provisioningParameters
+	})
 	if repeat != 0 {
 		log.Errorf("cannot save the operation")
 		return operation, time.Second, nil
@@ -130,9 +140,10 @@ func (s *InitialisationStep) Run(operation internal.UpgradeKymaOperation, log lo
 }
 
 func (s *InitialisationStep) rescheduleAtNextMaintenanceWindow(operation internal.UpgradeKymaOperation, log logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
-	operation.MaintenanceWindowBegin = operation.MaintenanceWindowBegin.AddDate(0, 0, 1)
-	operation.MaintenanceWindowEnd = operation.MaintenanceWindowEnd.AddDate(0, 0, 1)
-	operation, repeat := s.operationManager.UpdateOperation(operation)
+	operation, repeat := s.operationManager.UpdateOperation(operation, func(operation *internal.UpgradeKymaOperation) {
+		operation.MaintenanceWindowBegin = operation.MaintenanceWindowBegin.AddDate(0, 0, 1)
+		operation.MaintenanceWindowEnd = operation.MaintenanceWindowEnd.AddDate(0, 0, 1)
+	})
 	if repeat != 0 {
 		log.Errorf("cannot save updated maintenance window to DB")
 		return operation, s.timeSchedule.Retry, nil
@@ -157,9 +168,9 @@ func (s *InitialisationStep) initializeUpgradeRuntimeRequest(operation internal.
 	creator, err := s.inputBuilder.CreateUpgradeInput(pp, operation.RuntimeVersion)
 	switch {
 	case err == nil:
-		operation.InputCreator = creator
-
-		operation, repeat := s.operationManager.UpdateOperation(operation)
+		operation, repeat := s.operationManager.UpdateOperation(operation, func(operation *internal.UpgradeKymaOperation) {
+			operation.InputCreator = creator
+		})
 		if repeat != 0 {
 			log.Errorf("cannot save the operation")
 			return operation, time.Second, nil
@@ -183,10 +194,10 @@ func (s *InitialisationStep) configureKymaVersion(operation *internal.UpgradeKym
 	if err != nil {
 		return errors.Wrap(err, "while getting runtime version for upgrade")
 	}
-	operation.RuntimeVersion = *version
-
 	var repeat time.Duration
-	if *operation, repeat = s.operationManager.UpdateOperation(*operation); repeat != 0 {
+	if *operation, repeat = s.operationManager.UpdateOperation(*operation, func(operation *internal.UpgradeKymaOperation) {
+		operation.RuntimeVersion = *version
+	}); repeat != 0 {
 		return errors.New("unable to update operation with RuntimeVersion property")
 	}
 
@@ -212,6 +223,12 @@ func (s *InitialisationStep) checkRuntimeStatus(operation internal.UpgradeKymaOp
 
 	switch status.State {
 	case gqlschema.OperationStateSucceeded:
+		if operation.UpdateOptionalComponents {
+			if err := s.storeOptionalComponents(operation, instance); err != nil {
+				log.Errorf("unable to store optional components installed by the operation: %s", err)
+				return operation, s.timeSchedule.Retry, nil
+			}
+		}
 		return s.operationManager.OperationSucceeded(operation, msg)
 	case gqlschema.OperationStateInProgress:
 		return operation, s.timeSchedule.StatusCheck, nil
@@ -223,3 +240,34 @@ func (s *InitialisationStep) checkRuntimeStatus(operation internal.UpgradeKymaOp
 
 	return s.operationManager.OperationFailed(operation, fmt.Sprintf("unsupported provisioner client status: %s", status.State.String()))
 }
+
+// storeOptionalComponents stores the optional components installed by the operation in the provisioning parameters
+// of the provisioning operation, which is the source of the parameters for the next upgrades, and of the instance
+func (s *InitialisationStep) storeOptionalComponents(operation internal.UpgradeKymaOperation, instance *internal.Instance) error {
+	pOpr, err := s.operationStorage.GetProvisioningOperationByInstanceID(operation.InstanceID)
+	if err != nil {
+		return errors.Wrap(err, "while getting provisioning operation")
+	}
+	var setErr error
+	pOpr, err = s.operationUpdater.UpdateProvisioningOperationWithRetry(*pOpr, func(op *internal.ProvisioningOperation) {
+		pp, err := op.GetProvisioningParameters()
+		if err != nil {
+			setErr = err
+			return
+		}
+		pp.Parameters.OptionalComponentsToInstall = operation.OptionalComponentsToInstall
+		setErr = op.SetProvisioningParameters(pp)
+	})
+	if setErr != nil {
+		return errors.Wrap(setErr, "while setting provisioning parameters")
+	}
+	if err != nil {
+		return errors.Wrap(err, "while updating provisioning operation")
+	}
+
+	instance.ProvisioningParameters = pOpr.ProvisioningParameters
+	if err := s.instanceStorage.Update(*instance); err != nil {
+		return errors.Wrap(err, "while updating instance")
+	}
+	return nil
+}
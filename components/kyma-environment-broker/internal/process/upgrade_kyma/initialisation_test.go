@@ -75,6 +75,61 @@ func TestInitialisationStep_Run(t *testing.T) {
 
 	})
 
+	t.Run("should store optional components only when the upgrade which changes them succeeded", func(t *testing.T) {
+		for state, expected := range map[gqlschema.OperationState][]string{
+			gqlschema.OperationStateSucceeded: {"kiali"},
+			gqlschema.OperationStateFailed:    nil,
+		} {
+			// given
+			log := logrus.New()
+			memoryStorage := storage.NewMemoryStorage()
+
+			provisioningOperation := fixProvisioningOperation(t)
+			err := memoryStorage.Operations().InsertProvisioningOperation(provisioningOperation)
+			require.NoError(t, err)
+
+			upgradeOperation := fixUpgradeKymaOperation(t)
+			upgradeOperation.OrchestrationID = ""
+			upgradeOperation.State = orchestration.InProgress
+			upgradeOperation.UpdateOptionalComponents = true
+			upgradeOperation.OptionalComponentsToInstall = []string{"kiali"}
+			err = memoryStorage.Operations().InsertUpgradeKymaOperation(upgradeOperation)
+			require.NoError(t, err)
+
+			instance := fixInstanceRuntimeStatus()
+			instance.ProvisioningParameters = provisioningOperation.ProvisioningParameters
+			err = memoryStorage.Instances().Insert(instance)
+			require.NoError(t, err)
+
+			provisionerClient := &provisionerAutomock.Client{}
+			provisionerClient.On("RuntimeOperationStatus", fixGlobalAccountID, fixProvisionerOperationID).Return(gqlschema.OperationStatus{
+				ID:        ptr.String(fixProvisionerOperationID),
+				State:     state,
+				RuntimeID: StringPtr(fixRuntimeID),
+			}, nil)
+
+			step := NewInitialisationStep(memoryStorage.Operations(), memoryStorage.Instances(), provisionerClient, nil, nil, nil)
+
+			// when
+			op, _, _ := step.Run(upgradeOperation, log)
+
+			// then
+			pp, err := op.GetProvisioningParameters()
+			require.NoError(t, err)
+			assert.Equal(t, []string{"kiali"}, pp.Parameters.OptionalComponentsToInstall)
+
+			pOpr, err := memoryStorage.Operations().GetProvisioningOperationByInstanceID(fixInstanceID)
+			require.NoError(t, err)
+			pp, err = pOpr.GetProvisioningParameters()
+			require.NoError(t, err)
+			assert.Equal(t, expected, pp.Parameters.OptionalComponentsToInstall, string(state))
+
+			storedInstance, err := memoryStorage.Instances().GetByID(fixInstanceID)
+			require.NoError(t, err)
+			assert.Equal(t, pOpr.ProvisioningParameters, storedInstance.ProvisioningParameters, string(state))
+		}
+	})
+
 	t.Run("should initialize UpgradeRuntimeInput request when run", func(t *testing.T) {
 		// given
 		log := logrus.New()
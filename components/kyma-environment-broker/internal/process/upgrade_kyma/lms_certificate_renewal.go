@@ -0,0 +1,159 @@
+package upgrade_kyma
+
+import (
+	"crypto/x509/pkix"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/lms"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	lmsCertPollingInterval = 15 * time.Second
+	lmsCertPollingTimeout  = 30 * time.Minute
+)
+
+type LmsCertificateClient interface {
+	RequestCertificate(tenantID string, subject pkix.Name) (id string, privateKey []byte, err error)
+	GetCertificateByURL(url string) (cert string, found bool, err error)
+	GetCACertificate(tenantID string) (cert string, found bool, err error)
+	GetTenantInfo(tenantID string) (status lms.TenantInfo, err error)
+}
+
+// LMSCertificateRenewalStep requests a new LMS client certificate and passes it to the Runtime as overrides.
+// It runs only for the upgrade operations created to renew the certificate. The requested certificate is stored
+// in the operation, the certificate record is updated by the renewal job after the operation succeeded.
+// The operation which retries a failed renewal gets the certificate issued by the failed one, so it is not requested again.
+type LMSCertificateRenewalStep struct {
+	operationManager *process.UpgradeKymaOperationManager
+	certificates     storage.LMSCertificates
+	client           LmsCertificateClient
+
+	pollingInterval time.Duration
+	pollingTimeout  time.Duration
+}
+
+func NewLMSCertificateRenewalStep(os storage.Operations, certificates storage.LMSCertificates, client LmsCertificateClient) *LMSCertificateRenewalStep {
+	return &LMSCertificateRenewalStep{
+		operationManager: process.NewUpgradeKymaOperationManager(os),
+		certificates:     certificates,
+		client:           client,
+		pollingInterval:  lmsCertPollingInterval,
+		pollingTimeout:   lmsCertPollingTimeout,
+	}
+}
+
+func (s *LMSCertificateRenewalStep) Name() string {
+	return "LMS_Certificate_Renewal"
+}
+
+func (s *LMSCertificateRenewalStep) Run(operation internal.UpgradeKymaOperation, log logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+	if !operation.RenewLMSCertificate || operation.DryRun {
+		return operation, 0, nil
+	}
+
+	certificate, err := s.certificates.Get(operation.InstanceID)
+	if err != nil {
+		return s.handleError(operation, kebError.AsTemporaryError(err, "while getting LMS certificate"), log, "fetching LMS certificate failed")
+	}
+	log = log.WithField("LMSTenant", certificate.TenantID)
+
+	pp, err := operation.GetProvisioningParameters()
+	if err != nil {
+		log.Errorf("Unable to get provisioning parameters: %s", err)
+		return s.operationManager.OperationFailed(operation, "invalid operation provisioning parameters")
+	}
+
+	tenantInfo, err := s.client.GetTenantInfo(certificate.TenantID)
+	if err != nil {
+		return s.handleError(operation, err, log, "getting LMS tenant info failed")
+	}
+
+	// the certificate is requested only once, the step is repeated until the certificate is signed
+	if operation.LMSCertificateRenewal.CertificateURL == "" {
+		subj := pkix.Name{
+			CommonName:         "fluentbit", // do not modify
+			Organization:       []string{pp.ErsContext.GlobalAccountID},
+			OrganizationalUnit: []string{uuid.New().String()},
+		}
+		certURL, pKey, err := s.client.RequestCertificate(certificate.TenantID, subj)
+		if err != nil {
+			return s.handleError(operation, err, log, "requesting LMS certificate failed")
+		}
+		renewal := internal.LMSCertificateRenewal{
+			CertificateURL: certURL,
+			PrivateKey:     string(pKey),
+			RequestedAt:    time.Now(),
+		}
+		var repeat time.Duration
+		if operation, repeat = s.operationManager.UpdateOperation(operation, func(operation *internal.UpgradeKymaOperation) {
+			operation.LMSCertificateRenewal = renewal
+		}); repeat != 0 {
+			log.Errorf("Unable to save the requested LMS certificate")
+			return operation, repeat, nil
+		}
+	}
+	renewal := operation.LMSCertificateRenewal
+
+	signedCert, found, err := s.client.GetCertificateByURL(renewal.CertificateURL)
+	if err != nil {
+		log.Warnf("Unable to get LMS Signed Certificate: %s, retrying", err)
+	}
+	if !found {
+		return s.waitForCertificate(operation, "getting LMS Signed Certificate timeout", log)
+	}
+	caCert, found, err := s.client.GetCACertificate(certificate.TenantID)
+	if err != nil {
+		log.Warnf("Unable to get LMS CA Certificate: %s, retrying", err)
+	}
+	if !found {
+		return s.waitForCertificate(operation, "getting LMS CA Certificate timeout", log)
+	}
+
+	if renewal.ExpiresAt.IsZero() {
+		expiresAt, err := lms.CertificateExpiry(signedCert)
+		if err != nil {
+			log.Warnf("Unable to read LMS Signed Certificate expiry: %s", err)
+		} else {
+			var repeat time.Duration
+			if operation, repeat = s.operationManager.UpdateOperation(operation, func(operation *internal.UpgradeKymaOperation) {
+				operation.LMSCertificateRenewal.ExpiresAt = expiresAt
+			}); repeat != 0 {
+				log.Errorf("Unable to save the LMS certificate expiry")
+				return operation, repeat, nil
+			}
+		}
+	}
+
+	operation.InputCreator.AppendOverrides("logging", lms.CertificateOverrides(tenantInfo.DNS, caCert, signedCert, []byte(renewal.PrivateKey)))
+	operation.InputCreator.AppendOverrides("logging", lms.RecordModifierOverrides(pp.ErsContext.SubAccountID))
+	log.Infof("LMS certificate requested at %s passed to the Runtime", renewal.RequestedAt)
+
+	return operation, 0, nil
+}
+
+// waitForCertificate repeats the step until the certificate is signed or the polling timeout is reached
+func (s *LMSCertificateRenewalStep) waitForCertificate(operation internal.UpgradeKymaOperation, timeoutMsg string, log logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+	if time.Since(operation.LMSCertificateRenewal.RequestedAt) > s.pollingTimeout {
+		log.Error(timeoutMsg)
+		return s.operationManager.OperationFailed(operation, timeoutMsg)
+	}
+	log.Infof("LMS certificate requested at %s is not ready yet, retrying", operation.LMSCertificateRenewal.RequestedAt)
+	return operation, s.pollingInterval, nil
+}
+
+func (s *LMSCertificateRenewalStep) handleError(operation internal.UpgradeKymaOperation, err error, log logrus.FieldLogger, msg string) (internal.UpgradeKymaOperation, time.Duration, error) {
+	log.Errorf("%s: %s", msg, err)
+	switch {
+	case kebError.IsTemporaryError(err):
+		return s.operationManager.RetryOperation(operation, msg, 10*time.Second, time.Minute*30, log)
+	default:
+		return s.operationManager.OperationFailed(operation, msg)
+	}
+}
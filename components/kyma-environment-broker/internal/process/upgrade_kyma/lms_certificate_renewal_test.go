@@ -0,0 +1,182 @@
+package upgrade_kyma
+
+import (
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/lms"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/logger"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/upgrade_kyma/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const lmsInstanceID = "8a6c5e1f-1c1b-4f4e-9c55-8b1a2d3e4f50"
+
+func TestLMSCertificateRenewalStep_Run(t *testing.T) {
+	t.Run("should request new certificate and append overrides", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		lmsClient := lms.NewFakeClient(0)
+		tenant, err := lmsClient.CreateTenant(lms.CreateTenantInput{Name: "tenant"})
+		require.NoError(t, err)
+		issuedAt := time.Now().Add(-30 * 24 * time.Hour)
+		require.NoError(t, memoryStorage.LMSCertificates().Save(internal.LMSCertificate{
+			InstanceID: lmsInstanceID,
+			TenantID:   tenant.ID,
+			IssuedAt:   issuedAt,
+			ExpiresAt:  time.Now().Add(24 * time.Hour),
+		}))
+
+		inputCreatorMock := &automock.ProvisionerInputCreator{}
+		defer inputCreatorMock.AssertExpectations(t)
+		inputCreatorMock.On("AppendOverrides", "logging", mock.Anything).Return(nil).Twice()
+
+		operation := fixLMSRenewalOperation(inputCreatorMock)
+		require.NoError(t, memoryStorage.Operations().InsertUpgradeKymaOperation(operation))
+		step := NewLMSCertificateRenewalStep(memoryStorage.Operations(), memoryStorage.LMSCertificates(), lmsClient)
+
+		// when
+		operation, repeat, err := step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), repeat)
+		assert.True(t, lmsClient.IsCertRequestedForTenant(tenant.ID))
+		stored, err := memoryStorage.Operations().GetUpgradeKymaOperationByID(operation.Operation.ID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, stored.LMSCertificateRenewal.CertificateURL)
+		assert.Equal(t, lms.FakePrivateKey, stored.LMSCertificateRenewal.PrivateKey)
+		// the certificate is tracked only after the upgrade delivered it to the Runtime
+		certificate, err := memoryStorage.LMSCertificates().Get(lmsInstanceID)
+		require.NoError(t, err)
+		assert.Equal(t, issuedAt.Unix(), certificate.IssuedAt.Unix())
+	})
+
+	t.Run("should retry until the certificate is signed without requesting it again", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		lmsClient := &notSignedCertificateClient{FakeClient: lms.NewFakeClient(0)}
+		tenant, err := lmsClient.CreateTenant(lms.CreateTenantInput{Name: "tenant"})
+		require.NoError(t, err)
+		require.NoError(t, memoryStorage.LMSCertificates().Save(internal.LMSCertificate{
+			InstanceID: lmsInstanceID,
+			TenantID:   tenant.ID,
+		}))
+
+		operation := fixLMSRenewalOperation(&automock.ProvisionerInputCreator{})
+		require.NoError(t, memoryStorage.Operations().InsertUpgradeKymaOperation(operation))
+		step := NewLMSCertificateRenewalStep(memoryStorage.Operations(), memoryStorage.LMSCertificates(), lmsClient)
+
+		// when
+		operation, repeat, err := step.Run(operation, logger.NewLogDummy())
+		require.NoError(t, err)
+		requestedAt := operation.LMSCertificateRenewal.RequestedAt
+		operation, repeat, err = step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, lmsCertPollingInterval, repeat)
+		assert.Equal(t, orchestration.InProgress, string(operation.State))
+		assert.Equal(t, 1, lmsClient.requested)
+		assert.Equal(t, requestedAt, operation.LMSCertificateRenewal.RequestedAt)
+	})
+
+	t.Run("should fail when the certificate is not signed in time", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		lmsClient := &notSignedCertificateClient{FakeClient: lms.NewFakeClient(0)}
+		tenant, err := lmsClient.CreateTenant(lms.CreateTenantInput{Name: "tenant"})
+		require.NoError(t, err)
+		require.NoError(t, memoryStorage.LMSCertificates().Save(internal.LMSCertificate{
+			InstanceID: lmsInstanceID,
+			TenantID:   tenant.ID,
+		}))
+
+		operation := fixLMSRenewalOperation(&automock.ProvisionerInputCreator{})
+		operation.LMSCertificateRenewal = internal.LMSCertificateRenewal{
+			CertificateURL: "cert-url",
+			PrivateKey:     lms.FakePrivateKey,
+			RequestedAt:    time.Now().Add(-lmsCertPollingTimeout - time.Minute),
+		}
+		require.NoError(t, memoryStorage.Operations().InsertUpgradeKymaOperation(operation))
+		step := NewLMSCertificateRenewalStep(memoryStorage.Operations(), memoryStorage.LMSCertificates(), lmsClient)
+
+		// when
+		operation, _, err = step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, orchestration.Failed, string(operation.State))
+		assert.Zero(t, lmsClient.requested)
+	})
+
+	t.Run("should skip operations which do not renew certificate", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := fixLMSRenewalOperation(&automock.ProvisionerInputCreator{})
+		operation.RenewLMSCertificate = false
+		step := NewLMSCertificateRenewalStep(memoryStorage.Operations(), memoryStorage.LMSCertificates(), nil)
+
+		// when
+		_, repeat, err := step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), repeat)
+	})
+
+	t.Run("should fail when the LMS tenant does not exist", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		require.NoError(t, memoryStorage.LMSCertificates().Save(internal.LMSCertificate{
+			InstanceID: lmsInstanceID,
+			TenantID:   "not-existing",
+		}))
+
+		operation := fixLMSRenewalOperation(&automock.ProvisionerInputCreator{})
+		require.NoError(t, memoryStorage.Operations().InsertUpgradeKymaOperation(operation))
+		step := NewLMSCertificateRenewalStep(memoryStorage.Operations(), memoryStorage.LMSCertificates(), lms.NewFakeClient(0))
+
+		// when
+		operation, _, err := step.Run(operation, logger.NewLogDummy())
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, orchestration.Failed, string(operation.State))
+	})
+}
+
+func fixLMSRenewalOperation(inputCreator internal.ProvisionerInputCreator) internal.UpgradeKymaOperation {
+	return internal.UpgradeKymaOperation{
+		Operation: internal.Operation{
+			ID:         "lms-operation-id",
+			InstanceID: lmsInstanceID,
+			State:      orchestration.InProgress,
+			UpdatedAt:  time.Now(),
+		},
+		ProvisioningParameters: `{"ers_context": {"globalaccount_id": "ga-id", "subaccount_id": "sa-id"}}`,
+		InputCreator:           inputCreator,
+		RenewLMSCertificate:    true,
+	}
+}
+
+type notSignedCertificateClient struct {
+	*lms.FakeClient
+	requested int
+}
+
+func (c *notSignedCertificateClient) RequestCertificate(tenantID string, subj pkix.Name) (string, []byte, error) {
+	c.requested++
+	return c.FakeClient.RequestCertificate(tenantID, subj)
+}
+
+func (c *notSignedCertificateClient) GetCertificateByURL(string) (string, bool, error) {
+	return "", false, nil
+}
@@ -78,10 +78,10 @@ func (s *UpgradeKymaStep) Run(operation internal.UpgradeKymaOperation, log logru
 			log.Errorf("call to provisioner failed: %s", err)
 			return operation, s.timeSchedule.Retry, nil
 		}
-		operation.ProvisionerOperationID = *provisionerResponse.ID
-		operation.Description = "kyma upgrade in progress"
-
-		operation, repeat := s.operationManager.UpdateOperation(operation)
+		operation, repeat := s.operationManager.UpdateOperation(operation, func(operation *internal.UpgradeKymaOperation) {
+			operation.ProvisionerOperationID = *provisionerResponse.ID
+			operation.Description = "kyma upgrade in progress"
+		})
 		if repeat != 0 {
 			log.Errorf("cannot save operation ID from provisioner")
 			return operation, s.timeSchedule.Retry, nil
@@ -14,10 +14,14 @@ import (
 
 type UpgradeKymaOperationManager struct {
 	storage storage.UpgradeKyma
+	updater *storage.OperationUpdater
 }
 
-func NewUpgradeKymaOperationManager(storage storage.Operations) *UpgradeKymaOperationManager {
-	return &UpgradeKymaOperationManager{storage: storage}
+func NewUpgradeKymaOperationManager(operations storage.Operations) *UpgradeKymaOperationManager {
+	return &UpgradeKymaOperationManager{
+		storage: operations,
+		updater: storage.NewOperationUpdater(operations),
+	}
 }
 
 // OperationSucceeded marks the operation as succeeded and only repeats it if there is a storage error
@@ -65,18 +69,29 @@ func (om *UpgradeKymaOperationManager) RetryOperation(operation internal.Upgrade
 	return om.OperationFailed(operation, errorMessage)
 }
 
-// UpdateOperation updates a given operation
-func (om *UpgradeKymaOperationManager) UpdateOperation(operation internal.UpgradeKymaOperation) (internal.UpgradeKymaOperation, time.Duration) {
-	updatedOperation, err := om.storage.UpdateUpgradeKymaOperation(operation)
+// UpdateOperation applies the update to the given operation and stores it, on conflict the update is applied
+// to the latest version of the operation, so all changes which should be stored must be done by the update function
+func (om *UpgradeKymaOperationManager) UpdateOperation(operation internal.UpgradeKymaOperation, update func(operation *internal.UpgradeKymaOperation)) (internal.UpgradeKymaOperation, time.Duration) {
+	updatedOperation, err := om.updater.UpdateUpgradeKymaOperationWithRetry(operation, update)
+	// repeat if there is a problem with the storage
 	if err != nil {
+		update(&operation)
 		return operation, 1 * time.Minute
 	}
 	return *updatedOperation, 0
 }
 
 func (om *UpgradeKymaOperationManager) update(operation internal.UpgradeKymaOperation, state domain.LastOperationState, description string) (internal.UpgradeKymaOperation, time.Duration) {
-	operation.State = state
-	operation.Description = description
+	mutate := func(op *internal.UpgradeKymaOperation) {
+		op.State = state
+		op.Description = description
+	}
 
-	return om.UpdateOperation(operation)
+	updatedOperation, err := om.updater.UpdateUpgradeKymaOperationWithRetry(operation, mutate)
+	// repeat if there is a problem with the storage
+	if err != nil {
+		mutate(&operation)
+		return operation, 1 * time.Minute
+	}
+	return *updatedOperation, 0
 }
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+)
+
+type trialInput interface {
+	Defaults() *gqlschema.ClusterConfigInput
+	ApplyParameters(input *gqlschema.ClusterConfigInput, pp internal.ProvisioningParameters)
+}
+
+// TrialRegion returns the hyperscaler specific region in which the trial cluster is created
+func TrialRegion(pp internal.ProvisioningParameters, platformRegionMapping map[string]string) string {
+	var input trialInput = &AzureTrialInput{PlatformRegionMapping: platformRegionMapping}
+	if pp.Parameters.Provider != nil && *pp.Parameters.Provider == internal.Gcp {
+		input = &GcpTrialInput{PlatformRegionMapping: platformRegionMapping}
+	}
+
+	config := input.Defaults()
+	input.ApplyParameters(config, pp)
+
+	return config.GardenerConfig.Region
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrialRegion(t *testing.T) {
+	gcp := internal.Gcp
+	us := "us"
+	mapping := map[string]string{"cf-eu": "europe", "cf-asia": "asia"}
+
+	for name, tc := range map[string]struct {
+		pp       internal.ProvisioningParameters
+		expected string
+	}{
+		"default Azure region": {
+			pp:       internal.ProvisioningParameters{},
+			expected: "westeurope",
+		},
+		"Azure region from platform region": {
+			pp:       internal.ProvisioningParameters{PlatformRegion: "cf-asia"},
+			expected: "southeastasia",
+		},
+		"GCP region from parameters": {
+			pp: internal.ProvisioningParameters{
+				PlatformRegion: "cf-asia",
+				Parameters:     internal.ProvisioningParametersDTO{Provider: &gcp, Region: &us},
+			},
+			expected: "us-east4",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TrialRegion(tc.pp, mapping))
+		})
+	}
+}
@@ -3,6 +3,8 @@
 package automock
 
 import (
+	context "context"
+
 	gqlschema "github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -96,20 +98,20 @@ func (_m *Client) RuntimeOperationStatus(accountID string, operationID string) (
 	return r0, r1
 }
 
-// RuntimeStatus provides a mock function with given fields: accountID, runtimeID
-func (_m *Client) RuntimeStatus(accountID string, runtimeID string) (gqlschema.RuntimeStatus, error) {
-	ret := _m.Called(accountID, runtimeID)
+// RuntimeStatus provides a mock function with given fields: ctx, accountID, runtimeID
+func (_m *Client) RuntimeStatus(ctx context.Context, accountID string, runtimeID string) (gqlschema.RuntimeStatus, error) {
+	ret := _m.Called(ctx, accountID, runtimeID)
 
 	var r0 gqlschema.RuntimeStatus
-	if rf, ok := ret.Get(0).(func(string, string) gqlschema.RuntimeStatus); ok {
-		r0 = rf(accountID, runtimeID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) gqlschema.RuntimeStatus); ok {
+		r0 = rf(ctx, accountID, runtimeID)
 	} else {
 		r0 = ret.Get(0).(gqlschema.RuntimeStatus)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(accountID, runtimeID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accountID, runtimeID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -27,7 +27,8 @@ type Client interface {
 	UpgradeRuntime(accountID, runtimeID string, config schema.UpgradeRuntimeInput) (schema.OperationStatus, error)
 	ReconnectRuntimeAgent(accountID, runtimeID string) (string, error)
 	RuntimeOperationStatus(accountID, operationID string) (schema.OperationStatus, error)
-	RuntimeStatus(accountID, runtimeID string) (schema.RuntimeStatus, error)
+	// RuntimeStatus returns the status of the Runtime, the request is cancelled with the given context
+	RuntimeStatus(ctx context.Context, accountID, runtimeID string) (schema.RuntimeStatus, error)
 }
 
 type client struct {
@@ -128,13 +129,13 @@ func (c *client) RuntimeOperationStatus(accountID, operationID string) (schema.O
 	return response, nil
 }
 
-func (c *client) RuntimeStatus(accountID, runtimeID string) (schema.RuntimeStatus, error) {
+func (c *client) RuntimeStatus(ctx context.Context, accountID, runtimeID string) (schema.RuntimeStatus, error) {
 	query := c.queryProvider.runtimeStatus(runtimeID)
 	req := gcli.NewRequest(query)
 	req.Header.Add(accountIDKey, accountID)
 
 	var response schema.RuntimeStatus
-	err := c.executeRequest(req, &response)
+	err := c.executeRequestWithContext(ctx, req, &response)
 	if err != nil {
 		return schema.RuntimeStatus{}, errors.Wrap(err, "Failed to get Runtime status")
 	}
@@ -142,6 +143,10 @@ func (c *client) RuntimeStatus(accountID, runtimeID string) (schema.RuntimeStatu
 }
 
 func (c *client) executeRequest(req *gcli.Request, respDestination interface{}) error {
+	return c.executeRequestWithContext(context.TODO(), req, respDestination)
+}
+
+func (c *client) executeRequestWithContext(ctx context.Context, req *gcli.Request, respDestination interface{}) error {
 	if reflect.ValueOf(respDestination).Kind() != reflect.Ptr {
 		return errors.New("destination is not of pointer type")
 	}
@@ -151,7 +156,7 @@ func (c *client) executeRequest(req *gcli.Request, respDestination interface{})
 	}
 
 	wrapper := &graphQLResponseWrapper{Result: respDestination}
-	err := c.graphQLClient.Run(context.TODO(), req, wrapper)
+	err := c.graphQLClient.Run(ctx, req, wrapper)
 	switch {
 	case isClientError(err):
 		return err
@@ -1,6 +1,7 @@
 package provisioner
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -106,7 +107,7 @@ func (c *FakeClient) RuntimeOperationStatus(accountID, operationID string) (sche
 	return o, nil
 }
 
-func (c *FakeClient) RuntimeStatus(accountID, runtimeID string) (schema.RuntimeStatus, error) {
+func (c *FakeClient) RuntimeStatus(ctx context.Context, accountID, runtimeID string) (schema.RuntimeStatus, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -0,0 +1,154 @@
+package retention
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// KindOrchestration is used in statistics next to operation types
+	KindOrchestration = "orchestration"
+
+	SinkTable = "table"
+	SinkFile  = "file"
+)
+
+type Config struct {
+	Disabled  bool          `envconfig:"default=true"`
+	Interval  time.Duration `envconfig:"default=24h"`
+	BatchSize int           `envconfig:"default=100"`
+
+	// the age is counted from the last update of a finished operation or orchestration
+	ProvisioningMaxAge   time.Duration `envconfig:"default=2160h"`
+	DeprovisioningMaxAge time.Duration `envconfig:"default=2160h"`
+	UpgradeKymaMaxAge    time.Duration `envconfig:"default=2160h"`
+	OrchestrationMaxAge  time.Duration `envconfig:"default=2160h"`
+
+	// Sink is "table" to move rows to the archive tables or "file" to export them to gzipped JSONL files in the Directory
+	Sink      string `envconfig:"default=table"`
+	Directory string `envconfig:"optional"`
+}
+
+// Job periodically moves finished operations and orchestrations which exceeded the retention period out of the hot tables
+type Job struct {
+	archive storage.Archive
+	sink    Sink
+	cfg     Config
+	log     logrus.FieldLogger
+
+	mu       sync.Mutex
+	archived map[string]int
+	failures map[string]int
+}
+
+func NewJob(archive storage.Archive, sink Sink, cfg Config, log logrus.FieldLogger) *Job {
+	return &Job{
+		archive:  archive,
+		sink:     sink,
+		cfg:      cfg,
+		log:      log,
+		archived: make(map[string]int),
+		failures: make(map[string]int),
+	}
+}
+
+// Run archives finished records periodically until the stop channel is closed
+func (j *Job) Run(stop <-chan struct{}) {
+	wait.Until(j.ArchiveAll, j.cfg.Interval, stop)
+}
+
+// ArchiveAll archives records of every kind in batches until there is nothing left to archive.
+// Operations are archived first, so orchestrations without operations can be archived in the same run.
+// A failure stops processing of the given kind only, the rest of its records are archived in the next run.
+func (j *Job) ArchiveAll() {
+	now := time.Now()
+	for _, operationType := range []dbmodel.OperationType{
+		dbmodel.OperationTypeProvision,
+		dbmodel.OperationTypeDeprovision,
+		dbmodel.OperationTypeUpgradeKyma,
+	} {
+		before := now.Add(-j.maxAge(operationType))
+		j.archiveBatches(string(operationType), func() (int, error) {
+			operations, err := j.archive.ListOperationsToArchive(operationType, before, j.cfg.BatchSize)
+			if err != nil || len(operations) == 0 {
+				return 0, err
+			}
+			return len(operations), j.sink.ArchiveOperations(operations)
+		})
+	}
+
+	before := now.Add(-j.cfg.OrchestrationMaxAge)
+	j.archiveBatches(KindOrchestration, func() (int, error) {
+		orchestrations, err := j.archive.ListOrchestrationsToArchive(before, j.cfg.BatchSize)
+		if err != nil || len(orchestrations) == 0 {
+			return 0, err
+		}
+		return len(orchestrations), j.sink.ArchiveOrchestrations(orchestrations)
+	})
+}
+
+// ArchivedStats returns the number of archived records by kind
+func (j *Job) ArchivedStats() map[string]int {
+	return j.snapshot(j.archived)
+}
+
+// FailureStats returns the number of failed archive batches by kind
+func (j *Job) FailureStats() map[string]int {
+	return j.snapshot(j.failures)
+}
+
+func (j *Job) archiveBatches(kind string, archiveBatch func() (int, error)) {
+	total := 0
+	for {
+		count, err := archiveBatch()
+		if err != nil {
+			j.log.Errorf("while archiving %s records: %s", kind, err)
+			j.record(j.failures, kind, 1)
+			break
+		}
+		total += count
+		j.record(j.archived, kind, count)
+		if count < j.cfg.BatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		j.log.Infof("archived %d %s records", total, kind)
+	}
+}
+
+func (j *Job) maxAge(operationType dbmodel.OperationType) time.Duration {
+	switch operationType {
+	case dbmodel.OperationTypeProvision:
+		return j.cfg.ProvisioningMaxAge
+	case dbmodel.OperationTypeDeprovision:
+		return j.cfg.DeprovisioningMaxAge
+	default:
+		return j.cfg.UpgradeKymaMaxAge
+	}
+}
+
+func (j *Job) record(counts map[string]int, kind string, count int) {
+	if count == 0 {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	counts[kind] += count
+}
+
+func (j *Job) snapshot(counts map[string]int) map[string]int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	result := make(map[string]int, len(counts))
+	for k, v := range counts {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+package retention
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJob_ArchiveAll(t *testing.T) {
+	t.Run("should archive all finished records in batches", func(t *testing.T) {
+		// given
+		archive := newFakeArchive()
+		archive.operations[dbmodel.OperationTypeProvision] = 25
+		archive.operations[dbmodel.OperationTypeUpgradeKyma] = 3
+		archive.orchestrations = 2
+		job := NewJob(archive, NewTableSink(archive), fixConfig(), logrus.New())
+
+		// when
+		job.ArchiveAll()
+
+		// then
+		assert.Equal(t, map[string]int{
+			string(dbmodel.OperationTypeProvision):   25,
+			string(dbmodel.OperationTypeUpgradeKyma): 3,
+			KindOrchestration:                        2,
+		}, job.ArchivedStats())
+		assert.Empty(t, job.FailureStats())
+		assert.Equal(t, 0, archive.operations[dbmodel.OperationTypeProvision])
+		assert.Equal(t, 0, archive.orchestrations)
+	})
+
+	t.Run("should use the max age of the operation type", func(t *testing.T) {
+		// given
+		archive := newFakeArchive()
+		cfg := fixConfig()
+		cfg.DeprovisioningMaxAge = time.Hour
+		job := NewJob(archive, NewTableSink(archive), cfg, logrus.New())
+
+		// when
+		job.ArchiveAll()
+
+		// then
+		require.Contains(t, archive.before, dbmodel.OperationTypeDeprovision)
+		assert.WithinDuration(t, time.Now().Add(-time.Hour), archive.before[dbmodel.OperationTypeDeprovision], time.Minute)
+		assert.WithinDuration(t, time.Now().Add(-cfg.ProvisioningMaxAge), archive.before[dbmodel.OperationTypeProvision], time.Minute)
+	})
+
+	t.Run("should stop archiving the kind on failure", func(t *testing.T) {
+		// given
+		archive := newFakeArchive()
+		archive.operations[dbmodel.OperationTypeProvision] = 25
+		archive.operations[dbmodel.OperationTypeDeprovision] = 5
+		archive.moveErr = map[dbmodel.OperationType]error{dbmodel.OperationTypeProvision: errors.New("boom")}
+		job := NewJob(archive, NewTableSink(archive), fixConfig(), logrus.New())
+
+		// when
+		job.ArchiveAll()
+
+		// then
+		assert.Equal(t, map[string]int{string(dbmodel.OperationTypeDeprovision): 5}, job.ArchivedStats())
+		assert.Equal(t, map[string]int{string(dbmodel.OperationTypeProvision): 1}, job.FailureStats())
+		assert.Equal(t, 25, archive.operations[dbmodel.OperationTypeProvision])
+	})
+}
+
+func fixConfig() Config {
+	return Config{
+		BatchSize:            10,
+		ProvisioningMaxAge:   24 * time.Hour,
+		DeprovisioningMaxAge: 24 * time.Hour,
+		UpgradeKymaMaxAge:    24 * time.Hour,
+		OrchestrationMaxAge:  24 * time.Hour,
+		Sink:                 SinkTable,
+	}
+}
+
+// fakeArchive keeps only the number of records to archive
+type fakeArchive struct {
+	operations     map[dbmodel.OperationType]int
+	orchestrations int
+	before         map[dbmodel.OperationType]time.Time
+	moveErr        map[dbmodel.OperationType]error
+	deleteErr      error
+	deleted        []string
+}
+
+func newFakeArchive() *fakeArchive {
+	return &fakeArchive{
+		operations: make(map[dbmodel.OperationType]int),
+		before:     make(map[dbmodel.OperationType]time.Time),
+	}
+}
+
+func (f *fakeArchive) ListOperationsToArchive(operationType dbmodel.OperationType, finishedBefore time.Time, limit int) ([]dbmodel.OperationDTO, error) {
+	f.before[operationType] = finishedBefore
+	count := f.operations[operationType]
+	if count > limit {
+		count = limit
+	}
+	operations := make([]dbmodel.OperationDTO, 0, count)
+	for i := 0; i < count; i++ {
+		operations = append(operations, dbmodel.OperationDTO{ID: string(operationType), Type: operationType})
+	}
+	return operations, nil
+}
+
+func (f *fakeArchive) ListOrchestrationsToArchive(finishedBefore time.Time, limit int) ([]dbmodel.OrchestrationDTO, error) {
+	count := f.orchestrations
+	if count > limit {
+		count = limit
+	}
+	return make([]dbmodel.OrchestrationDTO, count), nil
+}
+
+func (f *fakeArchive) MoveOperationsToArchive(operations []dbmodel.OperationDTO) error {
+	if len(operations) == 0 {
+		return nil
+	}
+	operationType := operations[0].Type
+	if err := f.moveErr[operationType]; err != nil {
+		return err
+	}
+	f.operations[operationType] -= len(operations)
+	return nil
+}
+
+func (f *fakeArchive) MoveOrchestrationsToArchive(orchestrations []dbmodel.OrchestrationDTO) error {
+	f.orchestrations -= len(orchestrations)
+	return nil
+}
+
+func (f *fakeArchive) DeleteOperations(ids []string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, ids...)
+	return nil
+}
+
+func (f *fakeArchive) DeleteOrchestrations(ids []string) error {
+	f.deleted = append(f.deleted, ids...)
+	return nil
+}
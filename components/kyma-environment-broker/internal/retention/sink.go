@@ -0,0 +1,199 @@
+package retention
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+
+	"github.com/pkg/errors"
+)
+
+// Sink stores finished records outside of the tables used by the broker and removes them from these tables
+type Sink interface {
+	ArchiveOperations(operations []dbmodel.OperationDTO) error
+	ArchiveOrchestrations(orchestrations []dbmodel.OrchestrationDTO) error
+}
+
+// NewSink returns the sink configured in the retention config
+func NewSink(cfg Config, archive storage.Archive) (Sink, error) {
+	switch cfg.Sink {
+	case SinkTable:
+		return NewTableSink(archive), nil
+	case SinkFile:
+		if cfg.Directory == "" {
+			return nil, errors.New("directory is required for the file sink")
+		}
+		return NewFileSink(archive, cfg.Directory), nil
+	default:
+		return nil, errors.Errorf("unknown retention sink %q", cfg.Sink)
+	}
+}
+
+// TableSink moves records to the archive tables in the same database
+type TableSink struct {
+	archive storage.Archive
+}
+
+func NewTableSink(archive storage.Archive) *TableSink {
+	return &TableSink{archive: archive}
+}
+
+func (s *TableSink) ArchiveOperations(operations []dbmodel.OperationDTO) error {
+	return s.archive.MoveOperationsToArchive(operations)
+}
+
+func (s *TableSink) ArchiveOrchestrations(orchestrations []dbmodel.OrchestrationDTO) error {
+	return s.archive.MoveOrchestrationsToArchive(orchestrations)
+}
+
+// FileSink exports records to gzipped JSONL files, one file per batch. The directory can be a mounted object store bucket.
+// Records are deleted only after the file is completely written. The file name is derived from IDs of the records,
+// so when the deletion fails the same batch is exported again to the same file instead of a new one.
+type FileSink struct {
+	archive   storage.Archive
+	directory string
+}
+
+func NewFileSink(archive storage.Archive, directory string) *FileSink {
+	return &FileSink{
+		archive:   archive,
+		directory: directory,
+	}
+}
+
+type archivedOperation struct {
+	ID                string          `json:"id"`
+	Version           int             `json:"version"`
+	CreatedAt         time.Time       `json:"createdAt"`
+	UpdatedAt         time.Time       `json:"updatedAt"`
+	InstanceID        string          `json:"instanceId"`
+	OrchestrationID   string          `json:"orchestrationId,omitempty"`
+	TargetOperationID string          `json:"targetOperationId"`
+	Data              json.RawMessage `json:"data"`
+	State             string          `json:"state"`
+	Description       string          `json:"description"`
+	Type              string          `json:"type"`
+}
+
+type archivedOrchestration struct {
+	OrchestrationID string          `json:"orchestrationId"`
+	State           string          `json:"state"`
+	Description     string          `json:"description"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt"`
+	Parameters      json.RawMessage `json:"parameters"`
+}
+
+func (s *FileSink) ArchiveOperations(operations []dbmodel.OperationDTO) error {
+	if len(operations) == 0 {
+		return nil
+	}
+	records := make([]interface{}, 0, len(operations))
+	ids := make([]string, 0, len(operations))
+	for _, op := range operations {
+		records = append(records, archivedOperation{
+			ID:                op.ID,
+			Version:           op.Version,
+			CreatedAt:         op.CreatedAt,
+			UpdatedAt:         op.UpdatedAt,
+			InstanceID:        op.InstanceID,
+			OrchestrationID:   op.OrchestrationID.String,
+			TargetOperationID: op.TargetOperationID,
+			Data:              rawJSON(op.Data),
+			State:             op.State,
+			Description:       op.Description,
+			Type:              string(op.Type),
+		})
+		ids = append(ids, op.ID)
+	}
+	if err := s.write("operations", ids, records); err != nil {
+		return err
+	}
+	return s.archive.DeleteOperations(ids)
+}
+
+func (s *FileSink) ArchiveOrchestrations(orchestrations []dbmodel.OrchestrationDTO) error {
+	if len(orchestrations) == 0 {
+		return nil
+	}
+	records := make([]interface{}, 0, len(orchestrations))
+	ids := make([]string, 0, len(orchestrations))
+	for _, o := range orchestrations {
+		records = append(records, archivedOrchestration{
+			OrchestrationID: o.OrchestrationID,
+			State:           o.State,
+			Description:     o.Description,
+			CreatedAt:       o.CreatedAt,
+			UpdatedAt:       o.UpdatedAt,
+			Parameters:      rawJSON(o.Parameters),
+		})
+		ids = append(ids, o.OrchestrationID)
+	}
+	if err := s.write("orchestrations", ids, records); err != nil {
+		return err
+	}
+	return s.archive.DeleteOrchestrations(ids)
+}
+
+// write stores records in a temporary file which is renamed when it is complete, so readers never see partial files
+func (s *FileSink) write(prefix string, ids []string, records []interface{}) error {
+	tmp, err := ioutil.TempFile(s.directory, fmt.Sprintf(".%s-*.tmp", prefix))
+	if err != nil {
+		return errors.Wrap(err, "while creating archive file")
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	encoder := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			tmp.Close()
+			return errors.Wrap(err, "while writing archive record")
+		}
+	}
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "while compressing archive file")
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "while syncing archive file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "while closing archive file")
+	}
+
+	name := batchFileName(prefix, ids)
+	if err := os.Rename(tmp.Name(), filepath.Join(s.directory, name)); err != nil {
+		return errors.Wrap(err, "while renaming archive file")
+	}
+	return nil
+}
+
+// batchFileName returns the same name for the same set of records regardless of their order
+func batchFileName(prefix string, ids []string) string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return fmt.Sprintf("%s-%s.jsonl.gz", prefix, hex.EncodeToString(sum[:8]))
+}
+
+// rawJSON keeps a stored JSON document as it is and falls back to a JSON string for invalid documents
+func rawJSON(value string) json.RawMessage {
+	if json.Valid([]byte(value)) {
+		return json.RawMessage(value)
+	}
+	encoded, _ := json.Marshal(value)
+	return encoded
+}
@@ -0,0 +1,121 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink_ArchiveOperations(t *testing.T) {
+	// given
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	archive := newFakeArchive()
+	sink := NewFileSink(archive, dir)
+
+	operations := []dbmodel.OperationDTO{
+		{ID: "op-1", Type: dbmodel.OperationTypeProvision, Data: `{"runtime_id":"runtime-1"}`, State: "succeeded"},
+		{ID: "op-2", Type: dbmodel.OperationTypeUpgradeKyma, Data: `{}`, OrchestrationID: sql.NullString{String: "orch-1", Valid: true}},
+	}
+
+	// when
+	err = sink.ArchiveOperations(operations)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"op-1", "op-2"}, archive.deleted)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, batchFileName("operations", []string{"op-2", "op-1"}), filepath.Base(files[0]))
+
+	records := readRecords(t, files[0])
+	require.Len(t, records, 2)
+	assert.Equal(t, "op-1", records[0]["id"])
+	assert.Equal(t, map[string]interface{}{"runtime_id": "runtime-1"}, records[0]["data"])
+	assert.Equal(t, "orch-1", records[1]["orchestrationId"])
+}
+
+func TestFileSink_ExportsBatchAgainToSameFileWhenDeleteFails(t *testing.T) {
+	// given
+	dir, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	archive := newFakeArchive()
+	archive.deleteErr = errors.New("connection refused")
+	sink := NewFileSink(archive, dir)
+	operations := []dbmodel.OperationDTO{{ID: "op-1", Data: `{}`}, {ID: "op-2", Data: `{}`}}
+
+	// when
+	err = sink.ArchiveOperations(operations)
+	require.Error(t, err)
+	archive.deleteErr = nil
+	err = sink.ArchiveOperations(operations)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"op-1", "op-2"}, archive.deleted)
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestFileSink_DoesNotDeleteWhenWriteFails(t *testing.T) {
+	// given
+	archive := newFakeArchive()
+	sink := NewFileSink(archive, filepath.Join(os.TempDir(), "not-existing-archive-dir"))
+
+	// when
+	err := sink.ArchiveOrchestrations([]dbmodel.OrchestrationDTO{{OrchestrationID: "orch-1", Parameters: "{}"}})
+
+	// then
+	assert.Error(t, err)
+	assert.Empty(t, archive.deleted)
+}
+
+func TestNewSink(t *testing.T) {
+	archive := newFakeArchive()
+
+	sink, err := NewSink(Config{Sink: SinkTable}, archive)
+	require.NoError(t, err)
+	assert.IsType(t, &TableSink{}, sink)
+
+	_, err = NewSink(Config{Sink: SinkFile}, archive)
+	assert.Error(t, err)
+
+	_, err = NewSink(Config{Sink: "s3"}, archive)
+	assert.Error(t, err)
+}
+
+func readRecords(t *testing.T, path string) []map[string]interface{} {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
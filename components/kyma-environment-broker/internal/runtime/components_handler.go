@@ -0,0 +1,275 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type Queue interface {
+	Add(operationID string)
+}
+
+// ComponentsHandler exposes the optional components of the Runtime and allows to enable or disable them
+// after the Runtime was provisioned. The change is applied by the upgrade Kyma operation with the same Kyma version.
+type ComponentsHandler struct {
+	instancesDb      storage.Instances
+	operationsDb     storage.Operations
+	optComponentsSvc *OptionalComponentsService
+	queue            Queue
+
+	log logrus.FieldLogger
+}
+
+func NewComponentsHandler(instanceDb storage.Instances, operationDb storage.Operations, optComponentsSvc *OptionalComponentsService, q Queue, log logrus.FieldLogger) *ComponentsHandler {
+	return &ComponentsHandler{
+		instancesDb:      instanceDb,
+		operationsDb:     operationDb,
+		optComponentsSvc: optComponentsSvc,
+		queue:            q,
+		log:              log,
+	}
+}
+
+func (h *ComponentsHandler) AttachRoutes(router *mux.Router) {
+	router.HandleFunc("/runtimes/{runtime_id}/components", h.getComponents).Methods(http.MethodGet)
+	router.HandleFunc("/runtimes/{runtime_id}/components", h.updateComponents).Methods(http.MethodPut)
+}
+
+func (h *ComponentsHandler) getComponents(w http.ResponseWriter, req *http.Request) {
+	runtimeID := mux.Vars(req)["runtime_id"]
+	log := h.log.WithField("runtimeID", runtimeID)
+
+	_, pOpr, err := h.fetchInstanceWithProvisioning(runtimeID)
+	if err != nil {
+		httputil.WriteErrorResponse(w, h.resolveErrorStatus(err), err)
+		return
+	}
+	pp, err := pOpr.GetProvisioningParameters()
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	installed := h.installed(pp.Parameters.OptionalComponentsToInstall, log)
+
+	httputil.WriteResponse(w, http.StatusOK, pkg.ComponentsDTO{
+		RuntimeID: runtimeID,
+		Installed: installed,
+		Available: h.available(),
+	})
+}
+
+func (h *ComponentsHandler) updateComponents(w http.ResponseWriter, req *http.Request) {
+	runtimeID := mux.Vars(req)["runtime_id"]
+	log := h.log.WithField("runtimeID", runtimeID)
+
+	var update pkg.ComponentsUpdate
+	if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrap(err, "while decoding request body"))
+		return
+	}
+
+	instance, pOpr, err := h.fetchInstanceWithProvisioning(runtimeID)
+	if err != nil {
+		httputil.WriteErrorResponse(w, h.resolveErrorStatus(err), err)
+		return
+	}
+	if err := process.CheckNoOperationInProgress(h.operationsDb, instance.InstanceID, pOpr); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusConflict, err)
+		return
+	}
+
+	pp, err := pOpr.GetProvisioningParameters()
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	current := h.installed(pp.Parameters.OptionalComponentsToInstall, log)
+	desired, err := h.applyUpdate(current, update)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	dto := pkg.ComponentsDTO{
+		RuntimeID: runtimeID,
+		Installed: desired,
+		Available: h.available(),
+	}
+	if equalComponents(current, desired) {
+		log.Infof("optional components %v are already installed, nothing to do", desired)
+		httputil.WriteResponse(w, http.StatusOK, dto)
+		return
+	}
+
+	// the changed optional components are applied using the Kyma version currently installed on the Runtime.
+	// The new list is kept in the operation and stored in the provisioning parameters only when the upgrade succeeds.
+	operation, err := process.NewSameVersionUpgradeKymaOperation(h.operationsDb, *instance, *pOpr, "Operation created: optional components update")
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	operation.UpdateOptionalComponents = true
+	operation.OptionalComponentsToInstall = desired
+	pp.Parameters.OptionalComponentsToInstall = desired
+	if err := operation.SetProvisioningParameters(pp); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	// the storage allows only one upgrade kyma operation in progress created outside of orchestrations,
+	// so concurrent updates of the same Runtime are rejected
+	if err := h.operationsDb.InsertUpgradeKymaOperation(operation); err != nil {
+		httputil.WriteErrorResponse(w, h.resolveErrorStatus(err), errors.Wrap(err, "while inserting upgrade kyma operation"))
+		return
+	}
+
+	h.queue.Add(operation.Operation.ID)
+	log.Infof("optional components change from %v to %v, upgrade kyma operation %s created", current, desired, operation.Operation.ID)
+
+	dto.OperationID = operation.Operation.ID
+	httputil.WriteResponse(w, http.StatusAccepted, dto)
+}
+
+func (h *ComponentsHandler) fetchInstanceWithProvisioning(runtimeID string) (*internal.Instance, *internal.ProvisioningOperation, error) {
+	instances, err := h.instancesDb.FindAllInstancesForRuntimes([]string{runtimeID})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "while fetching instance")
+	}
+	if len(instances) == 0 {
+		return nil, nil, dberr.NotFound("instance for runtime %s not found", runtimeID)
+	}
+	instance := instances[0]
+
+	pOpr, err := h.operationsDb.GetProvisioningOperationByInstanceID(instance.InstanceID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "while fetching provisioning operation for instance")
+	}
+
+	return &instance, pOpr, nil
+}
+
+// applyUpdate returns the sorted list of optional components names after enabling and disabling the requested ones
+func (h *ComponentsHandler) applyUpdate(current []string, update pkg.ComponentsUpdate) ([]string, error) {
+	enable, err := h.normalize(update.Enable)
+	if err != nil {
+		return nil, err
+	}
+	disable, err := h.normalize(update.Disable)
+	if err != nil {
+		return nil, err
+	}
+
+	toEnable := toNormalizedMap(enable)
+	result := map[string]struct{}{}
+	for _, name := range current {
+		result[name] = struct{}{}
+	}
+	for _, name := range enable {
+		result[name] = struct{}{}
+	}
+	for _, name := range disable {
+		if _, found := toEnable[strings.ToLower(name)]; found {
+			return nil, fmt.Errorf("component %s cannot be enabled and disabled at the same time", name)
+		}
+		delete(result, name)
+	}
+
+	out := make([]string, 0, len(result))
+	for name := range result {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+
+	return out, nil
+}
+
+// normalize maps given names (case insensitive) to the names of the registered optional components
+func (h *ComponentsHandler) normalize(names []string) ([]string, error) {
+	registered := map[string]string{}
+	for _, name := range h.optComponentsSvc.GetAllOptionalComponentsNames() {
+		registered[strings.ToLower(name)] = name
+	}
+
+	out := make([]string, 0, len(names))
+	for name := range toNormalizedMap(names) {
+		canonical, found := registered[name]
+		if !found {
+			return nil, fmt.Errorf("component %s is not an optional component", name)
+		}
+		out = append(out, canonical)
+	}
+	sort.Strings(out)
+
+	return out, nil
+}
+
+// installed maps the stored names to the names of the registered optional components. Stored names which are not registered
+// anymore (e.g. the component was removed from the broker) are passed through unchanged, they are not disabled by the upgrade
+// and they are kept, so the stored parameters are not lost when the Runtime is updated.
+func (h *ComponentsHandler) installed(stored []string, log logrus.FieldLogger) []string {
+	registered := map[string]string{}
+	for _, name := range h.optComponentsSvc.GetAllOptionalComponentsNames() {
+		registered[strings.ToLower(name)] = name
+	}
+
+	result := map[string]struct{}{}
+	for _, name := range stored {
+		canonical, found := registered[strings.ToLower(name)]
+		if !found {
+			log.Warnf("stored component %s is not an optional component, passing it through", name)
+			canonical = name
+		}
+		result[canonical] = struct{}{}
+	}
+
+	out := make([]string, 0, len(result))
+	for name := range result {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+func (h *ComponentsHandler) available() []string {
+	names := h.optComponentsSvc.GetAllOptionalComponentsNames()
+	sort.Strings(names)
+	return names
+}
+
+func (h *ComponentsHandler) resolveErrorStatus(err error) int {
+	cause := errors.Cause(err)
+	switch {
+	case dberr.IsNotFound(cause):
+		return http.StatusNotFound
+	case dberr.IsConflict(cause):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func equalComponents(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,279 @@
+package runtime_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime/components"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/driver/memory"
+
+	"github.com/gorilla/mux"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fixInstanceID = "instance-id"
+	fixRuntimeID  = "runtime-id"
+)
+
+func TestComponentsHandler(t *testing.T) {
+	t.Run("should enable and disable optional components", func(t *testing.T) {
+		// given
+		operations := memory.NewOperation()
+		instances := memory.NewInstance(operations)
+		fixInstanceWithProvisioning(t, instances, operations, []string{components.Tracing})
+		queue := &fakeQueue{}
+		router := fixComponentsRouter(instances, operations, queue)
+
+		// when
+		rr := doComponentsRequest(t, router, http.MethodPut, pkg.ComponentsUpdate{
+			Enable:  []string{"kiali"},
+			Disable: []string{components.Tracing},
+		})
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		var out pkg.ComponentsDTO
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.Equal(t, []string{components.Kiali}, out.Installed)
+		assert.NotEmpty(t, out.OperationID)
+		assert.Equal(t, []string{out.OperationID}, queue.ids)
+
+		upgradeOp, err := operations.GetUpgradeKymaOperationByID(out.OperationID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.LastOperationState(orchestration.InProgress), upgradeOp.State)
+		assert.Equal(t, fixRuntimeID, upgradeOp.RuntimeID)
+		assert.Equal(t, "1.17.0", upgradeOp.RuntimeVersion.Version)
+		assert.True(t, upgradeOp.UpdateOptionalComponents)
+		assert.Equal(t, []string{components.Kiali}, upgradeOp.OptionalComponentsToInstall)
+
+		pOpr, err := operations.GetProvisioningOperationByInstanceID(fixInstanceID)
+		require.NoError(t, err)
+		pp, err := pOpr.GetProvisioningParameters()
+		require.NoError(t, err)
+		assert.Equal(t, []string{components.Tracing}, pp.Parameters.OptionalComponentsToInstall)
+
+		instance, err := instances.GetByID(fixInstanceID)
+		require.NoError(t, err)
+		assert.Equal(t, pOpr.ProvisioningParameters, instance.ProvisioningParameters)
+	})
+
+	t.Run("should not create operation when nothing changed", func(t *testing.T) {
+		// given
+		operations := memory.NewOperation()
+		instances := memory.NewInstance(operations)
+		fixInstanceWithProvisioning(t, instances, operations, []string{components.Kiali})
+		queue := &fakeQueue{}
+		router := fixComponentsRouter(instances, operations, queue)
+
+		// when
+		rr := doComponentsRequest(t, router, http.MethodPut, pkg.ComponentsUpdate{
+			Enable: []string{components.Kiali},
+		})
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, queue.ids)
+	})
+
+	t.Run("should reject unknown component", func(t *testing.T) {
+		// given
+		operations := memory.NewOperation()
+		instances := memory.NewInstance(operations)
+		fixInstanceWithProvisioning(t, instances, operations, nil)
+		router := fixComponentsRouter(instances, operations, &fakeQueue{})
+
+		// when
+		rr := doComponentsRequest(t, router, http.MethodPut, pkg.ComponentsUpdate{
+			Enable: []string{"not-optional"},
+		})
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should reject update when upgrade is in progress", func(t *testing.T) {
+		// given
+		operations := memory.NewOperation()
+		instances := memory.NewInstance(operations)
+		fixInstanceWithProvisioning(t, instances, operations, nil)
+		err := operations.InsertUpgradeKymaOperation(internal.UpgradeKymaOperation{
+			Operation: internal.Operation{
+				ID:         "upgrade-id",
+				InstanceID: fixInstanceID,
+				State:      domain.InProgress,
+			},
+		})
+		require.NoError(t, err)
+		router := fixComponentsRouter(instances, operations, &fakeQueue{})
+
+		// when
+		rr := doComponentsRequest(t, router, http.MethodPut, pkg.ComponentsUpdate{
+			Enable: []string{components.Kiali},
+		})
+
+		// then
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("should reject concurrent update", func(t *testing.T) {
+		// given
+		operations := memory.NewOperation()
+		instances := memory.NewInstance(operations)
+		fixInstanceWithProvisioning(t, instances, operations, nil)
+		err := operations.InsertUpgradeKymaOperation(internal.UpgradeKymaOperation{
+			Operation: internal.Operation{
+				ID:         "upgrade-id",
+				InstanceID: fixInstanceID,
+				State:      domain.InProgress,
+			},
+		})
+		require.NoError(t, err)
+		// the operation of the concurrent update is not visible yet when the update checks the operations in progress
+		router := fixComponentsRouter(instances, &concurrentUpdate{Operations: operations}, &fakeQueue{})
+
+		// when
+		rr := doComponentsRequest(t, router, http.MethodPut, pkg.ComponentsUpdate{
+			Enable: []string{components.Kiali},
+		})
+
+		// then
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("should return installed components", func(t *testing.T) {
+		// given
+		operations := memory.NewOperation()
+		instances := memory.NewInstance(operations)
+		fixInstanceWithProvisioning(t, instances, operations, []string{"tracing"})
+		router := fixComponentsRouter(instances, operations, &fakeQueue{})
+
+		// when
+		rr := doComponentsRequest(t, router, http.MethodGet, nil)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		var out pkg.ComponentsDTO
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.Equal(t, []string{components.Tracing}, out.Installed)
+		assert.ElementsMatch(t, []string{components.Kiali, components.Tracing}, out.Available)
+	})
+
+	t.Run("should pass through stored components which are not optional components", func(t *testing.T) {
+		// given
+		operations := memory.NewOperation()
+		instances := memory.NewInstance(operations)
+		fixInstanceWithProvisioning(t, instances, operations, []string{"tracing", "removed-component"})
+		router := fixComponentsRouter(instances, operations, &fakeQueue{})
+
+		// when
+		rr := doComponentsRequest(t, router, http.MethodGet, nil)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		var out pkg.ComponentsDTO
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.Equal(t, []string{"removed-component", components.Tracing}, out.Installed)
+		assert.ElementsMatch(t, []string{components.Kiali, components.Tracing}, out.Available)
+	})
+
+	t.Run("should keep stored components which are not optional components on update", func(t *testing.T) {
+		// given
+		operations := memory.NewOperation()
+		instances := memory.NewInstance(operations)
+		fixInstanceWithProvisioning(t, instances, operations, []string{"removed-component"})
+		queue := &fakeQueue{}
+		router := fixComponentsRouter(instances, operations, queue)
+
+		// when
+		rr := doComponentsRequest(t, router, http.MethodPut, pkg.ComponentsUpdate{
+			Enable: []string{components.Kiali},
+		})
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		var out pkg.ComponentsDTO
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+		assert.Equal(t, []string{components.Kiali, "removed-component"}, out.Installed)
+
+		upgradeOp, err := operations.GetUpgradeKymaOperationByID(out.OperationID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{components.Kiali, "removed-component"}, upgradeOp.OptionalComponentsToInstall)
+		pp, err := upgradeOp.GetProvisioningParameters()
+		require.NoError(t, err)
+		assert.Equal(t, []string{components.Kiali, "removed-component"}, pp.Parameters.OptionalComponentsToInstall)
+	})
+}
+
+type concurrentUpdate struct {
+	storage.Operations
+}
+
+func (c *concurrentUpdate) ListUpgradeKymaOperationsByInstanceID(_ string) ([]internal.UpgradeKymaOperation, error) {
+	return nil, nil
+}
+
+type fakeQueue struct {
+	ids []string
+}
+
+func (q *fakeQueue) Add(id string) {
+	q.ids = append(q.ids, id)
+}
+
+func fixComponentsRouter(instances *memory.Instance, operations storage.Operations, queue *fakeQueue) *mux.Router {
+	optComponentsSvc := runtime.NewOptionalComponentsService(runtime.ComponentsDisablers{
+		components.Kiali:   runtime.NewGenericComponentDisabler(components.Kiali),
+		components.Tracing: runtime.NewGenericComponentDisabler(components.Tracing),
+	})
+	handler := runtime.NewComponentsHandler(instances, operations, optComponentsSvc, queue, logrus.New())
+	router := mux.NewRouter()
+	handler.AttachRoutes(router)
+	return router
+}
+
+func fixInstanceWithProvisioning(t *testing.T, instances *memory.Instance, operations storage.Operations, optionalComponents []string) {
+	pp := internal.ProvisioningParameters{
+		PlanID: "4deee563-e5ec-4731-b9b1-53b42d855f0c",
+		Parameters: internal.ProvisioningParametersDTO{
+			OptionalComponentsToInstall: optionalComponents,
+		},
+	}
+	pOpr, err := internal.NewProvisioningOperationWithID("provisioning-id", fixInstanceID, pp)
+	require.NoError(t, err)
+	pOpr.State = domain.Succeeded
+	pOpr.RuntimeVersion = internal.RuntimeVersionData{Version: "1.17.0", Origin: internal.Defaults}
+	require.NoError(t, operations.InsertProvisioningOperation(pOpr))
+
+	require.NoError(t, instances.Insert(internal.Instance{
+		InstanceID:             fixInstanceID,
+		RuntimeID:              fixRuntimeID,
+		ProvisioningParameters: pOpr.ProvisioningParameters,
+		CreatedAt:              time.Now(),
+	}))
+}
+
+func doComponentsRequest(t *testing.T, router *mux.Router, method string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&payload).Encode(body))
+	}
+	req, err := http.NewRequest(method, "/runtimes/"+fixRuntimeID+"/components", &payload)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
@@ -13,6 +13,7 @@ type Converter interface {
 	ApplyProvisioningOperation(dto *pkg.RuntimeDTO, pOpr *internal.ProvisioningOperation)
 	ApplyDeprovisioningOperation(dto *pkg.RuntimeDTO, dOpr *internal.DeprovisioningOperation)
 	ApplyUpgradingKymaOperations(dto *pkg.RuntimeDTO, oprs []internal.UpgradeKymaOperation, totalCount int)
+	ApplyInstanceOperations(dto *pkg.RuntimeDTO, instance internal.InstanceWithOperations)
 }
 
 type converter struct {
@@ -96,12 +97,34 @@ func (c *converter) NewDTO(instance internal.Instance) (pkg.RuntimeDTO, error) {
 }
 
 func (c *converter) ApplyUpgradingKymaOperations(dto *pkg.RuntimeDTO, oprs []internal.UpgradeKymaOperation, totalCount int) {
+	operations := make([]internal.Operation, 0, len(oprs))
+	for _, o := range oprs {
+		operations = append(operations, o.Operation)
+	}
+	c.applyUpgradingKymaOperations(dto, operations, totalCount)
+}
+
+// ApplyInstanceOperations sets the state, Kyma version and operations resolved by the storage
+func (c *converter) ApplyInstanceOperations(dto *pkg.RuntimeDTO, instance internal.InstanceWithOperations) {
+	dto.KymaVersion = instance.KymaVersion
+	dto.Status.State = instance.State
+	if instance.Provisioning != nil {
+		c.applyOperation(instance.Provisioning, dto.Status.Provisioning)
+	}
+	if instance.Deprovisioning != nil {
+		dto.Status.Deprovisioning = &pkg.Operation{}
+		c.applyOperation(instance.Deprovisioning, dto.Status.Deprovisioning)
+	}
+	c.applyUpgradingKymaOperations(dto, instance.UpgradeKyma, instance.UpgradeKymaTotal)
+}
+
+func (c *converter) applyUpgradingKymaOperations(dto *pkg.RuntimeDTO, oprs []internal.Operation, totalCount int) {
 	dto.Status.UpgradingKyma.TotalCount = totalCount
 	dto.Status.UpgradingKyma.Count = len(oprs)
 	dto.Status.UpgradingKyma.Data = make([]pkg.Operation, 0)
-	for _, o := range oprs {
+	for i := range oprs {
 		op := pkg.Operation{}
-		c.applyOperation(&o.Operation, &op)
+		c.applyOperation(&oprs[i], &op)
 		dto.Status.UpgradingKyma.Data = append(dto.Status.UpgradingKyma.Data, op)
 	}
 }
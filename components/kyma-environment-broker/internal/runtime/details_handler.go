@@ -0,0 +1,235 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+
+	gardenerapi "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	gardenerclient "github.com/gardener/gardener/pkg/client/core/clientset/versioned/typed/core/v1beta1"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RuntimeStatusProvider returns the Runtime status known to the Runtime Provisioner
+type RuntimeStatusProvider interface {
+	RuntimeStatus(ctx context.Context, accountID, runtimeID string) (gqlschema.RuntimeStatus, error)
+}
+
+// DetailsHandler exposes a single Runtime with all its operations, the latest Runtime state and the status
+// fetched live from the Runtime Provisioner and Gardener. The live sources are queried with a timeout,
+// when one of them fails the response contains the error instead of its status.
+// The provisioner request is cancelled on timeout. The Gardener client does not accept a context,
+// so the given shoot client is expected to be configured with the request timeout.
+type DetailsHandler struct {
+	instancesDb     storage.Instances
+	operationsDb    storage.Operations
+	runtimeStatesDb storage.RuntimeStates
+	provisioner     RuntimeStatusProvider
+	shoots          gardenerclient.ShootInterface
+	converter       Converter
+
+	liveStatusTimeout time.Duration
+	log               logrus.FieldLogger
+}
+
+func NewDetailsHandler(instanceDb storage.Instances, operationDb storage.Operations, runtimeStatesDb storage.RuntimeStates,
+	provisioner RuntimeStatusProvider, shoots gardenerclient.ShootInterface, liveStatusTimeout time.Duration,
+	defaultRequestRegion string, log logrus.FieldLogger) *DetailsHandler {
+	return &DetailsHandler{
+		instancesDb:       instanceDb,
+		operationsDb:      operationDb,
+		runtimeStatesDb:   runtimeStatesDb,
+		provisioner:       provisioner,
+		shoots:            shoots,
+		converter:         NewConverter(defaultRequestRegion),
+		liveStatusTimeout: liveStatusTimeout,
+		log:               log,
+	}
+}
+
+func (h *DetailsHandler) AttachRoutes(router *mux.Router) {
+	router.HandleFunc("/runtimes/{runtime_id}", h.getRuntime).Methods(http.MethodGet)
+}
+
+func (h *DetailsHandler) getRuntime(w http.ResponseWriter, req *http.Request) {
+	runtimeID := mux.Vars(req)["runtime_id"]
+
+	instances, _, _, err := h.instancesDb.ListWithOperations(dbmodel.RuntimeFilter{
+		InstanceFilter: dbmodel.InstanceFilter{RuntimeIDs: []string{runtimeID}},
+	})
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching instance"))
+		return
+	}
+	if len(instances) == 0 {
+		httputil.WriteErrorResponse(w, http.StatusNotFound, errors.Errorf("runtime %s not found", runtimeID))
+		return
+	}
+	instance := instances[0]
+
+	dto, err := h.converter.NewDTO(instance.Instance)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while converting instance to DTO"))
+		return
+	}
+	h.converter.ApplyInstanceOperations(&dto, instance)
+
+	// the listing returns only the latest upgrades, the details contain all of them
+	upgrades, err := h.operationsDb.ListUpgradeKymaOperationsByInstanceID(instance.InstanceID)
+	if err != nil && !dberr.IsNotFound(err) {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching upgrade kyma operations for instance"))
+		return
+	}
+	upgrades = withoutDryRun(upgrades)
+	h.converter.ApplyUpgradingKymaOperations(&dto, upgrades, len(upgrades))
+
+	details := pkg.RuntimeDetailsDTO{RuntimeDTO: dto}
+
+	states, err := h.runtimeStatesDb.ListByRuntimeID(runtimeID)
+	if err != nil && !dberr.IsNotFound(err) {
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching runtime states"))
+		return
+	}
+	if len(states) > 0 {
+		details.RuntimeConfig = toRuntimeConfig(states[len(states)-1])
+	}
+
+	shootName := dto.ShootName
+	pOpr, err := h.operationsDb.GetProvisioningOperationByInstanceID(instance.InstanceID)
+	switch {
+	case err == nil && pOpr.ShootName != "":
+		shootName = pOpr.ShootName
+	case err != nil && !dberr.IsNotFound(err):
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching provisioning operation for instance"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), h.liveStatusTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		details.Provisioner = h.provisionerStatus(ctx, instance.GlobalAccountID, runtimeID)
+	}()
+	go func() {
+		defer wg.Done()
+		details.Gardener = h.gardenerStatus(ctx, shootName)
+	}()
+	wg.Wait()
+
+	httputil.WriteResponse(w, http.StatusOK, details)
+}
+
+func (h *DetailsHandler) provisionerStatus(ctx context.Context, globalAccountID, runtimeID string) pkg.ProvisionerStatus {
+	runtimeStatus, err := h.provisioner.RuntimeStatus(ctx, globalAccountID, runtimeID)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", h.liveStatusTimeout)
+	}
+	if err != nil {
+		h.log.Warnf("while fetching status of runtime %s from provisioner: %s", runtimeID, err)
+		return pkg.ProvisionerStatus{Error: err.Error()}
+	}
+
+	status := pkg.ProvisionerStatus{}
+	if op := runtimeStatus.LastOperationStatus; op != nil {
+		status.LastOperation = &pkg.ProvisionerOperation{
+			ID:      ptr.ToString(op.ID),
+			Type:    string(op.Operation),
+			State:   string(op.State),
+			Message: ptr.ToString(op.Message),
+		}
+	}
+	if conn := runtimeStatus.RuntimeConnectionStatus; conn != nil {
+		status.AgentConnectionStatus = string(conn.Status)
+	}
+	return status
+}
+
+func (h *DetailsHandler) gardenerStatus(ctx context.Context, shootName string) pkg.GardenerStatus {
+	if shootName == "" {
+		return pkg.GardenerStatus{Error: "shoot name is unknown"}
+	}
+	type result struct {
+		shoot *gardenerapi.Shoot
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		shoot, err := h.shoots.Get(shootName, metav1.GetOptions{})
+		done <- result{shoot: shoot, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.err = fmt.Errorf("timed out after %s", h.liveStatusTimeout)
+	}
+	if res.err != nil {
+		h.log.Warnf("while fetching shoot %s from gardener: %s", shootName, res.err)
+		return pkg.GardenerStatus{ShootName: shootName, Error: res.err.Error()}
+	}
+
+	status := pkg.GardenerStatus{ShootName: shootName}
+	if op := res.shoot.Status.LastOperation; op != nil {
+		status.LastOperation = &pkg.GardenerOperation{
+			Type:           string(op.Type),
+			State:          string(op.State),
+			Progress:       int(op.Progress),
+			Description:    op.Description,
+			LastUpdateTime: op.LastUpdateTime.Time,
+		}
+	}
+	for _, c := range res.shoot.Status.Conditions {
+		status.Conditions = append(status.Conditions, pkg.GardenerCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+	return status
+}
+
+func toRuntimeConfig(state internal.RuntimeState) *pkg.RuntimeConfigDTO {
+	config := &pkg.RuntimeConfigDTO{
+		KymaVersion:       state.KymaConfig.Version,
+		Provider:          state.ClusterConfig.Provider,
+		Region:            state.ClusterConfig.Region,
+		KubernetesVersion: state.ClusterConfig.KubernetesVersion,
+		MachineType:       state.ClusterConfig.MachineType,
+		AutoScalerMin:     state.ClusterConfig.AutoScalerMin,
+		AutoScalerMax:     state.ClusterConfig.AutoScalerMax,
+	}
+	if state.KymaConfig.Profile != nil {
+		config.KymaProfile = string(*state.KymaConfig.Profile)
+	}
+	return config
+}
+
+func withoutDryRun(operations []internal.UpgradeKymaOperation) []internal.UpgradeKymaOperation {
+	result := make([]internal.UpgradeKymaOperation, 0, len(operations))
+	for _, op := range operations {
+		if !op.DryRun {
+			result = append(result, op)
+		}
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package runtime_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+
+	gardenerapi "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	gardenerFake "github.com/gardener/gardener/pkg/client/core/clientset/versioned/fake"
+	gardenerclient "github.com/gardener/gardener/pkg/client/core/clientset/versioned/typed/core/v1beta1"
+	"github.com/gorilla/mux"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	detailsRuntimeID = "runtime-1"
+	detailsShootName = "c-1234567"
+	gardenerNS       = "garden-kyma"
+)
+
+func TestDetailsHandler(t *testing.T) {
+	t.Run("should return runtime with operations, config and live status", func(t *testing.T) {
+		// given
+		db := fixDetailsStorage(t)
+		provisioner := &provisionerStub{status: gqlschema.RuntimeStatus{
+			LastOperationStatus: &gqlschema.OperationStatus{
+				ID:        ptr.String("provisioner-op"),
+				Operation: gqlschema.OperationTypeUpgrade,
+				State:     gqlschema.OperationStateSucceeded,
+			},
+			RuntimeConnectionStatus: &gqlschema.RuntimeConnectionStatus{Status: gqlschema.RuntimeAgentConnectionStatusConnected},
+		}}
+		shoots := gardenerFake.NewSimpleClientset(&gardenerapi.Shoot{
+			ObjectMeta: metav1.ObjectMeta{Name: detailsShootName, Namespace: gardenerNS},
+			Status: gardenerapi.ShootStatus{
+				LastOperation: &gardenerapi.LastOperation{Type: gardenerapi.LastOperationTypeReconcile, State: gardenerapi.LastOperationStateSucceeded, Progress: 100},
+				Conditions:    []gardenerapi.Condition{{Type: gardenerapi.ShootAPIServerAvailable, Status: gardenerapi.ConditionTrue}},
+			},
+		}).CoreV1beta1().Shoots(gardenerNS)
+		router := fixDetailsRouter(db, provisioner, shoots, time.Second)
+
+		// when
+		details := getRuntimeDetails(t, router, detailsRuntimeID, http.StatusOK)
+
+		// then
+		assert.Equal(t, "instance-1", details.InstanceID)
+		assert.Equal(t, pkg.StateSucceeded, details.Status.State)
+		assert.Equal(t, "1.18.0", details.KymaVersion)
+		assert.Equal(t, "provisioning-1", details.Status.Provisioning.OperationID)
+		assert.Equal(t, 3, details.Status.UpgradingKyma.TotalCount)
+		require.Len(t, details.Status.UpgradingKyma.Data, 3)
+		assert.Equal(t, "upgrade-3", details.Status.UpgradingKyma.Data[0].OperationID)
+
+		require.NotNil(t, details.RuntimeConfig)
+		assert.Equal(t, "1.18.0", details.RuntimeConfig.KymaVersion)
+		assert.Equal(t, "azure", details.RuntimeConfig.Provider)
+
+		assert.Empty(t, details.Provisioner.Error)
+		require.NotNil(t, details.Provisioner.LastOperation)
+		assert.Equal(t, "provisioner-op", details.Provisioner.LastOperation.ID)
+		assert.Equal(t, string(gqlschema.RuntimeAgentConnectionStatusConnected), details.Provisioner.AgentConnectionStatus)
+
+		assert.Empty(t, details.Gardener.Error)
+		assert.Equal(t, detailsShootName, details.Gardener.ShootName)
+		require.NotNil(t, details.Gardener.LastOperation)
+		assert.Equal(t, 100, details.Gardener.LastOperation.Progress)
+		require.Len(t, details.Gardener.Conditions, 1)
+		assert.Equal(t, string(gardenerapi.ConditionTrue), details.Gardener.Conditions[0].Status)
+	})
+
+	t.Run("should degrade when live sources fail or time out", func(t *testing.T) {
+		// given
+		db := fixDetailsStorage(t)
+		provisioner := &provisionerStub{delay: time.Minute}
+		// the shoot does not exist
+		shoots := gardenerFake.NewSimpleClientset().CoreV1beta1().Shoots(gardenerNS)
+		router := fixDetailsRouter(db, provisioner, shoots, 10*time.Millisecond)
+
+		// when
+		details := getRuntimeDetails(t, router, detailsRuntimeID, http.StatusOK)
+
+		// then
+		assert.Equal(t, "instance-1", details.InstanceID)
+		assert.Contains(t, details.Provisioner.Error, "timed out")
+		assert.Nil(t, details.Provisioner.LastOperation)
+		assert.True(t, provisioner.cancelled)
+		assert.Equal(t, detailsShootName, details.Gardener.ShootName)
+		assert.NotEmpty(t, details.Gardener.Error)
+	})
+
+	t.Run("should return provisioner error", func(t *testing.T) {
+		// given
+		db := fixDetailsStorage(t)
+		provisioner := &provisionerStub{err: errors.New("connection refused")}
+		shoots := gardenerFake.NewSimpleClientset().CoreV1beta1().Shoots(gardenerNS)
+		router := fixDetailsRouter(db, provisioner, shoots, time.Second)
+
+		// when
+		details := getRuntimeDetails(t, router, detailsRuntimeID, http.StatusOK)
+
+		// then
+		assert.Equal(t, "connection refused", details.Provisioner.Error)
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		shoots := gardenerFake.NewSimpleClientset().CoreV1beta1().Shoots(gardenerNS)
+		router := fixDetailsRouter(db, &provisionerStub{}, shoots, time.Second)
+
+		// then
+		getRuntimeDetails(t, router, "not-existing", http.StatusNotFound)
+	})
+}
+
+type provisionerStub struct {
+	status    gqlschema.RuntimeStatus
+	err       error
+	delay     time.Duration
+	cancelled bool
+}
+
+func (p *provisionerStub) RuntimeStatus(ctx context.Context, _, _ string) (gqlschema.RuntimeStatus, error) {
+	select {
+	case <-time.After(p.delay):
+		return p.status, p.err
+	case <-ctx.Done():
+		p.cancelled = true
+		return gqlschema.RuntimeStatus{}, ctx.Err()
+	}
+}
+
+func fixDetailsStorage(t *testing.T) storage.BrokerStorage {
+	db := storage.NewMemoryStorage()
+	now := time.Now()
+
+	instance := fixInstance("instance-1", now.Add(-time.Hour))
+	instance.RuntimeID = detailsRuntimeID
+	require.NoError(t, db.Instances().Insert(instance))
+
+	provisioning := fixProvisioningOperation("provisioning-1", instance.InstanceID, domain.Succeeded, "1.16.0", now.Add(-time.Hour))
+	provisioning.ShootName = detailsShootName
+	require.NoError(t, db.Operations().InsertProvisioningOperation(provisioning))
+	require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation("upgrade-1", instance.InstanceID, domain.Failed, "1.17.0", false, now.Add(-50*time.Minute))))
+	require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation("upgrade-2", instance.InstanceID, domain.Succeeded, "1.17.0", false, now.Add(-40*time.Minute))))
+	require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation("upgrade-3", instance.InstanceID, domain.Succeeded, "1.18.0", false, now.Add(-30*time.Minute))))
+	require.NoError(t, db.Operations().InsertUpgradeKymaOperation(fixUpgradeKymaOperation("upgrade-4", instance.InstanceID, domain.Succeeded, "1.19.0", true, now.Add(-20*time.Minute))))
+
+	require.NoError(t, db.RuntimeStates().Insert(internal.RuntimeState{
+		ID:            "state-1",
+		RuntimeID:     detailsRuntimeID,
+		OperationID:   "upgrade-3",
+		CreatedAt:     now.Add(-30 * time.Minute),
+		KymaConfig:    gqlschema.KymaConfigInput{Version: "1.18.0"},
+		ClusterConfig: gqlschema.GardenerConfigInput{Provider: "azure", Region: "westeurope"},
+	}))
+
+	return db
+}
+
+func fixDetailsRouter(db storage.BrokerStorage, provisioner runtime.RuntimeStatusProvider, shoots gardenerclient.ShootInterface, timeout time.Duration) *mux.Router {
+	handler := runtime.NewDetailsHandler(db.Instances(), db.Operations(), db.RuntimeStates(), provisioner,
+		shoots, timeout, "", logrus.New())
+	router := mux.NewRouter()
+	handler.AttachRoutes(router)
+	return router
+}
+
+func getRuntimeDetails(t *testing.T, router *mux.Router, runtimeID string, expectedCode int) pkg.RuntimeDetailsDTO {
+	req, err := http.NewRequest(http.MethodGet, "/runtimes/"+runtimeID, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, expectedCode, rr.Code)
+	var out pkg.RuntimeDetailsDTO
+	if expectedCode == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	}
+	return out
+}
@@ -1,14 +1,14 @@
 package runtime
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
 	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
-	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
-	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
 
 	"github.com/gorilla/mux"
@@ -17,18 +17,26 @@ import (
 
 const numberOfUpgradeOperationsToReturn = 2
 
+var sortFields = map[string]dbmodel.RuntimeSortField{
+	pkg.SortByCreatedAt:       dbmodel.RuntimeSortByCreatedAt,
+	pkg.SortByGlobalAccountID: dbmodel.RuntimeSortByGlobalAccountID,
+	pkg.SortBySubAccountID:    dbmodel.RuntimeSortBySubAccountID,
+	pkg.SortByRegion:          dbmodel.RuntimeSortByRegion,
+	pkg.SortByPlan:            dbmodel.RuntimeSortByPlan,
+	pkg.SortByState:           dbmodel.RuntimeSortByState,
+	pkg.SortByKymaVersion:     dbmodel.RuntimeSortByKymaVersion,
+}
+
 type Handler struct {
-	instancesDb  storage.Instances
-	operationsDb storage.Operations
-	converter    Converter
+	instancesDb storage.Instances
+	converter   Converter
 
 	defaultMaxPage int
 }
 
-func NewHandler(instanceDb storage.Instances, operationDb storage.Operations, defaultMaxPage int, defaultRequestRegion string) *Handler {
+func NewHandler(instanceDb storage.Instances, defaultMaxPage int, defaultRequestRegion string) *Handler {
 	return &Handler{
 		instancesDb:    instanceDb,
-		operationsDb:   operationDb,
 		converter:      NewConverter(defaultRequestRegion),
 		defaultMaxPage: defaultMaxPage,
 	}
@@ -46,44 +54,28 @@ func (h *Handler) getRuntimes(w http.ResponseWriter, req *http.Request) {
 		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrap(err, "while getting query parameters"))
 		return
 	}
-	filter := h.getFilters(req)
+	filter, err := h.getFilters(req)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrap(err, "while getting query parameters"))
+		return
+	}
 	filter.PageSize = pageSize
 	filter.Page = page
+	filter.UpgradeKymaLimit = numberOfUpgradeOperationsToReturn
 
-	instances, count, totalCount, err := h.instancesDb.List(filter)
+	instances, count, totalCount, err := h.instancesDb.ListWithOperations(filter)
 	if err != nil {
 		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching instances"))
 		return
 	}
 
 	for _, instance := range instances {
-		dto, err := h.converter.NewDTO(instance)
+		dto, err := h.converter.NewDTO(instance.Instance)
 		if err != nil {
 			httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while converting instance to DTO"))
 			return
 		}
-
-		pOpr, err := h.operationsDb.GetProvisioningOperationByInstanceID(instance.InstanceID)
-		if err != nil && !dberr.IsNotFound(err) {
-			httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching provisioning operation for instance"))
-			return
-		}
-		h.converter.ApplyProvisioningOperation(&dto, pOpr)
-
-		dOpr, err := h.operationsDb.GetDeprovisioningOperationByInstanceID(instance.InstanceID)
-		if err != nil && !dberr.IsNotFound(err) {
-			httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching deprovisioning operation for instance"))
-			return
-		}
-		h.converter.ApplyDeprovisioningOperation(&dto, dOpr)
-
-		ukOprs, err := h.operationsDb.ListUpgradeKymaOperationsByInstanceID(instance.InstanceID)
-		if err != nil && !dberr.IsNotFound(err) {
-			httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while fetching upgrade kyma operation for instance"))
-			return
-		}
-		ukOprs, totalCount := h.takeLastNonDryRunOperations(ukOprs)
-		h.converter.ApplyUpgradingKymaOperations(&dto, ukOprs, totalCount)
+		h.converter.ApplyInstanceOperations(&dto, instance)
 
 		toReturn = append(toReturn, dto)
 	}
@@ -96,23 +88,8 @@ func (h *Handler) getRuntimes(w http.ResponseWriter, req *http.Request) {
 	httputil.WriteResponse(w, http.StatusOK, runtimePage)
 }
 
-func (h *Handler) takeLastNonDryRunOperations(oprs []internal.UpgradeKymaOperation) ([]internal.UpgradeKymaOperation, int) {
-	toReturn := make([]internal.UpgradeKymaOperation, 0)
-	totalCount := 0
-	for _, op := range oprs {
-		if op.DryRun {
-			continue
-		}
-		if len(toReturn) < numberOfUpgradeOperationsToReturn {
-			toReturn = append(toReturn, op)
-		}
-		totalCount = totalCount + 1
-	}
-	return toReturn, totalCount
-}
-
-func (h *Handler) getFilters(req *http.Request) dbmodel.InstanceFilter {
-	var filter dbmodel.InstanceFilter
+func (h *Handler) getFilters(req *http.Request) (dbmodel.RuntimeFilter, error) {
+	var filter dbmodel.RuntimeFilter
 	query := req.URL.Query()
 	// For optional filter, zero value (nil) is fine if not supplied
 	filter.GlobalAccountIDs = query[pkg.GlobalAccountIDParam]
@@ -122,6 +99,48 @@ func (h *Handler) getFilters(req *http.Request) dbmodel.InstanceFilter {
 	filter.Regions = query[pkg.RegionParam]
 	filter.Domains = query[pkg.ShootParam]
 	filter.Plans = query[pkg.PlanParam]
+	filter.States = query[pkg.StateParam]
+	filter.KymaVersions = query[pkg.KymaVersionParam]
+	filter.Search = query.Get(pkg.SearchParam)
+
+	for _, state := range filter.States {
+		switch state {
+		case pkg.StateSucceeded, pkg.StateFailed, pkg.StateInProgress, pkg.StateSuspended:
+		default:
+			return filter, fmt.Errorf("unsupported %s value %q", pkg.StateParam, state)
+		}
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseTime(query.Get(pkg.CreatedFromParam)); err != nil {
+		return filter, errors.Wrapf(err, "while parsing %s", pkg.CreatedFromParam)
+	}
+	if filter.CreatedTo, err = parseTime(query.Get(pkg.CreatedToParam)); err != nil {
+		return filter, errors.Wrapf(err, "while parsing %s", pkg.CreatedToParam)
+	}
+
+	if sort := query.Get(pkg.SortParam); sort != "" {
+		field, ok := sortFields[sort]
+		if !ok {
+			return filter, fmt.Errorf("unsupported %s value %q", pkg.SortParam, sort)
+		}
+		filter.SortBy = field
+	}
+	switch order := query.Get(pkg.OrderParam); order {
+	case "", pkg.OrderAsc:
+	case pkg.OrderDesc:
+		filter.SortDesc = true
+	default:
+		return filter, fmt.Errorf("unsupported %s value %q", pkg.OrderParam, order)
+	}
+
+	return filter, nil
+}
 
-	return filter
+// parseTime parses RFC3339 time, empty value is returned as zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
 }
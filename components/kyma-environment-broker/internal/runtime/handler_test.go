@@ -5,15 +5,18 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime"
 
 	"github.com/gorilla/mux"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/driver/memory"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,7 +48,7 @@ func TestRuntimeHandler(t *testing.T) {
 		err = instances.Insert(testInstance2)
 		require.NoError(t, err)
 
-		runtimeHandler := runtime.NewHandler(instances, operations, 2, "")
+		runtimeHandler := runtime.NewHandler(instances, 2, "")
 
 		req, err := http.NewRequest("GET", "/runtimes?page_size=1", nil)
 		require.NoError(t, err)
@@ -95,7 +98,7 @@ func TestRuntimeHandler(t *testing.T) {
 		operations := memory.NewOperation()
 		instances := memory.NewInstance(operations)
 
-		runtimeHandler := runtime.NewHandler(instances, operations, 2, "region")
+		runtimeHandler := runtime.NewHandler(instances, 2, "region")
 
 		req, err := http.NewRequest("GET", "/runtimes?page_size=a", nil)
 		require.NoError(t, err)
@@ -141,7 +144,7 @@ func TestRuntimeHandler(t *testing.T) {
 		err = instances.Insert(testInstance2)
 		require.NoError(t, err)
 
-		runtimeHandler := runtime.NewHandler(instances, operations, 2, "")
+		runtimeHandler := runtime.NewHandler(instances, 2, "")
 
 		req, err := http.NewRequest("GET", fmt.Sprintf("/runtimes?account=%s&subaccount=%s&instance_id=%s&runtime_id=%s&region=%s&shoot=%s", testID1, testID1, testID1, testID1, testID1, testID1), nil)
 		require.NoError(t, err)
@@ -167,6 +170,172 @@ func TestRuntimeHandler(t *testing.T) {
 	})
 }
 
+func TestRuntimeHandlerWithOperations(t *testing.T) {
+	// given
+	operations := memory.NewOperation()
+	instances := memory.NewInstance(operations)
+	now := time.Now().Truncate(time.Second)
+
+	// provisioned and upgraded twice, the dry run upgrade is not taken into account
+	upgraded := fixInstance("upgraded", now.Add(-3*time.Hour))
+	upgraded.GlobalAccountID = "ga-1"
+	require.NoError(t, instances.Insert(upgraded))
+	require.NoError(t, operations.InsertProvisioningOperation(fixProvisioningOperation("p-1", upgraded.InstanceID, domain.Succeeded, "1.16.0", now.Add(-3*time.Hour))))
+	require.NoError(t, operations.InsertUpgradeKymaOperation(fixUpgradeKymaOperation("u-1", upgraded.InstanceID, domain.Succeeded, "1.17.0", false, now.Add(-2*time.Hour))))
+	require.NoError(t, operations.InsertUpgradeKymaOperation(fixUpgradeKymaOperation("u-2", upgraded.InstanceID, domain.Succeeded, "1.18.0", false, now.Add(-time.Hour))))
+	require.NoError(t, operations.InsertUpgradeKymaOperation(fixUpgradeKymaOperation("u-3", upgraded.InstanceID, domain.Failed, "1.19.0", true, now.Add(-time.Minute))))
+
+	// provisioning failed
+	failed := fixInstance("failed", now.Add(-2*time.Hour))
+	failed.GlobalAccountID = "ga-2"
+	require.NoError(t, instances.Insert(failed))
+	require.NoError(t, operations.InsertProvisioningOperation(fixProvisioningOperation("p-2", failed.InstanceID, domain.Failed, "1.17.0", now.Add(-2*time.Hour))))
+
+	// deprovisioned
+	suspended := fixInstance("suspended", now.Add(-time.Hour))
+	suspended.GlobalAccountID = "ga-3"
+	require.NoError(t, instances.Insert(suspended))
+	require.NoError(t, operations.InsertProvisioningOperation(fixProvisioningOperation("p-3", suspended.InstanceID, domain.Succeeded, "1.17.0", now.Add(-time.Hour))))
+	require.NoError(t, operations.InsertDeprovisioningOperation(fixDeprovisioningOperation("d-3", suspended.InstanceID, domain.Succeeded, now.Add(-time.Minute))))
+
+	runtimeHandler := runtime.NewHandler(instances, 10, "")
+	router := mux.NewRouter()
+	runtimeHandler.AttachRoutes(router)
+
+	t.Run("should return state, Kyma version and operations", func(t *testing.T) {
+		// when
+		out := getRuntimes(t, router, "/runtimes", http.StatusOK)
+
+		// then
+		require.Len(t, out.Data, 3)
+		assert.Equal(t, upgraded.InstanceID, out.Data[0].InstanceID)
+		assert.Equal(t, pkg.StateSucceeded, out.Data[0].Status.State)
+		assert.Equal(t, "1.18.0", out.Data[0].KymaVersion)
+		assert.Equal(t, "p-1", out.Data[0].Status.Provisioning.OperationID)
+		assert.Nil(t, out.Data[0].Status.Deprovisioning)
+		assert.Equal(t, 2, out.Data[0].Status.UpgradingKyma.TotalCount)
+		require.Len(t, out.Data[0].Status.UpgradingKyma.Data, 2)
+		assert.Equal(t, "u-2", out.Data[0].Status.UpgradingKyma.Data[0].OperationID)
+		assert.Equal(t, "u-1", out.Data[0].Status.UpgradingKyma.Data[1].OperationID)
+
+		assert.Equal(t, failed.InstanceID, out.Data[1].InstanceID)
+		assert.Equal(t, pkg.StateFailed, out.Data[1].Status.State)
+		assert.Empty(t, out.Data[1].KymaVersion)
+
+		assert.Equal(t, suspended.InstanceID, out.Data[2].InstanceID)
+		assert.Equal(t, pkg.StateSuspended, out.Data[2].Status.State)
+		assert.Equal(t, "1.17.0", out.Data[2].KymaVersion)
+		require.NotNil(t, out.Data[2].Status.Deprovisioning)
+		assert.Equal(t, "d-3", out.Data[2].Status.Deprovisioning.OperationID)
+	})
+
+	for name, tc := range map[string]struct {
+		query       string
+		expectedIDs []string
+	}{
+		"by state": {
+			query:       "state=failed&state=suspended",
+			expectedIDs: []string{failed.InstanceID, suspended.InstanceID},
+		},
+		"by Kyma version": {
+			query:       "kyma_version=1.17.0",
+			expectedIDs: []string{suspended.InstanceID},
+		},
+		"by creation date range": {
+			query: fmt.Sprintf("created_from=%s&created_to=%s",
+				url.QueryEscape(now.Add(-150*time.Minute).Format(time.RFC3339)),
+				url.QueryEscape(now.Add(-90*time.Minute).Format(time.RFC3339))),
+			expectedIDs: []string{failed.InstanceID},
+		},
+		"by shoot name search": {
+			query:       "search=SUSP",
+			expectedIDs: []string{suspended.InstanceID},
+		},
+		"by global account search": {
+			query:       "search=ga-",
+			expectedIDs: []string{upgraded.InstanceID, failed.InstanceID, suspended.InstanceID},
+		},
+		"sorted by state descending": {
+			query:       "sort=state&order=desc",
+			expectedIDs: []string{suspended.InstanceID, upgraded.InstanceID, failed.InstanceID},
+		},
+		"sorted by creation time descending with pagination": {
+			query:       "order=desc&page=2&page_size=2",
+			expectedIDs: []string{upgraded.InstanceID},
+		},
+	} {
+		t.Run(fmt.Sprintf("should filter %s", name), func(t *testing.T) {
+			// when
+			out := getRuntimes(t, router, "/runtimes?"+tc.query, http.StatusOK)
+
+			// then
+			ids := make([]string, 0)
+			for _, r := range out.Data {
+				ids = append(ids, r.InstanceID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+
+	for _, query := range []string{
+		"state=unknown",
+		"created_from=yesterday",
+		"created_to=2020-12-01",
+		"sort=shoot",
+		"order=random",
+	} {
+		t.Run(fmt.Sprintf("should reject %s", query), func(t *testing.T) {
+			getRuntimes(t, router, "/runtimes?"+query, http.StatusBadRequest)
+		})
+	}
+}
+
+func getRuntimes(t *testing.T, router *mux.Router, path string, expectedCode int) pkg.RuntimesPage {
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, expectedCode, rr.Code)
+	var out pkg.RuntimesPage
+	if expectedCode == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	}
+	return out
+}
+
+func fixProvisioningOperation(id, instanceID string, state domain.LastOperationState, kymaVersion string, createdAt time.Time) internal.ProvisioningOperation {
+	return internal.ProvisioningOperation{
+		Operation:      fixOperation(id, instanceID, state, createdAt),
+		RuntimeVersion: internal.RuntimeVersionData{Version: kymaVersion},
+	}
+}
+
+func fixDeprovisioningOperation(id, instanceID string, state domain.LastOperationState, createdAt time.Time) internal.DeprovisioningOperation {
+	return internal.DeprovisioningOperation{
+		Operation: fixOperation(id, instanceID, state, createdAt),
+	}
+}
+
+func fixUpgradeKymaOperation(id, instanceID string, state domain.LastOperationState, kymaVersion string, dryRun bool, createdAt time.Time) internal.UpgradeKymaOperation {
+	return internal.UpgradeKymaOperation{
+		Operation:        fixOperation(id, instanceID, state, createdAt),
+		RuntimeOperation: orchestration.RuntimeOperation{ID: id, DryRun: dryRun},
+		RuntimeVersion:   internal.RuntimeVersionData{Version: kymaVersion},
+	}
+}
+
+func fixOperation(id, instanceID string, state domain.LastOperationState, createdAt time.Time) internal.Operation {
+	return internal.Operation{
+		ID:         id,
+		InstanceID: instanceID,
+		State:      state,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+}
+
 func fixInstance(id string, t time.Time) internal.Instance {
 	return internal.Instance{
 		InstanceID:             id,
@@ -17,7 +17,11 @@ type Config struct {
 	Name     string `envconfig:"default=broker"`
 	SSLMode  string `envconfig:"default=disable"`
 
+	// SecretKey is used to decrypt values encrypted before the key rotation was introduced.
+	// If SecretKeysFilePath is not set, it is also used to encrypt new values.
 	SecretKey string `envconfig:"optional"`
+	// SecretKeysFilePath points to the YAML list of versioned keys, the last key is used to encrypt new values
+	SecretKeysFilePath string `envconfig:"optional"`
 
 	MaxOpenConns    int           `envconfig:"default=8"`
 	MaxIdleConns    int           `envconfig:"default=2"`
@@ -0,0 +1,755 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStorageConformance(t *testing.T) {
+	runConformanceSuite(t, func(t *testing.T) BrokerStorage {
+		return NewMemoryStorage()
+	})
+}
+
+// runConformanceSuite verifies the behaviour which every BrokerStorage driver must provide,
+// newStorage must return an empty storage for every test
+func runConformanceSuite(t *testing.T, newStorage func(t *testing.T) BrokerStorage) {
+	t.Run("Instances", func(t *testing.T) {
+		t.Run("should insert, update and delete instance", func(t *testing.T) {
+			// given
+			instances := newStorage(t).Instances()
+			instance := fixConformanceInstance("instance-1", "ga-1", "azure", "eu-west")
+
+			// when
+			require.NoError(t, instances.Insert(instance))
+			instance.DashboardURL = "https://console.updated.kyma.local"
+			require.NoError(t, instances.Update(instance))
+
+			// then
+			got, err := instances.GetByID(instance.InstanceID)
+			require.NoError(t, err)
+			assert.Equal(t, instance.DashboardURL, got.DashboardURL)
+			assert.Equal(t, instance.GlobalAccountID, got.GlobalAccountID)
+			assert.False(t, got.CreatedAt.IsZero())
+			assert.False(t, got.UpdatedAt.IsZero())
+			assert.True(t, got.DeletedAt.IsZero())
+
+			// when
+			require.NoError(t, instances.Delete(instance.InstanceID))
+
+			// then
+			_, err = instances.GetByID(instance.InstanceID)
+			assert.True(t, dberr.IsNotFound(err))
+			assert.NoError(t, instances.Delete(instance.InstanceID), "deletion of not existing instance must not fail")
+		})
+
+		t.Run("should reject duplicated instance", func(t *testing.T) {
+			// given
+			instances := newStorage(t).Instances()
+			instance := fixConformanceInstance("instance-1", "ga-1", "azure", "eu-west")
+			require.NoError(t, instances.Insert(instance))
+
+			// when
+			err := instances.Insert(instance)
+
+			// then
+			assert.True(t, dberr.IsAlreadyExists(err))
+		})
+
+		t.Run("should not update not existing instance", func(t *testing.T) {
+			// given
+			instances := newStorage(t).Instances()
+
+			// when
+			err := instances.Update(fixConformanceInstance("not-existing", "ga-1", "azure", "eu-west"))
+
+			// then
+			assert.True(t, dberr.IsNotFound(err))
+			_, err = instances.GetByID("not-existing")
+			assert.True(t, dberr.IsNotFound(err))
+		})
+
+		t.Run("should list instances page by page", func(t *testing.T) {
+			// given
+			instances := newStorage(t).Instances()
+			for i := 1; i <= 5; i++ {
+				require.NoError(t, instances.Insert(fixConformanceInstance(fmt.Sprintf("instance-%d", i), "ga-1", "azure", "eu-west")))
+			}
+
+			// when
+			page1, count1, total1, err1 := instances.List(dbmodel.InstanceFilter{Page: 1, PageSize: 2})
+			page2, count2, total2, err2 := instances.List(dbmodel.InstanceFilter{Page: 2, PageSize: 2})
+			page3, count3, total3, err3 := instances.List(dbmodel.InstanceFilter{Page: 3, PageSize: 2})
+
+			// then
+			require.NoError(t, err1)
+			require.NoError(t, err2)
+			require.NoError(t, err3)
+			assert.Equal(t, []int{2, 2, 1}, []int{count1, count2, count3})
+			assert.Equal(t, []int{5, 5, 5}, []int{total1, total2, total3})
+			assert.Equal(t, []string{"instance-1", "instance-2"}, instanceIDs(page1))
+			assert.Equal(t, []string{"instance-3", "instance-4"}, instanceIDs(page2))
+			assert.Equal(t, []string{"instance-5"}, instanceIDs(page3))
+		})
+
+		t.Run("should list instances matching filters", func(t *testing.T) {
+			// given
+			instances := newStorage(t).Instances()
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-1", "ga-1", "azure", "eu-west")))
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-2", "ga-1", "gcp", "eu-west")))
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-3", "ga-2", "azure", "us-east")))
+
+			for name, tc := range map[string]struct {
+				filter   dbmodel.InstanceFilter
+				expected []string
+			}{
+				"global accounts": {filter: dbmodel.InstanceFilter{GlobalAccountIDs: []string{"ga-1"}}, expected: []string{"instance-1", "instance-2"}},
+				"sub accounts":    {filter: dbmodel.InstanceFilter{SubAccountIDs: []string{"sa-instance-3"}}, expected: []string{"instance-3"}},
+				"instance IDs":    {filter: dbmodel.InstanceFilter{InstanceIDs: []string{"instance-1", "instance-3"}}, expected: []string{"instance-1", "instance-3"}},
+				"runtime IDs":     {filter: dbmodel.InstanceFilter{RuntimeIDs: []string{"runtime-instance-2"}}, expected: []string{"instance-2"}},
+				"plans":           {filter: dbmodel.InstanceFilter{Plans: []string{"azure"}}, expected: []string{"instance-1", "instance-3"}},
+				"regions":         {filter: dbmodel.InstanceFilter{Regions: []string{"us-east"}}, expected: []string{"instance-3"}},
+				"domains":         {filter: dbmodel.InstanceFilter{Domains: []string{"instance-2"}}, expected: []string{"instance-2"}},
+				"combined":        {filter: dbmodel.InstanceFilter{GlobalAccountIDs: []string{"ga-1"}, Plans: []string{"azure"}}, expected: []string{"instance-1"}},
+				"no match":        {filter: dbmodel.InstanceFilter{Regions: []string{"ap-south"}}, expected: []string{}},
+			} {
+				t.Run(name, func(t *testing.T) {
+					// when
+					out, count, totalCount, err := instances.List(tc.filter)
+
+					// then
+					require.NoError(t, err)
+					assert.Equal(t, tc.expected, instanceIDs(out))
+					assert.Equal(t, len(tc.expected), count)
+					assert.Equal(t, len(tc.expected), totalCount)
+				})
+			}
+		})
+
+		t.Run("should count instances", func(t *testing.T) {
+			// given
+			instances := newStorage(t).Instances()
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-1", "ga-1", "azure", "eu-west")))
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-2", "ga-1", "azure", "eu-west")))
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-3", "ga-2", "azure", "eu-west")))
+
+			// when
+			stats, err := instances.GetInstanceStats()
+			require.NoError(t, err)
+			numberOfInstances, err := instances.GetNumberOfInstancesForGlobalAccountID("ga-1")
+			require.NoError(t, err)
+
+			// then
+			assert.Equal(t, internal.InstanceStats{
+				TotalNumberOfInstances: 3,
+				PerGlobalAccountID:     map[string]int{"ga-1": 2, "ga-2": 1},
+			}, stats)
+			assert.Equal(t, 2, numberOfInstances)
+		})
+
+		t.Run("should find instances for runtimes and accounts", func(t *testing.T) {
+			// given
+			instances := newStorage(t).Instances()
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-1", "ga-1", "azure", "eu-west")))
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-2", "ga-2", "azure", "eu-west")))
+
+			// when
+			byRuntimes, err := instances.FindAllInstancesForRuntimes([]string{"runtime-instance-2"})
+			require.NoError(t, err)
+			bySubAccounts, err := instances.FindAllInstancesForSubAccounts([]string{"sa-instance-1"})
+			require.NoError(t, err)
+			byGlobalAccounts, err := instances.FindAllInstancesForGlobalAccounts([]string{"ga-1", "ga-2"})
+			require.NoError(t, err)
+			_, notFoundErr := instances.FindAllInstancesForRuntimes([]string{"not-existing"})
+
+			// then
+			assert.ElementsMatch(t, []string{"instance-2"}, instanceIDs(byRuntimes))
+			assert.ElementsMatch(t, []string{"instance-1"}, instanceIDs(bySubAccounts))
+			assert.ElementsMatch(t, []string{"instance-1", "instance-2"}, instanceIDs(byGlobalAccounts))
+			assert.True(t, dberr.IsNotFound(notFoundErr))
+		})
+		t.Run("should list instances with their operations", func(t *testing.T) {
+			// given
+			storage := newStorage(t)
+			instances, operations := storage.Instances(), storage.Operations()
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-1", "ga-3", "azure", "eu-west")))
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-2", "ga-1", "gcp", "eu-west")))
+			require.NoError(t, instances.Insert(fixConformanceInstance("instance-3", "ga-2", "azure", "us-east")))
+
+			provisioning := internal.ProvisioningOperation{Operation: fixConformanceOperation("provisioning-1", "instance-1", domain.Succeeded, 1)}
+			provisioning.RuntimeVersion = internal.RuntimeVersionData{Version: "1.16.0"}
+			require.NoError(t, operations.InsertProvisioningOperation(provisioning))
+			for i, dryRun := range []bool{false, false, false, true} {
+				upgrade := fixConformanceUpgradeKymaOperation(fmt.Sprintf("upgrade-%d", i+1), "instance-1", "orchestration-1", domain.Succeeded, 2+i)
+				upgrade.DryRun = dryRun
+				upgrade.RuntimeVersion = internal.RuntimeVersionData{Version: fmt.Sprintf("1.1%d.0", 7+i)}
+				require.NoError(t, operations.InsertUpgradeKymaOperation(upgrade))
+			}
+			require.NoError(t, operations.InsertProvisioningOperation(internal.ProvisioningOperation{Operation: fixConformanceOperation("provisioning-2", "instance-2", domain.InProgress, 1)}))
+			require.NoError(t, operations.InsertProvisioningOperation(internal.ProvisioningOperation{Operation: fixConformanceOperation("provisioning-3", "instance-3", domain.Succeeded, 1)}))
+			require.NoError(t, operations.InsertDeprovisioningOperation(internal.DeprovisioningOperation{
+				Operation: fixConformanceOperation("deprovisioning-3", "instance-3", domain.Succeeded, 2),
+			}))
+
+			// when
+			all, count, totalCount, err := instances.ListWithOperations(dbmodel.RuntimeFilter{
+				SortBy:           dbmodel.RuntimeSortByGlobalAccountID,
+				UpgradeKymaLimit: 2,
+			})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, 3, count)
+			assert.Equal(t, 3, totalCount)
+			require.Len(t, all, 3)
+			assert.Equal(t, "instance-2", all[0].InstanceID)
+			assert.Equal(t, string(domain.InProgress), all[0].State)
+			assert.Empty(t, all[0].KymaVersion)
+			assert.Nil(t, all[0].Deprovisioning)
+
+			assert.Equal(t, "instance-3", all[1].InstanceID)
+			assert.Equal(t, "suspended", all[1].State)
+			require.NotNil(t, all[1].Deprovisioning)
+			assert.Equal(t, "deprovisioning-3", all[1].Deprovisioning.ID)
+
+			assert.Equal(t, "instance-1", all[2].InstanceID)
+			assert.Equal(t, string(domain.Succeeded), all[2].State)
+			assert.Equal(t, "1.19.0", all[2].KymaVersion)
+			require.NotNil(t, all[2].Provisioning)
+			assert.Equal(t, "provisioning-1", all[2].Provisioning.ID)
+			assert.Equal(t, 3, all[2].UpgradeKymaTotal)
+			require.Len(t, all[2].UpgradeKyma, 2)
+			assert.Equal(t, "upgrade-3", all[2].UpgradeKyma[0].ID)
+			assert.Equal(t, "orchestration-1", all[2].UpgradeKyma[0].OrchestrationID)
+			assert.Equal(t, "upgrade-2", all[2].UpgradeKyma[1].ID)
+
+			for name, tc := range map[string]struct {
+				filter   dbmodel.RuntimeFilter
+				expected []string
+			}{
+				"states":              {filter: dbmodel.RuntimeFilter{States: []string{"suspended", string(domain.InProgress)}}, expected: []string{"instance-2", "instance-3"}},
+				"Kyma versions":       {filter: dbmodel.RuntimeFilter{KymaVersions: []string{"1.19.0"}}, expected: []string{"instance-1"}},
+				"search":              {filter: dbmodel.RuntimeFilter{Search: "GA-2"}, expected: []string{"instance-3"}},
+				"search escapes LIKE": {filter: dbmodel.RuntimeFilter{Search: "instance_"}, expected: []string{}},
+				"instance filter":     {filter: dbmodel.RuntimeFilter{InstanceFilter: dbmodel.InstanceFilter{Plans: []string{"azure"}}}, expected: []string{"instance-1", "instance-3"}},
+				"sorted descending":   {filter: dbmodel.RuntimeFilter{SortBy: dbmodel.RuntimeSortByRegion, SortDesc: true}, expected: []string{"instance-3", "instance-1", "instance-2"}},
+				"paginated":           {filter: dbmodel.RuntimeFilter{SortBy: dbmodel.RuntimeSortByState, InstanceFilter: dbmodel.InstanceFilter{Page: 2, PageSize: 2}}, expected: []string{"instance-3"}},
+			} {
+				t.Run(name, func(t *testing.T) {
+					// when
+					out, _, _, err := instances.ListWithOperations(tc.filter)
+
+					// then
+					require.NoError(t, err)
+					ids := make([]string, 0, len(out))
+					for _, i := range out {
+						ids = append(ids, i.InstanceID)
+					}
+					assert.Equal(t, tc.expected, ids)
+				})
+			}
+		})
+	})
+
+	t.Run("Operations", func(t *testing.T) {
+		t.Run("should insert and update operation with optimistic locking", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			operation := internal.ProvisioningOperation{Operation: fixConformanceOperation("op-1", "instance-1", domain.InProgress, 0)}
+			require.NoError(t, operations.InsertProvisioningOperation(operation))
+
+			// when
+			operation.Description = "updated"
+			updated, err := operations.UpdateProvisioningOperation(operation)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, operation.Version+1, updated.Version)
+			got, err := operations.GetProvisioningOperationByID("op-1")
+			require.NoError(t, err)
+			assert.Equal(t, "updated", got.Description)
+			assert.Equal(t, updated.Version, got.Version)
+
+			// when
+			_, err = operations.UpdateProvisioningOperation(operation)
+
+			// then
+			assert.True(t, dberr.IsConflict(err), "update of the outdated version must fail with conflict")
+		})
+
+		t.Run("should reject duplicated operation", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			operation := internal.DeprovisioningOperation{Operation: fixConformanceOperation("op-1", "instance-1", domain.InProgress, 0)}
+			require.NoError(t, operations.InsertDeprovisioningOperation(operation))
+
+			// when
+			err := operations.InsertDeprovisioningOperation(operation)
+
+			// then
+			assert.True(t, dberr.IsAlreadyExists(err))
+		})
+
+		t.Run("should return not found error", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+
+			// when
+			_, errByID := operations.GetProvisioningOperationByID("not-existing")
+			_, errByInstance := operations.GetDeprovisioningOperationByInstanceID("not-existing")
+			_, errOperation := operations.GetOperationByID("not-existing")
+			_, errUpgrade := operations.GetUpgradeKymaOperationByInstanceID("not-existing")
+
+			// then
+			assert.True(t, dberr.IsNotFound(errByID))
+			assert.True(t, dberr.IsNotFound(errByInstance))
+			assert.True(t, dberr.IsNotFound(errOperation))
+			assert.True(t, dberr.IsNotFound(errUpgrade))
+		})
+
+		t.Run("should return the latest operation of the instance", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			require.NoError(t, operations.InsertDeprovisioningOperation(internal.DeprovisioningOperation{Operation: fixConformanceOperation("op-old", "instance-1", domain.Failed, 0)}))
+			require.NoError(t, operations.InsertDeprovisioningOperation(internal.DeprovisioningOperation{Operation: fixConformanceOperation("op-new", "instance-1", domain.InProgress, 1)}))
+			require.NoError(t, operations.InsertDeprovisioningOperation(internal.DeprovisioningOperation{Operation: fixConformanceOperation("op-other", "instance-2", domain.InProgress, 2)}))
+
+			// when
+			got, err := operations.GetDeprovisioningOperationByInstanceID("instance-1")
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, "op-new", got.ID)
+		})
+
+		t.Run("should allow one upgrade kyma operation in progress created outside of orchestrations", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-1", "instance-1", "", domain.InProgress, 0)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-2", "instance-1", "orchestration-1", domain.InProgress, 1)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-3", "instance-2", "", domain.InProgress, 2)))
+
+			// when
+			err := operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-4", "instance-1", "", domain.InProgress, 3))
+
+			// then
+			require.Error(t, err)
+			assert.True(t, dberr.IsConflict(err))
+
+			// when
+			op, err := operations.GetUpgradeKymaOperationByID("op-1")
+			require.NoError(t, err)
+			op.State = domain.Succeeded
+			_, err = operations.UpdateUpgradeKymaOperation(*op)
+			require.NoError(t, err)
+
+			// then
+			assert.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-4", "instance-1", "", domain.InProgress, 3)))
+		})
+		t.Run("should list operations in progress by type", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			require.NoError(t, operations.InsertProvisioningOperation(internal.ProvisioningOperation{Operation: fixConformanceOperation("op-1", "instance-1", domain.InProgress, 0)}))
+			require.NoError(t, operations.InsertProvisioningOperation(internal.ProvisioningOperation{Operation: fixConformanceOperation("op-2", "instance-2", domain.Succeeded, 1)}))
+			require.NoError(t, operations.InsertDeprovisioningOperation(internal.DeprovisioningOperation{Operation: fixConformanceOperation("op-3", "instance-3", domain.InProgress, 2)}))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-4", "instance-4", "orchestration-1", domain.InProgress, 3)))
+
+			for operationType, expected := range map[dbmodel.OperationType][]string{
+				dbmodel.OperationTypeProvision:   {"op-1"},
+				dbmodel.OperationTypeDeprovision: {"op-3"},
+				dbmodel.OperationTypeUpgradeKyma: {"op-4"},
+			} {
+				// when
+				got, err := operations.GetOperationsInProgressByType(operationType)
+
+				// then
+				require.NoError(t, err)
+				var ids []string
+				for _, op := range got {
+					ids = append(ids, op.ID)
+				}
+				assert.ElementsMatch(t, expected, ids, string(operationType))
+			}
+		})
+
+		t.Run("should count operations", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			require.NoError(t, operations.InsertProvisioningOperation(internal.ProvisioningOperation{Operation: fixConformanceOperation("op-1", "instance-1", domain.InProgress, 0)}))
+			require.NoError(t, operations.InsertProvisioningOperation(internal.ProvisioningOperation{Operation: fixConformanceOperation("op-2", "instance-2", domain.Succeeded, 1)}))
+			require.NoError(t, operations.InsertProvisioningOperation(internal.ProvisioningOperation{Operation: fixConformanceOperation("op-3", "instance-3", domain.Succeeded, 2)}))
+			require.NoError(t, operations.InsertDeprovisioningOperation(internal.DeprovisioningOperation{Operation: fixConformanceOperation("op-4", "instance-4", domain.Failed, 3)}))
+
+			// when
+			stats, err := operations.GetOperationStats()
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, 1, stats.Provisioning[domain.InProgress])
+			assert.Equal(t, 2, stats.Provisioning[domain.Succeeded])
+			assert.Equal(t, 0, stats.Provisioning[domain.Failed])
+			assert.Equal(t, 1, stats.Deprovisioning[domain.Failed])
+			assert.Equal(t, 0, stats.Deprovisioning[domain.InProgress])
+		})
+
+		t.Run("should list upgrade kyma operations of the instance starting with the newest", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-1", "instance-1", "orchestration-1", domain.Succeeded, 0)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-2", "instance-2", "orchestration-1", domain.Succeeded, 1)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-3", "instance-1", "orchestration-2", domain.InProgress, 2)))
+
+			// when
+			got, err := operations.ListUpgradeKymaOperationsByInstanceID("instance-1")
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"op-3", "op-1"}, upgradeKymaOperationIDs(got))
+		})
+
+		t.Run("should list upgrade kyma operations of the orchestration", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-1", "instance-1", "orchestration-1", domain.Succeeded, 0)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-2", "instance-2", "orchestration-1", domain.Failed, 1)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-3", "instance-3", "orchestration-1", domain.Succeeded, 2)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-4", "instance-4", "orchestration-2", domain.Succeeded, 3)))
+
+			// when
+			all, count, totalCount, err := operations.ListUpgradeKymaOperationsByOrchestrationID("orchestration-1", dbmodel.OperationFilter{})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"op-1", "op-2", "op-3"}, upgradeKymaOperationIDs(all))
+			assert.Equal(t, 3, count)
+			assert.Equal(t, 3, totalCount)
+
+			// when
+			page, count, totalCount, err := operations.ListUpgradeKymaOperationsByOrchestrationID("orchestration-1", dbmodel.OperationFilter{Page: 2, PageSize: 2})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"op-3"}, upgradeKymaOperationIDs(page))
+			assert.Equal(t, 1, count)
+			assert.Equal(t, 3, totalCount)
+
+			// when
+			succeeded, count, totalCount, err := operations.ListUpgradeKymaOperationsByOrchestrationID("orchestration-1", dbmodel.OperationFilter{States: []string{string(domain.Succeeded)}})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"op-1", "op-3"}, upgradeKymaOperationIDs(succeeded))
+			assert.Equal(t, 2, count)
+			assert.Equal(t, 2, totalCount)
+		})
+
+		t.Run("should count operations of the orchestration", func(t *testing.T) {
+			// given
+			operations := newStorage(t).Operations()
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-1", "instance-1", "orchestration-1", domain.Succeeded, 0)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-2", "instance-2", "orchestration-1", domain.Failed, 1)))
+			require.NoError(t, operations.InsertUpgradeKymaOperation(fixConformanceUpgradeKymaOperation("op-3", "instance-3", "orchestration-2", domain.Succeeded, 2)))
+
+			// when
+			stats, err := operations.GetOperationStatsForOrchestration("orchestration-1")
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, 1, stats[orchestration.Succeeded])
+			assert.Equal(t, 1, stats[orchestration.Failed])
+			assert.Equal(t, 0, stats[orchestration.InProgress])
+		})
+	})
+
+	t.Run("Orchestrations", func(t *testing.T) {
+		t.Run("should insert and update orchestration", func(t *testing.T) {
+			// given
+			orchestrations := newStorage(t).Orchestrations()
+			o := fixConformanceOrchestration("orchestration-1", orchestration.Pending, 0)
+			require.NoError(t, orchestrations.Insert(o))
+
+			// when
+			o.State = orchestration.InProgress
+			o.Description = "started"
+			require.NoError(t, orchestrations.Update(o))
+
+			// then
+			got, err := orchestrations.GetByID("orchestration-1")
+			require.NoError(t, err)
+			assert.Equal(t, orchestration.InProgress, got.State)
+			assert.Equal(t, "started", got.Description)
+		})
+
+		t.Run("should reject duplicated orchestration", func(t *testing.T) {
+			// given
+			orchestrations := newStorage(t).Orchestrations()
+			o := fixConformanceOrchestration("orchestration-1", orchestration.Pending, 0)
+			require.NoError(t, orchestrations.Insert(o))
+
+			// when
+			err := orchestrations.Insert(o)
+
+			// then
+			assert.True(t, dberr.IsAlreadyExists(err))
+		})
+
+		t.Run("should return not found error", func(t *testing.T) {
+			// given
+			orchestrations := newStorage(t).Orchestrations()
+
+			// when
+			_, errGet := orchestrations.GetByID("not-existing")
+			errUpdate := orchestrations.Update(fixConformanceOrchestration("not-existing", orchestration.Pending, 0))
+
+			// then
+			assert.True(t, dberr.IsNotFound(errGet))
+			assert.True(t, dberr.IsNotFound(errUpdate))
+		})
+
+		t.Run("should list orchestrations page by page and by state", func(t *testing.T) {
+			// given
+			orchestrations := newStorage(t).Orchestrations()
+			require.NoError(t, orchestrations.Insert(fixConformanceOrchestration("orchestration-1", orchestration.Succeeded, 0)))
+			require.NoError(t, orchestrations.Insert(fixConformanceOrchestration("orchestration-2", orchestration.InProgress, 1)))
+			require.NoError(t, orchestrations.Insert(fixConformanceOrchestration("orchestration-3", orchestration.Succeeded, 2)))
+
+			// when
+			page, count, totalCount, err := orchestrations.List(dbmodel.OrchestrationFilter{Page: 2, PageSize: 2})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"orchestration-3"}, orchestrationIDs(page))
+			assert.Equal(t, 1, count)
+			assert.Equal(t, 3, totalCount)
+
+			// when
+			succeeded, count, totalCount, err := orchestrations.List(dbmodel.OrchestrationFilter{States: []string{orchestration.Succeeded}})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"orchestration-1", "orchestration-3"}, orchestrationIDs(succeeded))
+			assert.Equal(t, 2, count)
+			assert.Equal(t, 2, totalCount)
+
+			// when
+			inProgress, err := orchestrations.ListByState(orchestration.InProgress)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, []string{"orchestration-2"}, orchestrationIDs(inProgress))
+		})
+	})
+
+	t.Run("RuntimeStates", func(t *testing.T) {
+		t.Run("should insert and get runtime states", func(t *testing.T) {
+			// given
+			runtimeStates := newStorage(t).RuntimeStates()
+			require.NoError(t, runtimeStates.Insert(fixConformanceRuntimeState("state-2", "runtime-1", "op-2", 1)))
+			require.NoError(t, runtimeStates.Insert(fixConformanceRuntimeState("state-1", "runtime-1", "op-1", 0)))
+			require.NoError(t, runtimeStates.Insert(fixConformanceRuntimeState("state-3", "runtime-2", "op-3", 2)))
+
+			// when
+			byOperation, err := runtimeStates.GetByOperationID("op-2")
+			require.NoError(t, err)
+			byRuntime, err := runtimeStates.ListByRuntimeID("runtime-1")
+			require.NoError(t, err)
+			_, notFoundErr := runtimeStates.GetByOperationID("not-existing")
+
+			// then
+			assert.Equal(t, "state-2", byOperation.ID)
+			require.Len(t, byRuntime, 2)
+			assert.Equal(t, "state-1", byRuntime[0].ID)
+			assert.Equal(t, "state-2", byRuntime[1].ID)
+			assert.True(t, dberr.IsNotFound(notFoundErr))
+		})
+
+		t.Run("should reject duplicated runtime state", func(t *testing.T) {
+			// given
+			runtimeStates := newStorage(t).RuntimeStates()
+			state := fixConformanceRuntimeState("state-1", "runtime-1", "op-1", 0)
+			require.NoError(t, runtimeStates.Insert(state))
+
+			// when
+			err := runtimeStates.Insert(state)
+
+			// then
+			assert.True(t, dberr.IsAlreadyExists(err))
+		})
+	})
+
+	t.Run("LMSTenants", func(t *testing.T) {
+		t.Run("should insert and find tenant", func(t *testing.T) {
+			// given
+			tenants := newStorage(t).LMSTenants()
+			tenant := internal.LMSTenant{ID: "tenant-1", Name: "tenant", Region: "eu", CreatedAt: conformanceTime(0)}
+			require.NoError(t, tenants.InsertTenant(tenant))
+
+			// when
+			got, found, err := tenants.FindTenantByName("tenant", "eu")
+			require.NoError(t, err)
+			_, foundInOtherRegion, err := tenants.FindTenantByName("tenant", "us")
+			require.NoError(t, err)
+
+			// then
+			assert.True(t, found)
+			assert.Equal(t, "tenant-1", got.ID)
+			assert.False(t, foundInOtherRegion)
+		})
+
+		t.Run("should reject duplicated tenant", func(t *testing.T) {
+			// given
+			tenants := newStorage(t).LMSTenants()
+			require.NoError(t, tenants.InsertTenant(internal.LMSTenant{ID: "tenant-1", Name: "tenant", Region: "eu", CreatedAt: conformanceTime(0)}))
+
+			// when
+			err := tenants.InsertTenant(internal.LMSTenant{ID: "tenant-2", Name: "tenant", Region: "eu", CreatedAt: conformanceTime(1)})
+
+			// then
+			assert.True(t, dberr.IsAlreadyExists(err))
+		})
+	})
+
+	t.Run("IASRotations", func(t *testing.T) {
+		t.Run("should save, get and list rotations", func(t *testing.T) {
+			// given
+			rotations := newStorage(t).IASRotations()
+			_, err := rotations.Get("instance-1")
+			assert.True(t, dberr.IsNotFound(err))
+
+			// when
+			require.NoError(t, rotations.Save(internal.IASRotation{InstanceID: "instance-1", SecretRotatedAt: conformanceTime(0), DashboardURL: "https://console.old.kyma.local"}))
+			require.NoError(t, rotations.Save(internal.IASRotation{InstanceID: "instance-1", SecretRotatedAt: conformanceTime(10), DashboardURL: "https://console.new.kyma.local"}))
+			require.NoError(t, rotations.Save(internal.IASRotation{InstanceID: "instance-2", SecretRotatedAt: conformanceTime(5), DashboardURL: "https://console.other.kyma.local"}))
+
+			// then
+			got, err := rotations.Get("instance-1")
+			require.NoError(t, err)
+			assert.True(t, conformanceTime(10).Equal(got.SecretRotatedAt))
+			assert.Equal(t, "https://console.new.kyma.local", got.DashboardURL)
+			assert.False(t, got.UpdatedAt.IsZero())
+
+			all, err := rotations.List()
+			require.NoError(t, err)
+			assert.Len(t, all, 2)
+		})
+	})
+
+	t.Run("LMSCertificates", func(t *testing.T) {
+		t.Run("should save, get and list certificates", func(t *testing.T) {
+			// given
+			certificates := newStorage(t).LMSCertificates()
+			_, err := certificates.Get("instance-1")
+			assert.True(t, dberr.IsNotFound(err))
+
+			// when
+			require.NoError(t, certificates.Save(internal.LMSCertificate{InstanceID: "instance-1", TenantID: "tenant-1", IssuedAt: conformanceTime(0), ExpiresAt: conformanceTime(100)}))
+			require.NoError(t, certificates.Save(internal.LMSCertificate{InstanceID: "instance-1", TenantID: "tenant-1", IssuedAt: conformanceTime(90), ExpiresAt: conformanceTime(190)}))
+			require.NoError(t, certificates.Save(internal.LMSCertificate{InstanceID: "instance-2", TenantID: "tenant-2", IssuedAt: conformanceTime(5)}))
+
+			// then
+			got, err := certificates.Get("instance-1")
+			require.NoError(t, err)
+			assert.Equal(t, "tenant-1", got.TenantID)
+			assert.True(t, conformanceTime(90).Equal(got.IssuedAt))
+			assert.True(t, conformanceTime(190).Equal(got.ExpiresAt))
+			assert.False(t, got.UpdatedAt.IsZero())
+
+			all, err := certificates.List()
+			require.NoError(t, err)
+			assert.Len(t, all, 2)
+		})
+	})
+}
+
+func fixConformanceInstance(id, globalAccountID, plan, region string) internal.Instance {
+	return internal.Instance{
+		InstanceID:      id,
+		RuntimeID:       "runtime-" + id,
+		GlobalAccountID: globalAccountID,
+		SubAccountID:    "sa-" + id,
+		ServiceID:       "service-id",
+		ServiceName:     "kymaruntime",
+		ServicePlanID:   plan + "-id",
+		ServicePlanName: plan,
+		DashboardURL:    fmt.Sprintf("https://console.%s.kyma.local", id),
+		ProviderRegion:  region,
+	}
+}
+
+func fixConformanceOperation(id, instanceID string, state domain.LastOperationState, minutes int) internal.Operation {
+	return internal.Operation{
+		ID:          id,
+		InstanceID:  instanceID,
+		State:       state,
+		Description: "description",
+		CreatedAt:   conformanceTime(minutes),
+		UpdatedAt:   conformanceTime(minutes),
+	}
+}
+
+func fixConformanceUpgradeKymaOperation(id, instanceID, orchestrationID string, state domain.LastOperationState, minutes int) internal.UpgradeKymaOperation {
+	operation := fixConformanceOperation(id, instanceID, state, minutes)
+	operation.OrchestrationID = orchestrationID
+	return internal.UpgradeKymaOperation{
+		Operation: operation,
+		RuntimeOperation: orchestration.RuntimeOperation{
+			Runtime: orchestration.Runtime{InstanceID: instanceID},
+		},
+	}
+}
+
+func fixConformanceOrchestration(id, state string, minutes int) internal.Orchestration {
+	return internal.Orchestration{
+		OrchestrationID: id,
+		State:           state,
+		CreatedAt:       conformanceTime(minutes),
+		UpdatedAt:       conformanceTime(minutes),
+	}
+}
+
+func fixConformanceRuntimeState(id, runtimeID, operationID string, minutes int) internal.RuntimeState {
+	return internal.RuntimeState{
+		ID:          id,
+		RuntimeID:   runtimeID,
+		OperationID: operationID,
+		CreatedAt:   conformanceTime(minutes),
+	}
+}
+
+func conformanceTime(minutes int) time.Time {
+	return time.Date(2020, 11, 2, 10, 0, 0, 0, time.UTC).Add(time.Duration(minutes) * time.Minute)
+}
+
+func instanceIDs(instances []internal.Instance) []string {
+	ids := make([]string, 0, len(instances))
+	for _, i := range instances {
+		ids = append(ids, i.InstanceID)
+	}
+	return ids
+}
+
+func upgradeKymaOperationIDs(operations []internal.UpgradeKymaOperation) []string {
+	ids := make([]string, 0, len(operations))
+	for _, op := range operations {
+		ids = append(ids, op.Operation.ID)
+	}
+	return ids
+}
+
+func orchestrationIDs(orchestrations []internal.Orchestration) []string {
+	ids := make([]string, 0, len(orchestrations))
+	for _, o := range orchestrations {
+		ids = append(ids, o.OrchestrationID)
+	}
+	return ids
+}
@@ -71,5 +71,7 @@ func TestAppError(t *testing.T) {
 		//then
 		assert.False(t, checkOne)
 		assert.True(t, checkTwo)
+		assert.True(t, IsAlreadyExists(AlreadyExists("Some AlreadyExists apperror")))
+		assert.False(t, IsAlreadyExists(notFoundErr))
 	})
 }
@@ -0,0 +1,13 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type CISHighWaterMarkDTO struct {
+	Source         string    `json:"source"`
+	LastEventID    string    `json:"last_event_id"`
+	LastEventTime  time.Time `json:"last_event_time"`
+	FailedAttempts int       `json:"failed_attempts"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
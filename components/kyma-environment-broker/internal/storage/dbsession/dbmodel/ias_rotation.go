@@ -0,0 +1,12 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type IASRotationDTO struct {
+	InstanceID      string    `json:"instance_id"`
+	SecretRotatedAt time.Time `json:"secret_rotated_at"`
+	DashboardURL    string    `json:"dashboard_url"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
@@ -1,5 +1,12 @@
 package dbmodel
 
+import (
+	"database/sql"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
 // InstanceFilter holds the filters when queryíing Instances
 type InstanceFilter struct {
 	PageSize         int
@@ -12,3 +19,70 @@ type InstanceFilter struct {
 	Plans            []string
 	Domains          []string
 }
+
+// RuntimeSortField defines the columns by which instances listed with their operations can be sorted
+type RuntimeSortField string
+
+const (
+	RuntimeSortByCreatedAt       RuntimeSortField = "created_at"
+	RuntimeSortByGlobalAccountID RuntimeSortField = "global_account_id"
+	RuntimeSortBySubAccountID    RuntimeSortField = "sub_account_id"
+	RuntimeSortByRegion          RuntimeSortField = "provider_region"
+	RuntimeSortByPlan            RuntimeSortField = "service_plan_name"
+	RuntimeSortByState           RuntimeSortField = "state"
+	RuntimeSortByKymaVersion     RuntimeSortField = "kyma_version"
+)
+
+// RuntimeFilter holds the filters when listing instances together with their operations
+type RuntimeFilter struct {
+	InstanceFilter
+
+	// States are the runtime states computed from the last operation of the instance
+	States       []string
+	KymaVersions []string
+	// CreatedFrom and CreatedTo limit the instance creation time, zero value means no limit
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Search matches case-insensitively any part of the dashboard URL (which contains the shoot name),
+	// the global account ID or the subaccount ID
+	Search string
+
+	SortBy   RuntimeSortField
+	SortDesc bool
+
+	// UpgradeKymaLimit is the maximum number of the latest upgrade Kyma operations returned per instance
+	UpgradeKymaLimit int
+}
+
+// InstanceWithOperationsDTO is an instance joined with the summary of its operations
+type InstanceWithOperationsDTO struct {
+	internal.Instance
+
+	ProvisioningID              sql.NullString
+	ProvisioningState           sql.NullString
+	ProvisioningDescription     sql.NullString
+	ProvisioningCreatedAt       *time.Time
+	ProvisioningOrchestrationID sql.NullString
+
+	DeprovisioningID              sql.NullString
+	DeprovisioningState           sql.NullString
+	DeprovisioningDescription     sql.NullString
+	DeprovisioningCreatedAt       *time.Time
+	DeprovisioningOrchestrationID sql.NullString
+
+	// UpgradeKymaOperations is a JSON array of OperationSummaryDTO
+	UpgradeKymaOperations string
+	UpgradeKymaTotal      int
+
+	KymaVersion string
+	State       string
+}
+
+// OperationSummaryDTO holds the operation fields aggregated into JSON by the joined instance query
+type OperationSummaryDTO struct {
+	ID              string    `json:"id"`
+	State           string    `json:"state"`
+	Description     string    `json:"description"`
+	CreatedAt       time.Time `json:"created_at"`
+	OrchestrationID *string   `json:"orchestration_id"`
+}
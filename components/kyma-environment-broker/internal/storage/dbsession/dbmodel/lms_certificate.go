@@ -0,0 +1,13 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type LMSCertificateDTO struct {
+	InstanceID string    `json:"instance_id"`
+	TenantID   string    `json:"tenant_id"`
+	IssuedAt   time.Time `json:"issued_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type SubAccountCleanupRunDTO struct {
+	ID string `json:"id"`
+
+	DryRun      bool   `json:"dry_run"`
+	Aborted     bool   `json:"aborted"`
+	Description string `json:"description"`
+
+	// Data contains JSON encoded subaccounts and instances processed in the run
+	Data string `json:"data"`
+
+	CreatedAt  time.Time `json:"created_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
@@ -1,6 +1,8 @@
 package dbsession
 
 import (
+	"time"
+
 	dbr "github.com/gocraft/dbr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
@@ -20,6 +22,7 @@ type ReadSession interface {
 	FindAllInstancesJoinedWithOperation(prct ...predicate.Predicate) ([]internal.InstanceWithOperation, dberr.Error)
 	FindAllInstancesForRuntimes(runtimeIdList []string) ([]internal.Instance, dberr.Error)
 	FindAllInstancesForSubAccounts(subAccountslist []string) ([]internal.Instance, dberr.Error)
+	FindAllInstancesForGlobalAccounts(globalAccountsList []string) ([]internal.Instance, dberr.Error)
 	GetInstanceByID(instanceID string) (internal.Instance, dberr.Error)
 	GetOperationByID(opID string) (dbmodel.OperationDTO, dberr.Error)
 	GetOperationsInProgressByType(operationType dbmodel.OperationType) ([]dbmodel.OperationDTO, dberr.Error)
@@ -32,24 +35,50 @@ type ReadSession interface {
 	GetNumberOfInstancesForGlobalAccountID(globalAccountID string) (int, error)
 	GetRuntimeStateByOperationID(operationID string) (dbmodel.RuntimeStateDTO, dberr.Error)
 	ListRuntimeStateByRuntimeID(runtimeID string) ([]dbmodel.RuntimeStateDTO, dberr.Error)
+	ListRuntimeStatesToReEncrypt(currentPrefix, afterID string, limit int) ([]dbmodel.RuntimeStateDTO, dberr.Error)
+	ListInstancesToReEncrypt(currentPrefix, afterID string, limit int) ([]internal.Instance, dberr.Error)
+	ListOperationsToReEncrypt(currentPrefix, afterID string, limit int) ([]dbmodel.OperationDTO, dberr.Error)
 	GetOrchestrationByID(oID string) (dbmodel.OrchestrationDTO, dberr.Error)
 	ListOrchestrations(filter dbmodel.OrchestrationFilter) ([]dbmodel.OrchestrationDTO, int, int, error)
 	ListInstances(filter dbmodel.InstanceFilter) ([]internal.Instance, int, int, error)
+	ListInstancesWithOperations(filter dbmodel.RuntimeFilter) ([]dbmodel.InstanceWithOperationsDTO, int, int, error)
 	ListOperationsByOrchestrationID(orchestrationID string, filter dbmodel.OperationFilter) ([]dbmodel.OperationDTO, int, int, error)
 	GetOperationStatsForOrchestration(orchestrationID string) ([]dbmodel.OperationStatEntry, error)
+	GetSubAccountCleanupRunByID(runID string) (dbmodel.SubAccountCleanupRunDTO, dberr.Error)
+	GetCISHighWaterMark(source string) (dbmodel.CISHighWaterMarkDTO, dberr.Error)
+	GetIASRotation(instanceID string) (dbmodel.IASRotationDTO, dberr.Error)
+	ListIASRotations() ([]dbmodel.IASRotationDTO, dberr.Error)
+	GetLMSCertificate(instanceID string) (dbmodel.LMSCertificateDTO, dberr.Error)
+	ListLMSCertificates() ([]dbmodel.LMSCertificateDTO, dberr.Error)
+	ListOperationsToArchive(operationType dbmodel.OperationType, finishedBefore time.Time, limit int) ([]dbmodel.OperationDTO, dberr.Error)
+	ListOrchestrationsToArchive(finishedBefore time.Time, limit int) ([]dbmodel.OrchestrationDTO, dberr.Error)
 }
 
 //go:generate mockery -name=WriteSession
 type WriteSession interface {
 	InsertInstance(instance internal.Instance) dberr.Error
 	UpdateInstance(instance internal.Instance) dberr.Error
+	UpdateInstanceProvisioningParameters(instanceID, oldParameters, newParameters string) dberr.Error
 	DeleteInstance(instanceID string) dberr.Error
 	InsertOperation(dto dbmodel.OperationDTO) dberr.Error
 	UpdateOperation(instance dbmodel.OperationDTO) dberr.Error
+	UpdateOperationData(operationID string, version int, data string) dberr.Error
 	InsertOrchestration(o dbmodel.OrchestrationDTO) dberr.Error
 	UpdateOrchestration(o dbmodel.OrchestrationDTO) dberr.Error
 	InsertRuntimeState(state dbmodel.RuntimeStateDTO) dberr.Error
+	UpdateRuntimeStateKymaConfig(id, oldKymaConfig, newKymaConfig string) dberr.Error
 	InsertLMSTenant(dto dbmodel.LMSTenantDTO) dberr.Error
+	InsertSubAccountCleanupRun(dto dbmodel.SubAccountCleanupRunDTO) dberr.Error
+	InsertCISHighWaterMark(dto dbmodel.CISHighWaterMarkDTO) dberr.Error
+	UpdateCISHighWaterMark(dto dbmodel.CISHighWaterMarkDTO) dberr.Error
+	InsertIASRotation(dto dbmodel.IASRotationDTO) dberr.Error
+	UpdateIASRotation(dto dbmodel.IASRotationDTO) dberr.Error
+	InsertLMSCertificate(dto dbmodel.LMSCertificateDTO) dberr.Error
+	UpdateLMSCertificate(dto dbmodel.LMSCertificateDTO) dberr.Error
+	InsertArchivedOperation(dto dbmodel.OperationDTO, archivedAt time.Time) dberr.Error
+	InsertArchivedOrchestration(dto dbmodel.OrchestrationDTO, archivedAt time.Time) dberr.Error
+	DeleteOperations(ids []string) dberr.Error
+	DeleteOrchestrations(ids []string) dberr.Error
 }
 
 type Transaction interface {
@@ -3,9 +3,12 @@ package dbsession
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
@@ -16,6 +19,9 @@ import (
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 )
 
+// finishedOperationStates are the final states of operations and orchestrations
+var finishedOperationStates = []string{string(domain.Succeeded), string(domain.Failed), orchestration.Canceled}
+
 type readSession struct {
 	session *dbr.Session
 }
@@ -98,6 +104,24 @@ func (r readSession) FindAllInstancesForSubAccounts(subAccountslist []string) ([
 	return instances, nil
 }
 
+func (r readSession) FindAllInstancesForGlobalAccounts(globalAccountsList []string) ([]internal.Instance, dberr.Error) {
+	var instances []internal.Instance
+
+	err := r.session.
+		Select("*").
+		From(postsql.InstancesTableName).
+		Where("global_account_id IN ?", globalAccountsList).
+		LoadOne(&instances)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return []internal.Instance{}, nil
+		}
+		return []internal.Instance{}, dberr.Internal("Failed to get Instances: %s", err)
+	}
+	return instances, nil
+}
+
 func (r readSession) GetOperationByID(opID string) (dbmodel.OperationDTO, dberr.Error) {
 	condition := dbr.Eq("id", opID)
 	operation, err := r.getOperation(condition)
@@ -142,6 +166,9 @@ func (r readSession) ListOrchestrations(filter dbmodel.OrchestrationFilter) ([]d
 	addOrchestrationFilters(stmt, filter)
 
 	_, err := stmt.Load(&orchestrations)
+	if err != nil {
+		return nil, -1, -1, errors.Wrap(err, "while fetching orchestrations")
+	}
 
 	totalCount, err := r.getOrchestrationCount(filter)
 	if err != nil {
@@ -286,6 +313,7 @@ func (r readSession) ListRuntimeStateByRuntimeID(runtimeID string) ([]dbmodel.Ru
 		Select("*").
 		From(postsql.RuntimeStateTableName).
 		Where(stateCondition).
+		OrderBy(postsql.CreatedAtField).
 		Load(&states)
 	if err != nil {
 		return nil, dberr.Internal("Failed to get states: %s", err)
@@ -293,6 +321,91 @@ func (r readSession) ListRuntimeStateByRuntimeID(runtimeID string) ([]dbmodel.Ru
 	return states, nil
 }
 
+// ListRuntimeStatesToReEncrypt returns at most limit runtime states with ID greater than afterID and kyma config
+// which is not encrypted with the current key
+func (r readSession) ListRuntimeStatesToReEncrypt(currentPrefix, afterID string, limit int) ([]dbmodel.RuntimeStateDTO, dberr.Error) {
+	var states []dbmodel.RuntimeStateDTO
+
+	_, err := r.session.
+		Select("*").
+		From(postsql.RuntimeStateTableName).
+		Where(dbr.And(
+			dbr.Gt("id", afterID),
+			dbr.Neq("kyma_config", ""),
+			dbr.Expr("kyma_config NOT LIKE ?", likePrefix(currentPrefix)),
+		)).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Load(&states)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get states to re-encrypt: %s", err)
+	}
+	return states, nil
+}
+
+// ListInstancesToReEncrypt returns at most limit instances with ID greater than afterID and Service Manager credentials
+// which are not encrypted with the current key
+func (r readSession) ListInstancesToReEncrypt(currentPrefix, afterID string, limit int) ([]internal.Instance, dberr.Error) {
+	var instances []internal.Instance
+	password := smPasswordExpr("provisioning_parameters")
+
+	_, err := r.session.
+		Select("*").
+		From(postsql.InstancesTableName).
+		Where(dbr.And(
+			dbr.Gt("instance_id", afterID),
+			dbr.Expr(password+" <> ''"),
+			dbr.Expr(password+" NOT LIKE ?", likePrefix(currentPrefix)),
+		)).
+		OrderBy("instance_id").
+		Limit(uint64(limit)).
+		Load(&instances)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get instances to re-encrypt: %s", err)
+	}
+	return instances, nil
+}
+
+// ListOperationsToReEncrypt returns at most limit operations with ID greater than afterID and Service Manager credentials,
+// LMS certificate private key or IAS client secrets which are not encrypted with the current key
+func (r readSession) ListOperationsToReEncrypt(currentPrefix, afterID string, limit int) ([]dbmodel.OperationDTO, dberr.Error) {
+	var operations []dbmodel.OperationDTO
+	pattern := likePrefix(currentPrefix)
+	password := smPasswordExpr("data ->> 'provisioning_parameters'")
+	privateKey := "(data -> 'lms_certificate_renewal' ->> 'private_key')"
+	iasSecrets := "(CASE WHEN json_typeof(data -> 'ias_secrets') = 'object' THEN data -> 'ias_secrets' ELSE '{}'::json END)"
+
+	_, err := r.session.
+		Select("*").
+		From(postsql.OperationTableName).
+		Where(dbr.And(
+			dbr.Gt("id", afterID),
+			dbr.Or(
+				dbr.Expr(password+" <> '' AND "+password+" NOT LIKE ?", pattern),
+				dbr.Expr(privateKey+" <> '' AND "+privateKey+" NOT LIKE ?", pattern),
+				dbr.Expr("EXISTS (SELECT 1 FROM json_each("+iasSecrets+") AS secret "+
+					"WHERE secret.value ->> 'client_secret' <> '' AND secret.value ->> 'client_secret' NOT LIKE ?)", pattern),
+			),
+		)).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Load(&operations)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get operations to re-encrypt: %s", err)
+	}
+	return operations, nil
+}
+
+// smPasswordExpr extracts the Service Manager password from the column which contains serialized provisioning parameters
+func smPasswordExpr(column string) string {
+	return fmt.Sprintf("(CASE WHEN %s <> '' THEN (%s)::json #>> '{ers_context,sm_platform_credentials,credentials,basic,password}' END)", column, column)
+}
+
+// likePrefix returns the LIKE pattern which matches values starting with the given prefix
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(prefix) + "%"
+}
+
 func (r readSession) getOperation(condition dbr.Builder) (dbmodel.OperationDTO, dberr.Error) {
 	var operation dbmodel.OperationDTO
 
@@ -427,6 +540,118 @@ func (r readSession) getInstanceCount(filter dbmodel.InstanceFilter) (int, error
 	return res.Total, err
 }
 
+func (r readSession) ListInstancesWithOperations(filter dbmodel.RuntimeFilter) ([]dbmodel.InstanceWithOperationsDTO, int, int, error) {
+	var instances []dbmodel.InstanceWithOperationsDTO
+
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = dbmodel.RuntimeSortByCreatedAt
+	}
+	stmt := r.session.
+		Select("*").
+		From(instancesWithOperationsTable(filter.UpgradeKymaLimit)).
+		OrderDir(string(sortBy), !filter.SortDesc).
+		OrderBy("instance_id")
+
+	if filter.Page > 0 && filter.PageSize > 0 {
+		stmt.Paginate(uint64(filter.Page), uint64(filter.PageSize))
+	}
+
+	addRuntimeFilters(stmt, filter)
+
+	_, err := stmt.Load(&instances)
+	if err != nil {
+		return nil, -1, -1, errors.Wrap(err, "while fetching instances with operations")
+	}
+
+	var res struct {
+		Total int
+	}
+	countStmt := r.session.
+		Select("count(*) as total").
+		From(instancesWithOperationsTable(filter.UpgradeKymaLimit))
+	addRuntimeFilters(countStmt, filter)
+	if err := countStmt.LoadOne(&res); err != nil {
+		return nil, -1, -1, errors.Wrap(err, "while counting instances with operations")
+	}
+
+	return instances,
+		len(instances),
+		res.Total,
+		nil
+}
+
+// instancesWithOperationsTable returns a derived table with the instance columns and the summary of the instance
+// operations, so all of them can be filtered and sorted in a single query. The runtime state is the state of the last
+// operation which was started, except for the dry run upgrades.
+func instancesWithOperationsTable(upgradeKymaLimit int) string {
+	const dryRun = "COALESCE((o.data->'runtime_operation'->>'dryRun')::boolean, false)"
+	latestOperation := func(opType dbmodel.OperationType) string {
+		return fmt.Sprintf(`SELECT o.id, o.state, o.description, o.created_at, o.orchestration_id FROM %s o
+			WHERE o.instance_id = i.instance_id AND o.type = '%s' ORDER BY o.created_at DESC LIMIT 1`,
+			postsql.OperationTableName, opType)
+	}
+
+	return fmt.Sprintf(`(SELECT i.*,
+		p.id AS provisioning_id, p.state AS provisioning_state, p.description AS provisioning_description,
+		p.created_at AS provisioning_created_at, p.orchestration_id AS provisioning_orchestration_id,
+		d.id AS deprovisioning_id, d.state AS deprovisioning_state, d.description AS deprovisioning_description,
+		d.created_at AS deprovisioning_created_at, d.orchestration_id AS deprovisioning_orchestration_id,
+		COALESCE(u.operations, '[]')::text AS upgrade_kyma_operations, COALESCE(u.total, 0) AS upgrade_kyma_total,
+		COALESCE(v.version, '') AS kyma_version,
+		COALESCE(CASE WHEN l.type = '%[1]s' AND l.state = '%[2]s' THEN '%[3]s' ELSE l.state END, '') AS state
+	FROM %[4]s i
+	LEFT JOIN LATERAL (%[5]s) p ON true
+	LEFT JOIN LATERAL (%[6]s) d ON true
+	LEFT JOIN LATERAL (
+		SELECT json_agg(json_build_object('id', uo.id, 'state', uo.state, 'description', uo.description,
+			'created_at', uo.created_at, 'orchestration_id', uo.orchestration_id) ORDER BY uo.created_at DESC)
+			FILTER (WHERE uo.rn <= %[7]d) AS operations, count(*) AS total
+		FROM (SELECT o.*, row_number() OVER (ORDER BY o.created_at DESC) AS rn FROM %[8]s o
+			WHERE o.instance_id = i.instance_id AND o.type = '%[9]s' AND NOT %[10]s) uo
+	) u ON true
+	LEFT JOIN LATERAL (
+		SELECT o.data->'runtime_version'->>'version' AS version FROM %[8]s o
+		WHERE o.instance_id = i.instance_id AND o.type IN ('%[11]s', '%[9]s') AND o.state = '%[2]s' AND NOT %[10]s
+		ORDER BY o.created_at DESC LIMIT 1
+	) v ON true
+	LEFT JOIN LATERAL (
+		SELECT o.type, o.state FROM %[8]s o
+		WHERE o.instance_id = i.instance_id AND o.state IN ('%[12]s', '%[2]s', '%[13]s') AND NOT (o.type = '%[9]s' AND %[10]s)
+		ORDER BY o.created_at DESC LIMIT 1
+	) l ON true) AS r`,
+		dbmodel.OperationTypeDeprovision, domain.Succeeded, runtime.StateSuspended,
+		postsql.InstancesTableName,
+		latestOperation(dbmodel.OperationTypeProvision),
+		latestOperation(dbmodel.OperationTypeDeprovision),
+		upgradeKymaLimit, postsql.OperationTableName, dbmodel.OperationTypeUpgradeKyma, dryRun,
+		dbmodel.OperationTypeProvision, domain.InProgress, domain.Failed)
+}
+
+func addRuntimeFilters(stmt *dbr.SelectStmt, filter dbmodel.RuntimeFilter) {
+	addInstanceFilters(stmt, filter.InstanceFilter)
+	if len(filter.States) > 0 {
+		stmt.Where("state IN ?", filter.States)
+	}
+	if len(filter.KymaVersions) > 0 {
+		stmt.Where("kyma_version IN ?", filter.KymaVersions)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		stmt.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		stmt.Where("created_at <= ?", filter.CreatedTo)
+	}
+	if filter.Search != "" {
+		pattern := "%" + likePrefix(filter.Search)
+		stmt.Where(dbr.Or(
+			dbr.Expr("dashboard_url ILIKE ?", pattern),
+			dbr.Expr("global_account_id ILIKE ?", pattern),
+			dbr.Expr("sub_account_id ILIKE ?", pattern),
+		))
+	}
+}
+
 func addInstanceFilters(stmt *dbr.SelectStmt, filter dbmodel.InstanceFilter) {
 	if len(filter.GlobalAccountIDs) > 0 {
 		stmt.Where("global_account_id IN ?", filter.GlobalAccountIDs)
@@ -490,3 +715,154 @@ func (r readSession) getOrchestrationCount(filter dbmodel.OrchestrationFilter) (
 
 	return res.Total, err
 }
+
+func (r readSession) GetSubAccountCleanupRunByID(runID string) (dbmodel.SubAccountCleanupRunDTO, dberr.Error) {
+	var run dbmodel.SubAccountCleanupRunDTO
+
+	err := r.session.
+		Select("*").
+		From(postsql.SubAccountCleanupRunTableName).
+		Where(dbr.Eq("id", runID)).
+		LoadOne(&run)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.SubAccountCleanupRunDTO{}, dberr.NotFound("cannot find subaccount cleanup run: %s", err)
+		}
+		return dbmodel.SubAccountCleanupRunDTO{}, dberr.Internal("Failed to get subaccount cleanup run: %s", err)
+	}
+	return run, nil
+}
+
+func (r readSession) GetCISHighWaterMark(source string) (dbmodel.CISHighWaterMarkDTO, dberr.Error) {
+	var mark dbmodel.CISHighWaterMarkDTO
+
+	err := r.session.
+		Select("*").
+		From(postsql.CISHighWaterMarkTableName).
+		Where(dbr.Eq("source", source)).
+		LoadOne(&mark)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.CISHighWaterMarkDTO{}, dberr.NotFound("cannot find CIS high-water mark: %s", err)
+		}
+		return dbmodel.CISHighWaterMarkDTO{}, dberr.Internal("Failed to get CIS high-water mark: %s", err)
+	}
+	return mark, nil
+}
+
+func (r readSession) GetIASRotation(instanceID string) (dbmodel.IASRotationDTO, dberr.Error) {
+	var rotation dbmodel.IASRotationDTO
+
+	err := r.session.
+		Select("*").
+		From(postsql.IASRotationTableName).
+		Where(dbr.Eq("instance_id", instanceID)).
+		LoadOne(&rotation)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.IASRotationDTO{}, dberr.NotFound("cannot find IAS rotation: %s", err)
+		}
+		return dbmodel.IASRotationDTO{}, dberr.Internal("Failed to get IAS rotation: %s", err)
+	}
+	return rotation, nil
+}
+
+func (r readSession) ListIASRotations() ([]dbmodel.IASRotationDTO, dberr.Error) {
+	var rotations []dbmodel.IASRotationDTO
+
+	_, err := r.session.
+		Select("*").
+		From(postsql.IASRotationTableName).
+		Load(&rotations)
+
+	if err != nil {
+		return nil, dberr.Internal("Failed to list IAS rotations: %s", err)
+	}
+	return rotations, nil
+}
+
+func (r readSession) GetLMSCertificate(instanceID string) (dbmodel.LMSCertificateDTO, dberr.Error) {
+	var certificate dbmodel.LMSCertificateDTO
+
+	err := r.session.
+		Select("*").
+		From(postsql.LMSCertificateTableName).
+		Where(dbr.Eq("instance_id", instanceID)).
+		LoadOne(&certificate)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.LMSCertificateDTO{}, dberr.NotFound("cannot find LMS certificate: %s", err)
+		}
+		return dbmodel.LMSCertificateDTO{}, dberr.Internal("Failed to get LMS certificate: %s", err)
+	}
+	return certificate, nil
+}
+
+func (r readSession) ListLMSCertificates() ([]dbmodel.LMSCertificateDTO, dberr.Error) {
+	var certificates []dbmodel.LMSCertificateDTO
+
+	_, err := r.session.
+		Select("*").
+		From(postsql.LMSCertificateTableName).
+		Load(&certificates)
+
+	if err != nil {
+		return nil, dberr.Internal("Failed to list LMS certificates: %s", err)
+	}
+	return certificates, nil
+}
+
+// ListOperationsToArchive returns finished operations of the given type which were updated before the given time.
+// Provisioning and deprovisioning operations are returned only when the instance does not exist anymore.
+func (r readSession) ListOperationsToArchive(operationType dbmodel.OperationType, finishedBefore time.Time, limit int) ([]dbmodel.OperationDTO, dberr.Error) {
+	var operations []dbmodel.OperationDTO
+
+	conditions := []dbr.Builder{
+		dbr.Eq("type", operationType),
+		dbr.Eq("state", finishedOperationStates),
+		dbr.Lt("updated_at", finishedBefore),
+	}
+	if operationType != dbmodel.OperationTypeUpgradeKyma {
+		conditions = append(conditions, dbr.Expr(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s i WHERE i.instance_id = %s.instance_id)",
+			postsql.InstancesTableName, postsql.OperationTableName)))
+	}
+
+	_, err := r.session.
+		Select("*").
+		From(postsql.OperationTableName).
+		Where(dbr.And(conditions...)).
+		OrderBy("updated_at").
+		Limit(uint64(limit)).
+		Load(&operations)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get operations to archive: %s", err)
+	}
+	return operations, nil
+}
+
+// ListOrchestrationsToArchive returns finished orchestrations which were updated before the given time
+// and which operations were already archived
+func (r readSession) ListOrchestrationsToArchive(finishedBefore time.Time, limit int) ([]dbmodel.OrchestrationDTO, dberr.Error) {
+	var orchestrations []dbmodel.OrchestrationDTO
+
+	_, err := r.session.
+		Select("*").
+		From(postsql.OrchestrationTableName).
+		Where(dbr.And(
+			dbr.Eq("state", finishedOperationStates),
+			dbr.Lt("updated_at", finishedBefore),
+			dbr.Expr(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s o WHERE o.orchestration_id = %s.orchestration_id)",
+				postsql.OperationTableName, postsql.OrchestrationTableName)),
+		)).
+		OrderBy("updated_at").
+		Limit(uint64(limit)).
+		Load(&orchestrations)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get orchestrations to archive: %s", err)
+	}
+	return orchestrations, nil
+}
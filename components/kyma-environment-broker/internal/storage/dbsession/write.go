@@ -62,7 +62,7 @@ func (ws writeSession) DeleteInstance(instanceID string) dberr.Error {
 }
 
 func (ws writeSession) UpdateInstance(instance internal.Instance) dberr.Error {
-	_, err := ws.update(postsql.InstancesTableName).
+	res, err := ws.update(postsql.InstancesTableName).
 		Where(dbr.Eq("instance_id", instance.InstanceID)).
 		Set("instance_id", instance.InstanceID).
 		Set("runtime_id", instance.RuntimeID).
@@ -77,6 +77,33 @@ func (ws writeSession) UpdateInstance(instance internal.Instance) dberr.Error {
 	if err != nil {
 		return dberr.Internal("Failed to update record to Instance table: %s", err)
 	}
+	rAffected, e := res.RowsAffected()
+	if e != nil {
+		return dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	if rAffected == int64(0) {
+		return dberr.NotFound("Cannot find Instance with ID:'%s'", instance.InstanceID)
+	}
+
+	return nil
+}
+
+// UpdateInstanceProvisioningParameters replaces the provisioning parameters only if they were not changed in the meantime
+func (ws writeSession) UpdateInstanceProvisioningParameters(instanceID, oldParameters, newParameters string) dberr.Error {
+	res, err := ws.update(postsql.InstancesTableName).
+		Where(dbr.And(dbr.Eq("instance_id", instanceID), dbr.Eq("provisioning_parameters", oldParameters))).
+		Set("provisioning_parameters", newParameters).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to update record to Instance table: %s", err)
+	}
+	rAffected, e := res.RowsAffected()
+	if e != nil {
+		return dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	if rAffected == int64(0) {
+		return dberr.Conflict("Instance with id %s was changed", instanceID)
+	}
 
 	return nil
 }
@@ -98,6 +125,9 @@ func (ws writeSession) InsertOperation(op dbmodel.OperationDTO) dberr.Error {
 
 	if err != nil {
 		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode && err.Constraint == postsql.SameVersionUpgradeInProgressIndexName {
+				return dberr.Conflict("upgrade kyma operation for instance %s is already in progress", op.InstanceID)
+			}
 			if err.Code == UniqueViolationErrorCode {
 				return dberr.AlreadyExists("operation with id %s already exist", op.ID)
 			}
@@ -158,6 +188,27 @@ func (ws writeSession) UpdateOrchestration(o dbmodel.OrchestrationDTO) dberr.Err
 	return nil
 }
 
+// UpdateOperationData replaces the operation data without changing the version,
+// so the update does not interrupt the process which handles the operation
+func (ws writeSession) UpdateOperationData(operationID string, version int, data string) dberr.Error {
+	res, err := ws.update(postsql.OperationTableName).
+		Where(dbr.And(dbr.Eq("id", operationID), dbr.Eq("version", version))).
+		Set("data", data).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to update record to Operation table: %s", err)
+	}
+	rAffected, e := res.RowsAffected()
+	if e != nil {
+		return dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	if rAffected == int64(0) {
+		return dberr.Conflict("Operation with id %s was changed", operationID)
+	}
+
+	return nil
+}
+
 func (ws writeSession) InsertRuntimeState(state dbmodel.RuntimeStateDTO) dberr.Error {
 	_, err := ws.insertInto(postsql.RuntimeStateTableName).
 		Pair("id", state.ID).
@@ -182,6 +233,176 @@ func (ws writeSession) InsertRuntimeState(state dbmodel.RuntimeStateDTO) dberr.E
 	return nil
 }
 
+// UpdateRuntimeStateKymaConfig replaces the kyma config only if it was not changed in the meantime
+func (ws writeSession) UpdateRuntimeStateKymaConfig(id, oldKymaConfig, newKymaConfig string) dberr.Error {
+	res, err := ws.update(postsql.RuntimeStateTableName).
+		Where(dbr.And(dbr.Eq("id", id), dbr.Eq("kyma_config", oldKymaConfig))).
+		Set("kyma_config", newKymaConfig).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to update record to RuntimeState table: %s", err)
+	}
+	rAffected, e := res.RowsAffected()
+	if e != nil {
+		return dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	if rAffected == int64(0) {
+		return dberr.Conflict("RuntimeState with id %s was changed", id)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertSubAccountCleanupRun(dto dbmodel.SubAccountCleanupRunDTO) dberr.Error {
+	_, err := ws.insertInto(postsql.SubAccountCleanupRunTableName).
+		Pair("id", dto.ID).
+		Pair("dry_run", dto.DryRun).
+		Pair("aborted", dto.Aborted).
+		Pair("description", dto.Description).
+		Pair("data", dto.Data).
+		Pair("created_at", dto.CreatedAt).
+		Pair("finished_at", dto.FinishedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("SubAccountCleanupRun with id %s already exist", dto.ID)
+			}
+		}
+		return dberr.Internal("Failed to insert record to SubAccountCleanupRun table: %s", err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertCISHighWaterMark(dto dbmodel.CISHighWaterMarkDTO) dberr.Error {
+	_, err := ws.insertInto(postsql.CISHighWaterMarkTableName).
+		Pair("source", dto.Source).
+		Pair("last_event_id", dto.LastEventID).
+		Pair("last_event_time", dto.LastEventTime).
+		Pair("failed_attempts", dto.FailedAttempts).
+		Pair("updated_at", dto.UpdatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("CIS high-water mark for source %s already exist", dto.Source)
+			}
+		}
+		return dberr.Internal("Failed to insert record to CIS high-water mark table: %s", err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) UpdateCISHighWaterMark(dto dbmodel.CISHighWaterMarkDTO) dberr.Error {
+	res, err := ws.update(postsql.CISHighWaterMarkTableName).
+		Where(dbr.Eq("source", dto.Source)).
+		Set("last_event_id", dto.LastEventID).
+		Set("last_event_time", dto.LastEventTime).
+		Set("failed_attempts", dto.FailedAttempts).
+		Set("updated_at", dto.UpdatedAt).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to update record to CIS high-water mark table: %s", err)
+	}
+	rAffected, e := res.RowsAffected()
+	if e != nil {
+		return dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	if rAffected == int64(0) {
+		return dberr.NotFound("Cannot find CIS high-water mark for source %s", dto.Source)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertIASRotation(dto dbmodel.IASRotationDTO) dberr.Error {
+	_, err := ws.insertInto(postsql.IASRotationTableName).
+		Pair("instance_id", dto.InstanceID).
+		Pair("secret_rotated_at", dto.SecretRotatedAt).
+		Pair("dashboard_url", dto.DashboardURL).
+		Pair("updated_at", dto.UpdatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("IAS rotation for instance %s already exist", dto.InstanceID)
+			}
+		}
+		return dberr.Internal("Failed to insert record to IAS rotation table: %s", err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) UpdateIASRotation(dto dbmodel.IASRotationDTO) dberr.Error {
+	res, err := ws.update(postsql.IASRotationTableName).
+		Where(dbr.Eq("instance_id", dto.InstanceID)).
+		Set("secret_rotated_at", dto.SecretRotatedAt).
+		Set("dashboard_url", dto.DashboardURL).
+		Set("updated_at", dto.UpdatedAt).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to update record to IAS rotation table: %s", err)
+	}
+	rAffected, e := res.RowsAffected()
+	if e != nil {
+		return dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	if rAffected == int64(0) {
+		return dberr.NotFound("Cannot find IAS rotation for instance %s", dto.InstanceID)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertLMSCertificate(dto dbmodel.LMSCertificateDTO) dberr.Error {
+	_, err := ws.insertInto(postsql.LMSCertificateTableName).
+		Pair("instance_id", dto.InstanceID).
+		Pair("tenant_id", dto.TenantID).
+		Pair("issued_at", dto.IssuedAt).
+		Pair("expires_at", dto.ExpiresAt).
+		Pair("updated_at", dto.UpdatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("LMS certificate for instance %s already exist", dto.InstanceID)
+			}
+		}
+		return dberr.Internal("Failed to insert record to LMS certificate table: %s", err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) UpdateLMSCertificate(dto dbmodel.LMSCertificateDTO) dberr.Error {
+	res, err := ws.update(postsql.LMSCertificateTableName).
+		Where(dbr.Eq("instance_id", dto.InstanceID)).
+		Set("tenant_id", dto.TenantID).
+		Set("issued_at", dto.IssuedAt).
+		Set("expires_at", dto.ExpiresAt).
+		Set("updated_at", dto.UpdatedAt).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to update record to LMS certificate table: %s", err)
+	}
+	rAffected, e := res.RowsAffected()
+	if e != nil {
+		return dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	if rAffected == int64(0) {
+		return dberr.NotFound("Cannot find LMS certificate for instance %s", dto.InstanceID)
+	}
+
+	return nil
+}
+
 func (ws writeSession) InsertLMSTenant(dto dbmodel.LMSTenantDTO) dberr.Error {
 	_, err := ws.insertInto(postsql.LMSTenantTableName).
 		Pair("id", dto.ID).
@@ -236,6 +457,77 @@ func (ws writeSession) UpdateOperation(op dbmodel.OperationDTO) dberr.Error {
 	return nil
 }
 
+func (ws writeSession) InsertArchivedOperation(op dbmodel.OperationDTO, archivedAt time.Time) dberr.Error {
+	_, err := ws.insertInto(postsql.OperationArchiveTableName).
+		Pair("id", op.ID).
+		Pair("instance_id", op.InstanceID).
+		Pair("version", op.Version).
+		Pair("created_at", op.CreatedAt).
+		Pair("updated_at", op.UpdatedAt).
+		Pair("description", op.Description).
+		Pair("state", op.State).
+		Pair("target_operation_id", op.TargetOperationID).
+		Pair("type", op.Type).
+		Pair("data", op.Data).
+		Pair("orchestration_id", op.OrchestrationID.String).
+		Pair("archived_at", archivedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("archived operation with id %s already exist", op.ID)
+			}
+		}
+		return dberr.Internal("Failed to insert record to operations archive table: %s", err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertArchivedOrchestration(o dbmodel.OrchestrationDTO, archivedAt time.Time) dberr.Error {
+	_, err := ws.insertInto(postsql.OrchestrationArchiveTableName).
+		Pair("orchestration_id", o.OrchestrationID).
+		Pair("created_at", o.CreatedAt).
+		Pair("updated_at", o.UpdatedAt).
+		Pair("description", o.Description).
+		Pair("state", o.State).
+		Pair("parameters", o.Parameters).
+		Pair("archived_at", archivedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("archived orchestration with id %s already exist", o.OrchestrationID)
+			}
+		}
+		return dberr.Internal("Failed to insert record to orchestrations archive table: %s", err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) DeleteOperations(ids []string) dberr.Error {
+	_, err := ws.deleteFrom(postsql.OperationTableName).
+		Where(dbr.Eq("id", ids)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to delete records from operations table: %s", err)
+	}
+	return nil
+}
+
+func (ws writeSession) DeleteOrchestrations(ids []string) dberr.Error {
+	_, err := ws.deleteFrom(postsql.OrchestrationTableName).
+		Where(dbr.Eq("orchestration_id", ids)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to delete records from orchestrations table: %s", err)
+	}
+	return nil
+}
+
 func (ws writeSession) Commit() dberr.Error {
 	err := ws.transaction.Commit()
 	if err != nil {
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+)
+
+// archive does nothing because the memory storage does not keep data across restarts
+type archive struct{}
+
+func NewArchive() *archive {
+	return &archive{}
+}
+
+func (s *archive) ListOperationsToArchive(operationType dbmodel.OperationType, finishedBefore time.Time, limit int) ([]dbmodel.OperationDTO, error) {
+	return []dbmodel.OperationDTO{}, nil
+}
+
+func (s *archive) ListOrchestrationsToArchive(finishedBefore time.Time, limit int) ([]dbmodel.OrchestrationDTO, error) {
+	return []dbmodel.OrchestrationDTO{}, nil
+}
+
+func (s *archive) MoveOperationsToArchive(operations []dbmodel.OperationDTO) error {
+	return nil
+}
+
+func (s *archive) MoveOrchestrationsToArchive(orchestrations []dbmodel.OrchestrationDTO) error {
+	return nil
+}
+
+func (s *archive) DeleteOperations(ids []string) error {
+	return nil
+}
+
+func (s *archive) DeleteOrchestrations(ids []string) error {
+	return nil
+}
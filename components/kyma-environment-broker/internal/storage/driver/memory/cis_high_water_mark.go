@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+type cisHighWaterMarks struct {
+	mu sync.Mutex
+
+	marks map[string]internal.CISHighWaterMark
+}
+
+func NewCISHighWaterMarks() *cisHighWaterMarks {
+	return &cisHighWaterMarks{
+		marks: make(map[string]internal.CISHighWaterMark, 0),
+	}
+}
+
+func (s *cisHighWaterMarks) Get(source string) (internal.CISHighWaterMark, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mark, exists := s.marks[source]
+	if !exists {
+		return internal.CISHighWaterMark{}, dberr.NotFound("CIS high-water mark for source %s not found", source)
+	}
+
+	return mark, nil
+}
+
+func (s *cisHighWaterMarks) Save(mark internal.CISHighWaterMark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mark.UpdatedAt = time.Now()
+	s.marks[mark.Source] = mark
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+type iasRotations struct {
+	mu sync.Mutex
+
+	rotations map[string]internal.IASRotation
+}
+
+func NewIASRotations() *iasRotations {
+	return &iasRotations{
+		rotations: make(map[string]internal.IASRotation, 0),
+	}
+}
+
+func (s *iasRotations) Get(instanceID string) (internal.IASRotation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rotation, exists := s.rotations[instanceID]
+	if !exists {
+		return internal.IASRotation{}, dberr.NotFound("IAS rotation for instance %s not found", instanceID)
+	}
+
+	return rotation, nil
+}
+
+func (s *iasRotations) List() ([]internal.IASRotation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]internal.IASRotation, 0, len(s.rotations))
+	for _, rotation := range s.rotations {
+		result = append(result, rotation)
+	}
+
+	return result, nil
+}
+
+func (s *iasRotations) Save(rotation internal.IASRotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rotation.UpdatedAt = time.Now()
+	s.rotations[rotation.InstanceID] = rotation
+
+	return nil
+}
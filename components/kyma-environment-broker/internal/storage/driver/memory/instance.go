@@ -2,11 +2,12 @@ package memory
 
 import (
 	"database/sql"
+	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 	"sync"
-
-	"fmt"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
@@ -113,7 +114,27 @@ func (s *Instance) FindAllInstancesForSubAccounts(subAccountslist []string) ([]i
 	return instances, nil
 }
 
+func (s *Instance) FindAllInstancesForGlobalAccounts(globalAccountsList []string) ([]internal.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var instances []internal.Instance
+
+	for _, globalAccount := range globalAccountsList {
+		for _, inst := range s.instances {
+			if inst.GlobalAccountID == globalAccount {
+				instances = append(instances, inst)
+			}
+		}
+	}
+
+	return instances, nil
+}
+
 func (s *Instance) GetNumberOfInstancesForGlobalAccountID(globalAccountID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	numberOfInstances := 0
 	for _, inst := range s.instances {
 		if inst.GlobalAccountID == globalAccountID {
@@ -124,6 +145,9 @@ func (s *Instance) GetNumberOfInstancesForGlobalAccountID(globalAccountID string
 }
 
 func (s *Instance) GetByID(instanceID string) (*internal.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	inst, ok := s.instances[instanceID]
 	if !ok {
 		return nil, dberr.NotFound("instance with id %s not exist", instanceID)
@@ -143,6 +167,16 @@ func (s *Instance) Delete(instanceID string) error {
 func (s *Instance) Insert(instance internal.Instance) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+
+	if _, exists := s.instances[instance.InstanceID]; exists {
+		return dberr.AlreadyExists("instance with id %s already exist", instance.InstanceID)
+	}
+	// set the timestamps which the database fills in by default
+	now := time.Now()
+	if instance.CreatedAt.IsZero() {
+		instance.CreatedAt = now
+	}
+	instance.UpdatedAt = now
 	s.instances[instance.InstanceID] = instance
 
 	return nil
@@ -151,13 +185,30 @@ func (s *Instance) Insert(instance internal.Instance) error {
 func (s *Instance) Update(instance internal.Instance) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+
+	old, exists := s.instances[instance.InstanceID]
+	if !exists {
+		return dberr.NotFound("instance with id %s not exist", instance.InstanceID)
+	}
+	instance.CreatedAt = old.CreatedAt
+	instance.UpdatedAt = time.Now()
 	s.instances[instance.InstanceID] = instance
 
 	return nil
 }
 
 func (s *Instance) GetInstanceStats() (internal.InstanceStats, error) {
-	return internal.InstanceStats{}, fmt.Errorf("not implemented")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := internal.InstanceStats{
+		PerGlobalAccountID: make(map[string]int),
+	}
+	for _, inst := range s.instances {
+		result.PerGlobalAccountID[inst.GlobalAccountID]++
+		result.TotalNumberOfInstances++
+	}
+	return result, nil
 }
 
 func (s *Instance) List(filter dbmodel.InstanceFilter) ([]internal.Instance, int, int, error) {
@@ -180,8 +231,100 @@ func (s *Instance) List(filter dbmodel.InstanceFilter) ([]internal.Instance, int
 		nil
 }
 
+func (s *Instance) ListWithOperations(filter dbmodel.RuntimeFilter) ([]internal.InstanceWithOperations, int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	equal := func(a, b string) bool {
+		return a == b
+	}
+	search := strings.ToLower(filter.Search)
+	contains := func(values ...string) bool {
+		for _, v := range values {
+			if strings.Contains(strings.ToLower(v), search) {
+				return true
+			}
+		}
+		return false
+	}
+
+	instances := make([]internal.InstanceWithOperations, 0)
+	for _, inst := range s.filterInstances(filter.InstanceFilter) {
+		if !filter.CreatedFrom.IsZero() && inst.CreatedAt.Before(filter.CreatedFrom) {
+			continue
+		}
+		if !filter.CreatedTo.IsZero() && inst.CreatedAt.After(filter.CreatedTo) {
+			continue
+		}
+		if search != "" && !contains(inst.DashboardURL, inst.GlobalAccountID, inst.SubAccountID) {
+			continue
+		}
+		withOperations := internal.InstanceWithOperations{Instance: inst}
+		s.operationsStorage.resolveInstanceOperations(&withOperations, filter.UpgradeKymaLimit)
+		if !matchFilter(withOperations.State, filter.States, equal) {
+			continue
+		}
+		if !matchFilter(withOperations.KymaVersion, filter.KymaVersions, equal) {
+			continue
+		}
+		instances = append(instances, withOperations)
+	}
+	sortInstancesWithOperations(instances, filter.SortBy, filter.SortDesc)
+
+	offset := pagination.ConvertPageAndPageSizeToOffset(filter.PageSize, filter.Page)
+	toReturn := make([]internal.InstanceWithOperations, 0)
+	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(instances); i++ {
+		toReturn = append(toReturn, instances[i])
+	}
+
+	return toReturn,
+		len(toReturn),
+		len(instances),
+		nil
+}
+
+func sortInstancesWithOperations(instances []internal.InstanceWithOperations, sortBy dbmodel.RuntimeSortField, desc bool) {
+	sortValue := func(instance internal.InstanceWithOperations) string {
+		switch sortBy {
+		case dbmodel.RuntimeSortByGlobalAccountID:
+			return instance.GlobalAccountID
+		case dbmodel.RuntimeSortBySubAccountID:
+			return instance.SubAccountID
+		case dbmodel.RuntimeSortByRegion:
+			return instance.ProviderRegion
+		case dbmodel.RuntimeSortByPlan:
+			return instance.ServicePlanName
+		case dbmodel.RuntimeSortByState:
+			return instance.State
+		case dbmodel.RuntimeSortByKymaVersion:
+			return instance.KymaVersion
+		}
+		return ""
+	}
+	less := func(a, b internal.InstanceWithOperations) bool {
+		if sortBy == "" || sortBy == dbmodel.RuntimeSortByCreatedAt {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return sortValue(a) < sortValue(b)
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if less(a, b) {
+			return !desc
+		}
+		if less(b, a) {
+			return desc
+		}
+		return a.InstanceID < b.InstanceID
+	})
+}
+
 func sortInstancesByCreatedAt(instances []internal.Instance) {
 	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].CreatedAt.Equal(instances[j].CreatedAt) {
+			return instances[i].InstanceID < instances[j].InstanceID
+		}
 		return instances[i].CreatedAt.Before(instances[j].CreatedAt)
 	})
 }
@@ -242,3 +385,8 @@ func matchFilter(value string, filters []string, match func(string, string) bool
 	}
 	return false
 }
+
+// ReEncrypt does nothing because the memory storage does not encrypt data
+func (s *Instance) ReEncrypt(afterID string, batchSize int) (internal.ReEncryptionResult, error) {
+	return internal.ReEncryptionResult{}, nil
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+type lmsCertificates struct {
+	mu sync.Mutex
+
+	certificates map[string]internal.LMSCertificate
+}
+
+func NewLMSCertificates() *lmsCertificates {
+	return &lmsCertificates{
+		certificates: make(map[string]internal.LMSCertificate, 0),
+	}
+}
+
+func (s *lmsCertificates) Get(instanceID string) (internal.LMSCertificate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	certificate, exists := s.certificates[instanceID]
+	if !exists {
+		return internal.LMSCertificate{}, dberr.NotFound("LMS certificate for instance %s not found", instanceID)
+	}
+
+	return certificate, nil
+}
+
+func (s *lmsCertificates) List() ([]internal.LMSCertificate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]internal.LMSCertificate, 0, len(s.certificates))
+	for _, certificate := range s.certificates {
+		result = append(result, certificate)
+	}
+
+	return result, nil
+}
+
+func (s *lmsCertificates) Save(certificate internal.LMSCertificate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	certificate.UpdatedAt = time.Now()
+	s.certificates[certificate.InstanceID] = certificate
+
+	return nil
+}
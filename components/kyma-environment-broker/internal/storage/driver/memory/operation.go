@@ -6,6 +6,7 @@ import (
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 
@@ -45,6 +46,9 @@ func (s *operations) InsertProvisioningOperation(operation internal.Provisioning
 }
 
 func (s *operations) GetProvisioningOperationByID(operationID string) (*internal.ProvisioningOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	op, exists := s.provisioningOperations[operationID]
 	if !exists {
 		return nil, dberr.NotFound("instance provisioning operation with id %s not found", operationID)
@@ -53,11 +57,19 @@ func (s *operations) GetProvisioningOperationByID(operationID string) (*internal
 }
 
 func (s *operations) GetProvisioningOperationByInstanceID(instanceID string) (*internal.ProvisioningOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest *internal.ProvisioningOperation
 	for _, op := range s.provisioningOperations {
-		if op.InstanceID == instanceID {
-			return &op, nil
+		if op.InstanceID == instanceID && (latest == nil || op.CreatedAt.After(latest.CreatedAt)) {
+			found := op
+			latest = &found
 		}
 	}
+	if latest != nil {
+		return latest, nil
+	}
 	return nil, dberr.NotFound("instance provisioning operation with instanceID %s not found", instanceID)
 }
 
@@ -92,6 +104,9 @@ func (s *operations) InsertDeprovisioningOperation(operation internal.Deprovisio
 }
 
 func (s *operations) GetDeprovisioningOperationByID(operationID string) (*internal.DeprovisioningOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	op, exists := s.deprovisioningOperations[operationID]
 	if !exists {
 		return nil, dberr.NotFound("instance deprovisioning operation with id %s not found", operationID)
@@ -100,11 +115,19 @@ func (s *operations) GetDeprovisioningOperationByID(operationID string) (*intern
 }
 
 func (s *operations) GetDeprovisioningOperationByInstanceID(instanceID string) (*internal.DeprovisioningOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest *internal.DeprovisioningOperation
 	for _, op := range s.deprovisioningOperations {
-		if op.InstanceID == instanceID {
-			return &op, nil
+		if op.InstanceID == instanceID && (latest == nil || op.CreatedAt.After(latest.CreatedAt)) {
+			found := op
+			latest = &found
 		}
 	}
+	if latest != nil {
+		return latest, nil
+	}
 
 	return nil, dberr.NotFound("instance deprovisioning operation with instanceID %s not found", instanceID)
 }
@@ -134,12 +157,23 @@ func (s *operations) InsertUpgradeKymaOperation(operation internal.UpgradeKymaOp
 	if _, exists := s.upgradeKymaOperations[id]; exists {
 		return dberr.AlreadyExists("instance operation with id %s already exist", id)
 	}
+	// the same as the unique index of the PostgreSQL driver
+	if isSameVersionUpgradeInProgress(operation) {
+		for _, op := range s.upgradeKymaOperations {
+			if op.InstanceID == operation.InstanceID && isSameVersionUpgradeInProgress(op) {
+				return dberr.Conflict("upgrade kyma operation for instance %s is already in progress", operation.InstanceID)
+			}
+		}
+	}
 
 	s.upgradeKymaOperations[id] = operation
 	return nil
 }
 
 func (s *operations) GetUpgradeKymaOperationByID(operationID string) (*internal.UpgradeKymaOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	op, exists := s.upgradeKymaOperations[operationID]
 	if !exists {
 		return nil, dberr.NotFound("instance upgradeKyma operation with id %s not found", operationID)
@@ -148,11 +182,19 @@ func (s *operations) GetUpgradeKymaOperationByID(operationID string) (*internal.
 }
 
 func (s *operations) GetUpgradeKymaOperationByInstanceID(instanceID string) (*internal.UpgradeKymaOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest *internal.UpgradeKymaOperation
 	for _, op := range s.upgradeKymaOperations {
-		if op.InstanceID == instanceID {
-			return &op, nil
+		if op.InstanceID == instanceID && (latest == nil || op.CreatedAt.After(latest.CreatedAt)) {
+			found := op
+			latest = &found
 		}
 	}
+	if latest != nil {
+		return latest, nil
+	}
 
 	return nil, dberr.NotFound("instance upgradeKyma operation with instanceID %s not found", instanceID)
 }
@@ -175,6 +217,9 @@ func (s *operations) UpdateUpgradeKymaOperation(op internal.UpgradeKymaOperation
 }
 
 func (s *operations) GetOperationByID(operationID string) (*internal.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var res *internal.Operation
 
 	provisionOp, exists := s.provisioningOperations[operationID]
@@ -214,6 +259,12 @@ func (s *operations) GetOperationsInProgressByType(opType dbmodel.OperationType)
 				ops = append(ops, op.Operation)
 			}
 		}
+	case dbmodel.OperationTypeUpgradeKyma:
+		for _, op := range s.upgradeKymaOperations {
+			if op.State == domain.InProgress {
+				ops = append(ops, op.Operation)
+			}
+		}
 	}
 
 	return ops, nil
@@ -285,6 +336,9 @@ func (s *operations) GetOperationStatsForOrchestration(orchestrationID string) (
 		orchestration.Failed:     0,
 	}
 	for _, op := range s.upgradeKymaOperations {
+		if op.OrchestrationID != orchestrationID {
+			continue
+		}
 		result[string(op.State)] = result[string(op.State)] + 1
 	}
 	return result, nil
@@ -297,11 +351,13 @@ func (s *operations) ListUpgradeKymaOperationsByOrchestrationID(orchestrationID
 	result := make([]internal.UpgradeKymaOperation, 0)
 	offset := pagination.ConvertPageAndPageSizeToOffset(filter.PageSize, filter.Page)
 
-	operations := s.filterUpgrade(filter)
+	operations := s.filterUpgrade(filter, func(op internal.UpgradeKymaOperation) bool {
+		return op.OrchestrationID == orchestrationID
+	})
 	s.sortUpgradeByCreatedAt(operations)
 
-	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(operations)+offset; i++ {
-		result = append(result, s.upgradeKymaOperations[operations[i].OrchestrationID])
+	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(operations); i++ {
+		result = append(result, operations[i])
 	}
 
 	return result,
@@ -315,8 +371,13 @@ func (s *operations) ListUpgradeKymaOperationsByInstanceID(instanceID string) ([
 	defer s.mu.Unlock()
 
 	// Empty filter means get all
-	operations := s.filterUpgrade(dbmodel.OperationFilter{})
-	s.sortUpgradeByCreatedAt(operations)
+	operations := s.filterUpgrade(dbmodel.OperationFilter{}, func(op internal.UpgradeKymaOperation) bool {
+		return op.InstanceID == instanceID
+	})
+	// the newest operations go first
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].CreatedAt.After(operations[j].CreatedAt)
+	})
 
 	return operations, nil
 }
@@ -327,10 +388,13 @@ func (s *operations) sortUpgradeByCreatedAt(operations []internal.UpgradeKymaOpe
 	})
 }
 
-func (s *operations) filterUpgrade(filter dbmodel.OperationFilter) []internal.UpgradeKymaOperation {
+func (s *operations) filterUpgrade(filter dbmodel.OperationFilter, match func(internal.UpgradeKymaOperation) bool) []internal.UpgradeKymaOperation {
 	operations := make([]internal.UpgradeKymaOperation, 0, len(s.upgradeKymaOperations))
 	equal := func(a, b string) bool { return a == b }
 	for _, v := range s.upgradeKymaOperations {
+		if !match(v) {
+			continue
+		}
 		if ok := matchFilter(string(v.State), filter.States, equal); !ok {
 			continue
 		}
@@ -340,3 +404,88 @@ func (s *operations) filterUpgrade(filter dbmodel.OperationFilter) []internal.Up
 
 	return operations
 }
+
+// resolveInstanceOperations sets the latest operations, the Kyma version and the state of the instance
+// the same way as the joined query of the PostgreSQL storage
+func (s *operations) resolveInstanceOperations(instance *internal.InstanceWithOperations, upgradeKymaLimit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		last          *internal.Operation
+		lastType      dbmodel.OperationType
+		lastSucceeded *internal.Operation
+	)
+	track := func(op internal.Operation, opType dbmodel.OperationType, version string) {
+		switch op.State {
+		case domain.InProgress, domain.Succeeded, domain.Failed:
+		default:
+			return
+		}
+		if last == nil || op.CreatedAt.After(last.CreatedAt) {
+			last, lastType = &op, opType
+		}
+		if opType != dbmodel.OperationTypeDeprovision && op.State == domain.Succeeded &&
+			(lastSucceeded == nil || op.CreatedAt.After(lastSucceeded.CreatedAt)) {
+			lastSucceeded = &op
+			instance.KymaVersion = version
+		}
+	}
+
+	for _, op := range s.provisioningOperations {
+		if op.InstanceID != instance.InstanceID {
+			continue
+		}
+		if instance.Provisioning == nil || op.CreatedAt.After(instance.Provisioning.CreatedAt) {
+			latest := op.Operation
+			instance.Provisioning = &latest
+		}
+		track(op.Operation, dbmodel.OperationTypeProvision, op.RuntimeVersion.Version)
+	}
+	for _, op := range s.deprovisioningOperations {
+		if op.InstanceID != instance.InstanceID {
+			continue
+		}
+		if instance.Deprovisioning == nil || op.CreatedAt.After(instance.Deprovisioning.CreatedAt) {
+			latest := op.Operation
+			instance.Deprovisioning = &latest
+		}
+		track(op.Operation, dbmodel.OperationTypeDeprovision, "")
+	}
+	upgrades := make([]internal.Operation, 0)
+	for _, op := range s.upgradeKymaOperations {
+		if op.InstanceID != instance.InstanceID || op.DryRun {
+			continue
+		}
+		upgrades = append(upgrades, op.Operation)
+		track(op.Operation, dbmodel.OperationTypeUpgradeKyma, op.RuntimeVersion.Version)
+	}
+
+	// the newest operations go first
+	sort.Slice(upgrades, func(i, j int) bool {
+		return upgrades[i].CreatedAt.After(upgrades[j].CreatedAt)
+	})
+	instance.UpgradeKymaTotal = len(upgrades)
+	if len(upgrades) > upgradeKymaLimit {
+		upgrades = upgrades[:upgradeKymaLimit]
+	}
+	instance.UpgradeKyma = upgrades
+
+	switch {
+	case last == nil:
+		instance.State = ""
+	case lastType == dbmodel.OperationTypeDeprovision && last.State == domain.Succeeded:
+		instance.State = runtime.StateSuspended
+	default:
+		instance.State = string(last.State)
+	}
+}
+
+// ReEncrypt does nothing because the memory storage does not encrypt data
+func (s *operations) ReEncrypt(afterID string, batchSize int) (internal.ReEncryptionResult, error) {
+	return internal.ReEncryptionResult{}, nil
+}
+
+func isSameVersionUpgradeInProgress(op internal.UpgradeKymaOperation) bool {
+	return op.OrchestrationID == "" && (op.State == orchestration.Pending || op.State == orchestration.InProgress)
+}
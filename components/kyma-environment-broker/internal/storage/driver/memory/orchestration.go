@@ -27,6 +27,9 @@ func NewOrchestrations() *orchestrations {
 func (s *orchestrations) Insert(orchestration internal.Orchestration) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if _, exists := s.orchestrations[orchestration.OrchestrationID]; exists {
+		return dberr.AlreadyExists("orchestration with id %s already exist", orchestration.OrchestrationID)
+	}
 	s.orchestrations[orchestration.OrchestrationID] = orchestration
 
 	return nil
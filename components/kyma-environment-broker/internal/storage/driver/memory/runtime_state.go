@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
@@ -23,6 +24,9 @@ func NewRuntimeStates() *runtimeState {
 func (s *runtimeState) Insert(runtimeState internal.RuntimeState) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if _, exists := s.runtimeStates[runtimeState.ID]; exists {
+		return dberr.AlreadyExists("runtime state with id %s already exist", runtimeState.ID)
+	}
 	s.runtimeStates[runtimeState.ID] = runtimeState
 
 	return nil
@@ -39,6 +43,9 @@ func (s *runtimeState) ListByRuntimeID(runtimeID string) ([]internal.RuntimeStat
 			result = append(result, state)
 		}
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
 
 	return result, nil
 }
@@ -55,3 +62,8 @@ func (s *runtimeState) GetByOperationID(operationID string) (internal.RuntimeSta
 
 	return internal.RuntimeState{}, dberr.NotFound("runtime state with operation ID %s not found", operationID)
 }
+
+// ReEncrypt does nothing because the memory storage does not encrypt data
+func (s *runtimeState) ReEncrypt(afterID string, batchSize int) (internal.ReEncryptionResult, error) {
+	return internal.ReEncryptionResult{}, nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+type subAccountCleanupRuns struct {
+	mu sync.Mutex
+
+	runs map[string]internal.SubAccountCleanupRun
+}
+
+func NewSubAccountCleanupRuns() *subAccountCleanupRuns {
+	return &subAccountCleanupRuns{
+		runs: make(map[string]internal.SubAccountCleanupRun, 0),
+	}
+}
+
+func (s *subAccountCleanupRuns) Insert(run internal.SubAccountCleanupRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.runs[run.ID]; exists {
+		return dberr.AlreadyExists("subaccount cleanup run with ID %s already exists", run.ID)
+	}
+	s.runs[run.ID] = run
+
+	return nil
+}
+
+func (s *subAccountCleanupRuns) GetByID(runID string) (internal.SubAccountCleanupRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, exists := s.runs[runID]
+	if !exists {
+		return internal.SubAccountCleanupRun{}, dberr.NotFound("subaccount cleanup run with ID %s not found", runID)
+	}
+
+	return run, nil
+}
@@ -0,0 +1,132 @@
+package postsql
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type archive struct {
+	dbsession.Factory
+}
+
+func NewArchive(sess dbsession.Factory) *archive {
+	return &archive{
+		Factory: sess,
+	}
+}
+
+func (s *archive) ListOperationsToArchive(operationType dbmodel.OperationType, finishedBefore time.Time, limit int) ([]dbmodel.OperationDTO, error) {
+	sess := s.NewReadSession()
+	var (
+		operations []dbmodel.OperationDTO
+		lastErr    dberr.Error
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		operations, lastErr = sess.ListOperationsToArchive(operationType, finishedBefore, limit)
+		if lastErr != nil {
+			log.Warn(errors.Wrapf(lastErr, "while listing operations to archive").Error())
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastErr
+	}
+	return operations, nil
+}
+
+func (s *archive) ListOrchestrationsToArchive(finishedBefore time.Time, limit int) ([]dbmodel.OrchestrationDTO, error) {
+	sess := s.NewReadSession()
+	var (
+		orchestrations []dbmodel.OrchestrationDTO
+		lastErr        dberr.Error
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		orchestrations, lastErr = sess.ListOrchestrationsToArchive(finishedBefore, limit)
+		if lastErr != nil {
+			log.Warn(errors.Wrapf(lastErr, "while listing orchestrations to archive").Error())
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastErr
+	}
+	return orchestrations, nil
+}
+
+// MoveOperationsToArchive copies the operations to the archive table and deletes them in one transaction
+func (s *archive) MoveOperationsToArchive(operations []dbmodel.OperationDTO) error {
+	if len(operations) == 0 {
+		return nil
+	}
+	sess, dErr := s.NewSessionWithinTransaction()
+	if dErr != nil {
+		return errors.Wrap(dErr, "while starting transaction")
+	}
+	defer sess.RollbackUnlessCommitted()
+
+	archivedAt := time.Now()
+	ids := make([]string, 0, len(operations))
+	for _, op := range operations {
+		if err := sess.InsertArchivedOperation(op, archivedAt); err != nil {
+			return errors.Wrapf(err, "while archiving operation %s", op.ID)
+		}
+		ids = append(ids, op.ID)
+	}
+	if err := sess.DeleteOperations(ids); err != nil {
+		return errors.Wrap(err, "while deleting archived operations")
+	}
+	if err := sess.Commit(); err != nil {
+		return errors.Wrap(err, "while committing archived operations")
+	}
+	return nil
+}
+
+// MoveOrchestrationsToArchive copies the orchestrations to the archive table and deletes them in one transaction
+func (s *archive) MoveOrchestrationsToArchive(orchestrations []dbmodel.OrchestrationDTO) error {
+	if len(orchestrations) == 0 {
+		return nil
+	}
+	sess, dErr := s.NewSessionWithinTransaction()
+	if dErr != nil {
+		return errors.Wrap(dErr, "while starting transaction")
+	}
+	defer sess.RollbackUnlessCommitted()
+
+	archivedAt := time.Now()
+	ids := make([]string, 0, len(orchestrations))
+	for _, o := range orchestrations {
+		if err := sess.InsertArchivedOrchestration(o, archivedAt); err != nil {
+			return errors.Wrapf(err, "while archiving orchestration %s", o.OrchestrationID)
+		}
+		ids = append(ids, o.OrchestrationID)
+	}
+	if err := sess.DeleteOrchestrations(ids); err != nil {
+		return errors.Wrap(err, "while deleting archived orchestrations")
+	}
+	if err := sess.Commit(); err != nil {
+		return errors.Wrap(err, "while committing archived orchestrations")
+	}
+	return nil
+}
+
+func (s *archive) DeleteOperations(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.NewWriteSession().DeleteOperations(ids)
+}
+
+func (s *archive) DeleteOrchestrations(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.NewWriteSession().DeleteOrchestrations(ids)
+}
@@ -0,0 +1,73 @@
+package postsql
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type cisHighWaterMarks struct {
+	dbsession.Factory
+}
+
+func NewCISHighWaterMarks(sess dbsession.Factory) *cisHighWaterMarks {
+	return &cisHighWaterMarks{
+		Factory: sess,
+	}
+}
+
+func (s *cisHighWaterMarks) Get(source string) (internal.CISHighWaterMark, error) {
+	sess := s.NewReadSession()
+	dto := dbmodel.CISHighWaterMarkDTO{}
+	var lastErr dberr.Error
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		dto, lastErr = sess.GetCISHighWaterMark(source)
+		if lastErr != nil {
+			if dberr.IsNotFound(lastErr) {
+				return false, dberr.NotFound("CIS high-water mark for source %s not found", source)
+			}
+			log.Warnf("while getting CIS high-water mark: %v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return internal.CISHighWaterMark{}, lastErr
+	}
+
+	return internal.CISHighWaterMark{
+		Source:         dto.Source,
+		LastEventID:    dto.LastEventID,
+		LastEventTime:  dto.LastEventTime,
+		FailedAttempts: dto.FailedAttempts,
+		UpdatedAt:      dto.UpdatedAt,
+	}, nil
+}
+
+// Save updates the mark for the given source or creates it if it does not exist yet
+func (s *cisHighWaterMarks) Save(mark internal.CISHighWaterMark) error {
+	dto := dbmodel.CISHighWaterMarkDTO{
+		Source:         mark.Source,
+		LastEventID:    mark.LastEventID,
+		LastEventTime:  mark.LastEventTime,
+		FailedAttempts: mark.FailedAttempts,
+		UpdatedAt:      time.Now(),
+	}
+	sess := s.NewWriteSession()
+	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		err := sess.UpdateCISHighWaterMark(dto)
+		if dberr.IsNotFound(err) {
+			err = sess.InsertCISHighWaterMark(dto)
+		}
+		if err != nil {
+			log.Warnf("while saving CIS high-water mark for source %s: %v", mark.Source, err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
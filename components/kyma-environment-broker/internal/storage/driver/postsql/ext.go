@@ -3,4 +3,10 @@ package postsql
 type Cipher interface {
 	Encrypt(text []byte) ([]byte, error)
 	Decrypt(text []byte) ([]byte, error)
+	// IsEncrypted checks if the value was encrypted with one of the versioned keys, the value is authenticated
+	IsEncrypted(text []byte) bool
+	// HasEncryptedFormat checks if the value has the format of an encrypted value, the value is not authenticated
+	HasEncryptedFormat(text []byte) bool
+	// CurrentPrefix returns the prefix of values encrypted with the newest key
+	CurrentPrefix() string
 }
@@ -0,0 +1,98 @@
+package postsql
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type iasRotations struct {
+	dbsession.Factory
+}
+
+func NewIASRotations(sess dbsession.Factory) *iasRotations {
+	return &iasRotations{
+		Factory: sess,
+	}
+}
+
+func (s *iasRotations) Get(instanceID string) (internal.IASRotation, error) {
+	sess := s.NewReadSession()
+	dto := dbmodel.IASRotationDTO{}
+	var lastErr dberr.Error
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		dto, lastErr = sess.GetIASRotation(instanceID)
+		if lastErr != nil {
+			if dberr.IsNotFound(lastErr) {
+				return false, dberr.NotFound("IAS rotation for instance %s not found", instanceID)
+			}
+			log.Warnf("while getting IAS rotation: %v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return internal.IASRotation{}, lastErr
+	}
+
+	return toIASRotation(dto), nil
+}
+
+func (s *iasRotations) List() ([]internal.IASRotation, error) {
+	sess := s.NewReadSession()
+	var dtos []dbmodel.IASRotationDTO
+	var lastErr dberr.Error
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		dtos, lastErr = sess.ListIASRotations()
+		if lastErr != nil {
+			log.Warnf("while listing IAS rotations: %v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastErr
+	}
+
+	result := make([]internal.IASRotation, 0, len(dtos))
+	for _, dto := range dtos {
+		result = append(result, toIASRotation(dto))
+	}
+	return result, nil
+}
+
+// Save updates the rotation of the given instance or creates it if it does not exist yet
+func (s *iasRotations) Save(rotation internal.IASRotation) error {
+	dto := dbmodel.IASRotationDTO{
+		InstanceID:      rotation.InstanceID,
+		SecretRotatedAt: rotation.SecretRotatedAt,
+		DashboardURL:    rotation.DashboardURL,
+		UpdatedAt:       time.Now(),
+	}
+	sess := s.NewWriteSession()
+	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		err := sess.UpdateIASRotation(dto)
+		if dberr.IsNotFound(err) {
+			err = sess.InsertIASRotation(dto)
+		}
+		if err != nil {
+			log.Warnf("while saving IAS rotation for instance %s: %v", rotation.InstanceID, err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+func toIASRotation(dto dbmodel.IASRotationDTO) internal.IASRotation {
+	return internal.IASRotation{
+		InstanceID:      dto.InstanceID,
+		SecretRotatedAt: dto.SecretRotatedAt,
+		DashboardURL:    dto.DashboardURL,
+		UpdatedAt:       dto.UpdatedAt,
+	}
+}
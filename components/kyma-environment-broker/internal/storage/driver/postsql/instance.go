@@ -1,11 +1,15 @@
 package postsql
 
 import (
+	"encoding/json"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/predicate"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -13,11 +17,14 @@ import (
 
 type Instance struct {
 	dbsession.Factory
+
+	cipher Cipher
 }
 
-func NewInstance(sess dbsession.Factory) *Instance {
+func NewInstance(sess dbsession.Factory, cipher Cipher) *Instance {
 	return &Instance{
 		Factory: sess,
+		cipher:  cipher,
 	}
 }
 
@@ -38,6 +45,11 @@ func (s *Instance) FindAllJoinedWithOperations(prct ...predicate.Predicate) ([]i
 	if err != nil {
 		return nil, lastErr
 	}
+	for i := range instances {
+		if err := s.decrypt(&instances[i].Instance); err != nil {
+			return nil, err
+		}
+	}
 
 	return instances, nil
 }
@@ -60,7 +72,7 @@ func (s *Instance) FindAllInstancesForRuntimes(runtimeIdList []string) ([]intern
 	if err != nil {
 		return nil, lastErr
 	}
-	return instances, nil
+	return s.decryptAll(instances)
 }
 
 func (s *Instance) FindAllInstancesForSubAccounts(subAccountslist []string) ([]internal.Instance, error) {
@@ -81,7 +93,28 @@ func (s *Instance) FindAllInstancesForSubAccounts(subAccountslist []string) ([]i
 		return nil, lastErr
 	}
 
-	return instances, nil
+	return s.decryptAll(instances)
+}
+
+func (s *Instance) FindAllInstancesForGlobalAccounts(globalAccountsList []string) ([]internal.Instance, error) {
+	sess := s.NewReadSession()
+	var (
+		instances []internal.Instance
+		lastErr   dberr.Error
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		instances, lastErr = sess.FindAllInstancesForGlobalAccounts(globalAccountsList)
+		if lastErr != nil {
+			log.Warn(errors.Wrapf(lastErr, "while fetching instances by global account list").Error())
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastErr
+	}
+
+	return s.decryptAll(instances)
 }
 
 func (s *Instance) GetNumberOfInstancesForGlobalAccountID(globalAccountID string) (int, error) {
@@ -114,6 +147,9 @@ func (s *Instance) GetByID(instanceID string) (*internal.Instance, error) {
 	if err != nil {
 		return nil, lastErr
 	}
+	if err := s.decrypt(&instance); err != nil {
+		return nil, err
+	}
 	return &instance, nil
 }
 
@@ -123,6 +159,10 @@ func (s *Instance) Insert(instance internal.Instance) error {
 		return dberr.AlreadyExists("instance with id %s already exist", instance.InstanceID)
 	}
 
+	if err := s.encrypt(&instance); err != nil {
+		return err
+	}
+
 	sess := s.NewWriteSession()
 	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
 		err := sess.InsertInstance(instance)
@@ -135,6 +175,10 @@ func (s *Instance) Insert(instance internal.Instance) error {
 }
 
 func (s *Instance) Update(instance internal.Instance) error {
+	if err := s.encrypt(&instance); err != nil {
+		return err
+	}
+
 	sess := s.NewWriteSession()
 	var lastErr dberr.Error
 	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
@@ -176,5 +220,145 @@ func (s *Instance) GetInstanceStats() (internal.InstanceStats, error) {
 }
 
 func (s *Instance) List(filter dbmodel.InstanceFilter) ([]internal.Instance, int, int, error) {
-	return s.NewReadSession().ListInstances(filter)
+	instances, count, totalCount, err := s.NewReadSession().ListInstances(filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	instances, err = s.decryptAll(instances)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return instances, count, totalCount, nil
+}
+
+func (s *Instance) ListWithOperations(filter dbmodel.RuntimeFilter) ([]internal.InstanceWithOperations, int, int, error) {
+	dtos, count, totalCount, err := s.NewReadSession().ListInstancesWithOperations(filter)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	result := make([]internal.InstanceWithOperations, 0, len(dtos))
+	for _, dto := range dtos {
+		instance, err := s.toInstanceWithOperations(dto)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		result = append(result, instance)
+	}
+	return result, count, totalCount, nil
+}
+
+func (s *Instance) toInstanceWithOperations(dto dbmodel.InstanceWithOperationsDTO) (internal.InstanceWithOperations, error) {
+	if err := s.decrypt(&dto.Instance); err != nil {
+		return internal.InstanceWithOperations{}, err
+	}
+	result := internal.InstanceWithOperations{
+		Instance:         dto.Instance,
+		State:            dto.State,
+		KymaVersion:      dto.KymaVersion,
+		UpgradeKymaTotal: dto.UpgradeKymaTotal,
+		UpgradeKyma:      make([]internal.Operation, 0),
+	}
+	if dto.ProvisioningID.Valid {
+		result.Provisioning = &internal.Operation{
+			ID:              dto.ProvisioningID.String,
+			InstanceID:      dto.InstanceID,
+			State:           domain.LastOperationState(dto.ProvisioningState.String),
+			Description:     dto.ProvisioningDescription.String,
+			CreatedAt:       *dto.ProvisioningCreatedAt,
+			OrchestrationID: storage.SQLNullStringToString(dto.ProvisioningOrchestrationID),
+		}
+	}
+	if dto.DeprovisioningID.Valid {
+		result.Deprovisioning = &internal.Operation{
+			ID:              dto.DeprovisioningID.String,
+			InstanceID:      dto.InstanceID,
+			State:           domain.LastOperationState(dto.DeprovisioningState.String),
+			Description:     dto.DeprovisioningDescription.String,
+			CreatedAt:       *dto.DeprovisioningCreatedAt,
+			OrchestrationID: storage.SQLNullStringToString(dto.DeprovisioningOrchestrationID),
+		}
+	}
+
+	var upgrades []dbmodel.OperationSummaryDTO
+	if err := json.Unmarshal([]byte(dto.UpgradeKymaOperations), &upgrades); err != nil {
+		return internal.InstanceWithOperations{}, errors.Wrapf(err, "while unmarshalling upgrade kyma operations of instance %s", dto.InstanceID)
+	}
+	for _, op := range upgrades {
+		upgrade := internal.Operation{
+			ID:          op.ID,
+			InstanceID:  dto.InstanceID,
+			State:       domain.LastOperationState(op.State),
+			Description: op.Description,
+			CreatedAt:   op.CreatedAt,
+		}
+		if op.OrchestrationID != nil {
+			upgrade.OrchestrationID = *op.OrchestrationID
+		}
+		result.UpgradeKyma = append(result.UpgradeKyma, upgrade)
+	}
+	return result, nil
+}
+
+// ReEncrypt encrypts with the newest key at most batchSize Service Manager credentials of instances with ID greater than
+// afterID which are stored in plain text or encrypted with an older key. Credentials which cannot be decrypted are
+// skipped and logged.
+func (s *Instance) ReEncrypt(afterID string, batchSize int) (internal.ReEncryptionResult, error) {
+	result := internal.ReEncryptionResult{}
+
+	instances, err := s.NewReadSession().ListInstancesToReEncrypt(s.cipher.CurrentPrefix(), afterID, batchSize)
+	if err != nil {
+		return result, errors.Wrap(err, "while listing instances to re-encrypt")
+	}
+	result.Listed = len(instances)
+
+	sess := s.NewWriteSession()
+	for _, instance := range instances {
+		result.LastID = instance.InstanceID
+
+		params, err := reEncryptProvisioningParameters(s.cipher, instance.ProvisioningParameters)
+		if err != nil {
+			log.Errorf("while re-encrypting provisioning parameters of instance %s, skipping: %s", instance.InstanceID, err)
+			result.Skipped++
+			continue
+		}
+		err = sess.UpdateInstanceProvisioningParameters(instance.InstanceID, instance.ProvisioningParameters, params)
+		switch {
+		case dberr.IsConflict(err):
+			// the instance was changed in the meantime, so its credentials are already encrypted with the newest key
+			continue
+		case err != nil:
+			return result, errors.Wrapf(err, "while updating provisioning parameters of instance %s", instance.InstanceID)
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+func (s *Instance) encrypt(instance *internal.Instance) error {
+	params, err := encryptProvisioningParameters(s.cipher, instance.ProvisioningParameters)
+	if err != nil {
+		return errors.Wrapf(err, "while encrypting provisioning parameters of instance %s", instance.InstanceID)
+	}
+	instance.ProvisioningParameters = params
+	return nil
+}
+
+func (s *Instance) decrypt(instance *internal.Instance) error {
+	params, err := decryptProvisioningParameters(s.cipher, instance.ProvisioningParameters)
+	if err != nil {
+		return errors.Wrapf(err, "while decrypting provisioning parameters of instance %s", instance.InstanceID)
+	}
+	instance.ProvisioningParameters = params
+	return nil
+}
+
+func (s *Instance) decryptAll(instances []internal.Instance) ([]internal.Instance, error) {
+	for i := range instances {
+		if err := s.decrypt(&instances[i]); err != nil {
+			return nil, err
+		}
+	}
+	return instances, nil
 }
@@ -0,0 +1,100 @@
+package postsql
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type lmsCertificates struct {
+	dbsession.Factory
+}
+
+func NewLMSCertificates(sess dbsession.Factory) *lmsCertificates {
+	return &lmsCertificates{
+		Factory: sess,
+	}
+}
+
+func (s *lmsCertificates) Get(instanceID string) (internal.LMSCertificate, error) {
+	sess := s.NewReadSession()
+	dto := dbmodel.LMSCertificateDTO{}
+	var lastErr dberr.Error
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		dto, lastErr = sess.GetLMSCertificate(instanceID)
+		if lastErr != nil {
+			if dberr.IsNotFound(lastErr) {
+				return false, dberr.NotFound("LMS certificate for instance %s not found", instanceID)
+			}
+			log.Warnf("while getting LMS certificate: %v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return internal.LMSCertificate{}, lastErr
+	}
+
+	return toLMSCertificate(dto), nil
+}
+
+func (s *lmsCertificates) List() ([]internal.LMSCertificate, error) {
+	sess := s.NewReadSession()
+	var dtos []dbmodel.LMSCertificateDTO
+	var lastErr dberr.Error
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		dtos, lastErr = sess.ListLMSCertificates()
+		if lastErr != nil {
+			log.Warnf("while listing LMS certificates: %v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastErr
+	}
+
+	result := make([]internal.LMSCertificate, 0, len(dtos))
+	for _, dto := range dtos {
+		result = append(result, toLMSCertificate(dto))
+	}
+	return result, nil
+}
+
+// Save updates the certificate of the given instance or creates it if it does not exist yet
+func (s *lmsCertificates) Save(certificate internal.LMSCertificate) error {
+	dto := dbmodel.LMSCertificateDTO{
+		InstanceID: certificate.InstanceID,
+		TenantID:   certificate.TenantID,
+		IssuedAt:   certificate.IssuedAt,
+		ExpiresAt:  certificate.ExpiresAt,
+		UpdatedAt:  time.Now(),
+	}
+	sess := s.NewWriteSession()
+	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		err := sess.UpdateLMSCertificate(dto)
+		if dberr.IsNotFound(err) {
+			err = sess.InsertLMSCertificate(dto)
+		}
+		if err != nil {
+			log.Warnf("while saving LMS certificate for instance %s: %v", certificate.InstanceID, err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+func toLMSCertificate(dto dbmodel.LMSCertificateDTO) internal.LMSCertificate {
+	return internal.LMSCertificate{
+		InstanceID: dto.InstanceID,
+		TenantID:   dto.TenantID,
+		IssuedAt:   dto.IssuedAt,
+		ExpiresAt:  dto.ExpiresAt,
+		UpdatedAt:  dto.UpdatedAt,
+	}
+}
@@ -21,18 +21,21 @@ import (
 
 type operations struct {
 	dbsession.Factory
+
+	cipher Cipher
 }
 
-func NewOperation(sess dbsession.Factory) *operations {
+func NewOperation(sess dbsession.Factory, cipher Cipher) *operations {
 	return &operations{
 		Factory: sess,
+		cipher:  cipher,
 	}
 }
 
 // InsertProvisioningOperation insert new ProvisioningOperation to storage
 func (s *operations) InsertProvisioningOperation(operation internal.ProvisioningOperation) error {
 	session := s.NewWriteSession()
-	dto, err := provisioningOperationToDTO(&operation)
+	dto, err := s.provisioningOperationToDTO(&operation)
 	if err != nil {
 		return errors.Wrapf(err, "while inserting provisioning operation (id: %s)", operation.ID)
 	}
@@ -40,7 +43,10 @@ func (s *operations) InsertProvisioningOperation(operation internal.Provisioning
 	_ = wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
 		lastErr = session.InsertOperation(dto)
 		if lastErr != nil {
-			log.Warn(errors.Wrap(err, "while insert operation"))
+			if dberr.IsAlreadyExists(lastErr) {
+				return false, lastErr
+			}
+			log.Warn(errors.Wrap(lastErr, "while insert operation"))
 			return false, nil
 		}
 		return true, nil
@@ -68,7 +74,7 @@ func (s *operations) GetProvisioningOperationByID(operationID string) (*internal
 	if err != nil {
 		return nil, errors.Wrap(err, "while getting operation by ID")
 	}
-	ret, err := toProvisioningOperation(&operation)
+	ret, err := s.toProvisioningOperation(&operation)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting DTO to Operation")
 	}
@@ -96,7 +102,7 @@ func (s *operations) GetProvisioningOperationByInstanceID(instanceID string) (*i
 	if err != nil {
 		return nil, lastErr
 	}
-	ret, err := toProvisioningOperation(&operation)
+	ret, err := s.toProvisioningOperation(&operation)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting DTO to Operation")
 	}
@@ -108,7 +114,7 @@ func (s *operations) GetProvisioningOperationByInstanceID(instanceID string) (*i
 func (s *operations) UpdateProvisioningOperation(op internal.ProvisioningOperation) (*internal.ProvisioningOperation, error) {
 	session := s.NewWriteSession()
 	op.UpdatedAt = time.Now()
-	dto, err := provisioningOperationToDTO(&op)
+	dto, err := s.provisioningOperationToDTO(&op)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting Operation to DTO")
 	}
@@ -138,7 +144,7 @@ func (s *operations) UpdateProvisioningOperation(op internal.ProvisioningOperati
 func (s *operations) InsertDeprovisioningOperation(operation internal.DeprovisioningOperation) error {
 	session := s.NewWriteSession()
 
-	dto, err := deprovisioningOperationToDTO(&operation)
+	dto, err := s.deprovisioningOperationToDTO(&operation)
 	if err != nil {
 		return errors.Wrapf(err, "while converting Operation to DTO")
 	}
@@ -147,7 +153,10 @@ func (s *operations) InsertDeprovisioningOperation(operation internal.Deprovisio
 	_ = wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
 		lastErr = session.InsertOperation(dto)
 		if lastErr != nil {
-			log.Warn(errors.Wrap(err, "while insert operation"))
+			if dberr.IsAlreadyExists(lastErr) {
+				return false, lastErr
+			}
+			log.Warn(errors.Wrap(lastErr, "while insert operation"))
 			return false, nil
 		}
 		return true, nil
@@ -175,7 +184,7 @@ func (s *operations) GetDeprovisioningOperationByID(operationID string) (*intern
 	if err != nil {
 		return nil, errors.Wrap(err, "while getting operation by ID")
 	}
-	ret, err := toDeprovisioningOperation(&operation)
+	ret, err := s.toDeprovisioningOperation(&operation)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting DTO to Operation")
 	}
@@ -203,7 +212,7 @@ func (s *operations) GetDeprovisioningOperationByInstanceID(instanceID string) (
 	if err != nil {
 		return nil, lastErr
 	}
-	ret, err := toDeprovisioningOperation(&operation)
+	ret, err := s.toDeprovisioningOperation(&operation)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting DTO to Operation")
 	}
@@ -216,7 +225,7 @@ func (s *operations) UpdateDeprovisioningOperation(operation internal.Deprovisio
 	session := s.NewWriteSession()
 	operation.UpdatedAt = time.Now()
 
-	dto, err := deprovisioningOperationToDTO(&operation)
+	dto, err := s.deprovisioningOperationToDTO(&operation)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting Operation to DTO")
 	}
@@ -245,7 +254,7 @@ func (s *operations) UpdateDeprovisioningOperation(operation internal.Deprovisio
 // InsertUpgradeKymaOperation insert new UpgradeKymaOperation to storage
 func (s *operations) InsertUpgradeKymaOperation(operation internal.UpgradeKymaOperation) error {
 	session := s.NewWriteSession()
-	dto, err := upgradeKymaOperationToDTO(&operation)
+	dto, err := s.upgradeKymaOperationToDTO(&operation)
 	if err != nil {
 		return errors.Wrapf(err, "while inserting upgrade kyma operation (id: %s)", operation.Operation.ID)
 	}
@@ -253,7 +262,10 @@ func (s *operations) InsertUpgradeKymaOperation(operation internal.UpgradeKymaOp
 	_ = wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
 		lastErr = session.InsertOperation(dto)
 		if lastErr != nil {
-			log.Warn(errors.Wrap(err, "while insert operation"))
+			if dberr.IsAlreadyExists(lastErr) || dberr.IsConflict(lastErr) {
+				return false, lastErr
+			}
+			log.Warn(errors.Wrap(lastErr, "while insert operation"))
 			return false, nil
 		}
 
@@ -283,7 +295,7 @@ func (s *operations) GetUpgradeKymaOperationByID(operationID string) (*internal.
 	if err != nil {
 		return nil, errors.Wrap(err, "while getting operation by ID")
 	}
-	ret, err := toUpgradeKymaOperation(&operation)
+	ret, err := s.toUpgradeKymaOperation(&operation)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting DTO to Operation")
 	}
@@ -311,7 +323,7 @@ func (s *operations) GetUpgradeKymaOperationByInstanceID(instanceID string) (*in
 	if err != nil {
 		return nil, lastErr
 	}
-	ret, err := toUpgradeKymaOperation(&operation)
+	ret, err := s.toUpgradeKymaOperation(&operation)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting DTO to Operation")
 	}
@@ -334,7 +346,7 @@ func (s *operations) ListUpgradeKymaOperationsByInstanceID(instanceID string) ([
 	if err != nil {
 		return nil, lastErr
 	}
-	ret, err := toUpgradeKymaOperationList(operations)
+	ret, err := s.toUpgradeKymaOperationList(operations)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting DTO to Operation")
 	}
@@ -346,7 +358,7 @@ func (s *operations) ListUpgradeKymaOperationsByInstanceID(instanceID string) ([
 func (s *operations) UpdateUpgradeKymaOperation(operation internal.UpgradeKymaOperation) (*internal.UpgradeKymaOperation, error) {
 	session := s.NewWriteSession()
 	operation.UpdatedAt = time.Now()
-	dto, err := upgradeKymaOperationToDTO(&operation)
+	dto, err := s.upgradeKymaOperationToDTO(&operation)
 	if err != nil {
 		return nil, errors.Wrapf(err, "while converting Operation to DTO")
 	}
@@ -487,7 +499,7 @@ func (s *operations) ListUpgradeKymaOperationsByOrchestrationID(orchestrationID
 	if err != nil {
 		return nil, -1, -1, errors.Wrapf(err, "while getting operation by ID: %v", lastErr)
 	}
-	ret, err := toUpgradeKymaOperationList(operations)
+	ret, err := s.toUpgradeKymaOperationList(operations)
 	if err != nil {
 		return nil, -1, -1, errors.Wrapf(err, "while converting DTO to Operation")
 	}
@@ -495,6 +507,99 @@ func (s *operations) ListUpgradeKymaOperationsByOrchestrationID(orchestrationID
 	return ret, count, totalCount, nil
 }
 
+// ReEncrypt encrypts with the newest key at most batchSize operations with ID greater than afterID which contain
+// Service Manager credentials, LMS certificate private key or IAS client secrets stored in plain text or encrypted
+// with an older key. Operations with values which cannot be decrypted are skipped and logged.
+func (s *operations) ReEncrypt(afterID string, batchSize int) (internal.ReEncryptionResult, error) {
+	result := internal.ReEncryptionResult{}
+
+	operations, err := s.NewReadSession().ListOperationsToReEncrypt(s.cipher.CurrentPrefix(), afterID, batchSize)
+	if err != nil {
+		return result, errors.Wrap(err, "while listing operations to re-encrypt")
+	}
+	result.Listed = len(operations)
+
+	sess := s.NewWriteSession()
+	for _, op := range operations {
+		result.LastID = op.ID
+
+		data, err := s.reEncryptOperationData(op.Data)
+		if err != nil {
+			log.Errorf("while re-encrypting data of operation %s, skipping: %s", op.ID, err)
+			result.Skipped++
+			continue
+		}
+		err = sess.UpdateOperationData(op.ID, op.Version, data)
+		switch {
+		case dberr.IsConflict(err):
+			// the operation was changed in the meantime, so its data is already encrypted with the newest key
+			continue
+		case err != nil:
+			return result, errors.Wrapf(err, "while updating data of operation %s", op.ID)
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// reEncryptOperationData replaces only the encrypted fields, so data of every operation type is preserved
+func (s *operations) reEncryptOperationData(data string) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return "", errors.Wrap(err, "while unmarshalling operation data")
+	}
+
+	if raw, found := fields["provisioning_parameters"]; found {
+		var params string
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return "", errors.Wrap(err, "while unmarshalling provisioning parameters")
+		}
+		params, err := reEncryptProvisioningParameters(s.cipher, params)
+		if err != nil {
+			return "", err
+		}
+		if fields["provisioning_parameters"], err = json.Marshal(params); err != nil {
+			return "", errors.Wrap(err, "while marshalling provisioning parameters")
+		}
+	}
+
+	if raw, found := fields["lms_certificate_renewal"]; found {
+		renewal, err := transformJSONObject(raw, nil, func(renewal map[string]json.RawMessage) error {
+			return reEncryptJSONString(s.cipher, renewal, "private_key")
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "while re-encrypting LMS certificate private key")
+		}
+		fields["lms_certificate_renewal"] = renewal
+	}
+
+	if raw, found := fields["ias_secrets"]; found {
+		secrets, err := transformJSONObject(raw, nil, func(secrets map[string]json.RawMessage) error {
+			for name, secret := range secrets {
+				transformed, err := transformJSONObject(secret, nil, func(secret map[string]json.RawMessage) error {
+					return reEncryptJSONString(s.cipher, secret, "client_secret")
+				})
+				if err != nil {
+					return errors.Wrapf(err, "for ServiceProvider %s", name)
+				}
+				secrets[name] = transformed
+			}
+			return nil
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "while re-encrypting IAS client secrets")
+		}
+		fields["ias_secrets"] = secrets
+	}
+
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return "", errors.Wrap(err, "while marshalling operation data")
+	}
+	return string(result), nil
+}
+
 func toOperation(op *dbmodel.OperationDTO) internal.Operation {
 	return internal.Operation{
 		ID:                     op.ID,
@@ -517,7 +622,7 @@ func toOperations(op []dbmodel.OperationDTO) []internal.Operation {
 	return operations
 }
 
-func toProvisioningOperation(op *dbmodel.OperationDTO) (*internal.ProvisioningOperation, error) {
+func (s *operations) toProvisioningOperation(op *dbmodel.OperationDTO) (*internal.ProvisioningOperation, error) {
 	if op.Type != dbmodel.OperationTypeProvision {
 		return nil, errors.New(fmt.Sprintf("expected operation type Provisioning, but was %s", op.Type))
 	}
@@ -527,12 +632,22 @@ func toProvisioningOperation(op *dbmodel.OperationDTO) (*internal.ProvisioningOp
 		return nil, errors.New("unable to unmarshall provisioning data")
 	}
 	operation.Operation = toOperation(op)
+	operation.ProvisioningParameters, err = decryptProvisioningParameters(s.cipher, operation.ProvisioningParameters)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while decrypting provisioning parameters of operation %s", op.ID)
+	}
 
 	return &operation, nil
 }
 
-func provisioningOperationToDTO(op *internal.ProvisioningOperation) (dbmodel.OperationDTO, error) {
-	serialized, err := json.Marshal(op)
+func (s *operations) provisioningOperationToDTO(op *internal.ProvisioningOperation) (dbmodel.OperationDTO, error) {
+	encrypted := *op
+	params, err := encryptProvisioningParameters(s.cipher, op.ProvisioningParameters)
+	if err != nil {
+		return dbmodel.OperationDTO{}, errors.Wrapf(err, "while encrypting provisioning parameters of operation %s", op.ID)
+	}
+	encrypted.ProvisioningParameters = params
+	serialized, err := json.Marshal(encrypted)
 	if err != nil {
 		return dbmodel.OperationDTO{}, errors.Wrapf(err, "while serializing provisioning data %v", op)
 	}
@@ -543,7 +658,7 @@ func provisioningOperationToDTO(op *internal.ProvisioningOperation) (dbmodel.Ope
 	return ret, nil
 }
 
-func toDeprovisioningOperation(op *dbmodel.OperationDTO) (*internal.DeprovisioningOperation, error) {
+func (s *operations) toDeprovisioningOperation(op *dbmodel.OperationDTO) (*internal.DeprovisioningOperation, error) {
 	if op.Type != dbmodel.OperationTypeDeprovision {
 		return nil, errors.New(fmt.Sprintf("expected operation type Provisioning, but was %s", op.Type))
 	}
@@ -553,12 +668,22 @@ func toDeprovisioningOperation(op *dbmodel.OperationDTO) (*internal.Deprovisioni
 		return nil, errors.New("unable to unmarshall provisioning data")
 	}
 	operation.Operation = toOperation(op)
+	operation.ProvisioningParameters, err = decryptProvisioningParameters(s.cipher, operation.ProvisioningParameters)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while decrypting provisioning parameters of operation %s", op.ID)
+	}
 
 	return &operation, nil
 }
 
-func deprovisioningOperationToDTO(op *internal.DeprovisioningOperation) (dbmodel.OperationDTO, error) {
-	serialized, err := json.Marshal(op)
+func (s *operations) deprovisioningOperationToDTO(op *internal.DeprovisioningOperation) (dbmodel.OperationDTO, error) {
+	encrypted := *op
+	params, err := encryptProvisioningParameters(s.cipher, op.ProvisioningParameters)
+	if err != nil {
+		return dbmodel.OperationDTO{}, errors.Wrapf(err, "while encrypting provisioning parameters of operation %s", op.ID)
+	}
+	encrypted.ProvisioningParameters = params
+	serialized, err := json.Marshal(encrypted)
 	if err != nil {
 		return dbmodel.OperationDTO{}, errors.Wrapf(err, "while serializing deprovisioning data %v", op)
 	}
@@ -569,7 +694,7 @@ func deprovisioningOperationToDTO(op *internal.DeprovisioningOperation) (dbmodel
 	return ret, nil
 }
 
-func toUpgradeKymaOperation(op *dbmodel.OperationDTO) (*internal.UpgradeKymaOperation, error) {
+func (s *operations) toUpgradeKymaOperation(op *dbmodel.OperationDTO) (*internal.UpgradeKymaOperation, error) {
 	if op.Type != dbmodel.OperationTypeUpgradeKyma {
 		return nil, errors.New(fmt.Sprintf("expected operation type Upgrade Kyma, but was %s", op.Type))
 	}
@@ -579,6 +704,21 @@ func toUpgradeKymaOperation(op *dbmodel.OperationDTO) (*internal.UpgradeKymaOper
 		return nil, errors.New("unable to unmarshall provisioning data")
 	}
 	operation.Operation = toOperation(op)
+	operation.ProvisioningParameters, err = decryptProvisioningParameters(s.cipher, operation.ProvisioningParameters)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while decrypting provisioning parameters of operation %s", op.ID)
+	}
+	operation.LMSCertificateRenewal.PrivateKey, err = decryptValue(s.cipher, operation.LMSCertificateRenewal.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while decrypting LMS certificate private key of operation %s", op.ID)
+	}
+	for name, secret := range operation.IASSecrets {
+		secret.ClientSecret, err = decryptValue(s.cipher, secret.ClientSecret)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while decrypting IAS client secret of operation %s", op.ID)
+		}
+		operation.IASSecrets[name] = secret
+	}
 	operation.RuntimeOperation.ID = op.ID
 	if op.OrchestrationID.Valid {
 		operation.OrchestrationID = op.OrchestrationID.String
@@ -587,11 +727,11 @@ func toUpgradeKymaOperation(op *dbmodel.OperationDTO) (*internal.UpgradeKymaOper
 	return &operation, nil
 }
 
-func toUpgradeKymaOperationList(ops []dbmodel.OperationDTO) ([]internal.UpgradeKymaOperation, error) {
+func (s *operations) toUpgradeKymaOperationList(ops []dbmodel.OperationDTO) ([]internal.UpgradeKymaOperation, error) {
 	result := make([]internal.UpgradeKymaOperation, 0)
 
 	for _, op := range ops {
-		o, err := toUpgradeKymaOperation(&op)
+		o, err := s.toUpgradeKymaOperation(&op)
 		if err != nil {
 			return nil, errors.Wrap(err, "while converting to upgrade kyma operation")
 		}
@@ -601,8 +741,29 @@ func toUpgradeKymaOperationList(ops []dbmodel.OperationDTO) ([]internal.UpgradeK
 	return result, nil
 }
 
-func upgradeKymaOperationToDTO(op *internal.UpgradeKymaOperation) (dbmodel.OperationDTO, error) {
-	serialized, err := json.Marshal(op)
+func (s *operations) upgradeKymaOperationToDTO(op *internal.UpgradeKymaOperation) (dbmodel.OperationDTO, error) {
+	encrypted := *op
+	params, err := encryptProvisioningParameters(s.cipher, op.ProvisioningParameters)
+	if err != nil {
+		return dbmodel.OperationDTO{}, errors.Wrapf(err, "while encrypting provisioning parameters of operation %s", op.Operation.ID)
+	}
+	encrypted.ProvisioningParameters = params
+	privateKey, err := encryptValue(s.cipher, op.LMSCertificateRenewal.PrivateKey)
+	if err != nil {
+		return dbmodel.OperationDTO{}, errors.Wrapf(err, "while encrypting LMS certificate private key of operation %s", op.Operation.ID)
+	}
+	encrypted.LMSCertificateRenewal.PrivateKey = privateKey
+	if op.IASSecrets != nil {
+		encrypted.IASSecrets = make(map[string]internal.IASClientSecret, len(op.IASSecrets))
+		for name, secret := range op.IASSecrets {
+			secret.ClientSecret, err = encryptValue(s.cipher, secret.ClientSecret)
+			if err != nil {
+				return dbmodel.OperationDTO{}, errors.Wrapf(err, "while encrypting IAS client secret of operation %s", op.Operation.ID)
+			}
+			encrypted.IASSecrets[name] = secret
+		}
+	}
+	serialized, err := json.Marshal(encrypted)
 	if err != nil {
 		return dbmodel.OperationDTO{}, errors.Wrapf(err, "while serializing provisioning data %v", op)
 	}
@@ -0,0 +1,57 @@
+package postsql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperations_reEncryptOperationData(t *testing.T) {
+	t.Run("should re-encrypt all encrypted fields and keep the rest of the data", func(t *testing.T) {
+		// given
+		data := `{"plan_id":"plan",` +
+			`"provisioning_parameters":"{\"ers_context\":{\"sm_platform_credentials\":{\"credentials\":{\"basic\":{\"username\":\"user\",\"password\":\"pass\"}}}}}",` +
+			`"lms_certificate_renewal":{"certificate_url":"https://lms.example.com","private_key":"key"},` +
+			`"ias_secrets":{"grafana":{"client_id":"client","client_secret":"enc:secret"}}}`
+		svc := &operations{cipher: fakeCipher{}}
+
+		// when
+		reEncrypted, err := svc.reEncryptOperationData(data)
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, reEncrypted, `"plan_id":"plan"`)
+		assert.Contains(t, reEncrypted, `\"password\":\"enc:pass\"`)
+		assert.Contains(t, reEncrypted, `"certificate_url":"https://lms.example.com"`)
+		assert.Contains(t, reEncrypted, `"private_key":"enc:key"`)
+		assert.Contains(t, reEncrypted, `"client_id":"client"`)
+		assert.Contains(t, reEncrypted, `"client_secret":"enc:secret"`)
+	})
+
+	t.Run("should return error when the value cannot be decrypted", func(t *testing.T) {
+		// given
+		data := `{"lms_certificate_renewal":{"private_key":"enc:key"}}`
+		svc := &operations{cipher: unknownKeyCipher{}}
+
+		// when
+		_, err := svc.reEncryptOperationData(data)
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+// unknownKeyCipher fails to decrypt values, as if they were encrypted with a removed key
+type unknownKeyCipher struct {
+	fakeCipher
+}
+
+func (unknownKeyCipher) Decrypt(_ []byte) ([]byte, error) {
+	return nil, errors.New("unknown encryption key")
+}
+
+func (unknownKeyCipher) IsEncrypted(_ []byte) bool {
+	return false
+}
@@ -0,0 +1,172 @@
+package postsql
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// encryptProvisioningParameters encrypts the Service Manager credentials in the serialized provisioning parameters,
+// the rest of the parameters is stored as plain text to keep them searchable
+func encryptProvisioningParameters(cipher Cipher, params string) (string, error) {
+	return transformServiceManagerCredentials(params, func(value string) (string, error) {
+		encrypted, err := encryptValue(cipher, value)
+		if err != nil {
+			return "", errors.Wrap(err, "while encrypting Service Manager credentials")
+		}
+		return encrypted, nil
+	})
+}
+
+// decryptProvisioningParameters decrypts the Service Manager credentials in the serialized provisioning parameters.
+// Credentials stored before the encryption was introduced are returned as they are.
+func decryptProvisioningParameters(cipher Cipher, params string) (string, error) {
+	return transformServiceManagerCredentials(params, func(value string) (string, error) {
+		decrypted, err := decryptValue(cipher, value)
+		if err != nil {
+			return "", errors.Wrap(err, "while decrypting Service Manager credentials")
+		}
+		return decrypted, nil
+	})
+}
+
+// encryptValue encrypts the non empty value which is not encrypted yet
+func encryptValue(cipher Cipher, value string) (string, error) {
+	if value == "" || cipher.IsEncrypted([]byte(value)) {
+		return value, nil
+	}
+	encrypted, err := cipher.Encrypt([]byte(value))
+	if err != nil {
+		return "", err
+	}
+	return string(encrypted), nil
+}
+
+// decryptValue decrypts the value, values stored before the encryption was introduced are returned as they are.
+// The value which has the format of an encrypted value but cannot be decrypted is reported as an error.
+func decryptValue(cipher Cipher, value string) (string, error) {
+	if !cipher.HasEncryptedFormat([]byte(value)) {
+		return value, nil
+	}
+	decrypted, err := cipher.Decrypt([]byte(value))
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+// reEncryptValue encrypts the value with the newest key. The value which has the format of an encrypted value
+// but cannot be decrypted, e.g. because its key was removed, is reported as an error instead of being taken as plain text.
+func reEncryptValue(cipher Cipher, value string) (string, error) {
+	if cipher.HasEncryptedFormat([]byte(value)) {
+		decrypted, err := cipher.Decrypt([]byte(value))
+		if err != nil {
+			return "", err
+		}
+		value = string(decrypted)
+	}
+	return encryptValue(cipher, value)
+}
+
+// reEncryptProvisioningParameters encrypts the Service Manager credentials with the newest key
+func reEncryptProvisioningParameters(cipher Cipher, params string) (string, error) {
+	return transformServiceManagerCredentials(params, func(value string) (string, error) {
+		reEncrypted, err := reEncryptValue(cipher, value)
+		if err != nil {
+			return "", errors.Wrap(err, "while re-encrypting Service Manager credentials")
+		}
+		return reEncrypted, nil
+	})
+}
+
+// reEncryptJSONString encrypts the string field of the JSON object with the newest key, the field is left unchanged
+// when it does not exist
+func reEncryptJSONString(cipher Cipher, object map[string]json.RawMessage, field string) error {
+	raw, found := object[field]
+	if !found {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return errors.Wrapf(err, "while unmarshalling %s", field)
+	}
+	reEncrypted, err := reEncryptValue(cipher, value)
+	if err != nil {
+		return errors.Wrapf(err, "while re-encrypting %s", field)
+	}
+	if object[field], err = json.Marshal(reEncrypted); err != nil {
+		return errors.Wrapf(err, "while marshalling %s", field)
+	}
+	return nil
+}
+
+// serviceManagerCredentialsPath is the path of the object with the Service Manager credentials in the serialized
+// provisioning parameters
+var serviceManagerCredentialsPath = []string{"ers_context", "sm_platform_credentials", "credentials", "basic"}
+
+// transformServiceManagerCredentials transforms the Service Manager username and password in the raw JSON,
+// so the fields which are not known by the internal.ProvisioningParameters are kept
+func transformServiceManagerCredentials(params string, transform func(string) (string, error)) (string, error) {
+	if params == "" {
+		return params, nil
+	}
+
+	result, err := transformJSONObject(json.RawMessage(params), serviceManagerCredentialsPath, func(basicAuth map[string]json.RawMessage) error {
+		for _, field := range []string{"username", "password"} {
+			raw, found := basicAuth[field]
+			if !found {
+				continue
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return errors.Wrapf(err, "while unmarshalling Service Manager %s", field)
+			}
+			transformed, err := transform(value)
+			if err != nil {
+				return err
+			}
+			if basicAuth[field], err = json.Marshal(transformed); err != nil {
+				return errors.Wrapf(err, "while marshalling Service Manager %s", field)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "while transforming provisioning parameters")
+	}
+	return string(result), nil
+}
+
+// transformJSONObject calls the transform for the object under the given path, the raw JSON is returned unchanged
+// when the path does not exist
+func transformJSONObject(raw json.RawMessage, path []string, transform func(map[string]json.RawMessage) error) (json.RawMessage, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return raw, nil
+	}
+
+	if len(path) == 0 {
+		if err := transform(object); err != nil {
+			return nil, err
+		}
+		return json.Marshal(object)
+	}
+
+	field, found := object[path[0]]
+	if !found {
+		return raw, nil
+	}
+	transformed, err := transformJSONObject(field, path[1:], transform)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(transformed, field) {
+		return raw, nil
+	}
+	object[path[0]] = transformed
+	return json.Marshal(object)
+}
@@ -0,0 +1,99 @@
+package postsql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptProvisioningParameters(t *testing.T) {
+	t.Run("should encrypt Service Manager credentials and keep unknown fields", func(t *testing.T) {
+		// given
+		params := `{"plan_id":"plan","future_field":{"a":1},"ers_context":{"tenant_id":"tenant","sm_platform_credentials":{"url":"https://sm.example.com","credentials":{"basic":{"username":"user","password":"pass","extra":true}}}}}`
+
+		// when
+		encrypted, err := encryptProvisioningParameters(fakeCipher{}, params)
+		require.NoError(t, err)
+		decrypted, err := decryptProvisioningParameters(fakeCipher{}, encrypted)
+		require.NoError(t, err)
+
+		// then
+		assert.Contains(t, encrypted, `"username":"enc:user"`)
+		assert.Contains(t, encrypted, `"password":"enc:pass"`)
+		assert.Contains(t, encrypted, `"future_field":{"a":1}`)
+		assert.Contains(t, encrypted, `"extra":true`)
+		assert.JSONEq(t, params, decrypted)
+	})
+
+	t.Run("should not change parameters without Service Manager credentials", func(t *testing.T) {
+		for _, params := range []string{
+			``,
+			`{"plan_id":"plan"}`,
+			`{"plan_id":"plan","ers_context":{"tenant_id":"tenant"}}`,
+			`{"plan_id":"plan","ers_context":{"tenant_id":"tenant","sm_platform_credentials":null}}`,
+		} {
+			// when
+			encrypted, err := encryptProvisioningParameters(fakeCipher{}, params)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, params, encrypted)
+		}
+	})
+}
+
+func TestDecryptProvisioningParameters(t *testing.T) {
+	t.Run("should return plain text credentials stored before the encryption was introduced", func(t *testing.T) {
+		// given
+		params := `{"ers_context":{"sm_platform_credentials":{"credentials":{"basic":{"username":"user","password":"pass"}}}}}`
+
+		// when
+		decrypted, err := decryptProvisioningParameters(fakeCipher{}, params)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, params, decrypted)
+	})
+
+	t.Run("should fail when credentials are encrypted with a key which is not configured", func(t *testing.T) {
+		// given
+		params := `{"ers_context":{"sm_platform_credentials":{"credentials":{"basic":{"username":"enc:user","password":"enc:removed:pass"}}}}}`
+
+		// when
+		_, err := decryptProvisioningParameters(fakeCipher{}, params)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown encryption key removed")
+	})
+}
+
+type fakeCipher struct{}
+
+func (fakeCipher) Encrypt(text []byte) ([]byte, error) {
+	return []byte("enc:" + string(text)), nil
+}
+
+// Decrypt fails for values with the "enc:removed:" prefix, as if they were encrypted with a key which is not configured
+func (fakeCipher) Decrypt(text []byte) ([]byte, error) {
+	if strings.HasPrefix(string(text), "enc:removed:") {
+		return nil, errors.New("unknown encryption key removed")
+	}
+	return []byte(strings.TrimPrefix(string(text), "enc:")), nil
+}
+
+func (c fakeCipher) IsEncrypted(text []byte) bool {
+	_, err := c.Decrypt(text)
+	return c.HasEncryptedFormat(text) && err == nil
+}
+
+func (fakeCipher) HasEncryptedFormat(text []byte) bool {
+	return strings.HasPrefix(string(text), "enc:")
+}
+
+func (fakeCipher) CurrentPrefix() string {
+	return "enc:"
+}
@@ -35,6 +35,9 @@ func (s *runtimeState) Insert(runtimeState internal.RuntimeState) error {
 	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
 		err := sess.InsertRuntimeState(state)
 		if err != nil {
+			if dberr.IsAlreadyExists(err) {
+				return false, err
+			}
 			log.Warnf("while saving runtime state ID %s: %v", runtimeState.ID, err)
 			return false, nil
 		}
@@ -93,6 +96,45 @@ func (s *runtimeState) GetByOperationID(operationID string) (internal.RuntimeSta
 	return result, nil
 }
 
+// ReEncrypt encrypts with the newest key at most batchSize kyma configs with ID greater than afterID which are encrypted
+// with an older key. Kyma configs which cannot be decrypted are skipped and logged.
+func (s *runtimeState) ReEncrypt(afterID string, batchSize int) (internal.ReEncryptionResult, error) {
+	result := internal.ReEncryptionResult{}
+
+	states, err := s.NewReadSession().ListRuntimeStatesToReEncrypt(s.cipher.CurrentPrefix(), afterID, batchSize)
+	if err != nil {
+		return result, errors.Wrap(err, "while listing runtime states to re-encrypt")
+	}
+	result.Listed = len(states)
+
+	sess := s.NewWriteSession()
+	for _, state := range states {
+		result.LastID = state.ID
+
+		cfg, err := s.cipher.Decrypt([]byte(state.KymaConfig))
+		if err != nil {
+			log.Errorf("while decrypting kyma config of runtime state %s, skipping: %s", state.ID, err)
+			result.Skipped++
+			continue
+		}
+		encCfg, err := s.cipher.Encrypt(cfg)
+		if err != nil {
+			return result, errors.Wrapf(err, "while encrypting kyma config of runtime state %s", state.ID)
+		}
+		err = sess.UpdateRuntimeStateKymaConfig(state.ID, state.KymaConfig, string(encCfg))
+		switch {
+		case dberr.IsConflict(err):
+			// the kyma config was changed in the meantime, so it is already encrypted with the newest key
+			continue
+		case err != nil:
+			return result, errors.Wrapf(err, "while updating kyma config of runtime state %s", state.ID)
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
 func (s *runtimeState) runtimeStateToDB(op internal.RuntimeState) (dbmodel.RuntimeStateDTO, error) {
 	kymaCfg, err := json.Marshal(op.KymaConfig)
 	if err != nil {
@@ -0,0 +1,112 @@
+package postsql
+
+import (
+	"encoding/json"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type subAccountCleanupRuns struct {
+	dbsession.Factory
+}
+
+type subAccountCleanupRunData struct {
+	SubAccounts    []string                                `json:"subAccounts"`
+	GlobalAccounts []string                                `json:"globalAccounts,omitempty"`
+	Instances      []internal.SubAccountCleanupRunInstance `json:"instances"`
+	LookupErrors   []string                                `json:"lookupErrors,omitempty"`
+}
+
+func NewSubAccountCleanupRuns(sess dbsession.Factory) *subAccountCleanupRuns {
+	return &subAccountCleanupRuns{
+		Factory: sess,
+	}
+}
+
+func (s *subAccountCleanupRuns) Insert(run internal.SubAccountCleanupRun) error {
+	dto, err := s.toDTO(run)
+	if err != nil {
+		return err
+	}
+	sess := s.NewWriteSession()
+	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		err := sess.InsertSubAccountCleanupRun(dto)
+		if err != nil {
+			log.Warnf("while saving subaccount cleanup run ID %s: %v", run.ID, err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+func (s *subAccountCleanupRuns) GetByID(runID string) (internal.SubAccountCleanupRun, error) {
+	sess := s.NewReadSession()
+	dto := dbmodel.SubAccountCleanupRunDTO{}
+	var lastErr dberr.Error
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		dto, lastErr = sess.GetSubAccountCleanupRunByID(runID)
+		if lastErr != nil {
+			if dberr.IsNotFound(lastErr) {
+				return false, dberr.NotFound("SubAccountCleanupRun with ID %s not found", runID)
+			}
+			log.Warnf("while getting SubAccountCleanupRun: %v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return internal.SubAccountCleanupRun{}, lastErr
+	}
+
+	return s.toSubAccountCleanupRun(dto)
+}
+
+func (s *subAccountCleanupRuns) toDTO(run internal.SubAccountCleanupRun) (dbmodel.SubAccountCleanupRunDTO, error) {
+	data, err := json.Marshal(subAccountCleanupRunData{
+		SubAccounts:    run.SubAccounts,
+		GlobalAccounts: run.GlobalAccounts,
+		Instances:      run.Instances,
+		LookupErrors:   run.LookupErrors,
+	})
+	if err != nil {
+		return dbmodel.SubAccountCleanupRunDTO{}, errors.Wrap(err, "while encoding subaccount cleanup run data")
+	}
+
+	return dbmodel.SubAccountCleanupRunDTO{
+		ID:          run.ID,
+		DryRun:      run.DryRun,
+		Aborted:     run.Aborted,
+		Description: run.Description,
+		Data:        string(data),
+		CreatedAt:   run.CreatedAt,
+		FinishedAt:  run.FinishedAt,
+	}, nil
+}
+
+func (s *subAccountCleanupRuns) toSubAccountCleanupRun(dto dbmodel.SubAccountCleanupRunDTO) (internal.SubAccountCleanupRun, error) {
+	var data subAccountCleanupRunData
+	if dto.Data != "" {
+		if err := json.Unmarshal([]byte(dto.Data), &data); err != nil {
+			return internal.SubAccountCleanupRun{}, errors.Wrap(err, "while unmarshalling subaccount cleanup run data")
+		}
+	}
+
+	return internal.SubAccountCleanupRun{
+		ID:             dto.ID,
+		DryRun:         dto.DryRun,
+		Aborted:        dto.Aborted,
+		Description:    dto.Description,
+		SubAccounts:    data.SubAccounts,
+		GlobalAccounts: data.GlobalAccounts,
+		Instances:      data.Instances,
+		LookupErrors:   data.LookupErrors,
+		CreatedAt:      dto.CreatedAt,
+		FinishedAt:     dto.FinishedAt,
+	}, nil
+}
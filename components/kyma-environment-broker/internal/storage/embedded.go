@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocraft/dbr"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	migrationUpSuffix     = ".up.sql"
+	schemaMigrationsTable = "schema_migrations"
+)
+
+// EmbeddedConfig configures the PostgreSQL server started by the broker process,
+// it is meant for local development and tests which need the same storage semantics as production.
+// The server is not linked into the binary, the PostgreSQL binaries (initdb, pg_ctl, createdb) must be installed.
+type EmbeddedConfig struct {
+	Port uint32 `envconfig:"default=15432"`
+	// BinariesPath is the directory with the PostgreSQL binaries (initdb, pg_ctl, createdb), PATH is used when empty
+	BinariesPath string `envconfig:"optional"`
+	// RuntimePath is the directory where the data is stored, a temporary directory removed at stop is used when empty
+	RuntimePath string `envconfig:"optional"`
+	// MigrationsPath points to the schema-migrator migrations of the broker which are applied at startup, it is required
+	// when the embedded server is used
+	MigrationsPath string        `envconfig:"optional"`
+	StartTimeout   time.Duration `envconfig:"default=60s"`
+	// SecretKey is used to encrypt the secrets stored in the database, it is required when the embedded server is used
+	SecretKey string `envconfig:"optional"`
+}
+
+// NewEmbeddedStorage starts a PostgreSQL server as a child process by running initdb, pg_ctl and createdb
+// from BinariesPath or PATH, applies the migrations and returns the storage which uses the PostgreSQL drivers.
+// It fails when the binaries are not installed. The returned function stops the server.
+func NewEmbeddedStorage(cfg EmbeddedConfig, log logrus.FieldLogger) (BrokerStorage, func() error, error) {
+	if cfg.MigrationsPath == "" {
+		return nil, nil, errors.New("migrations path of the embedded PostgreSQL is required")
+	}
+	if cfg.SecretKey == "" {
+		return nil, nil, errors.New("secret key of the embedded PostgreSQL is required")
+	}
+
+	dbCfg := Config{
+		User:            "postgres",
+		Password:        "password",
+		Host:            "localhost",
+		Port:            strconv.FormatUint(uint64(cfg.Port), 10),
+		Name:            "broker",
+		SSLMode:         "disable",
+		SecretKey:       cfg.SecretKey,
+		MaxOpenConns:    8,
+		MaxIdleConns:    2,
+		ConnMaxLifetime: 30 * time.Minute,
+	}
+
+	server, err := newLocalPostgres(cfg, dbCfg, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Infof("Starting embedded PostgreSQL on port %d", cfg.Port)
+	if err := server.start(); err != nil {
+		server.stop()
+		return nil, nil, errors.Wrap(err, "while starting embedded PostgreSQL")
+	}
+
+	store, connection, err := NewFromConfig(dbCfg, log)
+	if err != nil {
+		server.stop()
+		return nil, nil, err
+	}
+	if err := ApplyMigrations(connection, cfg.MigrationsPath, log); err != nil {
+		connection.Close()
+		server.stop()
+		return nil, nil, err
+	}
+
+	stop := func() error {
+		if err := connection.Close(); err != nil {
+			log.Warnf("while closing connection to embedded PostgreSQL: %s", err)
+		}
+		return server.stop()
+	}
+	return store, stop, nil
+}
+
+// localPostgres manages a PostgreSQL cluster with the pg_ctl tool, the server accepts only local connections
+type localPostgres struct {
+	binariesPath string
+	dataPath     string
+	temporary    bool
+	port         string
+	user         string
+	database     string
+	startTimeout time.Duration
+	log          logrus.FieldLogger
+}
+
+func newLocalPostgres(cfg EmbeddedConfig, dbCfg Config, log logrus.FieldLogger) (*localPostgres, error) {
+	server := &localPostgres{
+		binariesPath: cfg.BinariesPath,
+		dataPath:     cfg.RuntimePath,
+		port:         dbCfg.Port,
+		user:         dbCfg.User,
+		database:     dbCfg.Name,
+		startTimeout: cfg.StartTimeout,
+		log:          log,
+	}
+	if server.dataPath == "" {
+		dir, err := ioutil.TempDir("", "keb-postgres")
+		if err != nil {
+			return nil, errors.Wrap(err, "while creating embedded PostgreSQL data directory")
+		}
+		server.dataPath = dir
+		server.temporary = true
+	}
+	return server, nil
+}
+
+func (p *localPostgres) start() error {
+	initialized, err := p.initialized()
+	if err != nil {
+		return err
+	}
+	if !initialized {
+		if err := p.run("initdb", "-D", p.dataPath, "-U", p.user, "-A", "trust", "-E", "UTF8"); err != nil {
+			return errors.Wrap(err, "while initializing database cluster")
+		}
+	}
+
+	timeout := strconv.Itoa(int(p.startTimeout.Seconds()))
+	options := fmt.Sprintf("-h localhost -p %s -k %s", p.port, p.dataPath)
+	if err := p.run("pg_ctl", "start", "-w", "-t", timeout, "-D", p.dataPath, "-l", filepath.Join(p.dataPath, "server.log"), "-o", options); err != nil {
+		return errors.Wrap(err, "while starting server")
+	}
+
+	if !initialized {
+		if err := p.run("createdb", "-h", "localhost", "-p", p.port, "-U", p.user, p.database); err != nil {
+			return errors.Wrapf(err, "while creating database %s", p.database)
+		}
+	}
+	return nil
+}
+
+func (p *localPostgres) stop() error {
+	var err error
+	if _, statErr := os.Stat(filepath.Join(p.dataPath, "postmaster.pid")); statErr == nil {
+		err = p.run("pg_ctl", "stop", "-w", "-m", "fast", "-D", p.dataPath)
+	}
+	if p.temporary {
+		if rmErr := os.RemoveAll(p.dataPath); rmErr != nil {
+			p.log.Warnf("while removing embedded PostgreSQL data directory: %s", rmErr)
+		}
+	}
+	return err
+}
+
+// initialized checks if the data directory contains a database cluster
+func (p *localPostgres) initialized() (bool, error) {
+	_, err := os.Stat(filepath.Join(p.dataPath, "PG_VERSION"))
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, errors.Wrap(err, "while checking embedded PostgreSQL data directory")
+	}
+}
+
+func (p *localPostgres) run(name string, args ...string) error {
+	if p.binariesPath != "" {
+		name = filepath.Join(p.binariesPath, name)
+	}
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "%s failed: %s", filepath.Base(name), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+type migration struct {
+	version uint64
+	path    string
+}
+
+// ApplyMigrations applies the up migrations from the given directory which are newer than the current schema version.
+// The version is stored in the same table as the schema-migrator uses, so both can be used with one database.
+func ApplyMigrations(connection *dbr.Connection, dir string, log logrus.FieldLogger) error {
+	migrations, err := listMigrations(dir)
+	if err != nil {
+		return err
+	}
+
+	_, err = connection.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)", schemaMigrationsTable))
+	if err != nil {
+		return errors.Wrap(err, "while creating schema migrations table")
+	}
+	var current uint64
+	var dirty bool
+	err = connection.QueryRow(fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", schemaMigrationsTable)).Scan(&current, &dirty)
+	switch {
+	case err == nil && dirty:
+		return errors.Errorf("schema version %d is dirty", current)
+	case err != nil && err != sql.ErrNoRows:
+		return errors.Wrap(err, "while reading schema version")
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		content, err := ioutil.ReadFile(m.path)
+		if err != nil {
+			return errors.Wrapf(err, "while reading migration %s", m.path)
+		}
+
+		tx, err := connection.Begin()
+		if err != nil {
+			return errors.Wrap(err, "while starting migration transaction")
+		}
+		if _, err := tx.Exec(string(content)); err != nil {
+			tx.RollbackUnlessCommitted()
+			return errors.Wrapf(err, "while applying migration %s", filepath.Base(m.path))
+		}
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", schemaMigrationsTable)); err != nil {
+			tx.RollbackUnlessCommitted()
+			return errors.Wrap(err, "while updating schema version")
+		}
+		if _, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (version, dirty) VALUES ($1, false)", schemaMigrationsTable), m.version); err != nil {
+			tx.RollbackUnlessCommitted()
+			return errors.Wrap(err, "while updating schema version")
+		}
+		if err := tx.Commit(); err != nil {
+			return errors.Wrapf(err, "while committing migration %s", filepath.Base(m.path))
+		}
+		log.Infof("Applied migration %s", filepath.Base(m.path))
+	}
+	return nil
+}
+
+// listMigrations returns the up migrations sorted by version, the file name format is <version>_<name>.up.sql
+func listMigrations(dir string) ([]migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading migrations directory %s", dir)
+	}
+
+	var migrations []migration
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), migrationUpSuffix) {
+			continue
+		}
+		version, err := strconv.ParseUint(strings.SplitN(f.Name(), "_", 2)[0], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid migration file name %s", f.Name())
+		}
+		migrations = append(migrations, migration{version: version, path: filepath.Join(dir, f.Name())})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListMigrations(t *testing.T) {
+	t.Run("should return up migrations sorted by version", func(t *testing.T) {
+		// given
+		dir := fixMigrationsDir(t,
+			"202012011000_add_archive_tables.up.sql",
+			"202012011000_add_archive_tables.down.sql",
+			"201911271200_create_tables.up.sql",
+			"202003161437_add_orchestrations.up.sql",
+			"README.md",
+		)
+
+		// when
+		migrations, err := listMigrations(dir)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, migrations, 3)
+		assert.Equal(t, uint64(201911271200), migrations[0].version)
+		assert.Equal(t, uint64(202003161437), migrations[1].version)
+		assert.Equal(t, uint64(202012011000), migrations[2].version)
+		assert.Equal(t, filepath.Join(dir, "202012011000_add_archive_tables.up.sql"), migrations[2].path)
+	})
+
+	t.Run("should fail on invalid file name", func(t *testing.T) {
+		// given
+		dir := fixMigrationsDir(t, "create_tables.up.sql")
+
+		// when
+		_, err := listMigrations(dir)
+
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail when directory does not exist", func(t *testing.T) {
+		// when
+		_, err := listMigrations(filepath.Join(os.TempDir(), "not-existing-migrations"))
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestListMigrations_SchemaMigrator(t *testing.T) {
+	// when
+	migrations, err := listMigrations(filepath.Join("..", "..", "..", "schema-migrator", "migrations", "kyma-environment-broker"))
+
+	// then
+	require.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+}
+
+func fixMigrationsDir(t *testing.T, names ...string) string {
+	dir, err := ioutil.TempDir("", "migrations")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	for _, name := range names {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0644))
+	}
+	return dir
+}
@@ -5,42 +5,164 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"strings"
 
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 )
 
+const (
+	// gcmPrefix marks values encrypted with AES-GCM, the format is: gcm:<key ID>:<base64 of nonce and cipher text>
+	// values without the prefix are encrypted with AES-CFB using the legacy key
+	gcmPrefix = "gcm:"
+
+	defaultKeyID = "default"
+)
+
+// EncryptionKey is a versioned key used to encrypt data stored in the database
+type EncryptionKey struct {
+	ID  string `yaml:"id"`
+	Key string `yaml:"key"`
+}
+
+// ReadEncryptionKeysFromFile reads the list of encryption keys, the last key in the list is used for new writes
+func ReadEncryptionKeysFromFile(filename string) ([]EncryptionKey, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with encryption keys", filename)
+	}
+	var keys []EncryptionKey
+	if err := yaml.Unmarshal(content, &keys); err != nil {
+		return nil, errors.Wrap(err, "while unmarshalling a file with encryption keys")
+	}
+	return keys, nil
+}
+
+// NewEncrypter returns the Encrypter which uses only one key, the key is used for both AES-GCM and legacy AES-CFB values
 func NewEncrypter(secretKey string) *Encrypter {
-	return &Encrypter{key: []byte(secretKey)}
+	return &Encrypter{
+		keys:      map[string][]byte{defaultKeyID: []byte(secretKey)},
+		currentID: defaultKeyID,
+		legacyKey: []byte(secretKey),
+	}
+}
+
+// NewEncrypterWithKeys returns the Encrypter which encrypts new values with the last of the given keys
+// and decrypts values encrypted with any of the keys. The legacy key is used to decrypt AES-CFB values.
+func NewEncrypterWithKeys(keys []EncryptionKey, legacyKey string) (*Encrypter, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one encryption key is required")
+	}
+
+	e := &Encrypter{
+		keys:      make(map[string][]byte, len(keys)),
+		legacyKey: []byte(legacyKey),
+	}
+	for _, k := range keys {
+		if k.ID == "" || strings.Contains(k.ID, ":") {
+			return nil, errors.Errorf("invalid encryption key ID %q", k.ID)
+		}
+		if _, exists := e.keys[k.ID]; exists {
+			return nil, errors.Errorf("encryption key ID %s is not unique", k.ID)
+		}
+		if _, err := aes.NewCipher([]byte(k.Key)); err != nil {
+			return nil, errors.Wrapf(err, "invalid encryption key %s", k.ID)
+		}
+		e.keys[k.ID] = []byte(k.Key)
+	}
+	e.currentID = keys[len(keys)-1].ID
+
+	return e, nil
 }
 
 type Encrypter struct {
-	key []byte
+	keys      map[string][]byte
+	currentID string
+	legacyKey []byte
 }
 
+// Encrypt encrypts the object with AES-GCM using the newest key
 func (e *Encrypter) Encrypt(obj []byte) ([]byte, error) {
-	block, err := aes.NewCipher(e.key)
+	gcm, err := newGCM(e.keys[e.currentID])
 	if err != nil {
 		return nil, err
 	}
-	b := base64.StdEncoding.EncodeToString(obj)
-	bytes := make([]byte, aes.BlockSize+len(b))
-	iv := bytes[:aes.BlockSize]
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(obj)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
-	cfb := cipher.NewCFBEncrypter(block, iv)
-	cfb.XORKeyStream(bytes[aes.BlockSize:], []byte(b))
+	sealed := gcm.Seal(nonce, nonce, obj, []byte(e.currentID))
 
-	return []byte(base64.StdEncoding.EncodeToString(bytes)), nil
+	return []byte(fmt.Sprintf("%s%s:%s", gcmPrefix, e.currentID, base64.StdEncoding.EncodeToString(sealed))), nil
 }
 
+// Decrypt decrypts the object encrypted with any of the known keys or with the legacy AES-CFB mode
 func (e *Encrypter) Decrypt(obj []byte) ([]byte, error) {
+	if !strings.HasPrefix(string(obj), gcmPrefix) {
+		return e.decryptCFB(obj)
+	}
+
+	keyID, data, err := splitKeyID(obj)
+	if err != nil {
+		return nil, err
+	}
+	key, found := e.keys[keyID]
+	if !found {
+		return nil, errors.Errorf("unknown encryption key %s", keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "while decoding object")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("cipher text is too short")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(keyID))
+	if err != nil {
+		return nil, errors.Wrap(err, "while decrypting object")
+	}
+	return plain, nil
+}
+
+// IsEncrypted checks if the object was encrypted with AES-GCM using one of the versioned keys. The cipher text
+// is authenticated, so a plain text which only looks like an encrypted value is not reported as encrypted.
+func (e *Encrypter) IsEncrypted(obj []byte) bool {
+	if !strings.HasPrefix(string(obj), gcmPrefix) {
+		return false
+	}
+	_, err := e.Decrypt(obj)
+	return err == nil
+}
+
+// HasEncryptedFormat checks if the object has the format of values encrypted with AES-GCM, it does not check
+// if the object can be decrypted with one of the known keys
+func (e *Encrypter) HasEncryptedFormat(obj []byte) bool {
+	return strings.HasPrefix(string(obj), gcmPrefix)
+}
+
+// NeedsReEncryption returns true if the object is not encrypted with AES-GCM using the newest key
+func (e *Encrypter) NeedsReEncryption(obj []byte) bool {
+	return !strings.HasPrefix(string(obj), e.CurrentPrefix())
+}
+
+// CurrentPrefix returns the prefix of values encrypted with the newest key
+func (e *Encrypter) CurrentPrefix() string {
+	return fmt.Sprintf("%s%s:", gcmPrefix, e.currentID)
+}
+
+func (e *Encrypter) decryptCFB(obj []byte) ([]byte, error) {
 	obj, err := base64.StdEncoding.DecodeString(string(obj))
 	if err != nil {
 		return nil, errors.Wrap(err, "while decoding object")
 	}
-	block, err := aes.NewCipher(e.key)
+	block, err := aes.NewCipher(e.legacyKey)
 	if err != nil {
 		return nil, err
 	}
@@ -57,3 +179,19 @@ func (e *Encrypter) Decrypt(obj []byte) ([]byte, error) {
 	}
 	return data, nil
 }
+
+func splitKeyID(obj []byte) (string, string, error) {
+	parts := strings.SplitN(strings.TrimPrefix(string(obj), gcmPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", "", errors.New("encryption key ID is missing")
+	}
+	return parts[0], parts[1], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -1,7 +1,13 @@
 package storage
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -55,3 +61,134 @@ func TestNewEncrypter(t *testing.T) {
 	})
 
 }
+
+func TestEncrypterWithKeys(t *testing.T) {
+	oldKey := EncryptionKey{ID: "2020-10", Key: rand.String(32)}
+	newKey := EncryptionKey{ID: "2020-11", Key: rand.String(32)}
+	legacyKey := rand.String(32)
+	data := []byte(`{"data":"secret"}`)
+
+	t.Run("should encrypt with the newest key and decrypt values encrypted with older keys", func(t *testing.T) {
+		// given
+		oldEncrypter, err := NewEncrypterWithKeys([]EncryptionKey{oldKey}, legacyKey)
+		require.NoError(t, err)
+		e, err := NewEncrypterWithKeys([]EncryptionKey{oldKey, newKey}, legacyKey)
+		require.NoError(t, err)
+
+		oldEnc, err := oldEncrypter.Encrypt(data)
+		require.NoError(t, err)
+
+		// when
+		enc, err := e.Encrypt(data)
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, strings.HasPrefix(string(enc), "gcm:2020-11:"))
+		assert.False(t, e.NeedsReEncryption(enc))
+		assert.True(t, e.NeedsReEncryption(oldEnc))
+
+		dec, err := e.Decrypt(oldEnc)
+		require.NoError(t, err)
+		assert.Equal(t, data, dec)
+
+		dec, err = e.Decrypt(enc)
+		require.NoError(t, err)
+		assert.Equal(t, data, dec)
+	})
+
+	t.Run("should decrypt legacy AES-CFB values", func(t *testing.T) {
+		// given
+		e, err := NewEncrypterWithKeys([]EncryptionKey{newKey}, legacyKey)
+		require.NoError(t, err)
+		legacy := encryptCFB(t, legacyKey, data)
+
+		// when
+		dec, err := e.Decrypt(legacy)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, data, dec)
+		assert.True(t, e.NeedsReEncryption(legacy))
+	})
+
+	t.Run("should reject modified cipher text", func(t *testing.T) {
+		// given
+		e, err := NewEncrypterWithKeys([]EncryptionKey{newKey}, legacyKey)
+		require.NoError(t, err)
+		enc, err := e.Encrypt(data)
+		require.NoError(t, err)
+
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(enc), e.CurrentPrefix()))
+		require.NoError(t, err)
+		sealed[len(sealed)-1] ^= 0xff
+		modified := []byte(e.CurrentPrefix() + base64.StdEncoding.EncodeToString(sealed))
+
+		// when
+		_, err = e.Decrypt(modified)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should fail for unknown key", func(t *testing.T) {
+		// given
+		oldEncrypter, err := NewEncrypterWithKeys([]EncryptionKey{oldKey}, legacyKey)
+		require.NoError(t, err)
+		e, err := NewEncrypterWithKeys([]EncryptionKey{newKey}, legacyKey)
+		require.NoError(t, err)
+		enc, err := oldEncrypter.Encrypt(data)
+		require.NoError(t, err)
+
+		// when
+		_, err = e.Decrypt(enc)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown encryption key 2020-10")
+	})
+
+	t.Run("should report only authenticated values as encrypted", func(t *testing.T) {
+		// given
+		e, err := NewEncrypterWithKeys([]EncryptionKey{newKey}, legacyKey)
+		require.NoError(t, err)
+		enc, err := e.Encrypt(data)
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, e.IsEncrypted(enc))
+		assert.False(t, e.IsEncrypted(data))
+		assert.False(t, e.IsEncrypted([]byte("gcm:")))
+		assert.False(t, e.IsEncrypted([]byte(e.CurrentPrefix()+"plain-text-password")))
+		assert.False(t, e.IsEncrypted([]byte(e.CurrentPrefix()+base64.StdEncoding.EncodeToString([]byte("plain-text-password-long-enough")))))
+	})
+
+	t.Run("should validate keys", func(t *testing.T) {
+		for name, keys := range map[string][]EncryptionKey{
+			"no keys":        {},
+			"empty ID":       {{ID: "", Key: rand.String(32)}},
+			"ID with colon":  {{ID: "a:b", Key: rand.String(32)}},
+			"duplicated ID":  {oldKey, oldKey},
+			"wrong key size": {{ID: "short", Key: "short"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := NewEncrypterWithKeys(keys, legacyKey)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+// encryptCFB encrypts the object the same way as the previous version of the Encrypter
+func encryptCFB(t *testing.T, key string, obj []byte) []byte {
+	block, err := aes.NewCipher([]byte(key))
+	require.NoError(t, err)
+	b := base64.StdEncoding.EncodeToString(obj)
+	bytes := make([]byte, aes.BlockSize+len(b))
+	iv := bytes[:aes.BlockSize]
+	_, err = io.ReadFull(cryptorand.Reader, iv)
+	require.NoError(t, err)
+	cfb := cipher.NewCFBEncrypter(block, iv)
+	cfb.XORKeyStream(bytes[aes.BlockSize:], []byte(b))
+
+	return []byte(base64.StdEncoding.EncodeToString(bytes))
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/predicate"
@@ -10,6 +12,7 @@ type Instances interface {
 	FindAllJoinedWithOperations(prct ...predicate.Predicate) ([]internal.InstanceWithOperation, error)
 	FindAllInstancesForRuntimes(runtimeIdList []string) ([]internal.Instance, error)
 	FindAllInstancesForSubAccounts(subAccountslist []string) ([]internal.Instance, error)
+	FindAllInstancesForGlobalAccounts(globalAccountsList []string) ([]internal.Instance, error)
 	GetByID(instanceID string) (*internal.Instance, error)
 	Insert(instance internal.Instance) error
 	Update(instance internal.Instance) error
@@ -17,6 +20,8 @@ type Instances interface {
 	GetInstanceStats() (internal.InstanceStats, error)
 	GetNumberOfInstancesForGlobalAccountID(globalAccountID string) (int, error)
 	List(dbmodel.InstanceFilter) ([]internal.Instance, int, int, error)
+	ListWithOperations(filter dbmodel.RuntimeFilter) ([]internal.InstanceWithOperations, int, int, error)
+	ReEncrypter
 }
 
 type Operations interface {
@@ -29,6 +34,7 @@ type Operations interface {
 	GetOperationStats() (internal.OperationStats, error)
 	GetOperationsForIDs(operationIDList []string) ([]internal.Operation, error)
 	GetOperationStatsForOrchestration(orchestrationID string) (map[string]int, error)
+	ReEncrypter
 }
 
 type Provisioning interface {
@@ -57,6 +63,14 @@ type RuntimeStates interface {
 	Insert(runtimeState internal.RuntimeState) error
 	GetByOperationID(operationID string) (internal.RuntimeState, error)
 	ListByRuntimeID(runtimeID string) ([]internal.RuntimeState, error)
+	ReEncrypter
+}
+
+// ReEncrypter encrypts stored values with the newest encryption key
+type ReEncrypter interface {
+	// ReEncrypt re-encrypts at most batchSize values with ID greater than afterID,
+	// values which cannot be decrypted are skipped
+	ReEncrypt(afterID string, batchSize int) (internal.ReEncryptionResult, error)
 }
 
 type UpgradeKyma interface {
@@ -72,3 +86,37 @@ type LMSTenants interface {
 	FindTenantByName(name, region string) (internal.LMSTenant, bool, error)
 	InsertTenant(tenant internal.LMSTenant) error
 }
+
+type SubAccountCleanupRuns interface {
+	Insert(run internal.SubAccountCleanupRun) error
+	GetByID(runID string) (internal.SubAccountCleanupRun, error)
+}
+
+type CISHighWaterMarks interface {
+	Get(source string) (internal.CISHighWaterMark, error)
+	Save(mark internal.CISHighWaterMark) error
+}
+
+type IASRotations interface {
+	Get(instanceID string) (internal.IASRotation, error)
+	List() ([]internal.IASRotation, error)
+	Save(rotation internal.IASRotation) error
+}
+
+type LMSCertificates interface {
+	Get(instanceID string) (internal.LMSCertificate, error)
+	List() ([]internal.LMSCertificate, error)
+	Save(certificate internal.LMSCertificate) error
+}
+
+// Archive moves finished operations and orchestrations out of the tables used by the broker
+type Archive interface {
+	ListOperationsToArchive(operationType dbmodel.OperationType, finishedBefore time.Time, limit int) ([]dbmodel.OperationDTO, error)
+	ListOrchestrationsToArchive(finishedBefore time.Time, limit int) ([]dbmodel.OrchestrationDTO, error)
+	// MoveOperationsToArchive copies the operations to the archive table and deletes them in one transaction
+	MoveOperationsToArchive(operations []dbmodel.OperationDTO) error
+	// MoveOrchestrationsToArchive copies the orchestrations to the archive table and deletes them in one transaction
+	MoveOrchestrationsToArchive(orchestrations []dbmodel.OrchestrationDTO) error
+	DeleteOperations(ids []string) error
+	DeleteOrchestrations(ids []string) error
+}
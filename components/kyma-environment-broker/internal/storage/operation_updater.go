@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultConflictRetries       = 5
+	defaultConflictRetryInterval = 100 * time.Millisecond
+)
+
+var operationConflicts = &conflictCounter{counts: make(map[dbmodel.OperationType]int)}
+
+// OperationUpdateConflicts returns the number of optimistic locking conflicts of operation updates by operation type
+func OperationUpdateConflicts() map[dbmodel.OperationType]int {
+	return operationConflicts.snapshot()
+}
+
+// OperationUpdater updates operations which can be modified concurrently, e.g. by the orchestration and the OSB API flows.
+// When the stored operation was changed in the meantime, the latest version is read, the change of the caller is applied
+// to it again and the update is retried with a jittered delay. The change must be expressed by the mutation, fields
+// modified directly on the given operation are not stored when a conflict occurs.
+type OperationUpdater struct {
+	operations    Operations
+	retries       int
+	retryInterval time.Duration
+}
+
+func NewOperationUpdater(operations Operations) *OperationUpdater {
+	return &OperationUpdater{
+		operations:    operations,
+		retries:       defaultConflictRetries,
+		retryInterval: defaultConflictRetryInterval,
+	}
+}
+
+// UpdateProvisioningOperationWithRetry applies the mutation to the given operation and stores it, on conflict the mutation
+// is applied to the latest version of the operation.
+func (u *OperationUpdater) UpdateProvisioningOperationWithRetry(operation internal.ProvisioningOperation, mutate func(*internal.ProvisioningOperation)) (*internal.ProvisioningOperation, error) {
+	var updated *internal.ProvisioningOperation
+	current := operation
+	err := u.retry(dbmodel.OperationTypeProvision, func(conflicted bool) error {
+		if conflicted {
+			latest, err := u.operations.GetProvisioningOperationByID(operation.ID)
+			if err != nil {
+				return errors.Wrapf(err, "while getting provisioning operation %s", operation.ID)
+			}
+			current = *latest
+			current.InputCreator = operation.InputCreator
+			current.SMClientFactory = operation.SMClientFactory
+		}
+		state, description := current.State, current.Description
+		mutate(&current)
+		keepFinishedState(&current.Operation, state, description)
+
+		var err error
+		updated, err = u.operations.UpdateProvisioningOperation(current)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// UpdateDeprovisioningOperationWithRetry applies the mutation to the given operation and stores it, on conflict the mutation
+// is applied to the latest version of the operation.
+func (u *OperationUpdater) UpdateDeprovisioningOperationWithRetry(operation internal.DeprovisioningOperation, mutate func(*internal.DeprovisioningOperation)) (*internal.DeprovisioningOperation, error) {
+	var updated *internal.DeprovisioningOperation
+	current := operation
+	err := u.retry(dbmodel.OperationTypeDeprovision, func(conflicted bool) error {
+		if conflicted {
+			latest, err := u.operations.GetDeprovisioningOperationByID(operation.ID)
+			if err != nil {
+				return errors.Wrapf(err, "while getting deprovisioning operation %s", operation.ID)
+			}
+			current = *latest
+			current.SMClientFactory = operation.SMClientFactory
+			current.SubAccountID = operation.SubAccountID
+		}
+		state, description := current.State, current.Description
+		mutate(&current)
+		keepFinishedState(&current.Operation, state, description)
+
+		var err error
+		updated, err = u.operations.UpdateDeprovisioningOperation(current)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// UpdateUpgradeKymaOperationWithRetry applies the mutation to the given operation and stores it, on conflict the mutation
+// is applied to the latest version of the operation.
+func (u *OperationUpdater) UpdateUpgradeKymaOperationWithRetry(operation internal.UpgradeKymaOperation, mutate func(*internal.UpgradeKymaOperation)) (*internal.UpgradeKymaOperation, error) {
+	var updated *internal.UpgradeKymaOperation
+	current := operation
+	err := u.retry(dbmodel.OperationTypeUpgradeKyma, func(conflicted bool) error {
+		if conflicted {
+			latest, err := u.operations.GetUpgradeKymaOperationByID(operation.Operation.ID)
+			if err != nil {
+				return errors.Wrapf(err, "while getting upgrade kyma operation %s", operation.Operation.ID)
+			}
+			current = *latest
+			current.InputCreator = operation.InputCreator
+		}
+		state, description := current.State, current.Description
+		mutate(&current)
+		keepFinishedState(&current.Operation, state, description)
+
+		var err error
+		updated, err = u.operations.UpdateUpgradeKymaOperation(current)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// keepFinishedState restores the state of the operation which was already finished, e.g. by a concurrent writer,
+// so the mutation cannot bring it back to another state.
+func keepFinishedState(operation *internal.Operation, state domain.LastOperationState, description string) {
+	if !isFinished(state) {
+		return
+	}
+	operation.State = state
+	operation.Description = description
+}
+
+func isFinished(state domain.LastOperationState) bool {
+	switch state {
+	case domain.Succeeded, domain.Failed, orchestration.Canceled:
+		return true
+	}
+	return false
+}
+
+func (u *OperationUpdater) retry(operationType dbmodel.OperationType, update func(conflicted bool) error) error {
+	conflicted := false
+	for attempt := 0; ; attempt++ {
+		err := update(conflicted)
+		if err == nil || !dberr.IsConflict(err) {
+			return err
+		}
+		operationConflicts.inc(operationType)
+		if attempt >= u.retries {
+			return err
+		}
+		conflicted = true
+		time.Sleep(jitter(u.retryInterval, attempt))
+	}
+}
+
+// jitter returns the delay which grows with every attempt and is randomized to spread concurrent writers
+func jitter(interval time.Duration, attempt int) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	base := interval * time.Duration(attempt+1)
+	return base + time.Duration(rand.Int63n(int64(interval)))
+}
+
+type conflictCounter struct {
+	mu     sync.Mutex
+	counts map[dbmodel.OperationType]int
+}
+
+func (c *conflictCounter) inc(operationType dbmodel.OperationType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[operationType]++
+}
+
+func (c *conflictCounter) snapshot() map[dbmodel.OperationType]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make(map[dbmodel.OperationType]int, len(c.counts))
+	for k, v := range c.counts {
+		result[k] = v
+	}
+	return result
+}
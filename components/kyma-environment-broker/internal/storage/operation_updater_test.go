@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbsession/dbmodel"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationUpdater_UpdateProvisioningOperationWithRetry(t *testing.T) {
+	t.Run("should update the operation without conflict", func(t *testing.T) {
+		// given
+		operations := NewMemoryStorage().Operations()
+		operation := fixProvisioningOperation("op-1")
+		require.NoError(t, operations.InsertProvisioningOperation(operation))
+		updater := NewOperationUpdater(operations)
+		conflictsBefore := OperationUpdateConflicts()[dbmodel.OperationTypeProvision]
+
+		// when
+		updated, err := updater.UpdateProvisioningOperationWithRetry(operation, func(op *internal.ProvisioningOperation) {
+			op.State = domain.Succeeded
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, domain.Succeeded, updated.State)
+		assert.Equal(t, conflictsBefore, OperationUpdateConflicts()[dbmodel.OperationTypeProvision])
+	})
+
+	t.Run("should re-apply the mutation on the latest version", func(t *testing.T) {
+		// given
+		operations := NewMemoryStorage().Operations()
+		operation := fixProvisioningOperation("op-2")
+		require.NoError(t, operations.InsertProvisioningOperation(operation))
+
+		concurrent := operation
+		concurrent.RuntimeID = "runtime-id"
+		_, err := operations.UpdateProvisioningOperation(concurrent)
+		require.NoError(t, err)
+
+		updater := NewOperationUpdater(operations)
+		updater.retryInterval = 0
+		conflictsBefore := OperationUpdateConflicts()[dbmodel.OperationTypeProvision]
+
+		// when
+		updated, err := updater.UpdateProvisioningOperationWithRetry(operation, func(op *internal.ProvisioningOperation) {
+			op.State = domain.Succeeded
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, domain.Succeeded, updated.State)
+		assert.Equal(t, "runtime-id", updated.RuntimeID)
+		assert.Equal(t, conflictsBefore+1, OperationUpdateConflicts()[dbmodel.OperationTypeProvision])
+
+		stored, err := operations.GetProvisioningOperationByID("op-2")
+		require.NoError(t, err)
+		assert.Equal(t, domain.Succeeded, stored.State)
+		assert.Equal(t, "runtime-id", stored.RuntimeID)
+	})
+
+	t.Run("should keep changes of both writers which modify different fields", func(t *testing.T) {
+		// given
+		operations := NewMemoryStorage().Operations()
+		operation := fixProvisioningOperation("op-4")
+		require.NoError(t, operations.InsertProvisioningOperation(operation))
+
+		updater := NewOperationUpdater(operations)
+		updater.retryInterval = 0
+
+		_, err := updater.UpdateProvisioningOperationWithRetry(operation, func(op *internal.ProvisioningOperation) {
+			op.ShootName = "shoot"
+		})
+		require.NoError(t, err)
+
+		// when
+		updated, err := updater.UpdateProvisioningOperationWithRetry(operation, func(op *internal.ProvisioningOperation) {
+			op.ProvisionerOperationID = "provisioner-operation-id"
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "shoot", updated.ShootName)
+		assert.Equal(t, "provisioner-operation-id", updated.ProvisionerOperationID)
+		assert.Equal(t, domain.InProgress, updated.State)
+
+		stored, err := operations.GetProvisioningOperationByID("op-4")
+		require.NoError(t, err)
+		assert.Equal(t, "shoot", stored.ShootName)
+		assert.Equal(t, "provisioner-operation-id", stored.ProvisionerOperationID)
+	})
+
+	t.Run("should not store fields changed outside of the mutation on conflict", func(t *testing.T) {
+		// given
+		operations := NewMemoryStorage().Operations()
+		operation := fixProvisioningOperation("op-6")
+		require.NoError(t, operations.InsertProvisioningOperation(operation))
+
+		concurrent := operation
+		concurrent.RuntimeID = "runtime-id"
+		_, err := operations.UpdateProvisioningOperation(concurrent)
+		require.NoError(t, err)
+
+		updater := NewOperationUpdater(operations)
+		updater.retryInterval = 0
+
+		operation.RuntimeID = "stale-runtime-id"
+
+		// when
+		updated, err := updater.UpdateProvisioningOperationWithRetry(operation, func(op *internal.ProvisioningOperation) {
+			op.ShootName = "shoot"
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "runtime-id", updated.RuntimeID)
+		assert.Equal(t, "shoot", updated.ShootName)
+	})
+
+	t.Run("should not override state of the operation finished concurrently", func(t *testing.T) {
+		// given
+		operations := NewMemoryStorage().Operations()
+		operation := fixProvisioningOperation("op-5")
+		require.NoError(t, operations.InsertProvisioningOperation(operation))
+
+		concurrent := operation
+		concurrent.State = domain.Failed
+		concurrent.Description = "failed concurrently"
+		_, err := operations.UpdateProvisioningOperation(concurrent)
+		require.NoError(t, err)
+
+		updater := NewOperationUpdater(operations)
+		updater.retryInterval = 0
+
+		// when
+		updated, err := updater.UpdateProvisioningOperationWithRetry(operation, func(op *internal.ProvisioningOperation) {
+			op.State = domain.Succeeded
+			op.ShootName = "shoot"
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, domain.Failed, updated.State)
+		assert.Equal(t, "failed concurrently", updated.Description)
+		assert.Equal(t, "shoot", updated.ShootName)
+	})
+
+	t.Run("should return not found error", func(t *testing.T) {
+		// given
+		updater := NewOperationUpdater(NewMemoryStorage().Operations())
+
+		// when
+		_, err := updater.UpdateProvisioningOperationWithRetry(fixProvisioningOperation("not-existing"), func(op *internal.ProvisioningOperation) {})
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestOperationUpdater_UpdateDeprovisioningOperationWithRetry(t *testing.T) {
+	// given
+	operations := NewMemoryStorage().Operations()
+	operation := internal.DeprovisioningOperation{
+		Operation:    internal.Operation{ID: "op-3", InstanceID: "instance-id", State: domain.InProgress},
+		SubAccountID: "sub-account-id",
+	}
+	require.NoError(t, operations.InsertDeprovisioningOperation(operation))
+
+	concurrent := operation
+	concurrent.Description = "concurrent change"
+	_, err := operations.UpdateDeprovisioningOperation(concurrent)
+	require.NoError(t, err)
+
+	updater := NewOperationUpdater(operations)
+	updater.retryInterval = 0
+
+	// when
+	updated, err := updater.UpdateDeprovisioningOperationWithRetry(operation, func(op *internal.DeprovisioningOperation) {
+		op.State = domain.Failed
+	})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, domain.Failed, updated.State)
+	assert.Equal(t, "concurrent change", updated.Description)
+	assert.Equal(t, "sub-account-id", updated.SubAccountID)
+}
+
+func fixProvisioningOperation(id string) internal.ProvisioningOperation {
+	return internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:         id,
+			InstanceID: "instance-id",
+			State:      domain.InProgress,
+		},
+	}
+}
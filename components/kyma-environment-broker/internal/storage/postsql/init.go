@@ -20,6 +20,18 @@ const (
 	RuntimeStateTableName  = "runtime_states"
 	LMSTenantTableName     = "lms_tenants"
 	CreatedAtField         = "created_at"
+
+	SubAccountCleanupRunTableName = "subaccount_cleanup_runs"
+	CISHighWaterMarkTableName     = "cis_high_water_marks"
+	IASRotationTableName          = "ias_rotations"
+	LMSCertificateTableName       = "lms_certificates"
+
+	OperationArchiveTableName     = "operations_archive"
+	OrchestrationArchiveTableName = "orchestrations_archive"
+
+	// SameVersionUpgradeInProgressIndexName allows only one upgrade kyma operation created outside of orchestrations
+	// in progress for the instance
+	SameVersionUpgradeInProgressIndexName = "operations_instance_id_same_version_upgrade_in_progress"
 )
 
 // InitializeDatabase opens database connection and initializes schema if it does not exist
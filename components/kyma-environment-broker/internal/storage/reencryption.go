@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type ReEncryptionConfig struct {
+	Disabled  bool          `envconfig:"default=false"`
+	Interval  time.Duration `envconfig:"default=1h"`
+	BatchSize int           `envconfig:"default=100"`
+}
+
+// ReEncryptionJob periodically migrates encrypted values to the newest encryption key
+type ReEncryptionJob struct {
+	targets map[string]ReEncrypter
+	cfg     ReEncryptionConfig
+	log     logrus.FieldLogger
+}
+
+func NewReEncryptionJob(targets map[string]ReEncrypter, cfg ReEncryptionConfig, log logrus.FieldLogger) *ReEncryptionJob {
+	return &ReEncryptionJob{
+		targets: targets,
+		cfg:     cfg,
+		log:     log,
+	}
+}
+
+// Run re-encrypts values periodically until the stop channel is closed
+func (j *ReEncryptionJob) Run(stop <-chan struct{}) {
+	wait.Until(j.ReEncryptAll, j.cfg.Interval, stop)
+}
+
+// ReEncryptAll re-encrypts values of all targets in batches until there is nothing left to migrate.
+// Values which cannot be decrypted, for example because their key was removed, are skipped and reported.
+// A failure stops processing of the given target only, the rest of its values are migrated in the next run.
+func (j *ReEncryptionJob) ReEncryptAll() {
+	for name, target := range j.targets {
+		total, skipped := 0, 0
+		afterID := ""
+		for {
+			result, err := target.ReEncrypt(afterID, j.cfg.BatchSize)
+			total += result.Updated
+			skipped += result.Skipped
+			if err != nil {
+				j.log.Errorf("while re-encrypting %s: %s", name, err)
+				break
+			}
+			if result.Listed < j.cfg.BatchSize {
+				break
+			}
+			afterID = result.LastID
+		}
+		if total > 0 {
+			j.log.Infof("re-encrypted %d %s with the newest key", total, name)
+		}
+		if skipped > 0 {
+			j.log.Warnf("skipped %d %s which could not be re-encrypted", skipped, name)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReEncryptionJob_ReEncryptAll(t *testing.T) {
+	t.Run("should re-encrypt all values in batches", func(t *testing.T) {
+		// given
+		target := newFakeReEncrypter(25)
+		job := NewReEncryptionJob(map[string]ReEncrypter{"runtime states": target}, ReEncryptionConfig{BatchSize: 10}, logrus.New())
+
+		// when
+		job.ReEncryptAll()
+
+		// then
+		assert.Empty(t, target.left)
+		assert.Equal(t, 3, target.calls)
+	})
+
+	t.Run("should skip values which cannot be decrypted", func(t *testing.T) {
+		// given
+		target := newFakeReEncrypter(25)
+		for i := 0; i < 10; i++ {
+			target.broken[fixValueID(i)] = true
+		}
+		job := NewReEncryptionJob(map[string]ReEncrypter{"runtime states": target}, ReEncryptionConfig{BatchSize: 10}, logrus.New())
+
+		// when
+		job.ReEncryptAll()
+
+		// then
+		assert.Len(t, target.left, 10)
+		assert.Equal(t, 3, target.calls)
+	})
+
+	t.Run("should stop processing the target on error", func(t *testing.T) {
+		// given
+		failing := newFakeReEncrypter(25)
+		failing.err = errors.New("boom")
+		target := newFakeReEncrypter(5)
+		job := NewReEncryptionJob(map[string]ReEncrypter{"failing": failing, "runtime states": target}, ReEncryptionConfig{BatchSize: 10}, logrus.New())
+
+		// when
+		job.ReEncryptAll()
+
+		// then
+		assert.Equal(t, 1, failing.calls)
+		assert.Empty(t, target.left)
+	})
+}
+
+// fakeReEncrypter keeps sorted IDs of values which are not re-encrypted yet, the broken ones are never re-encrypted
+type fakeReEncrypter struct {
+	left   []string
+	broken map[string]bool
+	calls  int
+	err    error
+}
+
+func newFakeReEncrypter(amount int) *fakeReEncrypter {
+	f := &fakeReEncrypter{broken: map[string]bool{}}
+	for i := 0; i < amount; i++ {
+		f.left = append(f.left, fixValueID(i))
+	}
+	return f
+}
+
+func (f *fakeReEncrypter) ReEncrypt(afterID string, batchSize int) (internal.ReEncryptionResult, error) {
+	f.calls++
+	result := internal.ReEncryptionResult{}
+	if f.err != nil {
+		return result, f.err
+	}
+
+	var left []string
+	for _, id := range f.left {
+		if id <= afterID || result.Listed == batchSize {
+			left = append(left, id)
+			continue
+		}
+		result.Listed++
+		result.LastID = id
+		if f.broken[id] {
+			result.Skipped++
+			left = append(left, id)
+			continue
+		}
+		result.Updated++
+	}
+	f.left = left
+	return result, nil
+}
+
+func fixValueID(i int) string {
+	return fmt.Sprintf("id-%02d", i)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/driver/memory"
 	postgres "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/driver/postsql"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -17,6 +18,11 @@ type BrokerStorage interface {
 	LMSTenants() LMSTenants
 	Orchestrations() Orchestrations
 	RuntimeStates() RuntimeStates
+	SubAccountCleanupRuns() SubAccountCleanupRuns
+	CISHighWaterMarks() CISHighWaterMarks
+	Archive() Archive
+	IASRotations() IASRotations
+	LMSCertificates() LMSCertificates
 }
 
 const (
@@ -24,6 +30,11 @@ const (
 )
 
 func NewFromConfig(cfg Config, log logrus.FieldLogger) (BrokerStorage, *dbr.Connection, error) {
+	enc, err := newEncrypter(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	log.Infof("Setting DB connection pool params: connectionMaxLifetime=%s "+
 		"maxIdleConnections=%d maxOpenConnections=%d", cfg.ConnMaxLifetime, cfg.MaxIdleConns, cfg.MaxOpenConns)
 
@@ -38,17 +49,36 @@ func NewFromConfig(cfg Config, log logrus.FieldLogger) (BrokerStorage, *dbr.Conn
 
 	fact := dbsession.NewFactory(connection)
 
-	enc := NewEncrypter(cfg.SecretKey)
-
 	return storage{
-		instance:       postgres.NewInstance(fact),
-		operation:      postgres.NewOperation(fact),
+		instance:       postgres.NewInstance(fact, enc),
+		operation:      postgres.NewOperation(fact, enc),
 		lmsTenants:     postgres.NewLMSTenants(fact),
 		orchestrations: postgres.NewOrchestrations(fact),
 		runtimeStates:  postgres.NewRuntimeStates(fact, enc),
+		cleanupRuns:    postgres.NewSubAccountCleanupRuns(fact),
+		cisMarks:       postgres.NewCISHighWaterMarks(fact),
+		archive:        postgres.NewArchive(fact),
+		iasRotations:   postgres.NewIASRotations(fact),
+		lmsCerts:       postgres.NewLMSCertificates(fact),
 	}, connection, nil
 }
 
+func newEncrypter(cfg Config) (*Encrypter, error) {
+	if cfg.SecretKeysFilePath == "" {
+		return NewEncrypter(cfg.SecretKey), nil
+	}
+
+	keys, err := ReadEncryptionKeysFromFile(cfg.SecretKeysFilePath)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := NewEncrypterWithKeys(keys, cfg.SecretKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating encrypter")
+	}
+	return enc, nil
+}
+
 func NewMemoryStorage() BrokerStorage {
 	op := memory.NewOperation()
 	return storage{
@@ -57,6 +87,11 @@ func NewMemoryStorage() BrokerStorage {
 		lmsTenants:     memory.NewLMSTenants(),
 		orchestrations: memory.NewOrchestrations(),
 		runtimeStates:  memory.NewRuntimeStates(),
+		cleanupRuns:    memory.NewSubAccountCleanupRuns(),
+		cisMarks:       memory.NewCISHighWaterMarks(),
+		archive:        memory.NewArchive(),
+		iasRotations:   memory.NewIASRotations(),
+		lmsCerts:       memory.NewLMSCertificates(),
 	}
 }
 
@@ -66,6 +101,11 @@ type storage struct {
 	lmsTenants     LMSTenants
 	orchestrations Orchestrations
 	runtimeStates  RuntimeStates
+	cleanupRuns    SubAccountCleanupRuns
+	cisMarks       CISHighWaterMarks
+	archive        Archive
+	iasRotations   IASRotations
+	lmsCerts       LMSCertificates
 }
 
 func (s storage) Instances() Instances {
@@ -95,3 +135,23 @@ func (s storage) Orchestrations() Orchestrations {
 func (s storage) RuntimeStates() RuntimeStates {
 	return s.runtimeStates
 }
+
+func (s storage) SubAccountCleanupRuns() SubAccountCleanupRuns {
+	return s.cleanupRuns
+}
+
+func (s storage) CISHighWaterMarks() CISHighWaterMarks {
+	return s.cisMarks
+}
+
+func (s storage) Archive() Archive {
+	return s.archive
+}
+
+func (s storage) IASRotations() IASRotations {
+	return s.iasRotations
+}
+
+func (s storage) LMSCertificates() LMSCertificates {
+	return s.lmsCerts
+}
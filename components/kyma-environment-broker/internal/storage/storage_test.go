@@ -5,7 +5,9 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
+	"os"
 	"sort"
 	"testing"
 	"time"
@@ -826,6 +828,279 @@ func TestSchemaInitializer(t *testing.T) {
 		assert.Equal(t, fixID, state.ClusterConfig.KubernetesVersion)
 	})
 
+	t.Run("RuntimeStates re-encryption", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		err = InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		oldStorage, _, err := NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+		for _, id := range []string{"id-1", "id-2", "id-3"} {
+			err = oldStorage.RuntimeStates().Insert(internal.RuntimeState{
+				ID:          id,
+				CreatedAt:   time.Now(),
+				RuntimeID:   id,
+				OperationID: id,
+				KymaConfig:  gqlschema.KymaConfigInput{Version: id},
+			})
+			require.NoError(t, err)
+		}
+
+		keysFile, err := ioutil.TempFile("", "keys")
+		require.NoError(t, err)
+		defer os.Remove(keysFile.Name())
+		_, err = fmt.Fprintf(keysFile, "- id: default\n  key: %q\n- id: new\n  key: %q\n", cfg.SecretKey, "KaPdSgVkYp3s6v9y$B&E)H@MbQeThWmZ")
+		require.NoError(t, err)
+		require.NoError(t, keysFile.Close())
+
+		cfg.SecretKeysFilePath = keysFile.Name()
+		brokerStorage, _, err := NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+		svc := brokerStorage.RuntimeStates()
+
+		// when
+		result, err := svc.ReEncrypt("", 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Updated)
+
+		result, err = svc.ReEncrypt(result.LastID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Updated)
+
+		result, err = svc.ReEncrypt("", 2)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Listed)
+
+		// then
+		state, err := svc.GetByOperationID("id-2")
+		require.NoError(t, err)
+		assert.Equal(t, "id-2", state.KymaConfig.Version)
+	})
+
+	t.Run("Service Manager credentials encryption", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		err = InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		params := `{"ers_context":{"sm_platform_credentials":{"credentials":{"basic":{"username":"sm-user","password":"sm-pass"}},"url":"http://sm.url"}}}`
+		connection, err := postsql.WaitForDatabaseAccess(cfg.ConnectionURL(), 10, logrus.New())
+		require.NoError(t, err)
+
+		// instance and operation stored before the encryption was introduced
+		_, err = connection.Exec("INSERT INTO instances (instance_id, runtime_id, global_account_id, sub_account_id, service_id, service_name, service_plan_id, service_plan_name, dashboard_url, provisioning_parameters, provider_region) VALUES ('legacy', 'legacy', 'ga', 'sa', 'svc', 'svc', 'plan', 'plan', 'url', $1, 'region')", params)
+		require.NoError(t, err)
+
+		oldStorage, _, err := NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+		instance := fixInstance(instanceData{val: "encrypted"})
+		instance.ProvisioningParameters = params
+		err = oldStorage.Instances().Insert(*instance)
+		require.NoError(t, err)
+		err = oldStorage.Operations().InsertProvisioningOperation(internal.ProvisioningOperation{
+			Operation: internal.Operation{
+				ID:         "operation-id",
+				State:      domain.InProgress,
+				CreatedAt:  time.Now(),
+				UpdatedAt:  time.Now(),
+				InstanceID: "encrypted",
+			},
+			ProvisioningParameters: params,
+		})
+		require.NoError(t, err)
+		err = oldStorage.Operations().InsertUpgradeKymaOperation(internal.UpgradeKymaOperation{
+			Operation: internal.Operation{
+				ID:         "upgrade-operation-id",
+				State:      domain.InProgress,
+				CreatedAt:  time.Now(),
+				UpdatedAt:  time.Now(),
+				InstanceID: "encrypted",
+			},
+			LMSCertificateRenewal: internal.LMSCertificateRenewal{PrivateKey: "lms-key"},
+			IASSecrets:            map[string]internal.IASClientSecret{"grafana": {ClientID: "ias-id", ClientSecret: "ias-secret"}},
+		})
+		require.NoError(t, err)
+
+		var storedParams, storedData string
+		err = connection.QueryRow("SELECT provisioning_parameters FROM instances WHERE instance_id = 'encrypted'").Scan(&storedParams)
+		require.NoError(t, err)
+		assert.NotContains(t, storedParams, "sm-pass")
+		assert.Contains(t, storedParams, "http://sm.url")
+		err = connection.QueryRow("SELECT data FROM operations WHERE id = 'operation-id'").Scan(&storedData)
+		require.NoError(t, err)
+		assert.NotContains(t, storedData, "sm-pass")
+
+		keysFile, err := ioutil.TempFile("", "keys")
+		require.NoError(t, err)
+		defer os.Remove(keysFile.Name())
+		_, err = fmt.Fprintf(keysFile, "- id: default\n  key: %q\n- id: new\n  key: %q\n", cfg.SecretKey, "KaPdSgVkYp3s6v9y$B&E)H@MbQeThWmZ")
+		require.NoError(t, err)
+		require.NoError(t, keysFile.Close())
+
+		cfg.SecretKeysFilePath = keysFile.Name()
+		brokerStorage, _, err := NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+
+		// when
+		result, err := brokerStorage.Instances().ReEncrypt("", 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Updated)
+
+		result, err = brokerStorage.Instances().ReEncrypt("", 10)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Listed)
+
+		result, err = brokerStorage.Operations().ReEncrypt("", 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Updated)
+
+		result, err = brokerStorage.Operations().ReEncrypt("", 10)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Listed)
+
+		// then
+		err = connection.QueryRow("SELECT provisioning_parameters FROM instances WHERE instance_id = 'legacy'").Scan(&storedParams)
+		require.NoError(t, err)
+		assert.NotContains(t, storedParams, "sm-pass")
+
+		for _, id := range []string{"legacy", "encrypted"} {
+			got, err := brokerStorage.Instances().GetByID(id)
+			require.NoError(t, err)
+			assert.JSONEq(t, params, got.ProvisioningParameters)
+		}
+		operation, err := brokerStorage.Operations().GetProvisioningOperationByID("operation-id")
+		require.NoError(t, err)
+		assert.JSONEq(t, params, operation.ProvisioningParameters)
+		upgradeOperation, err := brokerStorage.Operations().GetUpgradeKymaOperationByID("upgrade-operation-id")
+		require.NoError(t, err)
+		assert.Equal(t, "lms-key", upgradeOperation.LMSCertificateRenewal.PrivateKey)
+		assert.Equal(t, "ias-secret", upgradeOperation.IASSecrets["grafana"].ClientSecret)
+	})
+
+	t.Run("Re-encryption skips values which cannot be decrypted", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		err = InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		connection, err := postsql.WaitForDatabaseAccess(cfg.ConnectionURL(), 10, logrus.New())
+		require.NoError(t, err)
+
+		// runtime state encrypted with the key which was removed
+		_, err = connection.Exec("INSERT INTO runtime_states (id, runtime_id, operation_id, kyma_version, k8s_version, kyma_config, cluster_config, created_at) VALUES ('id-0', 'id-0', 'id-0', '', '', 'gcm:removed:c2VjcmV0', '{}', NOW())")
+		require.NoError(t, err)
+
+		brokerStorage, _, err := NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+		svc := brokerStorage.RuntimeStates()
+		err = svc.Insert(internal.RuntimeState{ID: "id-1", CreatedAt: time.Now(), RuntimeID: "id-1", OperationID: "id-1"})
+		require.NoError(t, err)
+
+		keysFile, err := ioutil.TempFile("", "keys")
+		require.NoError(t, err)
+		defer os.Remove(keysFile.Name())
+		_, err = fmt.Fprintf(keysFile, "- id: default\n  key: %q\n- id: new\n  key: %q\n", cfg.SecretKey, "KaPdSgVkYp3s6v9y$B&E)H@MbQeThWmZ")
+		require.NoError(t, err)
+		require.NoError(t, keysFile.Close())
+
+		cfg.SecretKeysFilePath = keysFile.Name()
+		brokerStorage, _, err = NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+
+		// when
+		result, err := brokerStorage.RuntimeStates().ReEncrypt("", 10)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Listed)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Equal(t, 1, result.Updated)
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		err = InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		brokerStorage, _, err := NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+
+		old := time.Now().Add(-48 * time.Hour)
+		err = brokerStorage.Instances().Insert(*fixInstance(instanceData{val: "existing"}))
+		require.NoError(t, err)
+		for _, op := range []internal.ProvisioningOperation{
+			{Operation: internal.Operation{ID: "deleted-instance-op", InstanceID: "deleted", State: domain.Succeeded, CreatedAt: old, UpdatedAt: old}},
+			{Operation: internal.Operation{ID: "existing-instance-op", InstanceID: "existing", State: domain.Succeeded, CreatedAt: old, UpdatedAt: old}},
+			{Operation: internal.Operation{ID: "recent-op", InstanceID: "deleted", State: domain.Succeeded, CreatedAt: time.Now(), UpdatedAt: time.Now()}},
+			{Operation: internal.Operation{ID: "in-progress-op", InstanceID: "deleted", State: domain.InProgress, CreatedAt: old, UpdatedAt: old}},
+		} {
+			err = brokerStorage.Operations().InsertProvisioningOperation(op)
+			require.NoError(t, err)
+		}
+		err = brokerStorage.Orchestrations().Insert(internal.Orchestration{OrchestrationID: "orch-id", State: orchestration.Succeeded, CreatedAt: old, UpdatedAt: old})
+		require.NoError(t, err)
+		err = brokerStorage.Operations().InsertUpgradeKymaOperation(internal.UpgradeKymaOperation{
+			Operation: internal.Operation{ID: "upgrade-op", InstanceID: "existing", OrchestrationID: "orch-id", State: domain.Succeeded, CreatedAt: old, UpdatedAt: old},
+		})
+		require.NoError(t, err)
+
+		svc := brokerStorage.Archive()
+		before := time.Now().Add(-24 * time.Hour)
+
+		// when
+		operations, err := svc.ListOperationsToArchive(dbmodel.OperationTypeProvision, before, 10)
+		require.NoError(t, err)
+		orchestrations, err := svc.ListOrchestrationsToArchive(before, 10)
+		require.NoError(t, err)
+
+		// then
+		require.Len(t, operations, 1)
+		assert.Equal(t, "deleted-instance-op", operations[0].ID)
+		assert.Empty(t, orchestrations, "orchestration with operations must not be archived")
+
+		// when
+		err = svc.MoveOperationsToArchive(operations)
+		require.NoError(t, err)
+		upgradeOperations, err := svc.ListOperationsToArchive(dbmodel.OperationTypeUpgradeKyma, before, 10)
+		require.NoError(t, err)
+		require.Len(t, upgradeOperations, 1)
+		err = svc.MoveOperationsToArchive(upgradeOperations)
+		require.NoError(t, err)
+		orchestrations, err = svc.ListOrchestrationsToArchive(before, 10)
+		require.NoError(t, err)
+		require.Len(t, orchestrations, 1)
+		err = svc.MoveOrchestrationsToArchive(orchestrations)
+		require.NoError(t, err)
+
+		// then
+		_, err = brokerStorage.Operations().GetProvisioningOperationByID("deleted-instance-op")
+		assert.Error(t, err)
+		_, err = brokerStorage.Operations().GetProvisioningOperationByID("existing-instance-op")
+		assert.NoError(t, err)
+		_, err = brokerStorage.Orchestrations().GetByID("orch-id")
+		assert.Error(t, err)
+
+		connection, err := postsql.WaitForDatabaseAccess(cfg.ConnectionURL(), 10, logrus.New())
+		require.NoError(t, err)
+		var archived int
+		err = connection.QueryRow("SELECT COUNT(*) FROM operations_archive").Scan(&archived)
+		require.NoError(t, err)
+		assert.Equal(t, 2, archived)
+		err = connection.QueryRow("SELECT COUNT(*) FROM orchestrations_archive").Scan(&archived)
+		require.NoError(t, err)
+		assert.Equal(t, 1, archived)
+	})
+
 	t.Run("LMS Tenants", func(t *testing.T) {
 		containerCleanupFunc, cfg, err := InitTestDBContainer(t, ctx, "test_DB_1")
 		require.NoError(t, err)
@@ -860,6 +1135,100 @@ func TestSchemaInitializer(t *testing.T) {
 		assert.False(t, differentNameExists)
 		assert.NoError(t, dnErr)
 	})
+
+	t.Run("SubAccount cleanup runs", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		givenRun := internal.SubAccountCleanupRun{
+			ID:          "run-001",
+			DryRun:      true,
+			Description: "dry run finished",
+			SubAccounts: []string{"sa-1", "sa-2"},
+			Instances: []internal.SubAccountCleanupRunInstance{
+				{InstanceID: "inst-1", SubAccountID: "sa-1"},
+			},
+			CreatedAt:  time.Now(),
+			FinishedAt: time.Now(),
+		}
+
+		err = InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		brokerStorage, _, err := NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+
+		svc := brokerStorage.SubAccountCleanupRuns()
+
+		// when
+		err = svc.Insert(givenRun)
+		require.NoError(t, err)
+		gotRun, err := svc.GetByID(givenRun.ID)
+		require.NoError(t, err)
+		_, notFoundErr := svc.GetByID("not-existing")
+
+		// then
+		assert.True(t, gotRun.DryRun)
+		assert.Equal(t, givenRun.SubAccounts, gotRun.SubAccounts)
+		assert.Equal(t, givenRun.Instances, gotRun.Instances)
+		assert.True(t, dberr.IsNotFound(notFoundErr))
+	})
+
+	t.Run("CIS high-water marks", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		err = InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		brokerStorage, _, err := NewFromConfig(cfg, logrus.StandardLogger())
+		require.NoError(t, err)
+
+		svc := brokerStorage.CISHighWaterMarks()
+
+		// when
+		_, notFoundErr := svc.Get("CIS-2.0")
+		err = svc.Save(internal.CISHighWaterMark{Source: "CIS-2.0", LastEventID: "1", LastEventTime: time.Unix(1, 0)})
+		require.NoError(t, err)
+		err = svc.Save(internal.CISHighWaterMark{Source: "CIS-2.0", LastEventID: "2", LastEventTime: time.Unix(2, 0)})
+		require.NoError(t, err)
+		gotMark, err := svc.Get("CIS-2.0")
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, dberr.IsNotFound(notFoundErr))
+		assert.Equal(t, "2", gotMark.LastEventID)
+		assert.True(t, gotMark.LastEventTime.Equal(time.Unix(2, 0)))
+	})
+}
+
+func TestPostgresStorageConformance(t *testing.T) {
+	ctx := context.Background()
+
+	cleanupNetwork, err := EnsureTestNetworkForDB(t, ctx)
+	require.NoError(t, err)
+	defer cleanupNetwork()
+
+	containerCleanupFunc, cfg, err := InitTestDBContainer(t, ctx, "test_DB_conformance")
+	require.NoError(t, err)
+	defer containerCleanupFunc()
+
+	err = InitTestDBTables(t, cfg.ConnectionURL())
+	require.NoError(t, err)
+
+	brokerStorage, connection, err := NewFromConfig(cfg, logrus.StandardLogger())
+	require.NoError(t, err)
+	defer CloseDatabase(t, connection)
+
+	runConformanceSuite(t, func(t *testing.T) BrokerStorage {
+		for table := range FixTables() {
+			_, err := connection.Exec(fmt.Sprintf("TRUNCATE TABLE %s", table))
+			require.NoError(t, err)
+		}
+		return brokerStorage
+	})
 }
 
 func assertProvisioningOperation(t *testing.T, expected, got internal.ProvisioningOperation) {
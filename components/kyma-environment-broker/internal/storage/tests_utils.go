@@ -228,7 +228,10 @@ func FixTables() map[string]string {
 			orchestration_id varchar(64),
 			created_at TIMESTAMPTZ NOT NULL,
 			updated_at TIMESTAMPTZ NOT NULL
-			)`, postsql.OperationTableName),
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s (instance_id)
+			WHERE type = 'upgradeKyma' AND state IN ('pending', 'in progress') AND (orchestration_id IS NULL OR orchestration_id = '')`,
+			postsql.OperationTableName, postsql.SameVersionUpgradeInProgressIndexName, postsql.OperationTableName),
 		postsql.OrchestrationTableName: fmt.Sprintf(
 			`CREATE TABLE IF NOT EXISTS %s (
 			orchestration_id varchar(255) PRIMARY KEY,
@@ -258,5 +261,63 @@ func FixTables() map[string]string {
 			kyma_version text,
 			k8s_version text
 			)`, postsql.RuntimeStateTableName),
+		postsql.SubAccountCleanupRunTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			id varchar(255) PRIMARY KEY,
+			dry_run boolean NOT NULL DEFAULT false,
+			aborted boolean NOT NULL DEFAULT false,
+			description text,
+			data text,
+			created_at TIMESTAMPTZ NOT NULL,
+			finished_at TIMESTAMPTZ NOT NULL
+			)`, postsql.SubAccountCleanupRunTableName),
+		postsql.CISHighWaterMarkTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			source varchar(255) PRIMARY KEY,
+			last_event_id varchar(255),
+			last_event_time TIMESTAMPTZ NOT NULL,
+			failed_attempts integer NOT NULL DEFAULT 0,
+			updated_at TIMESTAMPTZ NOT NULL
+			)`, postsql.CISHighWaterMarkTableName),
+		postsql.IASRotationTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			instance_id varchar(255) PRIMARY KEY,
+			secret_rotated_at TIMESTAMPTZ NOT NULL,
+			dashboard_url text NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+			)`, postsql.IASRotationTableName),
+		postsql.LMSCertificateTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			instance_id varchar(255) PRIMARY KEY,
+			tenant_id varchar(255) NOT NULL,
+			issued_at TIMESTAMPTZ NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+			)`, postsql.LMSCertificateTableName),
+		postsql.OperationArchiveTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			id varchar(255) PRIMARY KEY,
+			instance_id varchar(255) NOT NULL,
+			target_operation_id varchar(255) NOT NULL,
+			version integer NOT NULL,
+			state varchar(32) NOT NULL,
+			description text NOT NULL,
+			type varchar(32) NOT NULL,
+			data json NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL,
+			orchestration_id varchar(64),
+			archived_at TIMESTAMPTZ NOT NULL
+			)`, postsql.OperationArchiveTableName),
+		postsql.OrchestrationArchiveTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			orchestration_id varchar(255) PRIMARY KEY,
+			created_at TIMESTAMPTZ NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL,
+			state varchar(32) NOT NULL,
+			parameters text NOT NULL,
+			description text,
+			archived_at TIMESTAMPTZ NOT NULL
+			)`, postsql.OrchestrationArchiveTableName),
 	}
 }
@@ -9,7 +9,8 @@ CREATE TABLE cluster
     tenant varchar(256) NOT NULL,
     creation_timestamp timestamp without time zone NOT NULL,
     deleted boolean default false,
-    sub_account_id varchar(256)
+    sub_account_id varchar(256),
+    hibernated boolean NOT NULL default false
 );
 
 -- Cluster Config
@@ -40,11 +41,34 @@ CREATE TABLE gardener_config
     enable_kubernetes_version_auto_update boolean NOT NULL,
     enable_machine_image_version_auto_update boolean NOT NULL,
     allow_privileged_containers boolean NOT NULL,
+    hibernation_schedules jsonb,
+    kube_api_server_config jsonb,
     provider_specific_config jsonb,
     UNIQUE(cluster_id),
     foreign key (cluster_id) REFERENCES cluster (id) ON DELETE CASCADE
 );
 
+CREATE TABLE worker_pool
+(
+    id uuid PRIMARY KEY CHECK (id <> '00000000-0000-0000-0000-000000000000'),
+    gardener_config_id uuid NOT NULL,
+    name varchar(256) NOT NULL,
+    machine_type varchar(256) NOT NULL,
+    machine_image varchar(256),
+    machine_image_version varchar(256),
+    disk_type varchar(256) NOT NULL,
+    volume_size_gb integer NOT NULL,
+    auto_scaler_min integer NOT NULL,
+    auto_scaler_max integer NOT NULL,
+    max_surge integer NOT NULL,
+    max_unavailable integer NOT NULL,
+    zones jsonb,
+    labels jsonb,
+    taints jsonb,
+    UNIQUE(gardener_config_id, name),
+    foreign key (gardener_config_id) REFERENCES gardener_config (id) ON DELETE CASCADE
+);
+
 
 -- Operation
 
@@ -59,7 +83,10 @@ CREATE TYPE operation_type AS ENUM (
     'UPGRADE',
     'DEPROVISION',
     'RECONNECT_RUNTIME',
-    'UPGRADE_SHOOT'
+    'UPGRADE_SHOOT',
+    'HIBERNATE',
+    'WAKE_UP',
+    'ROLLBACK'
     );
 
 CREATE TABLE operation
@@ -73,7 +100,10 @@ CREATE TABLE operation
     cluster_id uuid NOT NULL,
     foreign key (cluster_id) REFERENCES cluster (id) ON DELETE CASCADE,
     stage varchar(256) NOT NULL,
-    last_transition timestamp without time zone
+    last_transition timestamp without time zone,
+    lease_owner varchar(256),
+    lease_expiration timestamp without time zone,
+    urgent boolean NOT NULL DEFAULT false
 );
 
 -- Kyma Release
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
@@ -8,6 +9,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
@@ -17,6 +20,7 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/graphql"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/installation/release"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/oauth"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/encryption"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
 	"github.com/pkg/errors"
@@ -45,6 +49,9 @@ func newProvisioningService(
 	deprovisioningQueue queue.OperationQueue,
 	upgradeQueue queue.OperationQueue,
 	shootUpgradeQueue queue.OperationQueue,
+	hibernationQueue queue.OperationQueue,
+	wakeUpQueue queue.OperationQueue,
+	rollbackStarter failure.RollbackStarter,
 	defaultEnableKubernetesVersionAutoUpdate,
 	defaultEnableMachineImageVersionAutoUpdate,
 	forceAllowPrivilegedContainers bool) provisioning.Service {
@@ -54,7 +61,38 @@ func newProvisioningService(
 	inputConverter := provisioning.NewInputConverter(uuidGenerator, releaseProvider, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate, forceAllowPrivilegedContainers)
 	graphQLConverter := provisioning.NewGraphQLConverter()
 
-	return provisioning.NewProvisioningService(inputConverter, graphQLConverter, directorService, dbsFactory, provisioner, uuidGenerator, provisioningQueue, deprovisioningQueue, upgradeQueue, shootUpgradeQueue)
+	return provisioning.NewProvisioningService(inputConverter, graphQLConverter, directorService, dbsFactory, provisioner, uuidGenerator, provisioningQueue, deprovisioningQueue, upgradeQueue, shootUpgradeQueue, hibernationQueue, wakeUpQueue, rollbackStarter)
+}
+
+func newKubeconfigEncrypter(keysFilePath string) (*encryption.EnvelopeEncrypter, error) {
+	keys, err := encryption.ReadKeysFromFile(keysFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.NewEnvelopeEncrypter(keys)
+}
+
+// newAuthenticator returns Authenticator of API callers, nil is returned if the authentication is disabled
+func newAuthenticator(ctx context.Context, config middlewares.AuthConfig) (middlewares.Authenticator, error) {
+	switch config.Mode {
+	case middlewares.AuthModeNone:
+		logrus.Warnf("API authentication is disabled, the tenant and sub-account headers of all requests are trusted. Set the authentication mode to %s or %s outside of local development", middlewares.AuthModeMTLS, middlewares.AuthModeJWT)
+		return nil, nil
+	case middlewares.AuthModeMTLS:
+		return middlewares.NewCertificateAuthenticator(config.MTLS.AllTenantsSubjects), nil
+	case middlewares.AuthModeJWT:
+		return middlewares.NewJWTAuthenticator(ctx, config.JWT)
+	default:
+		return nil, errors.Errorf("unknown authentication mode %s", config.Mode)
+	}
+}
+
+func listenAndServe(server *http.Server, config middlewares.AuthConfig) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS(config.MTLS.ServerCertPath, config.MTLS.ServerKeyPath)
+	}
+	return server.ListenAndServe()
 }
 
 func newDirectorClient(config config) (director.DirectorClient, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
@@ -18,6 +19,8 @@ import (
 	retry "github.com/avast/retry-go"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/notifications"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue"
 	"k8s.io/client-go/rest"
 
@@ -31,6 +34,7 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/installation"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/database"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/encryption"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
 
@@ -61,6 +65,8 @@ type config struct {
 	OauthCredentialsNamespace    string `envconfig:"default=kcp-system"`
 	OauthCredentialsSecretName   string `envconfig:"default=kcp-provisioner-credentials"`
 
+	Auth middlewares.AuthConfig
+
 	Database struct {
 		User     string `envconfig:"default=postgres"`
 		Password string `envconfig:"default=password"`
@@ -70,9 +76,19 @@ type config struct {
 		SSLMode  string `envconfig:"default=disable"`
 	}
 
+	KubeconfigEncryption   encryption.Config
+	StoreKubeconfigs       bool `envconfig:"default=true"`
+	ClearStoredKubeconfigs bool `envconfig:"default=false"`
+
 	ProvisioningTimeout   queue.ProvisioningTimeouts
 	DeprovisioningTimeout queue.DeprovisioningTimeouts
 
+	Queues queue.QueuesConfig
+
+	OperationLease queue.LeaseConfig
+
+	OperationEvents notifications.PollerConfig
+
 	OperatorRoleBinding provisioningStages.OperatorRoleBinding
 
 	Gardener struct {
@@ -92,6 +108,8 @@ type config struct {
 
 	EnqueueInProgressOperations bool `envconfig:"default=true"`
 
+	AutoRollbackFailedUpgrades bool `envconfig:"default=false"`
+
 	MetricsAddress string `envconfig:"default=127.0.0.1:9000"`
 
 	LogLevel string `envconfig:"default=info"`
@@ -100,8 +118,10 @@ type config struct {
 func (c *config) String() string {
 	return fmt.Sprintf("Address: %s, APIEndpoint: %s, DirectorURL: %s, "+
 		"SkipDirectorCertVerification: %v, OauthCredentialsNamespace: %s, OauthCredentialsSecretName: %s, "+
+		"AuthMode: %s, AuthJWTIssuer: %s, "+
 		"DatabaseUser: %s, DatabaseHost: %s, DatabasePort: %s, "+
 		"DatabaseName: %s, DatabaseSSLMode: %s, "+
+		"KubeconfigEncryptionKeysFilePath: %s, StoreKubeconfigs: %v, ClearStoredKubeconfigs: %v, "+
 		"ProvisioningTimeoutClusterCreation: %s "+
 		"ProvisioningTimeoutInstallation: %s, ProvisioningTimeoutUpgrade: %s, "+
 		"ProvisioningTimeoutAgentConfiguration: %s, ProvisioningTimeoutAgentConnection: %s, "+
@@ -109,12 +129,17 @@ func (c *config) String() string {
 		"GardenerProject: %s, GardenerKubeconfigPath: %s, GardenerAuditLogsPolicyConfigMap: %s, AuditLogsTenantConfigPath: %s, "+
 		"ForceAllowPrivilegedContainers: %t, "+
 		"LatestDownloadedReleases: %d, DownloadPreReleases: %v, "+
-		"EnqueueInProgressOperations: %v"+
+		"EnqueueInProgressOperations: %v, AutoRollbackFailedUpgrades: %v, "+
+		"ProvisioningWorkers: %d, DeprovisioningWorkers: %d, UpgradeWorkers: %d, ShootUpgradeWorkers: %d, PriorityTenants: %v, "+
+		"OperationLeaseOwner: %s, OperationLeaseDuration: %s, OperationLeaseReclaimInterval: %s, "+
+		"OperationEventsPollInterval: %s, "+
 		"LogLevel: %s",
 		c.Address, c.APIEndpoint, c.DirectorURL,
 		c.SkipDirectorCertVerification, c.OauthCredentialsNamespace, c.OauthCredentialsSecretName,
+		c.Auth.Mode, c.Auth.JWT.Issuer,
 		c.Database.User, c.Database.Host, c.Database.Port,
 		c.Database.Name, c.Database.SSLMode,
+		c.KubeconfigEncryption.KeysFilePath, c.StoreKubeconfigs, c.ClearStoredKubeconfigs,
 		c.ProvisioningTimeout.ClusterCreation.String(),
 		c.ProvisioningTimeout.Installation.String(), c.ProvisioningTimeout.Upgrade.String(),
 		c.ProvisioningTimeout.AgentConfiguration.String(), c.ProvisioningTimeout.AgentConnection.String(),
@@ -122,7 +147,10 @@ func (c *config) String() string {
 		c.Gardener.Project, c.Gardener.KubeconfigPath, c.Gardener.AuditLogsPolicyConfigMap, c.Gardener.AuditLogsTenantConfigPath,
 		c.Gardener.ForceAllowPrivilegedContainers,
 		c.LatestDownloadedReleases, c.DownloadPreReleases,
-		c.EnqueueInProgressOperations,
+		c.EnqueueInProgressOperations, c.AutoRollbackFailedUpgrades,
+		c.Queues.Provisioning.Workers, c.Queues.Deprovisioning.Workers, c.Queues.Upgrade.Workers, c.Queues.ShootUpgrade.Workers, c.Queues.PriorityTenants,
+		c.OperationLease.Owner, c.OperationLease.Duration.String(), c.OperationLease.ReclaimInterval.String(),
+		c.OperationEvents.PollInterval.String(),
 		c.LogLevel)
 }
 
@@ -143,6 +171,11 @@ func main() {
 	}
 	log.SetLevel(logLevel)
 
+	if cfg.OperationLease.Owner == "" {
+		cfg.OperationLease.Owner, err = os.Hostname()
+		exitOnError(err, "Failed to determine operation lease owner")
+	}
+
 	log.Infof("Starting Provisioner")
 	log.Infof("Config: %s", cfg.String())
 
@@ -171,7 +204,34 @@ func main() {
 		return installationSDK.NewKymaInstaller(c, o...)
 	}
 
-	dbsFactory := dbsession.NewFactory(connection)
+	var kubeconfigCipher dbsession.KubeconfigCipher
+	var kubeconfigEncrypter *encryption.EnvelopeEncrypter
+	if cfg.KubeconfigEncryption.KeysFilePath != "" {
+		kubeconfigEncrypter, err = newKubeconfigEncrypter(cfg.KubeconfigEncryption.KeysFilePath)
+		exitOnError(err, "Failed to create kubeconfig encrypter")
+		kubeconfigCipher = kubeconfigEncrypter
+	}
+
+	var kubeconfigProvider dbsession.KubeconfigProvider
+	if !cfg.StoreKubeconfigs {
+		kubeconfigProvider = gardener.NewKubeconfigProvider(secretsInterface)
+	}
+
+	dbsFactory := dbsession.NewFactory(connection, kubeconfigCipher, kubeconfigProvider)
+
+	if !cfg.StoreKubeconfigs && cfg.ClearStoredKubeconfigs {
+		log.Warn("Removing kubeconfigs stored in the database")
+		err = dbsFactory.NewWriteSession().ClearKubeconfigs()
+		exitOnError(err, "Failed to remove stored kubeconfigs")
+	}
+
+	var reEncryptionJob *encryption.ReEncryptionJob
+	if kubeconfigEncrypter != nil && cfg.StoreKubeconfigs {
+		reEncryptionJob = encryption.NewReEncryptionJob(dbsFactory, kubeconfigEncrypter, cfg.KubeconfigEncryption.ReEncryptionInterval, cfg.KubeconfigEncryption.ReEncryptionBatchSize)
+		// Kubeconfigs stored before the encryption was enabled are encrypted before the API is exposed
+		log.Info("Encrypting stored kubeconfigs...")
+		reEncryptionJob.ReEncryptAll()
+	}
 	installationService := installation.NewInstallationService(cfg.ProvisioningTimeout.Installation, installationHandlerConstructor, cfg.Gardener.ClusterCleanupResourceSelector)
 
 	directorClient, err := newDirectorClient(cfg)
@@ -181,6 +241,13 @@ func main() {
 
 	runtimeConfigurator := runtime.NewRuntimeConfigurator(k8sClientProvider, directorClient)
 
+	// operation events are dispatched to subscribers connected to this replica, changes of operations processed
+	// by other replicas are read by the poller
+	operationEvents := notifications.NewBroker()
+
+	prioritizer := queue.NewTenantPrioritizer(dbsFactory.NewReadSession(), cfg.Queues.PriorityTenants)
+	leaser := queue.NewOperationLeaser(dbsFactory.NewWriteSession(), cfg.OperationLease.Owner, cfg.OperationLease.Duration)
+
 	provisioningQueue := queue.CreateProvisioningQueue(
 		cfg.ProvisioningTimeout,
 		dbsFactory,
@@ -191,13 +258,30 @@ func main() {
 		shootClient,
 		secretsInterface,
 		cfg.OperatorRoleBinding,
-		k8sClientProvider)
+		k8sClientProvider,
+		operationEvents,
+		cfg.Queues.Provisioning,
+		prioritizer,
+		leaser)
+
+	rollbackQueue := queue.CreateRollbackQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, installationService, operationEvents, cfg.Queues.Rollback, prioritizer, leaser)
+
+	rollbackStarter := queue.NewRollbackStarter(dbsFactory, uuid.NewUUIDGenerator(), rollbackQueue)
+
+	var upgradeRollbackStarter failure.RollbackStarter
+	if cfg.AutoRollbackFailedUpgrades {
+		upgradeRollbackStarter = rollbackStarter
+	}
+
+	upgradeQueue := queue.CreateUpgradeQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, installationService, operationEvents, cfg.Queues.Upgrade, prioritizer, leaser, upgradeRollbackStarter)
+
+	deprovisioningQueue := queue.CreateDeprovisioningQueue(cfg.DeprovisioningTimeout, dbsFactory, installationService, directorClient, shootClient, 5*time.Minute, operationEvents, cfg.Queues.Deprovisioning, prioritizer, leaser)
 
-	upgradeQueue := queue.CreateUpgradeQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, installationService)
+	shootUpgradeQueue := queue.CreateShootUpgradeQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, shootClient, operationEvents, cfg.Queues.ShootUpgrade, prioritizer, leaser)
 
-	deprovisioningQueue := queue.CreateDeprovisioningQueue(cfg.DeprovisioningTimeout, dbsFactory, installationService, directorClient, shootClient, 5*time.Minute)
+	hibernationQueue := queue.CreateHibernationQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, shootClient, operationEvents, cfg.Queues.Hibernation, prioritizer, leaser)
 
-	shootUpgradeQueue := queue.CreateShootUpgradeQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, shootClient)
+	wakeUpQueue := queue.CreateWakeUpQueue(cfg.ProvisioningTimeout, dbsFactory, directorClient, shootClient, operationEvents, cfg.Queues.WakeUp, prioritizer, leaser)
 
 	provisioner := gardener.NewProvisioner(gardenerNamespace, shootClient, dbsFactory, cfg.Gardener.AuditLogsPolicyConfigMap, cfg.Gardener.MaintenanceWindowConfigPath)
 	shootController, err := newShootController(gardenerNamespace, gardenerClusterConfig, dbsFactory, cfg.Gardener.AuditLogsTenantConfigPath)
@@ -225,12 +309,15 @@ func main() {
 		deprovisioningQueue,
 		upgradeQueue,
 		shootUpgradeQueue,
+		hibernationQueue,
+		wakeUpQueue,
+		rollbackStarter,
 		cfg.Gardener.DefaultEnableKubernetesVersionAutoUpdate,
 		cfg.Gardener.DefaultEnableMachineImageVersionAutoUpdate,
 		cfg.Gardener.ForceAllowPrivilegedContainers)
 
 	validator := api.NewValidator(dbsFactory.NewReadSession())
-	resolver := api.NewResolver(provisioningSVC, validator)
+	resolver := api.NewResolver(provisioningSVC, validator, operationEvents)
 	logger := log.WithField("Component", "Artifact Downloader")
 	downloader := release.NewArtifactsDownloader(releaseRepository, cfg.LatestDownloadedReleases, cfg.DownloadPreReleases, httpClient, fileDownloader, logger)
 
@@ -249,6 +336,33 @@ func main() {
 
 	shootUpgradeQueue.Run(ctx.Done())
 
+	hibernationQueue.Run(ctx.Done())
+
+	wakeUpQueue.Run(ctx.Done())
+
+	rollbackQueue.Run(ctx.Done())
+
+	// Run reclaimer of operations left by stopped replicas
+	leaseReclaimer := queue.NewLeaseReclaimer(dbsFactory.NewReadSession(), map[model.OperationType]queue.OperationQueue{
+		model.Provision:    provisioningQueue,
+		model.Deprovision:  deprovisioningQueue,
+		model.Upgrade:      upgradeQueue,
+		model.UpgradeShoot: shootUpgradeQueue,
+		model.Hibernate:    hibernationQueue,
+		model.WakeUp:       wakeUpQueue,
+		model.Rollback:     rollbackQueue,
+	}, cfg.OperationLease.ReclaimInterval)
+	leaseReclaimer.Run(ctx.Done())
+
+	// Run poller of operations changed by other replicas
+	operationEventsPoller := notifications.NewPoller(dbsFactory.NewReadSession(), operationEvents, cfg.OperationEvents.PollInterval)
+	operationEventsPoller.Run(ctx.Done())
+
+	// Run encryption of stored kubeconfigs with the newest key
+	if reEncryptionJob != nil {
+		reEncryptionJob.Run(ctx.Done())
+	}
+
 	gqlCfg := gqlschema.Config{
 		Resolvers: resolver,
 	}
@@ -256,16 +370,32 @@ func main() {
 
 	presenter := apperrors.NewPresenter(log.StandardLogger())
 
+	authenticator, err := newAuthenticator(ctx, cfg.Auth)
+	exitOnError(err, "Failed to initialize API authentication")
+
 	log.Infof("Registering endpoint on %s...", cfg.APIEndpoint)
 	router := mux.NewRouter()
 	router.Use(middlewares.ExtractTenant)
 
 	router.HandleFunc("/", handler.Playground("Dataloader", cfg.PlaygroundAPIEndpoint))
-	router.HandleFunc(cfg.APIEndpoint, handler.GraphQL(executableSchema, handler.ErrorPresenter(presenter.Do)))
+	auditLogMiddleware := api.NewAuditLogMiddleware(log.WithField("Component", "AuditLog"))
+	var apiHandler http.Handler = handler.GraphQL(executableSchema, handler.ErrorPresenter(presenter.Do), handler.ResolverMiddleware(auditLogMiddleware))
+	if authenticator != nil {
+		apiHandler = middlewares.Authenticate(authenticator)(apiHandler)
+	}
+	router.Handle(cfg.APIEndpoint, apiHandler)
 	router.HandleFunc("/healthz", healthz.NewHTTPHandler(log.StandardLogger()))
 
 	// Metrics
-	err = metrics.Register(dbsFactory.NewReadSession())
+	err = metrics.Register(dbsFactory.NewReadSession(), map[model.OperationType]metrics.QueueDepthGetter{
+		model.Provision:    provisioningQueue,
+		model.Deprovision:  deprovisioningQueue,
+		model.Upgrade:      upgradeQueue,
+		model.UpgradeShoot: shootUpgradeQueue,
+		model.Hibernate:    hibernationQueue,
+		model.WakeUp:       wakeUpQueue,
+		model.Rollback:     rollbackQueue,
+	})
 	exitOnError(err, "Failed to register metrics collectors")
 
 	// Expose metrics on different port as it cannot be secured with mTLS
@@ -277,6 +407,15 @@ func main() {
 		Addr:    cfg.MetricsAddress,
 	}
 
+	apiServer := &http.Server{
+		Handler: router,
+		Addr:    cfg.Address,
+	}
+	if cfg.Auth.Mode == middlewares.AuthModeMTLS {
+		apiServer.TLSConfig, err = middlewares.NewServerTLSConfig(cfg.Auth.MTLS)
+		exitOnError(err, "Failed to create API server TLS config")
+	}
+
 	log.Infof("API listening on %s...", cfg.Address)
 	log.Infof("Metrics API listening on %s...", cfg.MetricsAddress)
 
@@ -286,7 +425,7 @@ func main() {
 	go func() {
 		defer wg.Done()
 
-		if err := http.ListenAndServe(cfg.Address, router); err != nil {
+		if err := listenAndServe(apiServer, cfg.Auth); err != nil {
 			log.Errorf("Error starting server: %s", err.Error())
 		}
 	}()
@@ -298,14 +437,14 @@ func main() {
 	}()
 
 	if cfg.EnqueueInProgressOperations {
-		err = enqueueOperationsInProgress(dbsFactory, provisioningQueue, deprovisioningQueue, upgradeQueue, shootUpgradeQueue)
+		err = enqueueOperationsInProgress(dbsFactory, provisioningQueue, deprovisioningQueue, upgradeQueue, shootUpgradeQueue, hibernationQueue, wakeUpQueue, rollbackQueue)
 		exitOnError(err, "Failed to enqueue in progress operations")
 	}
 
 	wg.Wait()
 }
 
-func enqueueOperationsInProgress(dbFactory dbsession.Factory, provisioningQueue, deprovisioningQueue, upgradeQueue, shootUpgradeQueue queue.OperationQueue) error {
+func enqueueOperationsInProgress(dbFactory dbsession.Factory, provisioningQueue, deprovisioningQueue, upgradeQueue, shootUpgradeQueue, hibernationQueue, wakeUpQueue, rollbackQueue queue.OperationQueue) error {
 	readSession := dbFactory.NewReadSession()
 
 	var inProgressOps []model.Operation
@@ -327,20 +466,32 @@ func enqueueOperationsInProgress(dbFactory dbsession.Factory, provisioningQueue,
 
 	for _, op := range inProgressOps {
 		if op.Type == model.Provision {
-			provisioningQueue.Add(op.ID)
+			queue.Enqueue(provisioningQueue, op)
 			continue
 		}
 
 		if op.Type == model.Deprovision {
-			deprovisioningQueue.Add(op.ID)
+			queue.Enqueue(deprovisioningQueue, op)
 		}
 
 		if op.Type == model.Upgrade {
-			upgradeQueue.Add(op.ID)
+			queue.Enqueue(upgradeQueue, op)
 		}
 
 		if op.Type == model.UpgradeShoot {
-			shootUpgradeQueue.Add(op.ID)
+			queue.Enqueue(shootUpgradeQueue, op)
+		}
+
+		if op.Type == model.Hibernate {
+			queue.Enqueue(hibernationQueue, op)
+		}
+
+		if op.Type == model.WakeUp {
+			queue.Enqueue(wakeUpQueue, op)
+		}
+
+		if op.Type == model.Rollback {
+			queue.Enqueue(rollbackQueue, op)
 		}
 	}
 
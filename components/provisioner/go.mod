@@ -5,6 +5,7 @@ go 1.14
 require (
 	github.com/99designs/gqlgen v0.9.3
 	github.com/avast/retry-go v2.6.0+incompatible
+	github.com/coreos/go-oidc v2.2.1+incompatible
 	github.com/gardener/gardener v1.10.1-0.20200903060046-8bed4ed6c257
 	github.com/gocraft/dbr/v2 v2.6.3
 	github.com/google/uuid v1.1.1
@@ -26,6 +27,7 @@ require (
 	github.com/testcontainers/testcontainers-go v0.7.0
 	github.com/vektah/gqlparser v1.2.0
 	github.com/vrischmann/envconfig v1.3.0
+	gopkg.in/square/go-jose.v2 v2.2.2
 	gotest.tools v2.2.0+incompatible
 	k8s.io/api v0.18.10
 	k8s.io/apiextensions-apiserver v0.18.8
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"sort"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
+	"github.com/sirupsen/logrus"
+)
+
+const anonymousPrincipal = "anonymous"
+
+// NewAuditLogMiddleware returns GraphQL field middleware which logs every mutation with the caller identity and the result.
+// Only string arguments, such as Runtime IDs, are logged because inputs may contain credentials.
+func NewAuditLogMiddleware(logger logrus.FieldLogger) graphql.FieldMiddleware {
+	return func(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+		resolverCtx := graphql.GetResolverContext(ctx)
+		if resolverCtx == nil || resolverCtx.Object != "Mutation" {
+			return next(ctx)
+		}
+
+		result, err := next(ctx)
+
+		entry := logger.WithFields(logrus.Fields{
+			"mutation":  resolverCtx.Field.Name,
+			"principal": principalName(ctx),
+			"tenant":    headerTenant(ctx),
+		})
+		for _, name := range sortedArgNames(resolverCtx.Args) {
+			if value, ok := resolverCtx.Args[name].(string); ok {
+				entry = entry.WithField("arg."+name, value)
+			}
+		}
+
+		if err != nil {
+			entry.WithField("result", "failure").Warnf("Mutation %s failed: %s", resolverCtx.Field.Name, err.Error())
+		} else {
+			entry.WithField("result", "success").Infof("Mutation %s succeeded", resolverCtx.Field.Name)
+		}
+
+		return result, err
+	}
+}
+
+func principalName(ctx context.Context) string {
+	principal, authenticated := middlewares.PrincipalFromContext(ctx)
+	if !authenticated {
+		return anonymousPrincipal
+	}
+	return principal.Name
+}
+
+func headerTenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(middlewares.Tenant).(string)
+	return tenant
+}
+
+func sortedArgNames(args map[string]interface{}) []string {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
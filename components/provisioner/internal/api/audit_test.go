@@ -0,0 +1,88 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/api"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/ast"
+)
+
+func TestAuditLogMiddleware(t *testing.T) {
+	resolverContext := func(object, field string, args map[string]interface{}) context.Context {
+		ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+		ctx = middlewares.WithPrincipal(ctx, middlewares.Principal{Name: "broker", Tenants: []string{tenant}})
+		return graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+			Object: object,
+			Field:  graphql.CollectedField{Field: &ast.Field{Name: field}},
+			Args:   args,
+		})
+	}
+
+	t.Run("Should log successful mutation", func(t *testing.T) {
+		//given
+		logger, hook := test.NewNullLogger()
+		middleware := api.NewAuditLogMiddleware(logger)
+		ctx := resolverContext("Mutation", "deprovisionRuntime", map[string]interface{}{"id": runtimeID})
+
+		//when
+		result, err := middleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return operationID, nil
+		})
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, operationID, result)
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		assert.Equal(t, logrus.InfoLevel, entry.Level)
+		assert.Equal(t, "deprovisionRuntime", entry.Data["mutation"])
+		assert.Equal(t, "broker", entry.Data["principal"])
+		assert.Equal(t, tenant, entry.Data["tenant"])
+		assert.Equal(t, runtimeID, entry.Data["arg.id"])
+		assert.Equal(t, "success", entry.Data["result"])
+	})
+
+	t.Run("Should log failed mutation without non string arguments", func(t *testing.T) {
+		//given
+		logger, hook := test.NewNullLogger()
+		middleware := api.NewAuditLogMiddleware(logger)
+		ctx := resolverContext("Mutation", "provisionRuntime", map[string]interface{}{"config": struct{ Credentials string }{"secret"}})
+
+		//when
+		_, err := middleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("failed")
+		})
+
+		//then
+		require.Error(t, err)
+		entry := hook.LastEntry()
+		require.NotNil(t, entry)
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Equal(t, "failure", entry.Data["result"])
+		assert.NotContains(t, entry.Data, "arg.config")
+	})
+
+	t.Run("Should not log queries", func(t *testing.T) {
+		//given
+		logger, hook := test.NewNullLogger()
+		middleware := api.NewAuditLogMiddleware(logger)
+		ctx := resolverContext("Query", "runtimeStatus", map[string]interface{}{"id": runtimeID})
+
+		//when
+		_, err := middleware(ctx, func(ctx context.Context) (interface{}, error) {
+			return nil, nil
+		})
+
+		//then
+		require.NoError(t, err)
+		assert.Empty(t, hook.AllEntries())
+	})
+}
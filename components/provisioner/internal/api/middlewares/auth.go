@@ -0,0 +1,93 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	AuthModeNone = "none"
+	AuthModeMTLS = "mtls"
+	AuthModeJWT  = "jwt"
+)
+
+type contextKey string
+
+const principalKey contextKey = "principal"
+
+// AuthConfig holds settings of the authentication of the API callers
+type AuthConfig struct {
+	Mode string `envconfig:"default=none"`
+	MTLS MTLSConfig
+	JWT  JWTConfig
+}
+
+// Principal is the authenticated identity of the API caller
+type Principal struct {
+	Name        string
+	Tenants     []string
+	SubAccounts []string
+	Scopes      []string
+	AllTenants  bool
+	// Urgent is set for principals which may add their operations to the priority lane of the queues
+	Urgent bool
+}
+
+// CanAccess checks if the principal may access resources of the tenant
+func (p Principal) CanAccess(tenant string) bool {
+	return p.AllTenants || contains(p.Tenants, tenant)
+}
+
+// CanRequestUrgent checks if the principal may request urgent processing of operations
+func (p Principal) CanRequestUrgent() bool {
+	return p.AllTenants || p.Urgent
+}
+
+// CanAccessSubAccount checks if the principal may create resources of the sub-account
+func (p Principal) CanAccessSubAccount(subAccount string) bool {
+	return p.AllTenants || contains(p.SubAccounts, subAccount)
+}
+
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Authenticate rejects requests which cannot be authenticated and stores the Principal of the others in the request context
+func Authenticate(authenticator Authenticator) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := authenticator.Authenticate(r)
+			if err != nil {
+				log.Warnf("Failed to authenticate request from %s: %s", r.RemoteAddr, err.Error())
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), principalKey, principal)
+
+			handler.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// PrincipalFromContext returns the authenticated Principal, false is returned if the authentication is disabled
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(Principal)
+	return principal, ok
+}
+
+// WithPrincipal returns the context with the Principal as if it was authenticated
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package middlewares
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuthenticator struct {
+	principal Principal
+	err       error
+}
+
+func (a fakeAuthenticator) Authenticate(_ *http.Request) (Principal, error) {
+	return a.principal, a.err
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Run("Should store principal in request context", func(t *testing.T) {
+		//given
+		principal := Principal{Name: "caller", Tenants: []string{"tenant"}}
+
+		var authenticated Principal
+		var found bool
+		handler := Authenticate(fakeAuthenticator{principal: principal})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authenticated, found = PrincipalFromContext(r.Context())
+		}))
+		recorder := httptest.NewRecorder()
+
+		//when
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+
+		//then
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		require.True(t, found)
+		assert.Equal(t, principal, authenticated)
+	})
+
+	t.Run("Should reject request which cannot be authenticated", func(t *testing.T) {
+		//given
+		called := false
+		handler := Authenticate(fakeAuthenticator{err: errors.New("invalid token")})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		recorder := httptest.NewRecorder()
+
+		//when
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+
+		//then
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.False(t, called)
+	})
+}
+
+func TestPrincipal_CanAccess(t *testing.T) {
+	assert.True(t, Principal{Tenants: []string{"tenant"}}.CanAccess("tenant"))
+	assert.False(t, Principal{Tenants: []string{"tenant"}}.CanAccess("other-tenant"))
+	assert.True(t, Principal{AllTenants: true}.CanAccess("other-tenant"))
+}
+
+func TestPrincipal_CanAccessSubAccount(t *testing.T) {
+	assert.True(t, Principal{SubAccounts: []string{"sub-account"}}.CanAccessSubAccount("sub-account"))
+	assert.False(t, Principal{SubAccounts: []string{"sub-account"}}.CanAccessSubAccount("other-sub-account"))
+	assert.False(t, Principal{Tenants: []string{"tenant"}}.CanAccessSubAccount("sub-account"))
+	assert.True(t, Principal{AllTenants: true}.CanAccessSubAccount("other-sub-account"))
+}
+
+func TestCertificateAuthenticator_Authenticate(t *testing.T) {
+	authenticator := NewCertificateAuthenticator([]string{"operator"})
+
+	for _, testCase := range []struct {
+		description string
+		subject     pkix.Name
+		expected    Principal
+	}{
+		{
+			description: "tenant client",
+			subject:     pkix.Name{CommonName: "broker", OrganizationalUnit: []string{"tenant-1", "tenant-2"}},
+			expected:    Principal{Name: "broker", Tenants: []string{"tenant-1", "tenant-2"}},
+		},
+		{
+			description: "all tenants client",
+			subject:     pkix.Name{CommonName: "operator"},
+			expected:    Principal{Name: "operator", AllTenants: true},
+		},
+	} {
+		t.Run("Should authenticate "+testCase.description, func(t *testing.T) {
+			//given
+			request := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+			request.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Subject: testCase.subject}}},
+			}
+
+			//when
+			principal, err := authenticator.Authenticate(request)
+
+			//then
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, principal)
+		})
+	}
+
+	t.Run("Should return error when client certificate is not verified", func(t *testing.T) {
+		//given
+		request := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+		request.TLS = &tls.ConnectionState{}
+
+		//when
+		_, err := authenticator.Authenticate(request)
+
+		//then
+		require.Error(t, err)
+	})
+
+	t.Run("Should return error when client certificate has no common name", func(t *testing.T) {
+		//given
+		request := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+		request.TLS = &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{OrganizationalUnit: []string{"tenant"}}}}},
+		}
+
+		//when
+		_, err := authenticator.Authenticate(request)
+
+		//then
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,100 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc"
+	"github.com/pkg/errors"
+)
+
+// JWTConfig holds settings of the authentication with OAuth2 access tokens
+type JWTConfig struct {
+	JwksURL         string `envconfig:"optional"`
+	Issuer          string `envconfig:"optional"`
+	Audience        string `envconfig:"optional"`
+	TenantClaim     string `envconfig:"default=tenant"`
+	SubAccountClaim string `envconfig:"default=sub_account"`
+	ScopeClaim      string `envconfig:"default=scope"`
+	AllTenantsScope string `envconfig:"default=provisioner:all-tenants"`
+	UrgentScope     string `envconfig:"default=provisioner:urgent"`
+}
+
+type jwtAuthenticator struct {
+	verifier *oidc.IDTokenVerifier
+	config   JWTConfig
+}
+
+// NewJWTAuthenticator returns Authenticator which identifies callers by bearer tokens signed with keys from the JWKS URL.
+// The subject of the token is the name of the Principal, the tenant and sub-account claims contain tenants and sub-accounts it may access.
+// Principals with the all tenants scope in the scope claim may access all tenants, principals with the urgent scope or the all tenants
+// scope may request urgent operations. Only tokens of the issuer issued for the audience are accepted.
+func NewJWTAuthenticator(ctx context.Context, config JWTConfig) (Authenticator, error) {
+	if config.JwksURL == "" {
+		return nil, errors.New("JWKS URL is required")
+	}
+	if config.Issuer == "" {
+		return nil, errors.New("issuer is required")
+	}
+	if config.Audience == "" {
+		return nil, errors.New("audience is required")
+	}
+
+	keySet := oidc.NewRemoteKeySet(ctx, config.JwksURL)
+	verifier := oidc.NewVerifier(config.Issuer, keySet, &oidc.Config{
+		ClientID:             config.Audience,
+		SupportedSigningAlgs: []string{oidc.RS256, oidc.ES256},
+	})
+
+	return &jwtAuthenticator{
+		verifier: verifier,
+		config:   config,
+	}, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return Principal{}, errors.New("bearer token is required")
+	}
+
+	token, err := a.verifier.Verify(r.Context(), strings.TrimPrefix(authorization, "Bearer "))
+	if err != nil {
+		return Principal{}, errors.Wrap(err, "while verifying token")
+	}
+
+	claims := map[string]interface{}{}
+	err = token.Claims(&claims)
+	if err != nil {
+		return Principal{}, errors.Wrap(err, "while reading token claims")
+	}
+
+	scopes := stringsClaim(claims[a.config.ScopeClaim])
+
+	return Principal{
+		Name:        token.Subject,
+		Tenants:     stringsClaim(claims[a.config.TenantClaim]),
+		SubAccounts: stringsClaim(claims[a.config.SubAccountClaim]),
+		Scopes:      scopes,
+		AllTenants:  contains(scopes, a.config.AllTenantsScope),
+		Urgent:      contains(scopes, a.config.UrgentScope),
+	}, nil
+}
+
+// stringsClaim returns values of the claim which is either space separated string or list of strings
+func stringsClaim(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	keyID    = "key-1"
+	issuer   = "https://issuer.example.com"
+	audience = "provisioner"
+)
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keySet := jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: &privateKey.PublicKey, KeyID: keyID, Algorithm: "RS256", Use: "sig"}},
+		}
+		err := json.NewEncoder(w).Encode(keySet)
+		require.NoError(t, err)
+	}))
+	defer jwksServer.Close()
+
+	authenticator, err := NewJWTAuthenticator(context.Background(), JWTConfig{
+		JwksURL:         jwksServer.URL,
+		Issuer:          issuer,
+		Audience:        audience,
+		TenantClaim:     "tenant",
+		SubAccountClaim: "sub_account",
+		ScopeClaim:      "scope",
+		AllTenantsScope: "provisioner:all-tenants",
+		UrgentScope:     "provisioner:urgent",
+	})
+	require.NoError(t, err)
+
+	t.Run("Should authenticate tenant client", func(t *testing.T) {
+		//given
+		request := requestWithToken(t, privateKey, map[string]interface{}{
+			"sub":         "broker",
+			"tenant":      []string{"tenant-1", "tenant-2"},
+			"sub_account": "sub-account-1",
+			"scope":       "runtimes:read runtimes:write",
+		})
+
+		//when
+		principal, err := authenticator.Authenticate(request)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, Principal{
+			Name:        "broker",
+			Tenants:     []string{"tenant-1", "tenant-2"},
+			SubAccounts: []string{"sub-account-1"},
+			Scopes:      []string{"runtimes:read", "runtimes:write"},
+		}, principal)
+	})
+
+	t.Run("Should authenticate all tenants client", func(t *testing.T) {
+		//given
+		request := requestWithToken(t, privateKey, map[string]interface{}{
+			"sub":   "operator",
+			"scope": "provisioner:all-tenants",
+		})
+
+		//when
+		principal, err := authenticator.Authenticate(request)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, "operator", principal.Name)
+		assert.True(t, principal.AllTenants)
+	})
+
+	t.Run("Should authenticate client which may request urgent operations", func(t *testing.T) {
+		//given
+		request := requestWithToken(t, privateKey, map[string]interface{}{
+			"sub":    "broker",
+			"tenant": "tenant-1",
+			"scope":  "runtimes:write provisioner:urgent",
+		})
+
+		//when
+		principal, err := authenticator.Authenticate(request)
+
+		//then
+		require.NoError(t, err)
+		assert.True(t, principal.Urgent)
+		assert.True(t, principal.CanRequestUrgent())
+		assert.False(t, principal.AllTenants)
+	})
+
+	t.Run("Should return error when token is issued for other audience", func(t *testing.T) {
+		//given
+		request := requestWithToken(t, privateKey, map[string]interface{}{
+			"sub": "broker",
+			"aud": "other",
+		})
+
+		//when
+		_, err := authenticator.Authenticate(request)
+
+		//then
+		require.Error(t, err)
+	})
+
+	t.Run("Should return error when token is issued by other issuer", func(t *testing.T) {
+		//given
+		request := requestWithToken(t, privateKey, map[string]interface{}{
+			"sub": "broker",
+			"iss": "https://other.example.com",
+		})
+
+		//when
+		_, err := authenticator.Authenticate(request)
+
+		//then
+		require.Error(t, err)
+	})
+
+	t.Run("Should return error when token is signed with unknown key", func(t *testing.T) {
+		//given
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		request := requestWithToken(t, otherKey, map[string]interface{}{"sub": "broker"})
+
+		//when
+		_, err = authenticator.Authenticate(request)
+
+		//then
+		require.Error(t, err)
+	})
+
+	t.Run("Should return error when bearer token is missing", func(t *testing.T) {
+		//when
+		_, err := authenticator.Authenticate(httptest.NewRequest(http.MethodPost, "/graphql", nil))
+
+		//then
+		require.Error(t, err)
+	})
+}
+
+func TestNewJWTAuthenticator(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		config      JWTConfig
+	}{
+		{
+			description: "JWKS URL",
+			config:      JWTConfig{Issuer: issuer, Audience: audience},
+		},
+		{
+			description: "issuer",
+			config:      JWTConfig{JwksURL: "https://issuer.example.com/keys", Audience: audience},
+		},
+		{
+			description: "audience",
+			config:      JWTConfig{JwksURL: "https://issuer.example.com/keys", Issuer: issuer},
+		},
+	} {
+		t.Run("Should return error when "+testCase.description+" is not set", func(t *testing.T) {
+			//when
+			_, err := NewJWTAuthenticator(context.Background(), testCase.config)
+
+			//then
+			require.Error(t, err)
+		})
+	}
+}
+
+func requestWithToken(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) *http.Request {
+	tokenClaims := map[string]interface{}{
+		"iss": issuer,
+		"aud": audience,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		tokenClaims[name] = value
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithHeader("kid", keyID),
+	)
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(tokenClaims)
+	require.NoError(t, err)
+
+	signed, err := signer.Sign(payload)
+	require.NoError(t, err)
+
+	token, err := signed.CompactSerialize()
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	return request
+}
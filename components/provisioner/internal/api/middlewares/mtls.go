@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// MTLSConfig holds settings of the authentication with client certificates
+type MTLSConfig struct {
+	ServerCertPath     string   `envconfig:"optional"`
+	ServerKeyPath      string   `envconfig:"optional"`
+	ClientCAPath       string   `envconfig:"optional"`
+	AllTenantsSubjects []string `envconfig:"optional"`
+}
+
+// NewServerTLSConfig returns TLS config of the API server which verifies client certificates signed by the client CA.
+// Requests without the certificate are accepted on TLS level so that health checks work, they are rejected by the Authenticate middleware.
+func NewServerTLSConfig(config MTLSConfig) (*tls.Config, error) {
+	caCert, err := ioutil.ReadFile(config.ClientCAPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading client CA certificate %s", config.ClientCAPath)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caCert) {
+		return nil, errors.Errorf("client CA certificate %s does not contain PEM encoded certificates", config.ClientCAPath)
+	}
+
+	return &tls.Config{
+		ClientCAs:  clientCAs,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+type certificateAuthenticator struct {
+	allTenantsSubjects []string
+}
+
+// NewCertificateAuthenticator returns Authenticator which identifies callers by the verified client certificate.
+// The common name of the certificate is the name of the Principal and organizational units are the tenants it may access.
+// Principals with common names from allTenantsSubjects may access all tenants and request urgent operations.
+// The certificate does not carry sub-accounts, so only principals which may access all tenants can pass the sub-account header.
+func NewCertificateAuthenticator(allTenantsSubjects []string) Authenticator {
+	return &certificateAuthenticator{
+		allTenantsSubjects: allTenantsSubjects,
+	}
+}
+
+func (a *certificateAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return Principal{}, errors.New("verified client certificate is required")
+	}
+
+	subject := r.TLS.VerifiedChains[0][0].Subject
+	if subject.CommonName == "" {
+		return Principal{}, errors.New("client certificate does not contain common name")
+	}
+
+	return Principal{
+		Name:       subject.CommonName,
+		Tenants:    subject.OrganizationalUnit,
+		AllTenants: contains(a.allTenantsSubjects, subject.CommonName),
+	}, nil
+}
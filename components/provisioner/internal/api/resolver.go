@@ -6,6 +6,8 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/notifications"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 
 	log "github.com/sirupsen/logrus"
 
@@ -14,27 +16,43 @@ import (
 )
 
 type Resolver struct {
-	provisioning provisioning.Service
-	validator    Validator
+	provisioning     provisioning.Service
+	validator        Validator
+	operationEvents  notifications.Subscriber
+	graphQLConverter provisioning.GraphQLConverter
 }
 
 func (r *Resolver) Mutation() gqlschema.MutationResolver {
 	return &Resolver{
-		provisioning: r.provisioning,
-		validator:    r.validator,
+		provisioning:     r.provisioning,
+		validator:        r.validator,
+		operationEvents:  r.operationEvents,
+		graphQLConverter: r.graphQLConverter,
 	}
 }
 func (r *Resolver) Query() gqlschema.QueryResolver {
 	return &Resolver{
-		provisioning: r.provisioning,
-		validator:    r.validator,
+		provisioning:     r.provisioning,
+		validator:        r.validator,
+		operationEvents:  r.operationEvents,
+		graphQLConverter: r.graphQLConverter,
+	}
+}
+func (r *Resolver) Subscription() gqlschema.SubscriptionResolver {
+	return &Resolver{
+		provisioning:     r.provisioning,
+		validator:        r.validator,
+		operationEvents:  r.operationEvents,
+		graphQLConverter: r.graphQLConverter,
 	}
 }
 
-func NewResolver(provisioningService provisioning.Service, validator Validator) *Resolver {
+func NewResolver(provisioningService provisioning.Service, validator Validator, operationEvents notifications.Subscriber) *Resolver {
 	return &Resolver{
-		provisioning: provisioningService,
-		validator:    validator,
+		provisioning:     provisioningService,
+		validator:        validator,
+		operationEvents:  operationEvents,
+		graphQLConverter: provisioning.NewGraphQLConverter(),
 	}
 }
 
@@ -51,7 +69,17 @@ func (r *Resolver) ProvisionRuntime(ctx context.Context, config gqlschema.Provis
 		return nil, err
 	}
 
-	subAccount := getSubAccount(ctx)
+	subAccount, err := getSubAccount(ctx)
+	if err != nil {
+		log.Errorf("Failed to provision Runtime %s: %s", config.RuntimeInput.Name, err)
+		return nil, err
+	}
+
+	err = authorizeUrgent(ctx, config.Urgent)
+	if err != nil {
+		log.Errorf("Failed to provision Runtime %s: %s", config.RuntimeInput.Name, err)
+		return nil, err
+	}
 
 	log.Infof("Requested provisioning of Runtime %s.", config.RuntimeInput.Name)
 
@@ -99,6 +127,12 @@ func (r *Resolver) UpgradeRuntime(ctx context.Context, runtimeId string, input g
 		return nil, err
 	}
 
+	err = authorizeUrgent(ctx, input.Urgent)
+	if err != nil {
+		log.Errorf("Failed to upgrade Runtime %s: %s", runtimeId, err)
+		return nil, err
+	}
+
 	operationStatus, err := r.provisioning.UpgradeRuntime(runtimeId, input)
 	if err != nil {
 		log.Errorf("Failed to upgrade Runtime %s: %s", runtimeId, err)
@@ -124,6 +158,24 @@ func (r *Resolver) RollBackUpgradeOperation(ctx context.Context, runtimeID strin
 	return runtimeStatus, nil
 }
 
+func (r *Resolver) RollbackRuntimeUpgrade(ctx context.Context, runtimeID string) (*gqlschema.OperationStatus, error) {
+	log.Infof("Requested rollback of last upgrade of Runtime %s.", runtimeID)
+
+	_, err := r.getAndValidateTenant(ctx, runtimeID)
+	if err != nil {
+		log.Errorf("Failed to roll back last Runtime upgrade: %s, Runtime ID: %s", err, runtimeID)
+		return nil, err
+	}
+
+	operationStatus, err := r.provisioning.RollbackRuntimeUpgrade(runtimeID)
+	if err != nil {
+		log.Errorf("Failed to roll back last Runtime upgrade: %s, Runtime ID: %s", err, runtimeID)
+		return nil, err
+	}
+
+	return operationStatus, nil
+}
+
 func (r *Resolver) ReconnectRuntimeAgent(ctx context.Context, id string) (string, error) {
 	return "", nil
 }
@@ -167,6 +219,58 @@ func (r *Resolver) RuntimeOperationStatus(ctx context.Context, operationID strin
 	return status, nil
 }
 
+func (r *Resolver) Runtimes(ctx context.Context, filter *gqlschema.RuntimesFilter, first *int, after *string) (*gqlschema.RuntimesPage, error) {
+	log.Infof("Requested to list Runtimes.")
+
+	runtimesFilter := gqlschema.RuntimesFilter{}
+	if filter != nil {
+		runtimesFilter = *filter
+	}
+
+	tenant, err := getListTenant(ctx, runtimesFilter.Tenant)
+	if err != nil {
+		log.Errorf("Failed to list Runtimes: %s", err)
+		return nil, err
+	}
+	runtimesFilter.Tenant = tenant
+
+	page, err := r.provisioning.ListRuntimes(&runtimesFilter, first, after)
+	if err != nil {
+		log.Errorf("Failed to list Runtimes: %s", err)
+		return nil, err
+	}
+
+	log.Infof("Listing Runtimes succeeded, returned %d of %d Runtimes.", len(page.Data), page.TotalCount)
+
+	return page, nil
+}
+
+func (r *Resolver) Operations(ctx context.Context, filter *gqlschema.OperationsFilter, first *int, after *string) (*gqlschema.OperationsPage, error) {
+	log.Infof("Requested to list operations.")
+
+	operationsFilter := gqlschema.OperationsFilter{}
+	if filter != nil {
+		operationsFilter = *filter
+	}
+
+	tenant, err := getListTenant(ctx, operationsFilter.Tenant)
+	if err != nil {
+		log.Errorf("Failed to list operations: %s", err)
+		return nil, err
+	}
+	operationsFilter.Tenant = tenant
+
+	page, err := r.provisioning.ListOperations(&operationsFilter, first, after)
+	if err != nil {
+		log.Errorf("Failed to list operations: %s", err)
+		return nil, err
+	}
+
+	log.Infof("Listing operations succeeded, returned %d of %d operations.", len(page.Data), page.TotalCount)
+
+	return page, nil
+}
+
 func (r *Resolver) UpgradeShoot(ctx context.Context, runtimeID string, input gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, error) {
 	log.Infof("Requested to upgrade Gardener Shoot cluster specification for Runtime : %s.", runtimeID)
 
@@ -182,6 +286,12 @@ func (r *Resolver) UpgradeShoot(ctx context.Context, runtimeID string, input gql
 		return nil, err
 	}
 
+	err = authorizeUrgent(ctx, input.Urgent)
+	if err != nil {
+		log.Errorf("Failed to upgrade Gardener Shoot cluster specification for Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
 	status, err := r.provisioning.UpgradeGardenerShoot(runtimeID, input)
 	if err != nil {
 		log.Errorf("Failed to upgrade Gardener Shoot cluster specification for Runtime %s: %s", runtimeID, err)
@@ -193,6 +303,134 @@ func (r *Resolver) UpgradeShoot(ctx context.Context, runtimeID string, input gql
 	return status, nil
 }
 
+func (r *Resolver) HibernateRuntime(ctx context.Context, runtimeID string) (*gqlschema.OperationStatus, error) {
+	log.Infof("Requested hibernation of Runtime %s.", runtimeID)
+
+	_, err := r.getAndValidateTenant(ctx, runtimeID)
+	if err != nil {
+		log.Errorf("Failed to hibernate Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.HibernateRuntime(runtimeID)
+	if err != nil {
+		log.Errorf("Failed to hibernate Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	log.Infof("Hibernation of Runtime %s started", runtimeID)
+
+	return status, nil
+}
+
+func (r *Resolver) WakeUpRuntime(ctx context.Context, runtimeID string) (*gqlschema.OperationStatus, error) {
+	log.Infof("Requested wake up of Runtime %s.", runtimeID)
+
+	_, err := r.getAndValidateTenant(ctx, runtimeID)
+	if err != nil {
+		log.Errorf("Failed to wake up Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	status, err := r.provisioning.WakeUpRuntime(runtimeID)
+	if err != nil {
+		log.Errorf("Failed to wake up Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	log.Infof("Wake up of Runtime %s started", runtimeID)
+
+	return status, nil
+}
+
+func (r *Resolver) OperationStatusChanged(ctx context.Context, runtimeID *string, operationID *string) (<-chan *gqlschema.OperationStatus, error) {
+	filter, err := operationEventsFilter(runtimeID, operationID)
+	if err != nil {
+		log.Errorf("Failed to subscribe to operation status changes: %s", err)
+		return nil, err
+	}
+
+	log.Infof("Requested to subscribe to status changes of Operation %q of Runtime %q.", filter.OperationID, filter.RuntimeID)
+
+	if filter.OperationID != "" {
+		_, err = r.getAndValidateTenantForOp(ctx, filter.OperationID)
+	} else {
+		_, err = r.getAndValidateTenant(ctx, filter.RuntimeID)
+	}
+	if err != nil {
+		log.Errorf("Failed to subscribe to operation status changes: %s", err)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	events := r.operationEvents.Subscribe(ctx, filter)
+
+	current, err := r.currentOperationStatus(filter)
+	if err != nil {
+		cancel()
+		log.Errorf("Failed to subscribe to operation status changes: %s", err)
+		return nil, err
+	}
+
+	statuses := make(chan *gqlschema.OperationStatus, 1)
+
+	go func() {
+		defer cancel()
+		defer close(statuses)
+
+		// Subscription to a single operation completes when the operation is finished
+		send := func(status *gqlschema.OperationStatus) bool {
+			select {
+			case statuses <- status:
+			case <-ctx.Done():
+				return false
+			}
+			return filter.OperationID == "" || status.State == gqlschema.OperationStateInProgress
+		}
+
+		if current != nil && !send(current) {
+			return
+		}
+
+		for operation := range events {
+			if !send(r.graphQLConverter.OperationStatusToGQLOperationStatus(operation)) {
+				return
+			}
+		}
+	}()
+
+	return statuses, nil
+}
+
+func (r *Resolver) currentOperationStatus(filter notifications.Filter) (*gqlschema.OperationStatus, apperrors.AppError) {
+	if filter.OperationID != "" {
+		return r.provisioning.RuntimeOperationStatus(filter.OperationID)
+	}
+
+	status, err := r.provisioning.RuntimeStatus(filter.RuntimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return status.LastOperationStatus, nil
+}
+
+func operationEventsFilter(runtimeID, operationID *string) (notifications.Filter, error) {
+	filter := notifications.Filter{}
+	if runtimeID != nil {
+		filter.RuntimeID = *runtimeID
+	}
+	if operationID != nil {
+		filter.OperationID = *operationID
+	}
+
+	if (filter.RuntimeID == "") == (filter.OperationID == "") {
+		return notifications.Filter{}, apperrors.BadRequest("either runtimeID or operationID has to be provided")
+	}
+
+	return filter, nil
+}
+
 func (r *Resolver) getAndValidateTenant(ctx context.Context, runtimeID string) (string, error) {
 	tenant, err := getTenant(ctx)
 	if err != nil {
@@ -221,19 +459,108 @@ func (r *Resolver) getAndValidateTenantForOp(ctx context.Context, operationID st
 	return tenant, nil
 }
 
+// getTenant returns the tenant from the tenant header if the authenticated principal may access it.
+// If the header is not set the only tenant of the principal is used.
 func getTenant(ctx context.Context) (string, apperrors.AppError) {
 	tenant, ok := ctx.Value(middlewares.Tenant).(string)
 	if !ok || tenant == "" {
-		return "", apperrors.BadRequest("tenant header is empty")
+		principal, authenticated := middlewares.PrincipalFromContext(ctx)
+		if !authenticated || principal.AllTenants || len(principal.Tenants) != 1 {
+			return "", apperrors.BadRequest("tenant header is empty")
+		}
+		return principal.Tenants[0], nil
+	}
+
+	err := authorizeTenant(ctx, tenant)
+	if err != nil {
+		return "", err
 	}
 
 	return tenant, nil
 }
 
-func getSubAccount(ctx context.Context) string {
+// getListTenant returns the tenant to which listed resources are restricted.
+// The tenant is taken from the tenant header or from the filter. Only principals which may access all tenants
+// can list resources of all tenants, the others always get resources of a single tenant.
+func getListTenant(ctx context.Context, filterTenant *string) (*string, apperrors.AppError) {
+	tenant, ok := ctx.Value(middlewares.Tenant).(string)
+	if !ok || tenant == "" {
+		return getListTenantOfPrincipal(ctx, filterTenant)
+	}
+
+	if filterTenant != nil && *filterTenant != tenant {
+		return nil, apperrors.BadRequest("tenant from the filter does not match the tenant header")
+	}
+
+	err := authorizeTenant(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenant, nil
+}
+
+func getListTenantOfPrincipal(ctx context.Context, filterTenant *string) (*string, apperrors.AppError) {
+	principal, authenticated := middlewares.PrincipalFromContext(ctx)
+	if authenticated && principal.AllTenants {
+		return filterTenant, nil
+	}
+
+	if filterTenant != nil {
+		err := authorizeTenant(ctx, *filterTenant)
+		if err != nil {
+			return nil, err
+		}
+		return filterTenant, nil
+	}
+
+	if !authenticated || len(principal.Tenants) != 1 {
+		return nil, apperrors.BadRequest("tenant has to be provided to list resources")
+	}
+
+	return &principal.Tenants[0], nil
+}
+
+// authorizeTenant checks that the authenticated principal may access resources of the tenant, all tenants are accessible if the authentication is disabled
+func authorizeTenant(ctx context.Context, tenant string) apperrors.AppError {
+	principal, authenticated := middlewares.PrincipalFromContext(ctx)
+	if !authenticated {
+		return nil
+	}
+
+	if !principal.CanAccess(tenant) {
+		return apperrors.Forbidden("%s is not allowed to access resources of tenant %s", principal.Name, tenant)
+	}
+
+	return nil
+}
+
+// authorizeUrgent checks that the authenticated principal may request urgent processing of the operation,
+// all callers may request it if the authentication is disabled
+func authorizeUrgent(ctx context.Context, urgent *bool) apperrors.AppError {
+	if !util.UnwrapBoolOrDefault(urgent, false) {
+		return nil
+	}
+
+	principal, authenticated := middlewares.PrincipalFromContext(ctx)
+	if authenticated && !principal.CanRequestUrgent() {
+		return apperrors.Forbidden("%s is not allowed to request urgent operations", principal.Name)
+	}
+
+	return nil
+}
+
+// getSubAccount returns the sub-account from the sub-account header, the authenticated principal must be allowed to access it
+func getSubAccount(ctx context.Context) (string, apperrors.AppError) {
 	subAccount, ok := ctx.Value(middlewares.SubAccountID).(string)
-	if !ok {
-		return ""
+	if !ok || subAccount == "" {
+		return "", nil
 	}
-	return subAccount
+
+	principal, authenticated := middlewares.PrincipalFromContext(ctx)
+	if authenticated && !principal.CanAccessSubAccount(subAccount) {
+		return "", apperrors.Forbidden("%s is not allowed to access resources of sub-account %s", principal.Name, subAccount)
+	}
+
+	return subAccount, nil
 }
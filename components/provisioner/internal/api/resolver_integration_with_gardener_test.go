@@ -17,6 +17,7 @@ import (
 	v1alpha12 "github.com/kyma-project/kyma/components/compass-runtime-agent/pkg/apis/compass/v1alpha1"
 	"github.com/kyma-project/kyma/components/compass-runtime-agent/pkg/client/clientset/versioned/typed/compass/v1alpha1"
 
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/notifications"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
@@ -150,10 +151,13 @@ func TestProvisioning_ProvisionRuntimeWithDatabase(t *testing.T) {
 
 	shootInterface := gardener_fake.NewFakeShootsInterface(t, cfg)
 	secretsInterface := setupSecretsClient(t, cfg)
-	dbsFactory := dbsession.NewFactory(connection)
+	dbsFactory := dbsession.NewFactory(connection, nil, nil)
 
 	queueCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	operationEvents := notifications.NewBroker()
+	queueConfig := queue.Config{Workers: 5, PriorityWorkers: 1}
+	leaser := queue.NewOperationLeaser(dbsFactory.NewWriteSession(), "provisioner-test", time.Minute)
 	provisioningQueue := queue.CreateProvisioningQueue(
 		testProvisioningTimeouts(),
 		dbsFactory,
@@ -164,18 +168,33 @@ func TestProvisioning_ProvisionRuntimeWithDatabase(t *testing.T) {
 		shootInterface,
 		secretsInterface,
 		testOperatorRoleBinding(),
-		mockK8sClientProvider)
+		mockK8sClientProvider,
+		operationEvents,
+		queueConfig,
+		nil,
+		leaser)
 	provisioningQueue.Run(queueCtx.Done())
 
-	deprovisioningQueue := queue.CreateDeprovisioningQueue(testDeprovisioningTimeouts(), dbsFactory, installationServiceMock, directorServiceMock, shootInterface, 1*time.Second)
+	deprovisioningQueue := queue.CreateDeprovisioningQueue(testDeprovisioningTimeouts(), dbsFactory, installationServiceMock, directorServiceMock, shootInterface, 1*time.Second, operationEvents, queueConfig, nil, leaser)
 	deprovisioningQueue.Run(queueCtx.Done())
 
-	upgradeQueue := queue.CreateUpgradeQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, installationServiceMock)
+	rollbackQueue := queue.CreateRollbackQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, installationServiceMock, operationEvents, queueConfig, nil, leaser)
+	rollbackQueue.Run(queueCtx.Done())
+
+	rollbackStarter := queue.NewRollbackStarter(dbsFactory, uuid.NewUUIDGenerator(), rollbackQueue)
+
+	upgradeQueue := queue.CreateUpgradeQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, installationServiceMock, operationEvents, queueConfig, nil, leaser, nil)
 	upgradeQueue.Run(queueCtx.Done())
 
-	shootUpgradeQueue := queue.CreateShootUpgradeQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, shootInterface)
+	shootUpgradeQueue := queue.CreateShootUpgradeQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, shootInterface, operationEvents, queueConfig, nil, leaser)
 	shootUpgradeQueue.Run(queueCtx.Done())
 
+	hibernationQueue := queue.CreateHibernationQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, shootInterface, operationEvents, queueConfig, nil, leaser)
+	hibernationQueue.Run(queueCtx.Done())
+
+	wakeUpQueue := queue.CreateWakeUpQueue(testProvisioningTimeouts(), dbsFactory, directorServiceMock, shootInterface, operationEvents, queueConfig, nil, leaser)
+	wakeUpQueue.Run(queueCtx.Done())
+
 	controler, err := gardener.NewShootController(mgr, dbsFactory, auditLogsConfigPath)
 	require.NoError(t, err)
 
@@ -223,11 +242,11 @@ func TestProvisioning_ProvisionRuntimeWithDatabase(t *testing.T) {
 			inputConverter := provisioning.NewInputConverter(uuidGenerator, provider, "Project", defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate, forceAllowPrivilegedContainers)
 			graphQLConverter := provisioning.NewGraphQLConverter()
 
-			provisioningService := provisioning.NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, dbsFactory, provisioner, uuidGenerator, provisioningQueue, deprovisioningQueue, upgradeQueue, shootUpgradeQueue)
+			provisioningService := provisioning.NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, dbsFactory, provisioner, uuidGenerator, provisioningQueue, deprovisioningQueue, upgradeQueue, shootUpgradeQueue, hibernationQueue, wakeUpQueue, rollbackStarter)
 
 			validator := api.NewValidator(dbsFactory.NewReadSession())
 
-			resolver := api.NewResolver(provisioningService, validator)
+			resolver := api.NewResolver(provisioningService, validator, operationEvents)
 
 			err = insertDummyReleaseIfNotExist(releaseRepository, uuidGenerator.New(), kymaVersion)
 			require.NoError(t, err)
@@ -328,6 +347,24 @@ func testProvisionRuntime(t *testing.T, ctx context.Context, resolver *api.Resol
 	require.NotNil(t, runtimeStatusProvisioned)
 	assert.Equal(t, fixOperationStatusProvisioned(provisionRuntime.RuntimeID, provisionRuntime.ID), runtimeStatusProvisioned.LastOperationStatus)
 	assert.Equal(t, fixKymaGraphQLConfig(), runtimeStatusProvisioned.RuntimeConfiguration.KymaConfig)
+
+	// when listing Runtimes and operations of the tenant
+	runtimesPage, err := resolver.Runtimes(ctx, &gqlschema.RuntimesFilter{SubAccountID: util.StringPtr(subAccountId)}, nil, nil)
+	require.NoError(t, err)
+	operationsPage, err := resolver.Operations(ctx, &gqlschema.OperationsFilter{RuntimeID: provisionRuntime.RuntimeID}, nil, nil)
+	require.NoError(t, err)
+
+	// then
+	var listedRuntime *gqlschema.Runtime
+	for _, runtime := range runtimesPage.Data {
+		if runtime.ID == runtimeID {
+			listedRuntime = runtime
+		}
+	}
+	require.NotNil(t, listedRuntime)
+	assert.Equal(t, fixOperationStatusProvisioned(provisionRuntime.RuntimeID, provisionRuntime.ID), listedRuntime.LastOperationStatus)
+	require.Len(t, operationsPage.Data, 1)
+	assert.Equal(t, provisionRuntime.ID, operationsPage.Data[0].ID)
 }
 
 func testUpgradeRuntimeAndRollback(t *testing.T, ctx context.Context, resolver *api.Resolver, dbsFactory dbsession.Factory, runtimeID string) {
@@ -487,6 +524,7 @@ func testProvisioningTimeouts() queue.ProvisioningTimeouts {
 		Upgrade:                5 * time.Minute,
 		ShootUpgrade:           5 * time.Minute,
 		ShootRefresh:           5 * time.Minute,
+		ShootHibernation:       5 * time.Minute,
 		AgentConfiguration:     5 * time.Minute,
 		AgentConnection:        5 * time.Minute,
 	}
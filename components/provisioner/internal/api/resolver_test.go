@@ -2,6 +2,11 @@ package api_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
@@ -10,12 +15,15 @@ import (
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/api/middlewares"
 	validatorMocks "github.com/kyma-project/control-plane/components/provisioner/internal/api/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/notifications"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/mocks"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,7 +63,7 @@ func TestResolver_ProvisionRuntime(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		kymaConfig := &gqlschema.KymaConfigInput{
 			Version: "1.5",
@@ -103,7 +111,7 @@ func TestResolver_ProvisionRuntime(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		kymaConfig := &gqlschema.KymaConfigInput{
 			Version: "1.5",
@@ -125,7 +133,7 @@ func TestResolver_ProvisionRuntime(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		kymaConfig := &gqlschema.KymaConfigInput{
 			Version: "1.5",
@@ -155,7 +163,7 @@ func TestResolver_ProvisionRuntime(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		kymaConfig := &gqlschema.KymaConfigInput{
 			Version: "1.5",
@@ -184,6 +192,145 @@ func TestResolver_ProvisionRuntime(t *testing.T) {
 		require.Error(t, err)
 		assert.Nil(t, status)
 	})
+
+	t.Run("Should pass sub-account which principal may access", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		config := gqlschema.ProvisionRuntimeInput{RuntimeInput: runtimeInput, ClusterConfig: clusterConfig, KymaConfig: &gqlschema.KymaConfigInput{Version: "1.5"}}
+		operation := &gqlschema.OperationStatus{ID: util.StringPtr(operationID), RuntimeID: util.StringPtr(runtimeID)}
+
+		provisioningService.On("ProvisionRuntime", config, tenant, "sub-account").Return(operation, nil)
+		validator.On("ValidateProvisioningInput", config).Return(nil)
+
+		subAccountCtx := context.WithValue(ctx, middlewares.SubAccountID, "sub-account")
+		authenticatedCtx := middlewares.WithPrincipal(subAccountCtx, middlewares.Principal{Name: "caller", Tenants: []string{tenant}, SubAccounts: []string{"sub-account"}})
+
+		//when
+		status, err := provisioner.ProvisionRuntime(authenticatedCtx, config)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, operationID, *status.ID)
+	})
+
+	t.Run("Should pass sub-account of principal authenticated with client certificate which may access all tenants", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		config := gqlschema.ProvisionRuntimeInput{RuntimeInput: runtimeInput, ClusterConfig: clusterConfig, KymaConfig: &gqlschema.KymaConfigInput{Version: "1.5"}}
+		operation := &gqlschema.OperationStatus{ID: util.StringPtr(operationID), RuntimeID: util.StringPtr(runtimeID)}
+
+		provisioningService.On("ProvisionRuntime", config, tenant, "sub-account").Return(operation, nil)
+		validator.On("ValidateProvisioningInput", config).Return(nil)
+
+		principal := certificatePrincipal(t, pkix.Name{CommonName: "broker"}, []string{"broker"})
+
+		subAccountCtx := context.WithValue(ctx, middlewares.SubAccountID, "sub-account")
+		authenticatedCtx := middlewares.WithPrincipal(subAccountCtx, principal)
+
+		//when
+		status, err := provisioner.ProvisionRuntime(authenticatedCtx, config)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, operationID, *status.ID)
+	})
+
+	t.Run("Should return error when principal authenticated with client certificate of tenant passes sub-account", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		config := gqlschema.ProvisionRuntimeInput{RuntimeInput: runtimeInput, ClusterConfig: clusterConfig, KymaConfig: &gqlschema.KymaConfigInput{Version: "1.5"}}
+
+		validator.On("ValidateProvisioningInput", config).Return(nil)
+
+		principal := certificatePrincipal(t, pkix.Name{CommonName: "tenant-client", OrganizationalUnit: []string{tenant}}, nil)
+
+		subAccountCtx := context.WithValue(ctx, middlewares.SubAccountID, "sub-account")
+		authenticatedCtx := middlewares.WithPrincipal(subAccountCtx, principal)
+
+		//when
+		status, err := provisioner.ProvisionRuntime(authenticatedCtx, config)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
+		assert.Nil(t, status)
+		provisioningService.AssertNotCalled(t, "ProvisionRuntime", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Should return error when principal is not allowed to access sub-account from the header", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		config := gqlschema.ProvisionRuntimeInput{RuntimeInput: runtimeInput, ClusterConfig: clusterConfig, KymaConfig: &gqlschema.KymaConfigInput{Version: "1.5"}}
+
+		validator.On("ValidateProvisioningInput", config).Return(nil)
+
+		subAccountCtx := context.WithValue(ctx, middlewares.SubAccountID, "other-sub-account")
+		authenticatedCtx := middlewares.WithPrincipal(subAccountCtx, middlewares.Principal{Name: "caller", Tenants: []string{tenant}, SubAccounts: []string{"sub-account"}})
+
+		//when
+		status, err := provisioner.ProvisionRuntime(authenticatedCtx, config)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
+		assert.Nil(t, status)
+	})
+
+	t.Run("Should start urgent provisioning when principal has the urgent scope", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		config := gqlschema.ProvisionRuntimeInput{RuntimeInput: runtimeInput, ClusterConfig: clusterConfig, KymaConfig: &gqlschema.KymaConfigInput{Version: "1.5"}, Urgent: util.BoolPtr(true)}
+		operation := &gqlschema.OperationStatus{ID: util.StringPtr(operationID), RuntimeID: util.StringPtr(runtimeID)}
+
+		provisioningService.On("ProvisionRuntime", config, tenant, "").Return(operation, nil)
+		validator.On("ValidateProvisioningInput", config).Return(nil)
+
+		authenticatedCtx := middlewares.WithPrincipal(ctx, middlewares.Principal{Name: "caller", Tenants: []string{tenant}, Urgent: true})
+
+		//when
+		status, err := provisioner.ProvisionRuntime(authenticatedCtx, config)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, operationID, *status.ID)
+	})
+
+	t.Run("Should return error when principal is not allowed to request urgent provisioning", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		config := gqlschema.ProvisionRuntimeInput{RuntimeInput: runtimeInput, ClusterConfig: clusterConfig, KymaConfig: &gqlschema.KymaConfigInput{Version: "1.5"}, Urgent: util.BoolPtr(true)}
+
+		validator.On("ValidateProvisioningInput", config).Return(nil)
+
+		authenticatedCtx := middlewares.WithPrincipal(ctx, middlewares.Principal{Name: "caller", Tenants: []string{tenant}})
+
+		//when
+		status, err := provisioner.ProvisionRuntime(authenticatedCtx, config)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
+		assert.Nil(t, status)
+		provisioningService.AssertNotCalled(t, "ProvisionRuntime", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestResolver_DeprovisionRuntime(t *testing.T) {
@@ -193,7 +340,7 @@ func TestResolver_DeprovisionRuntime(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		expectedID := "ec781980-0533-4098-aab7-96b535569732"
 
@@ -212,7 +359,7 @@ func TestResolver_DeprovisionRuntime(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		provisioningService.On("DeprovisionRuntime", runtimeID, tenant).Return("", apperrors.Internal("Deprovisioning fails because reasons"))
 		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
@@ -230,7 +377,7 @@ func TestResolver_DeprovisionRuntime(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		expectedID := "ec781980-0533-4098-aab7-96b535569732"
 
@@ -250,7 +397,7 @@ func TestResolver_DeprovisionRuntime(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		expectedID := "ec781980-0533-4098-aab7-96b535569732"
 
@@ -291,7 +438,7 @@ func TestResolver_UpgradeRuntime(t *testing.T) {
 		validator.On("ValidateUpgradeInput", upgradeInput).Return(nil)
 		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		status, err := resolver.UpgradeRuntime(ctx, runtimeID, upgradeInput)
@@ -313,7 +460,7 @@ func TestResolver_UpgradeRuntime(t *testing.T) {
 		validator.On("ValidateUpgradeInput", upgradeInput).Return(nil)
 		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		_, err := resolver.UpgradeRuntime(ctx, runtimeID, upgradeInput)
@@ -331,7 +478,7 @@ func TestResolver_UpgradeRuntime(t *testing.T) {
 		validator.On("ValidateUpgradeInput", upgradeInput).Return(nil)
 		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("error"))
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		_, err := resolver.UpgradeRuntime(ctx, runtimeID, upgradeInput)
@@ -349,7 +496,7 @@ func TestResolver_UpgradeRuntime(t *testing.T) {
 		validator.On("ValidateUpgradeInput", upgradeInput).Return(apperrors.BadRequest("error"))
 		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		_, err := resolver.UpgradeRuntime(ctx, runtimeID, upgradeInput)
@@ -358,6 +505,91 @@ func TestResolver_UpgradeRuntime(t *testing.T) {
 		require.Error(t, err)
 		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
 	})
+
+	t.Run("Should return error when principal is not allowed to request urgent upgrade", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+
+		urgentInput := upgradeInput
+		urgentInput.Urgent = util.BoolPtr(true)
+
+		validator.On("ValidateUpgradeInput", urgentInput).Return(nil)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+		authenticatedCtx := middlewares.WithPrincipal(ctx, middlewares.Principal{Name: "caller", Tenants: []string{tenant}})
+
+		//when
+		_, err := resolver.UpgradeRuntime(authenticatedCtx, runtimeID, urgentInput)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
+		provisioningService.AssertNotCalled(t, "UpgradeRuntime", mock.Anything, mock.Anything)
+	})
+}
+
+func TestResolver_RollbackRuntimeUpgrade(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+
+	operation := gqlschema.OperationStatus{
+		ID:        util.StringPtr(operationID),
+		Operation: gqlschema.OperationTypeRollback,
+		State:     gqlschema.OperationStateInProgress,
+		RuntimeID: util.StringPtr(runtimeID),
+	}
+
+	t.Run("Should start rollback and return operation status", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+
+		provisioningService.On("RollbackRuntimeUpgrade", runtimeID).Return(&operation, nil)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		//when
+		status, err := resolver.RollbackRuntimeUpgrade(ctx, runtimeID)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, &operation, status)
+	})
+
+	t.Run("Should return error when failed to start rollback", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+
+		provisioningService.On("RollbackRuntimeUpgrade", runtimeID).Return(nil, apperrors.BadRequest("error"))
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		//when
+		_, err := resolver.RollbackRuntimeUpgrade(ctx, runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+	})
+
+	t.Run("Should return error when failed to validate tenant", func(t *testing.T) {
+		//given
+		validator := &validatorMocks.Validator{}
+		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("error"))
+
+		resolver := api.NewResolver(nil, validator, notifications.NewBroker())
+
+		//when
+		_, err := resolver.RollbackRuntimeUpgrade(ctx, runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+	})
 }
 
 func TestResolver_RollBackUpgradeOperation(t *testing.T) {
@@ -373,7 +605,7 @@ func TestResolver_RollBackUpgradeOperation(t *testing.T) {
 		provisioningService.On("RollBackLastUpgrade", runtimeID).Return(&runtimeStatus, nil)
 		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		status, err := resolver.RollBackUpgradeOperation(ctx, runtimeID)
@@ -391,7 +623,7 @@ func TestResolver_RollBackUpgradeOperation(t *testing.T) {
 		provisioningService.On("RollBackLastUpgrade", runtimeID).Return(nil, apperrors.Internal("error"))
 		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		_, err := resolver.RollBackUpgradeOperation(ctx, runtimeID)
@@ -406,7 +638,7 @@ func TestResolver_RollBackUpgradeOperation(t *testing.T) {
 		validator := &validatorMocks.Validator{}
 		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("error"))
 
-		resolver := api.NewResolver(nil, validator)
+		resolver := api.NewResolver(nil, validator, notifications.NewBroker())
 
 		//when
 		_, err := resolver.RollBackUpgradeOperation(ctx, runtimeID)
@@ -439,7 +671,7 @@ func TestResolver_UpgradeShoot(t *testing.T) {
 		validator.On("ValidateUpgradeShootInput", upgradeShootInput).Return(nil)
 		provisioningService.On("UpgradeGardenerShoot", runtimeID, upgradeShootInput).Return(operation, nil)
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		status, err := resolver.UpgradeShoot(ctx, runtimeID, upgradeShootInput)
@@ -459,7 +691,7 @@ func TestResolver_UpgradeShoot(t *testing.T) {
 		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("error"))
 		validator.On("ValidateUpgradeShootInput", upgradeShootInput).Return(nil)
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		_, err := resolver.UpgradeShoot(ctx, runtimeID, upgradeShootInput)
@@ -476,7 +708,7 @@ func TestResolver_UpgradeShoot(t *testing.T) {
 		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
 		validator.On("ValidateUpgradeShootInput", upgradeShootInput).Return(apperrors.BadRequest("error"))
 
-		resolver := api.NewResolver(provisioningService, validator)
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
 		_, err := resolver.UpgradeShoot(ctx, runtimeID, upgradeShootInput)
@@ -485,156 +717,726 @@ func TestResolver_UpgradeShoot(t *testing.T) {
 		require.Error(t, err)
 		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
 	})
-}
-
-func TestResolver_RuntimeStatus(t *testing.T) {
-	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
-	runtimeID := "1100bb59-9c40-4ebb-b846-7477c4dc5bbd"
-
-	t.Run("Should return operation status", func(t *testing.T) {
+	t.Run("Should start urgent shoot upgrade when principal may access all tenants", func(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
-
-		operationID := "acc5040c-3bb6-47b8-8651-07f6950bd0a7"
-		message := "some message"
 
-		status := &gqlschema.RuntimeStatus{
-			LastOperationStatus: &gqlschema.OperationStatus{
-				ID:        &operationID,
-				Operation: gqlschema.OperationTypeProvision,
-				State:     gqlschema.OperationStateInProgress,
-				RuntimeID: &runtimeID,
-				Message:   &message,
-			},
-			RuntimeConfiguration:    &gqlschema.RuntimeConfig{},
-			RuntimeConnectionStatus: &gqlschema.RuntimeConnectionStatus{},
-		}
+		urgentInput := upgradeShootInput
+		urgentInput.Urgent = util.BoolPtr(true)
+		operation := &gqlschema.OperationStatus{ID: util.StringPtr(operationID), RuntimeID: util.StringPtr(runtimeID)}
 
-		provisioningService.On("RuntimeStatus", runtimeID).Return(status, nil)
 		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+		validator.On("ValidateUpgradeShootInput", urgentInput).Return(nil)
+		provisioningService.On("UpgradeGardenerShoot", runtimeID, urgentInput).Return(operation, nil)
+
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+		authenticatedCtx := middlewares.WithPrincipal(ctx, middlewares.Principal{Name: "broker", AllTenants: true})
 
 		//when
-		runtimeStatus, err := provisioner.RuntimeStatus(ctx, runtimeID)
+		status, err := resolver.UpgradeShoot(authenticatedCtx, runtimeID, urgentInput)
 
 		//then
 		require.NoError(t, err)
-		assert.Equal(t, status, runtimeStatus)
+		assert.Equal(t, operation, status)
 	})
-
-	t.Run("Should return error when runtime status fails", func(t *testing.T) {
+	t.Run("Should return error when principal is not allowed to request urgent shoot upgrade", func(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
-
-		provisioningService.On("RuntimeStatus", runtimeID).Return(nil, apperrors.Internal("Runtime status fails"))
-		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
-
-		//when
-		status, err := provisioner.RuntimeStatus(ctx, runtimeID)
 
-		//then
-		require.Error(t, err)
-		util.CheckErrorType(t, err, apperrors.CodeInternal)
-		require.Empty(t, status)
-	})
+		urgentInput := upgradeShootInput
+		urgentInput.Urgent = util.BoolPtr(true)
 
-	t.Run("Should return error when tenant header does not match tenant provided during provisioning", func(t *testing.T) {
-		//given
-		provisioningService := &mocks.Service{}
-		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+		validator.On("ValidateUpgradeShootInput", urgentInput).Return(nil)
 
-		provisioningService.On("RuntimeStatus", runtimeID).Return(nil, nil)
-		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("Bad error"))
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+		authenticatedCtx := middlewares.WithPrincipal(ctx, middlewares.Principal{Name: "caller", Tenants: []string{tenant}})
 
 		//when
-		status, err := provisioner.RuntimeStatus(ctx, runtimeID)
+		_, err := resolver.UpgradeShoot(authenticatedCtx, runtimeID, urgentInput)
 
 		//then
 		require.Error(t, err)
-		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
-		require.Empty(t, status)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
+		provisioningService.AssertNotCalled(t, "UpgradeGardenerShoot", mock.Anything, mock.Anything)
 	})
 }
 
-func TestResolver_RuntimeOperationStatus(t *testing.T) {
+func TestResolver_HibernateRuntime(t *testing.T) {
 	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
-	runtimeID := "1100bb59-9c40-4ebb-b846-7477c4dc5bbd"
 
-	t.Run("Should return operation status", func(t *testing.T) {
+	t.Run("Should start hibernation and return operation status", func(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
 
-		operationID := "acc5040c-3bb6-47b8-8651-07f6950bd0a7"
-		message := "some message"
-
-		operationStatus := &gqlschema.OperationStatus{
-			ID:        &operationID,
-			Operation: gqlschema.OperationTypeProvision,
+		operation := &gqlschema.OperationStatus{
+			ID:        util.StringPtr(operationID),
+			Operation: gqlschema.OperationTypeHibernate,
 			State:     gqlschema.OperationStateInProgress,
-			RuntimeID: &runtimeID,
-			Message:   &message,
+			RuntimeID: util.StringPtr(runtimeID),
 		}
 
-		provisioningService.On("RuntimeOperationStatus", operationID).Return(operationStatus, nil)
-		validator.On("ValidateTenantForOperation", operationID, tenant).Return(nil)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+		provisioningService.On("HibernateRuntime", runtimeID).Return(operation, nil)
+
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
 		//when
-		status, err := provisioner.RuntimeOperationStatus(ctx, operationID)
+		status, err := resolver.HibernateRuntime(ctx, runtimeID)
 
 		//then
 		require.NoError(t, err)
-		assert.Equal(t, operationStatus, status)
+		assert.Equal(t, operation, status)
 	})
 
-	t.Run("Should return error when tenant validation fails", func(t *testing.T) {
+	t.Run("Should return error when failed to start hibernation", func(t *testing.T) {
 		//given
 		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		provisioner := api.NewResolver(provisioningService, validator)
 
-		operationID := "acc5040c-3bb6-47b8-8651-07f6950bd0a7"
-		message := "some message"
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+		provisioningService.On("HibernateRuntime", runtimeID).Return(nil, apperrors.BadRequest("error"))
 
-		operationStatus := &gqlschema.OperationStatus{
-			ID:        &operationID,
-			Operation: gqlschema.OperationTypeProvision,
-			State:     gqlschema.OperationStateInProgress,
-			RuntimeID: &runtimeID,
-			Message:   &message,
-		}
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
 
-		provisioningService.On("RuntimeOperationStatus", operationID).Return(operationStatus, nil)
-		validator.On("ValidateTenantForOperation", operationID, tenant).Return(apperrors.BadRequest("oh no"))
 		//when
-		status, err := provisioner.RuntimeOperationStatus(ctx, operationID)
+		_, err := resolver.HibernateRuntime(ctx, runtimeID)
 
 		//then
 		require.Error(t, err)
 		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
-		require.Empty(t, status)
 	})
 
-	t.Run("Should return error when Runtime Operation fails", func(t *testing.T) {
+	t.Run("Should return error when failed to validate tenant", func(t *testing.T) {
 		//given
-		provisioningService := &mocks.Service{}
 		validator := &validatorMocks.Validator{}
-		validator.On("ValidateTenantForOperation", operationID, tenant).Return(nil)
-		provisioner := api.NewResolver(provisioningService, validator)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("error"))
 
-		provisioningService.On("RuntimeOperationStatus", operationID).Return(nil, apperrors.Internal("Some error"))
+		resolver := api.NewResolver(nil, validator, notifications.NewBroker())
 
 		//when
-		status, err := provisioner.RuntimeOperationStatus(ctx, operationID)
+		_, err := resolver.HibernateRuntime(ctx, runtimeID)
 
 		//then
 		require.Error(t, err)
-		util.CheckErrorType(t, err, apperrors.CodeInternal)
-		require.Empty(t, status)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
 	})
 }
+
+func TestResolver_WakeUpRuntime(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+
+	t.Run("Should start wake up and return operation status", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+
+		operation := &gqlschema.OperationStatus{
+			ID:        util.StringPtr(operationID),
+			Operation: gqlschema.OperationTypeWakeUp,
+			State:     gqlschema.OperationStateInProgress,
+			RuntimeID: util.StringPtr(runtimeID),
+		}
+
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+		provisioningService.On("WakeUpRuntime", runtimeID).Return(operation, nil)
+
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		//when
+		status, err := resolver.WakeUpRuntime(ctx, runtimeID)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, operation, status)
+	})
+
+	t.Run("Should return error when failed to validate tenant", func(t *testing.T) {
+		//given
+		validator := &validatorMocks.Validator{}
+		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("error"))
+
+		resolver := api.NewResolver(nil, validator, notifications.NewBroker())
+
+		//when
+		_, err := resolver.WakeUpRuntime(ctx, runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+	})
+}
+
+func TestResolver_RuntimeStatus(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+	runtimeID := "1100bb59-9c40-4ebb-b846-7477c4dc5bbd"
+
+	t.Run("Should return operation status", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		operationID := "acc5040c-3bb6-47b8-8651-07f6950bd0a7"
+		message := "some message"
+
+		status := &gqlschema.RuntimeStatus{
+			LastOperationStatus: &gqlschema.OperationStatus{
+				ID:        &operationID,
+				Operation: gqlschema.OperationTypeProvision,
+				State:     gqlschema.OperationStateInProgress,
+				RuntimeID: &runtimeID,
+				Message:   &message,
+			},
+			RuntimeConfiguration:    &gqlschema.RuntimeConfig{},
+			RuntimeConnectionStatus: &gqlschema.RuntimeConnectionStatus{},
+		}
+
+		provisioningService.On("RuntimeStatus", runtimeID).Return(status, nil)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		//when
+		runtimeStatus, err := provisioner.RuntimeStatus(ctx, runtimeID)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, status, runtimeStatus)
+	})
+
+	t.Run("Should return error when runtime status fails", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		provisioningService.On("RuntimeStatus", runtimeID).Return(nil, apperrors.Internal("Runtime status fails"))
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		//when
+		status, err := provisioner.RuntimeStatus(ctx, runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeInternal)
+		require.Empty(t, status)
+	})
+
+	t.Run("Should return error when tenant header does not match tenant provided during provisioning", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		provisioningService.On("RuntimeStatus", runtimeID).Return(nil, nil)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("Bad error"))
+
+		//when
+		status, err := provisioner.RuntimeStatus(ctx, runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		require.Empty(t, status)
+	})
+
+	t.Run("Should return error when principal is not allowed to access tenant from the header", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		authenticatedCtx := middlewares.WithPrincipal(ctx, middlewares.Principal{Name: "caller", Tenants: []string{"other-tenant"}})
+
+		//when
+		status, err := provisioner.RuntimeStatus(authenticatedCtx, runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
+		require.Empty(t, status)
+		provisioningService.AssertNotCalled(t, "RuntimeStatus")
+		validator.AssertNotCalled(t, "ValidateTenant")
+	})
+
+	t.Run("Should use the only tenant of the principal when tenant header is not passed", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		authenticatedCtx := middlewares.WithPrincipal(context.Background(), middlewares.Principal{Name: "caller", Tenants: []string{tenant}})
+		status := &gqlschema.RuntimeStatus{}
+
+		provisioningService.On("RuntimeStatus", runtimeID).Return(status, nil)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		//when
+		runtimeStatus, err := provisioner.RuntimeStatus(authenticatedCtx, runtimeID)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, status, runtimeStatus)
+		validator.AssertExpectations(t)
+	})
+}
+
+func TestResolver_RuntimeOperationStatus(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+	runtimeID := "1100bb59-9c40-4ebb-b846-7477c4dc5bbd"
+
+	t.Run("Should return operation status", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		operationID := "acc5040c-3bb6-47b8-8651-07f6950bd0a7"
+		message := "some message"
+
+		operationStatus := &gqlschema.OperationStatus{
+			ID:        &operationID,
+			Operation: gqlschema.OperationTypeProvision,
+			State:     gqlschema.OperationStateInProgress,
+			RuntimeID: &runtimeID,
+			Message:   &message,
+		}
+
+		provisioningService.On("RuntimeOperationStatus", operationID).Return(operationStatus, nil)
+		validator.On("ValidateTenantForOperation", operationID, tenant).Return(nil)
+
+		//when
+		status, err := provisioner.RuntimeOperationStatus(ctx, operationID)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, operationStatus, status)
+	})
+
+	t.Run("Should return error when tenant validation fails", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		operationID := "acc5040c-3bb6-47b8-8651-07f6950bd0a7"
+		message := "some message"
+
+		operationStatus := &gqlschema.OperationStatus{
+			ID:        &operationID,
+			Operation: gqlschema.OperationTypeProvision,
+			State:     gqlschema.OperationStateInProgress,
+			RuntimeID: &runtimeID,
+			Message:   &message,
+		}
+
+		provisioningService.On("RuntimeOperationStatus", operationID).Return(operationStatus, nil)
+		validator.On("ValidateTenantForOperation", operationID, tenant).Return(apperrors.BadRequest("oh no"))
+		//when
+		status, err := provisioner.RuntimeOperationStatus(ctx, operationID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		require.Empty(t, status)
+	})
+
+	t.Run("Should return error when Runtime Operation fails", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		validator.On("ValidateTenantForOperation", operationID, tenant).Return(nil)
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		provisioningService.On("RuntimeOperationStatus", operationID).Return(nil, apperrors.Internal("Some error"))
+
+		//when
+		status, err := provisioner.RuntimeOperationStatus(ctx, operationID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeInternal)
+		require.Empty(t, status)
+	})
+}
+
+func TestResolver_Runtimes(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+
+	page := &gqlschema.RuntimesPage{
+		Data:       []*gqlschema.Runtime{{ID: runtimeID, Tenant: tenant}},
+		PageInfo:   &gqlschema.PageInfo{EndCursor: util.StringPtr("cursor"), HasNextPage: true},
+		TotalCount: 2,
+	}
+
+	t.Run("Should list Runtimes of the tenant from the header", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		expectedFilter := &gqlschema.RuntimesFilter{Tenant: util.StringPtr(tenant), Region: util.StringPtr("europe")}
+		provisioningService.On("ListRuntimes", expectedFilter, util.IntPtr(1), (*string)(nil)).Return(page, nil)
+
+		//when
+		runtimes, err := provisioner.Runtimes(ctx, &gqlschema.RuntimesFilter{Region: util.StringPtr("europe")}, util.IntPtr(1), nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, page, runtimes)
+		provisioningService.AssertExpectations(t)
+	})
+
+	t.Run("Should list Runtimes using tenant from the filter when tenant header is not passed", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		filter := &gqlschema.RuntimesFilter{Tenant: util.StringPtr("other-tenant")}
+		provisioningService.On("ListRuntimes", filter, (*int)(nil), util.StringPtr("cursor")).Return(page, nil)
+
+		//when
+		runtimes, err := provisioner.Runtimes(context.Background(), filter, nil, util.StringPtr("cursor"))
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, page, runtimes)
+		provisioningService.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when tenant from the filter does not match tenant header", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		//when
+		runtimes, err := provisioner.Runtimes(ctx, &gqlschema.RuntimesFilter{Tenant: util.StringPtr("other-tenant")}, nil, nil)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		assert.Nil(t, runtimes)
+		provisioningService.AssertNotCalled(t, "ListRuntimes")
+	})
+
+	t.Run("Should return error when listing Runtimes fails", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		provisioningService.On("ListRuntimes", &gqlschema.RuntimesFilter{Tenant: util.StringPtr(tenant)}, (*int)(nil), (*string)(nil)).Return(nil, apperrors.Internal("oh no"))
+
+		//when
+		runtimes, err := provisioner.Runtimes(ctx, nil, nil, nil)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeInternal)
+		assert.Nil(t, runtimes)
+	})
+
+	t.Run("Should list Runtimes of the only tenant of the principal when tenant is not passed", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		authenticatedCtx := middlewares.WithPrincipal(context.Background(), middlewares.Principal{Name: "caller", Tenants: []string{tenant}})
+		provisioningService.On("ListRuntimes", &gqlschema.RuntimesFilter{Tenant: util.StringPtr(tenant)}, (*int)(nil), (*string)(nil)).Return(page, nil)
+
+		//when
+		runtimes, err := provisioner.Runtimes(authenticatedCtx, nil, nil, nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, page, runtimes)
+		provisioningService.AssertExpectations(t)
+	})
+
+	t.Run("Should list Runtimes of all tenants when principal may access all tenants", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		authenticatedCtx := middlewares.WithPrincipal(context.Background(), middlewares.Principal{Name: "operator", AllTenants: true})
+		provisioningService.On("ListRuntimes", &gqlschema.RuntimesFilter{}, (*int)(nil), (*string)(nil)).Return(page, nil)
+
+		//when
+		runtimes, err := provisioner.Runtimes(authenticatedCtx, &gqlschema.RuntimesFilter{}, nil, nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, page, runtimes)
+		provisioningService.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when principal is not allowed to access tenant from the filter", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		authenticatedCtx := middlewares.WithPrincipal(context.Background(), middlewares.Principal{Name: "caller", Tenants: []string{tenant}})
+
+		//when
+		runtimes, err := provisioner.Runtimes(authenticatedCtx, &gqlschema.RuntimesFilter{Tenant: util.StringPtr("other-tenant")}, nil, nil)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeForbidden)
+		assert.Nil(t, runtimes)
+		provisioningService.AssertNotCalled(t, "ListRuntimes")
+	})
+
+	t.Run("Should return error when principal with many tenants does not pass tenant", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		authenticatedCtx := middlewares.WithPrincipal(context.Background(), middlewares.Principal{Name: "caller", Tenants: []string{tenant, "other-tenant"}})
+
+		//when
+		runtimes, err := provisioner.Runtimes(authenticatedCtx, nil, nil, nil)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		assert.Nil(t, runtimes)
+		provisioningService.AssertNotCalled(t, "ListRuntimes")
+	})
+	t.Run("Should return error when tenant is not passed and caller is not authenticated", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		//when
+		runtimes, err := provisioner.Runtimes(context.Background(), &gqlschema.RuntimesFilter{}, nil, nil)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		assert.Nil(t, runtimes)
+		provisioningService.AssertNotCalled(t, "ListRuntimes")
+	})
+}
+
+func TestResolver_Operations(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+
+	t.Run("Should list operations of the tenant from the header", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		state := gqlschema.OperationStateFailed
+		page := &gqlschema.OperationsPage{
+			Data: []*gqlschema.OperationStatus{{
+				ID:        util.StringPtr(operationID),
+				Operation: gqlschema.OperationTypeUpgrade,
+				State:     state,
+				RuntimeID: util.StringPtr(runtimeID),
+			}},
+			PageInfo:   &gqlschema.PageInfo{EndCursor: util.StringPtr("cursor")},
+			TotalCount: 1,
+		}
+
+		expectedFilter := &gqlschema.OperationsFilter{Tenant: util.StringPtr(tenant), State: &state}
+		provisioningService.On("ListOperations", expectedFilter, (*int)(nil), (*string)(nil)).Return(page, nil)
+
+		//when
+		operations, err := provisioner.Operations(ctx, &gqlschema.OperationsFilter{State: &state}, nil, nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, page, operations)
+		provisioningService.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when tenant from the filter does not match tenant header", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		//when
+		operations, err := provisioner.Operations(ctx, &gqlschema.OperationsFilter{Tenant: util.StringPtr("other-tenant")}, nil, nil)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		assert.Nil(t, operations)
+		provisioningService.AssertNotCalled(t, "ListOperations")
+	})
+
+	t.Run("Should return error when listing operations fails", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		provisioningService.On("ListOperations", &gqlschema.OperationsFilter{Tenant: util.StringPtr(tenant)}, (*int)(nil), (*string)(nil)).Return(nil, apperrors.Internal("oh no"))
+
+		//when
+		operations, err := provisioner.Operations(ctx, nil, nil, nil)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeInternal)
+		assert.Nil(t, operations)
+	})
+	t.Run("Should return error when tenant is not passed and caller is not authenticated", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		//when
+		operations, err := provisioner.Operations(context.Background(), nil, nil, nil)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		assert.Nil(t, operations)
+		provisioningService.AssertNotCalled(t, "ListOperations")
+	})
+}
+
+func TestResolver_OperationStatusChanged(t *testing.T) {
+	runtimeID := "1100bb59-9c40-4ebb-b846-7477c4dc5bbd"
+	operationID := "acc5040c-3bb6-47b8-8651-07f6950bd0a7"
+	message := "Operation in progress. Stage StartingInstallation"
+
+	currentStatus := &gqlschema.OperationStatus{
+		ID:        &operationID,
+		Operation: gqlschema.OperationTypeProvision,
+		State:     gqlschema.OperationStateInProgress,
+		RuntimeID: &runtimeID,
+		Message:   &message,
+	}
+
+	t.Run("Should send current status and changes of the operation until it is finished", func(t *testing.T) {
+		//given
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), middlewares.Tenant, tenant))
+		defer cancel()
+
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		broker := notifications.NewBroker()
+		resolver := api.NewResolver(provisioningService, validator, broker)
+
+		provisioningService.On("RuntimeOperationStatus", operationID).Return(currentStatus, nil)
+		validator.On("ValidateTenantForOperation", operationID, tenant).Return(nil)
+
+		//when
+		statuses, err := resolver.OperationStatusChanged(ctx, nil, &operationID)
+		require.NoError(t, err)
+
+		//then
+		assert.Equal(t, currentStatus, <-statuses)
+
+		//when
+		broker.OperationChanged(model.Operation{ID: "other-operation", ClusterID: runtimeID, Type: model.Upgrade, State: model.InProgress})
+		broker.OperationChanged(model.Operation{ID: operationID, ClusterID: runtimeID, Type: model.Provision, State: model.Succeeded, Message: "Operation succeeded"})
+
+		//then
+		status := <-statuses
+		assert.Equal(t, operationID, *status.ID)
+		assert.Equal(t, gqlschema.OperationStateSucceeded, status.State)
+
+		_, open := <-statuses
+		assert.False(t, open)
+	})
+
+	t.Run("Should send last operation status and changes of all operations of the Runtime", func(t *testing.T) {
+		//given
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), middlewares.Tenant, tenant))
+
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		broker := notifications.NewBroker()
+		resolver := api.NewResolver(provisioningService, validator, broker)
+
+		provisioningService.On("RuntimeStatus", runtimeID).Return(&gqlschema.RuntimeStatus{LastOperationStatus: currentStatus}, nil)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		//when
+		statuses, err := resolver.OperationStatusChanged(ctx, &runtimeID, nil)
+		require.NoError(t, err)
+
+		//then
+		assert.Equal(t, currentStatus, <-statuses)
+
+		//when
+		broker.OperationChanged(model.Operation{ID: operationID, ClusterID: runtimeID, Type: model.Provision, State: model.Succeeded})
+		broker.OperationChanged(model.Operation{ID: "upgrade-operation", ClusterID: runtimeID, Type: model.Upgrade, State: model.InProgress})
+
+		//then
+		assert.Equal(t, gqlschema.OperationStateSucceeded, (<-statuses).State)
+		assert.Equal(t, gqlschema.OperationTypeUpgrade, (<-statuses).Operation)
+
+		//when
+		cancel()
+
+		//then
+		for range statuses {
+		}
+	})
+
+	t.Run("Should return error when neither or both IDs are provided", func(t *testing.T) {
+		//given
+		ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		//when
+		_, err := resolver.OperationStatusChanged(ctx, nil, nil)
+
+		//then
+		require.Error(t, err)
+
+		//when
+		_, err = resolver.OperationStatusChanged(ctx, &runtimeID, &operationID)
+
+		//then
+		require.Error(t, err)
+	})
+
+	t.Run("Should return error when tenant validation fails", func(t *testing.T) {
+		//given
+		ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		resolver := api.NewResolver(provisioningService, validator, notifications.NewBroker())
+
+		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("error"))
+
+		//when
+		_, err := resolver.OperationStatusChanged(ctx, &runtimeID, nil)
+
+		//then
+		require.Error(t, err)
+		provisioningService.AssertNotCalled(t, "RuntimeStatus", runtimeID)
+	})
+}
+
+func certificatePrincipal(t *testing.T, subject pkix.Name, allTenantsSubjects []string) middlewares.Principal {
+	request := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}}}
+
+	principal, err := middlewares.NewCertificateAuthenticator(allTenantsSubjects).Authenticate(request)
+	require.NoError(t, err)
+
+	return principal
+}
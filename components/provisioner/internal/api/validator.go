@@ -1,11 +1,15 @@
 package api
 
 import (
+	"net/url"
+
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	corev1 "k8s.io/api/core/v1"
 )
 
 const RuntimeAgent = "compass-runtime-agent"
@@ -78,6 +82,18 @@ func (v *validator) ValidateUpgradeShootInput(input gqlschema.UpgradeShootInput)
 		return apperrors.BadRequest("empty purpose provided")
 	}
 
+	if err := validateHibernationSchedules(config.HibernationSchedules); err != nil {
+		return err
+	}
+
+	if err := validateWorkerPools(config.WorkerPools); err != nil {
+		return err
+	}
+
+	if err := validateKubeAPIServerConfig(config.KubeAPIServerConfig); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -130,6 +146,18 @@ func (v *validator) validateClusterConfig(clusterConfig *gqlschema.ClusterConfig
 		return err
 	}
 
+	if err := validateHibernationSchedules(clusterConfig.GardenerConfig.HibernationSchedules); err != nil {
+		return err
+	}
+
+	if err := validateWorkerPools(clusterConfig.GardenerConfig.WorkerPools); err != nil {
+		return err
+	}
+
+	if err := validateKubeAPIServerConfig(clusterConfig.GardenerConfig.KubeAPIServerConfig); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -140,6 +168,86 @@ func (v *validator) validateMachineImage(gardenerConfig gqlschema.GardenerConfig
 	return nil
 }
 
+func validateHibernationSchedules(schedules []*gqlschema.HibernationScheduleInput) apperrors.AppError {
+	for _, schedule := range schedules {
+		if util.IsNilOrEmpty(schedule.Start) && util.IsNilOrEmpty(schedule.End) {
+			return apperrors.BadRequest("error: Hibernation schedule has to specify start or end")
+		}
+	}
+	return nil
+}
+
+var taintEffects = map[string]bool{
+	string(corev1.TaintEffectNoSchedule):       true,
+	string(corev1.TaintEffectPreferNoSchedule): true,
+	string(corev1.TaintEffectNoExecute):        true,
+}
+
+func validateWorkerPools(workerPools []*gqlschema.WorkerPoolInput) apperrors.AppError {
+	names := map[string]bool{model.PrimaryWorkerPoolName: true}
+
+	for _, pool := range workerPools {
+		if pool.Name == "" {
+			return apperrors.BadRequest("error: Worker pool name is empty")
+		}
+		if names[pool.Name] {
+			return apperrors.BadRequest("error: Worker pool name %s is not unique", pool.Name)
+		}
+		names[pool.Name] = true
+
+		if pool.MachineType == "" {
+			return apperrors.BadRequest("error: Machine type of worker pool %s is empty", pool.Name)
+		}
+		if util.NotNilOrEmpty(pool.MachineImageVersion) && util.IsNilOrEmpty(pool.MachineImage) {
+			return apperrors.BadRequest("error: Machine Image Version of worker pool %s passed while Machine Image is empty", pool.Name)
+		}
+		if pool.AutoScalerMin < 0 || pool.AutoScalerMin > pool.AutoScalerMax {
+			return apperrors.BadRequest("error: Auto scaler minimum of worker pool %s has to be between 0 and the maximum", pool.Name)
+		}
+
+		if pool.Labels != nil {
+			for key, value := range *pool.Labels {
+				if _, ok := value.(string); !ok {
+					return apperrors.BadRequest("error: Value of label %s of worker pool %s is not a string", key, pool.Name)
+				}
+			}
+		}
+
+		for _, taint := range pool.Taints {
+			if taint.Key == "" {
+				return apperrors.BadRequest("error: Taint key of worker pool %s is empty", pool.Name)
+			}
+			if !taintEffects[taint.Effect] {
+				return apperrors.BadRequest("error: Taint effect %s of worker pool %s is not one of NoSchedule, PreferNoSchedule or NoExecute", taint.Effect, pool.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateKubeAPIServerConfig(config *gqlschema.KubeAPIServerConfigInput) apperrors.AppError {
+	if config == nil {
+		return nil
+	}
+
+	if oidc := config.OidcConfig; oidc != nil {
+		issuerURL, err := url.Parse(oidc.IssuerURL)
+		if err != nil || issuerURL.Scheme != "https" || issuerURL.Host == "" {
+			return apperrors.BadRequest("error: OIDC issuer URL %s is not a valid https URL", oidc.IssuerURL)
+		}
+		if oidc.ClientID == "" {
+			return apperrors.BadRequest("error: OIDC client ID is empty")
+		}
+	}
+
+	for _, plugin := range config.AdmissionPlugins {
+		if plugin == "" {
+			return apperrors.BadRequest("error: Admission plugin name is empty")
+		}
+	}
+	return nil
+}
+
 func configContainsRuntimeAgentComponent(components []*gqlschema.ComponentConfigurationInput) bool {
 	for _, component := range components {
 		if component.Component == RuntimeAgent {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
 	dbMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
@@ -344,6 +345,193 @@ func TestValidator_ValidateUpgradeShootInput(t *testing.T) {
 		require.Error(t, err)
 		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
 	})
+
+	t.Run("Should return error when hibernation schedule has neither start nor end", func(t *testing.T) {
+		//given
+		validator := NewValidator(nil)
+
+		input := gqlschema.UpgradeShootInput{
+			GardenerConfig: &gqlschema.GardenerUpgradeInput{
+				HibernationSchedules: []*gqlschema.HibernationScheduleInput{
+					{Location: util.StringPtr("Europe/Berlin")},
+				},
+			},
+		}
+
+		//when
+		err := validator.ValidateUpgradeShootInput(input)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+	})
+
+	t.Run("Should return nil when worker pools are correct", func(t *testing.T) {
+		//given
+		validator := NewValidator(nil)
+
+		input := gqlschema.UpgradeShootInput{
+			GardenerConfig: &gqlschema.GardenerUpgradeInput{
+				WorkerPools: []*gqlschema.WorkerPoolInput{
+					fixWorkerPoolInput("memory"),
+					fixWorkerPoolInput("gpu"),
+				},
+			},
+		}
+
+		//when
+		err := validator.ValidateUpgradeShootInput(input)
+
+		//then
+		require.NoError(t, err)
+	})
+
+	for _, testCase := range []struct {
+		description string
+		workerPools []*gqlschema.WorkerPoolInput
+	}{
+		{
+			description: "Should return error when worker pool name is not unique",
+			workerPools: []*gqlschema.WorkerPoolInput{fixWorkerPoolInput("memory"), fixWorkerPoolInput("memory")},
+		},
+		{
+			description: "Should return error when worker pool name is the name of the primary worker pool",
+			workerPools: []*gqlschema.WorkerPoolInput{fixWorkerPoolInput(model.PrimaryWorkerPoolName)},
+		},
+		{
+			description: "Should return error when auto scaler minimum of worker pool is greater than maximum",
+			workerPools: []*gqlschema.WorkerPoolInput{func() *gqlschema.WorkerPoolInput {
+				pool := fixWorkerPoolInput("memory")
+				pool.AutoScalerMin = 4
+				return pool
+			}()},
+		},
+		{
+			description: "Should return error when worker pool label value is not a string",
+			workerPools: []*gqlschema.WorkerPoolInput{func() *gqlschema.WorkerPoolInput {
+				pool := fixWorkerPoolInput("memory")
+				pool.Labels = &gqlschema.Labels{"workload": []string{"memory"}}
+				return pool
+			}()},
+		},
+		{
+			description: "Should return error when worker pool taint effect is invalid",
+			workerPools: []*gqlschema.WorkerPoolInput{func() *gqlschema.WorkerPoolInput {
+				pool := fixWorkerPoolInput("memory")
+				pool.Taints = []*gqlschema.TaintInput{{Key: "dedicated", Effect: "Evict"}}
+				return pool
+			}()},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			//given
+			validator := NewValidator(nil)
+
+			input := gqlschema.UpgradeShootInput{
+				GardenerConfig: &gqlschema.GardenerUpgradeInput{
+					WorkerPools: testCase.workerPools,
+				},
+			}
+
+			//when
+			err := validator.ValidateUpgradeShootInput(input)
+
+			//then
+			require.Error(t, err)
+			util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		})
+	}
+
+	t.Run("Should return nil when kube-apiserver config is correct", func(t *testing.T) {
+		//given
+		validator := NewValidator(nil)
+
+		input := gqlschema.UpgradeShootInput{
+			GardenerConfig: &gqlschema.GardenerUpgradeInput{
+				KubeAPIServerConfig: fixKubeAPIServerConfigInput(),
+			},
+		}
+
+		//when
+		err := validator.ValidateUpgradeShootInput(input)
+
+		//then
+		require.NoError(t, err)
+	})
+
+	for _, testCase := range []struct {
+		description         string
+		kubeAPIServerConfig func(config *gqlschema.KubeAPIServerConfigInput)
+	}{
+		{
+			description: "Should return error when OIDC issuer URL does not use https",
+			kubeAPIServerConfig: func(config *gqlschema.KubeAPIServerConfigInput) {
+				config.OidcConfig.IssuerURL = "http://accounts.example.com"
+			},
+		},
+		{
+			description: "Should return error when OIDC issuer URL is not an URL",
+			kubeAPIServerConfig: func(config *gqlschema.KubeAPIServerConfigInput) {
+				config.OidcConfig.IssuerURL = "accounts.example.com"
+			},
+		},
+		{
+			description: "Should return error when OIDC client ID is empty",
+			kubeAPIServerConfig: func(config *gqlschema.KubeAPIServerConfigInput) {
+				config.OidcConfig.ClientID = ""
+			},
+		},
+		{
+			description: "Should return error when admission plugin name is empty",
+			kubeAPIServerConfig: func(config *gqlschema.KubeAPIServerConfigInput) {
+				config.AdmissionPlugins = append(config.AdmissionPlugins, "")
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			//given
+			validator := NewValidator(nil)
+
+			kubeAPIServerConfig := fixKubeAPIServerConfigInput()
+			testCase.kubeAPIServerConfig(kubeAPIServerConfig)
+
+			input := gqlschema.UpgradeShootInput{
+				GardenerConfig: &gqlschema.GardenerUpgradeInput{
+					KubeAPIServerConfig: kubeAPIServerConfig,
+				},
+			}
+
+			//when
+			err := validator.ValidateUpgradeShootInput(input)
+
+			//then
+			require.Error(t, err)
+			util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		})
+	}
+}
+
+func fixKubeAPIServerConfigInput() *gqlschema.KubeAPIServerConfigInput {
+	return &gqlschema.KubeAPIServerConfigInput{
+		OidcConfig: &gqlschema.OIDCConfigInput{
+			IssuerURL:     "https://accounts.example.com",
+			ClientID:      "kyma",
+			UsernameClaim: util.StringPtr("email"),
+			GroupsClaim:   util.StringPtr("groups"),
+		},
+		AdmissionPlugins: []string{"PodNodeSelector"},
+	}
+}
+
+func fixWorkerPoolInput(name string) *gqlschema.WorkerPoolInput {
+	return &gqlschema.WorkerPoolInput{
+		Name:          name,
+		MachineType:   "m5.8xlarge",
+		AutoScalerMin: 1,
+		AutoScalerMax: 3,
+		Labels:        &gqlschema.Labels{"workload": name},
+		Taints:        []*gqlschema.TaintInput{{Key: "dedicated", Value: util.StringPtr(name), Effect: "NoSchedule"}},
+	}
 }
 
 func TestValidator_ValidateTenant(t *testing.T) {
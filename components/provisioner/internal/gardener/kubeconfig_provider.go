@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/pkg/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v12 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
@@ -21,12 +22,12 @@ func NewKubeconfigProvider(secretsClient v12.SecretInterface) KubeconfigProvider
 func (kp KubeconfigProvider) FetchRaw(shootName string) ([]byte, error) {
 	secret, err := kp.secretsClient.Get(context.Background(), fmt.Sprintf("%s.kubeconfig", shootName), v1.GetOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("error fetching kubeconfig: %s", err.Error())
+		return nil, errors.Wrap(err, "error fetching kubeconfig")
 	}
 
 	kubeconfig, found := secret.Data["kubeconfig"]
 	if !found {
-		return nil, fmt.Errorf("error fetching kubeconfig: secret does not contain kubeconfig")
+		return nil, errors.New("error fetching kubeconfig: secret does not contain kubeconfig")
 	}
 
 	return kubeconfig, nil
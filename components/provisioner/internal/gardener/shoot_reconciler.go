@@ -8,6 +8,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
 
 	"k8s.io/client-go/util/retry"
@@ -60,7 +61,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
-	shouldReconcile, err := r.shouldReconcileShoot(shoot)
+	cluster, shouldReconcile, err := r.shouldReconcileShoot(shoot)
 	if err != nil {
 		log.Errorf("Failed to verify if shoot should be reconciled: %s", err.Error())
 		return ctrl.Result{}, err
@@ -80,22 +81,39 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		}
 	}
 
+	err = r.syncHibernationState(log, cluster, shoot)
+	if err != nil {
+		log.Errorf("Failed to update hibernation state of %s shoot: %s", shoot.Name, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
-func (r *Reconciler) shouldReconcileShoot(shoot gardener_types.Shoot) (bool, error) {
+func (r *Reconciler) shouldReconcileShoot(shoot gardener_types.Shoot) (model.Cluster, bool, error) {
 	session := r.dbsFactory.NewReadSession()
 
-	_, err := session.GetGardenerClusterByName(shoot.Name)
+	cluster, err := session.GetGardenerClusterByName(shoot.Name)
 	if err != nil {
 		if err.Code() == dberrors.CodeNotFound {
-			return false, nil
+			return model.Cluster{}, false, nil
 		}
 
-		return false, err
+		return model.Cluster{}, false, err
+	}
+
+	return cluster, true, nil
+}
+
+// syncHibernationState keeps the hibernation state stored in the database up to date when the Shoot is hibernated or woken up by its schedule
+func (r *Reconciler) syncHibernationState(logger logrus.FieldLogger, cluster model.Cluster, shoot gardener_types.Shoot) error {
+	if cluster.Deleted || cluster.Hibernated == shoot.Status.IsHibernated {
+		return nil
 	}
 
-	return true, nil
+	logger.Infof("Updating hibernation state to %t", shoot.Status.IsHibernated)
+
+	return r.dbsFactory.NewWriteSession().SetHibernated(cluster.ID, shoot.Status.IsHibernated)
 }
 
 func (r *Reconciler) updateShoot(namespacedName types.NamespacedName, modifyShootFn func(s *gardener_types.Shoot)) error {
@@ -1,17 +1,30 @@
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 const (
 	prometheusNamespace = "kcp"
 	prometheusSubsystem = "provisioner"
 )
 
-func Register(opsStatsGetter OperationsStatsGetter) error {
+func Register(opsStatsGetter OperationsStatsGetter, queues map[model.OperationType]QueueDepthGetter) error {
 	err := prometheus.Register(NewInProgressOperationsCollector(opsStatsGetter))
 	if err != nil {
 		return err
 	}
 
+	err = prometheus.Register(NewOperationQueuesCollector(queues))
+	if err != nil {
+		return err
+	}
+
+	err = prometheus.Register(operationProcessingDuration)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
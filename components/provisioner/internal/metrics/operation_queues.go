@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	regularLane  = "regular"
+	priorityLane = "priority"
+)
+
+// QueueDepth is the number of operations waiting in the lanes of an operation queue
+type QueueDepth struct {
+	Regular  int
+	Priority int
+}
+
+type QueueDepthGetter interface {
+	Depth() QueueDepth
+}
+
+var operationProcessingDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: prometheusSubsystem,
+		Name:      "operation_processing_duration_seconds",
+		Help:      "The time of a single processing of an operation by a queue worker",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	},
+	[]string{"operation"})
+
+// ObserveOperationProcessing records the time of a single processing of an operation of the given type
+func ObserveOperationProcessing(operationType model.OperationType, duration time.Duration) {
+	operationProcessingDuration.WithLabelValues(operationLabel(operationType)).Observe(duration.Seconds())
+}
+
+type OperationQueuesCollector struct {
+	queues map[model.OperationType]QueueDepthGetter
+
+	depthDesc *prometheus.Desc
+
+	log logrus.FieldLogger
+}
+
+func NewOperationQueuesCollector(queues map[model.OperationType]QueueDepthGetter) *OperationQueuesCollector {
+	return &OperationQueuesCollector{
+		queues: queues,
+
+		depthDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(prometheusNamespace, prometheusSubsystem, "operation_queue_depth"),
+			"The number of operations waiting in the operation queue",
+			[]string{"operation", "lane"},
+			nil),
+
+		log: logrus.WithField("collector", "operation-queues"),
+	}
+}
+
+func (c *OperationQueuesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.depthDesc
+}
+
+func (c *OperationQueuesCollector) Collect(ch chan<- prometheus.Metric) {
+	for operationType, queue := range c.queues {
+		depth := queue.Depth()
+		c.newMeasure(ch, depth.Regular, operationLabel(operationType), regularLane)
+		c.newMeasure(ch, depth.Priority, operationLabel(operationType), priorityLane)
+	}
+}
+
+func (c *OperationQueuesCollector) newMeasure(ch chan<- prometheus.Metric, value int, labelValues ...string) {
+	m, err := prometheus.NewConstMetric(
+		c.depthDesc,
+		prometheus.GaugeValue,
+		float64(value),
+		labelValues...)
+	if err != nil {
+		c.log.Errorf("unable to register metric %s", err.Error())
+		return
+	}
+	ch <- m
+}
+
+func operationLabel(operationType model.OperationType) string {
+	return strings.ToLower(string(operationType))
+}
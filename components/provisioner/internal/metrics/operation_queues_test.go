@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type queueDepthStub QueueDepth
+
+func (s queueDepthStub) Depth() QueueDepth {
+	return QueueDepth(s)
+}
+
+func Test_OperationQueuesCollector_Collect(t *testing.T) {
+	collector := NewOperationQueuesCollector(map[model.OperationType]QueueDepthGetter{
+		model.UpgradeShoot: queueDepthStub{Regular: 7, Priority: 1},
+	})
+
+	receiver := make(chan prometheus.Metric, 2)
+	defer close(receiver)
+
+	collector.Collect(receiver)
+
+	regularMetric := <-receiver
+	assertGaugeValue(t, regularMetric, float64(7))
+	assert.Equal(t, map[string]string{"operation": "upgrade_shoot", "lane": "regular"}, metricLabels(t, regularMetric))
+	assert.Contains(t, regularMetric.Desc().String(), "kcp_provisioner_operation_queue_depth")
+
+	priorityMetric := <-receiver
+	assertGaugeValue(t, priorityMetric, float64(1))
+	assert.Equal(t, map[string]string{"operation": "upgrade_shoot", "lane": "priority"}, metricLabels(t, priorityMetric))
+}
+
+func Test_OperationQueuesCollector_Describe(t *testing.T) {
+	collector := NewOperationQueuesCollector(nil)
+
+	receiver := make(chan *prometheus.Desc, 1)
+	defer close(receiver)
+
+	collector.Describe(receiver)
+
+	depthDesc := <-receiver
+	assert.Contains(t, depthDesc.String(), "kcp_provisioner_operation_queue_depth")
+}
+
+func metricLabels(t *testing.T, metric prometheus.Metric) map[string]string {
+	metricDto := dto.Metric{}
+	err := metric.Write(&metricDto)
+	require.NoError(t, err)
+
+	labels := map[string]string{}
+	for _, label := range metricDto.Label {
+		labels[label.GetName()] = label.GetValue()
+	}
+	return labels
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 
 	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryRuntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -20,6 +21,8 @@ const (
 	AccountLabel    = "account"
 
 	LicenceTypeAnnotation = "kcp.provisioner.kyma-project.io/licence-type"
+
+	PrimaryWorkerPoolName = "cpu-worker-0"
 )
 
 type GardenerConfig struct {
@@ -47,9 +50,60 @@ type GardenerConfig struct {
 	EnableKubernetesVersionAutoUpdate   bool
 	EnableMachineImageVersionAutoUpdate bool
 	AllowPrivilegedContainers           bool
+	HibernationSchedules                []HibernationSchedule `db:"-"`
+	WorkerPools                         []WorkerPool          `db:"-"`
+	KubeAPIServerConfig                 *KubeAPIServerConfig  `db:"-"`
 	GardenerProviderConfig              GardenerProviderConfig
 }
 
+// HibernationSchedule defines when the Shoot is hibernated and woken up, Start and End are cron expressions evaluated in the Location time zone
+type HibernationSchedule struct {
+	Start    *string `json:"start,omitempty"`
+	End      *string `json:"end,omitempty"`
+	Location *string `json:"location,omitempty"`
+}
+
+// WorkerPool is an additional group of worker nodes of the Shoot, the primary worker group is defined by the GardenerConfig itself
+type WorkerPool struct {
+	ID                  string
+	GardenerConfigID    string
+	Name                string
+	MachineType         string
+	MachineImage        *string
+	MachineImageVersion *string
+	DiskType            string
+	VolumeSizeGB        int
+	AutoScalerMin       int
+	AutoScalerMax       int
+	MaxSurge            int
+	MaxUnavailable      int
+	Zones               []string          `db:"-"`
+	Labels              map[string]string `db:"-"`
+	Taints              []Taint           `db:"-"`
+}
+
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// KubeAPIServerConfig holds settings of the Shoot kube-apiserver, it allows to authenticate users with the identity provider of the customer
+type KubeAPIServerConfig struct {
+	OIDCConfig       *OIDCConfig `json:"oidcConfig,omitempty"`
+	AdmissionPlugins []string    `json:"admissionPlugins,omitempty"`
+}
+
+type OIDCConfig struct {
+	IssuerURL      string   `json:"issuerURL"`
+	ClientID       string   `json:"clientID"`
+	UsernameClaim  *string  `json:"usernameClaim,omitempty"`
+	UsernamePrefix *string  `json:"usernamePrefix,omitempty"`
+	GroupsClaim    *string  `json:"groupsClaim,omitempty"`
+	GroupsPrefix   *string  `json:"groupsPrefix,omitempty"`
+	SigningAlgs    []string `json:"signingAlgs,omitempty"`
+}
+
 func (c GardenerConfig) ToShootTemplate(namespace string, accountId string, subAccountId string) (*gardener_types.Shoot, apperrors.AppError) {
 	enableBasicAuthentication := false
 
@@ -87,13 +141,16 @@ func (c GardenerConfig) ToShootTemplate(namespace string, accountId string, subA
 				Version:                   c.KubernetesVersion,
 				KubeAPIServer: &gardener_types.KubeAPIServerConfig{
 					EnableBasicAuthentication: &enableBasicAuthentication,
+					OIDCConfig:                c.KubeAPIServerConfig.shootOIDCConfig(),
+					AdmissionPlugins:          c.KubeAPIServerConfig.shootAdmissionPlugins(),
 				},
 			},
 			Networking: gardener_types.Networking{
 				Type:  "calico",                        // Default value - we may consider adding it to API (if Hydroform will support it)
 				Nodes: util.StringPtr("10.250.0.0/19"), // TODO: it is required - provide configuration in API (when Hydroform will support it)
 			},
-			Purpose: purpose,
+			Purpose:     purpose,
+			Hibernation: c.shootHibernation(),
 			Maintenance: &gardener_types.Maintenance{
 				AutoUpdate: &gardener_types.MaintenanceAutoUpdate{
 					KubernetesVersion:   c.EnableKubernetesVersionAutoUpdate,
@@ -111,6 +168,58 @@ func (c GardenerConfig) ToShootTemplate(namespace string, accountId string, subA
 	return shoot, nil
 }
 
+func (c GardenerConfig) shootHibernation() *gardener_types.Hibernation {
+	if len(c.HibernationSchedules) == 0 {
+		return nil
+	}
+
+	return &gardener_types.Hibernation{
+		Schedules: c.hibernationSchedulesToShoot(),
+	}
+}
+
+func (c GardenerConfig) hibernationSchedulesToShoot() []gardener_types.HibernationSchedule {
+	schedules := make([]gardener_types.HibernationSchedule, 0, len(c.HibernationSchedules))
+	for _, schedule := range c.HibernationSchedules {
+		schedules = append(schedules, gardener_types.HibernationSchedule{
+			Start:    schedule.Start,
+			End:      schedule.End,
+			Location: schedule.Location,
+		})
+	}
+
+	return schedules
+}
+
+func (c *KubeAPIServerConfig) shootOIDCConfig() *gardener_types.OIDCConfig {
+	if c == nil || c.OIDCConfig == nil {
+		return nil
+	}
+
+	return &gardener_types.OIDCConfig{
+		IssuerURL:      util.StringPtr(c.OIDCConfig.IssuerURL),
+		ClientID:       util.StringPtr(c.OIDCConfig.ClientID),
+		UsernameClaim:  c.OIDCConfig.UsernameClaim,
+		UsernamePrefix: c.OIDCConfig.UsernamePrefix,
+		GroupsClaim:    c.OIDCConfig.GroupsClaim,
+		GroupsPrefix:   c.OIDCConfig.GroupsPrefix,
+		SigningAlgs:    c.OIDCConfig.SigningAlgs,
+	}
+}
+
+func (c *KubeAPIServerConfig) shootAdmissionPlugins() []gardener_types.AdmissionPlugin {
+	if c == nil || len(c.AdmissionPlugins) == 0 {
+		return nil
+	}
+
+	plugins := make([]gardener_types.AdmissionPlugin, 0, len(c.AdmissionPlugins))
+	for _, name := range c.AdmissionPlugins {
+		plugins = append(plugins, gardener_types.AdmissionPlugin{Name: name})
+	}
+
+	return plugins
+}
+
 type ProviderSpecificConfig string
 
 func (c ProviderSpecificConfig) RawJSON() string {
@@ -188,7 +297,7 @@ func (c GCPGardenerConfig) EditShootConfig(gardenerConfig GardenerConfig, shoot
 func (c GCPGardenerConfig) ExtendShootConfig(gardenerConfig GardenerConfig, shoot *gardener_types.Shoot) apperrors.AppError {
 	shoot.Spec.CloudProfileName = "gcp"
 
-	workers := []gardener_types.Worker{getWorkerConfig(gardenerConfig, c.input.Zones)}
+	workers := getWorkersConfig(gardenerConfig, c.input.Zones)
 
 	gcpInfra := NewGCPInfrastructure(gardenerConfig.WorkerCidr)
 	jsonData, err := json.Marshal(gcpInfra)
@@ -264,7 +373,7 @@ func (c AzureGardenerConfig) EditShootConfig(gardenerConfig GardenerConfig, shoo
 func (c AzureGardenerConfig) ExtendShootConfig(gardenerConfig GardenerConfig, shoot *gardener_types.Shoot) apperrors.AppError {
 	shoot.Spec.CloudProfileName = "az"
 
-	workers := []gardener_types.Worker{getWorkerConfig(gardenerConfig, c.input.Zones)}
+	workers := getWorkersConfig(gardenerConfig, c.input.Zones)
 
 	azInfra := NewAzureInfrastructure(gardenerConfig.WorkerCidr, c)
 	jsonData, err := json.Marshal(azInfra)
@@ -332,7 +441,7 @@ func (c AWSGardenerConfig) EditShootConfig(gardenerConfig GardenerConfig, shoot
 func (c AWSGardenerConfig) ExtendShootConfig(gardenerConfig GardenerConfig, shoot *gardener_types.Shoot) apperrors.AppError {
 	shoot.Spec.CloudProfileName = "aws"
 
-	workers := []gardener_types.Worker{getWorkerConfig(gardenerConfig, []string{c.input.Zone})}
+	workers := getWorkersConfig(gardenerConfig, []string{c.input.Zone})
 
 	awsInfra := NewAWSInfrastructure(gardenerConfig.WorkerCidr, c)
 	jsonData, err := json.Marshal(awsInfra)
@@ -356,9 +465,18 @@ func (c AWSGardenerConfig) ExtendShootConfig(gardenerConfig GardenerConfig, shoo
 	return nil
 }
 
+func getWorkersConfig(gardenerConfig GardenerConfig, zones []string) []gardener_types.Worker {
+	workers := []gardener_types.Worker{getWorkerConfig(gardenerConfig, zones)}
+	for _, pool := range gardenerConfig.WorkerPools {
+		workers = append(workers, pool.toShootWorker(zones))
+	}
+
+	return workers
+}
+
 func getWorkerConfig(gardenerConfig GardenerConfig, zones []string) gardener_types.Worker {
 	return gardener_types.Worker{
-		Name:           "cpu-worker-0",
+		Name:           PrimaryWorkerPoolName,
 		MaxSurge:       util.IntOrStringPtr(intstr.FromInt(gardenerConfig.MaxSurge)),
 		MaxUnavailable: util.IntOrStringPtr(intstr.FromInt(gardenerConfig.MaxUnavailable)),
 		Machine:        getMachineConfig(gardenerConfig),
@@ -386,11 +504,27 @@ func updateShootConfig(upgradeConfig GardenerConfig, shoot *gardener_types.Shoot
 	shoot.Spec.Maintenance.AutoUpdate.KubernetesVersion = upgradeConfig.EnableKubernetesVersionAutoUpdate
 	shoot.Spec.Maintenance.AutoUpdate.MachineImageVersion = upgradeConfig.EnableMachineImageVersionAutoUpdate
 
+	// Hibernation is toggled by separate operations, the upgrade changes only the schedules
+	if shoot.Spec.Hibernation != nil {
+		shoot.Spec.Hibernation.Schedules = upgradeConfig.hibernationSchedulesToShoot()
+	} else if len(upgradeConfig.HibernationSchedules) > 0 {
+		shoot.Spec.Hibernation = upgradeConfig.shootHibernation()
+	}
+
+	// Clusters provisioned without the kube-apiserver config keep the settings of the Shoot until the config is provided
+	if upgradeConfig.KubeAPIServerConfig != nil {
+		if shoot.Spec.Kubernetes.KubeAPIServer == nil {
+			shoot.Spec.Kubernetes.KubeAPIServer = &gardener_types.KubeAPIServerConfig{}
+		}
+		shoot.Spec.Kubernetes.KubeAPIServer.OIDCConfig = upgradeConfig.KubeAPIServerConfig.shootOIDCConfig()
+		shoot.Spec.Kubernetes.KubeAPIServer.AdmissionPlugins = upgradeConfig.KubeAPIServerConfig.shootAdmissionPlugins()
+	}
+
 	if len(shoot.Spec.Provider.Workers) == 0 {
 		return apperrors.Internal("no worker groups assigned to Gardener shoot '%s'", shoot.Name)
 	}
 
-	// We support only single working group during provisioning
+	// The first worker group is the primary one defined directly by the Gardener config
 	shoot.Spec.Provider.Workers[0].MaxSurge = util.IntOrStringPtr(intstr.FromInt(upgradeConfig.MaxSurge))
 	shoot.Spec.Provider.Workers[0].MaxUnavailable = util.IntOrStringPtr(intstr.FromInt(upgradeConfig.MaxUnavailable))
 	shoot.Spec.Provider.Workers[0].Machine.Type = upgradeConfig.MachineType
@@ -405,9 +539,78 @@ func updateShootConfig(upgradeConfig GardenerConfig, shoot *gardener_types.Shoot
 	if util.NotNilOrEmpty(upgradeConfig.MachineImageVersion) {
 		shoot.Spec.Provider.Workers[0].Machine.Image.Version = upgradeConfig.MachineImageVersion
 	}
+
+	shoot.Spec.Provider.Workers = append(shoot.Spec.Provider.Workers[:1], updateWorkerPools(upgradeConfig.WorkerPools, shoot.Spec.Provider.Workers[1:], zones)...)
 	return nil
 }
 
+// updateWorkerPools replaces additional worker groups of the Shoot with the worker pools, groups which already exist are updated in place to keep values defaulted by Gardener
+func updateWorkerPools(pools []WorkerPool, workers []gardener_types.Worker, zones []string) []gardener_types.Worker {
+	existingWorkers := make(map[string]gardener_types.Worker, len(workers))
+	for _, worker := range workers {
+		existingWorkers[worker.Name] = worker
+	}
+
+	updatedWorkers := make([]gardener_types.Worker, 0, len(pools))
+	for _, pool := range pools {
+		worker, found := existingWorkers[pool.Name]
+		if !found {
+			updatedWorkers = append(updatedWorkers, pool.toShootWorker(zones))
+			continue
+		}
+
+		pool.applyToShootWorker(&worker, zones)
+		updatedWorkers = append(updatedWorkers, worker)
+	}
+
+	return updatedWorkers
+}
+
+func (p WorkerPool) toShootWorker(zones []string) gardener_types.Worker {
+	worker := gardener_types.Worker{Name: p.Name}
+	p.applyToShootWorker(&worker, zones)
+
+	return worker
+}
+
+// applyToShootWorker sets the worker group fields managed by Provisioner, pools without zones are spread across the zones of the cluster
+func (p WorkerPool) applyToShootWorker(worker *gardener_types.Worker, zones []string) {
+	worker.MaxSurge = util.IntOrStringPtr(intstr.FromInt(p.MaxSurge))
+	worker.MaxUnavailable = util.IntOrStringPtr(intstr.FromInt(p.MaxUnavailable))
+	worker.Machine.Type = p.MachineType
+	if util.NotNilOrEmpty(p.MachineImage) {
+		if worker.Machine.Image == nil {
+			worker.Machine.Image = &gardener_types.ShootMachineImage{}
+		}
+		worker.Machine.Image.Name = *p.MachineImage
+		if util.NotNilOrEmpty(p.MachineImageVersion) {
+			worker.Machine.Image.Version = p.MachineImageVersion
+		}
+	}
+	if worker.Volume == nil {
+		worker.Volume = &gardener_types.Volume{}
+	}
+	worker.Volume.Type = util.StringPtr(p.DiskType)
+	worker.Volume.VolumeSize = fmt.Sprintf("%dGi", p.VolumeSizeGB)
+	worker.Maximum = int32(p.AutoScalerMax)
+	worker.Minimum = int32(p.AutoScalerMin)
+
+	worker.Zones = zones
+	if len(p.Zones) > 0 {
+		worker.Zones = p.Zones
+	}
+
+	worker.Labels = p.Labels
+	worker.Taints = nil
+	for _, taint := range p.Taints {
+		worker.Taints = append(worker.Taints, corev1.Taint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: corev1.TaintEffect(taint.Effect),
+		})
+	}
+}
+
 func getMachineConfig(config GardenerConfig) gardener_types.Machine {
 	machine := gardener_types.Machine{
 		Type: config.MachineType,
@@ -6,6 +6,7 @@ import (
 	apimachineryRuntime "k8s.io/apimachinery/pkg/runtime"
 
 	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -365,6 +366,38 @@ func TestGardenerConfig_ToShootTemplate(t *testing.T) {
 		})
 	}
 
+	t.Run("should add worker pools next to the primary worker group", func(t *testing.T) {
+		// given
+		gardenerConfig := fixGardenerConfig("gcp", gcpGardenerProvider)
+		gardenerConfig.WorkerPools = []WorkerPool{
+			fixWorkerPool("memory", nil),
+			fixWorkerPool("zonal", []string{"fix-zone-2"}),
+		}
+
+		// when
+		template, err := gardenerConfig.ToShootTemplate("gardener-namespace", "account", "sub-account")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []gardener_types.Worker{
+			fixWorker(zones),
+			fixWorkerPoolWorker("memory", zones...),
+			fixWorkerPoolWorker("zonal", "fix-zone-2"),
+		}, template.Spec.Provider.Workers)
+	})
+
+	t.Run("should set OIDC config and admission plugins of kube-apiserver", func(t *testing.T) {
+		// given
+		gardenerConfig := fixGardenerConfig("gcp", gcpGardenerProvider)
+		gardenerConfig.KubeAPIServerConfig = fixKubeAPIServerConfig()
+
+		// when
+		template, err := gardenerConfig.ToShootTemplate("gardener-namespace", "account", "sub-account")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fixShootKubeAPIServerConfig(), template.Spec.Kubernetes.KubeAPIServer)
+	})
 }
 
 func TestEditShootConfig(t *testing.T) {
@@ -429,6 +462,80 @@ func TestEditShootConfig(t *testing.T) {
 			initialShoot:  initialShoot.DeepCopy(),
 			expectedShoot: expectedShoot.DeepCopy(),
 		},
+		{description: "should update hibernation schedules and keep hibernation state",
+			provider: "gcp",
+			upgradeConfig: func(c GardenerConfig) GardenerConfig {
+				c.HibernationSchedules = []HibernationSchedule{{Start: util.StringPtr("00 20 * * 1,2,3,4,5"), Location: util.StringPtr("Europe/Berlin")}}
+				return c
+			}(fixGardenerConfig("gcp", gcpProviderConfig)),
+			initialShoot: func(s *gardener_types.Shoot) *gardener_types.Shoot {
+				shoot := s.DeepCopy()
+				shoot.Spec.Hibernation = &gardener_types.Hibernation{Enabled: util.BoolPtr(true)}
+				return shoot
+			}(initialShoot),
+			expectedShoot: func(s *gardener_types.Shoot) *gardener_types.Shoot {
+				shoot := s.DeepCopy()
+				shoot.Spec.Hibernation = &gardener_types.Hibernation{
+					Enabled:   util.BoolPtr(true),
+					Schedules: []gardener_types.HibernationSchedule{{Start: util.StringPtr("00 20 * * 1,2,3,4,5"), Location: util.StringPtr("Europe/Berlin")}},
+				}
+				return shoot
+			}(expectedShoot),
+		},
+		{description: "should update existing worker pools, add new ones and remove missing ones",
+			provider: "gcp",
+			upgradeConfig: func(c GardenerConfig) GardenerConfig {
+				c.WorkerPools = []WorkerPool{
+					fixWorkerPool("memory", nil),
+					fixWorkerPool("zonal", []string{"fix-zone-2"}),
+				}
+				return c
+			}(fixGardenerConfig("gcp", gcpProviderConfig)),
+			initialShoot: func(s *gardener_types.Shoot) *gardener_types.Shoot {
+				shoot := s.DeepCopy()
+				shoot.Spec.Provider.Workers = append(shoot.Spec.Provider.Workers,
+					testkit.NewTestWorker("memory").WithMachineType("small-machine").WithMinMax(1, 1).ToWorker(),
+					testkit.NewTestWorker("removed").ToWorker())
+				return shoot
+			}(initialShoot),
+			expectedShoot: func(s *gardener_types.Shoot) *gardener_types.Shoot {
+				shoot := s.DeepCopy()
+				shoot.Spec.Provider.Workers = append(shoot.Spec.Provider.Workers,
+					fixWorkerPoolWorker("memory", zones...),
+					fixWorkerPoolWorker("zonal", "fix-zone-2"))
+				return shoot
+			}(expectedShoot),
+		},
+		{description: "should set OIDC config and admission plugins of kube-apiserver",
+			provider: "gcp",
+			upgradeConfig: func(c GardenerConfig) GardenerConfig {
+				c.KubeAPIServerConfig = fixKubeAPIServerConfig()
+				return c
+			}(fixGardenerConfig("gcp", gcpProviderConfig)),
+			initialShoot: initialShoot.DeepCopy(),
+			expectedShoot: func(s *gardener_types.Shoot) *gardener_types.Shoot {
+				shoot := s.DeepCopy()
+				shoot.Spec.Kubernetes.KubeAPIServer = &gardener_types.KubeAPIServerConfig{
+					OIDCConfig:       fixShootKubeAPIServerConfig().OIDCConfig,
+					AdmissionPlugins: fixShootKubeAPIServerConfig().AdmissionPlugins,
+				}
+				return shoot
+			}(expectedShoot),
+		},
+		{description: "should keep kube-apiserver settings when config is not provided",
+			provider:      "gcp",
+			upgradeConfig: fixGardenerConfig("gcp", gcpProviderConfig),
+			initialShoot: func(s *gardener_types.Shoot) *gardener_types.Shoot {
+				shoot := s.DeepCopy()
+				shoot.Spec.Kubernetes.KubeAPIServer = fixShootKubeAPIServerConfig()
+				return shoot
+			}(initialShoot),
+			expectedShoot: func(s *gardener_types.Shoot) *gardener_types.Shoot {
+				shoot := s.DeepCopy()
+				shoot.Spec.Kubernetes.KubeAPIServer = fixShootKubeAPIServerConfig()
+				return shoot
+			}(expectedShoot),
+		},
 	} {
 		t.Run(testCase.description, func(t *testing.T) {
 			// given
@@ -513,6 +620,65 @@ func fixAzureGardenerInput(zones []string) *gqlschema.AzureProviderConfigInput {
 	return &gqlschema.AzureProviderConfigInput{VnetCidr: "10.10.11.11/255", Zones: zones}
 }
 
+func fixKubeAPIServerConfig() *KubeAPIServerConfig {
+	return &KubeAPIServerConfig{
+		OIDCConfig: &OIDCConfig{
+			IssuerURL:     "https://accounts.example.com",
+			ClientID:      "kyma",
+			UsernameClaim: util.StringPtr("email"),
+			GroupsClaim:   util.StringPtr("groups"),
+			SigningAlgs:   []string{"RS256"},
+		},
+		AdmissionPlugins: []string{"PodNodeSelector"},
+	}
+}
+
+func fixShootKubeAPIServerConfig() *gardener_types.KubeAPIServerConfig {
+	return &gardener_types.KubeAPIServerConfig{
+		EnableBasicAuthentication: util.BoolPtr(false),
+		OIDCConfig: &gardener_types.OIDCConfig{
+			IssuerURL:     util.StringPtr("https://accounts.example.com"),
+			ClientID:      util.StringPtr("kyma"),
+			UsernameClaim: util.StringPtr("email"),
+			GroupsClaim:   util.StringPtr("groups"),
+			SigningAlgs:   []string{"RS256"},
+		},
+		AdmissionPlugins: []gardener_types.AdmissionPlugin{{Name: "PodNodeSelector"}},
+	}
+}
+
+func fixWorkerPool(name string, zones []string) WorkerPool {
+	return WorkerPool{
+		Name:                name,
+		MachineType:         "memory-machine",
+		MachineImage:        util.StringPtr("gardenlinux"),
+		MachineImageVersion: util.StringPtr("25.0.0"),
+		DiskType:            "SSD",
+		VolumeSizeGB:        50,
+		AutoScalerMin:       0,
+		AutoScalerMax:       2,
+		MaxSurge:            1,
+		MaxUnavailable:      0,
+		Zones:               zones,
+		Labels:              map[string]string{"pool": name},
+		Taints:              []Taint{{Key: "dedicated", Value: name, Effect: "NoSchedule"}},
+	}
+}
+
+func fixWorkerPoolWorker(name string, zones ...string) gardener_types.Worker {
+	return testkit.NewTestWorker(name).
+		WithMachineType("memory-machine").
+		WithMachineImageAndVersion("gardenlinux", "25.0.0").
+		WithVolume("SSD", 50).
+		WithMinMax(0, 2).
+		WithMaxSurge(1).
+		WithMaxUnavailable(0).
+		WithZones(zones...).
+		WithLabels(map[string]string{"pool": name}).
+		WithTaints(corev1.Taint{Key: "dedicated", Value: name, Effect: corev1.TaintEffectNoSchedule}).
+		ToWorker()
+}
+
 func fixWorker(zones []string) gardener_types.Worker {
 	return gardener_types.Worker{
 		Name:           "cpu-worker-0",
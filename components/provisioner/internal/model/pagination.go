@@ -0,0 +1,67 @@
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 500
+)
+
+// RuntimeFilter narrows down listed Runtimes, empty fields are not taken into account
+type RuntimeFilter struct {
+	Tenant            string
+	SubAccountID      string
+	Provider          string
+	Region            string
+	KubernetesVersion string
+	KymaVersion       string
+}
+
+// OperationFilter narrows down listed Operations, empty fields are not taken into account
+type OperationFilter struct {
+	Tenant       string
+	SubAccountID string
+	RuntimeID    string
+	Type         OperationType
+	State        OperationState
+}
+
+// PageCursor identifies the last element of a page.
+// Lists are ordered by timestamp and ID so that the cursor stays valid when new elements are added.
+type PageCursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+func (c PageCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%s", c.Timestamp.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodePageCursor(cursor string) (PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return PageCursor{}, fmt.Errorf("cursor is not valid base64: %s", err.Error())
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return PageCursor{}, fmt.Errorf("cursor has invalid format")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return PageCursor{}, fmt.Errorf("cursor has invalid timestamp: %s", err.Error())
+	}
+
+	return PageCursor{
+		Timestamp: time.Unix(0, nanos).UTC(),
+		ID:        parts[1],
+	}, nil
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCursor(t *testing.T) {
+
+	t.Run("should decode encoded cursor", func(t *testing.T) {
+		// given
+		cursor := PageCursor{
+			Timestamp: time.Date(2020, 12, 1, 10, 30, 0, 123456000, time.UTC),
+			ID:        "6dd0a2e0-7bd5-4f6c-8b33-b41bfa2d9d4d",
+		}
+
+		// when
+		decoded, err := DecodePageCursor(cursor.Encode())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	})
+
+	for _, testCase := range []struct {
+		description string
+		cursor      string
+	}{
+		{
+			description: "not base64",
+			cursor:      "not a cursor!",
+		},
+		{
+			description: "missing ID",
+			cursor:      base64.RawURLEncoding.EncodeToString([]byte("1606818600000000000:")),
+		},
+		{
+			description: "invalid timestamp",
+			cursor:      base64.RawURLEncoding.EncodeToString([]byte("yesterday:id")),
+		},
+	} {
+		t.Run("should fail to decode cursor when "+testCase.description, func(t *testing.T) {
+			// when
+			_, err := DecodePageCursor(testCase.cursor)
+
+			// then
+			require.Error(t, err)
+		})
+	}
+}
@@ -20,6 +20,9 @@ const (
 	UpgradeShoot     OperationType = "UPGRADE_SHOOT"
 	Deprovision      OperationType = "DEPROVISION"
 	ReconnectRuntime OperationType = "RECONNECT_RUNTIME"
+	Hibernate        OperationType = "HIBERNATE"
+	WakeUp           OperationType = "WAKE_UP"
+	Rollback         OperationType = "ROLLBACK"
 )
 
 type OperationStage string
@@ -40,10 +43,16 @@ const (
 
 	StartingUpgrade      OperationStage = "StartingUpgrade"
 	UpdatingUpgradeState OperationStage = "UpdatingUpgradeState"
+	StartingRollback     OperationStage = "StartingRollback"
 
 	WaitingForShootUpgrade    OperationStage = "WaitingForShootUpgrade"
 	WaitingForShootNewVersion OperationStage = "WaitingForShootNewVersion"
 
+	StartingShootHibernation   OperationStage = "StartingShootHibernation"
+	WaitingForShootHibernation OperationStage = "WaitingForShootHibernation"
+	StartingShootWakeUp        OperationStage = "StartingShootWakeUp"
+	WaitingForShootWakeUp      OperationStage = "WaitingForShootWakeUp"
+
 	FinishedStage OperationStage = "Finished"
 )
 
@@ -55,6 +64,7 @@ type Cluster struct {
 	Tenant             string
 	SubAccountId       *string
 	ActiveKymaConfigId string
+	Hibernated         bool
 
 	ClusterConfig GardenerConfig `db:"-"`
 	KymaConfig    KymaConfig     `db:"-"`
@@ -70,6 +80,8 @@ type Operation struct {
 	ClusterID      string
 	Stage          OperationStage
 	LastTransition *time.Time
+	// Urgent operations are processed in the priority lane of the queue, also when they are enqueued again after restart
+	Urgent bool
 }
 
 type RuntimeAgentConnectionStatus int
@@ -24,7 +24,8 @@ func NewExecutor(
 	operation model.OperationType,
 	stages map[model.OperationStage]Step,
 	failureHandler FailureHandler,
-	directorClient director.DirectorClient) *Executor {
+	directorClient director.DirectorClient,
+	notifier OperationNotifier) *Executor {
 
 	return &Executor{
 		dbSession:      session,
@@ -33,6 +34,7 @@ func NewExecutor(
 		failureHandler: failureHandler,
 		log:            logrus.WithFields(logrus.Fields{"Component": "Executor", "OperationType": operation}),
 		directorClient: directorClient,
+		notifier:       notifier,
 	}
 }
 
@@ -42,6 +44,7 @@ type Executor struct {
 	operation      model.OperationType
 	failureHandler FailureHandler
 	directorClient director.DirectorClient
+	notifier       OperationNotifier
 
 	log logrus.FieldLogger
 }
@@ -172,7 +175,10 @@ func (e *Executor) updateOperationStatus(log logrus.FieldLogger, id, message str
 	}, retry.Attempts(5))
 	if err != nil {
 		log.Infof("Cannot set operation status to %s: %s", state, err.Error())
+		return
 	}
+
+	e.notifyOperationChanged(log, id)
 }
 
 func (e *Executor) setRuntimeStatusCondition(log logrus.FieldLogger, id, tenant string) {
@@ -190,5 +196,18 @@ func (e *Executor) updateOperationStage(log logrus.FieldLogger, id, message stri
 	}, retry.Attempts(5))
 	if err != nil {
 		log.Infof("Cannot modify operation stage to %s: %s", stage, err.Error())
+		return
 	}
+
+	e.notifyOperationChanged(log, id)
+}
+
+func (e *Executor) notifyOperationChanged(log logrus.FieldLogger, id string) {
+	operation, err := e.dbSession.GetOperation(id)
+	if err != nil {
+		log.Warnf("Cannot notify about operation change: %s", err.Error())
+		return
+	}
+
+	e.notifier.OperationChanged(operation)
 }
@@ -58,7 +58,9 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		directorClient := &directorMocks.DirectorClient{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), directorClient)
+		notifier := &MockOperationNotifier{}
+
+		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), directorClient, notifier)
 
 		// when
 		result := executor.Execute(operationId)
@@ -66,6 +68,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 		// then
 		assert.Equal(t, false, result.Requeue)
 		assert.True(t, mockStage.called)
+		assert.Len(t, notifier.operations, 2)
 	})
 
 	t.Run("should requeue operation if error occurred", func(t *testing.T) {
@@ -82,7 +85,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		directorClient := &directorMocks.DirectorClient{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, failure.NewNoopFailureHandler(), directorClient, &MockOperationNotifier{})
 
 		// when
 		result := executor.Execute(operationId)
@@ -111,7 +114,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		failureHandler := MockFailureHandler{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient, &MockOperationNotifier{})
 
 		// when
 		result := executor.Execute(operationId)
@@ -141,7 +144,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		failureHandler := MockFailureHandler{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient, &MockOperationNotifier{})
 
 		// when
 		result := executor.Execute(operationId)
@@ -173,7 +176,7 @@ func TestStagesExecutor_Execute(t *testing.T) {
 
 		failureHandler := MockFailureHandler{}
 
-		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient)
+		executor := NewExecutor(dbSession, model.Provision, installationStages, &failureHandler, directorClient, &MockOperationNotifier{})
 
 		// when
 		result := executor.Execute(operationId)
@@ -242,3 +245,11 @@ func (m *MockFailureHandler) HandleFailure(operation model.Operation, cluster mo
 	m.called = true
 	return nil
 }
+
+type MockOperationNotifier struct {
+	operations []model.Operation
+}
+
+func (m *MockOperationNotifier) OperationChanged(operation model.Operation) {
+	m.operations = append(m.operations, operation)
+}
@@ -3,18 +3,42 @@ package failure
 import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/sirupsen/logrus"
 )
 
+//go:generate mockery -name=RollbackStarter
+type RollbackStarter interface {
+	StartRollback(runtimeID string, upgradeOperationID string) (model.Operation, error)
+}
+
 type UpgradeFailureHandler struct {
-	session dbsession.WriteSession
+	session         dbsession.WriteSession
+	rollbackStarter RollbackStarter
 }
 
-func NewUpgradeFailureHandler(session dbsession.WriteSession) *UpgradeFailureHandler {
+// NewUpgradeFailureHandler returns handler marking failed upgrades, if rollbackStarter is not nil failed Kyma upgrades are rolled back automatically
+func NewUpgradeFailureHandler(session dbsession.WriteSession, rollbackStarter RollbackStarter) *UpgradeFailureHandler {
 	return &UpgradeFailureHandler{
-		session: session,
+		session:         session,
+		rollbackStarter: rollbackStarter,
 	}
 }
 
 func (u UpgradeFailureHandler) HandleFailure(operation model.Operation, _ model.Cluster) error {
-	return u.session.UpdateUpgradeState(operation.ID, model.UpgradeFailed)
+	dberr := u.session.UpdateUpgradeState(operation.ID, model.UpgradeFailed)
+	if dberr != nil {
+		return dberr
+	}
+
+	if u.rollbackStarter == nil || operation.Type != model.Upgrade {
+		return nil
+	}
+
+	rollbackOperation, err := u.rollbackStarter.StartRollback(operation.ClusterID, operation.ID)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Started rollback operation %s of failed upgrade operation %s", rollbackOperation.ID, operation.ID)
+	return nil
 }
@@ -0,0 +1,98 @@
+package failure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeFailureHandler_HandleFailure(t *testing.T) {
+	upgradeOperation := model.Operation{ID: "operation-id", ClusterID: "runtime-id", Type: model.Upgrade}
+
+	t.Run("should mark upgrade as failed", func(t *testing.T) {
+		//given
+		session := &sessionMocks.WriteSession{}
+		session.On("UpdateUpgradeState", "operation-id", model.UpgradeFailed).Return(nil)
+
+		handler := NewUpgradeFailureHandler(session, nil)
+
+		//when
+		err := handler.HandleFailure(upgradeOperation, model.Cluster{})
+
+		//then
+		require.NoError(t, err)
+		session.AssertExpectations(t)
+	})
+
+	t.Run("should start rollback of failed upgrade", func(t *testing.T) {
+		//given
+		session := &sessionMocks.WriteSession{}
+		session.On("UpdateUpgradeState", "operation-id", model.UpgradeFailed).Return(nil)
+		rollbackStarter := &mocks.RollbackStarter{}
+		rollbackStarter.On("StartRollback", "runtime-id", "operation-id").Return(model.Operation{ID: "rollback-id"}, nil)
+
+		handler := NewUpgradeFailureHandler(session, rollbackStarter)
+
+		//when
+		err := handler.HandleFailure(upgradeOperation, model.Cluster{})
+
+		//then
+		require.NoError(t, err)
+		session.AssertExpectations(t)
+		rollbackStarter.AssertExpectations(t)
+	})
+
+	t.Run("should not start rollback of failed rollback", func(t *testing.T) {
+		//given
+		session := &sessionMocks.WriteSession{}
+		session.On("UpdateUpgradeState", "rollback-id", model.UpgradeFailed).Return(nil)
+		rollbackStarter := &mocks.RollbackStarter{}
+
+		handler := NewUpgradeFailureHandler(session, rollbackStarter)
+
+		//when
+		err := handler.HandleFailure(model.Operation{ID: "rollback-id", ClusterID: "runtime-id", Type: model.Rollback}, model.Cluster{})
+
+		//then
+		require.NoError(t, err)
+		rollbackStarter.AssertNotCalled(t, "StartRollback", "runtime-id", "rollback-id")
+	})
+
+	t.Run("should return error when failed to update upgrade state", func(t *testing.T) {
+		//given
+		session := &sessionMocks.WriteSession{}
+		session.On("UpdateUpgradeState", "operation-id", model.UpgradeFailed).Return(dberrors.Internal("error"))
+		rollbackStarter := &mocks.RollbackStarter{}
+
+		handler := NewUpgradeFailureHandler(session, rollbackStarter)
+
+		//when
+		err := handler.HandleFailure(upgradeOperation, model.Cluster{})
+
+		//then
+		require.Error(t, err)
+		rollbackStarter.AssertNotCalled(t, "StartRollback", "runtime-id", "operation-id")
+	})
+
+	t.Run("should return error when failed to start rollback", func(t *testing.T) {
+		//given
+		session := &sessionMocks.WriteSession{}
+		session.On("UpdateUpgradeState", "operation-id", model.UpgradeFailed).Return(nil)
+		rollbackStarter := &mocks.RollbackStarter{}
+		rollbackStarter.On("StartRollback", "runtime-id", "operation-id").Return(model.Operation{}, errors.New("error"))
+
+		handler := NewUpgradeFailureHandler(session, rollbackStarter)
+
+		//when
+		err := handler.HandleFailure(upgradeOperation, model.Cluster{})
+
+		//then
+		assert.Error(t, err)
+	})
+}
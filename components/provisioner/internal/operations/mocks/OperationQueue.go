@@ -14,6 +14,11 @@ func (_m *OperationQueue) Add(processId string) {
 	_m.Called(processId)
 }
 
+// AddPriority provides a mock function with given fields: processId
+func (_m *OperationQueue) AddPriority(processId string) {
+	_m.Called(processId)
+}
+
 // Run provides a mock function with given fields: stop
 func (_m *OperationQueue) Run(stop <-chan struct{}) {
 	_m.Called(stop)
@@ -0,0 +1,116 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+)
+
+const subscriptionBufferSize = 10
+
+// Filter selects operations delivered to the subscriber, empty fields are not taken into account
+type Filter struct {
+	RuntimeID   string
+	OperationID string
+}
+
+func (f Filter) matches(operation model.Operation) bool {
+	if f.OperationID != "" && f.OperationID != operation.ID {
+		return false
+	}
+	if f.RuntimeID != "" && f.RuntimeID != operation.ClusterID {
+		return false
+	}
+	return true
+}
+
+// Subscriber delivers changes of operations until the context is canceled
+type Subscriber interface {
+	Subscribe(ctx context.Context, filter Filter) <-chan model.Operation
+}
+
+// Broker dispatches changes of operations to subscribers. It is fed by the operations executor of this Provisioner instance
+// and by the Poller which reads changes of operations processed by all replicas, the same change is delivered only once.
+// Slow subscribers do not block processing of operations, if the subscriber does not keep up the oldest pending change is dropped.
+type Broker struct {
+	mu            sync.Mutex
+	subscriptions map[*subscription]struct{}
+}
+
+type subscription struct {
+	filter Filter
+	events chan model.Operation
+	// delivered holds the last change delivered to the subscriber by the operation ID
+	delivered map[string]model.Operation
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscriptions: make(map[*subscription]struct{}),
+	}
+}
+
+func (b *Broker) Subscribe(ctx context.Context, filter Filter) <-chan model.Operation {
+	sub := &subscription{
+		filter:    filter,
+		events:    make(chan model.Operation, subscriptionBufferSize),
+		delivered: make(map[string]model.Operation),
+	}
+
+	b.mu.Lock()
+	b.subscriptions[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscriptions, sub)
+		close(sub.events)
+	}()
+
+	return sub.events
+}
+
+// HasSubscribers returns true if any subscriber is connected
+func (b *Broker) HasSubscribers() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.subscriptions) > 0
+}
+
+func (b *Broker) OperationChanged(operation model.Operation) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscriptions {
+		if !sub.filter.matches(operation) {
+			continue
+		}
+		if last, found := sub.delivered[operation.ID]; found && sameStatus(last, operation) {
+			continue
+		}
+		sub.delivered[operation.ID] = operation
+
+		select {
+		case sub.events <- operation:
+		default:
+			// The subscriber may drain the channel in the meantime so neither dropping nor resending can block
+			select {
+			case <-sub.events:
+			default:
+			}
+			select {
+			case sub.events <- operation:
+			default:
+			}
+		}
+	}
+}
+
+func sameStatus(a, b model.Operation) bool {
+	return a.State == b.State && a.Stage == b.Stage && a.Message == b.Message
+}
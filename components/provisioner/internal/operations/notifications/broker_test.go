@@ -0,0 +1,121 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker(t *testing.T) {
+
+	t.Run("should deliver only matching operations", func(t *testing.T) {
+		// given
+		broker := NewBroker()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		byRuntime := broker.Subscribe(ctx, Filter{RuntimeID: "runtime-1"})
+		byOperation := broker.Subscribe(ctx, Filter{OperationID: "operation-2"})
+
+		// when
+		broker.OperationChanged(model.Operation{ID: "operation-1", ClusterID: "runtime-1", Stage: model.StartingInstallation})
+		broker.OperationChanged(model.Operation{ID: "operation-2", ClusterID: "runtime-2", State: model.Succeeded})
+
+		// then
+		assert.Equal(t, "operation-1", (<-byRuntime).ID)
+		assert.Empty(t, byRuntime)
+		assert.Equal(t, "operation-2", (<-byOperation).ID)
+		assert.Empty(t, byOperation)
+	})
+
+	t.Run("should drop the oldest change when subscriber does not keep up", func(t *testing.T) {
+		// given
+		broker := NewBroker()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := broker.Subscribe(ctx, Filter{OperationID: "operation"})
+
+		// when
+		for i := 0; i < subscriptionBufferSize; i++ {
+			broker.OperationChanged(model.Operation{ID: "operation", State: model.InProgress, Message: fmt.Sprintf("step %d", i)})
+		}
+		broker.OperationChanged(model.Operation{ID: "operation", State: model.Succeeded})
+
+		// then
+		var last model.Operation
+		for i := 0; i < subscriptionBufferSize; i++ {
+			last = <-events
+		}
+		assert.Equal(t, model.Succeeded, last.State)
+	})
+
+	t.Run("should deliver the same change only once", func(t *testing.T) {
+		// given
+		broker := NewBroker()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := broker.Subscribe(ctx, Filter{OperationID: "operation"})
+
+		// when
+		broker.OperationChanged(model.Operation{ID: "operation", State: model.InProgress, Stage: model.StartingInstallation})
+		broker.OperationChanged(model.Operation{ID: "operation", State: model.InProgress, Stage: model.StartingInstallation})
+		broker.OperationChanged(model.Operation{ID: "operation", State: model.Succeeded, Stage: model.FinishedStage})
+
+		// then
+		assert.Equal(t, model.InProgress, (<-events).State)
+		assert.Equal(t, model.Succeeded, (<-events).State)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should not block when subscriber drains the channel concurrently", func(t *testing.T) {
+		// given
+		broker := NewBroker()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := broker.Subscribe(ctx, Filter{})
+		go func() {
+			for range events {
+			}
+		}()
+
+		done := make(chan struct{})
+
+		// when
+		go func() {
+			for i := 0; i < 10000; i++ {
+				broker.OperationChanged(model.Operation{ID: "operation"})
+			}
+			close(done)
+		}()
+
+		// then
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("notifying subscribers blocked")
+		}
+	})
+
+	t.Run("should close the channel when context is canceled", func(t *testing.T) {
+		// given
+		broker := NewBroker()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		events := broker.Subscribe(ctx, Filter{})
+
+		// when
+		cancel()
+
+		// then
+		_, open := <-events
+		assert.False(t, open)
+		broker.OperationChanged(model.Operation{ID: "operation"})
+	})
+}
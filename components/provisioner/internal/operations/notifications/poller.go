@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// PollerConfig holds settings of reading changes of operations processed by other replicas
+type PollerConfig struct {
+	PollInterval time.Duration `envconfig:"default=5s"`
+}
+
+// Poller periodically reads changes of operations stored by all Provisioner replicas and passes them to the Broker,
+// so subscribers connected to this replica receive changes of operations processed by other replicas.
+// Every poll reads also the changes from the previous interval, as the clocks of the replicas may differ
+// and changes may be committed with a delay, the Broker delivers the same change only once.
+type Poller struct {
+	readSession dbsession.ReadSession
+	broker      *Broker
+	interval    time.Duration
+
+	since time.Time
+	log   logrus.FieldLogger
+}
+
+func NewPoller(readSession dbsession.ReadSession, broker *Broker, interval time.Duration) *Poller {
+	return &Poller{
+		readSession: readSession,
+		broker:      broker,
+		interval:    interval,
+		since:       time.Now(),
+		log:         logrus.WithField("Component", "OperationsPoller"),
+	}
+}
+
+func (p *Poller) Run(stop <-chan struct{}) {
+	go wait.Until(p.poll, p.interval, stop)
+}
+
+func (p *Poller) poll() {
+	now := time.Now()
+	if !p.broker.HasSubscribers() {
+		p.since = now
+		return
+	}
+
+	operations, err := p.readSession.ListOperationsChangedSince(p.since.Add(-p.interval))
+	if err != nil {
+		p.log.Errorf("Failed to list changed operations: %s", err.Error())
+		return
+	}
+	p.since = now
+
+	for _, operation := range operations {
+		p.broker.OperationChanged(operation)
+	}
+}
@@ -0,0 +1,75 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoller(t *testing.T) {
+
+	t.Run("should pass changes of operations to subscribers", func(t *testing.T) {
+		// given
+		broker := NewBroker()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := broker.Subscribe(ctx, Filter{RuntimeID: "runtime"})
+
+		operation := model.Operation{ID: "operation", ClusterID: "runtime", State: model.Succeeded}
+
+		readSession := &sessionMocks.ReadSession{}
+		readSession.On("ListOperationsChangedSince", mock.AnythingOfType("time.Time")).Return([]model.Operation{operation}, nil)
+
+		poller := NewPoller(readSession, broker, time.Minute)
+
+		// when
+		poller.poll()
+		poller.poll()
+
+		// then
+		require.Len(t, events, 1)
+		assert.Equal(t, operation, <-events)
+		readSession.AssertNumberOfCalls(t, "ListOperationsChangedSince", 2)
+	})
+
+	t.Run("should read changes of previous interval", func(t *testing.T) {
+		// given
+		broker := NewBroker()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		broker.Subscribe(ctx, Filter{})
+
+		readSession := &sessionMocks.ReadSession{}
+		poller := NewPoller(readSession, broker, time.Minute)
+		since := poller.since
+
+		readSession.On("ListOperationsChangedSince", since.Add(-time.Minute)).Return([]model.Operation{}, nil)
+
+		// when
+		poller.poll()
+
+		// then
+		readSession.AssertExpectations(t)
+		assert.True(t, poller.since.After(since))
+	})
+
+	t.Run("should not read operations when there are no subscribers", func(t *testing.T) {
+		// given
+		readSession := &sessionMocks.ReadSession{}
+		poller := NewPoller(readSession, NewBroker(), time.Minute)
+
+		// when
+		poller.poll()
+
+		// then
+		readSession.AssertNotCalled(t, "ListOperationsChangedSince", mock.Anything)
+	})
+}
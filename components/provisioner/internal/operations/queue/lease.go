@@ -0,0 +1,176 @@
+package queue
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// LeaseConfig holds settings of operation leases which let several provisioner replicas share the work
+type LeaseConfig struct {
+	Owner           string        `envconfig:"optional"`
+	Duration        time.Duration `envconfig:"default=2m"`
+	ReclaimInterval time.Duration `envconfig:"default=1m"`
+}
+
+//go:generate mockery -name=OperationLeaser
+type OperationLeaser interface {
+	// Acquire leases the operation and returns the token of the lease, every acquisition gets a new token
+	// so that the operation is not executed twice even by workers of the same replica
+	Acquire(operationID string) (token string, acquired bool)
+	// KeepAlive renews the lease until the returned function is called
+	KeepAlive(operationID string, token string) (stop func())
+	// Hold extends the lease by the delay after which the operation is processed again, only the next acquisition
+	// of this leaser can take over the held lease
+	Hold(operationID string, token string, delay time.Duration)
+	Release(operationID string, token string)
+}
+
+type dbLeaser struct {
+	session  dbsession.WriteSession
+	owner    string
+	duration time.Duration
+
+	heldMutex sync.Mutex
+	held      map[string]string
+
+	log logrus.FieldLogger
+}
+
+// NewOperationLeaser returns OperationLeaser which stores leases of operations in the database
+func NewOperationLeaser(session dbsession.WriteSession, owner string, duration time.Duration) OperationLeaser {
+	return &dbLeaser{
+		session:  session,
+		owner:    owner,
+		duration: duration,
+		held:     map[string]string{},
+		log:      logrus.WithFields(logrus.Fields{"Component": "OperationLeaser", "Owner": owner}),
+	}
+}
+
+// Acquire leases the operation for the lease duration, returns false if the operation is leased by another
+// acquisition which is not expired and was not held for this leaser
+func (l *dbLeaser) Acquire(operationID string) (string, bool) {
+	now := time.Now()
+	token := fmt.Sprintf("%s/%s", l.owner, uuid.New().String())
+
+	acquired, err := l.session.AcquireOperationLease(operationID, token, l.takeHeld(operationID), now, now.Add(l.duration))
+	if err != nil {
+		l.log.Errorf("Failed to acquire lease of operation %s: %s", operationID, err.Error())
+		return "", false
+	}
+
+	return token, acquired
+}
+
+// KeepAlive renews the lease periodically before it expires, so the operation is not reclaimed by another replica
+// while it is executed. The returned function stops renewing and waits until the last renewal is finished.
+func (l *dbLeaser) KeepAlive(operationID string, token string) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(l.duration / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				l.renew(operationID, token, time.Now().Add(l.duration))
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+func (l *dbLeaser) Hold(operationID string, token string, delay time.Duration) {
+	if !l.renew(operationID, token, time.Now().Add(l.duration+delay)) {
+		return
+	}
+
+	l.heldMutex.Lock()
+	defer l.heldMutex.Unlock()
+	l.held[operationID] = token
+}
+
+// takeHeld returns the token of the held lease of the operation, the lease can be taken over only once
+func (l *dbLeaser) takeHeld(operationID string) string {
+	l.heldMutex.Lock()
+	defer l.heldMutex.Unlock()
+
+	token := l.held[operationID]
+	delete(l.held, operationID)
+	return token
+}
+
+func (l *dbLeaser) renew(operationID string, token string, expiration time.Time) bool {
+	renewed, err := l.session.RenewOperationLease(operationID, token, expiration)
+	switch {
+	case err != nil:
+		l.log.Errorf("Failed to renew lease of operation %s: %s", operationID, err.Error())
+	case !renewed:
+		l.log.Warnf("Lease of operation %s is not held anymore", operationID)
+	}
+
+	return err == nil && renewed
+}
+
+func (l *dbLeaser) Release(operationID string, token string) {
+	err := l.session.ReleaseOperationLease(operationID, token)
+	if err != nil {
+		l.log.Warnf("Failed to release lease of operation %s: %s", operationID, err.Error())
+	}
+}
+
+// LeaseReclaimer periodically enqueues In Progress operations which are not leased, for example because the replica processing them was stopped
+type LeaseReclaimer struct {
+	readSession dbsession.ReadSession
+	queues      map[model.OperationType]OperationQueue
+	interval    time.Duration
+
+	log logrus.FieldLogger
+}
+
+func NewLeaseReclaimer(readSession dbsession.ReadSession, queues map[model.OperationType]OperationQueue, interval time.Duration) *LeaseReclaimer {
+	return &LeaseReclaimer{
+		readSession: readSession,
+		queues:      queues,
+		interval:    interval,
+		log:         logrus.WithField("Component", "LeaseReclaimer"),
+	}
+}
+
+func (r *LeaseReclaimer) Run(stop <-chan struct{}) {
+	go wait.Until(r.reclaim, r.interval, stop)
+}
+
+func (r *LeaseReclaimer) reclaim() {
+	operations, err := r.readSession.ListUnleasedInProgressOperations(time.Now())
+	if err != nil {
+		r.log.Errorf("Failed to list unleased operations: %s", err.Error())
+		return
+	}
+
+	for _, operation := range operations {
+		queue, found := r.queues[operation.Type]
+		if !found {
+			continue
+		}
+
+		r.log.Infof("Enqueuing unleased operation %s of type %s", operation.ID, operation.Type)
+		Enqueue(queue, operation)
+	}
+}
@@ -0,0 +1,237 @@
+package queue
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/metrics"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	operationsMocks "github.com/kyma-project/control-plane/components/provisioner/internal/operations/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOperationLeaser_Acquire(t *testing.T) {
+	t.Run("should lease operation with new token for lease duration", func(t *testing.T) {
+		// given
+		writeSession := &sessionMocks.WriteSession{}
+		writeSession.On("AcquireOperationLease", "operation", mock.AnythingOfType("string"), "", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+			Return(func(_, holder, _ string, now, expiration time.Time) bool {
+				return strings.HasPrefix(holder, "replica-1/") && expiration.Sub(now) == 2*time.Minute
+			}, nil).Twice()
+
+		leaser := NewOperationLeaser(writeSession, "replica-1", 2*time.Minute)
+
+		// when
+		first, firstAcquired := leaser.Acquire("operation")
+		second, secondAcquired := leaser.Acquire("operation")
+
+		// then
+		assert.True(t, firstAcquired)
+		assert.True(t, secondAcquired)
+		assert.NotEqual(t, first, second)
+		writeSession.AssertExpectations(t)
+	})
+
+	t.Run("should not acquire lease when database fails", func(t *testing.T) {
+		// given
+		writeSession := &sessionMocks.WriteSession{}
+		writeSession.On("AcquireOperationLease", "operation", mock.AnythingOfType("string"), "", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+			Return(false, dberrors.Internal("error"))
+
+		leaser := NewOperationLeaser(writeSession, "replica-1", 2*time.Minute)
+
+		// when
+		_, acquired := leaser.Acquire("operation")
+
+		// then
+		assert.False(t, acquired)
+	})
+
+	t.Run("should not acquire operation executed by another worker of the same replica", func(t *testing.T) {
+		// given
+		leaser := NewOperationLeaser(&leaseStore{}, "replica-1", 2*time.Minute)
+
+		// when
+		_, regularAcquired := leaser.Acquire("operation")
+		_, priorityAcquired := leaser.Acquire("operation")
+
+		// then
+		assert.True(t, regularAcquired)
+		assert.False(t, priorityAcquired)
+	})
+
+	t.Run("should hand over held lease to a single acquisition", func(t *testing.T) {
+		// given
+		store := &leaseStore{}
+		leaser := NewOperationLeaser(store, "replica-1", 2*time.Minute)
+		token, _ := leaser.Acquire("operation")
+
+		// when
+		leaser.Hold("operation", token, time.Minute)
+		next, nextAcquired := leaser.Acquire("operation")
+		_, otherAcquired := leaser.Acquire("operation")
+
+		// then
+		assert.True(t, nextAcquired)
+		assert.False(t, otherAcquired)
+		assert.Equal(t, next, store.holder)
+	})
+
+	t.Run("should not hand over held lease to another replica", func(t *testing.T) {
+		// given
+		store := &leaseStore{}
+		leaser := NewOperationLeaser(store, "replica-1", 2*time.Minute)
+		token, _ := leaser.Acquire("operation")
+
+		// when
+		leaser.Hold("operation", token, time.Minute)
+		_, acquired := NewOperationLeaser(store, "replica-2", 2*time.Minute).Acquire("operation")
+
+		// then
+		assert.False(t, acquired)
+	})
+
+	t.Run("should acquire released lease", func(t *testing.T) {
+		// given
+		leaser := NewOperationLeaser(&leaseStore{}, "replica-1", 2*time.Minute)
+		token, _ := leaser.Acquire("operation")
+
+		// when
+		leaser.Release("operation", token)
+		_, acquired := leaser.Acquire("operation")
+
+		// then
+		assert.True(t, acquired)
+	})
+}
+
+// leaseStore keeps the lease of a single operation the same way as the operation table
+type leaseStore struct {
+	dbsession.WriteSession
+	holder     string
+	expiration time.Time
+}
+
+func (s *leaseStore) AcquireOperationLease(_ string, holder string, previousHolder string, now time.Time, expiration time.Time) (bool, dberrors.Error) {
+	if s.holder != "" && s.holder != previousHolder && !s.expiration.Before(now) {
+		return false, nil
+	}
+	s.holder, s.expiration = holder, expiration
+	return true, nil
+}
+
+func (s *leaseStore) RenewOperationLease(_ string, holder string, expiration time.Time) (bool, dberrors.Error) {
+	if s.holder != holder {
+		return false, nil
+	}
+	s.expiration = expiration
+	return true, nil
+}
+
+func (s *leaseStore) ReleaseOperationLease(_ string, holder string) dberrors.Error {
+	if s.holder == holder {
+		s.holder = ""
+	}
+	return nil
+}
+
+func TestOperationLeaser_KeepAlive(t *testing.T) {
+	t.Run("should renew lease until stopped", func(t *testing.T) {
+		// given
+		renewed := make(chan struct{}, 10)
+		writeSession := &sessionMocks.WriteSession{}
+		writeSession.On("RenewOperationLease", "operation", "token", mock.AnythingOfType("time.Time")).
+			Run(func(args mock.Arguments) {
+				renewed <- struct{}{}
+			}).
+			Return(true, nil)
+
+		leaser := NewOperationLeaser(writeSession, "replica-1", 30*time.Millisecond)
+
+		// when
+		stop := leaser.KeepAlive("operation", "token")
+		<-renewed
+		<-renewed
+		stop()
+
+		// then
+		calls := len(writeSession.Calls)
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, calls, len(writeSession.Calls))
+	})
+}
+
+func TestLeaseReclaimer_reclaim(t *testing.T) {
+	// given
+	readSession := &sessionMocks.ReadSession{}
+	readSession.On("ListUnleasedInProgressOperations", mock.AnythingOfType("time.Time")).Return([]model.Operation{
+		{ID: "provisioning", Type: model.Provision},
+		{ID: "deprovisioning", Type: model.Deprovision},
+		{ID: "reconnecting", Type: model.ReconnectRuntime},
+	}, nil)
+
+	provisioningQueue := &operationsMocks.OperationQueue{}
+	provisioningQueue.On("Add", "provisioning").Return()
+	deprovisioningQueue := &operationsMocks.OperationQueue{}
+	deprovisioningQueue.On("Add", "deprovisioning").Return()
+
+	reclaimer := NewLeaseReclaimer(readSession, map[model.OperationType]OperationQueue{
+		model.Provision:   provisioningQueue,
+		model.Deprovision: deprovisioningQueue,
+	}, time.Minute)
+
+	// when
+	reclaimer.reclaim()
+
+	// then
+	provisioningQueue.AssertExpectations(t)
+	deprovisioningQueue.AssertExpectations(t)
+	readSession.AssertExpectations(t)
+}
+
+func TestLeaseReclaimer_reclaimQueuedOperation(t *testing.T) {
+	// given
+	readSession := &sessionMocks.ReadSession{}
+	readSession.On("ListUnleasedInProgressOperations", mock.AnythingOfType("time.Time")).Return([]model.Operation{
+		{ID: "urgent", Type: model.Provision},
+	}, nil)
+
+	provisioningQueue := NewQueue(model.Provision, nil, Config{}, nil, nil)
+	provisioningQueue.AddPriority("urgent")
+
+	reclaimer := NewLeaseReclaimer(readSession, map[model.OperationType]OperationQueue{
+		model.Provision: provisioningQueue,
+	}, time.Minute)
+
+	// when
+	reclaimer.reclaim()
+
+	// then
+	assert.Equal(t, metrics.QueueDepth{Priority: 1}, provisioningQueue.Depth())
+}
+
+func TestLeaseReclaimer_reclaimUrgentOperation(t *testing.T) {
+	// given
+	readSession := &sessionMocks.ReadSession{}
+	readSession.On("ListUnleasedInProgressOperations", mock.AnythingOfType("time.Time")).Return([]model.Operation{
+		{ID: "urgent", Type: model.Provision, Urgent: true},
+		{ID: "regular", Type: model.Provision},
+	}, nil)
+
+	provisioningQueue := NewQueue(model.Provision, nil, Config{}, nil, nil)
+
+	reclaimer := NewLeaseReclaimer(readSession, map[model.OperationType]OperationQueue{
+		model.Provision: provisioningQueue,
+	}, time.Minute)
+
+	// when
+	reclaimer.reclaim()
+
+	// then
+	assert.Equal(t, metrics.QueueDepth{Regular: 1, Priority: 1}, provisioningQueue.Depth())
+}
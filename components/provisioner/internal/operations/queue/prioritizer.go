@@ -0,0 +1,43 @@
+package queue
+
+import (
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate mockery -name=Prioritizer
+type Prioritizer interface {
+	IsPriority(operationID string) bool
+}
+
+type tenantPrioritizer struct {
+	readSession dbsession.ReadSession
+	tenants     map[string]bool
+}
+
+// NewTenantPrioritizer returns Prioritizer which puts operations of the given tenants to the priority lane
+func NewTenantPrioritizer(readSession dbsession.ReadSession, tenants []string) Prioritizer {
+	tenantsSet := make(map[string]bool, len(tenants))
+	for _, tenant := range tenants {
+		tenantsSet[tenant] = true
+	}
+
+	return &tenantPrioritizer{
+		readSession: readSession,
+		tenants:     tenantsSet,
+	}
+}
+
+func (p *tenantPrioritizer) IsPriority(operationID string) bool {
+	if len(p.tenants) == 0 {
+		return false
+	}
+
+	tenant, err := p.readSession.GetTenantForOperation(operationID)
+	if err != nil {
+		logrus.Warnf("Failed to get tenant of operation %s, processing it without priority: %s", operationID, err.Error())
+		return false
+	}
+
+	return p.tenants[tenant]
+}
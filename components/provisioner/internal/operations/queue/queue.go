@@ -4,6 +4,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kyma-project/control-plane/components/provisioner/internal/metrics"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -13,39 +15,180 @@ import (
 //go:generate mockery -name=OperationQueue
 type OperationQueue interface {
 	Add(processId string)
+	AddPriority(processId string)
 	Run(stop <-chan struct{})
 }
 
-const (
-	workersAmount = 5
-)
+// Config holds the number of workers processing operations of a queue
+type Config struct {
+	Workers         int `envconfig:"default=5"`
+	PriorityWorkers int `envconfig:"default=2"`
+}
+
+// QueuesConfig holds settings of all operation queues
+type QueuesConfig struct {
+	Provisioning   Config
+	Deprovisioning Config
+	Upgrade        Config
+	ShootUpgrade   Config
+	Hibernation    Config
+	WakeUp         Config
+	Rollback       Config
+
+	PriorityTenants []string `envconfig:"optional"`
+}
+
+// Enqueue adds the operation to the priority lane of the queue if it is urgent
+func Enqueue(operationQueue OperationQueue, operation model.Operation) {
+	if operation.Urgent {
+		operationQueue.AddPriority(operation.ID)
+		return
+	}
+	operationQueue.Add(operation.ID)
+}
 
 type Executor interface {
 	Execute(operationID string) operations.ProcessingResult
 }
 
+// Queue processes operations of a single type, operations added to the priority lane are processed by dedicated workers so they do not wait for the regular ones
 type Queue struct {
-	queue    workqueue.RateLimitingInterface
-	executor Executor
+	operationType model.OperationType
+	queue         workqueue.RateLimitingInterface
+	priorityQueue workqueue.RateLimitingInterface
+	executor      Executor
+	prioritizer   Prioritizer
+	leaser        OperationLeaser
+	config        Config
+
+	// inFlight holds operations which are queued or processed in any lane, so that they are not added to the other lane
+	inFlightMutex sync.Mutex
+	inFlight      map[string]struct{}
 }
 
-func NewQueue(executor Executor) *Queue {
+func NewQueue(operationType model.OperationType, executor Executor, config Config, prioritizer Prioritizer, leaser OperationLeaser) *Queue {
 	return &Queue{
-		queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "operations"),
-		executor: executor,
+		operationType: operationType,
+		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "operations"),
+		priorityQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "priority-operations"),
+		executor:      executor,
+		prioritizer:   prioritizer,
+		leaser:        leaser,
+		config:        config,
+		inFlight:      map[string]struct{}{},
 	}
 }
 
+// Add enqueues the operation unless it is already queued or processed by this Queue
 func (q *Queue) Add(operationId string) {
+	if !q.track(operationId) {
+		return
+	}
+
+	if q.prioritizer != nil && q.prioritizer.IsPriority(operationId) {
+		q.priorityQueue.Add(operationId)
+		return
+	}
 	q.queue.Add(operationId)
 }
 
+// AddPriority enqueues the operation in the priority lane unless it is already queued or processed by this Queue
+func (q *Queue) AddPriority(operationId string) {
+	if !q.track(operationId) {
+		return
+	}
+
+	q.priorityQueue.Add(operationId)
+}
+
+// track marks the operation as in flight, returns false if it already is
+func (q *Queue) track(operationID string) bool {
+	q.inFlightMutex.Lock()
+	defer q.inFlightMutex.Unlock()
+
+	if _, found := q.inFlight[operationID]; found {
+		logrus.Debugf("Operation %s is already queued, skipping", operationID)
+		return false
+	}
+	q.inFlight[operationID] = struct{}{}
+	return true
+}
+
+func (q *Queue) untrack(operationID string) {
+	q.inFlightMutex.Lock()
+	defer q.inFlightMutex.Unlock()
+
+	delete(q.inFlight, operationID)
+}
+
+// Depth returns the number of operations waiting in the queue
+func (q *Queue) Depth() metrics.QueueDepth {
+	return metrics.QueueDepth{
+		Regular:  q.queue.Len(),
+		Priority: q.priorityQueue.Len(),
+	}
+}
+
 func (q *Queue) Run(stop <-chan struct{}) {
 	var waitGroup sync.WaitGroup
 
-	for i := 0; i < workersAmount; i++ {
-		createWorker(q.queue, q.executor.Execute, stop, &waitGroup)
+	for i := 0; i < q.config.Workers; i++ {
+		createWorker(q.queue, q.process, stop, &waitGroup)
+	}
+	for i := 0; i < q.config.PriorityWorkers; i++ {
+		createWorker(q.priorityQueue, q.process, stop, &waitGroup)
+	}
+}
+
+// process executes the operation, the operation stays in flight only if it is requeued
+func (q *Queue) process(operationID string) (result operations.ProcessingResult) {
+	defer func() {
+		if !result.Requeue {
+			q.untrack(operationID)
+		}
+	}()
+
+	if q.leaser == nil {
+		return q.execute(operationID)
 	}
+
+	return q.processLeased(operationID)
+}
+
+// processLeased executes the operation only if its lease is acquired so that the operation is not processed by several workers at the same time
+func (q *Queue) processLeased(operationID string) operations.ProcessingResult {
+	token, acquired := q.leaser.Acquire(operationID)
+	if !acquired {
+		logrus.Debugf("Operation %s is leased by another worker, skipping", operationID)
+		return operations.ProcessingResult{Requeue: false}
+	}
+
+	result := q.executeLeased(operationID, token)
+	if result.Requeue {
+		q.leaser.Hold(operationID, token, result.Delay)
+	} else {
+		q.leaser.Release(operationID, token)
+	}
+
+	return result
+}
+
+// executeLeased keeps the lease of the operation alive while the operation is executed, because the execution
+// of a single step can take longer than the lease duration
+func (q *Queue) executeLeased(operationID string, token string) operations.ProcessingResult {
+	stopRenewal := q.leaser.KeepAlive(operationID, token)
+	defer stopRenewal()
+
+	return q.execute(operationID)
+}
+
+func (q *Queue) execute(operationID string) operations.ProcessingResult {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveOperationProcessing(q.operationType, time.Since(start))
+	}()
+
+	return q.executor.Execute(operationID)
 }
 
 func createWorker(queue workqueue.RateLimitingInterface, process func(id string) operations.ProcessingResult, stopCh <-chan struct{}, waitGroup *sync.WaitGroup) {
@@ -0,0 +1,203 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/metrics"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQueue_Add(t *testing.T) {
+	t.Run("should add operations to the lane chosen by prioritizer", func(t *testing.T) {
+		// given
+		prioritizer := &mocks.Prioritizer{}
+		prioritizer.On("IsPriority", "regular").Return(false)
+		prioritizer.On("IsPriority", "priority").Return(true)
+
+		queue := NewQueue(model.Provision, nil, Config{}, prioritizer, nil)
+
+		// when
+		queue.Add("regular")
+		queue.Add("priority")
+		queue.AddPriority("urgent")
+
+		// then
+		assert.Equal(t, metrics.QueueDepth{Regular: 1, Priority: 2}, queue.Depth())
+		prioritizer.AssertExpectations(t)
+	})
+
+	t.Run("should not add operation which is already queued in the other lane", func(t *testing.T) {
+		// given
+		queue := NewQueue(model.Provision, nil, Config{}, nil, nil)
+
+		// when
+		queue.AddPriority("urgent")
+		queue.Add("urgent")
+
+		// then
+		assert.Equal(t, metrics.QueueDepth{Priority: 1}, queue.Depth())
+	})
+
+	t.Run("should add operations to the regular lane when there is no prioritizer", func(t *testing.T) {
+		// given
+		queue := NewQueue(model.Provision, nil, Config{}, nil, nil)
+
+		// when
+		queue.Add("regular")
+
+		// then
+		assert.Equal(t, metrics.QueueDepth{Regular: 1}, queue.Depth())
+	})
+}
+
+func TestTenantPrioritizer_IsPriority(t *testing.T) {
+	t.Run("should prioritize operations of configured tenants", func(t *testing.T) {
+		// given
+		readSession := &sessionMocks.ReadSession{}
+		readSession.On("GetTenantForOperation", "operation-1").Return("vip", nil)
+		readSession.On("GetTenantForOperation", "operation-2").Return("tenant", nil)
+		readSession.On("GetTenantForOperation", "operation-3").Return("", dberrors.NotFound("not found"))
+
+		prioritizer := NewTenantPrioritizer(readSession, []string{"vip"})
+
+		// then
+		assert.True(t, prioritizer.IsPriority("operation-1"))
+		assert.False(t, prioritizer.IsPriority("operation-2"))
+		assert.False(t, prioritizer.IsPriority("operation-3"))
+		readSession.AssertExpectations(t)
+	})
+
+	t.Run("should not read tenant when no tenants are configured", func(t *testing.T) {
+		// given
+		readSession := &sessionMocks.ReadSession{}
+
+		prioritizer := NewTenantPrioritizer(readSession, nil)
+
+		// then
+		assert.False(t, prioritizer.IsPriority("operation-1"))
+		readSession.AssertNotCalled(t, "GetTenantForOperation", "operation-1")
+	})
+}
+
+type executorStub struct {
+	result   operations.ProcessingResult
+	executed []string
+}
+
+func (e *executorStub) Execute(operationID string) operations.ProcessingResult {
+	e.executed = append(e.executed, operationID)
+	return e.result
+}
+
+func TestQueue_process(t *testing.T) {
+	t.Run("should skip operation leased by another replica", func(t *testing.T) {
+		// given
+		executor := &executorStub{}
+		leaser := &mocks.OperationLeaser{}
+		leaser.On("Acquire", "operation").Return("", false)
+
+		queue := NewQueue(model.Provision, executor, Config{}, nil, leaser)
+
+		// when
+		result := queue.process("operation")
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.Empty(t, executor.executed)
+		leaser.AssertExpectations(t)
+	})
+
+	t.Run("should extend lease of requeued operation", func(t *testing.T) {
+		// given
+		executor := &executorStub{result: operations.ProcessingResult{Requeue: true, Delay: 30 * time.Second}}
+		leaser := &mocks.OperationLeaser{}
+		leaser.On("Acquire", "operation").Return("token", true)
+		leaser.On("KeepAlive", "operation", "token").Return(func() {})
+		leaser.On("Hold", "operation", "token", 30*time.Second).Return()
+
+		queue := NewQueue(model.Provision, executor, Config{}, nil, leaser)
+
+		// when
+		result := queue.process("operation")
+
+		// then
+		assert.True(t, result.Requeue)
+		assert.Equal(t, []string{"operation"}, executor.executed)
+		leaser.AssertExpectations(t)
+		leaser.AssertNotCalled(t, "Release", "operation", "token")
+	})
+
+	t.Run("should release lease of processed operation", func(t *testing.T) {
+		// given
+		executor := &executorStub{result: operations.ProcessingResult{Requeue: false}}
+		leaser := &mocks.OperationLeaser{}
+		leaser.On("Acquire", "operation").Return("token", true)
+		leaser.On("KeepAlive", "operation", "token").Return(func() {})
+		leaser.On("Release", "operation", "token").Return()
+
+		queue := NewQueue(model.Provision, executor, Config{}, nil, leaser)
+
+		// when
+		result := queue.process("operation")
+
+		// then
+		assert.False(t, result.Requeue)
+		assert.Equal(t, []string{"operation"}, executor.executed)
+		leaser.AssertExpectations(t)
+	})
+
+	t.Run("should let operation be added again only after it was processed", func(t *testing.T) {
+		// given
+		executor := &executorStub{result: operations.ProcessingResult{Requeue: true}}
+		queue := NewQueue(model.Provision, executor, Config{}, nil, nil)
+		queue.Add("operation")
+
+		// when
+		queue.process("operation")
+		queue.AddPriority("operation")
+
+		// then
+		assert.Equal(t, metrics.QueueDepth{Regular: 1}, queue.Depth())
+
+		// when
+		executor.result = operations.ProcessingResult{Requeue: false}
+		queue.process("operation")
+		queue.AddPriority("operation")
+
+		// then
+		assert.Equal(t, metrics.QueueDepth{Regular: 1, Priority: 1}, queue.Depth())
+	})
+
+	t.Run("should keep lease alive while operation is executed", func(t *testing.T) {
+		// given
+		executor := &executorStub{result: operations.ProcessingResult{Requeue: false}}
+		renewing := false
+		leaser := &mocks.OperationLeaser{}
+		leaser.On("Acquire", "operation").Return("token", true)
+		leaser.On("KeepAlive", "operation", "token").Return(func(string, string) func() {
+			renewing = true
+			return func() {
+				renewing = false
+			}
+		})
+		leaser.On("Release", "operation", "token").Run(func(args mock.Arguments) {
+			assert.False(t, renewing, "lease is renewed after the operation was executed")
+		}).Return()
+
+		queue := NewQueue(model.Provision, executor, Config{}, nil, leaser)
+
+		// when
+		queue.process("operation")
+
+		// then
+		assert.False(t, renewing)
+		leaser.AssertExpectations(t)
+	})
+}
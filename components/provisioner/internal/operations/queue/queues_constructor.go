@@ -11,6 +11,7 @@ import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/deprovisioning"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/hibernation"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/provisioning"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/shootupgrade"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/upgrade"
@@ -29,6 +30,7 @@ type ProvisioningTimeouts struct {
 	Upgrade                time.Duration `envconfig:"default=60m"`
 	ShootUpgrade           time.Duration `envconfig:"default=30m"`
 	ShootRefresh           time.Duration `envconfig:"default=5m"`
+	ShootHibernation       time.Duration `envconfig:"default=30m"`
 	AgentConfiguration     time.Duration `envconfig:"default=15m"`
 	AgentConnection        time.Duration `envconfig:"default=15m"`
 }
@@ -49,7 +51,11 @@ func CreateProvisioningQueue(
 	shootClient gardener_apis.ShootInterface,
 	secretsClient v1core.SecretInterface,
 	operatorRoleBindingConfig provisioning.OperatorRoleBinding,
-	k8sClientProvider k8s.K8sClientProvider) OperationQueue {
+	k8sClientProvider k8s.K8sClientProvider,
+	notifier operations.OperationNotifier,
+	config Config,
+	prioritizer Prioritizer,
+	leaser OperationLeaser) *Queue {
 
 	waitForAgentToConnectStep := provisioning.NewWaitForAgentToConnectStep(ccClientConstructor, model.FinishedStage, timeouts.AgentConnection, directorClient)
 	configureAgentStep := provisioning.NewConnectAgentStep(configurator, waitForAgentToConnectStep.Name(), timeouts.AgentConfiguration)
@@ -75,16 +81,22 @@ func CreateProvisioningQueue(
 		provisionSteps,
 		failure.NewNoopFailureHandler(),
 		directorClient,
+		notifier,
 	)
 
-	return NewQueue(provisioningExecutor)
+	return NewQueue(model.Provision, provisioningExecutor, config, prioritizer, leaser)
 }
 
 func CreateUpgradeQueue(
 	timeouts ProvisioningTimeouts,
 	factory dbsession.Factory,
 	directorClient director.DirectorClient,
-	installationClient installation.Service) OperationQueue {
+	installationClient installation.Service,
+	notifier operations.OperationNotifier,
+	config Config,
+	prioritizer Prioritizer,
+	leaser OperationLeaser,
+	rollbackStarter failure.RollbackStarter) *Queue {
 
 	updatingUpgradeStep := upgrade.NewUpdateUpgradeStateStep(factory.NewWriteSession(), model.FinishedStage, 5*time.Minute)
 	waitForInstallStep := provisioning.NewWaitForInstallationStep(installationClient, updatingUpgradeStep.Name(), timeouts.Installation)
@@ -99,11 +111,43 @@ func CreateUpgradeQueue(
 	upgradeExecutor := operations.NewExecutor(factory.NewReadWriteSession(),
 		model.Upgrade,
 		upgradeSteps,
-		failure.NewUpgradeFailureHandler(factory.NewWriteSession()),
+		failure.NewUpgradeFailureHandler(factory.NewWriteSession(), rollbackStarter),
 		directorClient,
+		notifier,
 	)
 
-	return NewQueue(upgradeExecutor)
+	return NewQueue(model.Upgrade, upgradeExecutor, config, prioritizer, leaser)
+}
+
+func CreateRollbackQueue(
+	timeouts ProvisioningTimeouts,
+	factory dbsession.Factory,
+	directorClient director.DirectorClient,
+	installationClient installation.Service,
+	notifier operations.OperationNotifier,
+	config Config,
+	prioritizer Prioritizer,
+	leaser OperationLeaser) *Queue {
+
+	updatingUpgradeStep := upgrade.NewUpdateUpgradeStateStep(factory.NewWriteSession(), model.FinishedStage, 5*time.Minute)
+	waitForInstallStep := provisioning.NewWaitForInstallationStep(installationClient, updatingUpgradeStep.Name(), timeouts.Installation)
+	rollbackStep := upgrade.NewRollbackKymaStep(installationClient, waitForInstallStep.Name(), timeouts.Upgrade)
+
+	rollbackSteps := map[model.OperationStage]operations.Step{
+		model.UpdatingUpgradeState:   updatingUpgradeStep,
+		model.WaitingForInstallation: waitForInstallStep,
+		model.StartingRollback:       rollbackStep,
+	}
+
+	rollbackExecutor := operations.NewExecutor(factory.NewReadWriteSession(),
+		model.Rollback,
+		rollbackSteps,
+		failure.NewUpgradeFailureHandler(factory.NewWriteSession(), nil),
+		directorClient,
+		notifier,
+	)
+
+	return NewQueue(model.Rollback, rollbackExecutor, config, prioritizer, leaser)
 }
 
 func CreateDeprovisioningQueue(
@@ -112,7 +156,11 @@ func CreateDeprovisioningQueue(
 	installationClient installation.Service,
 	directorClient director.DirectorClient,
 	shootClient gardener_apis.ShootInterface,
-	deleteDelay time.Duration) OperationQueue {
+	deleteDelay time.Duration,
+	notifier operations.OperationNotifier,
+	config Config,
+	prioritizer Prioritizer,
+	leaser OperationLeaser) *Queue {
 
 	waitForClusterDeletion := deprovisioning.NewWaitForClusterDeletionStep(shootClient, factory, directorClient, model.FinishedStage, timeouts.WaitingForClusterDeletion)
 	deleteCluster := deprovisioning.NewDeleteClusterStep(shootClient, waitForClusterDeletion.Name(), timeouts.ClusterDeletion)
@@ -132,16 +180,21 @@ func CreateDeprovisioningQueue(
 		deprovisioningSteps,
 		failure.NewNoopFailureHandler(),
 		directorClient,
+		notifier,
 	)
 
-	return NewQueue(deprovisioningExecutor)
+	return NewQueue(model.Deprovision, deprovisioningExecutor, config, prioritizer, leaser)
 }
 
 func CreateShootUpgradeQueue(
 	timeouts ProvisioningTimeouts,
 	factory dbsession.Factory,
 	directorClient director.DirectorClient,
-	shootClient gardener_apis.ShootInterface) OperationQueue {
+	shootClient gardener_apis.ShootInterface,
+	notifier operations.OperationNotifier,
+	config Config,
+	prioritizer Prioritizer,
+	leaser OperationLeaser) *Queue {
 
 	waitForShootUpgrade := shootupgrade.NewWaitForShootUpgradeStep(shootClient, model.FinishedStage, timeouts.ShootUpgrade)
 	waitForShootNewVersion := shootupgrade.NewWaitForShootNewVersionStep(shootClient, waitForShootUpgrade.Name(), timeouts.ShootRefresh)
@@ -157,7 +210,68 @@ func CreateShootUpgradeQueue(
 		upgradeSteps,
 		failure.NewNoopFailureHandler(),
 		directorClient,
+		notifier,
+	)
+
+	return NewQueue(model.UpgradeShoot, upgradeClusterExecutor, config, prioritizer, leaser)
+}
+
+func CreateHibernationQueue(
+	timeouts ProvisioningTimeouts,
+	factory dbsession.Factory,
+	directorClient director.DirectorClient,
+	shootClient gardener_apis.ShootInterface,
+	notifier operations.OperationNotifier,
+	config Config,
+	prioritizer Prioritizer,
+	leaser OperationLeaser) *Queue {
+
+	waitForShootHibernation := hibernation.NewWaitForShootHibernationStep(shootClient, factory.NewWriteSession(), model.FinishedStage, timeouts.ShootHibernation)
+	hibernateShoot := hibernation.NewHibernateShootStep(shootClient, waitForShootHibernation.Name(), 5*time.Minute)
+
+	hibernationSteps := map[model.OperationStage]operations.Step{
+		model.StartingShootHibernation:   hibernateShoot,
+		model.WaitingForShootHibernation: waitForShootHibernation,
+	}
+
+	hibernationExecutor := operations.NewExecutor(
+		factory.NewReadWriteSession(),
+		model.Hibernate,
+		hibernationSteps,
+		failure.NewNoopFailureHandler(),
+		directorClient,
+		notifier,
+	)
+
+	return NewQueue(model.Hibernate, hibernationExecutor, config, prioritizer, leaser)
+}
+
+func CreateWakeUpQueue(
+	timeouts ProvisioningTimeouts,
+	factory dbsession.Factory,
+	directorClient director.DirectorClient,
+	shootClient gardener_apis.ShootInterface,
+	notifier operations.OperationNotifier,
+	config Config,
+	prioritizer Prioritizer,
+	leaser OperationLeaser) *Queue {
+
+	waitForShootWakeUp := hibernation.NewWaitForShootWakeUpStep(shootClient, factory.NewWriteSession(), model.FinishedStage, timeouts.ShootHibernation)
+	wakeUpShoot := hibernation.NewWakeUpShootStep(shootClient, waitForShootWakeUp.Name(), 5*time.Minute)
+
+	wakeUpSteps := map[model.OperationStage]operations.Step{
+		model.StartingShootWakeUp:   wakeUpShoot,
+		model.WaitingForShootWakeUp: waitForShootWakeUp,
+	}
+
+	wakeUpExecutor := operations.NewExecutor(
+		factory.NewReadWriteSession(),
+		model.WakeUp,
+		wakeUpSteps,
+		failure.NewNoopFailureHandler(),
+		directorClient,
+		notifier,
 	)
 
-	return NewQueue(upgradeClusterExecutor)
+	return NewQueue(model.WakeUp, wakeUpExecutor, config, prioritizer, leaser)
 }
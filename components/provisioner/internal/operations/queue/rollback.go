@@ -0,0 +1,96 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
+)
+
+type rollbackStarter struct {
+	factory       dbsession.Factory
+	uuidGenerator uuid.UUIDGenerator
+	rollbackQueue OperationQueue
+}
+
+// NewRollbackStarter returns RollbackStarter which restores the Kyma config active before the upgrade and enqueues Rollback operation applying it on the cluster
+func NewRollbackStarter(factory dbsession.Factory, uuidGenerator uuid.UUIDGenerator, rollbackQueue OperationQueue) failure.RollbackStarter {
+	return &rollbackStarter{
+		factory:       factory,
+		uuidGenerator: uuidGenerator,
+		rollbackQueue: rollbackQueue,
+	}
+}
+
+func (s *rollbackStarter) StartRollback(runtimeID string, upgradeOperationID string) (model.Operation, error) {
+	runtimeUpgrade, dberr := s.factory.NewReadSession().GetRuntimeUpgrade(upgradeOperationID)
+	if dberr != nil {
+		return model.Operation{}, dberr.Append("Failed to get Runtime Upgrade")
+	}
+
+	txSession, dberr := s.factory.NewSessionWithinTransaction()
+	if dberr != nil {
+		return model.Operation{}, dberr.Append("Failed to start transaction")
+	}
+	defer txSession.RollbackUnlessCommitted()
+
+	operation, dberr := s.setRollbackStarted(txSession, runtimeID, runtimeUpgrade)
+	if dberr != nil {
+		return model.Operation{}, dberr
+	}
+
+	dberr = txSession.Commit()
+	if dberr != nil {
+		return model.Operation{}, dberr.Append("Failed to commit rollback transaction")
+	}
+
+	s.rollbackQueue.Add(operation.ID)
+
+	return operation, nil
+}
+
+func (s *rollbackStarter) setRollbackStarted(txSession dbsession.WriteSession, runtimeID string, runtimeUpgrade model.RuntimeUpgrade) (model.Operation, dberrors.Error) {
+	timestamp := time.Now()
+
+	operation := model.Operation{
+		ID:             s.uuidGenerator.New(),
+		Type:           model.Rollback,
+		StartTimestamp: timestamp,
+		State:          model.InProgress,
+		Message:        "Starting Kyma upgrade rollback",
+		ClusterID:      runtimeID,
+		Stage:          model.StartingRollback,
+		LastTransition: &timestamp,
+	}
+
+	err := txSession.InsertOperation(operation)
+	if err != nil {
+		return model.Operation{}, err.Append("Failed to insert operation")
+	}
+
+	err = txSession.InsertRuntimeUpgrade(model.RuntimeUpgrade{
+		Id:                      s.uuidGenerator.New(),
+		State:                   model.UpgradeInProgress,
+		OperationId:             operation.ID,
+		PreUpgradeKymaConfigId:  runtimeUpgrade.PostUpgradeKymaConfigId,
+		PostUpgradeKymaConfigId: runtimeUpgrade.PreUpgradeKymaConfigId,
+	})
+	if err != nil {
+		return model.Operation{}, err.Append("Failed to insert Runtime Upgrade")
+	}
+
+	err = txSession.SetActiveKymaConfig(runtimeID, runtimeUpgrade.PreUpgradeKymaConfigId)
+	if err != nil {
+		return model.Operation{}, err.Append("Failed to restore Kyma config")
+	}
+
+	err = txSession.UpdateUpgradeState(runtimeUpgrade.OperationId, model.UpgradeRolledBack)
+	if err != nil {
+		return model.Operation{}, err.Append("Failed to update upgrade state")
+	}
+
+	return operation, nil
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	operationsMocks "github.com/kyma-project/control-plane/components/provisioner/internal/operations/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	uuidMocks "github.com/kyma-project/control-plane/components/provisioner/internal/uuid/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rollbackRuntimeID          = "runtime"
+	rollbackUpgradeOperationID = "upgrade-operation"
+	rollbackOperationID        = "rollback-operation"
+)
+
+func TestRollbackStarter_StartRollback(t *testing.T) {
+	runtimeUpgrade := model.RuntimeUpgrade{
+		Id:                      "runtime-upgrade",
+		State:                   model.UpgradeFailed,
+		OperationId:             rollbackUpgradeOperationID,
+		PreUpgradeKymaConfigId:  "pre-upgrade-config",
+		PostUpgradeKymaConfigId: "post-upgrade-config",
+	}
+
+	t.Run("should restore previous Kyma config and enqueue rollback operation", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+		uuidGenerator := &uuidMocks.UUIDGenerator{}
+		rollbackQueue := &operationsMocks.OperationQueue{}
+
+		uuidGenerator.On("New").Return(rollbackOperationID)
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetRuntimeUpgrade", rollbackUpgradeOperationID).Return(runtimeUpgrade, nil)
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("InsertOperation", mock.MatchedBy(func(operation model.Operation) bool {
+			return operation.Type == model.Rollback && operation.Stage == model.StartingRollback &&
+				operation.State == model.InProgress && operation.ClusterID == rollbackRuntimeID
+		})).Return(nil)
+		writeSession.On("InsertRuntimeUpgrade", mock.MatchedBy(func(upgrade model.RuntimeUpgrade) bool {
+			return upgrade.OperationId == rollbackOperationID &&
+				upgrade.PreUpgradeKymaConfigId == "post-upgrade-config" &&
+				upgrade.PostUpgradeKymaConfigId == "pre-upgrade-config"
+		})).Return(nil)
+		writeSession.On("SetActiveKymaConfig", rollbackRuntimeID, "pre-upgrade-config").Return(nil)
+		writeSession.On("UpdateUpgradeState", rollbackUpgradeOperationID, model.UpgradeRolledBack).Return(nil)
+		writeSession.On("Commit").Return(nil)
+		writeSession.On("RollbackUnlessCommitted").Return()
+		rollbackQueue.On("Add", rollbackOperationID).Return()
+
+		starter := NewRollbackStarter(sessionFactory, uuidGenerator, rollbackQueue)
+
+		// when
+		operation, err := starter.StartRollback(rollbackRuntimeID, rollbackUpgradeOperationID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, rollbackOperationID, operation.ID)
+		assert.Equal(t, model.Rollback, operation.Type)
+		sessionFactory.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+		rollbackQueue.AssertExpectations(t)
+	})
+
+	t.Run("should not enqueue rollback operation when failed to commit transaction", func(t *testing.T) {
+		// given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+		uuidGenerator := &uuidMocks.UUIDGenerator{}
+		rollbackQueue := &operationsMocks.OperationQueue{}
+
+		uuidGenerator.On("New").Return(rollbackOperationID)
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetRuntimeUpgrade", rollbackUpgradeOperationID).Return(runtimeUpgrade, nil)
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("InsertOperation", mock.AnythingOfType("model.Operation")).Return(nil)
+		writeSession.On("InsertRuntimeUpgrade", mock.AnythingOfType("model.RuntimeUpgrade")).Return(nil)
+		writeSession.On("SetActiveKymaConfig", rollbackRuntimeID, "pre-upgrade-config").Return(nil)
+		writeSession.On("UpdateUpgradeState", rollbackUpgradeOperationID, model.UpgradeRolledBack).Return(nil)
+		writeSession.On("Commit").Return(dberrors.Internal("error"))
+		writeSession.On("RollbackUnlessCommitted").Return()
+
+		starter := NewRollbackStarter(sessionFactory, uuidGenerator, rollbackQueue)
+
+		// when
+		_, err := starter.StartRollback(rollbackRuntimeID, rollbackUpgradeOperationID)
+
+		// then
+		require.Error(t, err)
+		writeSession.AssertExpectations(t)
+		rollbackQueue.AssertNotCalled(t, "Add", mock.Anything)
+	})
+}
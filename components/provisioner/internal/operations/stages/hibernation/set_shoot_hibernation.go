@@ -0,0 +1,80 @@
+package hibernation
+
+import (
+	"context"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//go:generate mockery -name=GardenerClient
+type GardenerClient interface {
+	Get(ctx context.Context, name string, options v1.GetOptions) (*gardener_types.Shoot, error)
+	Update(ctx context.Context, shoot *gardener_types.Shoot, options v1.UpdateOptions) (*gardener_types.Shoot, error)
+}
+
+type SetShootHibernationStep struct {
+	gardenerClient GardenerClient
+	name           model.OperationStage
+	hibernate      bool
+	nextStep       model.OperationStage
+	timeLimit      time.Duration
+}
+
+func NewHibernateShootStep(gardenerClient GardenerClient, nextStep model.OperationStage, timeLimit time.Duration) *SetShootHibernationStep {
+	return &SetShootHibernationStep{
+		gardenerClient: gardenerClient,
+		name:           model.StartingShootHibernation,
+		hibernate:      true,
+		nextStep:       nextStep,
+		timeLimit:      timeLimit,
+	}
+}
+
+func NewWakeUpShootStep(gardenerClient GardenerClient, nextStep model.OperationStage, timeLimit time.Duration) *SetShootHibernationStep {
+	return &SetShootHibernationStep{
+		gardenerClient: gardenerClient,
+		name:           model.StartingShootWakeUp,
+		hibernate:      false,
+		nextStep:       nextStep,
+		timeLimit:      timeLimit,
+	}
+}
+
+func (s *SetShootHibernationStep) Name() model.OperationStage {
+	return s.name
+}
+
+func (s *SetShootHibernationStep) TimeLimit() time.Duration {
+	return s.timeLimit
+}
+
+func (s *SetShootHibernationStep) Run(cluster model.Cluster, _ model.Operation, logger logrus.FieldLogger) (operations.StageResult, error) {
+	shoot, err := s.gardenerClient.Get(context.Background(), cluster.ClusterConfig.Name, v1.GetOptions{})
+	if err != nil {
+		return operations.StageResult{}, util.K8SErrorToAppError(err).Append("error getting Shoot")
+	}
+
+	if shoot.Spec.Hibernation == nil {
+		shoot.Spec.Hibernation = &gardener_types.Hibernation{}
+	}
+
+	if shoot.Spec.Hibernation.Enabled != nil && *shoot.Spec.Hibernation.Enabled == s.hibernate {
+		logger.Infof("Shoot hibernation already set to %t", s.hibernate)
+		return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+	}
+
+	shoot.Spec.Hibernation.Enabled = &s.hibernate
+
+	_, err = s.gardenerClient.Update(context.Background(), shoot, v1.UpdateOptions{})
+	if err != nil {
+		return operations.StageResult{}, util.K8SErrorToAppError(err).Append("error updating Shoot hibernation")
+	}
+
+	return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+}
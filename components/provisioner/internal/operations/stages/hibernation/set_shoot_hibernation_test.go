@@ -0,0 +1,100 @@
+package hibernation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	gardener_mocks "github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/hibernation/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util/testkit"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetShootHibernationStep_Run(t *testing.T) {
+	clusterName := "shootName"
+
+	cluster := model.Cluster{
+		ID: "runtimeID",
+		ClusterConfig: model.GardenerConfig{
+			Name: clusterName,
+		},
+	}
+
+	hibernationEnabled := func(enabled bool) interface{} {
+		return mock.MatchedBy(func(shoot *gardener_types.Shoot) bool {
+			return shoot.Spec.Hibernation != nil && shoot.Spec.Hibernation.Enabled != nil && *shoot.Spec.Hibernation.Enabled == enabled
+		})
+	}
+
+	t.Run("should enable hibernation of the Shoot", func(t *testing.T) {
+		// given
+		gardenerClient := &gardener_mocks.GardenerClient{}
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(testkit.NewTestShoot(clusterName).ToShoot(), nil)
+		gardenerClient.On("Update", context.Background(), hibernationEnabled(true), mock.Anything).Return(nil, nil)
+
+		step := NewHibernateShootStep(gardenerClient, model.WaitingForShootHibernation, time.Minute)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.WaitingForShootHibernation, result.Stage)
+		assert.Equal(t, time.Duration(0), result.Delay)
+		gardenerClient.AssertExpectations(t)
+	})
+
+	t.Run("should disable hibernation of the Shoot", func(t *testing.T) {
+		// given
+		gardenerClient := &gardener_mocks.GardenerClient{}
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(testkit.NewTestShoot(clusterName).WithHibernationEnabled(true).ToShoot(), nil)
+		gardenerClient.On("Update", context.Background(), hibernationEnabled(false), mock.Anything).Return(nil, nil)
+
+		step := NewWakeUpShootStep(gardenerClient, model.WaitingForShootWakeUp, time.Minute)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.WaitingForShootWakeUp, result.Stage)
+		gardenerClient.AssertExpectations(t)
+	})
+
+	t.Run("should not update the Shoot if hibernation is already set", func(t *testing.T) {
+		// given
+		gardenerClient := &gardener_mocks.GardenerClient{}
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(testkit.NewTestShoot(clusterName).WithHibernationEnabled(true).ToShoot(), nil)
+
+		step := NewHibernateShootStep(gardenerClient, model.WaitingForShootHibernation, time.Minute)
+
+		// when
+		result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.WaitingForShootHibernation, result.Stage)
+		gardenerClient.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error if failed to update the Shoot", func(t *testing.T) {
+		// given
+		gardenerClient := &gardener_mocks.GardenerClient{}
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(testkit.NewTestShoot(clusterName).ToShoot(), nil)
+		gardenerClient.On("Update", context.Background(), mock.Anything, mock.Anything).Return(nil, errors.New("some error"))
+
+		step := NewHibernateShootStep(gardenerClient, model.WaitingForShootHibernation, time.Minute)
+
+		// when
+		_, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.Error(t, err)
+	})
+}
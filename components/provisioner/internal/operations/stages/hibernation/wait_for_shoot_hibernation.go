@@ -0,0 +1,84 @@
+package hibernation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type WaitForShootHibernationStep struct {
+	gardenerClient GardenerClient
+	dbSession      dbsession.WriteSession
+	name           model.OperationStage
+	hibernated     bool
+	nextStep       model.OperationStage
+	timeLimit      time.Duration
+}
+
+func NewWaitForShootHibernationStep(gardenerClient GardenerClient, dbSession dbsession.WriteSession, nextStep model.OperationStage, timeLimit time.Duration) *WaitForShootHibernationStep {
+	return &WaitForShootHibernationStep{
+		gardenerClient: gardenerClient,
+		dbSession:      dbSession,
+		name:           model.WaitingForShootHibernation,
+		hibernated:     true,
+		nextStep:       nextStep,
+		timeLimit:      timeLimit,
+	}
+}
+
+func NewWaitForShootWakeUpStep(gardenerClient GardenerClient, dbSession dbsession.WriteSession, nextStep model.OperationStage, timeLimit time.Duration) *WaitForShootHibernationStep {
+	return &WaitForShootHibernationStep{
+		gardenerClient: gardenerClient,
+		dbSession:      dbSession,
+		name:           model.WaitingForShootWakeUp,
+		hibernated:     false,
+		nextStep:       nextStep,
+		timeLimit:      timeLimit,
+	}
+}
+
+func (s *WaitForShootHibernationStep) Name() model.OperationStage {
+	return s.name
+}
+
+func (s *WaitForShootHibernationStep) TimeLimit() time.Duration {
+	return s.timeLimit
+}
+
+func (s *WaitForShootHibernationStep) Run(cluster model.Cluster, _ model.Operation, logger logrus.FieldLogger) (operations.StageResult, error) {
+	shoot, err := s.gardenerClient.Get(context.Background(), cluster.ClusterConfig.Name, v1.GetOptions{})
+	if err != nil {
+		return operations.StageResult{}, util.K8SErrorToAppError(err).Append("error getting Shoot")
+	}
+
+	// Status is stale until Gardener observes the hibernation change
+	if shoot.Status.ObservedGeneration != shoot.Generation {
+		return operations.StageResult{Stage: s.Name(), Delay: 20 * time.Second}, nil
+	}
+
+	lastOperation := shoot.Status.LastOperation
+	if lastOperation != nil && lastOperation.State == gardener_types.LastOperationStateFailed {
+		logger.Warnf("Gardener Shoot hibernation change failed! Last state: %s, Description: %s", lastOperation.State, lastOperation.Description)
+		err := fmt.Errorf("Gardener Shoot hibernation change failed. Last Shoot state: %s, Shoot description: %s", lastOperation.State, lastOperation.Description)
+		return operations.StageResult{}, operations.NewNonRecoverableError(err)
+	}
+
+	if shoot.Status.IsHibernated != s.hibernated || (lastOperation != nil && lastOperation.State != gardener_types.LastOperationStateSucceeded) {
+		return operations.StageResult{Stage: s.Name(), Delay: 20 * time.Second}, nil
+	}
+
+	dberr := s.dbSession.SetHibernated(cluster.ID, s.hibernated)
+	if dberr != nil {
+		return operations.StageResult{}, dberr
+	}
+
+	return operations.StageResult{Stage: s.nextStep, Delay: 0}, nil
+}
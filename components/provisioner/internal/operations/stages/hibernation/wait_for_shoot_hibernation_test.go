@@ -0,0 +1,144 @@
+package hibernation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	gardener_mocks "github.com/kyma-project/control-plane/components/provisioner/internal/operations/stages/hibernation/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	dbMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util/testkit"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForShootHibernationStep_Run(t *testing.T) {
+	clusterName := "shootName"
+	runtimeID := "runtimeID"
+
+	cluster := model.Cluster{
+		ID: runtimeID,
+		ClusterConfig: model.GardenerConfig{
+			Name: clusterName,
+		},
+	}
+
+	for _, testCase := range []struct {
+		description   string
+		hibernate     bool
+		shoot         *testkit.TestShoot
+		expectedStage model.OperationStage
+		expectedDelay time.Duration
+	}{
+		{
+			description:   "should continue waiting if Gardener did not observe the change",
+			hibernate:     true,
+			shoot:         testkit.NewTestShoot(clusterName).WithGeneration(2).WithObservedGeneration(1).WithOperationSucceeded(),
+			expectedStage: model.WaitingForShootHibernation,
+			expectedDelay: 20 * time.Second,
+		},
+		{
+			description:   "should continue waiting if Shoot is being hibernated",
+			hibernate:     true,
+			shoot:         testkit.NewTestShoot(clusterName).WithGeneration(2).WithObservedGeneration(2).WithOperationProcessing(),
+			expectedStage: model.WaitingForShootHibernation,
+			expectedDelay: 20 * time.Second,
+		},
+		{
+			description:   "should continue waiting if Shoot is still hibernated",
+			hibernate:     false,
+			shoot:         testkit.NewTestShoot(clusterName).WithGeneration(2).WithObservedGeneration(2).WithOperationSucceeded().WithHibernated(true),
+			expectedStage: model.WaitingForShootWakeUp,
+			expectedDelay: 20 * time.Second,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			// given
+			gardenerClient := &gardener_mocks.GardenerClient{}
+			gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(testCase.shoot.ToShoot(), nil)
+			session := &dbMocks.WriteSession{}
+
+			step := newWaitStep(testCase.hibernate, gardenerClient, session)
+
+			// when
+			result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedStage, result.Stage)
+			assert.Equal(t, testCase.expectedDelay, result.Delay)
+			session.AssertNotCalled(t, "SetHibernated", mock.Anything, mock.Anything)
+		})
+	}
+
+	for _, hibernate := range []bool{true, false} {
+		t.Run(fmt.Sprintf("should store hibernated %t and finish when Shoot reports it", hibernate), func(t *testing.T) {
+			// given
+			gardenerClient := &gardener_mocks.GardenerClient{}
+			gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(
+				testkit.NewTestShoot(clusterName).WithGeneration(2).WithObservedGeneration(2).WithOperationSucceeded().WithHibernated(hibernate).ToShoot(), nil)
+			session := &dbMocks.WriteSession{}
+			session.On("SetHibernated", runtimeID, hibernate).Return(nil)
+
+			step := newWaitStep(hibernate, gardenerClient, session)
+
+			// when
+			result, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, model.FinishedStage, result.Stage)
+			session.AssertExpectations(t)
+		})
+	}
+
+	t.Run("should return unrecoverable error if Shoot operation failed", func(t *testing.T) {
+		// given
+		gardenerClient := &gardener_mocks.GardenerClient{}
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(
+			testkit.NewTestShoot(clusterName).WithGeneration(2).WithObservedGeneration(2).WithOperationFailed().ToShoot(), nil)
+
+		step := newWaitStep(true, gardenerClient, &dbMocks.WriteSession{})
+
+		// when
+		_, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.Error(t, err)
+		nonRecoverable := operations.NonRecoverableError{}
+		assert.True(t, errors.As(err, &nonRecoverable))
+	})
+
+	t.Run("should return error if failed to store hibernation state", func(t *testing.T) {
+		// given
+		gardenerClient := &gardener_mocks.GardenerClient{}
+		gardenerClient.On("Get", context.Background(), clusterName, mock.Anything).Return(
+			testkit.NewTestShoot(clusterName).WithGeneration(2).WithObservedGeneration(2).WithOperationSucceeded().WithHibernated(true).ToShoot(), nil)
+		session := &dbMocks.WriteSession{}
+		session.On("SetHibernated", runtimeID, true).Return(dberrors.Internal("some error"))
+
+		step := newWaitStep(true, gardenerClient, session)
+
+		// when
+		_, err := step.Run(cluster, model.Operation{}, logrus.New())
+
+		// then
+		require.Error(t, err)
+		nonRecoverable := operations.NonRecoverableError{}
+		assert.False(t, errors.As(err, &nonRecoverable))
+	})
+}
+
+func newWaitStep(hibernate bool, gardenerClient GardenerClient, session *dbMocks.WriteSession) *WaitForShootHibernationStep {
+	if hibernate {
+		return NewWaitForShootHibernationStep(gardenerClient, session, model.FinishedStage, time.Minute)
+	}
+	return NewWaitForShootWakeUpStep(gardenerClient, session, model.FinishedStage, time.Minute)
+}
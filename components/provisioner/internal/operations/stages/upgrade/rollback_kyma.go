@@ -0,0 +1,81 @@
+package upgrade
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/installation"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util/k8s"
+	"github.com/sirupsen/logrus"
+)
+
+// RollbackKymaStep re-applies the Kyma config which was active before the failed upgrade
+type RollbackKymaStep struct {
+	installationClient installation.Service
+	nextStep           model.OperationStage
+	timeLimit          time.Duration
+}
+
+func NewRollbackKymaStep(installationClient installation.Service, nextStep model.OperationStage, timeLimit time.Duration) *RollbackKymaStep {
+	return &RollbackKymaStep{
+		installationClient: installationClient,
+		nextStep:           nextStep,
+		timeLimit:          timeLimit,
+	}
+}
+
+func (s *RollbackKymaStep) Name() model.OperationStage {
+	return model.StartingRollback
+}
+
+func (s *RollbackKymaStep) TimeLimit() time.Duration {
+	return s.timeLimit
+}
+
+func (s *RollbackKymaStep) Run(cluster model.Cluster, _ model.Operation, logger logrus.FieldLogger) (operations.StageResult, error) {
+
+	if cluster.Kubeconfig == nil {
+		return operations.StageResult{}, fmt.Errorf("error: kubeconfig is nil")
+	}
+
+	k8sConfig, err := k8s.ParseToK8sConfig([]byte(*cluster.Kubeconfig))
+	if err != nil {
+		return operations.StageResult{}, fmt.Errorf("error: failed to create kubernetes config from raw: %s", err.Error())
+	}
+
+	installationState, err := s.installationClient.CheckInstallationState(k8sConfig)
+	if err != nil {
+		installErr := installationSDK.InstallationError{}
+		if !errors.As(err, &installErr) {
+			return operations.StageResult{}, fmt.Errorf("error: failed to check installation CR state: %s", err.Error())
+		}
+		// Failed upgrade leaves the Installation CR in the error state, the previous config is applied on top of it
+		logger.Warnf("Installation in error state: %s", installErr.Error())
+	}
+
+	if installationState.State == installationSDK.NoInstallationState {
+		return operations.StageResult{}, operations.NewNonRecoverableError(fmt.Errorf("error: Installation CR not found in the cluster, cannot trigger rollback"))
+	}
+
+	if installationState.State == "InProgress" {
+		logger.Infof("Installation in progress, waiting for it to finish before rolling back: %s", installationState.Description)
+		return operations.StageResult{Stage: s.Name(), Delay: 30 * time.Second}, nil
+	}
+
+	err = s.installationClient.TriggerUpgrade(
+		k8sConfig,
+		cluster.KymaConfig.Profile,
+		cluster.KymaConfig.Release,
+		cluster.KymaConfig.GlobalConfiguration,
+		cluster.KymaConfig.Components)
+	if err != nil {
+		return operations.StageResult{}, fmt.Errorf("error: failed to trigger rollback: %s", err.Error())
+	}
+
+	logger.Infof("Rollback to Kyma %s triggered", cluster.KymaConfig.Release.Version)
+	return operations.StageResult{Stage: s.nextStep, Delay: 30 * time.Second}, nil
+}
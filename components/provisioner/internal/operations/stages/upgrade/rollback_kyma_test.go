@@ -0,0 +1,115 @@
+package upgrade
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/install/installation"
+	installationMocks "github.com/kyma-project/control-plane/components/provisioner/internal/installation/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackKymaStep_Run(t *testing.T) {
+
+	cluster := model.Cluster{
+		Kubeconfig: util.StringPtr(kubeconfig),
+		KymaConfig: model.KymaConfig{Release: model.Release{Version: "1.17.0"}},
+	}
+
+	t.Run("should return error when kubeconfig is nil", func(t *testing.T) {
+		//given
+		rollbackStep := NewRollbackKymaStep(nil, nextStageName, 0)
+
+		//when
+		_, err := rollbackStep.Run(model.Cluster{}, model.Operation{}, logrus.New())
+
+		//then
+		require.Error(t, err)
+	})
+
+	t.Run("should return non recoverable error when installation CR is not present on the cluster", func(t *testing.T) {
+		//given
+		installationClient := &installationMocks.Service{}
+		installationClient.On("CheckInstallationState", mock.Anything).Return(installation.InstallationState{State: installation.NoInstallationState}, nil)
+
+		rollbackStep := NewRollbackKymaStep(installationClient, nextStageName, 0)
+
+		//when
+		_, err := rollbackStep.Run(cluster, model.Operation{}, logrus.New())
+
+		//then
+		require.Error(t, err)
+		assert.True(t, errors.As(err, &operations.NonRecoverableError{}))
+	})
+
+	t.Run("should wait when installation is in progress", func(t *testing.T) {
+		//given
+		installationClient := &installationMocks.Service{}
+		installationClient.On("CheckInstallationState", mock.Anything).Return(installation.InstallationState{State: "InProgress"}, nil)
+
+		rollbackStep := NewRollbackKymaStep(installationClient, nextStageName, 0)
+
+		//when
+		result, err := rollbackStep.Run(cluster, model.Operation{}, logrus.New())
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, model.StartingRollback, result.Stage)
+		installationClient.AssertNotCalled(t, "TriggerUpgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should trigger upgrade to previous config when installation is in error state", func(t *testing.T) {
+		//given
+		installationClient := &installationMocks.Service{}
+		installationClient.On("CheckInstallationState", mock.Anything).Return(installation.InstallationState{}, installation.InstallationError{ShortMessage: "upgrade failed"})
+		installationClient.On("TriggerUpgrade", mock.Anything, mock.Anything, cluster.KymaConfig.Release, mock.Anything, mock.Anything).Return(nil)
+
+		rollbackStep := NewRollbackKymaStep(installationClient, nextStageName, 0)
+
+		//when
+		result, err := rollbackStep.Run(cluster, model.Operation{}, logrus.New())
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+		installationClient.AssertExpectations(t)
+	})
+
+	t.Run("should trigger upgrade to previous config when installation is finished", func(t *testing.T) {
+		//given
+		installationClient := &installationMocks.Service{}
+		installationClient.On("CheckInstallationState", mock.Anything).Return(installation.InstallationState{State: "Installed"}, nil)
+		installationClient.On("TriggerUpgrade", mock.Anything, mock.Anything, cluster.KymaConfig.Release, mock.Anything, mock.Anything).Return(nil)
+
+		rollbackStep := NewRollbackKymaStep(installationClient, nextStageName, 0)
+
+		//when
+		result, err := rollbackStep.Run(cluster, model.Operation{}, logrus.New())
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, nextStageName, result.Stage)
+		installationClient.AssertExpectations(t)
+	})
+
+	t.Run("should return error when failed to trigger upgrade", func(t *testing.T) {
+		//given
+		installationClient := &installationMocks.Service{}
+		installationClient.On("CheckInstallationState", mock.Anything).Return(installation.InstallationState{State: "Installed"}, nil)
+		installationClient.On("TriggerUpgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("bad error"))
+
+		rollbackStep := NewRollbackKymaStep(installationClient, nextStageName, 0)
+
+		//when
+		_, err := rollbackStep.Run(cluster, model.Operation{}, logrus.New())
+
+		//then
+		require.Error(t, err)
+	})
+}
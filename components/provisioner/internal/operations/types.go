@@ -38,3 +38,7 @@ func NewNonRecoverableError(err error) NonRecoverableError {
 type FailureHandler interface {
 	HandleFailure(operation model.Operation, cluster model.Cluster) error
 }
+
+type OperationNotifier interface {
+	OperationChanged(operation model.Operation)
+}
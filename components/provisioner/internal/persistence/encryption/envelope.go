@@ -0,0 +1,212 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// envelopePrefix marks values encrypted with the envelope encryption, the format is:
+	// envelope:<key ID>:<base64 of data key encrypted with the key>:<base64 of value encrypted with data key>
+	envelopePrefix = "envelope:"
+
+	dataKeySize = 32
+)
+
+// Key is a versioned key used to encrypt data keys
+type Key struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// ReadKeysFromFile reads the JSON list of keys, the last key in the list is used to encrypt new values
+func ReadKeysFromFile(filename string) ([]Key, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading %s file with encryption keys", filename)
+	}
+
+	var keys []Key
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return nil, errors.Wrap(err, "while unmarshalling file with encryption keys")
+	}
+
+	return keys, nil
+}
+
+// EnvelopeEncrypter encrypts every value with a random data key which is stored next to the value encrypted with the newest key.
+// Rotation of the key requires only the data keys to be encrypted again.
+type EnvelopeEncrypter struct {
+	keys      map[string][]byte
+	currentID string
+}
+
+// NewEnvelopeEncrypter returns EnvelopeEncrypter which encrypts data keys with the last of the given keys
+// and decrypts values which data keys are encrypted with any of the keys
+func NewEnvelopeEncrypter(keys []Key) (*EnvelopeEncrypter, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one encryption key is required")
+	}
+
+	e := &EnvelopeEncrypter{
+		keys: make(map[string][]byte, len(keys)),
+	}
+	for _, k := range keys {
+		if k.ID == "" || strings.Contains(k.ID, ":") {
+			return nil, errors.Errorf("invalid encryption key ID %q", k.ID)
+		}
+		if _, exists := e.keys[k.ID]; exists {
+			return nil, errors.Errorf("encryption key ID %s is not unique", k.ID)
+		}
+		if _, err := aes.NewCipher([]byte(k.Key)); err != nil {
+			return nil, errors.Wrapf(err, "invalid encryption key %s", k.ID)
+		}
+		e.keys[k.ID] = []byte(k.Key)
+	}
+	e.currentID = keys[len(keys)-1].ID
+
+	return e, nil
+}
+
+// Encrypt encrypts the value with a new data key and encrypts the data key with the newest key
+func (e *EnvelopeEncrypter) Encrypt(value []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.Wrap(err, "while generating data key")
+	}
+
+	sealedValue, err := seal(dataKey, value, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "while encrypting value")
+	}
+
+	return e.envelope(dataKey, sealedValue)
+}
+
+// Decrypt decrypts the value encrypted with any of the known keys, values which are not encrypted are returned unchanged
+func (e *EnvelopeEncrypter) Decrypt(value []byte) ([]byte, error) {
+	if !e.IsEncrypted(value) {
+		return value, nil
+	}
+
+	dataKey, sealedValue, err := e.open(value)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := unseal(dataKey, sealedValue, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "while decrypting value")
+	}
+
+	return plain, nil
+}
+
+// ReEncrypt encrypts the data key of the value with the newest key, values which are not encrypted are encrypted
+func (e *EnvelopeEncrypter) ReEncrypt(value []byte) ([]byte, error) {
+	if !e.IsEncrypted(value) {
+		return e.Encrypt(value)
+	}
+
+	dataKey, sealedValue, err := e.open(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.envelope(dataKey, sealedValue)
+}
+
+// IsEncrypted checks if the value was encrypted with the envelope encryption
+func (e *EnvelopeEncrypter) IsEncrypted(value []byte) bool {
+	return strings.HasPrefix(string(value), envelopePrefix)
+}
+
+// CurrentPrefix returns the prefix of values which data keys are encrypted with the newest key
+func (e *EnvelopeEncrypter) CurrentPrefix() string {
+	return fmt.Sprintf("%s%s:", envelopePrefix, e.currentID)
+}
+
+func (e *EnvelopeEncrypter) envelope(dataKey, sealedValue []byte) ([]byte, error) {
+	sealedDataKey, err := seal(e.keys[e.currentID], dataKey, []byte(e.currentID))
+	if err != nil {
+		return nil, errors.Wrap(err, "while encrypting data key")
+	}
+
+	return []byte(fmt.Sprintf("%s%s:%s",
+		e.CurrentPrefix(),
+		base64.StdEncoding.EncodeToString(sealedDataKey),
+		base64.StdEncoding.EncodeToString(sealedValue))), nil
+}
+
+func (e *EnvelopeEncrypter) open(value []byte) ([]byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(string(value), envelopePrefix), ":")
+	if len(parts) != 3 {
+		return nil, nil, errors.New("invalid format of encrypted value")
+	}
+
+	keyID := parts[0]
+	key, found := e.keys[keyID]
+	if !found {
+		return nil, nil, errors.Errorf("unknown encryption key %s", keyID)
+	}
+
+	sealedDataKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "while decoding data key")
+	}
+	sealedValue, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "while decoding value")
+	}
+
+	dataKey, err := unseal(key, sealedDataKey, []byte(keyID))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "while decrypting data key")
+	}
+
+	return dataKey, sealedValue, nil
+}
+
+func seal(key, plain, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plain)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plain, additionalData), nil
+}
+
+func unseal(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("cipher text is too short")
+	}
+
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
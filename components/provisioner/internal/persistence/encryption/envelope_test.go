@@ -0,0 +1,172 @@
+package encryption
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oldKey = "old-key-old-key-old-key-old-key-"
+	newKey = "new-key-new-key-new-key-new-key-"
+)
+
+func TestEnvelopeEncrypter(t *testing.T) {
+	kubeconfig := []byte("apiVersion: v1\nkind: Config")
+
+	t.Run("should encrypt and decrypt value", func(t *testing.T) {
+		// given
+		encrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}})
+		require.NoError(t, err)
+
+		// when
+		encrypted, err := encrypter.Encrypt(kubeconfig)
+		require.NoError(t, err)
+		decrypted, err := encrypter.Decrypt(encrypted)
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, encrypter.IsEncrypted(encrypted))
+		assert.True(t, strings.HasPrefix(string(encrypted), "envelope:v1:"))
+		assert.NotContains(t, string(encrypted), string(kubeconfig))
+		assert.Equal(t, kubeconfig, decrypted)
+	})
+
+	t.Run("should return not encrypted value unchanged", func(t *testing.T) {
+		// given
+		encrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}})
+		require.NoError(t, err)
+
+		// when
+		decrypted, err := encrypter.Decrypt(kubeconfig)
+		require.NoError(t, err)
+
+		// then
+		assert.False(t, encrypter.IsEncrypted(kubeconfig))
+		assert.Equal(t, kubeconfig, decrypted)
+	})
+
+	t.Run("should re-encrypt data key with the newest key", func(t *testing.T) {
+		// given
+		oldEncrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}})
+		require.NoError(t, err)
+		encrypted, err := oldEncrypter.Encrypt(kubeconfig)
+		require.NoError(t, err)
+
+		encrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}, {ID: "v2", Key: newKey}})
+		require.NoError(t, err)
+
+		// when
+		reEncrypted, err := encrypter.ReEncrypt(encrypted)
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, strings.HasPrefix(string(reEncrypted), encrypter.CurrentPrefix()))
+		assert.Equal(t, lastPart(encrypted), lastPart(reEncrypted), "value encrypted with data key should not change")
+
+		decrypted, err := encrypter.Decrypt(reEncrypted)
+		require.NoError(t, err)
+		assert.Equal(t, kubeconfig, decrypted)
+
+		newEncrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v2", Key: newKey}})
+		require.NoError(t, err)
+		decrypted, err = newEncrypter.Decrypt(reEncrypted)
+		require.NoError(t, err)
+		assert.Equal(t, kubeconfig, decrypted)
+	})
+
+	t.Run("should encrypt not encrypted value when re-encrypting", func(t *testing.T) {
+		// given
+		encrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}})
+		require.NoError(t, err)
+
+		// when
+		reEncrypted, err := encrypter.ReEncrypt(kubeconfig)
+		require.NoError(t, err)
+
+		// then
+		assert.True(t, encrypter.IsEncrypted(reEncrypted))
+		decrypted, err := encrypter.Decrypt(reEncrypted)
+		require.NoError(t, err)
+		assert.Equal(t, kubeconfig, decrypted)
+	})
+
+	t.Run("should fail to decrypt value encrypted with unknown key", func(t *testing.T) {
+		// given
+		oldEncrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}})
+		require.NoError(t, err)
+		encrypted, err := oldEncrypter.Encrypt(kubeconfig)
+		require.NoError(t, err)
+
+		encrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v2", Key: newKey}})
+		require.NoError(t, err)
+
+		// when
+		_, err = encrypter.Decrypt(encrypted)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown encryption key v1")
+	})
+
+	t.Run("should fail to decrypt value with modified key ID", func(t *testing.T) {
+		// given
+		encrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}, {ID: "v2", Key: oldKey}})
+		require.NoError(t, err)
+		encrypted, err := encrypter.Encrypt(kubeconfig)
+		require.NoError(t, err)
+
+		// when
+		_, err = encrypter.Decrypt([]byte(strings.Replace(string(encrypted), "envelope:v2:", "envelope:v1:", 1)))
+
+		// then
+		require.Error(t, err)
+	})
+
+	for _, testCase := range []struct {
+		description string
+		keys        []Key
+	}{
+		{description: "no keys", keys: nil},
+		{description: "empty key ID", keys: []Key{{ID: "", Key: oldKey}}},
+		{description: "key ID with colon", keys: []Key{{ID: "v:1", Key: oldKey}}},
+		{description: "duplicated key ID", keys: []Key{{ID: "v1", Key: oldKey}, {ID: "v1", Key: newKey}}},
+		{description: "invalid key length", keys: []Key{{ID: "v1", Key: "short"}}},
+	} {
+		t.Run("should fail to create encrypter with "+testCase.description, func(t *testing.T) {
+			// when
+			_, err := NewEnvelopeEncrypter(testCase.keys)
+
+			// then
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestReadKeysFromFile(t *testing.T) {
+	// given
+	dir, err := ioutil.TempDir("", "keys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "keys.json")
+	err = ioutil.WriteFile(path, []byte(`[{"id": "v1", "key": "`+oldKey+`"}, {"id": "v2", "key": "`+newKey+`"}]`), 0600)
+	require.NoError(t, err)
+
+	// when
+	keys, err := ReadKeysFromFile(path)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []Key{{ID: "v1", Key: oldKey}, {ID: "v2", Key: newKey}}, keys)
+}
+
+func lastPart(value []byte) string {
+	parts := strings.Split(string(value), ":")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,111 @@
+package encryption
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Config holds settings of the kubeconfig encryption
+type Config struct {
+	KeysFilePath          string        `envconfig:"optional"`
+	ReEncryptionInterval  time.Duration `envconfig:"default=1h"`
+	ReEncryptionBatchSize int           `envconfig:"default=100"`
+}
+
+// ReEncryptionJob encrypts stored kubeconfigs which are not encrypted yet and encrypts data keys of the rest with the newest key
+type ReEncryptionJob struct {
+	factory   dbsession.Factory
+	encrypter *EnvelopeEncrypter
+	interval  time.Duration
+	batchSize int
+
+	log logrus.FieldLogger
+}
+
+func NewReEncryptionJob(factory dbsession.Factory, encrypter *EnvelopeEncrypter, interval time.Duration, batchSize int) *ReEncryptionJob {
+	return &ReEncryptionJob{
+		factory:   factory,
+		encrypter: encrypter,
+		interval:  interval,
+		batchSize: batchSize,
+		log:       logrus.WithField("Component", "KubeconfigReEncryptionJob"),
+	}
+}
+
+// Run re-encrypts kubeconfigs periodically until the stop channel is closed
+func (j *ReEncryptionJob) Run(stop <-chan struct{}) {
+	go wait.Until(j.ReEncryptAll, j.interval, stop)
+}
+
+// ReEncryptAll re-encrypts kubeconfigs in batches until there is nothing left to migrate.
+// Kubeconfigs which cannot be re-encrypted, for example because their key was removed, are skipped and reported.
+// A database failure stops processing, the rest of kubeconfigs is migrated in the next run.
+func (j *ReEncryptionJob) ReEncryptAll() {
+	total, skipped := 0, 0
+	afterID := ""
+	for {
+		result, err := j.reEncryptBatch(afterID)
+		total += result.updated
+		skipped += result.skipped
+		if err != nil {
+			j.log.Errorf("Failed to re-encrypt kubeconfigs: %s", err.Error())
+			break
+		}
+		if result.listed < j.batchSize {
+			break
+		}
+		afterID = result.lastID
+	}
+
+	if total > 0 {
+		j.log.Infof("Re-encrypted %d kubeconfigs with the newest key", total)
+	}
+	if skipped > 0 {
+		j.log.Warnf("Skipped %d kubeconfigs which could not be re-encrypted", skipped)
+	}
+}
+
+type batchResult struct {
+	listed  int
+	updated int
+	skipped int
+	lastID  string
+}
+
+func (j *ReEncryptionJob) reEncryptBatch(afterID string) (batchResult, error) {
+	result := batchResult{}
+
+	clusters, dberr := j.factory.NewReadSession().ListKubeconfigsToReEncrypt(j.encrypter.CurrentPrefix(), afterID, j.batchSize)
+	if dberr != nil {
+		return result, dberr
+	}
+	result.listed = len(clusters)
+
+	session := j.factory.NewWriteSession()
+	for _, cluster := range clusters {
+		result.lastID = cluster.ID
+
+		reEncrypted, err := j.encrypter.ReEncrypt([]byte(*cluster.Kubeconfig))
+		if err != nil {
+			j.log.Errorf("Failed to re-encrypt kubeconfig of cluster %s: %s", cluster.ID, err.Error())
+			result.skipped++
+			continue
+		}
+
+		dberr := session.ReplaceKubeconfig(cluster.ID, *cluster.Kubeconfig, string(reEncrypted))
+		if dberr != nil {
+			if dberr.Code() == dberrors.CodeNotFound {
+				// Kubeconfig was updated in the meantime so it is already encrypted with the newest key
+				continue
+			}
+			return result, dberr.Append("Failed to replace kubeconfig of cluster %s", cluster.ID)
+		}
+		result.updated++
+	}
+
+	return result, nil
+}
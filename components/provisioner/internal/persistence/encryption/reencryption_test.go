@@ -0,0 +1,173 @@
+package encryption
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	sessionMocks "github.com/kyma-project/control-plane/components/provisioner/internal/provisioning/persistence/dbsession/mocks"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReEncryptionJob_ReEncryptAll(t *testing.T) {
+	encrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}})
+	require.NoError(t, err)
+
+	decryptsTo := func(expected string) interface{} {
+		return mock.MatchedBy(func(value string) bool {
+			decrypted, err := encrypter.Decrypt([]byte(value))
+			return err == nil && encrypter.IsEncrypted([]byte(value)) && string(decrypted) == expected
+		})
+	}
+
+	t.Run("should encrypt stored kubeconfigs in batches", func(t *testing.T) {
+		// given
+		factory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSession{}
+
+		factory.On("NewReadSession").Return(readSession)
+		factory.On("NewWriteSession").Return(writeSession)
+		readSession.On("ListKubeconfigsToReEncrypt", encrypter.CurrentPrefix(), "", 2).Return([]model.Cluster{
+			{ID: "cluster-1", Kubeconfig: util.StringPtr("kubeconfig-1")},
+			{ID: "cluster-2", Kubeconfig: util.StringPtr("kubeconfig-2")},
+		}, nil).Once()
+		readSession.On("ListKubeconfigsToReEncrypt", encrypter.CurrentPrefix(), "cluster-2", 2).Return([]model.Cluster{
+			{ID: "cluster-3", Kubeconfig: util.StringPtr("kubeconfig-3")},
+		}, nil).Once()
+		writeSession.On("ReplaceKubeconfig", "cluster-1", "kubeconfig-1", decryptsTo("kubeconfig-1")).Return(nil)
+		writeSession.On("ReplaceKubeconfig", "cluster-2", "kubeconfig-2", decryptsTo("kubeconfig-2")).Return(nil)
+		writeSession.On("ReplaceKubeconfig", "cluster-3", "kubeconfig-3", decryptsTo("kubeconfig-3")).Return(nil)
+
+		job := NewReEncryptionJob(factory, encrypter, 0, 2)
+
+		// when
+		job.ReEncryptAll()
+
+		// then
+		readSession.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+	})
+
+	t.Run("should skip kubeconfig changed in the meantime", func(t *testing.T) {
+		// given
+		factory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSession{}
+
+		factory.On("NewReadSession").Return(readSession)
+		factory.On("NewWriteSession").Return(writeSession)
+		readSession.On("ListKubeconfigsToReEncrypt", encrypter.CurrentPrefix(), "", 10).Return([]model.Cluster{
+			{ID: "cluster-1", Kubeconfig: util.StringPtr("kubeconfig-1")},
+			{ID: "cluster-2", Kubeconfig: util.StringPtr("kubeconfig-2")},
+		}, nil).Once()
+		writeSession.On("ReplaceKubeconfig", "cluster-1", "kubeconfig-1", mock.AnythingOfType("string")).Return(dberrors.NotFound("changed"))
+		writeSession.On("ReplaceKubeconfig", "cluster-2", "kubeconfig-2", decryptsTo("kubeconfig-2")).Return(nil)
+
+		job := NewReEncryptionJob(factory, encrypter, 0, 10)
+
+		// when
+		job.ReEncryptAll()
+
+		// then
+		readSession.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+	})
+
+	t.Run("should skip kubeconfig which cannot be re-encrypted and continue with the next batch", func(t *testing.T) {
+		// given
+		otherEncrypter, err := NewEnvelopeEncrypter([]Key{{ID: "removed", Key: newKey}})
+		require.NoError(t, err)
+		undecryptable, err := otherEncrypter.Encrypt([]byte("kubeconfig-1"))
+		require.NoError(t, err)
+
+		factory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSession{}
+
+		factory.On("NewReadSession").Return(readSession)
+		factory.On("NewWriteSession").Return(writeSession)
+		readSession.On("ListKubeconfigsToReEncrypt", encrypter.CurrentPrefix(), "", 1).Return([]model.Cluster{
+			{ID: "cluster-1", Kubeconfig: util.StringPtr(string(undecryptable))},
+		}, nil).Once()
+		readSession.On("ListKubeconfigsToReEncrypt", encrypter.CurrentPrefix(), "cluster-1", 1).Return([]model.Cluster{
+			{ID: "cluster-2", Kubeconfig: util.StringPtr("kubeconfig-2")},
+		}, nil).Once()
+		readSession.On("ListKubeconfigsToReEncrypt", encrypter.CurrentPrefix(), "cluster-2", 1).Return([]model.Cluster{}, nil).Once()
+		writeSession.On("ReplaceKubeconfig", "cluster-2", "kubeconfig-2", decryptsTo("kubeconfig-2")).Return(nil)
+
+		job := NewReEncryptionJob(factory, encrypter, 0, 1)
+
+		// when
+		job.ReEncryptAll()
+
+		// then
+		readSession.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+		writeSession.AssertNotCalled(t, "ReplaceKubeconfig", "cluster-1", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should stop when failed to replace kubeconfig", func(t *testing.T) {
+		// given
+		factory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSession{}
+
+		factory.On("NewReadSession").Return(readSession)
+		factory.On("NewWriteSession").Return(writeSession)
+		readSession.On("ListKubeconfigsToReEncrypt", encrypter.CurrentPrefix(), "", 1).Return([]model.Cluster{
+			{ID: "cluster-1", Kubeconfig: util.StringPtr("kubeconfig-1")},
+		}, nil).Once()
+		writeSession.On("ReplaceKubeconfig", "cluster-1", "kubeconfig-1", mock.AnythingOfType("string")).Return(dberrors.Internal("error"))
+
+		job := NewReEncryptionJob(factory, encrypter, 0, 1)
+
+		// when
+		job.ReEncryptAll()
+
+		// then
+		readSession.AssertNumberOfCalls(t, "ListKubeconfigsToReEncrypt", 1)
+		writeSession.AssertExpectations(t)
+	})
+}
+
+func TestReEncryptionJob_ReEncryptAll_Rotation(t *testing.T) {
+	// given
+	oldEncrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}})
+	require.NoError(t, err)
+	encrypted, err := oldEncrypter.Encrypt([]byte("kubeconfig"))
+	require.NoError(t, err)
+
+	encrypter, err := NewEnvelopeEncrypter([]Key{{ID: "v1", Key: oldKey}, {ID: "v2", Key: newKey}})
+	require.NoError(t, err)
+
+	factory := &sessionMocks.Factory{}
+	readSession := &sessionMocks.ReadSession{}
+	writeSession := &sessionMocks.WriteSession{}
+
+	factory.On("NewReadSession").Return(readSession)
+	factory.On("NewWriteSession").Return(writeSession)
+	readSession.On("ListKubeconfigsToReEncrypt", "envelope:v2:", "", 10).Return([]model.Cluster{
+		{ID: "cluster-1", Kubeconfig: util.StringPtr(string(encrypted))},
+	}, nil).Once()
+
+	var replaced string
+	writeSession.On("ReplaceKubeconfig", "cluster-1", string(encrypted), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { replaced = args.String(2) }).
+		Return(nil)
+
+	job := NewReEncryptionJob(factory, encrypter, 0, 10)
+
+	// when
+	job.ReEncryptAll()
+
+	// then
+	writeSession.AssertExpectations(t)
+	assert.Contains(t, replaced, "envelope:v2:")
+	decrypted, err := encrypter.Decrypt([]byte(replaced))
+	require.NoError(t, err)
+	assert.Equal(t, "kubeconfig", string(decrypted))
+}
@@ -2,12 +2,14 @@ package provisioning
 
 import (
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
 )
 
 type GraphQLConverter interface {
 	RuntimeStatusToGraphQLStatus(status model.RuntimeStatus) *gqlschema.RuntimeStatus
 	OperationStatusToGQLOperationStatus(operation model.Operation) *gqlschema.OperationStatus
+	RuntimeToGraphQLRuntime(cluster model.Cluster, lastOperation *model.Operation) *gqlschema.Runtime
 }
 
 func NewGraphQLConverter() GraphQLConverter {
@@ -21,6 +23,7 @@ func (c graphQLConverter) RuntimeStatusToGraphQLStatus(status model.RuntimeStatu
 		LastOperationStatus:     c.OperationStatusToGQLOperationStatus(status.LastOperationStatus),
 		RuntimeConnectionStatus: c.runtimeConnectionStatusToGraphQLStatus(status.RuntimeConnectionStatus),
 		RuntimeConfiguration:    c.clusterToToGraphQLRuntimeConfiguration(status.RuntimeConfiguration),
+		Hibernated:              status.RuntimeConfiguration.Hibernated,
 	}
 }
 
@@ -34,6 +37,24 @@ func (c graphQLConverter) OperationStatusToGQLOperationStatus(operation model.Op
 	}
 }
 
+func (c graphQLConverter) RuntimeToGraphQLRuntime(cluster model.Cluster, lastOperation *model.Operation) *gqlschema.Runtime {
+	runtime := &gqlschema.Runtime{
+		ID:            cluster.ID,
+		Tenant:        cluster.Tenant,
+		SubAccountID:  cluster.SubAccountId,
+		ClusterConfig: c.gardenerConfigToGraphQLConfig(cluster.ClusterConfig),
+	}
+
+	if cluster.KymaConfig.Release.Version != "" {
+		runtime.KymaVersion = &cluster.KymaConfig.Release.Version
+	}
+	if lastOperation != nil {
+		runtime.LastOperationStatus = c.OperationStatusToGQLOperationStatus(*lastOperation)
+	}
+
+	return runtime
+}
+
 func (c graphQLConverter) runtimeConnectionStatusToGraphQLStatus(status model.RuntimeAgentConnectionStatus) *gqlschema.RuntimeConnectionStatus {
 	return &gqlschema.RuntimeConnectionStatus{Status: c.runtimeAgentConnectionStatusToGraphQLStatus(status)}
 }
@@ -89,7 +110,100 @@ func (c graphQLConverter) gardenerConfigToGraphQLConfig(config model.GardenerCon
 		EnableMachineImageVersionAutoUpdate: &config.EnableMachineImageVersionAutoUpdate,
 		AllowPrivilegedContainers:           &config.AllowPrivilegedContainers,
 		ProviderSpecificConfig:              providerSpecificConfig,
+		HibernationSchedules:                c.hibernationSchedulesToGraphQLSchedules(config.HibernationSchedules),
+		WorkerPools:                         c.workerPoolsToGraphQLWorkerPools(config.WorkerPools),
+		KubeAPIServerConfig:                 c.kubeAPIServerConfigToGraphQLConfig(config.KubeAPIServerConfig),
+	}
+}
+
+func (c graphQLConverter) hibernationSchedulesToGraphQLSchedules(schedules []model.HibernationSchedule) []*gqlschema.HibernationSchedule {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	gqlSchedules := make([]*gqlschema.HibernationSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		gqlSchedules = append(gqlSchedules, &gqlschema.HibernationSchedule{
+			Start:    schedule.Start,
+			End:      schedule.End,
+			Location: schedule.Location,
+		})
+	}
+
+	return gqlSchedules
+}
+
+func (c graphQLConverter) workerPoolsToGraphQLWorkerPools(workerPools []model.WorkerPool) []*gqlschema.WorkerPool {
+	if len(workerPools) == 0 {
+		return nil
 	}
+
+	gqlWorkerPools := make([]*gqlschema.WorkerPool, 0, len(workerPools))
+	for i := range workerPools {
+		pool := workerPools[i]
+
+		var labels *gqlschema.Labels
+		if len(pool.Labels) > 0 {
+			labels = &gqlschema.Labels{}
+			for key, value := range pool.Labels {
+				(*labels)[key] = value
+			}
+		}
+
+		var taints []*gqlschema.Taint
+		for _, taint := range pool.Taints {
+			var value *string
+			if taint.Value != "" {
+				value = util.StringPtr(taint.Value)
+			}
+			taints = append(taints, &gqlschema.Taint{
+				Key:    taint.Key,
+				Value:  value,
+				Effect: taint.Effect,
+			})
+		}
+
+		gqlWorkerPools = append(gqlWorkerPools, &gqlschema.WorkerPool{
+			Name:                pool.Name,
+			MachineType:         &pool.MachineType,
+			MachineImage:        pool.MachineImage,
+			MachineImageVersion: pool.MachineImageVersion,
+			DiskType:            &pool.DiskType,
+			VolumeSizeGb:        &pool.VolumeSizeGB,
+			AutoScalerMin:       &pool.AutoScalerMin,
+			AutoScalerMax:       &pool.AutoScalerMax,
+			MaxSurge:            &pool.MaxSurge,
+			MaxUnavailable:      &pool.MaxUnavailable,
+			Zones:               pool.Zones,
+			Labels:              labels,
+			Taints:              taints,
+		})
+	}
+
+	return gqlWorkerPools
+}
+
+func (c graphQLConverter) kubeAPIServerConfigToGraphQLConfig(config *model.KubeAPIServerConfig) *gqlschema.KubeAPIServerConfig {
+	if config == nil {
+		return nil
+	}
+
+	gqlConfig := &gqlschema.KubeAPIServerConfig{
+		AdmissionPlugins: config.AdmissionPlugins,
+	}
+	if config.OIDCConfig != nil {
+		gqlConfig.OidcConfig = &gqlschema.OIDCConfig{
+			IssuerURL:      config.OIDCConfig.IssuerURL,
+			ClientID:       config.OIDCConfig.ClientID,
+			UsernameClaim:  config.OIDCConfig.UsernameClaim,
+			UsernamePrefix: config.OIDCConfig.UsernamePrefix,
+			GroupsClaim:    config.OIDCConfig.GroupsClaim,
+			GroupsPrefix:   config.OIDCConfig.GroupsPrefix,
+			SigningAlgs:    config.OIDCConfig.SigningAlgs,
+		}
+	}
+
+	return gqlConfig
 }
 
 func (c graphQLConverter) kymaConfigToGraphQLConfig(config model.KymaConfig) *gqlschema.KymaConfig {
@@ -142,6 +256,12 @@ func (c graphQLConverter) operationTypeToGraphQLType(operationType model.Operati
 		return gqlschema.OperationTypeUpgradeShoot
 	case model.ReconnectRuntime:
 		return gqlschema.OperationTypeReconnectRuntime
+	case model.Hibernate:
+		return gqlschema.OperationTypeHibernate
+	case model.WakeUp:
+		return gqlschema.OperationTypeWakeUp
+	case model.Rollback:
+		return gqlschema.OperationTypeRollback
 	default:
 		return ""
 	}
@@ -81,6 +81,10 @@ func TestRuntimeStatusToGraphQLStatus(t *testing.T) {
 		enableKubernetesVersionAutoUpdate := true
 		enableMachineImageVersionAutoUpdate := false
 		allowPrivilegedContainers := true
+		hibernationStart := "00 20 * * 1,2,3,4,5"
+		hibernationLocation := "Europe/Berlin"
+		poolMachine := "m5.8xlarge"
+		poolAutoScMin := 0
 
 		gardenerProviderConfig, err := model.NewGardenerProviderConfigFromJSON(`{"zones":["fix-gcp-zone-1","fix-gcp-zone-2"]}`)
 		require.NoError(t, err)
@@ -119,9 +123,30 @@ func TestRuntimeStatusToGraphQLStatus(t *testing.T) {
 					EnableMachineImageVersionAutoUpdate: enableMachineImageVersionAutoUpdate,
 					AllowPrivilegedContainers:           allowPrivilegedContainers,
 					GardenerProviderConfig:              gardenerProviderConfig,
+					HibernationSchedules: []model.HibernationSchedule{
+						{Start: &hibernationStart, Location: &hibernationLocation},
+					},
+					WorkerPools: []model.WorkerPool{
+						{
+							Name:          "memory",
+							MachineType:   poolMachine,
+							DiskType:      disk,
+							VolumeSizeGB:  volume,
+							AutoScalerMin: poolAutoScMin,
+							AutoScalerMax: autoScMax,
+							Zones:         []string{"fix-gcp-zone-2"},
+							Labels:        map[string]string{"workload": "memory"},
+							Taints:        []model.Taint{{Key: "dedicated", Effect: "NoSchedule"}},
+						},
+					},
+					KubeAPIServerConfig: &model.KubeAPIServerConfig{
+						OIDCConfig:       &model.OIDCConfig{IssuerURL: "https://accounts.example.com", ClientID: "kyma", GroupsClaim: util.StringPtr("groups")},
+						AdmissionPlugins: []string{"PodNodeSelector"},
+					},
 				},
 				Kubeconfig: &kubeconfig,
 				KymaConfig: fixKymaConfig(nil),
+				Hibernated: true,
 			},
 		}
 
@@ -166,10 +191,33 @@ func TestRuntimeStatusToGraphQLStatus(t *testing.T) {
 					ProviderSpecificConfig: gqlschema.GCPProviderConfig{
 						Zones: zones,
 					},
+					HibernationSchedules: []*gqlschema.HibernationSchedule{
+						{Start: &hibernationStart, Location: &hibernationLocation},
+					},
+					WorkerPools: []*gqlschema.WorkerPool{
+						{
+							Name:           "memory",
+							MachineType:    &poolMachine,
+							DiskType:       &disk,
+							VolumeSizeGb:   &volume,
+							AutoScalerMin:  &poolAutoScMin,
+							AutoScalerMax:  &autoScMax,
+							MaxSurge:       util.IntPtr(0),
+							MaxUnavailable: util.IntPtr(0),
+							Zones:          []string{"fix-gcp-zone-2"},
+							Labels:         &gqlschema.Labels{"workload": "memory"},
+							Taints:         []*gqlschema.Taint{{Key: "dedicated", Effect: "NoSchedule"}},
+						},
+					},
+					KubeAPIServerConfig: &gqlschema.KubeAPIServerConfig{
+						OidcConfig:       &gqlschema.OIDCConfig{IssuerURL: "https://accounts.example.com", ClientID: "kyma", GroupsClaim: util.StringPtr("groups")},
+						AdmissionPlugins: []string{"PodNodeSelector"},
+					},
 				},
 				KymaConfig: fixKymaGraphQLConfig(nil),
 				Kubeconfig: &kubeconfig,
 			},
+			Hibernated: true,
 		}
 
 		//when
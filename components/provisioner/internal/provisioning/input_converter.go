@@ -1,6 +1,8 @@
 package provisioning
 
 import (
+	"fmt"
+
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/installation/release"
@@ -15,6 +17,8 @@ type InputConverter interface {
 	ProvisioningInputToCluster(runtimeID string, input gqlschema.ProvisionRuntimeInput, tenant, subAccountId string) (model.Cluster, apperrors.AppError)
 	KymaConfigFromInput(runtimeID string, input gqlschema.KymaConfigInput) (model.KymaConfig, apperrors.AppError)
 	UpgradeShootInputToGardenerConfig(input gqlschema.GardenerUpgradeInput, existing model.GardenerConfig) (model.GardenerConfig, apperrors.AppError)
+	RuntimesFilterFromInput(input *gqlschema.RuntimesFilter) model.RuntimeFilter
+	OperationsFilterFromInput(input *gqlschema.OperationsFilter) (model.OperationFilter, apperrors.AppError)
 }
 
 func NewInputConverter(
@@ -86,7 +90,7 @@ func (c converter) gardenerConfigFromInput(runtimeID string, input *gqlschema.Ga
 		return model.GardenerConfig{}, err
 	}
 
-	return model.GardenerConfig{
+	config := model.GardenerConfig{
 		ID:                                  c.uuidGenerator.New(),
 		Name:                                setClusterName(input.Name),
 		ProjectName:                         c.gardenerProject,
@@ -112,7 +116,115 @@ func (c converter) gardenerConfigFromInput(runtimeID string, input *gqlschema.Ga
 		AllowPrivilegedContainers:           allowPrivilegedContainers,
 		ClusterID:                           runtimeID,
 		GardenerProviderConfig:              providerSpecificConfig,
-	}, nil
+		HibernationSchedules:                hibernationSchedulesFromInput(input.HibernationSchedules),
+		KubeAPIServerConfig:                 kubeAPIServerConfigFromInput(input.KubeAPIServerConfig),
+	}
+	config.WorkerPools = c.workerPoolsFromInput(input.WorkerPools, config)
+
+	return config, nil
+}
+
+func hibernationSchedulesFromInput(input []*gqlschema.HibernationScheduleInput) []model.HibernationSchedule {
+	var schedules []model.HibernationSchedule
+	for _, schedule := range input {
+		if schedule == nil {
+			continue
+		}
+		schedules = append(schedules, model.HibernationSchedule{
+			Start:    schedule.Start,
+			End:      schedule.End,
+			Location: schedule.Location,
+		})
+	}
+
+	return schedules
+}
+
+func kubeAPIServerConfigFromInput(input *gqlschema.KubeAPIServerConfigInput) *model.KubeAPIServerConfig {
+	if input == nil {
+		return nil
+	}
+
+	config := &model.KubeAPIServerConfig{
+		AdmissionPlugins: input.AdmissionPlugins,
+	}
+	if input.OidcConfig != nil {
+		config.OIDCConfig = &model.OIDCConfig{
+			IssuerURL:      input.OidcConfig.IssuerURL,
+			ClientID:       input.OidcConfig.ClientID,
+			UsernameClaim:  input.OidcConfig.UsernameClaim,
+			UsernamePrefix: input.OidcConfig.UsernamePrefix,
+			GroupsClaim:    input.OidcConfig.GroupsClaim,
+			GroupsPrefix:   input.OidcConfig.GroupsPrefix,
+			SigningAlgs:    input.OidcConfig.SigningAlgs,
+		}
+	}
+
+	return config
+}
+
+// workerPoolsFromInput converts additional worker pools, values not provided in the input are taken from the primary worker pool of the cluster
+func (c converter) workerPoolsFromInput(input []*gqlschema.WorkerPoolInput, config model.GardenerConfig) []model.WorkerPool {
+	var workerPools []model.WorkerPool
+	for _, pool := range input {
+		if pool == nil {
+			continue
+		}
+
+		machineImage, machineImageVersion := config.MachineImage, config.MachineImageVersion
+		if pool.MachineImage != nil {
+			machineImage, machineImageVersion = pool.MachineImage, pool.MachineImageVersion
+		}
+
+		workerPools = append(workerPools, model.WorkerPool{
+			ID:                  c.uuidGenerator.New(),
+			GardenerConfigID:    config.ID,
+			Name:                pool.Name,
+			MachineType:         pool.MachineType,
+			MachineImage:        machineImage,
+			MachineImageVersion: machineImageVersion,
+			DiskType:            util.UnwrapStrOrDefault(pool.DiskType, config.DiskType),
+			VolumeSizeGB:        util.UnwrapIntOrDefault(pool.VolumeSizeGb, config.VolumeSizeGB),
+			AutoScalerMin:       pool.AutoScalerMin,
+			AutoScalerMax:       pool.AutoScalerMax,
+			MaxSurge:            util.UnwrapIntOrDefault(pool.MaxSurge, config.MaxSurge),
+			MaxUnavailable:      util.UnwrapIntOrDefault(pool.MaxUnavailable, config.MaxUnavailable),
+			Zones:               pool.Zones,
+			Labels:              workerPoolLabelsFromInput(pool.Labels),
+			Taints:              taintsFromInput(pool.Taints),
+		})
+	}
+
+	return workerPools
+}
+
+func workerPoolLabelsFromInput(input *gqlschema.Labels) map[string]string {
+	if input == nil || len(*input) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(*input))
+	for key, value := range *input {
+		labels[key] = fmt.Sprint(value)
+	}
+
+	return labels
+}
+
+func taintsFromInput(input []*gqlschema.TaintInput) []model.Taint {
+	var taints []model.Taint
+	for _, taint := range input {
+		if taint == nil {
+			continue
+		}
+		taints = append(taints, model.Taint{
+			Key:    taint.Key,
+			Value:  util.UnwrapStr(taint.Value),
+			Effect: taint.Effect,
+		})
+	}
+
+	return taints
 }
 
 func (c converter) shouldAllowPrivilegedContainers(inputAllowPrivilegedContainers *bool, tillerYaml string) bool {
@@ -141,7 +253,17 @@ func (c converter) UpgradeShootInputToGardenerConfig(input gqlschema.GardenerUpg
 		purpose = input.Purpose
 	}
 
-	return model.GardenerConfig{
+	hibernationSchedules := config.HibernationSchedules
+	if input.HibernationSchedules != nil {
+		hibernationSchedules = hibernationSchedulesFromInput(input.HibernationSchedules)
+	}
+
+	kubeAPIServerConfig := config.KubeAPIServerConfig
+	if input.KubeAPIServerConfig != nil {
+		kubeAPIServerConfig = kubeAPIServerConfigFromInput(input.KubeAPIServerConfig)
+	}
+
+	upgradedConfig := model.GardenerConfig{
 		ID:                        config.ID,
 		ClusterID:                 config.ClusterID,
 		Name:                      config.Name,
@@ -167,7 +289,16 @@ func (c converter) UpgradeShootInputToGardenerConfig(input gqlschema.GardenerUpg
 		EnableKubernetesVersionAutoUpdate:   util.UnwrapBoolOrDefault(input.EnableKubernetesVersionAutoUpdate, config.EnableKubernetesVersionAutoUpdate),
 		EnableMachineImageVersionAutoUpdate: util.UnwrapBoolOrDefault(input.EnableMachineImageVersionAutoUpdate, config.EnableMachineImageVersionAutoUpdate),
 		GardenerProviderConfig:              providerSpecificConfig,
-	}, nil
+		HibernationSchedules:                hibernationSchedules,
+		KubeAPIServerConfig:                 kubeAPIServerConfig,
+	}
+
+	upgradedConfig.WorkerPools = config.WorkerPools
+	if input.WorkerPools != nil {
+		upgradedConfig.WorkerPools = c.workerPoolsFromInput(input.WorkerPools, upgradedConfig)
+	}
+
+	return upgradedConfig, nil
 }
 
 func (c converter) providerSpecificConfigFromInput(input *gqlschema.ProviderSpecificInput) (model.GardenerProviderConfig, apperrors.AppError) {
@@ -259,6 +390,88 @@ func configEntryFromInput(entry *gqlschema.ConfigEntryInput) model.ConfigEntry {
 	return model.NewConfigEntry(entry.Key, entry.Value, util.UnwrapBoolOrDefault(entry.Secret, false))
 }
 
+func (c converter) RuntimesFilterFromInput(input *gqlschema.RuntimesFilter) model.RuntimeFilter {
+	if input == nil {
+		return model.RuntimeFilter{}
+	}
+
+	return model.RuntimeFilter{
+		Tenant:            util.UnwrapStr(input.Tenant),
+		SubAccountID:      util.UnwrapStr(input.SubAccountID),
+		Provider:          util.UnwrapStr(input.Provider),
+		Region:            util.UnwrapStr(input.Region),
+		KubernetesVersion: util.UnwrapStr(input.KubernetesVersion),
+		KymaVersion:       util.UnwrapStr(input.KymaVersion),
+	}
+}
+
+func (c converter) OperationsFilterFromInput(input *gqlschema.OperationsFilter) (model.OperationFilter, apperrors.AppError) {
+	if input == nil {
+		return model.OperationFilter{}, nil
+	}
+
+	filter := model.OperationFilter{
+		Tenant:       util.UnwrapStr(input.Tenant),
+		SubAccountID: util.UnwrapStr(input.SubAccountID),
+		RuntimeID:    util.UnwrapStr(input.RuntimeID),
+	}
+
+	if input.Operation != nil {
+		operationType, err := c.graphQLOperationTypeToOperationType(*input.Operation)
+		if err != nil {
+			return model.OperationFilter{}, err
+		}
+		filter.Type = operationType
+	}
+
+	if input.State != nil {
+		state, err := c.graphQLOperationStateToOperationState(*input.State)
+		if err != nil {
+			return model.OperationFilter{}, err
+		}
+		filter.State = state
+	}
+
+	return filter, nil
+}
+
+func (c converter) graphQLOperationTypeToOperationType(operationType gqlschema.OperationType) (model.OperationType, apperrors.AppError) {
+	switch operationType {
+	case gqlschema.OperationTypeProvision:
+		return model.Provision, nil
+	case gqlschema.OperationTypeUpgrade:
+		return model.Upgrade, nil
+	case gqlschema.OperationTypeUpgradeShoot:
+		return model.UpgradeShoot, nil
+	case gqlschema.OperationTypeDeprovision:
+		return model.Deprovision, nil
+	case gqlschema.OperationTypeReconnectRuntime:
+		return model.ReconnectRuntime, nil
+	case gqlschema.OperationTypeHibernate:
+		return model.Hibernate, nil
+	case gqlschema.OperationTypeWakeUp:
+		return model.WakeUp, nil
+	case gqlschema.OperationTypeRollback:
+		return model.Rollback, nil
+	default:
+		return "", apperrors.BadRequest("unsupported operation type: %s", operationType)
+	}
+}
+
+func (c converter) graphQLOperationStateToOperationState(state gqlschema.OperationState) (model.OperationState, apperrors.AppError) {
+	switch state {
+	case gqlschema.OperationStateInProgress:
+		return model.InProgress, nil
+	case gqlschema.OperationStateSucceeded:
+		return model.Succeeded, nil
+	case gqlschema.OperationStateFailed:
+		return model.Failed, nil
+	default:
+		// Operations are never stored in the Pending state
+		return "", apperrors.BadRequest("unsupported operation state: %s", state)
+	}
+}
+
 //TODO Remove when name is changed to obligatory field
 func setClusterName(name *string) string {
 	if name != nil {
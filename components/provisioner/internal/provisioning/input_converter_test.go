@@ -374,6 +374,86 @@ func Test_ProvisioningInputToCluster(t *testing.T) {
 		assert.Equal(t, expectedGardenerAzureRuntimeConfig, runtimeConfig)
 		uuidGeneratorMock.AssertExpectations(t)
 	})
+
+	t.Run("Should convert worker pools using values of the primary worker pool as defaults", func(t *testing.T) {
+		// given
+		gardenerAzureGQLInput := createGQLRuntimeInputAzure(nil)
+		gardenerAzureGQLInput.ClusterConfig.GardenerConfig.WorkerPools = []*gqlschema.WorkerPoolInput{
+			{
+				Name:          "memory",
+				MachineType:   "m5.8xlarge",
+				AutoScalerMin: 0,
+				AutoScalerMax: 3,
+				Labels:        &gqlschema.Labels{"workload": "memory"},
+				Taints:        []*gqlschema.TaintInput{{Key: "dedicated", Value: util.StringPtr("memory"), Effect: "NoSchedule"}},
+			},
+			{
+				Name:           "custom",
+				MachineType:    "m5.xlarge",
+				MachineImage:   util.StringPtr("ubuntu"),
+				DiskType:       util.StringPtr("hdd"),
+				VolumeSizeGb:   util.IntPtr(50),
+				AutoScalerMin:  1,
+				AutoScalerMax:  2,
+				MaxSurge:       util.IntPtr(3),
+				MaxUnavailable: util.IntPtr(0),
+				Zones:          []string{"2"},
+			},
+		}
+
+		expectedGardenerAzureRuntimeConfig := expectedGardenerAzureRuntimeConfig(nil)
+		expectedGardenerAzureRuntimeConfig.ClusterConfig.WorkerPools = []model.WorkerPool{
+			{
+				ID:                  "id",
+				GardenerConfigID:    "id",
+				Name:                "memory",
+				MachineType:         "m5.8xlarge",
+				MachineImage:        util.StringPtr("gardenlinux"),
+				MachineImageVersion: util.StringPtr("25.0.0"),
+				DiskType:            "ssd",
+				VolumeSizeGB:        1024,
+				AutoScalerMin:       0,
+				AutoScalerMax:       3,
+				MaxSurge:            1,
+				MaxUnavailable:      2,
+				Labels:              map[string]string{"workload": "memory"},
+				Taints:              []model.Taint{{Key: "dedicated", Value: "memory", Effect: "NoSchedule"}},
+			},
+			{
+				ID:               "id",
+				GardenerConfigID: "id",
+				Name:             "custom",
+				MachineType:      "m5.xlarge",
+				MachineImage:     util.StringPtr("ubuntu"),
+				DiskType:         "hdd",
+				VolumeSizeGB:     50,
+				AutoScalerMin:    1,
+				AutoScalerMax:    2,
+				MaxSurge:         3,
+				MaxUnavailable:   0,
+				Zones:            []string{"2"},
+			},
+		}
+
+		uuidGeneratorMock := &mocks.UUIDGenerator{}
+		uuidGeneratorMock.On("New").Return("id")
+
+		inputConverter := NewInputConverter(
+			uuidGeneratorMock,
+			releaseProvider,
+			gardenerProject,
+			defaultEnableKubernetesVersionAutoUpdate,
+			defaultEnableMachineImageVersionAutoUpdate,
+			forceAllowPrivilegedContainers)
+
+		// when
+		runtimeConfig, err := inputConverter.ProvisioningInputToCluster("runtimeID", gardenerAzureGQLInput, tenant, subAccountId)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, expectedGardenerAzureRuntimeConfig, runtimeConfig)
+		uuidGeneratorMock.AssertExpectations(t)
+	})
 }
 
 func TestConverter_ProvisioningInputToCluster_Error(t *testing.T) {
@@ -492,6 +572,10 @@ func Test_UpgradeShootInputToGardenerConfig(t *testing.T) {
 	initialGCPProviderConfig, _ := model.NewGCPGardenerConfig(&gqlschema.GCPProviderConfigInput{Zones: []string{"europe-west1-a"}})
 	upgradedGCPProviderConfig, _ := model.NewGCPGardenerConfig(&gqlschema.GCPProviderConfigInput{Zones: []string{"europe-west1-a", "europe-west1-b"}})
 
+	hibernationSchedules := []model.HibernationSchedule{{Start: util.StringPtr("00 20 * * 1,2,3,4,5"), Location: util.StringPtr("Europe/Berlin")}}
+	workerPools := []model.WorkerPool{{ID: "pool-id", GardenerConfigID: "config-id", Name: "memory", MachineType: "m5.8xlarge", AutoScalerMax: 3}}
+	kubeAPIServerConfig := &model.KubeAPIServerConfig{OIDCConfig: &model.OIDCConfig{IssuerURL: "https://accounts.example.com", ClientID: "kyma"}}
+
 	initialAzureProviderConfig, _ := model.NewAzureGardenerConfig(&gqlschema.AzureProviderConfigInput{Zones: []string{"1"}})
 	upgradedAzureProviderConfig, _ := model.NewAzureGardenerConfig(&gqlschema.AzureProviderConfigInput{Zones: []string{"1", "2"}})
 
@@ -583,26 +667,114 @@ func Test_UpgradeShootInputToGardenerConfig(t *testing.T) {
 		{description: "shoot upgrade with nil values",
 			upgradeInput: newUpgradeShootInputWithNilValues(),
 			initialConfig: model.GardenerConfig{
-				KubernetesVersion: "version",
-				VolumeSizeGB:      1,
-				DiskType:          "ssd",
-				MachineType:       "1",
-				Purpose:           &evaluationPurpose,
-				AutoScalerMin:     1,
-				AutoScalerMax:     2,
-				MaxSurge:          1,
-				MaxUnavailable:    1,
+				KubernetesVersion:    "version",
+				VolumeSizeGB:         1,
+				DiskType:             "ssd",
+				MachineType:          "1",
+				Purpose:              &evaluationPurpose,
+				AutoScalerMin:        1,
+				AutoScalerMax:        2,
+				MaxSurge:             1,
+				MaxUnavailable:       1,
+				HibernationSchedules: hibernationSchedules,
+			},
+			upgradedConfig: model.GardenerConfig{
+				KubernetesVersion:    "version",
+				VolumeSizeGB:         1,
+				DiskType:             "ssd",
+				MachineType:          "1",
+				Purpose:              &evaluationPurpose,
+				AutoScalerMin:        1,
+				AutoScalerMax:        2,
+				MaxSurge:             1,
+				MaxUnavailable:       1,
+				HibernationSchedules: hibernationSchedules,
+			},
+		},
+		{description: "shoot upgrade removing hibernation schedules",
+			upgradeInput: newUpgradeShootInputWithHibernationSchedules([]*gqlschema.HibernationScheduleInput{}),
+			initialConfig: model.GardenerConfig{
+				KubernetesVersion:    "version",
+				MachineType:          "1",
+				HibernationSchedules: hibernationSchedules,
 			},
 			upgradedConfig: model.GardenerConfig{
 				KubernetesVersion: "version",
-				VolumeSizeGB:      1,
-				DiskType:          "ssd",
 				MachineType:       "1",
-				Purpose:           &evaluationPurpose,
-				AutoScalerMin:     1,
-				AutoScalerMax:     2,
-				MaxSurge:          1,
-				MaxUnavailable:    1,
+			},
+		},
+		{description: "shoot upgrade replacing hibernation schedules",
+			upgradeInput: newUpgradeShootInputWithHibernationSchedules([]*gqlschema.HibernationScheduleInput{{End: util.StringPtr("00 08 * * 1")}}),
+			initialConfig: model.GardenerConfig{
+				KubernetesVersion:    "version",
+				MachineType:          "1",
+				HibernationSchedules: hibernationSchedules,
+			},
+			upgradedConfig: model.GardenerConfig{
+				KubernetesVersion:    "version",
+				MachineType:          "1",
+				HibernationSchedules: []model.HibernationSchedule{{End: util.StringPtr("00 08 * * 1")}},
+			},
+		},
+		{description: "shoot upgrade keeping worker pools",
+			upgradeInput: newUpgradeShootInputWithNilValues(),
+			initialConfig: model.GardenerConfig{
+				ID:          "config-id",
+				MachineType: "1",
+				WorkerPools: workerPools,
+			},
+			upgradedConfig: model.GardenerConfig{
+				ID:          "config-id",
+				MachineType: "1",
+				WorkerPools: workerPools,
+			},
+		},
+		{description: "shoot upgrade removing worker pools",
+			upgradeInput: newUpgradeShootInputWithWorkerPools([]*gqlschema.WorkerPoolInput{}),
+			initialConfig: model.GardenerConfig{
+				ID:          "config-id",
+				MachineType: "1",
+				WorkerPools: workerPools,
+			},
+			upgradedConfig: model.GardenerConfig{
+				ID:          "config-id",
+				MachineType: "1",
+			},
+		},
+		{description: "shoot upgrade keeping kube-apiserver config",
+			upgradeInput: newUpgradeShootInputWithNilValues(),
+			initialConfig: model.GardenerConfig{
+				MachineType:         "1",
+				KubeAPIServerConfig: kubeAPIServerConfig,
+			},
+			upgradedConfig: model.GardenerConfig{
+				MachineType:         "1",
+				KubeAPIServerConfig: kubeAPIServerConfig,
+			},
+		},
+		{description: "shoot upgrade replacing kube-apiserver config",
+			upgradeInput: newUpgradeShootInputWithKubeAPIServerConfig(&gqlschema.KubeAPIServerConfigInput{
+				OidcConfig: &gqlschema.OIDCConfigInput{
+					IssuerURL:     "https://login.example.com",
+					ClientID:      "runtime",
+					UsernameClaim: util.StringPtr("email"),
+				},
+				AdmissionPlugins: []string{"PodNodeSelector"},
+			}),
+			initialConfig: model.GardenerConfig{
+				MachineType:         "1",
+				KubeAPIServerConfig: kubeAPIServerConfig,
+			},
+			upgradedConfig: model.GardenerConfig{
+				MachineType: "1",
+				KubeAPIServerConfig: &model.KubeAPIServerConfig{
+					OIDCConfig: &model.OIDCConfig{
+						IssuerURL:     "https://login.example.com",
+						ClientID:      "runtime",
+						UsernameClaim: util.StringPtr("email"),
+					},
+					AdmissionPlugins: []string{"PodNodeSelector"},
+				},
 			},
 		},
 	}
@@ -652,6 +824,46 @@ func Test_UpgradeShootInputToGardenerConfig(t *testing.T) {
 		})
 	}
 
+	t.Run("shoot upgrade replacing worker pools", func(t *testing.T) {
+		//given
+		uuidGeneratorMock := &mocks.UUIDGenerator{}
+		uuidGeneratorMock.On("New").Return("new-pool-id").Once()
+		inputConverter := NewInputConverter(
+			uuidGeneratorMock,
+			releaseProvider,
+			gardenerProject,
+			defaultEnableKubernetesVersionAutoUpdate,
+			defaultEnableMachineImageVersionAutoUpdate,
+			forceAllowPrivilegedContainers,
+		)
+
+		upgradeInput := newUpgradeShootInputWithWorkerPools([]*gqlschema.WorkerPoolInput{{Name: "memory", MachineType: "m5.4xlarge", AutoScalerMax: 5}})
+		upgradeInput.GardenerConfig.DiskType = util.StringPtr("papyrus")
+		initialConfig := model.GardenerConfig{
+			ID:           "config-id",
+			MachineType:  "1",
+			DiskType:     "ssd",
+			VolumeSizeGB: 30,
+			WorkerPools:  workerPools,
+		}
+
+		//when
+		shootConfig, err := inputConverter.UpgradeShootInputToGardenerConfig(*upgradeInput.GardenerConfig, initialConfig)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, []model.WorkerPool{{
+			ID:               "new-pool-id",
+			GardenerConfigID: "config-id",
+			Name:             "memory",
+			MachineType:      "m5.4xlarge",
+			DiskType:         "papyrus",
+			VolumeSizeGB:     30,
+			AutoScalerMax:    5,
+		}}, shootConfig.WorkerPools)
+		uuidGeneratorMock.AssertExpectations(t)
+	})
+
 	for _, testCase := range casesWithErrors {
 		t.Run(testCase.description, func(t *testing.T) {
 			//given
@@ -675,6 +887,61 @@ func Test_UpgradeShootInputToGardenerConfig(t *testing.T) {
 	}
 }
 
+func Test_FiltersFromInput(t *testing.T) {
+	inputConverter := NewInputConverter(&mocks.UUIDGenerator{}, nil, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate, forceAllowPrivilegedContainers)
+
+	t.Run("should convert Runtimes filter", func(t *testing.T) {
+		//when
+		filter := inputConverter.RuntimesFilterFromInput(&gqlschema.RuntimesFilter{
+			Tenant:            util.StringPtr("tenant"),
+			Provider:          util.StringPtr("azure"),
+			KubernetesVersion: util.StringPtr("1.16.9"),
+			KymaVersion:       util.StringPtr("1.17.0"),
+		})
+
+		//then
+		assert.Equal(t, model.RuntimeFilter{
+			Tenant:            "tenant",
+			Provider:          "azure",
+			KubernetesVersion: "1.16.9",
+			KymaVersion:       "1.17.0",
+		}, filter)
+		assert.Equal(t, model.RuntimeFilter{}, inputConverter.RuntimesFilterFromInput(nil))
+	})
+
+	t.Run("should convert operations filter", func(t *testing.T) {
+		//given
+		operationType := gqlschema.OperationTypeUpgradeShoot
+		state := gqlschema.OperationStateInProgress
+
+		//when
+		filter, err := inputConverter.OperationsFilterFromInput(&gqlschema.OperationsFilter{
+			SubAccountID: util.StringPtr("sub-account"),
+			Operation:    &operationType,
+			State:        &state,
+		})
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, model.OperationFilter{
+			SubAccountID: "sub-account",
+			Type:         model.UpgradeShoot,
+			State:        model.InProgress,
+		}, filter)
+	})
+
+	t.Run("should return error when filtering operations by Pending state", func(t *testing.T) {
+		//given
+		state := gqlschema.OperationStatePending
+
+		//when
+		_, err := inputConverter.OperationsFilterFromInput(&gqlschema.OperationsFilter{State: &state})
+
+		//then
+		require.Error(t, err)
+	})
+}
+
 func newUpgradeShootInput(newPurpose string) gqlschema.UpgradeShootInput {
 	return gqlschema.UpgradeShootInput{
 		GardenerConfig: &gqlschema.GardenerUpgradeInput{
@@ -709,6 +976,24 @@ func newUpgradeShootInputWithNilValues() gqlschema.UpgradeShootInput {
 	}
 }
 
+func newUpgradeShootInputWithHibernationSchedules(schedules []*gqlschema.HibernationScheduleInput) gqlschema.UpgradeShootInput {
+	input := newUpgradeShootInputWithNilValues()
+	input.GardenerConfig.HibernationSchedules = schedules
+	return input
+}
+
+func newUpgradeShootInputWithWorkerPools(workerPools []*gqlschema.WorkerPoolInput) gqlschema.UpgradeShootInput {
+	input := newUpgradeShootInputWithNilValues()
+	input.GardenerConfig.WorkerPools = workerPools
+	return input
+}
+
+func newUpgradeShootInputWithKubeAPIServerConfig(config *gqlschema.KubeAPIServerConfigInput) gqlschema.UpgradeShootInput {
+	input := newUpgradeShootInputWithNilValues()
+	input.GardenerConfig.KubeAPIServerConfig = config
+	return input
+}
+
 func newGCPUpgradeShootInput(newPurpose string) gqlschema.UpgradeShootInput {
 	input := newUpgradeShootInput(newPurpose)
 	input.GardenerConfig.ProviderSpecificConfig = &gqlschema.ProviderSpecificInput{
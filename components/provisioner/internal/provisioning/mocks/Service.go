@@ -37,6 +37,81 @@ func (_m *Service) DeprovisionRuntime(id string, tenant string) (string, apperro
 	return r0, r1
 }
 
+// HibernateRuntime provides a mock function with given fields: id
+func (_m *Service) HibernateRuntime(id string) (*gqlschema.OperationStatus, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 *gqlschema.OperationStatus
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.OperationStatus); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OperationStatus)
+		}
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListOperations provides a mock function with given fields: filter, first, after
+func (_m *Service) ListOperations(filter *gqlschema.OperationsFilter, first *int, after *string) (*gqlschema.OperationsPage, apperrors.AppError) {
+	ret := _m.Called(filter, first, after)
+
+	var r0 *gqlschema.OperationsPage
+	if rf, ok := ret.Get(0).(func(*gqlschema.OperationsFilter, *int, *string) *gqlschema.OperationsPage); ok {
+		r0 = rf(filter, first, after)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OperationsPage)
+		}
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(*gqlschema.OperationsFilter, *int, *string) apperrors.AppError); ok {
+		r1 = rf(filter, first, after)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListRuntimes provides a mock function with given fields: filter, first, after
+func (_m *Service) ListRuntimes(filter *gqlschema.RuntimesFilter, first *int, after *string) (*gqlschema.RuntimesPage, apperrors.AppError) {
+	ret := _m.Called(filter, first, after)
+
+	var r0 *gqlschema.RuntimesPage
+	if rf, ok := ret.Get(0).(func(*gqlschema.RuntimesFilter, *int, *string) *gqlschema.RuntimesPage); ok {
+		r0 = rf(filter, first, after)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.RuntimesPage)
+		}
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(*gqlschema.RuntimesFilter, *int, *string) apperrors.AppError); ok {
+		r1 = rf(filter, first, after)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // ProvisionRuntime provides a mock function with given fields: config, tenant, subAccount
 func (_m *Service) ProvisionRuntime(config gqlschema.ProvisionRuntimeInput, tenant string, subAccount string) (*gqlschema.OperationStatus, apperrors.AppError) {
 	ret := _m.Called(config, tenant, subAccount)
@@ -110,6 +185,31 @@ func (_m *Service) RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStat
 	return r0, r1
 }
 
+// RollbackRuntimeUpgrade provides a mock function with given fields: runtimeID
+func (_m *Service) RollbackRuntimeUpgrade(runtimeID string) (*gqlschema.OperationStatus, apperrors.AppError) {
+	ret := _m.Called(runtimeID)
+
+	var r0 *gqlschema.OperationStatus
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.OperationStatus); ok {
+		r0 = rf(runtimeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OperationStatus)
+		}
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(runtimeID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // RuntimeOperationStatus provides a mock function with given fields: id
 func (_m *Service) RuntimeOperationStatus(id string) (*gqlschema.OperationStatus, apperrors.AppError) {
 	ret := _m.Called(id)
@@ -209,3 +309,28 @@ func (_m *Service) UpgradeRuntime(id string, config gqlschema.UpgradeRuntimeInpu
 
 	return r0, r1
 }
+
+// WakeUpRuntime provides a mock function with given fields: id
+func (_m *Service) WakeUpRuntime(id string) (*gqlschema.OperationStatus, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 *gqlschema.OperationStatus
+	if rf, ok := ret.Get(0).(func(string) *gqlschema.OperationStatus); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gqlschema.OperationStatus)
+		}
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
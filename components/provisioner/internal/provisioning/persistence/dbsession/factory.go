@@ -24,9 +24,15 @@ type ReadSession interface {
 	GetGardenerClusterByName(name string) (model.Cluster, dberrors.Error)
 	GetTenant(runtimeID string) (string, dberrors.Error)
 	ListInProgressOperations() ([]model.Operation, dberrors.Error)
+	ListOperationsChangedSince(since time.Time) ([]model.Operation, dberrors.Error)
+	ListUnleasedInProgressOperations(now time.Time) ([]model.Operation, dberrors.Error)
+	ListKubeconfigsToReEncrypt(currentPrefix, afterID string, limit int) ([]model.Cluster, dberrors.Error)
 	GetRuntimeUpgrade(operationId string) (model.RuntimeUpgrade, dberrors.Error)
 	GetTenantForOperation(operationID string) (string, dberrors.Error)
 	InProgressOperationsCount() (model.OperationsCount, dberrors.Error)
+	ListRuntimes(filter model.RuntimeFilter, after *model.PageCursor, limit int) ([]model.Cluster, int, dberrors.Error)
+	ListLastOperations(runtimeIDs []string) (map[string]model.Operation, dberrors.Error)
+	ListOperations(filter model.OperationFilter, after *model.PageCursor, limit int) ([]model.Operation, int, dberrors.Error)
 }
 
 //go:generate mockery -name=WriteSession
@@ -39,12 +45,18 @@ type WriteSession interface {
 	UpdateOperationState(operationID string, message string, state model.OperationState, endTime time.Time) dberrors.Error
 	TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) dberrors.Error
 	UpdateKubeconfig(runtimeID string, kubeconfig string) dberrors.Error
+	ReplaceKubeconfig(runtimeID string, oldKubeconfig string, newKubeconfig string) dberrors.Error
+	ClearKubeconfigs() dberrors.Error
 	SetActiveKymaConfig(runtimeID string, kymaConfigId string) dberrors.Error
 	UpdateUpgradeState(operationID string, upgradeState model.UpgradeState) dberrors.Error
 	DeleteCluster(runtimeID string) dberrors.Error
 	MarkClusterAsDeleted(runtimeID string) dberrors.Error
+	SetHibernated(runtimeID string, hibernated bool) dberrors.Error
 	InsertRuntimeUpgrade(runtimeUpgrade model.RuntimeUpgrade) dberrors.Error
 	FixShootProvisioningStage(message string, newStage model.OperationStage, transitionTime time.Time) dberrors.Error
+	AcquireOperationLease(operationID string, holder string, previousHolder string, now time.Time, expiration time.Time) (bool, dberrors.Error)
+	RenewOperationLease(operationID string, holder string, expiration time.Time) (bool, dberrors.Error)
+	ReleaseOperationLease(operationID string, holder string) dberrors.Error
 }
 
 //go:generate mockery -name=ReadWriteSession
@@ -65,32 +77,41 @@ type WriteSessionWithinTransaction interface {
 }
 
 type factory struct {
-	connection *dbr.Connection
+	connection  *dbr.Connection
+	kubeconfigs kubeconfigStore
 }
 
-func NewFactory(connection *dbr.Connection) Factory {
+// NewFactory returns Factory of database sessions. Kubeconfigs are encrypted with the cipher if it is not nil.
+// If the kubeconfigProvider is not nil kubeconfigs are not stored in the database and are fetched with the provider instead.
+func NewFactory(connection *dbr.Connection, cipher KubeconfigCipher, kubeconfigProvider KubeconfigProvider) Factory {
 	return &factory{
 		connection: connection,
+		kubeconfigs: kubeconfigStore{
+			cipher:   cipher,
+			provider: kubeconfigProvider,
+		},
 	}
 }
 
 func (sf *factory) NewReadSession() ReadSession {
 	return readSession{
-		session: sf.connection.NewSession(nil),
+		session:     sf.connection.NewSession(nil),
+		kubeconfigs: sf.kubeconfigs,
 	}
 }
 
 func (sf *factory) NewWriteSession() WriteSession {
 	return writeSession{
-		session: sf.connection.NewSession(nil),
+		session:     sf.connection.NewSession(nil),
+		kubeconfigs: sf.kubeconfigs,
 	}
 }
 
 func (sf *factory) NewReadWriteSession() ReadWriteSession {
 	session := sf.connection.NewSession(nil)
 	return readWriteSession{
-		readSession:  readSession{session: session},
-		writeSession: writeSession{session: session},
+		readSession:  readSession{session: session, kubeconfigs: sf.kubeconfigs},
+		writeSession: writeSession{session: session, kubeconfigs: sf.kubeconfigs},
 	}
 }
 
@@ -110,5 +131,6 @@ func (sf *factory) NewSessionWithinTransaction() (WriteSessionWithinTransaction,
 	return writeSession{
 		session:     dbSession,
 		transaction: dbTransaction,
+		kubeconfigs: sf.kubeconfigs,
 	}, nil
 }
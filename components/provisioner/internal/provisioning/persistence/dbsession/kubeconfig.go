@@ -0,0 +1,88 @@
+package dbsession
+
+import (
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/persistence/dberrors"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// KubeconfigCipher encrypts kubeconfigs before they are stored in the database
+type KubeconfigCipher interface {
+	Encrypt(value []byte) ([]byte, error)
+	Decrypt(value []byte) ([]byte, error)
+}
+
+// KubeconfigProvider fetches kubeconfigs of the clusters from Gardener when they are not stored in the database
+type KubeconfigProvider interface {
+	FetchRaw(shootName string) ([]byte, error)
+}
+
+// kubeconfigStore encrypts stored kubeconfigs if the cipher is set,
+// if the provider is set kubeconfigs are not stored at all and are fetched on demand
+type kubeconfigStore struct {
+	cipher   KubeconfigCipher
+	provider KubeconfigProvider
+}
+
+func (s kubeconfigStore) seal(kubeconfig string) (*string, dberrors.Error) {
+	if s.provider != nil {
+		return nil, nil
+	}
+
+	if s.cipher == nil {
+		return &kubeconfig, nil
+	}
+
+	encrypted, err := s.cipher.Encrypt([]byte(kubeconfig))
+	if err != nil {
+		return nil, dberrors.Internal("Failed to encrypt kubeconfig: %s", err)
+	}
+	sealed := string(encrypted)
+
+	return &sealed, nil
+}
+
+func (s kubeconfigStore) open(cluster *model.Cluster) dberrors.Error {
+	if s.provider != nil {
+		kubeconfig, err := s.fetch(cluster.ClusterConfig.Name)
+		if err != nil {
+			return dberrors.Internal("Failed to fetch kubeconfig of cluster %s: %s", cluster.ID, err)
+		}
+		cluster.Kubeconfig = kubeconfig
+		return nil
+	}
+
+	if s.cipher == nil || cluster.Kubeconfig == nil {
+		return nil
+	}
+
+	decrypted, err := s.cipher.Decrypt([]byte(*cluster.Kubeconfig))
+	if err != nil {
+		return dberrors.Internal("Failed to decrypt kubeconfig of cluster %s: %s", cluster.ID, err)
+	}
+	kubeconfig := string(decrypted)
+	cluster.Kubeconfig = &kubeconfig
+
+	return nil
+}
+
+// fetch returns nil if the kubeconfig Secret does not exist because the Shoot is not created yet
+func (s kubeconfigStore) fetch(shootName string) (*string, error) {
+	if shootName == "" {
+		return nil, nil
+	}
+
+	raw, err := s.provider.FetchRaw(shootName)
+	if err != nil {
+		if k8serrors.IsNotFound(errors.Cause(err)) {
+			log.Debugf("Kubeconfig of Shoot %s not available yet: %s", shootName, err.Error())
+			return nil, nil
+		}
+		return nil, err
+	}
+	kubeconfig := string(raw)
+
+	return &kubeconfig, nil
+}
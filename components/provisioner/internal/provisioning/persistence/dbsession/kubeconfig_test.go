@@ -0,0 +1,115 @@
+package dbsession
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type cipherStub struct{}
+
+func (c cipherStub) Encrypt(value []byte) ([]byte, error) {
+	return append([]byte("encrypted:"), value...), nil
+}
+
+func (c cipherStub) Decrypt(value []byte) ([]byte, error) {
+	return value[len("encrypted:"):], nil
+}
+
+type kubeconfigProviderStub map[string]string
+
+func (p kubeconfigProviderStub) FetchRaw(shootName string) ([]byte, error) {
+	kubeconfig, found := p[shootName]
+	if !found {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, shootName)
+	}
+	return []byte(kubeconfig), nil
+}
+
+func Test_kubeconfigStore(t *testing.T) {
+	t.Run("should store plain kubeconfig when encryption is disabled", func(t *testing.T) {
+		// given
+		store := kubeconfigStore{}
+		cluster := model.Cluster{ID: "cluster", Kubeconfig: util.StringPtr("kubeconfig")}
+
+		// when
+		sealed, err := store.seal("kubeconfig")
+		require.NoError(t, err)
+		err = store.open(&cluster)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, "kubeconfig", *sealed)
+		assert.Equal(t, "kubeconfig", *cluster.Kubeconfig)
+	})
+
+	t.Run("should encrypt stored kubeconfig", func(t *testing.T) {
+		// given
+		store := kubeconfigStore{cipher: cipherStub{}}
+
+		// when
+		sealed, err := store.seal("kubeconfig")
+		require.NoError(t, err)
+		cluster := model.Cluster{ID: "cluster", Kubeconfig: sealed}
+		err = store.open(&cluster)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, "encrypted:kubeconfig", *sealed)
+		assert.Equal(t, "kubeconfig", *cluster.Kubeconfig)
+	})
+
+	t.Run("should not store kubeconfig and fetch it on demand when provider is set", func(t *testing.T) {
+		// given
+		store := kubeconfigStore{cipher: cipherStub{}, provider: kubeconfigProviderStub{"shoot": "fetched"}}
+		cluster := model.Cluster{ID: "cluster", ClusterConfig: model.GardenerConfig{Name: "shoot"}}
+
+		// when
+		sealed, err := store.seal("kubeconfig")
+		require.NoError(t, err)
+		err = store.open(&cluster)
+		require.NoError(t, err)
+
+		// then
+		assert.Nil(t, sealed)
+		assert.Equal(t, "fetched", *cluster.Kubeconfig)
+	})
+
+	t.Run("should leave kubeconfig empty when Shoot is not created yet", func(t *testing.T) {
+		// given
+		store := kubeconfigStore{provider: kubeconfigProviderStub{}}
+		cluster := model.Cluster{ID: "cluster", ClusterConfig: model.GardenerConfig{Name: "shoot"}}
+
+		// when
+		err := store.open(&cluster)
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, cluster.Kubeconfig)
+	})
+
+	t.Run("should return error when kubeconfig cannot be fetched", func(t *testing.T) {
+		// given
+		store := kubeconfigStore{provider: failingKubeconfigProvider{}}
+		cluster := model.Cluster{ID: "cluster", ClusterConfig: model.GardenerConfig{Name: "shoot"}}
+
+		// when
+		err := store.open(&cluster)
+
+		// then
+		require.Error(t, err)
+		assert.Nil(t, cluster.Kubeconfig)
+	})
+}
+
+type failingKubeconfigProvider struct{}
+
+func (p failingKubeconfigProvider) FetchRaw(_ string) ([]byte, error) {
+	return nil, errors.New("forbidden")
+}
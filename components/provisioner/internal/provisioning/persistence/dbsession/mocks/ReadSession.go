@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/kyma-project/control-plane/components/provisioner/internal/model"
+
+	time "time"
 )
 
 // ReadSession is an autogenerated mock type for the ReadSession type
@@ -223,3 +225,167 @@ func (_m *ReadSession) ListInProgressOperations() ([]model.Operation, dberrors.E
 
 	return r0, r1
 }
+
+// ListKubeconfigsToReEncrypt provides a mock function with given fields: currentPrefix, afterID, limit
+func (_m *ReadSession) ListKubeconfigsToReEncrypt(currentPrefix string, afterID string, limit int) ([]model.Cluster, dberrors.Error) {
+	ret := _m.Called(currentPrefix, afterID, limit)
+
+	var r0 []model.Cluster
+	if rf, ok := ret.Get(0).(func(string, string, int) []model.Cluster); ok {
+		r0 = rf(currentPrefix, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Cluster)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string, string, int) dberrors.Error); ok {
+		r1 = rf(currentPrefix, afterID, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListLastOperations provides a mock function with given fields: runtimeIDs
+func (_m *ReadSession) ListLastOperations(runtimeIDs []string) (map[string]model.Operation, dberrors.Error) {
+	ret := _m.Called(runtimeIDs)
+
+	var r0 map[string]model.Operation
+	if rf, ok := ret.Get(0).(func([]string) map[string]model.Operation); ok {
+		r0 = rf(runtimeIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]model.Operation)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func([]string) dberrors.Error); ok {
+		r1 = rf(runtimeIDs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListOperations provides a mock function with given fields: filter, after, limit
+func (_m *ReadSession) ListOperations(filter model.OperationFilter, after *model.PageCursor, limit int) ([]model.Operation, int, dberrors.Error) {
+	ret := _m.Called(filter, after, limit)
+
+	var r0 []model.Operation
+	if rf, ok := ret.Get(0).(func(model.OperationFilter, *model.PageCursor, int) []model.Operation); ok {
+		r0 = rf(filter, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Operation)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(model.OperationFilter, *model.PageCursor, int) int); ok {
+		r1 = rf(filter, after, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 dberrors.Error
+	if rf, ok := ret.Get(2).(func(model.OperationFilter, *model.PageCursor, int) dberrors.Error); ok {
+		r2 = rf(filter, after, limit)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(dberrors.Error)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// ListOperationsChangedSince provides a mock function with given fields: since
+func (_m *ReadSession) ListOperationsChangedSince(since time.Time) ([]model.Operation, dberrors.Error) {
+	ret := _m.Called(since)
+
+	var r0 []model.Operation
+	if rf, ok := ret.Get(0).(func(time.Time) []model.Operation); ok {
+		r0 = rf(since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Operation)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(time.Time) dberrors.Error); ok {
+		r1 = rf(since)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListRuntimes provides a mock function with given fields: filter, after, limit
+func (_m *ReadSession) ListRuntimes(filter model.RuntimeFilter, after *model.PageCursor, limit int) ([]model.Cluster, int, dberrors.Error) {
+	ret := _m.Called(filter, after, limit)
+
+	var r0 []model.Cluster
+	if rf, ok := ret.Get(0).(func(model.RuntimeFilter, *model.PageCursor, int) []model.Cluster); ok {
+		r0 = rf(filter, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Cluster)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(model.RuntimeFilter, *model.PageCursor, int) int); ok {
+		r1 = rf(filter, after, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 dberrors.Error
+	if rf, ok := ret.Get(2).(func(model.RuntimeFilter, *model.PageCursor, int) dberrors.Error); ok {
+		r2 = rf(filter, after, limit)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(dberrors.Error)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// ListUnleasedInProgressOperations provides a mock function with given fields: now
+func (_m *ReadSession) ListUnleasedInProgressOperations(now time.Time) ([]model.Operation, dberrors.Error) {
+	ret := _m.Called(now)
+
+	var r0 []model.Operation
+	if rf, ok := ret.Get(0).(func(time.Time) []model.Operation); ok {
+		r0 = rf(now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Operation)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(time.Time) dberrors.Error); ok {
+		r1 = rf(now)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
@@ -17,6 +17,45 @@ type ReadWriteSession struct {
 	mock.Mock
 }
 
+// AcquireOperationLease provides a mock function with given fields: operationID, holder, previousHolder, now, expiration
+func (_m *ReadWriteSession) AcquireOperationLease(operationID string, holder string, previousHolder string, now time.Time, expiration time.Time) (bool, dberrors.Error) {
+	ret := _m.Called(operationID, holder, previousHolder, now, expiration)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Time, time.Time) bool); ok {
+		r0 = rf(operationID, holder, previousHolder, now, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string, string, string, time.Time, time.Time) dberrors.Error); ok {
+		r1 = rf(operationID, holder, previousHolder, now, expiration)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ClearKubeconfigs provides a mock function with given fields:
+func (_m *ReadWriteSession) ClearKubeconfigs() dberrors.Error {
+	ret := _m.Called()
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func() dberrors.Error); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // DeleteCluster provides a mock function with given fields: runtimeID
 func (_m *ReadWriteSession) DeleteCluster(runtimeID string) dberrors.Error {
 	ret := _m.Called(runtimeID)
@@ -338,6 +377,170 @@ func (_m *ReadWriteSession) ListInProgressOperations() ([]model.Operation, dberr
 	return r0, r1
 }
 
+// ListKubeconfigsToReEncrypt provides a mock function with given fields: currentPrefix, afterID, limit
+func (_m *ReadWriteSession) ListKubeconfigsToReEncrypt(currentPrefix string, afterID string, limit int) ([]model.Cluster, dberrors.Error) {
+	ret := _m.Called(currentPrefix, afterID, limit)
+
+	var r0 []model.Cluster
+	if rf, ok := ret.Get(0).(func(string, string, int) []model.Cluster); ok {
+		r0 = rf(currentPrefix, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Cluster)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string, string, int) dberrors.Error); ok {
+		r1 = rf(currentPrefix, afterID, limit)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListLastOperations provides a mock function with given fields: runtimeIDs
+func (_m *ReadWriteSession) ListLastOperations(runtimeIDs []string) (map[string]model.Operation, dberrors.Error) {
+	ret := _m.Called(runtimeIDs)
+
+	var r0 map[string]model.Operation
+	if rf, ok := ret.Get(0).(func([]string) map[string]model.Operation); ok {
+		r0 = rf(runtimeIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]model.Operation)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func([]string) dberrors.Error); ok {
+		r1 = rf(runtimeIDs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListOperations provides a mock function with given fields: filter, after, limit
+func (_m *ReadWriteSession) ListOperations(filter model.OperationFilter, after *model.PageCursor, limit int) ([]model.Operation, int, dberrors.Error) {
+	ret := _m.Called(filter, after, limit)
+
+	var r0 []model.Operation
+	if rf, ok := ret.Get(0).(func(model.OperationFilter, *model.PageCursor, int) []model.Operation); ok {
+		r0 = rf(filter, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Operation)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(model.OperationFilter, *model.PageCursor, int) int); ok {
+		r1 = rf(filter, after, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 dberrors.Error
+	if rf, ok := ret.Get(2).(func(model.OperationFilter, *model.PageCursor, int) dberrors.Error); ok {
+		r2 = rf(filter, after, limit)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(dberrors.Error)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// ListOperationsChangedSince provides a mock function with given fields: since
+func (_m *ReadWriteSession) ListOperationsChangedSince(since time.Time) ([]model.Operation, dberrors.Error) {
+	ret := _m.Called(since)
+
+	var r0 []model.Operation
+	if rf, ok := ret.Get(0).(func(time.Time) []model.Operation); ok {
+		r0 = rf(since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Operation)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(time.Time) dberrors.Error); ok {
+		r1 = rf(since)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ListRuntimes provides a mock function with given fields: filter, after, limit
+func (_m *ReadWriteSession) ListRuntimes(filter model.RuntimeFilter, after *model.PageCursor, limit int) ([]model.Cluster, int, dberrors.Error) {
+	ret := _m.Called(filter, after, limit)
+
+	var r0 []model.Cluster
+	if rf, ok := ret.Get(0).(func(model.RuntimeFilter, *model.PageCursor, int) []model.Cluster); ok {
+		r0 = rf(filter, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Cluster)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(model.RuntimeFilter, *model.PageCursor, int) int); ok {
+		r1 = rf(filter, after, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 dberrors.Error
+	if rf, ok := ret.Get(2).(func(model.RuntimeFilter, *model.PageCursor, int) dberrors.Error); ok {
+		r2 = rf(filter, after, limit)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(dberrors.Error)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// ListUnleasedInProgressOperations provides a mock function with given fields: now
+func (_m *ReadWriteSession) ListUnleasedInProgressOperations(now time.Time) ([]model.Operation, dberrors.Error) {
+	ret := _m.Called(now)
+
+	var r0 []model.Operation
+	if rf, ok := ret.Get(0).(func(time.Time) []model.Operation); ok {
+		r0 = rf(now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Operation)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(time.Time) dberrors.Error); ok {
+		r1 = rf(now)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // MarkClusterAsDeleted provides a mock function with given fields: runtimeID
 func (_m *ReadWriteSession) MarkClusterAsDeleted(runtimeID string) dberrors.Error {
 	ret := _m.Called(runtimeID)
@@ -354,6 +557,61 @@ func (_m *ReadWriteSession) MarkClusterAsDeleted(runtimeID string) dberrors.Erro
 	return r0
 }
 
+// ReleaseOperationLease provides a mock function with given fields: operationID, holder
+func (_m *ReadWriteSession) ReleaseOperationLease(operationID string, holder string) dberrors.Error {
+	ret := _m.Called(operationID, holder)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, string) dberrors.Error); ok {
+		r0 = rf(operationID, holder)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
+// RenewOperationLease provides a mock function with given fields: operationID, holder, expiration
+func (_m *ReadWriteSession) RenewOperationLease(operationID string, holder string, expiration time.Time) (bool, dberrors.Error) {
+	ret := _m.Called(operationID, holder, expiration)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) bool); ok {
+		r0 = rf(operationID, holder, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) dberrors.Error); ok {
+		r1 = rf(operationID, holder, expiration)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ReplaceKubeconfig provides a mock function with given fields: runtimeID, oldKubeconfig, newKubeconfig
+func (_m *ReadWriteSession) ReplaceKubeconfig(runtimeID string, oldKubeconfig string, newKubeconfig string) dberrors.Error {
+	ret := _m.Called(runtimeID, oldKubeconfig, newKubeconfig)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, string, string) dberrors.Error); ok {
+		r0 = rf(runtimeID, oldKubeconfig, newKubeconfig)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // SetActiveKymaConfig provides a mock function with given fields: runtimeID, kymaConfigId
 func (_m *ReadWriteSession) SetActiveKymaConfig(runtimeID string, kymaConfigId string) dberrors.Error {
 	ret := _m.Called(runtimeID, kymaConfigId)
@@ -370,6 +628,22 @@ func (_m *ReadWriteSession) SetActiveKymaConfig(runtimeID string, kymaConfigId s
 	return r0
 }
 
+// SetHibernated provides a mock function with given fields: runtimeID, hibernated
+func (_m *ReadWriteSession) SetHibernated(runtimeID string, hibernated bool) dberrors.Error {
+	ret := _m.Called(runtimeID, hibernated)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, bool) dberrors.Error); ok {
+		r0 = rf(runtimeID, hibernated)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // TransitionOperation provides a mock function with given fields: operationID, message, stage, transitionTime
 func (_m *ReadWriteSession) TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) dberrors.Error {
 	ret := _m.Called(operationID, message, stage, transitionTime)
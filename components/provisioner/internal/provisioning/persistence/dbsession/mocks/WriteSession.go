@@ -17,6 +17,45 @@ type WriteSession struct {
 	mock.Mock
 }
 
+// AcquireOperationLease provides a mock function with given fields: operationID, holder, previousHolder, now, expiration
+func (_m *WriteSession) AcquireOperationLease(operationID string, holder string, previousHolder string, now time.Time, expiration time.Time) (bool, dberrors.Error) {
+	ret := _m.Called(operationID, holder, previousHolder, now, expiration)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Time, time.Time) bool); ok {
+		r0 = rf(operationID, holder, previousHolder, now, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string, string, string, time.Time, time.Time) dberrors.Error); ok {
+		r1 = rf(operationID, holder, previousHolder, now, expiration)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ClearKubeconfigs provides a mock function with given fields:
+func (_m *WriteSession) ClearKubeconfigs() dberrors.Error {
+	ret := _m.Called()
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func() dberrors.Error); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // DeleteCluster provides a mock function with given fields: runtimeID
 func (_m *WriteSession) DeleteCluster(runtimeID string) dberrors.Error {
 	ret := _m.Called(runtimeID)
@@ -145,6 +184,61 @@ func (_m *WriteSession) MarkClusterAsDeleted(runtimeID string) dberrors.Error {
 	return r0
 }
 
+// ReleaseOperationLease provides a mock function with given fields: operationID, holder
+func (_m *WriteSession) ReleaseOperationLease(operationID string, holder string) dberrors.Error {
+	ret := _m.Called(operationID, holder)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, string) dberrors.Error); ok {
+		r0 = rf(operationID, holder)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
+// RenewOperationLease provides a mock function with given fields: operationID, holder, expiration
+func (_m *WriteSession) RenewOperationLease(operationID string, holder string, expiration time.Time) (bool, dberrors.Error) {
+	ret := _m.Called(operationID, holder, expiration)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) bool); ok {
+		r0 = rf(operationID, holder, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) dberrors.Error); ok {
+		r1 = rf(operationID, holder, expiration)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ReplaceKubeconfig provides a mock function with given fields: runtimeID, oldKubeconfig, newKubeconfig
+func (_m *WriteSession) ReplaceKubeconfig(runtimeID string, oldKubeconfig string, newKubeconfig string) dberrors.Error {
+	ret := _m.Called(runtimeID, oldKubeconfig, newKubeconfig)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, string, string) dberrors.Error); ok {
+		r0 = rf(runtimeID, oldKubeconfig, newKubeconfig)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // SetActiveKymaConfig provides a mock function with given fields: runtimeID, kymaConfigId
 func (_m *WriteSession) SetActiveKymaConfig(runtimeID string, kymaConfigId string) dberrors.Error {
 	ret := _m.Called(runtimeID, kymaConfigId)
@@ -161,6 +255,22 @@ func (_m *WriteSession) SetActiveKymaConfig(runtimeID string, kymaConfigId strin
 	return r0
 }
 
+// SetHibernated provides a mock function with given fields: runtimeID, hibernated
+func (_m *WriteSession) SetHibernated(runtimeID string, hibernated bool) dberrors.Error {
+	ret := _m.Called(runtimeID, hibernated)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, bool) dberrors.Error); ok {
+		r0 = rf(runtimeID, hibernated)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // TransitionOperation provides a mock function with given fields: operationID, message, stage, transitionTime
 func (_m *WriteSession) TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) dberrors.Error {
 	ret := _m.Called(operationID, message, stage, transitionTime)
@@ -17,6 +17,45 @@ type WriteSessionWithinTransaction struct {
 	mock.Mock
 }
 
+// AcquireOperationLease provides a mock function with given fields: operationID, holder, previousHolder, now, expiration
+func (_m *WriteSessionWithinTransaction) AcquireOperationLease(operationID string, holder string, previousHolder string, now time.Time, expiration time.Time) (bool, dberrors.Error) {
+	ret := _m.Called(operationID, holder, previousHolder, now, expiration)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Time, time.Time) bool); ok {
+		r0 = rf(operationID, holder, previousHolder, now, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string, string, string, time.Time, time.Time) dberrors.Error); ok {
+		r1 = rf(operationID, holder, previousHolder, now, expiration)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ClearKubeconfigs provides a mock function with given fields:
+func (_m *WriteSessionWithinTransaction) ClearKubeconfigs() dberrors.Error {
+	ret := _m.Called()
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func() dberrors.Error); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // Commit provides a mock function with given fields:
 func (_m *WriteSessionWithinTransaction) Commit() dberrors.Error {
 	ret := _m.Called()
@@ -161,11 +200,66 @@ func (_m *WriteSessionWithinTransaction) MarkClusterAsDeleted(runtimeID string)
 	return r0
 }
 
+// ReleaseOperationLease provides a mock function with given fields: operationID, holder
+func (_m *WriteSessionWithinTransaction) ReleaseOperationLease(operationID string, holder string) dberrors.Error {
+	ret := _m.Called(operationID, holder)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, string) dberrors.Error); ok {
+		r0 = rf(operationID, holder)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
+// RenewOperationLease provides a mock function with given fields: operationID, holder, expiration
+func (_m *WriteSessionWithinTransaction) RenewOperationLease(operationID string, holder string, expiration time.Time) (bool, dberrors.Error) {
+	ret := _m.Called(operationID, holder, expiration)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) bool); ok {
+		r0 = rf(operationID, holder, expiration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) dberrors.Error); ok {
+		r1 = rf(operationID, holder, expiration)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // RollbackUnlessCommitted provides a mock function with given fields:
 func (_m *WriteSessionWithinTransaction) RollbackUnlessCommitted() {
 	_m.Called()
 }
 
+// ReplaceKubeconfig provides a mock function with given fields: runtimeID, oldKubeconfig, newKubeconfig
+func (_m *WriteSessionWithinTransaction) ReplaceKubeconfig(runtimeID string, oldKubeconfig string, newKubeconfig string) dberrors.Error {
+	ret := _m.Called(runtimeID, oldKubeconfig, newKubeconfig)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, string, string) dberrors.Error); ok {
+		r0 = rf(runtimeID, oldKubeconfig, newKubeconfig)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // SetActiveKymaConfig provides a mock function with given fields: runtimeID, kymaConfigId
 func (_m *WriteSessionWithinTransaction) SetActiveKymaConfig(runtimeID string, kymaConfigId string) dberrors.Error {
 	ret := _m.Called(runtimeID, kymaConfigId)
@@ -182,6 +276,22 @@ func (_m *WriteSessionWithinTransaction) SetActiveKymaConfig(runtimeID string, k
 	return r0
 }
 
+// SetHibernated provides a mock function with given fields: runtimeID, hibernated
+func (_m *WriteSessionWithinTransaction) SetHibernated(runtimeID string, hibernated bool) dberrors.Error {
+	ret := _m.Called(runtimeID, hibernated)
+
+	var r0 dberrors.Error
+	if rf, ok := ret.Get(0).(func(string, bool) dberrors.Error); ok {
+		r0 = rf(runtimeID, hibernated)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(dberrors.Error)
+		}
+	}
+
+	return r0
+}
+
 // TransitionOperation provides a mock function with given fields: operationID, message, stage, transitionTime
 func (_m *WriteSessionWithinTransaction) TransitionOperation(operationID string, message string, stage model.OperationStage, transitionTime time.Time) dberrors.Error {
 	ret := _m.Called(operationID, message, stage, transitionTime)
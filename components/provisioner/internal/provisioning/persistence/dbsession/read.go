@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 
@@ -13,7 +14,8 @@ import (
 )
 
 type readSession struct {
-	session *dbr.Session
+	session     *dbr.Session
+	kubeconfigs kubeconfigStore
 }
 
 func (r readSession) GetTenant(runtimeID string) (string, dberrors.Error) {
@@ -61,7 +63,7 @@ func (r readSession) GetCluster(runtimeID string) (model.Cluster, dberrors.Error
 	err := r.session.
 		Select(
 			"id", "kubeconfig", "tenant",
-			"creation_timestamp", "deleted", "sub_account_id", "active_kyma_config_id").
+			"creation_timestamp", "deleted", "sub_account_id", "active_kyma_config_id", "hibernated").
 		From("cluster").
 		Where(dbr.Eq("cluster.id", runtimeID)).
 		LoadOne(&cluster)
@@ -85,6 +87,11 @@ func (r readSession) GetCluster(runtimeID string) (model.Cluster, dberrors.Error
 	}
 	cluster.KymaConfig = kymaConfig
 
+	dberr = r.kubeconfigs.open(&cluster)
+	if dberr != nil {
+		return model.Cluster{}, dberr
+	}
+
 	return cluster, nil
 }
 
@@ -97,12 +104,12 @@ func (r readSession) GetGardenerClusterByName(name string) (model.Cluster, dberr
 	err := r.session.
 		Select(
 			"cluster.id", "cluster.kubeconfig", "cluster.tenant",
-			"cluster.creation_timestamp", "cluster.deleted", "cluster.active_kyma_config_id",
+			"cluster.creation_timestamp", "cluster.deleted", "cluster.active_kyma_config_id", "cluster.hibernated",
 			"name", "project_name", "kubernetes_version",
 			"volume_size_gb", "disk_type", "machine_type", "machine_image", "machine_image_version",
 			"provider", "purpose", "seed", "target_secret", "worker_cidr", "region", "auto_scaler_min", "auto_scaler_max",
 			"max_surge", "max_unavailable", "enable_kubernetes_version_auto_update",
-			"enable_machine_image_version_auto_update", "allow_privileged_containers", "provider_specific_config").
+			"enable_machine_image_version_auto_update", "allow_privileged_containers", "hibernation_schedules", "kube_api_server_config", "provider_specific_config").
 		From("gardener_config").
 		Join("cluster", "gardener_config.cluster_id=cluster.id").
 		Where(dbr.Eq("name", name)).
@@ -117,18 +124,29 @@ func (r readSession) GetGardenerClusterByName(name string) (model.Cluster, dberr
 	}
 	cluster := clusterWithProvider.Cluster
 
-	err = clusterWithProvider.gardenerConfigRead.DecodeProviderConfig()
+	err = clusterWithProvider.gardenerConfigRead.Decode()
 	if err != nil {
-		return model.Cluster{}, dberrors.Internal("Failed to decode Gardener provider config fetched from database: %s", err.Error())
+		return model.Cluster{}, dberrors.Internal("Failed to decode Gardener config fetched from database: %s", err.Error())
 	}
 	cluster.ClusterConfig = clusterWithProvider.gardenerConfigRead.GardenerConfig
 
+	workerPools, dberr := r.getWorkerPools(cluster.ID)
+	if dberr != nil {
+		return model.Cluster{}, dberr.Append("Cannot get worker pools for runtimeID: %s", cluster.ID)
+	}
+	cluster.ClusterConfig.WorkerPools = workerPools
+
 	kymaConfig, dberr := r.getKymaConfig(clusterWithProvider.Cluster.ID, cluster.ActiveKymaConfigId)
 	if dberr != nil {
 		return model.Cluster{}, dberr.Append("Cannot get Kyma config for runtimeID: %s", clusterWithProvider.Cluster.ID)
 	}
 	cluster.KymaConfig = kymaConfig
 
+	dberr = r.kubeconfigs.open(&cluster)
+	if dberr != nil {
+		return model.Cluster{}, dberr
+	}
+
 	return cluster, nil
 }
 
@@ -243,16 +261,32 @@ func (r readSession) getKymaConfig(runtimeID, kymaConfigId string) (model.KymaCo
 
 type gardenerConfigRead struct {
 	model.GardenerConfig
-	ProviderSpecificConfig string `db:"provider_specific_config"`
+	ProviderSpecificConfig   string  `db:"provider_specific_config"`
+	HibernationSchedulesJSON *string `db:"hibernation_schedules"`
+	KubeAPIServerConfigJSON  *string `db:"kube_api_server_config"`
 }
 
-func (gcr *gardenerConfigRead) DecodeProviderConfig() error {
+func (gcr *gardenerConfigRead) Decode() error {
 	gardenerConfigProviderConfig, err := model.NewGardenerProviderConfigFromJSON(gcr.ProviderSpecificConfig)
 	if err != nil {
 		return fmt.Errorf("error decoding Gardener provider config: %s", err.Error())
 	}
-
 	gcr.GardenerProviderConfig = gardenerConfigProviderConfig
+
+	if gcr.HibernationSchedulesJSON != nil {
+		err := json.Unmarshal([]byte(*gcr.HibernationSchedulesJSON), &gcr.HibernationSchedules)
+		if err != nil {
+			return fmt.Errorf("error decoding hibernation schedules: %s", err.Error())
+		}
+	}
+
+	if gcr.KubeAPIServerConfigJSON != nil {
+		err := json.Unmarshal([]byte(*gcr.KubeAPIServerConfigJSON), &gcr.KubeAPIServerConfig)
+		if err != nil {
+			return fmt.Errorf("error decoding kube-apiserver config: %s", err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -264,7 +298,7 @@ func (r readSession) getGardenerConfig(runtimeID string) (model.GardenerConfig,
 			"volume_size_gb", "disk_type", "machine_type", "machine_image", "machine_image_version", "provider", "purpose", "seed",
 			"target_secret", "worker_cidr", "region", "auto_scaler_min", "auto_scaler_max",
 			"max_surge", "max_unavailable", "enable_kubernetes_version_auto_update",
-			"enable_machine_image_version_auto_update", "allow_privileged_containers", "provider_specific_config").
+			"enable_machine_image_version_auto_update", "allow_privileged_containers", "hibernation_schedules", "kube_api_server_config", "provider_specific_config").
 		From("cluster").
 		Join("gardener_config", "cluster.id=gardener_config.cluster_id").
 		Where(dbr.Eq("cluster.id", runtimeID)).
@@ -278,17 +312,108 @@ func (r readSession) getGardenerConfig(runtimeID string) (model.GardenerConfig,
 		return model.GardenerConfig{}, dberrors.Internal("Failed to get Gardener config for %s Runtime: %s", runtimeID, err.Error())
 	}
 
-	err = gardenerConfig.DecodeProviderConfig()
+	err = gardenerConfig.Decode()
 	if err != nil {
-		return model.GardenerConfig{}, dberrors.Internal("Failed to decode Gardener provider config fetched from database: %s", err.Error())
+		return model.GardenerConfig{}, dberrors.Internal("Failed to decode Gardener config fetched from database: %s", err.Error())
+	}
+
+	workerPools, dberr := r.getWorkerPools(runtimeID)
+	if dberr != nil {
+		return model.GardenerConfig{}, dberr
 	}
+	gardenerConfig.WorkerPools = workerPools
 
 	return gardenerConfig.GardenerConfig, nil
 }
 
+type workerPoolDTO struct {
+	model.WorkerPool
+	ClusterID  string  `db:"cluster_id"`
+	ZonesJSON  *string `db:"zones"`
+	LabelsJSON *string `db:"labels"`
+	TaintsJSON *string `db:"taints"`
+}
+
+func (dto workerPoolDTO) parseToWorkerPool() (model.WorkerPool, error) {
+	workerPool := dto.WorkerPool
+
+	for _, field := range []struct {
+		name   string
+		json   *string
+		target interface{}
+	}{
+		{name: "zones", json: dto.ZonesJSON, target: &workerPool.Zones},
+		{name: "labels", json: dto.LabelsJSON, target: &workerPool.Labels},
+		{name: "taints", json: dto.TaintsJSON, target: &workerPool.Taints},
+	} {
+		if field.json == nil {
+			continue
+		}
+		err := json.Unmarshal([]byte(*field.json), field.target)
+		if err != nil {
+			return model.WorkerPool{}, fmt.Errorf("error decoding %s of %s worker pool: %s", field.name, dto.Name, err.Error())
+		}
+	}
+
+	return workerPool, nil
+}
+
+func (r readSession) getWorkerPools(runtimeID string) ([]model.WorkerPool, dberrors.Error) {
+	workerPools, dberr := r.listWorkerPools([]string{runtimeID})
+	if dberr != nil {
+		return nil, dberr.Append("Failed to get worker pools for %s Runtime", runtimeID)
+	}
+
+	return workerPools[runtimeID], nil
+}
+
+// listWorkerPools returns worker pools of the Runtimes ordered by name and grouped by the Runtime ID
+func (r readSession) listWorkerPools(runtimeIDs []string) (map[string][]model.WorkerPool, dberrors.Error) {
+	if len(runtimeIDs) == 0 {
+		return map[string][]model.WorkerPool{}, nil
+	}
+
+	var workerPoolDTOs []workerPoolDTO
+
+	_, err := r.session.
+		Select("worker_pool.id", "gardener_config_id", "worker_pool.name", "worker_pool.machine_type",
+			"worker_pool.machine_image", "worker_pool.machine_image_version", "worker_pool.disk_type",
+			"worker_pool.volume_size_gb", "worker_pool.auto_scaler_min", "worker_pool.auto_scaler_max",
+			"worker_pool.max_surge", "worker_pool.max_unavailable", "zones", "labels", "taints", "gardener_config.cluster_id").
+		From("worker_pool").
+		Join("gardener_config", "worker_pool.gardener_config_id=gardener_config.id").
+		Where(dbr.Eq("gardener_config.cluster_id", runtimeIDs)).
+		OrderBy("worker_pool.name").
+		Load(&workerPoolDTOs)
+
+	if err != nil {
+		return nil, dberrors.Internal("Failed to list worker pools: %s", err.Error())
+	}
+
+	workerPools, err := groupWorkerPools(workerPoolDTOs)
+	if err != nil {
+		return nil, dberrors.Internal("Failed to decode worker pool fetched from database: %s", err.Error())
+	}
+
+	return workerPools, nil
+}
+
+func groupWorkerPools(dtos []workerPoolDTO) (map[string][]model.WorkerPool, error) {
+	workerPools := make(map[string][]model.WorkerPool)
+	for _, dto := range dtos {
+		workerPool, err := dto.parseToWorkerPool()
+		if err != nil {
+			return nil, err
+		}
+		workerPools[dto.ClusterID] = append(workerPools[dto.ClusterID], workerPool)
+	}
+
+	return workerPools, nil
+}
+
 var (
 	operationColumns = []string{
-		"id", "type", "start_timestamp", "stage", "end_timestamp", "state", "message", "cluster_id", "last_transition",
+		"id", "type", "start_timestamp", "stage", "end_timestamp", "state", "message", "cluster_id", "last_transition", "urgent",
 	}
 )
 
@@ -354,6 +479,79 @@ func (r readSession) ListInProgressOperations() ([]model.Operation, dberrors.Err
 	return operations, nil
 }
 
+// ListOperationsChangedSince returns operations which were started, changed stage or state after the given time
+func (r readSession) ListOperationsChangedSince(since time.Time) ([]model.Operation, dberrors.Error) {
+	var operations []model.Operation
+
+	_, err := r.session.
+		Select(operationColumns...).
+		From("operation").
+		Where(dbr.Or(
+			dbr.Gt("start_timestamp", since),
+			dbr.Gt("last_transition", since),
+			dbr.Gt("end_timestamp", since),
+		)).
+		Load(&operations)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return []model.Operation{}, nil
+		}
+		return nil, dberrors.Internal("Failed to list operations changed since %s: %s", since, err)
+	}
+
+	return operations, nil
+}
+
+// ListUnleasedInProgressOperations returns In Progress operations which are not leased by any provisioner replica or whose lease expired
+func (r readSession) ListUnleasedInProgressOperations(now time.Time) ([]model.Operation, dberrors.Error) {
+	var operations []model.Operation
+
+	_, err := r.session.
+		Select(operationColumns...).
+		From("operation").
+		Where(dbr.And(
+			dbr.Eq("state", model.InProgress),
+			dbr.Or(dbr.Eq("lease_owner", nil), dbr.Lt("lease_expiration", now)),
+		)).
+		Load(&operations)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return []model.Operation{}, nil
+		}
+		return nil, dberrors.Internal("Failed to list unleased In Progress operations: %s", err)
+	}
+
+	return operations, nil
+}
+
+// ListKubeconfigsToReEncrypt returns at most limit clusters with ID greater than afterID and stored kubeconfig which does not start with the current prefix of the encrypted values
+func (r readSession) ListKubeconfigsToReEncrypt(currentPrefix, afterID string, limit int) ([]model.Cluster, dberrors.Error) {
+	var clusters []model.Cluster
+
+	_, err := r.session.
+		Select("id", "kubeconfig").
+		From("cluster").
+		Where(dbr.And(
+			dbr.Neq("kubeconfig", nil),
+			dbr.Expr("kubeconfig NOT LIKE ?", currentPrefix+"%"),
+			dbr.Gt("id", afterID),
+		)).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Load(&clusters)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return []model.Cluster{}, nil
+		}
+		return nil, dberrors.Internal("Failed to list kubeconfigs to re-encrypt: %s", err)
+	}
+
+	return clusters, nil
+}
+
 func (r readSession) GetRuntimeUpgrade(operationId string) (model.RuntimeUpgrade, dberrors.Error) {
 	var runtimeUpgrade model.RuntimeUpgrade
 
@@ -401,3 +599,179 @@ func (r readSession) InProgressOperationsCount() (model.OperationsCount, dberror
 
 	return operationsCount, nil
 }
+
+func (r readSession) ListRuntimes(filter model.RuntimeFilter, after *model.PageCursor, limit int) ([]model.Cluster, int, dberrors.Error) {
+	var totalCount int
+
+	err := r.filterRuntimes(r.session.Select("count(*)"), filter).LoadOne(&totalCount)
+	if err != nil {
+		return nil, 0, dberrors.Internal("Failed to count Runtimes: %s", err)
+	}
+
+	query := r.filterRuntimes(r.session.Select(
+		"cluster.id", "cluster.tenant",
+		"cluster.creation_timestamp", "cluster.deleted", "cluster.sub_account_id", "cluster.active_kyma_config_id", "cluster.hibernated",
+		"gardener_config.name", "project_name", "kubernetes_version",
+		"volume_size_gb", "disk_type", "machine_type", "machine_image", "machine_image_version",
+		"provider", "purpose", "seed", "target_secret", "worker_cidr", "region", "auto_scaler_min", "auto_scaler_max",
+		"max_surge", "max_unavailable", "enable_kubernetes_version_auto_update",
+		"enable_machine_image_version_auto_update", "allow_privileged_containers", "hibernation_schedules", "kube_api_server_config", "provider_specific_config",
+		"kyma_release.version AS kyma_version"), filter)
+
+	if after != nil {
+		query = query.Where("(cluster.creation_timestamp, cluster.id) < (?, ?)", after.Timestamp, after.ID)
+	}
+
+	var runtimes []struct {
+		model.Cluster
+		gardenerConfigRead
+		KymaVersion *string
+	}
+
+	_, err = query.
+		OrderDesc("cluster.creation_timestamp").
+		OrderDesc("cluster.id").
+		Limit(uint64(limit)).
+		Load(&runtimes)
+	if err != nil && err != dbr.ErrNotFound {
+		return nil, 0, dberrors.Internal("Failed to list Runtimes: %s", err)
+	}
+
+	runtimeIDs := make([]string, 0, len(runtimes))
+	for _, runtime := range runtimes {
+		runtimeIDs = append(runtimeIDs, runtime.Cluster.ID)
+	}
+	workerPools, dberr := r.listWorkerPools(runtimeIDs)
+	if dberr != nil {
+		return nil, 0, dberr.Append("Failed to list Runtimes")
+	}
+
+	clusters := make([]model.Cluster, 0, len(runtimes))
+	for _, runtime := range runtimes {
+		err = runtime.gardenerConfigRead.Decode()
+		if err != nil {
+			return nil, 0, dberrors.Internal("Failed to decode Gardener config of %s Runtime: %s", runtime.Cluster.ID, err.Error())
+		}
+
+		cluster := runtime.Cluster
+		cluster.ClusterConfig = runtime.gardenerConfigRead.GardenerConfig
+		cluster.ClusterConfig.ClusterID = cluster.ID
+		cluster.ClusterConfig.WorkerPools = workerPools[cluster.ID]
+		// Only the Release version of the active Kyma config is loaded when listing Runtimes
+		cluster.KymaConfig = model.KymaConfig{
+			ID:        cluster.ActiveKymaConfigId,
+			ClusterID: cluster.ID,
+			Active:    true,
+		}
+		if runtime.KymaVersion != nil {
+			cluster.KymaConfig.Release.Version = *runtime.KymaVersion
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, totalCount, nil
+}
+
+func (r readSession) filterRuntimes(query *dbr.SelectStmt, filter model.RuntimeFilter) *dbr.SelectStmt {
+	query = query.
+		From("cluster").
+		Join("gardener_config", "cluster.id=gardener_config.cluster_id").
+		LeftJoin("kyma_config", "cluster.active_kyma_config_id=kyma_config.id").
+		LeftJoin("kyma_release", "kyma_config.release_id=kyma_release.id").
+		Where(dbr.Eq("cluster.deleted", false))
+
+	query = whereNotEmpty(query, "cluster.tenant", filter.Tenant)
+	query = whereNotEmpty(query, "cluster.sub_account_id", filter.SubAccountID)
+	query = whereNotEmpty(query, "gardener_config.provider", filter.Provider)
+	query = whereNotEmpty(query, "gardener_config.region", filter.Region)
+	query = whereNotEmpty(query, "gardener_config.kubernetes_version", filter.KubernetesVersion)
+	query = whereNotEmpty(query, "kyma_release.version", filter.KymaVersion)
+
+	return query
+}
+
+func (r readSession) ListLastOperations(runtimeIDs []string) (map[string]model.Operation, dberrors.Error) {
+	lastOperations := make(map[string]model.Operation, len(runtimeIDs))
+	if len(runtimeIDs) == 0 {
+		return lastOperations, nil
+	}
+
+	var operations []model.Operation
+
+	_, err := r.session.
+		Select("DISTINCT ON (cluster_id) id", "type", "start_timestamp", "stage", "end_timestamp", "state", "message", "cluster_id", "last_transition", "urgent").
+		From("operation").
+		Where(dbr.Eq("cluster_id", runtimeIDs)).
+		OrderAsc("cluster_id").
+		OrderDesc("start_timestamp").
+		Load(&operations)
+
+	if err != nil && err != dbr.ErrNotFound {
+		return nil, dberrors.Internal("Failed to list last operations: %s", err)
+	}
+
+	for _, operation := range operations {
+		lastOperations[operation.ClusterID] = operation
+	}
+
+	return lastOperations, nil
+}
+
+func (r readSession) ListOperations(filter model.OperationFilter, after *model.PageCursor, limit int) ([]model.Operation, int, dberrors.Error) {
+	var totalCount int
+
+	err := r.filterOperations(r.session.Select("count(*)"), filter).LoadOne(&totalCount)
+	if err != nil {
+		return nil, 0, dberrors.Internal("Failed to count operations: %s", err)
+	}
+
+	columns := make([]string, 0, len(operationColumns))
+	for _, column := range operationColumns {
+		columns = append(columns, "operation."+column)
+	}
+
+	query := r.filterOperations(r.session.Select(columns...), filter)
+
+	if after != nil {
+		query = query.Where("(operation.start_timestamp, operation.id) < (?, ?)", after.Timestamp, after.ID)
+	}
+
+	var operations []model.Operation
+
+	_, err = query.
+		OrderDesc("operation.start_timestamp").
+		OrderDesc("operation.id").
+		Limit(uint64(limit)).
+		Load(&operations)
+	if err != nil && err != dbr.ErrNotFound {
+		return nil, 0, dberrors.Internal("Failed to list operations: %s", err)
+	}
+
+	if operations == nil {
+		operations = []model.Operation{}
+	}
+
+	return operations, totalCount, nil
+}
+
+func (r readSession) filterOperations(query *dbr.SelectStmt, filter model.OperationFilter) *dbr.SelectStmt {
+	query = query.
+		From("operation").
+		Join("cluster", "operation.cluster_id=cluster.id")
+
+	query = whereNotEmpty(query, "cluster.tenant", filter.Tenant)
+	query = whereNotEmpty(query, "cluster.sub_account_id", filter.SubAccountID)
+	query = whereNotEmpty(query, "operation.cluster_id", filter.RuntimeID)
+	query = whereNotEmpty(query, "operation.type", string(filter.Type))
+	query = whereNotEmpty(query, "operation.state", string(filter.State))
+
+	return query
+}
+
+func whereNotEmpty(query *dbr.SelectStmt, column, value string) *dbr.SelectStmt {
+	if value == "" {
+		return query
+	}
+	return query.Where(dbr.Eq(column, value))
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/model"
+	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -149,3 +150,100 @@ func Test_parseToKymaConfig(t *testing.T) {
 	}
 
 }
+
+func Test_parseToWorkerPool(t *testing.T) {
+
+	t.Run("should decode zones, labels and taints", func(t *testing.T) {
+		// given
+		dto := workerPoolDTO{
+			WorkerPool: model.WorkerPool{
+				ID:               "pool-id",
+				GardenerConfigID: "config-id",
+				Name:             "memory",
+				MachineType:      "m5.8xlarge",
+				AutoScalerMax:    3,
+			},
+			ZonesJSON:  util.StringPtr(`["zone-1","zone-2"]`),
+			LabelsJSON: util.StringPtr(`{"workload":"memory"}`),
+			TaintsJSON: util.StringPtr(`[{"key":"dedicated","value":"memory","effect":"NoSchedule"}]`),
+		}
+
+		// when
+		workerPool, err := dto.parseToWorkerPool()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.WorkerPool{
+			ID:               "pool-id",
+			GardenerConfigID: "config-id",
+			Name:             "memory",
+			MachineType:      "m5.8xlarge",
+			AutoScalerMax:    3,
+			Zones:            []string{"zone-1", "zone-2"},
+			Labels:           map[string]string{"workload": "memory"},
+			Taints:           []model.Taint{{Key: "dedicated", Value: "memory", Effect: "NoSchedule"}},
+		}, workerPool)
+	})
+
+	t.Run("should leave empty values when columns are null", func(t *testing.T) {
+		// given
+		dto := workerPoolDTO{WorkerPool: model.WorkerPool{Name: "memory"}}
+
+		// when
+		workerPool, err := dto.parseToWorkerPool()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, model.WorkerPool{Name: "memory"}, workerPool)
+	})
+
+	t.Run("should return error when taints are invalid", func(t *testing.T) {
+		// given
+		dto := workerPoolDTO{
+			WorkerPool: model.WorkerPool{Name: "memory"},
+			TaintsJSON: util.StringPtr(`{"key":"dedicated"}`),
+		}
+
+		// when
+		_, err := dto.parseToWorkerPool()
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "taints")
+	})
+}
+
+func Test_groupWorkerPools(t *testing.T) {
+
+	t.Run("should group worker pools by Runtime keeping the order", func(t *testing.T) {
+		// given
+		dtos := []workerPoolDTO{
+			{WorkerPool: model.WorkerPool{Name: "cpu"}, ClusterID: "runtime-1"},
+			{WorkerPool: model.WorkerPool{Name: "gpu"}, ClusterID: "runtime-2", ZonesJSON: util.StringPtr(`["zone-1"]`)},
+			{WorkerPool: model.WorkerPool{Name: "memory"}, ClusterID: "runtime-1"},
+		}
+
+		// when
+		workerPools, err := groupWorkerPools(dtos)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]model.WorkerPool{
+			"runtime-1": {{Name: "cpu"}, {Name: "memory"}},
+			"runtime-2": {{Name: "gpu", Zones: []string{"zone-1"}}},
+		}, workerPools)
+	})
+
+	t.Run("should return error when worker pool cannot be decoded", func(t *testing.T) {
+		// given
+		dtos := []workerPoolDTO{
+			{WorkerPool: model.WorkerPool{Name: "memory"}, ClusterID: "runtime-1", LabelsJSON: util.StringPtr(`["invalid"]`)},
+		}
+
+		// when
+		_, err := groupWorkerPools(dtos)
+
+		// then
+		require.Error(t, err)
+	})
+}
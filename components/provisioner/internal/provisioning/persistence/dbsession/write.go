@@ -14,6 +14,7 @@ import (
 type writeSession struct {
 	session     *dbr.Session
 	transaction *dbr.Tx
+	kubeconfigs kubeconfigStore
 }
 
 func (ws writeSession) InsertCluster(cluster model.Cluster) dberrors.Error {
@@ -33,6 +34,15 @@ func (ws writeSession) InsertCluster(cluster model.Cluster) dberrors.Error {
 }
 
 func (ws writeSession) InsertGardenerConfig(config model.GardenerConfig) dberrors.Error {
+	hibernationSchedules, dberr := hibernationSchedulesJSON(config.HibernationSchedules)
+	if dberr != nil {
+		return dberr
+	}
+	kubeAPIServerConfig, dberr := kubeAPIServerConfigJSON(config.KubeAPIServerConfig)
+	if dberr != nil {
+		return dberr
+	}
+
 	_, err := ws.insertInto("gardener_config").
 		Pair("id", config.ID).
 		Pair("cluster_id", config.ClusterID).
@@ -58,6 +68,8 @@ func (ws writeSession) InsertGardenerConfig(config model.GardenerConfig) dberror
 		Pair("enable_kubernetes_version_auto_update", config.EnableKubernetesVersionAutoUpdate).
 		Pair("enable_machine_image_version_auto_update", config.EnableMachineImageVersionAutoUpdate).
 		Pair("allow_privileged_containers", config.AllowPrivilegedContainers).
+		Pair("hibernation_schedules", hibernationSchedules).
+		Pair("kube_api_server_config", kubeAPIServerConfig).
 		Pair("provider_specific_config", config.GardenerProviderConfig.RawJSON()).
 		Exec()
 
@@ -65,10 +77,19 @@ func (ws writeSession) InsertGardenerConfig(config model.GardenerConfig) dberror
 		return dberrors.Internal("Failed to insert record to GardenerConfig table: %s", err)
 	}
 
-	return nil
+	return ws.insertWorkerPools(config.ID, config.WorkerPools)
 }
 
 func (ws writeSession) UpdateGardenerClusterConfig(config model.GardenerConfig) dberrors.Error {
+	hibernationSchedules, dberr := hibernationSchedulesJSON(config.HibernationSchedules)
+	if dberr != nil {
+		return dberr
+	}
+	kubeAPIServerConfig, dberr := kubeAPIServerConfigJSON(config.KubeAPIServerConfig)
+	if dberr != nil {
+		return dberr
+	}
+
 	res, err := ws.update("gardener_config").
 		Where(dbr.Eq("cluster_id", config.ClusterID)).
 		Set("kubernetes_version", config.KubernetesVersion).
@@ -85,6 +106,8 @@ func (ws writeSession) UpdateGardenerClusterConfig(config model.GardenerConfig)
 		Set("max_unavailable", config.MaxUnavailable).
 		Set("enable_kubernetes_version_auto_update", config.EnableKubernetesVersionAutoUpdate).
 		Set("enable_machine_image_version_auto_update", config.EnableMachineImageVersionAutoUpdate).
+		Set("hibernation_schedules", hibernationSchedules).
+		Set("kube_api_server_config", kubeAPIServerConfig).
 		Set("provider_specific_config", config.GardenerProviderConfig.RawJSON()).
 		Exec()
 
@@ -92,7 +115,113 @@ func (ws writeSession) UpdateGardenerClusterConfig(config model.GardenerConfig)
 		return dberrors.Internal("Failed to update record of configuration for gardener shoot cluster '%s': %s", config.Name, err)
 	}
 
-	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update record of configuration for gardener shoot cluster '%s' state: %s", config.Name, err))
+	dberr = ws.updateSucceeded(res, fmt.Sprintf("Failed to update record of configuration for gardener shoot cluster '%s' state: %s", config.Name, err))
+	if dberr != nil {
+		return dberr
+	}
+
+	_, err = ws.deleteFrom("worker_pool").
+		Where(dbr.Eq("gardener_config_id", config.ID)).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to delete worker pools of gardener shoot cluster '%s': %s", config.Name, err)
+	}
+
+	return ws.insertWorkerPools(config.ID, config.WorkerPools)
+}
+
+func (ws writeSession) insertWorkerPools(gardenerConfigID string, workerPools []model.WorkerPool) dberrors.Error {
+	for _, workerPool := range workerPools {
+		dberr := ws.insertWorkerPool(gardenerConfigID, workerPool)
+		if dberr != nil {
+			return dberr
+		}
+	}
+
+	return nil
+}
+
+func (ws writeSession) insertWorkerPool(gardenerConfigID string, workerPool model.WorkerPool) dberrors.Error {
+	zones, dberr := optionalJSON(len(workerPool.Zones), workerPool.Zones)
+	if dberr != nil {
+		return dberr.Append("Failed to marshal zones of %s worker pool", workerPool.Name)
+	}
+	labels, dberr := optionalJSON(len(workerPool.Labels), workerPool.Labels)
+	if dberr != nil {
+		return dberr.Append("Failed to marshal labels of %s worker pool", workerPool.Name)
+	}
+	taints, dberr := optionalJSON(len(workerPool.Taints), workerPool.Taints)
+	if dberr != nil {
+		return dberr.Append("Failed to marshal taints of %s worker pool", workerPool.Name)
+	}
+
+	_, err := ws.insertInto("worker_pool").
+		Pair("id", workerPool.ID).
+		Pair("gardener_config_id", gardenerConfigID).
+		Pair("name", workerPool.Name).
+		Pair("machine_type", workerPool.MachineType).
+		Pair("machine_image", workerPool.MachineImage).
+		Pair("machine_image_version", workerPool.MachineImageVersion).
+		Pair("disk_type", workerPool.DiskType).
+		Pair("volume_size_gb", workerPool.VolumeSizeGB).
+		Pair("auto_scaler_min", workerPool.AutoScalerMin).
+		Pair("auto_scaler_max", workerPool.AutoScalerMax).
+		Pair("max_surge", workerPool.MaxSurge).
+		Pair("max_unavailable", workerPool.MaxUnavailable).
+		Pair("zones", zones).
+		Pair("labels", labels).
+		Pair("taints", taints).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to insert record to WorkerPool table: %s", err)
+	}
+
+	return nil
+}
+
+// optionalJSON marshals the value to JSON, empty values are stored as NULL
+func optionalJSON(length int, value interface{}) (*string, dberrors.Error) {
+	if length == 0 {
+		return nil, nil
+	}
+
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, dberrors.Internal("Failed to marshal value: %s", err.Error())
+	}
+
+	result := string(jsonValue)
+	return &result, nil
+}
+
+func hibernationSchedulesJSON(schedules []model.HibernationSchedule) (*string, dberrors.Error) {
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+
+	jsonSchedules, err := json.Marshal(schedules)
+	if err != nil {
+		return nil, dberrors.Internal("Failed to marshal hibernation schedules: %s", err.Error())
+	}
+
+	value := string(jsonSchedules)
+	return &value, nil
+}
+
+func kubeAPIServerConfigJSON(config *model.KubeAPIServerConfig) (*string, dberrors.Error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	jsonConfig, err := json.Marshal(config)
+	if err != nil {
+		return nil, dberrors.Internal("Failed to marshal kube-apiserver config: %s", err.Error())
+	}
+
+	value := string(jsonConfig)
+	return &value, nil
 }
 
 func (ws writeSession) InsertKymaConfig(kymaConfig model.KymaConfig) dberrors.Error {
@@ -231,10 +360,75 @@ func (ws writeSession) FixShootProvisioningStage(message string, newStage model.
 	return nil
 }
 
+// AcquireOperationLease leases the operation to the holder until expiration if it is not leased, the lease expired
+// or the lease is held by the previous holder which hands it over, the previous holder is ignored if empty
+func (ws writeSession) AcquireOperationLease(operationID string, holder string, previousHolder string, now time.Time, expiration time.Time) (bool, dberrors.Error) {
+	available := []dbr.Builder{dbr.Eq("lease_owner", nil), dbr.Lt("lease_expiration", now)}
+	if previousHolder != "" {
+		available = append(available, dbr.Eq("lease_owner", previousHolder))
+	}
+
+	res, err := ws.update("operation").
+		Where(dbr.And(dbr.Eq("id", operationID), dbr.Or(available...))).
+		Set("lease_owner", holder).
+		Set("lease_expiration", expiration).
+		Exec()
+
+	if err != nil {
+		return false, dberrors.Internal("Failed to acquire operation %s lease: %s", operationID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, dberrors.Internal("Failed to get number of rows affected: %s", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// RenewOperationLease extends the lease of the operation until expiration only if it is still held by the holder
+func (ws writeSession) RenewOperationLease(operationID string, holder string, expiration time.Time) (bool, dberrors.Error) {
+	res, err := ws.update("operation").
+		Where(dbr.And(dbr.Eq("id", operationID), dbr.Eq("lease_owner", holder))).
+		Set("lease_expiration", expiration).
+		Exec()
+
+	if err != nil {
+		return false, dberrors.Internal("Failed to renew operation %s lease: %s", operationID, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, dberrors.Internal("Failed to get number of rows affected: %s", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (ws writeSession) ReleaseOperationLease(operationID string, holder string) dberrors.Error {
+	_, err := ws.update("operation").
+		Where(dbr.And(dbr.Eq("id", operationID), dbr.Eq("lease_owner", holder))).
+		Set("lease_owner", nil).
+		Set("lease_expiration", nil).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to release operation %s lease: %s", operationID, err)
+	}
+
+	return nil
+}
+
+// UpdateKubeconfig stores the kubeconfig encrypted if encryption is enabled, the kubeconfig is removed if kubeconfigs are not stored
 func (ws writeSession) UpdateKubeconfig(runtimeID string, kubeconfig string) dberrors.Error {
+	sealed, dberr := ws.kubeconfigs.seal(kubeconfig)
+	if dberr != nil {
+		return dberr
+	}
+
 	res, err := ws.update("cluster").
 		Where(dbr.Eq("id", runtimeID)).
-		Set("kubeconfig", kubeconfig).
+		Set("kubeconfig", sealed).
 		Exec()
 
 	if err != nil {
@@ -244,6 +438,34 @@ func (ws writeSession) UpdateKubeconfig(runtimeID string, kubeconfig string) dbe
 	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update cluster %s data: %s", runtimeID, err))
 }
 
+// ReplaceKubeconfig replaces the stored kubeconfig only if it was not changed in the meantime
+func (ws writeSession) ReplaceKubeconfig(runtimeID string, oldKubeconfig string, newKubeconfig string) dberrors.Error {
+	res, err := ws.update("cluster").
+		Where(dbr.And(dbr.Eq("id", runtimeID), dbr.Eq("kubeconfig", oldKubeconfig))).
+		Set("kubeconfig", newKubeconfig).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to replace kubeconfig of cluster %s: %s", runtimeID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Kubeconfig of cluster %s not found or was changed", runtimeID))
+}
+
+// ClearKubeconfigs removes kubeconfigs of all clusters
+func (ws writeSession) ClearKubeconfigs() dberrors.Error {
+	_, err := ws.update("cluster").
+		Where(dbr.Neq("kubeconfig", nil)).
+		Set("kubeconfig", nil).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to clear kubeconfigs: %s", err)
+	}
+
+	return nil
+}
+
 func (ws writeSession) SetActiveKymaConfig(runtimeID string, kymaConfigId string) dberrors.Error {
 	res, err := ws.update("cluster").
 		Where(dbr.Eq("id", runtimeID)).
@@ -283,6 +505,19 @@ func (ws writeSession) MarkClusterAsDeleted(runtimeID string) dberrors.Error {
 	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update cluster %s data: %s", runtimeID, err))
 }
 
+func (ws writeSession) SetHibernated(runtimeID string, hibernated bool) dberrors.Error {
+	res, err := ws.update("cluster").
+		Where(dbr.Eq("id", runtimeID)).
+		Set("hibernated", hibernated).
+		Exec()
+
+	if err != nil {
+		return dberrors.Internal("Failed to update cluster %s hibernation state: %s", runtimeID, err)
+	}
+
+	return ws.updateSucceeded(res, fmt.Sprintf("Failed to update cluster %s hibernation state: %s", runtimeID, err))
+}
+
 func (ws writeSession) InsertRuntimeUpgrade(runtimeUpgrade model.RuntimeUpgrade) dberrors.Error {
 	_, err := ws.insertInto("runtime_upgrade").
 		Columns("id", "state", "operation_id", "pre_upgrade_kyma_config_id", "post_upgrade_kyma_config_id").
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
 
+	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/queue"
 
 	uuid "github.com/kyma-project/control-plane/components/provisioner/internal/uuid"
@@ -29,10 +30,15 @@ type Service interface {
 	UpgradeRuntime(id string, config gqlschema.UpgradeRuntimeInput) (*gqlschema.OperationStatus, apperrors.AppError)
 	DeprovisionRuntime(id, tenant string) (string, apperrors.AppError)
 	UpgradeGardenerShoot(id string, input gqlschema.UpgradeShootInput) (*gqlschema.OperationStatus, apperrors.AppError)
+	HibernateRuntime(id string) (*gqlschema.OperationStatus, apperrors.AppError)
+	WakeUpRuntime(id string) (*gqlschema.OperationStatus, apperrors.AppError)
 	ReconnectRuntimeAgent(id string) (string, apperrors.AppError)
 	RuntimeStatus(id string) (*gqlschema.RuntimeStatus, apperrors.AppError)
 	RuntimeOperationStatus(id string) (*gqlschema.OperationStatus, apperrors.AppError)
 	RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStatus, apperrors.AppError)
+	RollbackRuntimeUpgrade(runtimeID string) (*gqlschema.OperationStatus, apperrors.AppError)
+	ListRuntimes(filter *gqlschema.RuntimesFilter, first *int, after *string) (*gqlschema.RuntimesPage, apperrors.AppError)
+	ListOperations(filter *gqlschema.OperationsFilter, first *int, after *string) (*gqlschema.OperationsPage, apperrors.AppError)
 }
 
 //go:generate mockery -name=Provisioner
@@ -55,6 +61,10 @@ type service struct {
 	deprovisioningQueue queue.OperationQueue
 	upgradeQueue        queue.OperationQueue
 	shootUpgradeQueue   queue.OperationQueue
+	hibernationQueue    queue.OperationQueue
+	wakeUpQueue         queue.OperationQueue
+
+	rollbackStarter failure.RollbackStarter
 }
 
 func NewProvisioningService(
@@ -68,7 +78,9 @@ func NewProvisioningService(
 	deprovisioningQueue queue.OperationQueue,
 	upgradeQueue queue.OperationQueue,
 	shootUpgradeQueue queue.OperationQueue,
-
+	hibernationQueue queue.OperationQueue,
+	wakeUpQueue queue.OperationQueue,
+	rollbackStarter failure.RollbackStarter,
 ) Service {
 	return &service{
 		inputConverter:      inputConverter,
@@ -81,6 +93,9 @@ func NewProvisioningService(
 		deprovisioningQueue: deprovisioningQueue,
 		upgradeQueue:        upgradeQueue,
 		shootUpgradeQueue:   shootUpgradeQueue,
+		hibernationQueue:    hibernationQueue,
+		wakeUpQueue:         wakeUpQueue,
+		rollbackStarter:     rollbackStarter,
 	}
 }
 
@@ -111,7 +126,7 @@ func (r *service) ProvisionRuntime(config gqlschema.ProvisionRuntimeInput, tenan
 	defer dbSession.RollbackUnlessCommitted()
 
 	// Try to set provisioning started before triggering it (which is hard to interrupt) to verify all unique constraints
-	operation, dberr := r.setProvisioningStarted(dbSession, runtimeID, cluster)
+	operation, dberr := r.setProvisioningStarted(dbSession, runtimeID, cluster, util.UnwrapBoolOrDefault(config.Urgent, false))
 	if dberr != nil {
 		r.unregisterFailedRuntime(runtimeID, tenant)
 		return nil, apperrors.Internal(dberr.Error())
@@ -129,7 +144,7 @@ func (r *service) ProvisionRuntime(config gqlschema.ProvisionRuntimeInput, tenan
 		return nil, apperrors.Internal("Failed to commit transaction: %s", dberr.Error())
 	}
 
-	r.provisioningQueue.Add(operation.ID)
+	queue.Enqueue(r.provisioningQueue, operation)
 
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
@@ -192,6 +207,10 @@ func (r *service) UpgradeGardenerShoot(runtimeID string, input gqlschema.Upgrade
 		return &gqlschema.OperationStatus{}, apperrors.Internal("Failed to find shoot cluster to upgrade in database: %s", dberr.Error())
 	}
 
+	if cluster.Hibernated {
+		return &gqlschema.OperationStatus{}, apperrors.BadRequest("error: cannot upgrade Shoot of hibernated Runtime %s", runtimeID)
+	}
+
 	gardenerConfig, err := r.inputConverter.UpgradeShootInputToGardenerConfig(*input.GardenerConfig, cluster.ClusterConfig)
 	if err != nil {
 		return &gqlschema.OperationStatus{}, err.Append("Failed to convert GardenerClusterUpgradeConfig: %s", err.Error())
@@ -203,7 +222,7 @@ func (r *service) UpgradeGardenerShoot(runtimeID string, input gqlschema.Upgrade
 	}
 	defer txSession.RollbackUnlessCommitted()
 
-	operation, gardError := r.setGardenerShootUpgradeStarted(txSession, cluster, gardenerConfig)
+	operation, gardError := r.setGardenerShootUpgradeStarted(txSession, cluster, gardenerConfig, util.UnwrapBoolOrDefault(input.Urgent, false))
 	if gardError != nil {
 		return &gqlschema.OperationStatus{}, apperrors.Internal("Failed to set shoot upgrade started: %s", gardError.Error())
 	}
@@ -218,11 +237,95 @@ func (r *service) UpgradeGardenerShoot(runtimeID string, input gqlschema.Upgrade
 		return &gqlschema.OperationStatus{}, apperrors.Internal("Failed to commit upgrade transaction: %s", dbErr.Error())
 	}
 
-	r.shootUpgradeQueue.Add(operation.ID)
+	queue.Enqueue(r.shootUpgradeQueue, operation)
+
+	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
+}
+
+func (r *service) HibernateRuntime(runtimeID string) (*gqlschema.OperationStatus, apperrors.AppError) {
+	log.Infof("Starting hibernation of Runtime '%s'...", runtimeID)
+
+	cluster, err := r.getClusterForHibernationChange(runtimeID)
+	if err != nil {
+		return &gqlschema.OperationStatus{}, err
+	}
+
+	if cluster.Hibernated {
+		return &gqlschema.OperationStatus{}, apperrors.BadRequest("Runtime %s is already hibernated", runtimeID)
+	}
+
+	operation, err := r.setHibernationChangeStarted(runtimeID, model.Hibernate, model.StartingShootHibernation, "Starting Gardener Shoot hibernation")
+	if err != nil {
+		return &gqlschema.OperationStatus{}, err
+	}
+
+	r.hibernationQueue.Add(operation.ID)
 
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
 
+func (r *service) WakeUpRuntime(runtimeID string) (*gqlschema.OperationStatus, apperrors.AppError) {
+	log.Infof("Starting wake up of Runtime '%s'...", runtimeID)
+
+	cluster, err := r.getClusterForHibernationChange(runtimeID)
+	if err != nil {
+		return &gqlschema.OperationStatus{}, err
+	}
+
+	if !cluster.Hibernated {
+		return &gqlschema.OperationStatus{}, apperrors.BadRequest("Runtime %s is not hibernated", runtimeID)
+	}
+
+	operation, err := r.setHibernationChangeStarted(runtimeID, model.WakeUp, model.StartingShootWakeUp, "Starting Gardener Shoot wake up")
+	if err != nil {
+		return &gqlschema.OperationStatus{}, err
+	}
+
+	r.wakeUpQueue.Add(operation.ID)
+
+	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
+}
+
+func (r *service) getClusterForHibernationChange(runtimeID string) (model.Cluster, apperrors.AppError) {
+	session := r.dbSessionFactory.NewReadSession()
+
+	err := r.verifyLastOperationFinished(session, runtimeID)
+	if err != nil {
+		return model.Cluster{}, err
+	}
+
+	cluster, dberr := session.GetCluster(runtimeID)
+	if dberr != nil {
+		return model.Cluster{}, apperrors.Internal("failed to read cluster from database: %s", dberr.Error())
+	}
+
+	if cluster.Deleted {
+		return model.Cluster{}, apperrors.BadRequest("Runtime %s is deprovisioned", runtimeID)
+	}
+
+	return cluster, nil
+}
+
+func (r *service) setHibernationChangeStarted(runtimeID string, operationType model.OperationType, stage model.OperationStage, message string) (model.Operation, apperrors.AppError) {
+	txSession, dberr := r.dbSessionFactory.NewSessionWithinTransaction()
+	if dberr != nil {
+		return model.Operation{}, apperrors.Internal("failed to start database transaction: %s", dberr.Error())
+	}
+	defer txSession.RollbackUnlessCommitted()
+
+	operation, dberr := r.setOperationStarted(txSession, runtimeID, operationType, stage, time.Now(), message, false)
+	if dberr != nil {
+		return model.Operation{}, apperrors.Internal("failed to set operation started: %s", dberr.Error())
+	}
+
+	dberr = txSession.Commit()
+	if dberr != nil {
+		return model.Operation{}, apperrors.Internal("failed to commit transaction: %s", dberr.Error())
+	}
+
+	return operation, nil
+}
+
 func (r *service) verifyLastOperationFinished(session dbsession.ReadSession, runtimeId string) apperrors.AppError {
 	lastOperation, dberr := session.GetLastOperation(runtimeId)
 	if dberr != nil {
@@ -258,13 +361,17 @@ func (r *service) UpgradeRuntime(runtimeId string, input gqlschema.UpgradeRuntim
 		return &gqlschema.OperationStatus{}, apperrors.Internal("failed to read cluster from database: %s", dberr.Error())
 	}
 
+	if cluster.Hibernated {
+		return &gqlschema.OperationStatus{}, apperrors.BadRequest("error: cannot upgrade hibernated Runtime %s", runtimeId)
+	}
+
 	txSession, dberr := r.dbSessionFactory.NewSessionWithinTransaction()
 	if dberr != nil {
 		return &gqlschema.OperationStatus{}, apperrors.Internal("failed to start database transaction: %s", dberr.Error())
 	}
 	defer txSession.RollbackUnlessCommitted()
 
-	operation, dberr := r.setUpgradeStarted(txSession, cluster, kymaConfig)
+	operation, dberr := r.setUpgradeStarted(txSession, cluster, kymaConfig, util.UnwrapBoolOrDefault(input.Urgent, false))
 	if dberr != nil {
 		return &gqlschema.OperationStatus{}, apperrors.Internal("failed to set upgrade started: %s", dberr.Error())
 	}
@@ -274,7 +381,7 @@ func (r *service) UpgradeRuntime(runtimeId string, input gqlschema.UpgradeRuntim
 		return &gqlschema.OperationStatus{}, apperrors.Internal("failed to commit upgrade transaction: %s", dberr.Error())
 	}
 
-	r.upgradeQueue.Add(operation.ID)
+	queue.Enqueue(r.upgradeQueue, operation)
 
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
@@ -303,6 +410,117 @@ func (r *service) RuntimeOperationStatus(operationID string) (*gqlschema.Operati
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
 
+func (r *service) ListRuntimes(input *gqlschema.RuntimesFilter, first *int, after *string) (*gqlschema.RuntimesPage, apperrors.AppError) {
+	limit, cursor, err := pageRequest(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	readSession := r.dbSessionFactory.NewReadSession()
+
+	// One additional Runtime is fetched to find out if there is a next page
+	clusters, totalCount, dberr := readSession.ListRuntimes(r.inputConverter.RuntimesFilterFromInput(input), cursor, limit+1)
+	if dberr != nil {
+		return nil, apperrors.Internal("failed to list Runtimes: %s", dberr.Error())
+	}
+
+	hasNextPage := len(clusters) > limit
+	if hasNextPage {
+		clusters = clusters[:limit]
+	}
+
+	runtimeIDs := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		runtimeIDs = append(runtimeIDs, cluster.ID)
+	}
+
+	lastOperations, dberr := readSession.ListLastOperations(runtimeIDs)
+	if dberr != nil {
+		return nil, apperrors.Internal("failed to list last operations of Runtimes: %s", dberr.Error())
+	}
+
+	page := &gqlschema.RuntimesPage{
+		Data:       make([]*gqlschema.Runtime, 0, len(clusters)),
+		PageInfo:   &gqlschema.PageInfo{HasNextPage: hasNextPage},
+		TotalCount: totalCount,
+	}
+
+	for _, cluster := range clusters {
+		var lastOperation *model.Operation
+		if operation, found := lastOperations[cluster.ID]; found {
+			lastOperation = &operation
+		}
+		page.Data = append(page.Data, r.graphQLConverter.RuntimeToGraphQLRuntime(cluster, lastOperation))
+	}
+
+	if len(clusters) > 0 {
+		last := clusters[len(clusters)-1]
+		page.PageInfo.EndCursor = util.StringPtr(model.PageCursor{Timestamp: last.CreationTimestamp, ID: last.ID}.Encode())
+	}
+
+	return page, nil
+}
+
+func (r *service) ListOperations(input *gqlschema.OperationsFilter, first *int, after *string) (*gqlschema.OperationsPage, apperrors.AppError) {
+	limit, cursor, err := pageRequest(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := r.inputConverter.OperationsFilterFromInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	readSession := r.dbSessionFactory.NewReadSession()
+
+	// One additional operation is fetched to find out if there is a next page
+	operations, totalCount, dberr := readSession.ListOperations(filter, cursor, limit+1)
+	if dberr != nil {
+		return nil, apperrors.Internal("failed to list operations: %s", dberr.Error())
+	}
+
+	hasNextPage := len(operations) > limit
+	if hasNextPage {
+		operations = operations[:limit]
+	}
+
+	page := &gqlschema.OperationsPage{
+		Data:       make([]*gqlschema.OperationStatus, 0, len(operations)),
+		PageInfo:   &gqlschema.PageInfo{HasNextPage: hasNextPage},
+		TotalCount: totalCount,
+	}
+
+	for _, operation := range operations {
+		page.Data = append(page.Data, r.graphQLConverter.OperationStatusToGQLOperationStatus(operation))
+	}
+
+	if len(operations) > 0 {
+		last := operations[len(operations)-1]
+		page.PageInfo.EndCursor = util.StringPtr(model.PageCursor{Timestamp: last.StartTimestamp, ID: last.ID}.Encode())
+	}
+
+	return page, nil
+}
+
+func pageRequest(first *int, after *string) (int, *model.PageCursor, apperrors.AppError) {
+	limit := util.UnwrapIntOrDefault(first, model.DefaultPageSize)
+	if limit < 1 || limit > model.MaxPageSize {
+		return 0, nil, apperrors.BadRequest("page size must be between 1 and %d", model.MaxPageSize)
+	}
+
+	if after == nil || *after == "" {
+		return limit, nil, nil
+	}
+
+	cursor, err := model.DecodePageCursor(*after)
+	if err != nil {
+		return 0, nil, apperrors.BadRequest("invalid page cursor: %s", err.Error())
+	}
+
+	return limit, &cursor, nil
+}
+
 func (r *service) RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStatus, apperrors.AppError) {
 
 	readSession := r.dbSessionFactory.NewReadSession()
@@ -345,6 +563,44 @@ func (r *service) RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStatu
 	return r.RuntimeStatus(runtimeID)
 }
 
+func (r *service) RollbackRuntimeUpgrade(runtimeID string) (*gqlschema.OperationStatus, apperrors.AppError) {
+	readSession := r.dbSessionFactory.NewReadSession()
+
+	lastOp, dberr := readSession.GetLastOperation(runtimeID)
+	if dberr != nil {
+		return nil, apperrors.Internal("failed to get last operation: %s", dberr.Error())
+	}
+
+	if lastOp.Type != model.Upgrade || lastOp.State == model.InProgress {
+		return nil, apperrors.BadRequest("error: upgrade can be rolled back only if it is the last operation that is already finished")
+	}
+
+	runtimeUpgrade, dberr := readSession.GetRuntimeUpgrade(lastOp.ID)
+	if dberr != nil {
+		return nil, apperrors.Internal("failed to get Runtime upgrade: %s", dberr.Error())
+	}
+
+	if runtimeUpgrade.State == model.UpgradeRolledBack {
+		return nil, apperrors.BadRequest("error: upgrade of Runtime %s is already rolled back", runtimeID)
+	}
+
+	cluster, dberr := readSession.GetCluster(runtimeID)
+	if dberr != nil {
+		return nil, apperrors.Internal("failed to read cluster from database: %s", dberr.Error())
+	}
+
+	if cluster.Deleted || cluster.Hibernated {
+		return nil, apperrors.BadRequest("error: cannot roll back upgrade of deprovisioned or hibernated Runtime %s", runtimeID)
+	}
+
+	operation, err := r.rollbackStarter.StartRollback(runtimeID, lastOp.ID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to start rollback of Runtime upgrade: %s", err.Error())
+	}
+
+	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
+}
+
 func (r *service) getRuntimeStatus(runtimeID string) (model.RuntimeStatus, dberrors.Error) {
 	session := r.dbSessionFactory.NewReadSession()
 
@@ -364,7 +620,7 @@ func (r *service) getRuntimeStatus(runtimeID string) (model.RuntimeStatus, dberr
 	}, nil
 }
 
-func (r *service) setProvisioningStarted(dbSession dbsession.WriteSession, runtimeID string, cluster model.Cluster) (model.Operation, dberrors.Error) {
+func (r *service) setProvisioningStarted(dbSession dbsession.WriteSession, runtimeID string, cluster model.Cluster, urgent bool) (model.Operation, dberrors.Error) {
 	timestamp := time.Now()
 
 	cluster.CreationTimestamp = timestamp
@@ -384,7 +640,7 @@ func (r *service) setProvisioningStarted(dbSession dbsession.WriteSession, runti
 		return model.Operation{}, dberrors.Internal("Failed to set provisioning started: %s", err)
 	}
 
-	operation, err := r.setOperationStarted(dbSession, runtimeID, model.Provision, model.WaitingForClusterDomain, timestamp, "Provisioning started")
+	operation, err := r.setOperationStarted(dbSession, runtimeID, model.Provision, model.WaitingForClusterDomain, timestamp, "Provisioning started", urgent)
 	if err != nil {
 		return model.Operation{}, err.Append("Failed to set provisioning started: %s")
 	}
@@ -392,7 +648,7 @@ func (r *service) setProvisioningStarted(dbSession dbsession.WriteSession, runti
 	return operation, nil
 }
 
-func (r *service) setGardenerShootUpgradeStarted(txSession dbsession.WriteSession, currentCluster model.Cluster, gardenerConfig model.GardenerConfig) (model.Operation, error) {
+func (r *service) setGardenerShootUpgradeStarted(txSession dbsession.WriteSession, currentCluster model.Cluster, gardenerConfig model.GardenerConfig, urgent bool) (model.Operation, error) {
 	log.Infof("Starting Upgrade of Gardener Shoot operation")
 
 	dberr := txSession.UpdateGardenerClusterConfig(gardenerConfig)
@@ -400,7 +656,7 @@ func (r *service) setGardenerShootUpgradeStarted(txSession dbsession.WriteSessio
 		return model.Operation{}, dberrors.Internal("Failed to set Shoot Upgrade started: %s", dberr.Error())
 	}
 
-	operation, dbError := r.setOperationStarted(txSession, currentCluster.ID, model.UpgradeShoot, model.WaitingForShootNewVersion, time.Now(), "Starting Gardener Shoot upgrade")
+	operation, dbError := r.setOperationStarted(txSession, currentCluster.ID, model.UpgradeShoot, model.WaitingForShootNewVersion, time.Now(), "Starting Gardener Shoot upgrade", urgent)
 
 	if dbError != nil {
 		return model.Operation{}, dbError.Append("Failed to start operation of Gardener Shoot upgrade %s", dbError.Error())
@@ -409,14 +665,14 @@ func (r *service) setGardenerShootUpgradeStarted(txSession dbsession.WriteSessio
 	return operation, nil
 }
 
-func (r *service) setUpgradeStarted(txSession dbsession.WriteSession, cluster model.Cluster, kymaConfig model.KymaConfig) (model.Operation, dberrors.Error) {
+func (r *service) setUpgradeStarted(txSession dbsession.WriteSession, cluster model.Cluster, kymaConfig model.KymaConfig, urgent bool) (model.Operation, dberrors.Error) {
 
 	err := txSession.InsertKymaConfig(kymaConfig)
 	if err != nil {
 		return model.Operation{}, err.Append("Failed to insert Kyma Config")
 	}
 
-	operation, err := r.setOperationStarted(txSession, cluster.ID, model.Upgrade, model.StartingUpgrade, time.Now(), "Starting Kyma upgrade")
+	operation, err := r.setOperationStarted(txSession, cluster.ID, model.Upgrade, model.StartingUpgrade, time.Now(), "Starting Kyma upgrade", urgent)
 	if err != nil {
 		return model.Operation{}, err.Append("Failed to set operation started")
 	}
@@ -448,7 +704,8 @@ func (r *service) setOperationStarted(
 	operationType model.OperationType,
 	operationStage model.OperationStage,
 	timestamp time.Time,
-	message string) (model.Operation, dberrors.Error) {
+	message string,
+	urgent bool) (model.Operation, dberrors.Error) {
 	id := r.uuidGenerator.New()
 
 	operation := model.Operation{
@@ -460,6 +717,7 @@ func (r *service) setOperationStarted(
 		ClusterID:      runtimeID,
 		Stage:          operationStage,
 		LastTransition: &timestamp,
+		Urgent:         urgent,
 	}
 
 	err := dbSession.InsertOperation(operation)
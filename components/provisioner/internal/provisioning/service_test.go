@@ -6,6 +6,7 @@ import (
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/apperrors"
 
+	failureMocks "github.com/kyma-project/control-plane/components/provisioner/internal/operations/failure/mocks"
 	"github.com/kyma-project/control-plane/components/provisioner/internal/operations/mocks"
 
 	"github.com/kyma-project/control-plane/components/provisioner/internal/util"
@@ -110,7 +111,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 
 		provisioningQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, provisioningQueue, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, provisioningQueue, nil, nil, nil, nil, nil, nil)
 
 		//when
 		operationStatus, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -126,6 +127,44 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		releaseProvider.AssertExpectations(t)
 	})
 
+	t.Run("Should add urgent provisioning operation to the priority lane", func(t *testing.T) {
+		//given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		writeSessionWithinTransactionMock := &sessionMocks.WriteSessionWithinTransaction{}
+		directorServiceMock := &directormock.DirectorClient{}
+		provisioner := &mocks2.Provisioner{}
+
+		provisioningQueue := &mocks.OperationQueue{}
+
+		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return(runtimeID, nil)
+		sessionFactoryMock.On("NewSessionWithinTransaction").Return(writeSessionWithinTransactionMock, nil)
+		writeSessionWithinTransactionMock.On("InsertCluster", mock.MatchedBy(clusterMatcher)).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertGardenerConfig", mock.AnythingOfType("model.GardenerConfig")).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertKymaConfig", mock.AnythingOfType("model.KymaConfig")).Return(nil)
+		writeSessionWithinTransactionMock.On("InsertOperation", mock.MatchedBy(func(op model.Operation) bool {
+			return operationMatcher(op) && op.Urgent
+		})).Return(nil)
+		writeSessionWithinTransactionMock.On("Commit").Return(nil)
+		writeSessionWithinTransactionMock.On("RollbackUnlessCommitted").Return()
+		provisioner.On("ProvisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(nil)
+
+		provisioningQueue.On("AddPriority", mock.AnythingOfType("string")).Return(nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, provisioningQueue, nil, nil, nil, nil, nil, nil)
+
+		urgentInput := provisionRuntimeInput
+		urgentInput.Urgent = util.BoolPtr(true)
+
+		//when
+		operationStatus, err := service.ProvisionRuntime(urgentInput, tenant, subAccountId)
+		require.NoError(t, err)
+
+		//then
+		assert.NotEmpty(t, operationStatus.ID)
+		provisioningQueue.AssertExpectations(t)
+		provisioner.AssertExpectations(t)
+	})
+
 	t.Run("Should return error and unregister Runtime when failed to commit transaction", func(t *testing.T) {
 		//given
 		sessionFactoryMock := &sessionMocks.Factory{}
@@ -144,7 +183,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		provisioner.On("ProvisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(nil)
 		directorServiceMock.On("DeleteRuntime", runtimeID, tenant).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -176,7 +215,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 		provisioner.On("ProvisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(apperrors.Internal("error"))
 		directorServiceMock.On("DeleteRuntime", runtimeID, tenant).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -198,7 +237,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 
 		directorServiceMock.On("CreateRuntime", mock.Anything, tenant).Return("", apperrors.Internal("registering error"))
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, nil, nil, uuidGenerator, nil, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, nil, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -232,7 +271,7 @@ func TestService_ProvisionRuntime(t *testing.T) {
 
 		provisioningQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, provisioningQueue, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, directorServiceMock, sessionFactoryMock, provisioner, uuidGenerator, provisioningQueue, nil, nil, nil, nil, nil, nil)
 
 		//when
 		operationStatus, err := service.ProvisionRuntime(provisionRuntimeInput, tenant, subAccountId)
@@ -288,7 +327,7 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		provisioner.On("DeprovisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(operation, nil)
 		readWriteSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, deprovisioningQueue, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, deprovisioningQueue, nil, nil, nil, nil, nil)
 
 		//when
 		opID, err := resolver.DeprovisionRuntime(runtimeID, tenant)
@@ -312,7 +351,7 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		readWriteSession.On("GetCluster", runtimeID).Return(cluster, nil)
 		provisioner.On("DeprovisionCluster", mock.MatchedBy(clusterMatcher), mock.MatchedBy(notEmptyUUIDMatcher)).Return(model.Operation{}, apperrors.Internal("error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, provisioner, uuid.NewUUIDGenerator(), nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := resolver.DeprovisionRuntime(runtimeID, tenant)
@@ -335,7 +374,7 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		readWriteSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
 		readWriteSession.On("GetCluster", runtimeID).Return(model.Cluster{}, dberrors.Internal("error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := resolver.DeprovisionRuntime(runtimeID, tenant)
@@ -357,7 +396,7 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
 		readWriteSession.On("GetLastOperation", runtimeID).Return(operation, nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := resolver.DeprovisionRuntime(runtimeID, tenant)
@@ -377,7 +416,7 @@ func TestService_DeprovisionRuntime(t *testing.T) {
 		sessionFactoryMock.On("NewReadWriteSession").Return(readWriteSession)
 		readWriteSession.On("GetLastOperation", runtimeID).Return(model.Operation{}, dberrors.Internal("error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuid.NewUUIDGenerator(), nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := resolver.DeprovisionRuntime(runtimeID, tenant)
@@ -411,7 +450,7 @@ func TestService_RuntimeOperationStatus(t *testing.T) {
 		sessionFactoryMock.On("NewReadSession").Return(readSession)
 		readSession.On("GetOperation", operationID).Return(operation, nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		status, err := resolver.RuntimeOperationStatus(operationID)
@@ -434,7 +473,7 @@ func TestService_RuntimeOperationStatus(t *testing.T) {
 		sessionFactoryMock.On("NewReadSession").Return(readSession)
 		readSession.On("GetOperation", operationID).Return(model.Operation{}, dberrors.Internal("error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := resolver.RuntimeOperationStatus(operationID)
@@ -473,7 +512,7 @@ func TestService_RuntimeStatus(t *testing.T) {
 		readSession.On("GetLastOperation", operationID).Return(operation, nil)
 		readSession.On("GetCluster", operationID).Return(cluster, nil)
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		status, err := resolver.RuntimeStatus(operationID)
@@ -495,7 +534,7 @@ func TestService_RuntimeStatus(t *testing.T) {
 		readSession.On("GetLastOperation", operationID).Return(operation, nil)
 		readSession.On("GetCluster", operationID).Return(model.Cluster{}, dberrors.Internal("error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := resolver.RuntimeStatus(operationID)
@@ -514,7 +553,7 @@ func TestService_RuntimeStatus(t *testing.T) {
 		sessionFactoryMock.On("NewReadSession").Return(readSession)
 		readSession.On("GetLastOperation", operationID).Return(model.Operation{}, dberrors.Internal("error"))
 
-		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		_, err := resolver.RuntimeStatus(operationID)
@@ -526,6 +565,228 @@ func TestService_RuntimeStatus(t *testing.T) {
 	})
 }
 
+func TestService_ListRuntimes(t *testing.T) {
+	uuidGenerator := &uuidMocks.UUIDGenerator{}
+	inputConverter := NewInputConverter(uuidGenerator, nil, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate, forceAllowPrivilegedContainers)
+	graphQLConverter := NewGraphQLConverter()
+
+	creationTimestamp := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	newCluster := func(id string, created time.Time) model.Cluster {
+		return model.Cluster{
+			ID:                id,
+			Tenant:            tenant,
+			CreationTimestamp: created,
+			ClusterConfig:     model.GardenerConfig{Provider: "gcp", Region: "europe-west4"},
+			KymaConfig:        model.KymaConfig{Release: model.Release{Version: kymaVersion}},
+		}
+	}
+	clusters := []model.Cluster{
+		newCluster("runtime-3", creationTimestamp.Add(2*time.Hour)),
+		newCluster("runtime-2", creationTimestamp.Add(time.Hour)),
+		newCluster("runtime-1", creationTimestamp),
+	}
+	lastOperation := model.Operation{
+		ID:        operationID,
+		Type:      model.Provision,
+		State:     model.Succeeded,
+		ClusterID: "runtime-3",
+	}
+
+	expectedFilter := model.RuntimeFilter{Tenant: tenant, Provider: "gcp"}
+	inputFilter := &gqlschema.RuntimesFilter{Tenant: util.StringPtr(tenant), Provider: util.StringPtr("gcp")}
+
+	t.Run("Should return first page of Runtimes with last operations", func(t *testing.T) {
+		//given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("ListRuntimes", expectedFilter, (*model.PageCursor)(nil), 3).Return(clusters, 5, nil)
+		readSession.On("ListLastOperations", []string{"runtime-3", "runtime-2"}).Return(map[string]model.Operation{"runtime-3": lastOperation}, nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		page, err := service.ListRuntimes(inputFilter, util.IntPtr(2), nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, 5, page.TotalCount)
+		assert.True(t, page.PageInfo.HasNextPage)
+		require.Len(t, page.Data, 2)
+		assert.Equal(t, "runtime-3", page.Data[0].ID)
+		assert.Equal(t, kymaVersion, *page.Data[0].KymaVersion)
+		require.NotNil(t, page.Data[0].LastOperationStatus)
+		assert.Equal(t, gqlschema.OperationStateSucceeded, page.Data[0].LastOperationStatus.State)
+		assert.Equal(t, "runtime-2", page.Data[1].ID)
+		assert.Nil(t, page.Data[1].LastOperationStatus)
+
+		require.NotNil(t, page.PageInfo.EndCursor)
+		cursor, decodeErr := model.DecodePageCursor(*page.PageInfo.EndCursor)
+		require.NoError(t, decodeErr)
+		assert.Equal(t, model.PageCursor{Timestamp: creationTimestamp.Add(time.Hour), ID: "runtime-2"}, cursor)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+
+	t.Run("Should return last page of Runtimes starting after cursor", func(t *testing.T) {
+		//given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		after := model.PageCursor{Timestamp: creationTimestamp.Add(time.Hour), ID: "runtime-2"}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("ListRuntimes", model.RuntimeFilter{}, &after, model.DefaultPageSize+1).Return(clusters[2:], 3, nil)
+		readSession.On("ListLastOperations", []string{"runtime-1"}).Return(map[string]model.Operation{}, nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		page, err := service.ListRuntimes(nil, nil, util.StringPtr(after.Encode()))
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, 3, page.TotalCount)
+		assert.False(t, page.PageInfo.HasNextPage)
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "runtime-1", page.Data[0].ID)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+
+	for _, testCase := range []struct {
+		description string
+		first       *int
+		after       *string
+	}{
+		{
+			description: "page size is too small",
+			first:       util.IntPtr(0),
+		},
+		{
+			description: "page size is too big",
+			first:       util.IntPtr(model.MaxPageSize + 1),
+		},
+		{
+			description: "cursor is invalid",
+			after:       util.StringPtr("invalid cursor"),
+		},
+	} {
+		t.Run("Should return bad request error when "+testCase.description, func(t *testing.T) {
+			//given
+			service := NewProvisioningService(inputConverter, graphQLConverter, nil, nil, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+			//when
+			_, err := service.ListRuntimes(nil, testCase.first, testCase.after)
+
+			//then
+			require.Error(t, err)
+			assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+		})
+	}
+
+	t.Run("Should return error when failed to list Runtimes", func(t *testing.T) {
+		//given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("ListRuntimes", model.RuntimeFilter{}, (*model.PageCursor)(nil), model.DefaultPageSize+1).Return(nil, 0, dberrors.Internal("error"))
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		_, err := service.ListRuntimes(nil, nil, nil)
+
+		//then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeInternal, err.Code())
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+}
+
+func TestService_ListOperations(t *testing.T) {
+	uuidGenerator := &uuidMocks.UUIDGenerator{}
+	inputConverter := NewInputConverter(uuidGenerator, nil, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate, forceAllowPrivilegedContainers)
+	graphQLConverter := NewGraphQLConverter()
+
+	startTimestamp := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	operations := []model.Operation{
+		{ID: "operation-2", Type: model.Upgrade, State: model.Failed, ClusterID: runtimeID, StartTimestamp: startTimestamp.Add(time.Hour)},
+		{ID: "operation-1", Type: model.Upgrade, State: model.Failed, ClusterID: runtimeID, StartTimestamp: startTimestamp},
+	}
+
+	t.Run("Should return page of operations", func(t *testing.T) {
+		//given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		expectedFilter := model.OperationFilter{RuntimeID: runtimeID, Type: model.Upgrade, State: model.Failed}
+		operationType := gqlschema.OperationTypeUpgrade
+		operationState := gqlschema.OperationStateFailed
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("ListOperations", expectedFilter, (*model.PageCursor)(nil), 11).Return(operations, 2, nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		page, err := service.ListOperations(&gqlschema.OperationsFilter{
+			RuntimeID: util.StringPtr(runtimeID),
+			Operation: &operationType,
+			State:     &operationState,
+		}, util.IntPtr(10), nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, 2, page.TotalCount)
+		assert.False(t, page.PageInfo.HasNextPage)
+		require.Len(t, page.Data, 2)
+		assert.Equal(t, "operation-2", *page.Data[0].ID)
+		assert.Equal(t, gqlschema.OperationTypeUpgrade, page.Data[0].Operation)
+		assert.Equal(t, "operation-1", *page.Data[1].ID)
+		assert.Equal(t, model.PageCursor{Timestamp: startTimestamp, ID: "operation-1"}.Encode(), *page.PageInfo.EndCursor)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+
+	t.Run("Should return empty page when there are no operations", func(t *testing.T) {
+		//given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("ListOperations", model.OperationFilter{}, (*model.PageCursor)(nil), model.DefaultPageSize+1).Return([]model.Operation{}, 0, nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		page, err := service.ListOperations(nil, nil, nil)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, 0, page.TotalCount)
+		assert.Empty(t, page.Data)
+		assert.Nil(t, page.PageInfo.EndCursor)
+		assert.False(t, page.PageInfo.HasNextPage)
+	})
+
+	t.Run("Should return bad request error when filtering by Pending state", func(t *testing.T) {
+		//given
+		pending := gqlschema.OperationStatePending
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, nil, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		_, err := service.ListOperations(&gqlschema.OperationsFilter{State: &pending}, nil, nil)
+
+		//then
+		require.Error(t, err)
+		assert.Equal(t, apperrors.CodeBadRequest, err.Code())
+	})
+}
+
 func TestService_UpgradeRuntime(t *testing.T) {
 	releaseProvider := &releaseMocks.Provider{}
 	releaseProvider.On("GetReleaseByVersion", kymaVersion).Return(kymaRelease, nil)
@@ -583,7 +844,7 @@ func TestService_UpgradeRuntime(t *testing.T) {
 		writeSession.On("RollbackUnlessCommitted").Return()
 		upgradeQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, provisioningQueue, deprovisioningQueue, upgradeQueue, upgradeShootQueue)
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, provisioningQueue, deprovisioningQueue, upgradeQueue, upgradeShootQueue, nil, nil, nil)
 
 		//when
 		operationStatus, err := service.UpgradeRuntime(runtimeID, upgradeInput)
@@ -599,6 +860,30 @@ func TestService_UpgradeRuntime(t *testing.T) {
 		releaseProvider.AssertExpectations(t)
 	})
 
+	t.Run("Should return bad request error when Runtime is hibernated", func(t *testing.T) {
+		//given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		upgradeQueue := &mocks.OperationQueue{}
+
+		sessionFactory.On("NewReadSession").Return(readSession, nil)
+		readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readSession.On("GetCluster", runtimeID).Return(model.Cluster{ID: runtimeID, Hibernated: true}, nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, upgradeQueue, nil, nil, nil, nil)
+
+		//when
+		_, err := service.UpgradeRuntime(runtimeID, upgradeInput)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		sessionFactory.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+		sessionFactory.AssertNotCalled(t, "NewSessionWithinTransaction")
+		upgradeQueue.AssertNotCalled(t, "Add", mock.Anything)
+	})
+
 	for _, testCase := range []struct {
 		description string
 		mockFunc    func(sessionFactory *sessionMocks.Factory, writeSession *sessionMocks.WriteSessionWithinTransaction, readSession *sessionMocks.ReadSession)
@@ -650,7 +935,7 @@ func TestService_UpgradeRuntime(t *testing.T) {
 
 			testCase.mockFunc(sessionFactory, writeSession, readSession)
 
-			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, provisioningQueue, deprovisioningQueue, upgradeQueue, upgradeShootQueue)
+			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, provisioningQueue, deprovisioningQueue, upgradeQueue, upgradeShootQueue, nil, nil, nil)
 
 			//when
 			_, err := service.UpgradeRuntime(runtimeID, upgradeInput)
@@ -716,7 +1001,7 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 		writeSession.On("Commit").Return(nil)
 		upgradeShootQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, nil, nil, nil, upgradeShootQueue)
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, nil, nil, nil, upgradeShootQueue, nil, nil, nil)
 
 		//when
 		operationStatus, err := service.UpgradeGardenerShoot(runtimeID, upgradeShootInput)
@@ -731,6 +1016,35 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 		upgradeShootQueue.AssertExpectations(t)
 	})
 
+	t.Run("Should return bad request error when Runtime is hibernated", func(t *testing.T) {
+		//given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		upgradeShootQueue := &mocks.OperationQueue{}
+		provisioner := &mocks2.Provisioner{}
+
+		hibernatedCluster := cluster
+		hibernatedCluster.Hibernated = true
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readSession.On("GetCluster", runtimeID).Return(hibernatedCluster, nil)
+
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, nil, nil, nil, upgradeShootQueue, nil, nil, nil)
+
+		//when
+		_, err := service.UpgradeGardenerShoot(runtimeID, upgradeShootInput)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		sessionFactory.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+		sessionFactory.AssertNotCalled(t, "NewSessionWithinTransaction")
+		provisioner.AssertNotCalled(t, "UpgradeCluster", mock.Anything, mock.Anything)
+		upgradeShootQueue.AssertNotCalled(t, "Add", mock.Anything)
+	})
+
 	for _, testCase := range []struct {
 		description string
 		mockFunc    func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession, writeSession *sessionMocks.WriteSessionWithinTransaction, provisioner *mocks2.Provisioner)
@@ -811,7 +1125,7 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 
 			testCase.mockFunc(sessionFactory, readSession, writeSessionWithinTransaction, provisioner)
 
-			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, nil, nil, nil, upgradeShootQueue)
+			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactory, provisioner, uuidGenerator, nil, nil, nil, upgradeShootQueue, nil, nil, nil)
 
 			//when
 			_, err := service.UpgradeGardenerShoot(runtimeID, upgradeShootInput)
@@ -825,6 +1139,204 @@ func TestService_UpgradeGardenerShoot(t *testing.T) {
 	}
 }
 
+func TestService_HibernateRuntime(t *testing.T) {
+	graphQLConverter := NewGraphQLConverter()
+	uuidGenerator := uuid.NewUUIDGenerator()
+
+	lastOperation := model.Operation{State: model.Succeeded}
+	cluster := model.Cluster{ID: runtimeID}
+
+	operationMatcher := getOperationMatcher(model.Operation{
+		ClusterID: runtimeID,
+		State:     model.InProgress,
+		Type:      model.Hibernate,
+		Stage:     model.StartingShootHibernation,
+	})
+
+	t.Run("Should start Runtime hibernation and return operation status", func(t *testing.T) {
+		//given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+		hibernationQueue := &mocks.OperationQueue{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readSession.On("GetCluster", runtimeID).Return(cluster, nil)
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
+		writeSession.On("Commit").Return(nil)
+		writeSession.On("RollbackUnlessCommitted").Return()
+		hibernationQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, hibernationQueue, nil, nil)
+
+		//when
+		operationStatus, err := service.HibernateRuntime(runtimeID)
+		require.NoError(t, err)
+
+		//then
+		assert.Equal(t, runtimeID, *operationStatus.RuntimeID)
+		assert.Equal(t, gqlschema.OperationTypeHibernate, operationStatus.Operation)
+		assert.NotEmpty(t, operationStatus.ID)
+		sessionFactory.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+		hibernationQueue.AssertExpectations(t)
+	})
+
+	for _, testCase := range []struct {
+		description string
+		cluster     model.Cluster
+		errMessage  string
+	}{
+		{
+			description: "Runtime is already hibernated",
+			cluster:     model.Cluster{ID: runtimeID, Hibernated: true},
+			errMessage:  "already hibernated",
+		},
+		{
+			description: "Runtime is deprovisioned",
+			cluster:     model.Cluster{ID: runtimeID, Deleted: true},
+			errMessage:  "deprovisioned",
+		},
+	} {
+		t.Run("Should return bad request error when "+testCase.description, func(t *testing.T) {
+			//given
+			sessionFactory := &sessionMocks.Factory{}
+			readSession := &sessionMocks.ReadSession{}
+
+			sessionFactory.On("NewReadSession").Return(readSession)
+			readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+			readSession.On("GetCluster", runtimeID).Return(testCase.cluster, nil)
+
+			service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+			//when
+			_, err := service.HibernateRuntime(runtimeID)
+
+			//then
+			require.Error(t, err)
+			util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+			assert.Contains(t, err.Error(), testCase.errMessage)
+			sessionFactory.AssertExpectations(t)
+			readSession.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Should return error when last operation is in progress", func(t *testing.T) {
+		//given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", runtimeID).Return(model.Operation{State: model.InProgress}, nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		_, err := service.HibernateRuntime(runtimeID)
+
+		//then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "previous one is in progress")
+		sessionFactory.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when failed to insert operation", func(t *testing.T) {
+		//given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readSession.On("GetCluster", runtimeID).Return(cluster, nil)
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(dberrors.Internal("error"))
+		writeSession.On("RollbackUnlessCommitted").Return()
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		_, err := service.HibernateRuntime(runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeInternal)
+		sessionFactory.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+	})
+}
+
+func TestService_WakeUpRuntime(t *testing.T) {
+	graphQLConverter := NewGraphQLConverter()
+	uuidGenerator := uuid.NewUUIDGenerator()
+
+	lastOperation := model.Operation{State: model.Succeeded}
+
+	operationMatcher := getOperationMatcher(model.Operation{
+		ClusterID: runtimeID,
+		State:     model.InProgress,
+		Type:      model.WakeUp,
+		Stage:     model.StartingShootWakeUp,
+	})
+
+	t.Run("Should start Runtime wake up and return operation status", func(t *testing.T) {
+		//given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		writeSession := &sessionMocks.WriteSessionWithinTransaction{}
+		wakeUpQueue := &mocks.OperationQueue{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readSession.On("GetCluster", runtimeID).Return(model.Cluster{ID: runtimeID, Hibernated: true}, nil)
+		sessionFactory.On("NewSessionWithinTransaction").Return(writeSession, nil)
+		writeSession.On("InsertOperation", mock.MatchedBy(operationMatcher)).Return(nil)
+		writeSession.On("Commit").Return(nil)
+		writeSession.On("RollbackUnlessCommitted").Return()
+		wakeUpQueue.On("Add", mock.AnythingOfType("string")).Return(nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, nil, wakeUpQueue, nil)
+
+		//when
+		operationStatus, err := service.WakeUpRuntime(runtimeID)
+		require.NoError(t, err)
+
+		//then
+		assert.Equal(t, runtimeID, *operationStatus.RuntimeID)
+		assert.Equal(t, gqlschema.OperationTypeWakeUp, operationStatus.Operation)
+		sessionFactory.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+		writeSession.AssertExpectations(t)
+		wakeUpQueue.AssertExpectations(t)
+	})
+
+	t.Run("Should return bad request error when Runtime is not hibernated", func(t *testing.T) {
+		//given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readSession.On("GetCluster", runtimeID).Return(model.Cluster{ID: runtimeID}, nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
+
+		//when
+		_, err := service.WakeUpRuntime(runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		assert.Contains(t, err.Error(), "not hibernated")
+		sessionFactory.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+}
+
 func TestService_RollBackLastUpgrade(t *testing.T) {
 	releaseProvider := &releaseMocks.Provider{}
 	inputConverter := NewInputConverter(uuid.NewUUIDGenerator(), releaseProvider, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate, forceAllowPrivilegedContainers)
@@ -865,7 +1377,7 @@ func TestService_RollBackLastUpgrade(t *testing.T) {
 		writeSessionWithinTransactionMock.On("Commit").Return(nil)
 		writeSessionWithinTransactionMock.On("RollbackUnlessCommitted").Return()
 
-		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+		service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 		//when
 		runtimeStatus, err := service.RollBackLastUpgrade(runtimeID)
@@ -911,7 +1423,7 @@ func TestService_RollBackLastUpgrade(t *testing.T) {
 
 			testCase.mockFunc(sessionFactoryMock, writeSessionWithinTransactionMock, readSessionMock)
 
-			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil)
+			service := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, nil)
 
 			//when
 			_, err := service.RollBackLastUpgrade(runtimeID)
@@ -925,6 +1437,123 @@ func TestService_RollBackLastUpgrade(t *testing.T) {
 	}
 }
 
+func TestService_RollbackRuntimeUpgrade(t *testing.T) {
+	graphQLConverter := NewGraphQLConverter()
+	uuidGenerator := uuid.NewUUIDGenerator()
+
+	lastOperation := model.Operation{ID: operationID, ClusterID: runtimeID, State: model.Failed, Type: model.Upgrade}
+	runtimeUpgrade := model.RuntimeUpgrade{
+		State:                   model.UpgradeFailed,
+		OperationId:             operationID,
+		PreUpgradeKymaConfigId:  "old-kyma-config-id",
+		PostUpgradeKymaConfigId: "new-kyma-config-id",
+	}
+
+	t.Run("Should start rollback of Runtime upgrade and return operation status", func(t *testing.T) {
+		//given
+		sessionFactory := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+		rollbackStarter := &failureMocks.RollbackStarter{}
+
+		sessionFactory.On("NewReadSession").Return(readSession)
+		readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+		readSession.On("GetRuntimeUpgrade", operationID).Return(runtimeUpgrade, nil)
+		readSession.On("GetCluster", runtimeID).Return(model.Cluster{ID: runtimeID}, nil)
+		rollbackStarter.On("StartRollback", runtimeID, operationID).Return(model.Operation{
+			ID:        "rollback-operation-id",
+			ClusterID: runtimeID,
+			State:     model.InProgress,
+			Type:      model.Rollback,
+			Stage:     model.StartingRollback,
+		}, nil)
+
+		service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, rollbackStarter)
+
+		//when
+		operationStatus, err := service.RollbackRuntimeUpgrade(runtimeID)
+		require.NoError(t, err)
+
+		//then
+		assert.Equal(t, runtimeID, *operationStatus.RuntimeID)
+		assert.Equal(t, "rollback-operation-id", *operationStatus.ID)
+		assert.Equal(t, gqlschema.OperationTypeRollback, operationStatus.Operation)
+		sessionFactory.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+		rollbackStarter.AssertExpectations(t)
+	})
+
+	for _, testCase := range []struct {
+		description string
+		mockFunc    func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession)
+		errorCode   apperrors.ErrCode
+	}{
+		{
+			description: "should return bad request error when last operation is not upgrade",
+			mockFunc: func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession) {
+				sessionFactory.On("NewReadSession").Return(readSession)
+				readSession.On("GetLastOperation", runtimeID).Return(model.Operation{ID: operationID, State: model.Succeeded, Type: model.Provision}, nil)
+			},
+			errorCode: apperrors.CodeBadRequest,
+		},
+		{
+			description: "should return bad request error when upgrade is in progress",
+			mockFunc: func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession) {
+				sessionFactory.On("NewReadSession").Return(readSession)
+				readSession.On("GetLastOperation", runtimeID).Return(model.Operation{ID: operationID, State: model.InProgress, Type: model.Upgrade}, nil)
+			},
+			errorCode: apperrors.CodeBadRequest,
+		},
+		{
+			description: "should return bad request error when upgrade is already rolled back",
+			mockFunc: func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession) {
+				sessionFactory.On("NewReadSession").Return(readSession)
+				readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+				readSession.On("GetRuntimeUpgrade", operationID).Return(model.RuntimeUpgrade{State: model.UpgradeRolledBack}, nil)
+			},
+			errorCode: apperrors.CodeBadRequest,
+		},
+		{
+			description: "should return bad request error when Runtime is hibernated",
+			mockFunc: func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession) {
+				sessionFactory.On("NewReadSession").Return(readSession)
+				readSession.On("GetLastOperation", runtimeID).Return(lastOperation, nil)
+				readSession.On("GetRuntimeUpgrade", operationID).Return(runtimeUpgrade, nil)
+				readSession.On("GetCluster", runtimeID).Return(model.Cluster{ID: runtimeID, Hibernated: true}, nil)
+			},
+			errorCode: apperrors.CodeBadRequest,
+		},
+		{
+			description: "should return internal error when failed to get last operation",
+			mockFunc: func(sessionFactory *sessionMocks.Factory, readSession *sessionMocks.ReadSession) {
+				sessionFactory.On("NewReadSession").Return(readSession)
+				readSession.On("GetLastOperation", runtimeID).Return(model.Operation{}, dberrors.Internal("error"))
+			},
+			errorCode: apperrors.CodeInternal,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			//given
+			sessionFactory := &sessionMocks.Factory{}
+			readSession := &sessionMocks.ReadSession{}
+			rollbackStarter := &failureMocks.RollbackStarter{}
+
+			testCase.mockFunc(sessionFactory, readSession)
+
+			service := NewProvisioningService(nil, graphQLConverter, nil, sessionFactory, nil, uuidGenerator, nil, nil, nil, nil, nil, nil, rollbackStarter)
+
+			//when
+			_, err := service.RollbackRuntimeUpgrade(runtimeID)
+
+			//then
+			require.Error(t, err)
+			util.CheckErrorType(t, err, testCase.errorCode)
+			sessionFactory.AssertExpectations(t)
+			readSession.AssertExpectations(t)
+			rollbackStarter.AssertNotCalled(t, "StartRollback", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func getOperationMatcher(expected model.Operation) func(model.Operation) bool {
 	return func(op model.Operation) bool {
 		return op.Type == expected.Type && op.ClusterID == expected.ClusterID &&
@@ -118,3 +118,15 @@ func (ts *TestShoot) WithOperationNil() *TestShoot {
 	ts.shoot.Status.LastOperation = nil
 	return ts
 }
+
+// WithHibernationEnabled sets value of shoot.Spec.Hibernation.Enabled
+func (ts *TestShoot) WithHibernationEnabled(enabled bool) *TestShoot {
+	ts.shoot.Spec.Hibernation = &v1beta1.Hibernation{Enabled: &enabled}
+	return ts
+}
+
+// WithHibernated sets value of shoot.Status.IsHibernated
+func (ts *TestShoot) WithHibernated(hibernated bool) *TestShoot {
+	ts.shoot.Status.IsHibernated = hibernated
+	return ts
+}
@@ -107,48 +107,79 @@ type GardenerConfig struct {
 	EnableMachineImageVersionAutoUpdate *bool                  `json:"enableMachineImageVersionAutoUpdate"`
 	AllowPrivilegedContainers           *bool                  `json:"allowPrivilegedContainers"`
 	ProviderSpecificConfig              ProviderSpecificConfig `json:"providerSpecificConfig"`
+	HibernationSchedules                []*HibernationSchedule `json:"hibernationSchedules"`
+	WorkerPools                         []*WorkerPool          `json:"workerPools"`
+	KubeAPIServerConfig                 *KubeAPIServerConfig   `json:"kubeAPIServerConfig"`
 }
 
 type GardenerConfigInput struct {
-	Name                                *string                `json:"name"`
-	KubernetesVersion                   string                 `json:"kubernetesVersion"`
-	Provider                            string                 `json:"provider"`
-	TargetSecret                        string                 `json:"targetSecret"`
-	Region                              string                 `json:"region"`
-	MachineType                         string                 `json:"machineType"`
-	MachineImage                        *string                `json:"machineImage"`
-	MachineImageVersion                 *string                `json:"machineImageVersion"`
-	DiskType                            string                 `json:"diskType"`
-	VolumeSizeGb                        int                    `json:"volumeSizeGB"`
-	WorkerCidr                          string                 `json:"workerCidr"`
-	AutoScalerMin                       int                    `json:"autoScalerMin"`
-	AutoScalerMax                       int                    `json:"autoScalerMax"`
-	MaxSurge                            int                    `json:"maxSurge"`
-	MaxUnavailable                      int                    `json:"maxUnavailable"`
-	Purpose                             *string                `json:"purpose"`
-	LicenceType                         *string                `json:"licenceType"`
-	EnableKubernetesVersionAutoUpdate   *bool                  `json:"enableKubernetesVersionAutoUpdate"`
-	EnableMachineImageVersionAutoUpdate *bool                  `json:"enableMachineImageVersionAutoUpdate"`
-	AllowPrivilegedContainers           *bool                  `json:"allowPrivilegedContainers"`
-	ProviderSpecificConfig              *ProviderSpecificInput `json:"providerSpecificConfig"`
-	Seed                                *string                `json:"seed"`
+	Name                                *string                     `json:"name"`
+	KubernetesVersion                   string                      `json:"kubernetesVersion"`
+	Provider                            string                      `json:"provider"`
+	TargetSecret                        string                      `json:"targetSecret"`
+	Region                              string                      `json:"region"`
+	MachineType                         string                      `json:"machineType"`
+	MachineImage                        *string                     `json:"machineImage"`
+	MachineImageVersion                 *string                     `json:"machineImageVersion"`
+	DiskType                            string                      `json:"diskType"`
+	VolumeSizeGb                        int                         `json:"volumeSizeGB"`
+	WorkerCidr                          string                      `json:"workerCidr"`
+	AutoScalerMin                       int                         `json:"autoScalerMin"`
+	AutoScalerMax                       int                         `json:"autoScalerMax"`
+	MaxSurge                            int                         `json:"maxSurge"`
+	MaxUnavailable                      int                         `json:"maxUnavailable"`
+	Purpose                             *string                     `json:"purpose"`
+	LicenceType                         *string                     `json:"licenceType"`
+	EnableKubernetesVersionAutoUpdate   *bool                       `json:"enableKubernetesVersionAutoUpdate"`
+	EnableMachineImageVersionAutoUpdate *bool                       `json:"enableMachineImageVersionAutoUpdate"`
+	AllowPrivilegedContainers           *bool                       `json:"allowPrivilegedContainers"`
+	ProviderSpecificConfig              *ProviderSpecificInput      `json:"providerSpecificConfig"`
+	Seed                                *string                     `json:"seed"`
+	HibernationSchedules                []*HibernationScheduleInput `json:"hibernationSchedules"`
+	WorkerPools                         []*WorkerPoolInput          `json:"workerPools"`
+	KubeAPIServerConfig                 *KubeAPIServerConfigInput   `json:"kubeAPIServerConfig"`
 }
 
 type GardenerUpgradeInput struct {
-	KubernetesVersion                   *string                `json:"kubernetesVersion"`
-	MachineType                         *string                `json:"machineType"`
-	DiskType                            *string                `json:"diskType"`
-	VolumeSizeGb                        *int                   `json:"volumeSizeGB"`
-	AutoScalerMin                       *int                   `json:"autoScalerMin"`
-	AutoScalerMax                       *int                   `json:"autoScalerMax"`
-	MachineImage                        *string                `json:"machineImage"`
-	MachineImageVersion                 *string                `json:"machineImageVersion"`
-	MaxSurge                            *int                   `json:"maxSurge"`
-	MaxUnavailable                      *int                   `json:"maxUnavailable"`
-	Purpose                             *string                `json:"purpose"`
-	EnableKubernetesVersionAutoUpdate   *bool                  `json:"enableKubernetesVersionAutoUpdate"`
-	EnableMachineImageVersionAutoUpdate *bool                  `json:"enableMachineImageVersionAutoUpdate"`
-	ProviderSpecificConfig              *ProviderSpecificInput `json:"providerSpecificConfig"`
+	KubernetesVersion                   *string                     `json:"kubernetesVersion"`
+	MachineType                         *string                     `json:"machineType"`
+	DiskType                            *string                     `json:"diskType"`
+	VolumeSizeGb                        *int                        `json:"volumeSizeGB"`
+	AutoScalerMin                       *int                        `json:"autoScalerMin"`
+	AutoScalerMax                       *int                        `json:"autoScalerMax"`
+	MachineImage                        *string                     `json:"machineImage"`
+	MachineImageVersion                 *string                     `json:"machineImageVersion"`
+	MaxSurge                            *int                        `json:"maxSurge"`
+	MaxUnavailable                      *int                        `json:"maxUnavailable"`
+	Purpose                             *string                     `json:"purpose"`
+	EnableKubernetesVersionAutoUpdate   *bool                       `json:"enableKubernetesVersionAutoUpdate"`
+	EnableMachineImageVersionAutoUpdate *bool                       `json:"enableMachineImageVersionAutoUpdate"`
+	ProviderSpecificConfig              *ProviderSpecificInput      `json:"providerSpecificConfig"`
+	HibernationSchedules                []*HibernationScheduleInput `json:"hibernationSchedules"`
+	WorkerPools                         []*WorkerPoolInput          `json:"workerPools"`
+	KubeAPIServerConfig                 *KubeAPIServerConfigInput   `json:"kubeAPIServerConfig"`
+}
+
+type HibernationSchedule struct {
+	Start    *string `json:"start"`
+	End      *string `json:"end"`
+	Location *string `json:"location"`
+}
+
+type HibernationScheduleInput struct {
+	Start    *string `json:"start"`
+	End      *string `json:"end"`
+	Location *string `json:"location"`
+}
+
+type KubeAPIServerConfig struct {
+	OidcConfig       *OIDCConfig `json:"oidcConfig"`
+	AdmissionPlugins []string    `json:"admissionPlugins"`
+}
+
+type KubeAPIServerConfigInput struct {
+	OidcConfig       *OIDCConfigInput `json:"oidcConfig"`
+	AdmissionPlugins []string         `json:"admissionPlugins"`
 }
 
 type KymaConfig struct {
@@ -165,6 +196,26 @@ type KymaConfigInput struct {
 	Configuration []*ConfigEntryInput            `json:"configuration"`
 }
 
+type OIDCConfig struct {
+	IssuerURL      string   `json:"issuerURL"`
+	ClientID       string   `json:"clientID"`
+	UsernameClaim  *string  `json:"usernameClaim"`
+	UsernamePrefix *string  `json:"usernamePrefix"`
+	GroupsClaim    *string  `json:"groupsClaim"`
+	GroupsPrefix   *string  `json:"groupsPrefix"`
+	SigningAlgs    []string `json:"signingAlgs"`
+}
+
+type OIDCConfigInput struct {
+	IssuerURL      string   `json:"issuerURL"`
+	ClientID       string   `json:"clientID"`
+	UsernameClaim  *string  `json:"usernameClaim"`
+	UsernamePrefix *string  `json:"usernamePrefix"`
+	GroupsClaim    *string  `json:"groupsClaim"`
+	GroupsPrefix   *string  `json:"groupsPrefix"`
+	SigningAlgs    []string `json:"signingAlgs"`
+}
+
 type OperationStatus struct {
 	ID        *string        `json:"id"`
 	Operation OperationType  `json:"operation"`
@@ -173,6 +224,25 @@ type OperationStatus struct {
 	RuntimeID *string        `json:"runtimeID"`
 }
 
+type OperationsFilter struct {
+	Tenant       *string         `json:"tenant"`
+	SubAccountID *string         `json:"subAccountID"`
+	RuntimeID    *string         `json:"runtimeID"`
+	Operation    *OperationType  `json:"operation"`
+	State        *OperationState `json:"state"`
+}
+
+type OperationsPage struct {
+	Data       []*OperationStatus `json:"data"`
+	PageInfo   *PageInfo          `json:"pageInfo"`
+	TotalCount int                `json:"totalCount"`
+}
+
+type PageInfo struct {
+	EndCursor   *string `json:"endCursor"`
+	HasNextPage bool    `json:"hasNextPage"`
+}
+
 type ProviderSpecificInput struct {
 	GcpConfig   *GCPProviderConfigInput   `json:"gcpConfig"`
 	AzureConfig *AzureProviderConfigInput `json:"azureConfig"`
@@ -183,6 +253,16 @@ type ProvisionRuntimeInput struct {
 	RuntimeInput  *RuntimeInput       `json:"runtimeInput"`
 	ClusterConfig *ClusterConfigInput `json:"clusterConfig"`
 	KymaConfig    *KymaConfigInput    `json:"kymaConfig"`
+	Urgent        *bool               `json:"urgent"`
+}
+
+type Runtime struct {
+	ID                  string           `json:"id"`
+	Tenant              string           `json:"tenant"`
+	SubAccountID        *string          `json:"subAccountID"`
+	ClusterConfig       *GardenerConfig  `json:"clusterConfig"`
+	KymaVersion         *string          `json:"kymaVersion"`
+	LastOperationStatus *OperationStatus `json:"lastOperationStatus"`
 }
 
 type RuntimeConfig struct {
@@ -206,14 +286,76 @@ type RuntimeStatus struct {
 	LastOperationStatus     *OperationStatus         `json:"lastOperationStatus"`
 	RuntimeConnectionStatus *RuntimeConnectionStatus `json:"runtimeConnectionStatus"`
 	RuntimeConfiguration    *RuntimeConfig           `json:"runtimeConfiguration"`
+	Hibernated              bool                     `json:"hibernated"`
+}
+
+type RuntimesFilter struct {
+	Tenant            *string `json:"tenant"`
+	SubAccountID      *string `json:"subAccountID"`
+	Provider          *string `json:"provider"`
+	Region            *string `json:"region"`
+	KubernetesVersion *string `json:"kubernetesVersion"`
+	KymaVersion       *string `json:"kymaVersion"`
+}
+
+type RuntimesPage struct {
+	Data       []*Runtime `json:"data"`
+	PageInfo   *PageInfo  `json:"pageInfo"`
+	TotalCount int        `json:"totalCount"`
+}
+
+type Taint struct {
+	Key    string  `json:"key"`
+	Value  *string `json:"value"`
+	Effect string  `json:"effect"`
+}
+
+type TaintInput struct {
+	Key    string  `json:"key"`
+	Value  *string `json:"value"`
+	Effect string  `json:"effect"`
 }
 
 type UpgradeRuntimeInput struct {
 	KymaConfig *KymaConfigInput `json:"kymaConfig"`
+	Urgent     *bool            `json:"urgent"`
 }
 
 type UpgradeShootInput struct {
 	GardenerConfig *GardenerUpgradeInput `json:"gardenerConfig"`
+	Urgent         *bool                 `json:"urgent"`
+}
+
+type WorkerPool struct {
+	Name                string   `json:"name"`
+	MachineType         *string  `json:"machineType"`
+	MachineImage        *string  `json:"machineImage"`
+	MachineImageVersion *string  `json:"machineImageVersion"`
+	DiskType            *string  `json:"diskType"`
+	VolumeSizeGb        *int     `json:"volumeSizeGB"`
+	AutoScalerMin       *int     `json:"autoScalerMin"`
+	AutoScalerMax       *int     `json:"autoScalerMax"`
+	MaxSurge            *int     `json:"maxSurge"`
+	MaxUnavailable      *int     `json:"maxUnavailable"`
+	Zones               []string `json:"zones"`
+	Labels              *Labels  `json:"labels"`
+	Taints              []*Taint `json:"taints"`
+}
+
+type WorkerPoolInput struct {
+	Name                string        `json:"name"`
+	MachineType         string        `json:"machineType"`
+	MachineImage        *string       `json:"machineImage"`
+	MachineImageVersion *string       `json:"machineImageVersion"`
+	DiskType            *string       `json:"diskType"`
+	VolumeSizeGb        *int          `json:"volumeSizeGB"`
+	AutoScalerMin       int           `json:"autoScalerMin"`
+	AutoScalerMax       int           `json:"autoScalerMax"`
+	MaxSurge            *int          `json:"maxSurge"`
+	MaxUnavailable      *int          `json:"maxUnavailable"`
+	Zones               []string      `json:"zones"`
+	Labels              *Labels       `json:"labels"`
+	Taints              []*TaintInput `json:"taints"`
 }
 
 type KymaProfile string
@@ -310,6 +452,9 @@ const (
 	OperationTypeUpgradeShoot     OperationType = "UpgradeShoot"
 	OperationTypeDeprovision      OperationType = "Deprovision"
 	OperationTypeReconnectRuntime OperationType = "ReconnectRuntime"
+	OperationTypeHibernate        OperationType = "Hibernate"
+	OperationTypeWakeUp           OperationType = "WakeUp"
+	OperationTypeRollback         OperationType = "Rollback"
 )
 
 var AllOperationType = []OperationType{
@@ -318,11 +463,14 @@ var AllOperationType = []OperationType{
 	OperationTypeUpgradeShoot,
 	OperationTypeDeprovision,
 	OperationTypeReconnectRuntime,
+	OperationTypeHibernate,
+	OperationTypeWakeUp,
+	OperationTypeRollback,
 }
 
 func (e OperationType) IsValid() bool {
 	switch e {
-	case OperationTypeProvision, OperationTypeUpgrade, OperationTypeUpgradeShoot, OperationTypeDeprovision, OperationTypeReconnectRuntime:
+	case OperationTypeProvision, OperationTypeUpgrade, OperationTypeUpgradeShoot, OperationTypeDeprovision, OperationTypeReconnectRuntime, OperationTypeHibernate, OperationTypeWakeUp, OperationTypeRollback:
 		return true
 	}
 	return false
@@ -0,0 +1 @@
+DROP TABLE subaccount_cleanup_runs;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS subaccount_cleanup_runs (
+    id varchar(255) PRIMARY KEY,
+    dry_run boolean NOT NULL DEFAULT false,
+    aborted boolean NOT NULL DEFAULT false,
+    description text,
+    data text,
+    created_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL
+);
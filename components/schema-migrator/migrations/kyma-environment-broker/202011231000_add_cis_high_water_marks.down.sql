@@ -0,0 +1 @@
+DROP TABLE cis_high_water_marks;
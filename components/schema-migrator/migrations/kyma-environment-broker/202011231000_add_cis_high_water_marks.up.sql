@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS cis_high_water_marks (
+    source varchar(255) PRIMARY KEY,
+    last_event_id varchar(255),
+    last_event_time TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
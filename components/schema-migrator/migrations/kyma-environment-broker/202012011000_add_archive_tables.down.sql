@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS operations_type_state_updated_at;
+DROP TABLE orchestrations_archive;
+DROP TABLE operations_archive;
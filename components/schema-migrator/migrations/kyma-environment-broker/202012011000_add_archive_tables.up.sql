@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS operations_archive (
+    id varchar(255) PRIMARY KEY,
+    instance_id varchar(255) NOT NULL,
+    target_operation_id varchar(255) NOT NULL,
+    version integer NOT NULL,
+    state varchar(32) NOT NULL,
+    description text NOT NULL,
+    type varchar(32) NOT NULL,
+    data json NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    orchestration_id varchar(64),
+    archived_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS orchestrations_archive (
+    orchestration_id varchar(255) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    state varchar(32) NOT NULL,
+    parameters text NOT NULL,
+    description text,
+    archived_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS operations_type_state_updated_at ON operations (type, state, updated_at);
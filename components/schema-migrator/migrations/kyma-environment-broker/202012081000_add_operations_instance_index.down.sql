@@ -0,0 +1 @@
+DROP INDEX IF EXISTS operations_instance_id_type_created_at;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS operations_instance_id_type_created_at ON operations (instance_id, type, created_at);
@@ -0,0 +1 @@
+DROP TABLE ias_rotations;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS ias_rotations (
+    instance_id varchar(255) PRIMARY KEY,
+    secret_rotated_at TIMESTAMPTZ NOT NULL,
+    dashboard_url text NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1 @@
+DROP TABLE lms_certificates;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS lms_certificates (
+    instance_id varchar(255) PRIMARY KEY,
+    tenant_id varchar(255) NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1 @@
+-- the resumed operations cannot be distinguished from the other operations in progress
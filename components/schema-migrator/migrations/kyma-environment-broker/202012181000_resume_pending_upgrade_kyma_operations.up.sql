@@ -0,0 +1,2 @@
+-- upgrade kyma operations created outside of orchestrations are resumed only when they are in progress
+UPDATE operations SET state = 'in progress' WHERE type = 'upgradeKyma' AND state = 'pending' AND orchestration_id IS NULL;
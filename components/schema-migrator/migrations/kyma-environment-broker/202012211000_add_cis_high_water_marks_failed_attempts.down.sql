@@ -0,0 +1 @@
+ALTER TABLE cis_high_water_marks DROP COLUMN failed_attempts;
@@ -0,0 +1,2 @@
+ALTER TABLE cis_high_water_marks
+    ADD COLUMN failed_attempts integer NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS operations_instance_id_same_version_upgrade_in_progress;
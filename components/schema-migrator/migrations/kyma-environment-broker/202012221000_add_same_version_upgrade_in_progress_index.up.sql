@@ -0,0 +1,9 @@
+-- only the newest upgrade kyma operation created outside of orchestrations can stay in progress for the instance
+UPDATE operations o SET state = 'failed', description = 'Operation failed: superseded by a newer operation of the instance'
+WHERE o.type = 'upgradeKyma' AND o.state IN ('pending', 'in progress') AND (o.orchestration_id IS NULL OR o.orchestration_id = '')
+  AND EXISTS (SELECT 1 FROM operations n
+              WHERE n.instance_id = o.instance_id AND n.type = 'upgradeKyma' AND n.state IN ('pending', 'in progress')
+                AND (n.orchestration_id IS NULL OR n.orchestration_id = '') AND (n.created_at, n.id) > (o.created_at, o.id));
+
+CREATE UNIQUE INDEX IF NOT EXISTS operations_instance_id_same_version_upgrade_in_progress ON operations (instance_id)
+    WHERE type = 'upgradeKyma' AND state IN ('pending', 'in progress') AND (orchestration_id IS NULL OR orchestration_id = '');
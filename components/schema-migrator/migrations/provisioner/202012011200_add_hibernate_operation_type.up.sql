@@ -0,0 +1 @@
+ALTER TYPE operation_type ADD VALUE 'HIBERNATE' AFTER 'UPGRADE_SHOOT';
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE cluster DROP COLUMN hibernated;
+
+ALTER TABLE gardener_config DROP COLUMN hibernation_schedules;
+
+COMMIT;
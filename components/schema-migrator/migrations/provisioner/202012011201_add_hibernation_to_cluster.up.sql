@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE cluster ADD COLUMN hibernated boolean NOT NULL DEFAULT false;
+
+ALTER TABLE gardener_config ADD COLUMN hibernation_schedules jsonb;
+
+COMMIT;
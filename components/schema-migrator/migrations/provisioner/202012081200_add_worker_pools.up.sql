@@ -0,0 +1,20 @@
+CREATE TABLE worker_pool
+(
+    id uuid PRIMARY KEY CHECK (id <> '00000000-0000-0000-0000-000000000000'),
+    gardener_config_id uuid NOT NULL,
+    name varchar(256) NOT NULL,
+    machine_type varchar(256) NOT NULL,
+    machine_image varchar(256),
+    machine_image_version varchar(256),
+    disk_type varchar(256) NOT NULL,
+    volume_size_gb integer NOT NULL,
+    auto_scaler_min integer NOT NULL,
+    auto_scaler_max integer NOT NULL,
+    max_surge integer NOT NULL,
+    max_unavailable integer NOT NULL,
+    zones jsonb,
+    labels jsonb,
+    taints jsonb,
+    UNIQUE(gardener_config_id, name),
+    foreign key (gardener_config_id) REFERENCES gardener_config (id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE gardener_config DROP COLUMN kube_api_server_config;
+
+COMMIT;
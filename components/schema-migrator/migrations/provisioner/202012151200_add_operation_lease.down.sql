@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE operation DROP COLUMN lease_owner;
+ALTER TABLE operation DROP COLUMN lease_expiration;
+
+COMMIT;
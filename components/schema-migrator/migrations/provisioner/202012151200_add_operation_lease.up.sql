@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE operation ADD COLUMN lease_owner varchar(256);
+ALTER TABLE operation ADD COLUMN lease_expiration timestamp without time zone;
+
+COMMIT;
@@ -0,0 +1 @@
+ALTER TYPE operation_type ADD VALUE 'ROLLBACK' AFTER 'WAKE_UP';
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE operation ADD COLUMN urgent boolean NOT NULL DEFAULT false;
+
+COMMIT;
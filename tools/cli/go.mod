@@ -23,6 +23,7 @@ replace (
 	github.com/census-instrumentation/opencensus-proto v0.1.0-0.20181214143942-ba49f56771b8 => github.com/census-instrumentation/opencensus-proto v0.0.3-0.20181214143942-ba49f56771b8
 	github.com/gardener/gardener => github.com/gardener/gardener v1.2.3
 	github.com/googleapis/gnostic => github.com/googleapis/gnostic v0.3.1
+	github.com/kyma-project/control-plane => ../../
 	k8s.io/api => k8s.io/api v0.17.14
 	k8s.io/apimachinery => k8s.io/apimachinery v0.17.14
 	k8s.io/apiserver => k8s.io/apiserver v0.17.14
@@ -1,6 +1,10 @@
 package command
 
 import (
+	"fmt"
+	"os"
+	"text/template"
+
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/control-plane/tools/cli/pkg/logger"
 	"github.com/kyma-project/control-plane/tools/cli/pkg/printer"
@@ -49,20 +53,84 @@ var tableColumns = []printer.Column{
 	},
 }
 
+var runtimeDetailsTpl = `Runtime ID:         {{.RuntimeID}}
+Instance ID:        {{.InstanceID}}
+Global Account ID:  {{.GlobalAccountID}}
+Subaccount ID:      {{.SubAccountID}}
+Shoot Name:         {{.ShootName}}
+Region:             {{.ProviderRegion}}
+Service Plan:       {{.ServicePlanName}}
+Kyma Version:       {{.KymaVersion}}
+State:              {{runtimeStatus .RuntimeDTO}}
+Created At:         {{runtimeCreatedAt .RuntimeDTO}}
+{{- with .RuntimeConfig }}
+Configuration:
+  Provider:           {{.Provider}}
+  Region:             {{.Region}}
+  Kubernetes Version: {{.KubernetesVersion}}
+  Machine Type:       {{.MachineType}}
+  Auto Scaler:        {{.AutoScalerMin}} - {{.AutoScalerMax}}
+  Kyma Version:       {{.KymaVersion}}
+  {{- if .KymaProfile }}
+  Kyma Profile:       {{.KymaProfile}}
+  {{- end }}
+{{- end }}
+Operations:
+  {{- with .Status.Provisioning }}
+  Provisioning:       {{operationState .}}
+  {{- end }}
+  {{- range $i, $o := .Status.UpgradingKyma.Data }}
+  Kyma Upgrade:       {{operationState $o}}
+  {{- end }}
+  {{- with .Status.Deprovisioning }}
+  Deprovisioning:     {{operationState .}}
+  {{- end }}
+Provisioner:
+{{- with .Provisioner }}
+  {{- if .Error }}
+  Error:              {{.Error}}
+  {{- else }}
+  {{- with .LastOperation }}
+  Last Operation:     {{.Type}} {{.State}} {{.Message}}
+  {{- end }}
+  Agent Connection:   {{.AgentConnectionStatus}}
+  {{- end }}
+{{- end }}
+Gardener:
+{{- with .Gardener }}
+  {{- if .Error }}
+  Error:              {{.Error}}
+  {{- else }}
+  {{- with .LastOperation }}
+  Last Operation:     {{.Type}} {{.State}} ({{.Progress}}%) {{.Description}}
+  {{- end }}
+  {{- range $i, $c := .Conditions }}
+  {{ printf "%-19s %s" (print $c.Type ":") $c.Status }}
+  {{- end }}
+  {{- end }}
+{{- end }}
+`
+
 // NewRuntimeCmd constructs a new instance of RuntimeCommand and configures it in terms of a cobra.Command
 func NewRuntimeCmd() *cobra.Command {
 	cmd := RuntimeCommand{}
 	cobraCmd := &cobra.Command{
-		Use:     "runtimes",
+		Use:     "runtimes [id]",
 		Aliases: []string{"runtime", "rt"},
 		Short:   "Displays Kyma Runtimes.",
 		Long: `Displays Kyma Runtimes and their primary attributes, such as identifiers, region, or states.
-The command supports filtering Runtimes based on various attributes. See the list of options for more details.`,
+The command has the following modes:
+  - Without specifying a Runtime ID as an argument. In this mode, the command lists all Runtimes, or the Runtimes matching the filter options, if provided.
+    See the list of options for more details.
+  - When specifying a Runtime ID as an argument. In this mode, the command displays details about the specific Runtime,
+    including its operations, configuration, and the live status reported by the Runtime Provisioner and Gardener.`,
 		Example: `  kcp runtimes                                           Display table overview about all Runtimes.
   kcp rt -c c-178e034 -o json                            Display all details about one Runtime identified by a Shoot name in the JSON format.
-  kcp runtimes --account CA4836781TID000000000123456789  Display all Runtimes of a given global account.`,
-		PreRunE: func(_ *cobra.Command, _ []string) error { return cmd.Validate() },
-		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+  kcp runtimes --account CA4836781TID000000000123456789  Display all Runtimes of a given global account.
+  kcp runtime 054ac2c2-318f-45dd-855c-eee41513d40d        Display details about a specific Runtime.`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: func(_ *cobra.Command, args []string) error { return cmd.Validate(args) },
+		RunE:    func(_ *cobra.Command, args []string) error { return cmd.Run(args) },
 	}
 	cmd.cobraCmd = cobraCmd
 
@@ -77,10 +145,22 @@ The command supports filtering Runtimes based on various attributes. See the lis
 }
 
 // Run executes the runtimes command
-func (cmd *RuntimeCommand) Run() error {
+func (cmd *RuntimeCommand) Run(args []string) error {
 	cmd.log = logger.New()
 	client := runtime.NewClient(cmd.cobraCmd.Context(), GlobalOpts.KEBAPIURL(), CLICredentialManager(cmd.log))
 
+	if len(args) == 1 {
+		details, err := client.GetRuntime(args[0])
+		if err != nil {
+			return errors.Wrap(err, "while getting runtime")
+		}
+		err = cmd.printRuntimeDetails(details)
+		if err != nil {
+			return errors.Wrap(err, "while printing runtime details")
+		}
+		return nil
+	}
+
 	rp, err := client.ListRuntimes(cmd.params)
 	if err != nil {
 		return errors.Wrap(err, "while listing runtimes")
@@ -94,14 +174,22 @@ func (cmd *RuntimeCommand) Run() error {
 }
 
 // Validate checks the input parameters of the runtimes command
-func (cmd *RuntimeCommand) Validate() error {
+func (cmd *RuntimeCommand) Validate(args []string) error {
 	err := ValidateOutputOpt(cmd.output)
 	if err != nil {
 		return err
 	}
+	if len(args) == 1 && cmd.filtersSet() {
+		return errors.New("filter options should not be used when runtime id is given as an argument")
+	}
 	return nil
 }
 
+func (cmd *RuntimeCommand) filtersSet() bool {
+	p := cmd.params
+	return len(p.Shoots) > 0 || len(p.GlobalAccountIDs) > 0 || len(p.SubAccountIDs) > 0 || len(p.RuntimeIDs) > 0 || len(p.Regions) > 0
+}
+
 func (cmd *RuntimeCommand) printRuntimes(runtimes runtime.RuntimesPage) error {
 	switch cmd.output {
 	case tableOutput:
@@ -118,6 +206,31 @@ func (cmd *RuntimeCommand) printRuntimes(runtimes runtime.RuntimesPage) error {
 	return nil
 }
 
+func (cmd *RuntimeCommand) printRuntimeDetails(details runtime.RuntimeDetailsDTO) error {
+	switch cmd.output {
+	case tableOutput:
+		funcMap := template.FuncMap{
+			"runtimeStatus":    runtimeStatus,
+			"runtimeCreatedAt": runtimeCreatedAt,
+			"operationState":   operationState,
+		}
+		tmpl, err := template.New("runtimeDetails").Funcs(funcMap).Parse(runtimeDetailsTpl)
+		if err != nil {
+			return errors.Wrap(err, "while parsing runtime details template")
+		}
+		return tmpl.Execute(os.Stdout, details)
+	case jsonOutput:
+		jp := printer.NewJSONPrinter("  ")
+		jp.PrintObj(details)
+	}
+
+	return nil
+}
+
+func operationState(op runtime.Operation) string {
+	return fmt.Sprintf("%s (%s) %s", op.State, op.CreatedAt.Format("2006/01/02 15:04:05"), op.OperationID)
+}
+
 func runtimeStatus(obj interface{}) string {
 	rt := obj.(runtime.RuntimeDTO)
 	if rt.Status.Deprovisioning != nil {